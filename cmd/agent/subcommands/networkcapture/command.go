@@ -0,0 +1,227 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+// Package networkcapture implements 'agent network-capture'.
+package networkcapture
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/fx"
+
+	"github.com/DataDog/datadog-agent/cmd/agent/command"
+	sysprobeclient "github.com/DataDog/datadog-agent/cmd/system-probe/api/client"
+	sysconfig "github.com/DataDog/datadog-agent/cmd/system-probe/config"
+	"github.com/DataDog/datadog-agent/comp/core"
+	"github.com/DataDog/datadog-agent/comp/core/config"
+	log "github.com/DataDog/datadog-agent/comp/core/log/def"
+	"github.com/DataDog/datadog-agent/comp/core/sysprobeconfig"
+	"github.com/DataDog/datadog-agent/comp/core/sysprobeconfig/sysprobeconfigimpl"
+	"github.com/DataDog/datadog-agent/pkg/network/capture"
+	"github.com/DataDog/datadog-agent/pkg/util/filesystem"
+	"github.com/DataDog/datadog-agent/pkg/util/fxutil"
+)
+
+// responseTimeoutMargin is how long the capture may last beyond its duration, before the request times out
+const responseTimeoutMargin = 30 * time.Second
+
+// cliParams are the command-line arguments for this subcommand
+type cliParams struct {
+	*command.GlobalParams
+
+	srcIP       string
+	srcPort     uint16
+	dstIP       string
+	dstPort     uint16
+	protocol    string
+	pid         uint32
+	containerID string
+	duration    time.Duration
+	maxSize     int64
+	maxPackets  int
+	snapLen     int
+	path        string
+	flare       bool
+}
+
+// Commands returns a slice of subcommands for the 'agent' command.
+func Commands(globalParams *command.GlobalParams) []*cobra.Command {
+	cliParams := &cliParams{
+		GlobalParams: globalParams,
+	}
+
+	networkCaptureCmd := &cobra.Command{
+		Use:   "network-capture",
+		Short: "Capture the packets of a connection, process or container with system-probe",
+		Long: `Capture the packets matching a connection 5-tuple, a PID or a container with system-probe, into a pcapng file.
+The packets are annotated with the PID and the container of their connection. With --flare, the capture is included in
+the next flare instead, and removed once the flare is created.`,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return fxutil.OneShot(networkCapture,
+				fx.Supply(cliParams),
+				fx.Supply(core.BundleParams{
+					ConfigParams:         config.NewAgentParams(globalParams.ConfFilePath, config.WithExtraConfFiles(globalParams.ExtraConfFilePath), config.WithFleetPoliciesDirPath(globalParams.FleetPoliciesDirPath)),
+					SysprobeConfigParams: sysprobeconfigimpl.NewParams(sysprobeconfigimpl.WithSysProbeConfFilePath(globalParams.SysProbeConfFilePath), sysprobeconfigimpl.WithFleetPoliciesDirPath(globalParams.FleetPoliciesDirPath)),
+					LogParams:            log.ForOneShot(command.LoggerName, "off", true)}),
+				core.Bundle(),
+			)
+		},
+	}
+
+	flags := networkCaptureCmd.Flags()
+	flags.StringVar(&cliParams.srcIP, "src-ip", "", "Source IP address of the packets, in either direction.")
+	flags.Uint16Var(&cliParams.srcPort, "src-port", 0, "Source port of the packets, in either direction.")
+	flags.StringVar(&cliParams.dstIP, "dst-ip", "", "Destination IP address of the packets, in either direction.")
+	flags.Uint16Var(&cliParams.dstPort, "dst-port", 0, "Destination port of the packets, in either direction.")
+	flags.StringVar(&cliParams.protocol, "proto", "", "Transport protocol of the packets, tcp or udp.")
+	flags.Uint32Var(&cliParams.pid, "pid", 0, "Captures the packets of the connections of this process.")
+	flags.StringVar(&cliParams.containerID, "container-id", "", "Captures the packets of the connections of this container.")
+	flags.DurationVarP(&cliParams.duration, "duration", "d", capture.DefaultDuration, "Duration of the capture.")
+	flags.Int64Var(&cliParams.maxSize, "max-size", 0, "Maximum size of the capture, in bytes. Defaults to the maximum allowed by system-probe.")
+	flags.IntVar(&cliParams.maxPackets, "max-packets", 0, "Maximum number of packets captured.")
+	flags.IntVar(&cliParams.snapLen, "snaplen", capture.DefaultSnapLen, "Number of bytes captured of each packet.")
+	flags.StringVarP(&cliParams.path, "path", "p", "", "Directory path to write the capture to. Defaults to the current directory.")
+	flags.BoolVar(&cliParams.flare, "flare", false, "Include the capture in the next flare, and remove it once the flare is created. The packets aren't scrubbed.")
+
+	return []*cobra.Command{networkCaptureCmd}
+}
+
+func networkCapture(_ log.Component, config config.Component, sysprobeconfig sysprobeconfig.Component, cliParams *cliParams) error {
+	opts, err := cliParams.options()
+	if err != nil {
+		return err
+	}
+
+	dir := cliParams.path
+	if cliParams.flare {
+		// the captures of this directory are attached to the next flare, which removes them
+		dir = config.GetString("network_capture_path")
+		if dir == "" {
+			dir = filepath.Join(config.GetString("run_path"), "network_capture")
+		}
+	} else if dir == "" {
+		dir = "."
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("unable to create the capture directory: %w", err)
+	}
+	path := filepath.Join(dir, fmt.Sprintf("network-capture-%s.pcapng", time.Now().UTC().Format("20060102T150405Z")))
+
+	ctx, cancel := context.WithTimeout(context.Background(), opts.Duration+responseTimeoutMargin)
+	defer cancel()
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext: sysprobeclient.DialContextFunc(sysprobeconfig.GetString("system_probe_config.sysprobe_socket")),
+		},
+	}
+	url := sysprobeclient.ModuleURL(sysconfig.NetworkTracerModule, "/debug/packet_capture?"+opts.Values().Encode())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Capturing the packets matching %s for %s...\n", opts.Filter, opts.Duration)
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to reach system-probe, is the network tracer module enabled? %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return responseError(resp)
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("unable to create the capture file: %w", err)
+	}
+	defer f.Close()
+	if cliParams.flare {
+		// the agent builds the flare and removes the capture, it must be able to access them when the command runs as root
+		if err := restrictAccessToAgentUser(dir, path); err != nil {
+			return err
+		}
+	}
+	n, err := io.Copy(f, resp.Body)
+	if err != nil {
+		return fmt.Errorf("capture interrupted after %d bytes, written to %s: %w", n, path, err)
+	}
+
+	fmt.Printf("Captured %s packets (%d bytes), %s.\n", resp.Trailer.Get("X-Capture-Packets"), n, resp.Trailer.Get("X-Capture-Stop-Reason"))
+	if cliParams.flare {
+		fmt.Printf("Capture written to %s, it will be included in the next flare.\n", path)
+	} else {
+		fmt.Printf("Capture written to %s.\n", path)
+	}
+	return nil
+}
+
+// restrictAccessToAgentUser gives the ownership of the given paths to the agent user
+func restrictAccessToAgentUser(paths ...string) error {
+	perm, err := filesystem.NewPermission()
+	if err != nil {
+		return fmt.Errorf("unable to give the capture to the agent user: %w", err)
+	}
+	for _, path := range paths {
+		if err := perm.RestrictAccessToUser(path); err != nil {
+			return fmt.Errorf("unable to give the capture to the agent user: %w", err)
+		}
+	}
+	return nil
+}
+
+func (p *cliParams) options() (capture.Options, error) {
+	opts := capture.Options{
+		Filter: capture.Filter{
+			SrcPort:     p.srcPort,
+			DstPort:     p.dstPort,
+			Protocol:    capture.Protocol(strings.ToLower(p.protocol)),
+			PID:         p.pid,
+			ContainerID: p.containerID,
+		},
+		Duration:   p.duration,
+		MaxSize:    p.maxSize,
+		MaxPackets: p.maxPackets,
+		SnapLen:    p.snapLen,
+	}
+	var err error
+	if p.srcIP != "" {
+		if opts.SrcIP, err = netip.ParseAddr(p.srcIP); err != nil {
+			return opts, fmt.Errorf("invalid source IP: %w", err)
+		}
+	}
+	if p.dstIP != "" {
+		if opts.DstIP, err = netip.ParseAddr(p.dstIP); err != nil {
+			return opts, fmt.Errorf("invalid destination IP: %w", err)
+		}
+	}
+	if p.flare && p.path != "" {
+		return opts, fmt.Errorf("--path can't be used with --flare")
+	}
+	if opts.Filter.IsEmpty() {
+		return opts, fmt.Errorf("a filter is required: use --src-ip, --src-port, --dst-ip, --dst-port, --proto, --pid or --container-id")
+	}
+	return opts, nil
+}
+
+func responseError(resp *http.Response) error {
+	body, _ := io.ReadAll(resp.Body)
+	var errResponse struct {
+		Error string `json:"error"`
+	}
+	if json.Unmarshal(body, &errResponse) == nil && errResponse.Error != "" {
+		return fmt.Errorf("system-probe refused the capture: %s", errResponse.Error)
+	}
+	return fmt.Errorf("system-probe refused the capture: status code %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+}
@@ -0,0 +1,63 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package networkcapture
+
+import (
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/cmd/agent/command"
+	"github.com/DataDog/datadog-agent/comp/core"
+	"github.com/DataDog/datadog-agent/pkg/network/capture"
+	"github.com/DataDog/datadog-agent/pkg/util/fxutil"
+)
+
+func TestCommand(t *testing.T) {
+	fxutil.TestOneShotSubcommand(t,
+		Commands(&command.GlobalParams{}),
+		[]string{"network-capture", "--dst-ip", "10.0.0.2", "--dst-port", "8080", "--proto", "TCP", "--pid", "42", "-d", "10s"},
+		networkCapture,
+		func(cliParams *cliParams, _ core.BundleParams) {
+			opts, err := cliParams.options()
+			require.NoError(t, err)
+			require.Equal(t, capture.Options{
+				Filter: capture.Filter{
+					DstIP:    netip.MustParseAddr("10.0.0.2"),
+					DstPort:  8080,
+					Protocol: capture.ProtocolTCP,
+					PID:      42,
+				},
+				Duration: 10 * time.Second,
+				SnapLen:  capture.DefaultSnapLen,
+			}, opts)
+		})
+}
+
+func TestCommandRequiresFilter(t *testing.T) {
+	fxutil.TestOneShotSubcommand(t,
+		Commands(&command.GlobalParams{}),
+		[]string{"network-capture", "-d", "10s"},
+		networkCapture,
+		func(cliParams *cliParams, _ core.BundleParams) {
+			_, err := cliParams.options()
+			require.Error(t, err)
+		})
+}
+
+func TestCommandFlareWithPath(t *testing.T) {
+	fxutil.TestOneShotSubcommand(t,
+		Commands(&command.GlobalParams{}),
+		[]string{"network-capture", "--pid", "42", "--flare", "--path", "/tmp"},
+		networkCapture,
+		func(cliParams *cliParams, _ core.BundleParams) {
+			require.True(t, cliParams.flare)
+			_, err := cliParams.options()
+			require.Error(t, err)
+		})
+}
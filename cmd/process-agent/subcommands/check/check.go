@@ -54,6 +54,7 @@ type CliParams struct {
 	*command.GlobalParams
 	checkName       string
 	checkOutputJSON bool
+	checkOutputTree bool
 	waitInterval    time.Duration
 }
 
@@ -112,6 +113,12 @@ func MakeCommand(globalParamsGetter func() *command.GlobalParams, name string, a
 			if !slices.Contains(allowlist, cliParams.checkName) {
 				return fmt.Errorf("invalid check '%s'", cliParams.checkName)
 			}
+			if cliParams.checkOutputTree && cliParams.checkName != checks.ProcessCheckName {
+				return fmt.Errorf("--tree is only supported by the %s check", checks.ProcessCheckName)
+			}
+			if cliParams.checkOutputTree && cliParams.checkOutputJSON {
+				return fmt.Errorf("--tree and --json can't be used together")
+			}
 
 			bundleParams := command.GetCoreBundleParamsForOneShot(globalParamsGetter())
 
@@ -178,6 +185,7 @@ func MakeCommand(globalParamsGetter func() *command.GlobalParams, name string, a
 	}
 
 	checkCmd.Flags().BoolVar(&cliParams.checkOutputJSON, "json", false, "Output check results in JSON")
+	checkCmd.Flags().BoolVar(&cliParams.checkOutputTree, "tree", false, "Output the processes as a tree, each process under its parent (process check only)")
 	checkCmd.Flags().DurationVarP(&cliParams.waitInterval, "wait", "w", defaultWaitInterval, "How long to wait before running the check")
 
 	return checkCmd
@@ -279,6 +287,9 @@ func runCheck(log log.Component, cliParams *CliParams, ch checks.Check) error {
 		msgs = result.Payloads()
 	}
 
+	if cliParams.checkOutputTree {
+		return checks.HumanFormatProcessTree(msgs, os.Stdout)
+	}
 	return printResults(cliParams.checkName, msgs, cliParams.checkOutputJSON)
 }
 
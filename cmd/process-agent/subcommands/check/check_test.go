@@ -25,6 +25,17 @@ func TestRunCheckCmdCommand(t *testing.T) {
 	)
 }
 
+func TestRunCheckCmdCommandTree(t *testing.T) {
+	fxutil.TestOneShotSubcommand(t,
+		Commands(newGlobalParamsTest(t)),
+		[]string{"check", "process", "--tree"},
+		RunCheckCmd,
+		func(cliParams *CliParams) {
+			require.True(t, cliParams.checkOutputTree)
+		},
+	)
+}
+
 func newGlobalParamsTest(t *testing.T) *command.GlobalParams {
 	// Because we uses fx.Invoke some components are built
 	// Since process agent could use the remote tagger we should disable here just in case
@@ -0,0 +1,282 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package top
+
+import (
+	"fmt"
+	"net/http"
+	"os/user"
+	"strconv"
+	"strings"
+	"time"
+
+	model "github.com/DataDog/agent-payload/v5/process"
+
+	sysprobeclient "github.com/DataDog/datadog-agent/cmd/system-probe/api/client"
+	sysconfig "github.com/DataDog/datadog-agent/cmd/system-probe/config"
+	netEncoding "github.com/DataDog/datadog-agent/pkg/network/encoding/unmarshal"
+	"github.com/DataDog/datadog-agent/pkg/process/metadata/parser"
+	"github.com/DataDog/datadog-agent/pkg/process/net"
+	"github.com/DataDog/datadog-agent/pkg/process/procutil"
+	proccontainers "github.com/DataDog/datadog-agent/pkg/process/util/containers"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// clientID identifies the connections of top in system-probe, so that polling them doesn't
+// consume the connections of the process-agent
+const clientID = "process-agent-top"
+
+// processRow holds what's displayed about a process
+type processRow struct {
+	PID         int32
+	PPID        int32
+	User        string
+	Command     string
+	CPU         float64 // percent of a core
+	RSS         uint64
+	ReadRate    float64 // bytes per second, -1 when unknown
+	WriteRate   float64 // bytes per second, -1 when unknown
+	FDs         int32   // -1 when unknown
+	Threads     int32
+	ContainerID string
+	Tags        []string
+}
+
+// connectionRow holds what's displayed about a connection of a process
+type connectionRow struct {
+	Type      string
+	Direction string
+	Local     string
+	Remote    string
+	BytesSent uint64
+	BytesRecv uint64
+}
+
+// sampler collects the state of the processes and computes their rates between two samples
+type sampler struct {
+	probe             procutil.Probe
+	containerProvider proccontainers.ContainerProvider
+	serviceExtractor  *parser.ServiceExtractor
+	scrubber          *procutil.DataScrubber
+
+	// sysprobeClient is nil when system-probe isn't available
+	sysprobeClient        *http.Client
+	processModuleEnabled  bool
+	networkTracerEnabled  bool
+	registeredConnections bool
+
+	lastProcs          map[int32]*procutil.Process
+	lastRun            time.Time
+	lastContainerRates map[string]*proccontainers.ContainerRateMetrics
+	users              map[string]string
+	now                func() time.Time
+}
+
+// sample returns the processes running since the last sample
+func (s *sampler) sample() ([]processRow, error) {
+	now := s.now()
+	procs, err := s.probe.ProcessesByPID(now, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.sysprobeClient != nil && s.processModuleEnabled {
+		pids := make([]int32, 0, len(procs))
+		for pid := range procs {
+			pids = append(pids, pid)
+		}
+		if stats, err := net.GetProcStats(s.sysprobeClient, pids); err == nil {
+			mergeSysprobeStats(procs, stats)
+		} else {
+			log.Debugf("could not get the process stats from system-probe: %s", err)
+		}
+	}
+
+	var containerTags map[string][]string
+	var pidToCid map[int]string
+	if s.containerProvider != nil {
+		var containers []*model.Container
+		containers, s.lastContainerRates, pidToCid, err = s.containerProvider.GetContainers(time.Second, s.lastContainerRates)
+		if err != nil {
+			log.Debugf("could not get the containers: %s", err)
+		}
+		containerTags = make(map[string][]string, len(containers))
+		for _, c := range containers {
+			containerTags[c.Id] = c.Tags
+		}
+	}
+	s.serviceExtractor.Extract(procs)
+
+	elapsed := now.Sub(s.lastRun).Seconds()
+	rows := make([]processRow, 0, len(procs))
+	for pid, p := range procs {
+		row := processRow{
+			PID:       pid,
+			PPID:      p.Ppid,
+			User:      s.username(p),
+			Command:   strings.Join(s.scrubber.ScrubProcessCommand(p), " "),
+			ReadRate:  -1,
+			WriteRate: -1,
+			FDs:       -1,
+		}
+		if row.Command == "" {
+			row.Command = "[" + p.Name + "]"
+		}
+		if stats := p.Stats; stats != nil {
+			row.Threads = stats.NumThreads
+			if stats.MemInfo != nil {
+				row.RSS = stats.MemInfo.RSS
+			}
+			if stats.OpenFdCount >= 0 {
+				row.FDs = stats.OpenFdCount
+			}
+			if last, found := s.lastProcs[pid]; found && elapsed > 0 && last.Stats != nil && last.Stats.CreateTime == stats.CreateTime {
+				row.CPU, row.ReadRate, row.WriteRate = rates(last.Stats, stats, elapsed)
+			}
+		}
+		row.ContainerID = pidToCid[int(pid)]
+		row.Tags = append(append(row.Tags, containerTags[row.ContainerID]...), s.serviceExtractor.GetServiceContext(pid)...)
+		rows = append(rows, row)
+	}
+	s.scrubber.IncrementCacheAge()
+
+	s.lastProcs = procs
+	s.lastRun = now
+	return rows, nil
+}
+
+// rates returns the CPU usage and IO rates of a process between two samples
+func rates(last, current *procutil.Stats, elapsed float64) (cpu, readRate, writeRate float64) {
+	if last.CPUTime != nil && current.CPUTime != nil {
+		used := current.CPUTime.User + current.CPUTime.System - last.CPUTime.User - last.CPUTime.System
+		cpu = max(used, 0) / elapsed * 100
+	}
+	readRate, writeRate = -1, -1
+	// the IO counters are -1 when they couldn't be read
+	if last.IOStat != nil && current.IOStat != nil && last.IOStat.ReadBytes >= 0 && current.IOStat.ReadBytes >= 0 {
+		readRate = float64(max(current.IOStat.ReadBytes-last.IOStat.ReadBytes, 0)) / elapsed
+		writeRate = float64(max(current.IOStat.WriteBytes-last.IOStat.WriteBytes, 0)) / elapsed
+	}
+	return cpu, readRate, writeRate
+}
+
+func mergeSysprobeStats(procs map[int32]*procutil.Process, stats *model.ProcStatsWithPermByPID) {
+	for pid, p := range procs {
+		s, found := stats.StatsByPID[pid]
+		if !found || p.Stats == nil {
+			continue
+		}
+		p.Stats.OpenFdCount = s.OpenFDCount
+		p.Stats.IOStat = &procutil.IOCountersStat{
+			ReadCount:  s.ReadCount,
+			WriteCount: s.WriteCount,
+			ReadBytes:  s.ReadBytes,
+			WriteBytes: s.WriteBytes,
+		}
+	}
+}
+
+func (s *sampler) username(p *procutil.Process) string {
+	if p.Username != "" {
+		return p.Username
+	}
+	if len(p.Uids) == 0 {
+		return ""
+	}
+	uid := strconv.Itoa(int(p.Uids[0]))
+	name, found := s.users[uid]
+	if !found {
+		name = uid
+		if u, err := user.LookupId(uid); err == nil {
+			name = u.Username
+		}
+		s.users[uid] = name
+	}
+	return name
+}
+
+// connections returns the connections of a process tracked by the network tracer of system-probe
+func (s *sampler) connections(pid int32) ([]connectionRow, error) {
+	if s.sysprobeClient == nil || !s.networkTracerEnabled {
+		return nil, fmt.Errorf("the network tracer of system-probe isn't enabled")
+	}
+	if !s.registeredConnections {
+		if err := s.register(); err != nil {
+			return nil, err
+		}
+		s.registeredConnections = true
+	}
+	conns, err := s.getConnections()
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []connectionRow
+	for _, c := range conns.Conns {
+		if c.Pid != pid {
+			continue
+		}
+		rows = append(rows, connectionRow{
+			Type:      strings.ToUpper(c.Type.String()),
+			Direction: strings.ToLower(c.Direction.String()),
+			Local:     formatAddr(c.Laddr),
+			Remote:    formatAddr(c.Raddr),
+			BytesSent: c.LastBytesSent,
+			BytesRecv: c.LastBytesReceived,
+		})
+	}
+	return rows, nil
+}
+
+func (s *sampler) register() error {
+	url := sysprobeclient.ModuleURL(sysconfig.NetworkTracerModule, "/register?client_id="+clientID)
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.sysprobeClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("conn request failed: url: %s, status code: %d", req.URL, resp.StatusCode)
+	}
+	return nil
+}
+
+func (s *sampler) getConnections() (*model.Connections, error) {
+	url := sysprobeclient.ModuleURL(sysconfig.NetworkTracerModule, "/connections?client_id="+clientID)
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/protobuf")
+	resp, err := s.sysprobeClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("conn request failed: url: %s, status code: %d", req.URL, resp.StatusCode)
+	}
+
+	body, err := sysprobeclient.ReadAllResponseBody(resp)
+	if err != nil {
+		return nil, err
+	}
+	return netEncoding.GetUnmarshaler(resp.Header.Get("Content-type")).Unmarshal(body)
+}
+
+func formatAddr(addr *model.Addr) string {
+	if addr == nil {
+		return "-"
+	}
+	if strings.Contains(addr.Ip, ":") {
+		return "[" + addr.Ip + "]:" + strconv.Itoa(int(addr.Port))
+	}
+	return addr.Ip + ":" + strconv.Itoa(int(addr.Port))
+}
@@ -0,0 +1,104 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package top
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/process/metadata/parser"
+	"github.com/DataDog/datadog-agent/pkg/process/procutil"
+	"github.com/DataDog/datadog-agent/pkg/process/procutil/mocks"
+)
+
+func makeProcess(pid int32, cmdline []string, user, system float64, readBytes int64, fds int32) *procutil.Process {
+	return &procutil.Process{
+		Pid:      pid,
+		Ppid:     1,
+		Cmdline:  cmdline,
+		Username: "root",
+		Stats: &procutil.Stats{
+			CreateTime:  1000,
+			NumThreads:  4,
+			OpenFdCount: fds,
+			CPUTime:     &procutil.CPUTimesStat{User: user, System: system},
+			MemInfo:     &procutil.MemoryInfoStat{RSS: 1 << 20},
+			IOStat:      &procutil.IOCountersStat{ReadBytes: readBytes, WriteBytes: readBytes / 2},
+		},
+	}
+}
+
+func TestSample(t *testing.T) {
+	probe := mocks.NewProbe(t)
+	now := time.Now()
+	s := &sampler{
+		probe:            probe,
+		serviceExtractor: parser.NewServiceExtractor(true, false, false),
+		scrubber:         procutil.NewDefaultDataScrubber(),
+		users:            make(map[string]string),
+		now:              func() time.Time { return now },
+	}
+
+	probe.On("ProcessesByPID", now, true).Return(map[int32]*procutil.Process{
+		10: makeProcess(10, []string{"mysqld", "--password=secret"}, 1, 1, 1000, 20),
+	}, nil).Once()
+	rows, err := s.sample()
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	// no rates on the first sample
+	assert.Equal(t, processRow{
+		PID: 10, PPID: 1, User: "root", Command: "mysqld --password=********",
+		RSS: 1 << 20, ReadRate: -1, WriteRate: -1, FDs: 20, Threads: 4,
+		Tags: []string{"process_context:mysqld"},
+	}, rows[0])
+
+	now = now.Add(2 * time.Second)
+	probe.On("ProcessesByPID", now, true).Return(map[int32]*procutil.Process{
+		10: makeProcess(10, []string{"mysqld", "--password=secret"}, 1.5, 1.5, 5000, -1),
+		11: {Pid: 11, Name: "kworker", Stats: &procutil.Stats{OpenFdCount: -1}},
+	}, nil).Once()
+	rows, err = s.sample()
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	for _, row := range rows {
+		switch row.PID {
+		case 10:
+			assert.Equal(t, 50.0, row.CPU)
+			assert.Equal(t, 2000.0, row.ReadRate)
+			assert.Equal(t, 1000.0, row.WriteRate)
+			assert.EqualValues(t, -1, row.FDs)
+		case 11:
+			// kernel threads have no command line
+			assert.Equal(t, "[kworker]", row.Command)
+			assert.Equal(t, -1.0, row.ReadRate)
+		}
+	}
+}
+
+func TestRates(t *testing.T) {
+	last := &procutil.Stats{
+		CPUTime: &procutil.CPUTimesStat{User: 10, System: 5},
+		IOStat:  &procutil.IOCountersStat{ReadBytes: 100, WriteBytes: 100},
+	}
+	current := &procutil.Stats{
+		CPUTime: &procutil.CPUTimesStat{User: 11, System: 5.5},
+		IOStat:  &procutil.IOCountersStat{ReadBytes: 1100, WriteBytes: 50},
+	}
+	cpu, readRate, writeRate := rates(last, current, 0.5)
+	assert.Equal(t, 300.0, cpu)
+	assert.Equal(t, 2000.0, readRate)
+	// the counters going backward are ignored
+	assert.Equal(t, 0.0, writeRate)
+
+	// the IO counters couldn't be read
+	current.IOStat = &procutil.IOCountersStat{ReadBytes: -1, WriteBytes: -1}
+	_, readRate, writeRate = rates(last, current, 0.5)
+	assert.Equal(t, -1.0, readRate)
+	assert.Equal(t, -1.0, writeRate)
+}
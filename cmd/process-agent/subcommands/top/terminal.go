@@ -0,0 +1,61 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package top
+
+import (
+	"io"
+)
+
+// ANSI escape sequences switching to the alternate screen of the terminal and back
+const (
+	enterAlternateScreen = "\x1b[?1049h\x1b[?25l"
+	exitAlternateScreen  = "\x1b[?25h\x1b[?1049l"
+)
+
+var escapeSequences = map[string]key{
+	"\x1b[A":  keyUp,
+	"\x1bOA":  keyUp,
+	"\x1b[B":  keyDown,
+	"\x1bOB":  keyDown,
+	"\x1b[5~": keyPageUp,
+	"\x1b[6~": keyPageDown,
+}
+
+// parseKey returns the key of the bytes read from a terminal in raw mode, and whether it's known
+func parseKey(b []byte) (key, bool) {
+	switch {
+	case len(b) == 0:
+		return "", false
+	case b[0] == 0x1b:
+		if len(b) == 1 {
+			return keyEscape, true
+		}
+		k, found := escapeSequences[string(b)]
+		return k, found
+	case b[0] == 0x03: // ctrl-c
+		return keyQuit, true
+	case b[0] == '\r' || b[0] == '\n':
+		return keyEnter, true
+	case b[0] >= ' ' && b[0] < 0x7f:
+		return key(b[:1]), true
+	}
+	return "", false
+}
+
+// readKeys sends the keys read from the terminal until it's closed
+func readKeys(r io.Reader, keys chan<- key) {
+	defer close(keys)
+	buf := make([]byte, 16)
+	for {
+		n, err := r.Read(buf)
+		if err != nil {
+			return
+		}
+		if k, found := parseKey(buf[:n]); found {
+			keys <- k
+		}
+	}
+}
@@ -0,0 +1,44 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package top
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseKey(t *testing.T) {
+	for input, expected := range map[string]key{
+		"\x1b":    keyEscape,
+		"\x1b[A":  keyUp,
+		"\x1bOB":  keyDown,
+		"\x1b[5~": keyPageUp,
+		"\x1b[6~": keyPageDown,
+		"\x03":    keyQuit,
+		"\r":      keyEnter,
+		"c":       "c",
+	} {
+		k, found := parseKey([]byte(input))
+		assert.True(t, found, "%q", input)
+		assert.Equal(t, expected, k, "%q", input)
+	}
+
+	for _, input := range []string{"", "\x1b[C", "\x7f"} {
+		_, found := parseKey([]byte(input))
+		assert.False(t, found, "%q", input)
+	}
+}
+
+func TestReadKeys(t *testing.T) {
+	keys := make(chan key)
+	go readKeys(strings.NewReader("q"), keys)
+
+	assert.Equal(t, key("q"), <-keys)
+	_, open := <-keys
+	assert.False(t, open)
+}
@@ -0,0 +1,241 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+// Package top implements the `top` subcommand of the Process Agent, a live view of the
+// processes of the host with their containers, services and connections.
+package top
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/fx"
+	"golang.org/x/term"
+
+	"github.com/DataDog/datadog-agent/cmd/process-agent/command"
+	sysprobeclient "github.com/DataDog/datadog-agent/cmd/system-probe/api/client"
+	sysconfig "github.com/DataDog/datadog-agent/cmd/system-probe/config"
+	"github.com/DataDog/datadog-agent/comp/core"
+	"github.com/DataDog/datadog-agent/comp/core/config"
+	log "github.com/DataDog/datadog-agent/comp/core/log/def"
+	"github.com/DataDog/datadog-agent/comp/core/sysprobeconfig"
+	tagger "github.com/DataDog/datadog-agent/comp/core/tagger/def"
+	dualTaggerfx "github.com/DataDog/datadog-agent/comp/core/tagger/fx-dual"
+	taggerTypes "github.com/DataDog/datadog-agent/comp/core/tagger/types"
+	wmcatalog "github.com/DataDog/datadog-agent/comp/core/workloadmeta/collectors/catalog"
+	workloadmeta "github.com/DataDog/datadog-agent/comp/core/workloadmeta/def"
+	workloadmetafx "github.com/DataDog/datadog-agent/comp/core/workloadmeta/fx"
+	"github.com/DataDog/datadog-agent/pkg/api/security"
+	"github.com/DataDog/datadog-agent/pkg/config/structure"
+	"github.com/DataDog/datadog-agent/pkg/process/metadata/parser"
+	"github.com/DataDog/datadog-agent/pkg/process/procutil"
+	proccontainers "github.com/DataDog/datadog-agent/pkg/process/util/containers"
+	"github.com/DataDog/datadog-agent/pkg/util/fxutil"
+	"github.com/DataDog/datadog-agent/pkg/util/hostname"
+)
+
+const defaultRefreshInterval = time.Second
+
+type cliParams struct {
+	*command.GlobalParams
+	refreshInterval time.Duration
+}
+
+type dependencies struct {
+	fx.In
+
+	Params *cliParams
+
+	Config       config.Component
+	Syscfg       sysprobeconfig.Component
+	Log          log.Component
+	Tagger       tagger.Component
+	WorkloadMeta workloadmeta.Component
+}
+
+// Commands returns a slice of subcommands for the `top` command in the Process Agent
+func Commands(globalParams *command.GlobalParams) []*cobra.Command {
+	cliParams := &cliParams{
+		GlobalParams: globalParams,
+	}
+
+	topCmd := &cobra.Command{
+		Use:   "top",
+		Short: "Display a live view of the processes of the host, with their containers, services and connections",
+		Long: `Display a live view of the processes of the host, refreshed every second. The processes can be
+sorted by CPU, memory, IO or open file descriptors, and the details of a process list its container
+and service tags, and its connections when the network tracer of system-probe is enabled.`,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			bundleParams := command.GetCoreBundleParamsForOneShot(globalParams)
+			// the logs would garble the screen
+			bundleParams.LogParams = log.ForOneShot(string(command.LoggerName), "off", true)
+
+			return fxutil.OneShot(runTop,
+				fx.Supply(cliParams, bundleParams),
+				core.Bundle(),
+				wmcatalog.GetCatalog(),
+				workloadmetafx.ModuleWithProvider(func(config config.Component) workloadmeta.Params {
+					catalog := workloadmeta.ProcessAgent
+					if config.GetBool("process_config.remote_workloadmeta") {
+						catalog = workloadmeta.Remote
+					}
+					return workloadmeta.Params{AgentType: catalog}
+				}),
+				dualTaggerfx.Module(tagger.DualParams{
+					UseRemote: func(c config.Component) bool {
+						return c.GetBool("process_config.remote_tagger")
+					},
+				}, tagger.Params{}, tagger.RemoteParams{
+					RemoteTarget: func(c config.Component) (string, error) {
+						return fmt.Sprintf(":%v", c.GetInt("cmd_port")), nil
+					},
+					RemoteTokenFetcher: func(c config.Component) func() (string, error) {
+						return func() (string, error) {
+							return security.FetchAuthToken(c)
+						}
+					},
+					RemoteFilter: taggerTypes.NewMatchAllFilter(),
+				}),
+				fx.Invoke(func(wmeta workloadmeta.Component, tagger tagger.Component) {
+					proccontainers.InitSharedContainerProvider(wmeta, tagger)
+				}),
+			)
+		},
+		SilenceUsage: true,
+	}
+
+	topCmd.Flags().DurationVarP(&cliParams.refreshInterval, "interval", "i", defaultRefreshInterval, "How often the processes are refreshed")
+
+	return []*cobra.Command{topCmd}
+}
+
+func runTop(deps dependencies) error {
+	stdin, stdout := int(os.Stdin.Fd()), int(os.Stdout.Fd())
+	if !term.IsTerminal(stdin) || !term.IsTerminal(stdout) {
+		return errors.New("top needs an interactive terminal")
+	}
+	if deps.Params.refreshInterval <= 0 {
+		return fmt.Errorf("invalid refresh interval %s", deps.Params.refreshInterval)
+	}
+
+	s := newSampler(deps)
+	host, err := hostname.Get(context.Background())
+	if err != nil {
+		host = "unknown host"
+	}
+	v := newView(host)
+
+	state, err := term.MakeRaw(stdin)
+	if err != nil {
+		return err
+	}
+	defer term.Restore(stdin, state) //nolint:errcheck
+	fmt.Print(enterAlternateScreen)
+	defer fmt.Print(exitAlternateScreen)
+
+	keys := make(chan key)
+	go readKeys(os.Stdin, keys)
+	// ctrl-c is read as a key in raw mode, the other signals still stop the command
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, syscall.SIGHUP)
+	defer signal.Stop(signals)
+
+	height := 0
+	draw := func() {
+		var width int
+		var err error
+		if width, height, err = term.GetSize(stdout); err != nil {
+			width, height = 80, 24
+		}
+		var buf bytes.Buffer
+		v.render(&buf, width, height)
+		_, _ = os.Stdout.Write(buf.Bytes())
+	}
+	refreshConnections := func() {
+		if row, found := v.selected(); found && v.detail {
+			v.connections, v.connErr = s.connections(row.PID)
+		}
+	}
+	refresh := func() {
+		rows, err := s.sample()
+		v.lastErr = err
+		if err == nil {
+			v.setRows(rows, s.lastRun)
+		}
+		refreshConnections()
+	}
+
+	refresh()
+	draw()
+	ticker := time.NewTicker(deps.Params.refreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case k, ok := <-keys:
+			if !ok {
+				return nil
+			}
+			wasDetail := v.detail
+			// the page is the table, without the header, the title and the help lines
+			if !v.handleKey(k, height-4) {
+				return nil
+			}
+			if v.detail && !wasDetail {
+				refreshConnections()
+			}
+			draw()
+		case <-ticker.C:
+			refresh()
+			draw()
+		case <-signals:
+			return nil
+		}
+	}
+}
+
+func newSampler(deps dependencies) *sampler {
+	sysprobe := deps.Syscfg.SysProbeObject()
+	_, processModuleEnabled := sysprobe.EnabledModules[sysconfig.ProcessModule]
+	_, networkTracerEnabled := sysprobe.EnabledModules[sysconfig.NetworkTracerModule]
+
+	s := &sampler{
+		probe:                procutil.NewProcessProbe(procutil.WithPermission(processModuleEnabled)),
+		processModuleEnabled: processModuleEnabled,
+		networkTracerEnabled: networkTracerEnabled,
+		serviceExtractor: parser.NewServiceExtractor(true,
+			deps.Syscfg.GetBool("system_probe_config.process_service_inference.use_windows_service_name"),
+			deps.Syscfg.GetBool("system_probe_config.process_service_inference.use_improved_algorithm")),
+		scrubber: procutil.NewDefaultDataScrubber(),
+		users:    make(map[string]string),
+		now:      time.Now,
+	}
+	if processModuleEnabled || networkTracerEnabled {
+		s.sysprobeClient = sysprobeclient.Get(sysprobe.SocketAddress)
+	}
+	if containerProvider, err := proccontainers.GetSharedContainerProvider(); err == nil {
+		s.containerProvider = containerProvider
+	}
+
+	// the command lines are scrubbed like in the process check
+	if deps.Config.IsSet("process_config.scrub_args") {
+		s.scrubber.Enabled = deps.Config.GetBool("process_config.scrub_args")
+	}
+	if deps.Config.IsSet("process_config.custom_sensitive_words") {
+		s.scrubber.AddCustomSensitiveWords(deps.Config.GetStringSlice("process_config.custom_sensitive_words"))
+	}
+	s.scrubber.DisableDetectors(deps.Config.GetStringSlice("process_config.scrub_args_disabled_detectors"))
+	var rules []procutil.ArgumentRule
+	if err := structure.UnmarshalKey(deps.Config, "process_config.custom_sensitive_arguments", &rules); err == nil {
+		s.scrubber.AddArgumentRules(rules)
+	}
+	return s
+}
@@ -0,0 +1,349 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package top
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+)
+
+// ANSI escape sequences used to draw the screen
+const (
+	clearScreen  = "\x1b[H\x1b[2J"
+	reverseVideo = "\x1b[7m"
+	bold         = "\x1b[1m"
+	resetStyle   = "\x1b[0m"
+
+	// minHeight is the height of the header and help lines, plus one process
+	minHeight = 5
+)
+
+// key is a key pressed by the user
+type key string
+
+// Special keys, the other keys are their character
+const (
+	keyUp       key = "up"
+	keyDown     key = "down"
+	keyPageUp   key = "pgup"
+	keyPageDown key = "pgdown"
+	keyEnter    key = "enter"
+	keyEscape   key = "esc"
+	keyQuit     key = "quit"
+)
+
+// column is a column of the process table
+type column struct {
+	title string
+	// sortKey is the key sorting the table by the column, 0 if it can't be sorted by it
+	sortKey rune
+	format  string
+	value   func(processRow) string
+	less    func(a, b processRow) int
+}
+
+var columns = []column{
+	{
+		title: "PID", sortKey: 'p', format: "%7s",
+		value: func(r processRow) string { return fmt.Sprint(r.PID) },
+		// the lowest PIDs first
+		less: func(a, b processRow) int { return cmp.Compare(b.PID, a.PID) },
+	},
+	{
+		title: "USER", format: "%-10.10s",
+		value: func(r processRow) string { return r.User },
+	},
+	{
+		title: "CPU%", sortKey: 'c', format: "%6s",
+		value: func(r processRow) string { return fmt.Sprintf("%.1f", r.CPU) },
+		less:  func(a, b processRow) int { return cmp.Compare(a.CPU, b.CPU) },
+	},
+	{
+		title: "RSS", sortKey: 'm', format: "%8s",
+		value: func(r processRow) string { return humanize.Bytes(r.RSS) },
+		less:  func(a, b processRow) int { return cmp.Compare(a.RSS, b.RSS) },
+	},
+	{
+		title: "READ/s", sortKey: 'r', format: "%8s",
+		value: func(r processRow) string { return formatRate(r.ReadRate) },
+		less:  func(a, b processRow) int { return cmp.Compare(a.ReadRate, b.ReadRate) },
+	},
+	{
+		title: "WRITE/s", sortKey: 'w', format: "%8s",
+		value: func(r processRow) string { return formatRate(r.WriteRate) },
+		less:  func(a, b processRow) int { return cmp.Compare(a.WriteRate, b.WriteRate) },
+	},
+	{
+		title: "FDS", sortKey: 'f', format: "%5s",
+		value: func(r processRow) string { return formatCount(r.FDs) },
+		less:  func(a, b processRow) int { return cmp.Compare(a.FDs, b.FDs) },
+	},
+	{
+		title: "CONTAINER", format: "%-12.12s",
+		value: func(r processRow) string { return r.ContainerID },
+	},
+	{
+		title: "COMMAND", format: "%s",
+		value: func(r processRow) string { return r.Command },
+	},
+}
+
+// view holds the state of the screen
+type view struct {
+	hostname string
+	rows     []processRow
+	sortKey  rune
+	// selectedPID is the PID of the selected process, the selection follows it when the table is sorted
+	selectedPID int32
+	offset      int
+	// detail is true when the details of the selected process are displayed
+	detail      bool
+	connections []connectionRow
+	connErr     error
+	lastErr     error
+	updated     time.Time
+}
+
+func newView(hostname string) *view {
+	return &view{
+		hostname:    hostname,
+		sortKey:     'c',
+		selectedPID: -1,
+	}
+}
+
+// setRows replaces the processes, keeping the selection if the selected process still runs
+func (v *view) setRows(rows []processRow, updated time.Time) {
+	v.rows = rows
+	v.updated = updated
+	v.sort()
+	if v.selectedIndex() < 0 && len(v.rows) > 0 {
+		v.selectedPID = v.rows[0].PID
+		v.detail = false
+	}
+}
+
+func (v *view) sort() {
+	var less func(a, b processRow) int
+	for _, c := range columns {
+		if c.sortKey == v.sortKey {
+			less = c.less
+		}
+	}
+	// highest values first, then lowest PIDs
+	slices.SortStableFunc(v.rows, func(a, b processRow) int {
+		if c := less(b, a); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.PID, b.PID)
+	})
+}
+
+func (v *view) selectedIndex() int {
+	return slices.IndexFunc(v.rows, func(r processRow) bool { return r.PID == v.selectedPID })
+}
+
+// selected returns the selected process, if any
+func (v *view) selected() (processRow, bool) {
+	if i := v.selectedIndex(); i >= 0 {
+		return v.rows[i], true
+	}
+	return processRow{}, false
+}
+
+// handleKey updates the view after a key press, it returns false when the user quits
+func (v *view) handleKey(k key, pageSize int) bool {
+	switch k {
+	case keyQuit, "q":
+		return false
+	case keyEscape, "b":
+		v.detail = false
+	case keyEnter:
+		if _, found := v.selected(); found {
+			v.detail = true
+			v.connections, v.connErr = nil, nil
+		}
+	case keyUp, "k":
+		v.moveSelection(-1)
+	case keyDown, "j":
+		v.moveSelection(1)
+	case keyPageUp:
+		v.moveSelection(-pageSize)
+	case keyPageDown:
+		v.moveSelection(pageSize)
+	default:
+		if v.detail || len(k) != 1 {
+			break
+		}
+		for _, c := range columns {
+			if c.sortKey != 0 && string(c.sortKey) == string(k) {
+				v.sortKey = c.sortKey
+				v.sort()
+			}
+		}
+	}
+	return true
+}
+
+func (v *view) moveSelection(delta int) {
+	if v.detail || len(v.rows) == 0 {
+		return
+	}
+	i := min(max(v.selectedIndex()+delta, 0), len(v.rows)-1)
+	v.selectedPID = v.rows[i].PID
+}
+
+// render draws the screen, which is width columns by height lines
+func (v *view) render(w io.Writer, width, height int) {
+	height = max(height, minHeight)
+	var lines []string
+	lines = append(lines, fmt.Sprintf("%sprocess-agent top - %s - %s - %d processes%s",
+		bold, v.hostname, v.updated.Format(time.TimeOnly), len(v.rows), resetStyle))
+	if v.lastErr != nil {
+		lines = append(lines, "error: "+v.lastErr.Error())
+	} else {
+		lines = append(lines, "")
+	}
+
+	if v.detail {
+		lines = append(lines, v.detailLines()...)
+	} else {
+		lines = append(lines, v.tableLines(height-len(lines)-1)...)
+	}
+
+	for len(lines) < height-1 {
+		lines = append(lines, "")
+	}
+	lines = append(lines[:height-1], v.helpLine())
+
+	fmt.Fprint(w, clearScreen)
+	for i, line := range lines {
+		if i > 0 {
+			fmt.Fprint(w, "\r\n")
+		}
+		fmt.Fprint(w, truncate(line, width))
+	}
+}
+
+func (v *view) tableLines(height int) []string {
+	var header []string
+	for _, c := range columns {
+		title := c.title
+		if c.sortKey == v.sortKey {
+			title += "*"
+		}
+		header = append(header, fmt.Sprintf(c.format, title))
+	}
+	lines := []string{reverseVideo + strings.Join(header, " ") + resetStyle}
+
+	visible := max(height-1, 1)
+	selected := v.selectedIndex()
+	// scroll to keep the selected process visible
+	if selected >= 0 && selected < v.offset {
+		v.offset = selected
+	} else if selected >= v.offset+visible {
+		v.offset = selected - visible + 1
+	}
+	v.offset = min(v.offset, max(len(v.rows)-visible, 0))
+
+	for i := v.offset; i < len(v.rows) && i < v.offset+visible; i++ {
+		var cells []string
+		for _, c := range columns {
+			cells = append(cells, fmt.Sprintf(c.format, c.value(v.rows[i])))
+		}
+		line := strings.Join(cells, " ")
+		if i == selected {
+			line = reverseVideo + line + resetStyle
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func (v *view) detailLines() []string {
+	row, found := v.selected()
+	if !found {
+		return []string{"the process exited"}
+	}
+	lines := []string{
+		fmt.Sprintf("%sPID:%s %d  %sPPID:%s %d  %sUser:%s %s  %sThreads:%s %d", bold, resetStyle, row.PID, bold, resetStyle, row.PPID, bold, resetStyle, row.User, bold, resetStyle, row.Threads),
+		fmt.Sprintf("%sCommand:%s %s", bold, resetStyle, row.Command),
+		fmt.Sprintf("%sCPU:%s %.1f%%  %sRSS:%s %s  %sRead:%s %s/s  %sWrite:%s %s/s  %sFDs:%s %s",
+			bold, resetStyle, row.CPU, bold, resetStyle, humanize.Bytes(row.RSS), bold, resetStyle, formatRate(row.ReadRate),
+			bold, resetStyle, formatRate(row.WriteRate), bold, resetStyle, formatCount(row.FDs)),
+	}
+	if row.ContainerID != "" {
+		lines = append(lines, fmt.Sprintf("%sContainer:%s %s", bold, resetStyle, row.ContainerID))
+	}
+	if len(row.Tags) > 0 {
+		lines = append(lines, fmt.Sprintf("%sTags:%s %s", bold, resetStyle, strings.Join(row.Tags, ", ")))
+	}
+
+	lines = append(lines, "", bold+"Connections"+resetStyle)
+	switch {
+	case v.connErr != nil:
+		lines = append(lines, "  not available: "+v.connErr.Error())
+	case len(v.connections) == 0:
+		lines = append(lines, "  none")
+	default:
+		for _, c := range v.connections {
+			lines = append(lines, fmt.Sprintf("  %-4s %-9s %-22s -> %-22s sent %8s recv %8s",
+				c.Type, c.Direction, c.Local, c.Remote, humanize.Bytes(c.BytesSent), humanize.Bytes(c.BytesRecv)))
+		}
+	}
+	return lines
+}
+
+func (v *view) helpLine() string {
+	if v.detail {
+		return reverseVideo + " esc/b back  q quit " + resetStyle
+	}
+	return reverseVideo + " q quit  up/down select  enter details  sort: c cpu  m memory  r read  w write  f fds  p pid " + resetStyle
+}
+
+func formatRate(rate float64) string {
+	if rate < 0 {
+		return "-"
+	}
+	return humanize.Bytes(uint64(rate))
+}
+
+func formatCount(count int32) string {
+	if count < 0 {
+		return "-"
+	}
+	return fmt.Sprint(count)
+}
+
+// truncate cuts a line to the width of the screen, not counting the escape sequences
+func truncate(line string, width int) string {
+	var b strings.Builder
+	visible := 0
+	escape := false
+	for _, r := range line {
+		switch {
+		case r == '\x1b':
+			escape = true
+		case escape:
+			if r >= '@' && r <= '~' && r != '[' {
+				escape = false
+			}
+		default:
+			if visible >= width {
+				continue
+			}
+			visible++
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,142 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package top
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testRows() []processRow {
+	return []processRow{
+		{PID: 1, User: "root", Command: "/sbin/init", CPU: 0.1, RSS: 10 << 20, ReadRate: -1, WriteRate: -1, FDs: 50},
+		{PID: 42, User: "www", Command: "nginx: worker process", CPU: 12.5, RSS: 30 << 20, ReadRate: 1000, WriteRate: 2000, FDs: 12, ContainerID: "abcdef0123456789", Tags: []string{"image_name:nginx", "process_context:nginx"}},
+		{PID: 7, User: "redis", Command: "redis-server *:6379", CPU: 12.5, RSS: 100 << 20, ReadRate: 5000, WriteRate: 0, FDs: 3},
+	}
+}
+
+func pids(rows []processRow) []int32 {
+	var pids []int32
+	for _, r := range rows {
+		pids = append(pids, r.PID)
+	}
+	return pids
+}
+
+func TestViewSort(t *testing.T) {
+	v := newView("host")
+	v.setRows(testRows(), time.Now())
+
+	// CPU by default, the ties are sorted by PID
+	assert.Equal(t, []int32{7, 42, 1}, pids(v.rows))
+	for k, expected := range map[key][]int32{
+		"m": {7, 42, 1},
+		"r": {7, 42, 1},
+		"w": {42, 7, 1},
+		"f": {1, 42, 7},
+		"p": {1, 7, 42},
+	} {
+		require.True(t, v.handleKey(k, 10))
+		assert.Equal(t, expected, pids(v.rows), "sorted by %s", k)
+	}
+}
+
+func TestViewSelection(t *testing.T) {
+	v := newView("host")
+	v.setRows(testRows(), time.Now())
+	assert.EqualValues(t, 7, v.selectedPID)
+
+	v.handleKey(keyDown, 10)
+	assert.EqualValues(t, 42, v.selectedPID)
+	v.handleKey(keyPageDown, 10)
+	assert.EqualValues(t, 1, v.selectedPID)
+	v.handleKey(keyUp, 10)
+	assert.EqualValues(t, 42, v.selectedPID)
+
+	// the selection follows the process when the table is sorted
+	v.handleKey("p", 10)
+	assert.EqualValues(t, 42, v.selectedPID)
+
+	v.handleKey(keyEnter, 10)
+	assert.True(t, v.detail)
+	// the sort keys are ignored in the details
+	v.handleKey("c", 10)
+	assert.Equal(t, 'p', v.sortKey)
+	v.handleKey(keyEscape, 10)
+	assert.False(t, v.detail)
+
+	// the first process is selected when the selected one exits
+	v.setRows(testRows()[:1], time.Now())
+	assert.EqualValues(t, 1, v.selectedPID)
+
+	assert.False(t, v.handleKey("q", 10))
+	assert.False(t, v.handleKey(keyQuit, 10))
+}
+
+func TestViewRender(t *testing.T) {
+	v := newView("host")
+	v.setRows(testRows(), time.Date(2024, 1, 1, 12, 30, 0, 0, time.UTC))
+
+	var b strings.Builder
+	v.render(&b, 200, 10)
+	out := b.String()
+	assert.True(t, strings.HasPrefix(out, clearScreen))
+	lines := strings.Split(out, "\r\n")
+	require.Len(t, lines, 10)
+	assert.Contains(t, lines[0], "process-agent top - host - 12:30:00 - 3 processes")
+	assert.Contains(t, lines[2], "CPU%*")
+	assert.Contains(t, lines[3], reverseVideo)
+	assert.Contains(t, lines[3], "redis-server *:6379")
+	assert.Contains(t, lines[4], "abcdef012345")
+	assert.Contains(t, lines[5], "/sbin/init")
+	assert.Contains(t, lines[9], "q quit")
+
+	v.handleKey(keyDown, 10)
+	v.handleKey(keyEnter, 10)
+	v.connErr = errors.New("the network tracer of system-probe isn't enabled")
+	b.Reset()
+	v.render(&b, 200, 20)
+	out = b.String()
+	assert.Contains(t, out, "nginx: worker process")
+	assert.Contains(t, out, "image_name:nginx, process_context:nginx")
+	assert.Contains(t, out, "not available: the network tracer of system-probe isn't enabled")
+
+	v.connErr = nil
+	v.connections = []connectionRow{{Type: "TCP", Direction: "incoming", Local: "10.0.0.1:80", Remote: "10.0.0.2:51234", BytesSent: 2048, BytesRecv: 512}}
+	b.Reset()
+	v.render(&b, 200, 20)
+	assert.Contains(t, b.String(), "10.0.0.1:80            -> 10.0.0.2:51234")
+}
+
+func TestViewScroll(t *testing.T) {
+	v := newView("host")
+	var rows []processRow
+	for pid := int32(1); pid <= 20; pid++ {
+		rows = append(rows, processRow{PID: pid, CPU: float64(pid)})
+	}
+	v.setRows(rows, time.Now())
+
+	for i := 0; i < 10; i++ {
+		v.handleKey(keyDown, 5)
+	}
+	var b strings.Builder
+	// 6 lines for the processes, with the header
+	v.render(&b, 80, 9)
+	assert.Equal(t, 6, v.offset)
+	assert.Contains(t, b.String(), reverseVideo+"     10")
+}
+
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, "abc", truncate("abcdef", 3))
+	assert.Equal(t, "abcdef", truncate("abcdef", 10))
+	// the escape sequences aren't counted
+	assert.Equal(t, reverseVideo+"abc"+resetStyle, truncate(reverseVideo+"abcdef"+resetStyle, 3))
+}
@@ -72,6 +72,7 @@ func commonPolicyCommands(globalParams *command.GlobalParams) []*cobra.Command {
 	commonPolicyCmd.AddCommand(commonCheckPoliciesCommands(globalParams)...)
 	commonPolicyCmd.AddCommand(commonReloadPoliciesCommands(globalParams)...)
 	commonPolicyCmd.AddCommand(downloadPolicyCommands(globalParams)...)
+	commonPolicyCmd.AddCommand(importSigmaCommands(globalParams)...)
 
 	return []*cobra.Command{commonPolicyCmd}
 }
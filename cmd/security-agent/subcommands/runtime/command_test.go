@@ -9,6 +9,9 @@ package runtime
 
 import (
 	"bytes"
+	"io"
+	"os"
+	"path/filepath"
 	"testing"
 
 	secagent "github.com/DataDog/datadog-agent/pkg/security/agent"
@@ -214,3 +217,91 @@ func TestRunRuntimeSelfTestCommand(t *testing.T) {
 		runRuntimeSelfTest,
 		func() {})
 }
+
+func TestImportSigmaCommand(t *testing.T) {
+	fxutil.TestOneShotSubcommand(t,
+		Commands(&command.GlobalParams{}),
+		[]string{"runtime", "policy", "import-sigma", "--check", "--output=sigma.policy", "rules"},
+		importSigma,
+		func(cliParams *importSigmaCliParams) {
+			require.Equal(t, []string{"rules"}, cliParams.paths)
+			require.Equal(t, "sigma.policy", cliParams.outputPath)
+			require.True(t, cliParams.check)
+		})
+}
+
+func Test_importSigma(t *testing.T) {
+	rulesDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(rulesDir, "shell.yml"), []byte(`
+title: Shell Spawned By Web Server
+id: 8a3c4f0e-4b7c-4c3e-9d2a-0f6a3b1c2d4e
+logsource:
+  product: linux
+  category: process_creation
+detection:
+  selection:
+    ParentImage|endswith:
+      - /nginx
+      - /httpd
+    Image|endswith: /sh
+  filter:
+    CommandLine|contains: healthcheck
+  condition: selection and not filter
+`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(rulesDir, "cron.yaml"), []byte(`
+title: Cron File Created
+logsource:
+  product: linux
+  category: file_event
+detection:
+  selection:
+    TargetFilename|startswith: /etc/cron.d/
+  condition: selection
+`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(rulesDir, "mining.yml"), []byte(`
+title: Mining Pool Connection
+logsource:
+  product: linux
+  category: network_connection
+detection:
+  selection:
+    DestinationPort: 3333
+  filter:
+    DestinationIp|cidr: 10.0.0.0/8
+  miner:
+    Image|endswith: /xmrig
+  condition: selection and not filter or miner
+`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(rulesDir, "README.md"), []byte("not a rule"), 0644))
+
+	output := filepath.Join(t.TempDir(), "sigma.policy")
+	err := importSigma(nil, nil, nil, &importSigmaCliParams{paths: []string{rulesDir}, outputPath: output, check: true})
+	require.NoError(t, err)
+
+	// the generated policy passes the `policy check` validation
+	require.NoError(t, checkPoliciesLocal(&checkPoliciesCliParams{dir: filepath.Dir(output)}, io.Discard))
+
+	policy, err := os.ReadFile(output)
+	require.NoError(t, err)
+	assert.Contains(t, string(policy), "id: sigma_8a3c4f0e_4b7c_4c3e_9d2a_0f6a3b1c2d4e")
+	assert.Contains(t, string(policy), "id: sigma_cron_file_created")
+	assert.Contains(t, string(policy), "id: sigma_mining_pool_connection")
+	assert.Contains(t, string(policy), "id: sigma_mining_pool_connection_miner")
+}
+
+func Test_importSigmaNoRule(t *testing.T) {
+	rulesDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(rulesDir, "dns.yml"), []byte(`
+title: DNS Query
+logsource:
+  product: linux
+  category: dns_query
+detection:
+  selection:
+    QueryName: example.com
+  condition: selection
+`), 0644))
+
+	err := importSigma(nil, nil, nil, &importSigmaCliParams{paths: []string{rulesDir}})
+	assert.EqualError(t, err, "no Sigma rule could be translated")
+}
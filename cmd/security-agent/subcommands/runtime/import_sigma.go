@@ -0,0 +1,134 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+//go:build linux || windows
+
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/fx"
+
+	"github.com/DataDog/datadog-agent/cmd/security-agent/command"
+	"github.com/DataDog/datadog-agent/comp/core"
+	"github.com/DataDog/datadog-agent/comp/core/config"
+	log "github.com/DataDog/datadog-agent/comp/core/log/def"
+	"github.com/DataDog/datadog-agent/comp/core/secrets"
+	"github.com/DataDog/datadog-agent/pkg/security/rules/sigma"
+	"github.com/DataDog/datadog-agent/pkg/util/fxutil"
+)
+
+type importSigmaCliParams struct {
+	*command.GlobalParams
+
+	paths      []string
+	outputPath string
+	check      bool
+}
+
+func importSigmaCommands(globalParams *command.GlobalParams) []*cobra.Command {
+	cliParams := &importSigmaCliParams{
+		GlobalParams: globalParams,
+	}
+
+	importSigmaCmd := &cobra.Command{
+		Use:   "import-sigma [files or directories]",
+		Short: "Translate Sigma rules into a policy",
+		Long:  "Translate the Linux process_creation, file_event and network_connection Sigma rules into a policy. The rules which can't be translated are reported on the standard error and skipped.",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			cliParams.paths = args
+			return fxutil.OneShot(importSigma,
+				fx.Supply(cliParams),
+				fx.Supply(core.BundleParams{
+					ConfigParams: config.NewSecurityAgentParams(globalParams.ConfigFilePaths, config.WithFleetPoliciesDirPath(globalParams.FleetPoliciesDirPath)),
+					SecretParams: secrets.NewEnabledParams(),
+					LogParams:    log.ForOneShot(command.LoggerName, "off", false)}),
+				core.Bundle(),
+			)
+		},
+	}
+
+	importSigmaCmd.Flags().StringVarP(&cliParams.outputPath, "output", "o", "", "Output path of the policy, the standard output by default")
+	importSigmaCmd.Flags().BoolVar(&cliParams.check, "check", false, "Check the policy before writing it")
+
+	return []*cobra.Command{importSigmaCmd}
+}
+
+func importSigma(_ log.Component, _ config.Component, _ secrets.Component, args *importSigmaCliParams) error {
+	importer := sigma.NewImporter()
+	for _, root := range args.paths {
+		err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if entry.IsDir() {
+				return nil
+			}
+			// the files of the directories are filtered by extension, the ones given explicitly are always imported
+			if ext := strings.ToLower(filepath.Ext(path)); path != root && ext != ".yml" && ext != ".yaml" {
+				return nil
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			importer.Import(path, data)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, diagnostic := range importer.Diagnostics() {
+		fmt.Fprintln(os.Stderr, diagnostic.String())
+	}
+
+	policy := importer.Policy()
+	if len(policy.Rules) == 0 {
+		return errors.New("no Sigma rule could be translated")
+	}
+	fmt.Fprintf(os.Stderr, "%d Sigma rules translated\n", len(policy.Rules))
+
+	b, err := importer.Marshal()
+	if err != nil {
+		return err
+	}
+
+	if args.check {
+		if err := checkSigmaPolicy(b); err != nil {
+			return fmt.Errorf("the generated policy is invalid: %w", err)
+		}
+	}
+
+	if args.outputPath == "" || args.outputPath == "-" {
+		_, err = os.Stdout.Write(b)
+		return err
+	}
+	return os.WriteFile(args.outputPath, b, 0644)
+}
+
+// checkSigmaPolicy loads the policy in a rule set, like the `policy check` command
+func checkSigmaPolicy(policy []byte) error {
+	tempDir, err := os.MkdirTemp("", "policy_check")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tempDir)
+
+	if err := os.WriteFile(filepath.Join(tempDir, "sigma.policy"), policy, 0644); err != nil {
+		return err
+	}
+	return checkPoliciesLocal(&checkPoliciesCliParams{dir: tempDir}, io.Discard)
+}
@@ -16,6 +16,7 @@ import (
 	"net/http"
 	"os"
 	"runtime"
+	"strconv"
 	"strings"
 	"time"
 
@@ -26,10 +27,19 @@ import (
 	"github.com/DataDog/datadog-agent/cmd/system-probe/utils"
 	coreconfig "github.com/DataDog/datadog-agent/pkg/config/setup"
 	"github.com/DataDog/datadog-agent/pkg/network"
+	"github.com/DataDog/datadog-agent/pkg/network/anomaly"
+	"github.com/DataDog/datadog-agent/pkg/network/capture"
 	networkconfig "github.com/DataDog/datadog-agent/pkg/network/config"
+	"github.com/DataDog/datadog-agent/pkg/network/dns"
 	"github.com/DataDog/datadog-agent/pkg/network/encoding/marshal"
+	"github.com/DataDog/datadog-agent/pkg/network/flowexport"
+	"github.com/DataDog/datadog-agent/pkg/network/protocols/cassandra"
+	cassandradebugging "github.com/DataDog/datadog-agent/pkg/network/protocols/cassandra/debugging"
 	httpdebugging "github.com/DataDog/datadog-agent/pkg/network/protocols/http/debugging"
+	"github.com/DataDog/datadog-agent/pkg/network/protocols/http/openapi"
 	kafkadebugging "github.com/DataDog/datadog-agent/pkg/network/protocols/kafka/debugging"
+	"github.com/DataDog/datadog-agent/pkg/network/protocols/memcached"
+	memcacheddebugging "github.com/DataDog/datadog-agent/pkg/network/protocols/memcached/debugging"
 	postgresdebugging "github.com/DataDog/datadog-agent/pkg/network/protocols/postgres/debugging"
 	redisdebugging "github.com/DataDog/datadog-agent/pkg/network/protocols/redis/debugging"
 	"github.com/DataDog/datadog-agent/pkg/network/protocols/telemetry"
@@ -72,15 +82,66 @@ func createNetworkTracerModule(cfg *sysconfigtypes.Config, deps module.FactoryDe
 		startTelemetryReporter(cfg, done)
 	}
 
-	return &networkTracer{tracer: t, done: done}, err
+	// the exporter is started once the module is registered, see Register
+	var exporter *flowexport.Exporter
+	if err == nil && ncfg.EnableFlowExport {
+		var exportErr error
+		if exporter, exportErr = flowexport.NewExporter(ncfg, t); exportErr != nil {
+			log.Errorf("could not create the flow exporter, the connections won't be exported: %s", exportErr)
+		}
+	}
+
+	var anomalyMonitor *anomaly.Monitor
+	if err == nil && ncfg.EnableAnomalyDetection {
+		if anomalyMonitor, err = anomaly.NewMonitor(ncfg, t); err != nil {
+			if exporter != nil {
+				exporter.Stop()
+			}
+			t.Stop()
+			close(done)
+			return nil, err
+		}
+		anomalyMonitor.Start()
+	}
+
+	var openAPIWatcher *openapi.WorkloadWatcher
+	if err == nil && ncfg.ServiceMonitoringEnabled {
+		openapi.DefaultRegistry.LoadSpecFiles(ncfg.HTTPOpenAPISpecs)
+		if ncfg.EnableHTTPOpenAPIAnnotations && deps.WMeta != nil {
+			openAPIWatcher = openapi.NewWorkloadWatcher(deps.WMeta, openapi.DefaultRegistry)
+			openAPIWatcher.Start()
+		}
+	}
+
+	return &networkTracer{
+		tracer:         t,
+		flowExporter:   exporter,
+		anomalyMonitor: anomalyMonitor,
+		openAPIWatcher: openAPIWatcher,
+		packetCapture:  ncfg.EnablePacketCapture,
+		packetCaptureLimits: capture.Limits{
+			MaxDuration: ncfg.PacketCaptureMaxDuration,
+			MaxSize:     ncfg.PacketCaptureMaxSize,
+		},
+		done: done,
+	}, err
 }
 
 var _ module.Module = &networkTracer{}
 
 type networkTracer struct {
 	tracer       *tracer.Tracer
-	done         chan struct{}
-	restartTimer *time.Timer
+	flowExporter *flowexport.Exporter
+	// anomalyMonitor detects the anomalies of the TCP connections, it is nil when the detection is disabled
+	anomalyMonitor *anomaly.Monitor
+	// openAPIWatcher loads the OpenAPI specifications of the workloads
+	openAPIWatcher *openapi.WorkloadWatcher
+	// packetCapture enables the packet capture endpoint, which runs a single capture at a time
+	packetCapture        bool
+	packetCaptureLimits  capture.Limits
+	packetCaptureRunning atomic.Bool
+	done                 chan struct{}
+	restartTimer         *time.Timer
 }
 
 func (nt *networkTracer) GetStats() map[string]interface{} {
@@ -105,9 +166,7 @@ func (nt *networkTracer) Register(httpMux *module.Router) error {
 		marshaler := marshal.GetMarshaler(contentType)
 		writeConnections(w, marshaler, cs)
 
-		if nt.restartTimer != nil {
-			nt.restartTimer.Reset(inactivityRestartDuration)
-		}
+		nt.resetRestartTimer()
 		count := runCounter.Inc()
 		logRequests(id, count, len(cs.Conns), start)
 	}))
@@ -134,6 +193,31 @@ func (nt *networkTracer) Register(httpMux *module.Router) error {
 		}
 	}))
 
+	httpMux.HandleFunc("/dns_events", utils.WithConcurrencyLimit(utils.DefaultMaxConcurrentRequests, func(w http.ResponseWriter, _ *http.Request) {
+		events, err := nt.tracer.GetDNSEvents()
+		if err != nil {
+			log.Errorf("unable to retrieve DNS events: %s", err)
+			w.WriteHeader(500)
+			return
+		}
+		if events == nil {
+			events = []dns.Event{}
+		}
+		utils.WriteAsJSON(w, events)
+	}))
+
+	httpMux.HandleFunc("/anomalies", utils.WithConcurrencyLimit(utils.DefaultMaxConcurrentRequests, func(w http.ResponseWriter, _ *http.Request) {
+		if nt.anomalyMonitor == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		anomalies := nt.anomalyMonitor.GetAnomalies()
+		if anomalies == nil {
+			anomalies = []anomaly.Anomaly{}
+		}
+		utils.WriteAsJSON(w, anomalies)
+	}))
+
 	httpMux.HandleFunc("/debug/net_maps", func(w http.ResponseWriter, req *http.Request) {
 		cs, err := nt.tracer.DebugNetworkMaps()
 		if err != nil {
@@ -222,6 +306,40 @@ func (nt *networkTracer) Register(httpMux *module.Router) error {
 		utils.WriteAsJSON(w, redisdebugging.Redis(cs.Redis))
 	})
 
+	httpMux.HandleFunc("/debug/memcached_monitoring", func(w http.ResponseWriter, req *http.Request) {
+		if !coreconfig.SystemProbe().GetBool("service_monitoring_config.enable_memcached_monitoring") {
+			writeDisabledProtocolMessage("memcached", w)
+			return
+		}
+		// the stats are not sent to the agent, they are only collected by this endpoint
+		stats, err := nt.tracer.DebugProtocolStats("memcached")
+		if err != nil {
+			log.Errorf("unable to retrieve memcached stats: %s", err)
+			w.WriteHeader(500)
+			return
+		}
+
+		memcachedStats, _ := stats.(map[memcached.Key]*memcached.RequestStat)
+		utils.WriteAsJSON(w, memcacheddebugging.Memcached(memcachedStats))
+	})
+
+	httpMux.HandleFunc("/debug/cassandra_monitoring", func(w http.ResponseWriter, req *http.Request) {
+		if !coreconfig.SystemProbe().GetBool("service_monitoring_config.enable_cassandra_monitoring") {
+			writeDisabledProtocolMessage("cassandra", w)
+			return
+		}
+		// the stats are not sent to the agent, they are only collected by this endpoint
+		stats, err := nt.tracer.DebugProtocolStats("cassandra")
+		if err != nil {
+			log.Errorf("unable to retrieve cassandra stats: %s", err)
+			w.WriteHeader(500)
+			return
+		}
+
+		cassandraStats, _ := stats.(map[cassandra.Key]*cassandra.RequestStat)
+		utils.WriteAsJSON(w, cassandradebugging.Cassandra(cassandraStats))
+	})
+
 	httpMux.HandleFunc("/debug/http2_monitoring", func(w http.ResponseWriter, req *http.Request) {
 		if !coreconfig.SystemProbe().GetBool("service_monitoring_config.enable_http2_monitoring") {
 			writeDisabledProtocolMessage("http2", w)
@@ -293,6 +411,8 @@ func (nt *networkTracer) Register(httpMux *module.Router) error {
 		utils.WriteAsJSON(w, cache)
 	})
 
+	httpMux.HandleFunc("/debug/packet_capture", nt.capturePackets)
+
 	httpMux.HandleFunc("/debug/usm_telemetry", telemetry.Handler)
 	httpMux.HandleFunc("/debug/usm/traced_programs", usm.GetTracedProgramsEndpoint(usmconsts.USMModuleName))
 	httpMux.HandleFunc("/debug/usm/blocked_processes", usm.GetBlockedPathIDEndpoint(usmconsts.USMModuleName))
@@ -317,15 +437,86 @@ func (nt *networkTracer) Register(httpMux *module.Router) error {
 		})
 	}
 
+	if nt.flowExporter != nil {
+		// the exported connections are retrieved like the ones queried by the process-agent
+		nt.flowExporter.Start(nt.resetRestartTimer)
+	}
+
 	return nil
 }
 
+// resetRestartTimer postpones the exit of the system-probe when the connections aren't retrieved
+func (nt *networkTracer) resetRestartTimer() {
+	if nt.restartTimer != nil {
+		nt.restartTimer.Reset(inactivityRestartDuration)
+	}
+}
+
 // Close will stop all system probe activities
 func (nt *networkTracer) Close() {
 	close(nt.done)
+	if nt.flowExporter != nil {
+		nt.flowExporter.Stop()
+	}
+	if nt.anomalyMonitor != nil {
+		nt.anomalyMonitor.Stop()
+	}
+	if nt.openAPIWatcher != nil {
+		nt.openAPIWatcher.Stop()
+	}
 	nt.tracer.Stop()
 }
 
+// capturePackets streams a pcapng capture of the packets matching the query parameters, see capture.ParseOptions
+func (nt *networkTracer) capturePackets(w http.ResponseWriter, req *http.Request) {
+	if !nt.packetCapture {
+		writeJSONError(w, http.StatusNotFound, "packet capture is disabled")
+		return
+	}
+	opts, err := capture.ParseOptions(req.URL.Query(), nt.packetCaptureLimits)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !nt.packetCaptureRunning.CompareAndSwap(false, true) {
+		writeJSONError(w, http.StatusConflict, "a packet capture is already running")
+		return
+	}
+	defer nt.packetCaptureRunning.Store(false)
+
+	c, err := capture.NewCapture(opts, nt.tracer)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, capture.ErrNotSupported) {
+			status = http.StatusNotImplemented
+		}
+		writeJSONError(w, status, fmt.Sprintf("unable to start the packet capture: %s", err))
+		return
+	}
+	defer c.Close()
+
+	log.Infof("starting a packet capture of %s (filter: %s)", opts.Duration, opts.Filter)
+	w.Header().Set("Content-Type", "application/x-pcapng")
+	w.Header().Set("Trailer", "X-Capture-Packets, X-Capture-Stop-Reason")
+	w.WriteHeader(http.StatusOK)
+	stats, err := c.Run(req.Context(), w)
+	if err != nil {
+		log.Errorf("packet capture failed after %d packets: %s", stats.Packets, err)
+		return
+	}
+	w.Header().Set("X-Capture-Packets", strconv.Itoa(stats.Packets))
+	w.Header().Set("X-Capture-Stop-Reason", stats.StopReason)
+	log.Infof("packet capture done: %d packets of %d connections captured (%d bytes), %s", stats.Packets, stats.Connections, stats.Bytes, stats.StopReason)
+}
+
+func writeJSONError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	// We are marshaling a string, so we can ignore the error
+	buf, _ := json.Marshal(map[string]string{"error": message})
+	w.Write(buf)
+}
+
 func logRequests(client string, count uint64, connectionsCount int, start time.Time) {
 	args := []interface{}{client, count, connectionsCount, time.Since(start)}
 	msg := "Got request on /connections?client_id=%s (count: %d): retrieved %d connections in %s"
@@ -240,7 +240,7 @@ func (c *collectorImpl) RunCheck(inner check.Check) (checkid.ID, error) {
 		return emptyID, fmt.Errorf("a check with ID %s is already running", ch.ID())
 	}
 
-	if err := c.scheduler.Enter(ch); err != nil {
+	if err := c.scheduler.Enter(ch.Schedulable()); err != nil {
 		return emptyID, fmt.Errorf("unable to schedule the check: %s", err)
 	}
 
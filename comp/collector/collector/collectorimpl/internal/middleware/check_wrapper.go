@@ -7,6 +7,7 @@
 package middleware
 
 import (
+	"context"
 	"sync"
 	"time"
 
@@ -49,6 +50,24 @@ func (c *CheckWrapper) Run() error {
 	return c.inner.Run()
 }
 
+// Schedulable returns the check to schedule, which implements check.ContextRunner only
+// when the wrapped check does, since the workers give the runs of these checks a grace
+// period to return once their timeout is exceeded.
+func (c *CheckWrapper) Schedulable() check.Check {
+	if _, ok := c.inner.(check.ContextRunner); ok {
+		return contextCheckWrapper{c}
+	}
+	return c
+}
+
+// Timeout implements check.TimeoutCheck
+func (c *CheckWrapper) Timeout() time.Duration {
+	if timeoutCheck, ok := c.inner.(check.TimeoutCheck); ok {
+		return timeoutCheck.Timeout()
+	}
+	return 0
+}
+
 // Cancel implements Check#Cancel
 func (c *CheckWrapper) Cancel() {
 	c.inner.Cancel()
@@ -137,3 +156,18 @@ func (c *CheckWrapper) GetDiagnoses() ([]diagnosis.Diagnosis, error) {
 	}
 	return c.inner.GetDiagnoses()
 }
+
+// contextCheckWrapper is the CheckWrapper of a check implementing check.ContextRunner
+type contextCheckWrapper struct {
+	*CheckWrapper
+}
+
+// RunContext implements check.ContextRunner
+func (c contextCheckWrapper) RunContext(ctx context.Context) error {
+	c.runM.Lock()
+	defer c.runM.Unlock()
+	if c.done {
+		return nil
+	}
+	return c.inner.(check.ContextRunner).RunContext(ctx)
+}
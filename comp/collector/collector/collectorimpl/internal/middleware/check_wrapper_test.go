@@ -0,0 +1,111 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+//go:build test
+
+package middleware
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	haagentmock "github.com/DataDog/datadog-agent/comp/haagent/mock"
+	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
+	"github.com/DataDog/datadog-agent/pkg/collector/check"
+	checkid "github.com/DataDog/datadog-agent/pkg/collector/check/id"
+	"github.com/DataDog/datadog-agent/pkg/collector/check/stub"
+	"github.com/DataDog/datadog-agent/pkg/collector/runner/expvars"
+	"github.com/DataDog/datadog-agent/pkg/collector/runner/tracker"
+	"github.com/DataDog/datadog-agent/pkg/collector/worker"
+)
+
+// blockingCheck is a check whose runs block until it's released
+type blockingCheck struct {
+	stub.StubCheck
+	id      string
+	release chan struct{}
+}
+
+func (c *blockingCheck) ID() checkid.ID          { return checkid.ID(c.id) }
+func (c *blockingCheck) String() string          { return checkid.IDToCheckName(c.ID()) }
+func (c *blockingCheck) Interval() time.Duration { return time.Minute }
+func (c *blockingCheck) Timeout() time.Duration  { return 10 * time.Millisecond }
+
+func (c *blockingCheck) Run() error {
+	<-c.release
+	return nil
+}
+
+// contextCheck is a check whose runs block until their context is done
+type contextCheck struct {
+	blockingCheck
+}
+
+func (c *contextCheck) RunContext(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+// runWorker runs a check with a worker, and returns the tracker of the running checks
+func runWorker(t *testing.T, c check.Check) *tracker.RunningChecksTracker {
+	mockSender := mocksender.NewMockSender("")
+	mockSender.SetupAcceptAll()
+
+	checksTracker := tracker.NewRunningChecksTracker()
+	pendingChecksChan := make(chan check.Check, 1)
+	w, err := worker.NewWorker(mockSender.GetSenderManager(), haagentmock.NewMockHaAgent(), 1, 1, pendingChecksChan, checksTracker, func(checkid.ID) bool { return true })
+	require.NoError(t, err)
+
+	pendingChecksChan <- c
+	close(pendingChecksChan)
+	w.Run()
+	return checksTracker
+}
+
+func TestCheckWrapperTimeout(t *testing.T) {
+	expvars.Reset()
+	inner := &blockingCheck{id: "wrapped_blocking", release: make(chan struct{})}
+	wrapper := NewCheckWrapper(inner, nil)
+	c := wrapper.Schedulable()
+	_, isContextRunner := c.(check.ContextRunner)
+	assert.False(t, isContextRunner)
+
+	checksTracker := runWorker(t, c)
+
+	// the run was abandoned when it exceeded the timeout of the wrapped check
+	s, found := expvars.CheckStats(inner.ID())
+	require.True(t, found)
+	assert.Equal(t, uint64(1), s.TotalTimeouts)
+	_, running := checksTracker.Check(inner.ID())
+	assert.True(t, running)
+
+	close(inner.release)
+	assert.Eventually(t, func() bool {
+		_, running := checksTracker.Check(inner.ID())
+		return !running
+	}, time.Second, 10*time.Millisecond)
+	wrapper.Wait()
+}
+
+func TestCheckWrapperRunContext(t *testing.T) {
+	expvars.Reset()
+	inner := &contextCheck{blockingCheck{id: "wrapped_context"}}
+	c := NewCheckWrapper(inner, nil).Schedulable()
+	_, isContextRunner := c.(check.ContextRunner)
+	assert.True(t, isContextRunner)
+
+	checksTracker := runWorker(t, c)
+
+	// the run returned when its context was cancelled
+	s, found := expvars.CheckStats(inner.ID())
+	require.True(t, found)
+	assert.Equal(t, uint64(1), s.TotalTimeouts)
+	_, running := checksTracker.Check(inner.ID())
+	assert.False(t, running)
+}
@@ -118,6 +118,9 @@ type CommonInstanceConfig struct {
 	Name                  string   `yaml:"name"`
 	Namespace             string   `yaml:"namespace"`
 	NoIndex               bool     `yaml:"no_index"`
+	// CheckTimeout is the maximum duration of a run, in seconds. It doesn't use the
+	// `timeout` key which many integrations already use for their own requests.
+	CheckTimeout int `yaml:"check_timeout"`
 }
 
 // CommonGlobalConfig holds the reserved fields for the yaml init_config data
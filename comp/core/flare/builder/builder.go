@@ -135,6 +135,11 @@ type FlareBuilder interface {
 	// RegisterDirPerm add the current permissions for all the files in a directory to the flare's permissions.log.
 	RegisterDirPerm(path string)
 
+	// RegisterSaveCallback registers a function called once the archive has been successfully created by Save. This
+	// allows flare providers to only remove the data they added once it has been archived. The callback must not use
+	// the FlareBuilder.
+	RegisterSaveCallback(callback func())
+
 	// GetFlareArgs will return the struct of caller-provided arguments that can be referenced by various flare providers
 	GetFlareArgs() FlareArgs
 
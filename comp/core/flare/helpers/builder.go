@@ -127,6 +127,9 @@ type builder struct {
 	scrubber *scrubber.Scrubber
 
 	logFile *os.File
+
+	// saveCallbacks are called once the archive has been created
+	saveCallbacks []func()
 }
 
 func getArchiveName() string {
@@ -178,7 +181,14 @@ func (fb *builder) Save() (string, error) {
 		return "", err
 	}
 
-	return archiveFinalPath, os.Rename(archiveTmpPath, archiveFinalPath)
+	if err := os.Rename(archiveTmpPath, archiveFinalPath); err != nil {
+		return archiveFinalPath, err
+	}
+
+	for _, callback := range fb.saveCallbacks {
+		callback()
+	}
+	return archiveFinalPath, nil
 }
 
 func (fb *builder) clean() {
@@ -260,6 +270,16 @@ func (fb *builder) AddFileWithoutScrubbing(destFile string, content []byte) erro
 	return fb.addFile(false, destFile, content)
 }
 
+func (fb *builder) RegisterSaveCallback(callback func()) {
+	fb.Lock()
+	defer fb.Unlock()
+	if fb.isClosed {
+		return
+	}
+
+	fb.saveCallbacks = append(fb.saveCallbacks, callback)
+}
+
 func (fb *builder) GetFlareArgs() types.FlareArgs {
 	return fb.flareArgs
 }
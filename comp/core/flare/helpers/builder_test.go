@@ -352,3 +352,20 @@ func TestRegisterDirPerm(t *testing.T) {
 		assert.Contains(t, fb.permsInfos, path)
 	}
 }
+
+func TestRegisterSaveCallback(t *testing.T) {
+	fb := getNewBuilder(t)
+
+	called := 0
+	fb.RegisterSaveCallback(func() { called++ })
+	assert.Equal(t, 0, called)
+
+	archivePath, err := fb.Save()
+	require.NoError(t, err)
+	defer os.RemoveAll(archivePath)
+	assert.Equal(t, 1, called)
+
+	// the builder is closed
+	fb.RegisterSaveCallback(func() { called++ })
+	assert.Len(t, fb.saveCallbacks, 1)
+}
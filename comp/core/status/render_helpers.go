@@ -297,6 +297,9 @@ func formatTitle(title string) string {
 }
 
 func status(check map[string]interface{}) string {
+	if timedOut, _ := check["LastRunTimedOut"].(bool); timedOut {
+		return fmt.Sprintf("[%s]", color.RedString("TIMEOUT"))
+	}
 	if check["LastError"].(string) != "" {
 		return fmt.Sprintf("[%s]", color.RedString("ERROR"))
 	}
@@ -398,6 +401,9 @@ func lastErrorMessageHTML(value string) htemplate.HTML {
 }
 
 func statusHTML(check map[string]interface{}) htemplate.HTML {
+	if timedOut, _ := check["LastRunTimedOut"].(bool); timedOut {
+		return htemplate.HTML("[<span class=\"error\">TIMEOUT</span>]")
+	}
 	if check["LastError"].(string) != "" {
 		return htemplate.HTML("[<span class=\"error\">ERROR</span>]")
 	}
@@ -28,3 +28,18 @@ func TestMkHuman(t *testing.T) {
 	assert.Equal(t, "1", mkHuman("1"))
 	assert.Equal(t, "1.5", mkHuman(float32(1.5)))
 }
+
+func TestStatus(t *testing.T) {
+	check := map[string]interface{}{"LastError": "", "LastWarnings": []interface{}{}}
+	assert.Contains(t, status(check), "OK")
+
+	check["LastWarnings"] = []interface{}{"warning"}
+	assert.Contains(t, status(check), "WARNING")
+
+	check["LastError"] = "error"
+	assert.Contains(t, status(check), "ERROR")
+
+	check["LastRunTimedOut"] = true
+	assert.Contains(t, status(check), "TIMEOUT")
+	assert.Contains(t, string(statusHTML(check)), "TIMEOUT")
+}
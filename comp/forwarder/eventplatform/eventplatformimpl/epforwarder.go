@@ -400,11 +400,19 @@ func newHTTPPassthroughPipeline(coreConfig model.Reader, eventPlatformReceiver e
 
 	reliable := []client.Destination{}
 	for i, endpoint := range endpoints.GetReliableEndpoints() {
+		if endpoint.IsLocal() {
+			log.Warnf("Ignoring the local endpoint of type %q configured for %s, local endpoints are only supported for logs", endpoint.Type, desc.eventType)
+			continue
+		}
 		destMeta := client.NewDestinationMetadata(desc.eventType, pipelineMonitor.ID(), "reliable", strconv.Itoa(i))
 		reliable = append(reliable, logshttp.NewDestination(endpoint, desc.contentType, destinationsContext, endpoints.BatchMaxConcurrentSend, true, destMeta, pkgconfigsetup.Datadog(), pipelineMonitor))
 	}
 	additionals := []client.Destination{}
 	for i, endpoint := range endpoints.GetUnReliableEndpoints() {
+		if endpoint.IsLocal() {
+			log.Warnf("Ignoring the local endpoint of type %q configured for %s, local endpoints are only supported for logs", endpoint.Type, desc.eventType)
+			continue
+		}
 		destMeta := client.NewDestinationMetadata(desc.eventType, pipelineMonitor.ID(), "unreliable", strconv.Itoa(i))
 		additionals = append(additionals, logshttp.NewDestination(endpoint, desc.contentType, destinationsContext, endpoints.BatchMaxConcurrentSend, false, destMeta, pkgconfigsetup.Datadog(), pipelineMonitor))
 	}
@@ -117,8 +117,14 @@ func (l *LogsConfigKeys) useCompression() bool {
 	return l.getConfig().GetBool(l.getConfigKey("use_compression"))
 }
 
+// hasAdditionalEndpoints returns true if logs are also sent to other intakes, local endpoints don't count.
 func (l *LogsConfigKeys) hasAdditionalEndpoints() bool {
-	return len(l.getAdditionalEndpoints()) > 0
+	for _, e := range l.getAdditionalEndpoints() {
+		if !e.IsLocal() {
+			return true
+		}
+	}
+	return false
 }
 
 // getAPIKeyGetter returns a getter function to retrieve the API key from the configuration. The getter will refetch the
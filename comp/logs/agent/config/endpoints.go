@@ -11,6 +11,7 @@ import (
 
 	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
 	pkgconfigutils "github.com/DataDog/datadog-agent/pkg/config/utils"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
 )
 
 // EPIntakeVersion is the events platform intake API version
@@ -33,6 +34,14 @@ const (
 	EPIntakeVersion2
 )
 
+// Local endpoint types, the logs of these endpoints are written locally instead of being sent to an intake.
+const (
+	// FileEndpointType writes the logs to rotating local files
+	FileEndpointType = "file"
+	// StdoutEndpointType writes the logs to the standard output
+	StdoutEndpointType = "stdout"
+)
+
 // Endpoint holds all the organization and network parameters to send logs to Datadog.
 type Endpoint struct {
 	apiKeyGetter func() string
@@ -57,6 +66,15 @@ type Endpoint struct {
 	TrackType IntakeTrackType
 	Protocol  IntakeProtocol
 	Origin    IntakeOrigin
+
+	// Type is empty for the intake endpoints, or the type of a local endpoint.
+	Type string `mapstructure:"type" json:"type"`
+	// Path is the file the logs are written to, for file endpoints.
+	Path string `mapstructure:"path" json:"path"`
+	// MaxSizeBytes, RotationInterval (in seconds) and MaxFiles control the rotation of the files of file endpoints.
+	MaxSizeBytes     int64 `mapstructure:"max_size_bytes" json:"max_size_bytes"`
+	RotationInterval int   `mapstructure:"rotation_interval" json:"rotation_interval"`
+	MaxFiles         int   `mapstructure:"max_files" json:"max_files"`
 }
 
 // unmarshalEndpoint is used to load additional endpoints from the configuration which stored as JSON/mapstructure.
@@ -120,6 +138,12 @@ func loadTCPAdditionalEndpoints(main Endpoint, l *LogsConfigKeys) []Endpoint {
 
 	newEndpoints := make([]Endpoint, 0, len(additionals))
 	for _, e := range additionals {
+		if e.IsLocal() {
+			if newE, ok := newLocalEndpoint(e); ok {
+				newEndpoints = append(newEndpoints, newE)
+			}
+			continue
+		}
 		newE := NewEndpoint(e.APIKey, e.Host, e.Port, false)
 
 		newE.UseCompression = e.UseCompression
@@ -152,6 +176,12 @@ func loadHTTPAdditionalEndpoints(main Endpoint, l *LogsConfigKeys, intakeTrackTy
 
 	newEndpoints := make([]Endpoint, 0, len(additionals))
 	for _, e := range additionals {
+		if e.IsLocal() {
+			if newE, ok := newLocalEndpoint(e); ok {
+				newEndpoints = append(newEndpoints, newE)
+			}
+			continue
+		}
 		newE := NewEndpoint(e.APIKey, e.Host, e.Port, false)
 
 		newE.UseCompression = main.UseCompression
@@ -189,6 +219,32 @@ func loadHTTPAdditionalEndpoints(main Endpoint, l *LogsConfigKeys, intakeTrackTy
 	return newEndpoints
 }
 
+// newLocalEndpoint returns the local endpoint loaded from 'logs_config.additional_endpoints', the rotated files
+// of file endpoints are compressed when use_compression is set.
+func newLocalEndpoint(e unmarshalEndpoint) (Endpoint, bool) {
+	switch e.Type {
+	case FileEndpointType:
+		if e.Path == "" {
+			log.Warnf("Ignoring the additional file endpoint without a path")
+			return Endpoint{}, false
+		}
+	case StdoutEndpointType:
+	default:
+		log.Warnf("Ignoring the additional endpoint of unknown type %q", e.Type)
+		return Endpoint{}, false
+	}
+
+	newE := NewEndpoint("", "", 0, false)
+	newE.Type = e.Type
+	newE.Path = e.Path
+	newE.UseCompression = e.UseCompression
+	newE.MaxSizeBytes = e.MaxSizeBytes
+	newE.RotationInterval = e.RotationInterval
+	newE.MaxFiles = e.MaxFiles
+	newE.isReliable = e.IsReliable == nil || *e.IsReliable
+	return newE, true
+}
+
 // GetAPIKey returns the latest API Key for the Endpoint, including when the configuration gets updated at runtime
 func (e *Endpoint) GetAPIKey() string {
 	return e.apiKeyGetter()
@@ -199,8 +255,20 @@ func (e *Endpoint) UseSSL() bool {
 	return e.useSSL
 }
 
+// IsLocal returns true if the logs of the endpoint are written locally instead of being sent to an intake.
+func (e *Endpoint) IsLocal() bool {
+	return e.Type != ""
+}
+
 // GetStatus returns the endpoint status
 func (e *Endpoint) GetStatus(prefix string, useHTTP bool) string {
+	switch e.Type {
+	case FileEndpointType:
+		return fmt.Sprintf("%sWriting logs to file %s", prefix, e.Path)
+	case StdoutEndpointType:
+		return fmt.Sprintf("%sWriting logs to stdout", prefix)
+	}
+
 	compression := "uncompressed"
 	if e.UseCompression {
 		compression = "compressed"
@@ -454,6 +454,50 @@ func (suite *EndpointsTestSuite) TestAdditionalEndpointsMappedCorrectly() {
 	suite.Equal("2", endpoint.GetAPIKey())
 }
 
+func (suite *EndpointsTestSuite) TestLocalAdditionalEndpoints() {
+	suite.config.SetWithoutSource("logs_config.additional_endpoints", []map[string]interface{}{
+		{
+			"type":              "file",
+			"path":              "/var/log/datadog/logs.json",
+			"use_compression":   true,
+			"max_size_bytes":    1024,
+			"rotation_interval": 3600,
+			"max_files":         3,
+		},
+		{
+			"type":        "stdout",
+			"is_reliable": false,
+		},
+		// invalid local endpoints are ignored
+		{"type": "file"},
+		{"type": "syslog"},
+	})
+
+	// local endpoints don't prevent using HTTP
+	endpoints, err := BuildEndpoints(suite.config, HTTPConnectivitySuccess, "test-track", "test-proto", "test-source")
+	suite.Nil(err)
+	suite.True(endpoints.UseHTTP)
+	suite.Len(endpoints.Endpoints, 3)
+
+	file := endpoints.Endpoints[1]
+	suite.True(file.IsLocal())
+	suite.True(file.IsReliable())
+	suite.Equal(FileEndpointType, file.Type)
+	suite.Equal("/var/log/datadog/logs.json", file.Path)
+	suite.True(file.UseCompression)
+	suite.Equal(int64(1024), file.MaxSizeBytes)
+	suite.Equal(3600, file.RotationInterval)
+	suite.Equal(3, file.MaxFiles)
+	suite.Equal("Reliable: Writing logs to file /var/log/datadog/logs.json", file.GetStatus("Reliable: ", true))
+
+	stdout := endpoints.Endpoints[2]
+	suite.True(stdout.IsLocal())
+	suite.False(stdout.IsReliable())
+	suite.Equal(StdoutEndpointType, stdout.Type)
+
+	suite.False(endpoints.Main.IsLocal())
+}
+
 func (suite *EndpointsTestSuite) TestIsReliableDefaultTrue() {
 	var (
 		endpoints *Endpoints
@@ -8,6 +8,7 @@ package config
 import (
 	"encoding/json"
 	"fmt"
+	"path/filepath"
 	"strings"
 	"sync"
 
@@ -48,6 +49,9 @@ type LogsConfig struct {
 	Encoding     string   `mapstructure:"encoding" json:"encoding"`             // File
 	ExcludePaths []string `mapstructure:"exclude_paths" json:"exclude_paths"`   // File
 	TailingMode  string   `mapstructure:"start_position" json:"start_position"` // File
+	// ArchivePatterns are the glob patterns of the file names, among the files matching the path,
+	// that are compressed archives (gzip or zstd) to be decompressed and read once.
+	ArchivePatterns []string `mapstructure:"archive_patterns" json:"archive_patterns"` // File
 
 	//nolint:revive // TODO(AML) Fix revive linter
 	ConfigId           string   `mapstructure:"config_id" json:"config_id"`                   // Journald
@@ -88,6 +92,8 @@ type LogsConfig struct {
 	ProcessingRules []*ProcessingRule `mapstructure:"log_processing_rules" json:"log_processing_rules"`
 	// ProcessRawMessage is used to process the raw message instead of only the content part of the message.
 	ProcessRawMessage *bool `mapstructure:"process_raw_message" json:"process_raw_message"`
+	// RateLimit caps the throughput of the source
+	RateLimit *RateLimitConfig `mapstructure:"rate_limit" json:"rate_limit"`
 
 	AutoMultiLine               *bool   `mapstructure:"auto_multi_line_detection" json:"auto_multi_line_detection"`
 	AutoMultiLineSampleSize     int     `mapstructure:"auto_multi_line_sample_size" json:"auto_multi_line_sample_size"`
@@ -123,6 +129,7 @@ func (c *LogsConfig) Dump(multiline bool) string {
 		fmt.Fprintf(&b, ws("Identifier: %#v,"), c.Identifier)
 		fmt.Fprintf(&b, ws("ExcludePaths: %#v,"), c.ExcludePaths)
 		fmt.Fprintf(&b, ws("TailingMode: %#v,"), c.TailingMode)
+		fmt.Fprintf(&b, ws("ArchivePatterns: %#v,"), c.ArchivePatterns)
 	case DockerType, ContainerdType:
 		fmt.Fprintf(&b, ws("Image: %#v,"), c.Image)
 		fmt.Fprintf(&b, ws("Label: %#v,"), c.Label)
@@ -172,17 +179,19 @@ func (c *LogsConfig) PublicJSON() ([]byte, error) {
 	// Export only fields that are explicitly documented in the public documentation
 	return json.Marshal(&struct {
 		Type            string            `json:"type,omitempty"`
-		Port            int               `json:"port,omitempty"`           // Network
-		Path            string            `json:"path,omitempty"`           // File, Journald
-		Encoding        string            `json:"encoding,omitempty"`       // File
-		ExcludePaths    []string          `json:"exclude_paths,omitempty"`  // File
-		TailingMode     string            `json:"start_position,omitempty"` // File
-		ChannelPath     string            `json:"channel_path,omitempty"`   // Windows Event
+		Port            int               `json:"port,omitempty"`             // Network
+		Path            string            `json:"path,omitempty"`             // File, Journald
+		Encoding        string            `json:"encoding,omitempty"`         // File
+		ExcludePaths    []string          `json:"exclude_paths,omitempty"`    // File
+		TailingMode     string            `json:"start_position,omitempty"`   // File
+		ArchivePatterns []string          `json:"archive_patterns,omitempty"` // File
+		ChannelPath     string            `json:"channel_path,omitempty"`     // Windows Event
 		Service         string            `json:"service,omitempty"`
 		Source          string            `json:"source,omitempty"`
 		Tags            []string          `json:"tags,omitempty"`
 		ProcessingRules []*ProcessingRule `json:"log_processing_rules,omitempty"`
 		AutoMultiLine   *bool             `json:"auto_multi_line_detection,omitempty"`
+		RateLimit       *RateLimitConfig  `json:"rate_limit,omitempty"`
 	}{
 		Type:            c.Type,
 		Port:            c.Port,
@@ -190,12 +199,14 @@ func (c *LogsConfig) PublicJSON() ([]byte, error) {
 		Encoding:        c.Encoding,
 		ExcludePaths:    c.ExcludePaths,
 		TailingMode:     c.TailingMode,
+		ArchivePatterns: c.ArchivePatterns,
 		ChannelPath:     c.ChannelPath,
 		Service:         c.Service,
 		Source:          c.Source,
 		Tags:            c.Tags,
 		ProcessingRules: c.ProcessingRules,
 		AutoMultiLine:   c.AutoMultiLine,
+		RateLimit:       c.RateLimit,
 	})
 }
 
@@ -256,11 +267,21 @@ func (c *LogsConfig) Validate() error {
 		if err != nil {
 			return err
 		}
+		for _, pattern := range c.ArchivePatterns {
+			if _, err := filepath.Match(pattern, ""); err != nil {
+				return fmt.Errorf("invalid archive pattern '%v' for %v: %v", pattern, c.Path, err)
+			}
+		}
 	case c.Type == TCPType && c.Port == 0:
 		return fmt.Errorf("tcp source must have a port")
 	case c.Type == UDPType && c.Port == 0:
 		return fmt.Errorf("udp source must have a port")
 	}
+	if c.RateLimit != nil {
+		if err := c.RateLimit.Validate(); err != nil {
+			return err
+		}
+	}
 	err := ValidateProcessingRules(c.ProcessingRules)
 	if err != nil {
 		return err
@@ -19,6 +19,7 @@ import (
 func TestValidateShouldSucceedWithValidConfigs(t *testing.T) {
 	validConfigs := []*LogsConfig{
 		{Type: FileType, Path: "/var/log/foo.log"},
+		{Type: FileType, Path: "/var/log/foo.log*", ArchivePatterns: []string{"*.gz", "foo.log.[0-9].zst"}},
 		{Type: TCPType, Port: 1234},
 		{Type: UDPType, Port: 5678},
 		{Type: DockerType},
@@ -35,6 +36,7 @@ func TestValidateShouldFailWithInvalidConfigs(t *testing.T) {
 	invalidConfigs := []*LogsConfig{
 		{},
 		{Type: FileType},
+		{Type: FileType, Path: "/var/log/foo.log*", ArchivePatterns: []string{"foo.log.[0-9.gz"}},
 		{Type: TCPType},
 		{Type: UDPType},
 		{Type: DockerType, ProcessingRules: []*ProcessingRule{{Name: "foo"}}},
@@ -101,3 +103,23 @@ func TestPublicJSON(t *testing.T) {
 	expectedJSON := `{"type":"file","path":"/var/log/foo.log","encoding":"utf-8","service":"foo","source":"bar","tags":["foo:bar"]}`
 	assert.Equal(t, expectedJSON, string(ret))
 }
+
+func TestValidateRateLimit(t *testing.T) {
+	config := LogsConfig{Type: TCPType, Port: 1234, RateLimit: &RateLimitConfig{MaxEventsPerSecond: 10}}
+	assert.NoError(t, config.Validate())
+	assert.Equal(t, RateLimitDrop, config.RateLimit.Mode)
+	assert.Equal(t, RateLimitScopeSource, config.RateLimit.Scope)
+	assert.Equal(t, DefaultRateLimitSampleRate, config.RateLimit.SampleRate)
+
+	invalid := []*RateLimitConfig{
+		{},
+		{MaxEventsPerSecond: -1},
+		{MaxBytesPerSecond: 10, Mode: "throttle"},
+		{MaxBytesPerSecond: 10, Scope: "host"},
+		{MaxBytesPerSecond: 10, Mode: RateLimitSample, SampleRate: 2},
+	}
+	for _, rateLimit := range invalid {
+		config := LogsConfig{Type: TCPType, Port: 1234, RateLimit: rateLimit}
+		assert.Error(t, config.Validate())
+	}
+}
@@ -8,6 +8,7 @@ package config
 import (
 	"fmt"
 	"regexp"
+	"time"
 )
 
 // Processing rule types
@@ -16,8 +17,12 @@ const (
 	IncludeAtMatch = "include_at_match"
 	MaskSequences  = "mask_sequences"
 	MultiLine      = "multi_line"
+	Deduplicate    = "deduplicate"
 )
 
+// DefaultDeduplicationWindow is the window used by deduplicate rules that don't set one.
+const DefaultDeduplicationWindow = 10 * time.Second
+
 // ProcessingRule defines an exclusion or a masking rule to
 // be applied on log lines
 type ProcessingRule struct {
@@ -25,9 +30,12 @@ type ProcessingRule struct {
 	Name               string
 	ReplacePlaceholder string `mapstructure:"replace_placeholder" json:"replace_placeholder"`
 	Pattern            string
+	// Window is the duration during which identical messages are collapsed (deduplicate only)
+	Window string
 	// TODO: should be moved out
-	Regex       *regexp.Regexp
-	Placeholder []byte
+	Regex          *regexp.Regexp
+	Placeholder    []byte
+	WindowDuration time.Duration
 }
 
 // ValidateProcessingRules validates the rules and raises an error if one is misconfigured.
@@ -44,6 +52,17 @@ func ValidateProcessingRules(rules []*ProcessingRule) error {
 		switch rule.Type {
 		case ExcludeAtMatch, IncludeAtMatch, MaskSequences, MultiLine:
 			break
+		case Deduplicate:
+			if rule.Window != "" {
+				window, err := time.ParseDuration(rule.Window)
+				if err != nil || window <= 0 {
+					return fmt.Errorf("invalid window %s for processing rule: %s", rule.Window, rule.Name)
+				}
+			}
+			// the pattern is optional, it masks the sequences to ignore when comparing messages
+			if rule.Pattern == "" {
+				continue
+			}
 		case "":
 			return fmt.Errorf("type must be set for processing rule `%s`", rule.Name)
 		default:
@@ -79,6 +98,18 @@ func CompileProcessingRules(rules []*ProcessingRule) error {
 			if err != nil {
 				return err
 			}
+		case Deduplicate:
+			if rule.Pattern != "" {
+				rule.Regex = re
+				rule.Placeholder = []byte(rule.ReplacePlaceholder)
+			}
+			rule.WindowDuration = DefaultDeduplicationWindow
+			if rule.Window != "" {
+				rule.WindowDuration, err = time.ParseDuration(rule.Window)
+				if err != nil {
+					return err
+				}
+			}
 		}
 	}
 	return nil
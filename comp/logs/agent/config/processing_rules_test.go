@@ -9,6 +9,7 @@ package config
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -33,3 +34,15 @@ func TestCompileShouldFailWithInvalidRules(t *testing.T) {
 		assert.Nil(t, rule.Regex)
 	}
 }
+
+func TestDeduplicateRule(t *testing.T) {
+	assert.Nil(t, ValidateProcessingRules([]*ProcessingRule{{Type: Deduplicate, Name: "dedup"}}))
+	assert.NotNil(t, ValidateProcessingRules([]*ProcessingRule{{Type: Deduplicate, Name: "dedup", Window: "forever"}}))
+
+	rules := []*ProcessingRule{{Type: Deduplicate, Name: "dedup"}, {Type: Deduplicate, Name: "dedup", Pattern: "[0-9]+", Window: "1m"}}
+	assert.Nil(t, CompileProcessingRules(rules))
+	assert.Equal(t, DefaultDeduplicationWindow, rules[0].WindowDuration)
+	assert.Nil(t, rules[0].Regex)
+	assert.Equal(t, time.Minute, rules[1].WindowDuration)
+	assert.NotNil(t, rules[1].Regex)
+}
@@ -0,0 +1,72 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package config
+
+import (
+	"fmt"
+)
+
+// Rate limit modes
+const (
+	// RateLimitDrop drops the logs over the limit
+	RateLimitDrop = "drop"
+	// RateLimitSample keeps a random sample of the logs over the limit, and all the error logs
+	RateLimitSample = "sample"
+)
+
+// Rate limit scopes
+const (
+	// RateLimitScopeSource applies the limit to the logs of the source alone
+	RateLimitScopeSource = "source"
+	// RateLimitScopeService applies the limit to the logs of all the sources sharing the same service
+	RateLimitScopeService = "service"
+)
+
+// DefaultRateLimitSampleRate is the ratio of logs kept over the limit in sample mode
+const DefaultRateLimitSampleRate = 0.1
+
+// RateLimitConfig caps the throughput of a log source, in events and/or bytes per second.
+type RateLimitConfig struct {
+	MaxEventsPerSecond float64 `mapstructure:"max_events_per_second" json:"max_events_per_second"`
+	MaxBytesPerSecond  float64 `mapstructure:"max_bytes_per_second" json:"max_bytes_per_second"`
+	Mode               string  `mapstructure:"mode" json:"mode"`
+	SampleRate         float64 `mapstructure:"sample_rate" json:"sample_rate"`
+	Scope              string  `mapstructure:"scope" json:"scope"`
+}
+
+// Validate returns an error if the rate limit is misconfigured, and sets the defaults.
+func (r *RateLimitConfig) Validate() error {
+	if r.MaxEventsPerSecond < 0 || r.MaxBytesPerSecond < 0 {
+		return fmt.Errorf("rate limit must be positive")
+	}
+	if r.MaxEventsPerSecond == 0 && r.MaxBytesPerSecond == 0 {
+		return fmt.Errorf("rate limit must set max_events_per_second or max_bytes_per_second")
+	}
+
+	switch r.Mode {
+	case "":
+		r.Mode = RateLimitDrop
+	case RateLimitDrop, RateLimitSample:
+	default:
+		return fmt.Errorf("invalid rate limit mode: %s", r.Mode)
+	}
+
+	switch r.Scope {
+	case "":
+		r.Scope = RateLimitScopeSource
+	case RateLimitScopeSource, RateLimitScopeService:
+	default:
+		return fmt.Errorf("invalid rate limit scope: %s", r.Scope)
+	}
+
+	if r.SampleRate == 0 {
+		r.SampleRate = DefaultRateLimitSampleRate
+	}
+	if r.SampleRate < 0 || r.SampleRate > 1 {
+		return fmt.Errorf("rate limit sample_rate must be between 0 and 1")
+	}
+	return nil
+}
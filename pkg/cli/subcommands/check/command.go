@@ -73,6 +73,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/cli/standalone"
 	pkgcollector "github.com/DataDog/datadog-agent/pkg/collector"
 	"github.com/DataDog/datadog-agent/pkg/collector/check"
+	"github.com/DataDog/datadog-agent/pkg/collector/check/recording"
 	"github.com/DataDog/datadog-agent/pkg/collector/check/stats"
 	"github.com/DataDog/datadog-agent/pkg/collector/python"
 	"github.com/DataDog/datadog-agent/pkg/commonchecks"
@@ -84,6 +85,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/util/fxutil"
 	"github.com/DataDog/datadog-agent/pkg/util/optional"
 	"github.com/DataDog/datadog-agent/pkg/util/scrubber"
+	"github.com/DataDog/datadog-agent/pkg/version"
 )
 
 // cliParams are the command-line arguments for this subcommand
@@ -123,6 +125,8 @@ type cliParams struct {
 	discoveryRetryInterval    uint
 	discoveryMinInstances     uint
 	generateIntegrationTraces bool
+	recordFile                string
+	replayFile                string
 }
 
 // GlobalParams contains the values of agent-global Cobra flags.
@@ -232,6 +236,9 @@ func MakeCommand(globalParamsGetter func() GlobalParams) *cobra.Command {
 	cmd.Flags().BoolVarP(&cliParams.saveFlare, "flare", "", false, "save check results to the log dir so it may be reported in a flare")
 	cmd.Flags().UintVarP(&cliParams.discoveryTimeout, "discovery-timeout", "", 5, "max retry duration until Autodiscovery resolves the check template (in seconds)")
 	cmd.Flags().UintVarP(&cliParams.discoveryRetryInterval, "discovery-retry-interval", "", 1, "(unused)")
+	cmd.Flags().StringVar(&cliParams.recordFile, "record", "", "record the sender calls of the check runs and the configuration of the check, scrubbed of its credentials, to a file")
+	cmd.Flags().StringVar(&cliParams.replayFile, "replay", "", "run the check with the configuration of a recording and compare its submissions with the recording")
+	cmd.MarkFlagsMutuallyExclusive("record", "replay")
 	cmd.Flags().UintVarP(&cliParams.discoveryMinInstances, "discovery-min-instances", "", 1, "minimum number of config instances to be discovered before running the check(s)")
 
 	// Power user flags - mark as hidden
@@ -282,12 +289,25 @@ func run(
 		pkgconfigsetup.Datadog().Set("integration_tracing_exhaustive", true, model.SourceAgentRuntime)
 	}
 
+	var replayed *recording.Recording
+	if cliParams.replayFile != "" {
+		var err error
+		if replayed, err = recording.Load(cliParams.replayFile); err != nil {
+			return err
+		}
+	}
+
 	if len(cliParams.args) != 0 {
 		cliParams.checkName = cliParams.args[0]
+	} else if replayed != nil {
+		cliParams.checkName = replayed.CheckName
 	} else {
 		cliParams.cmd.Help() //nolint:errcheck
 		return nil
 	}
+	if replayed != nil && replayed.CheckName != cliParams.checkName {
+		return fmt.Errorf("the recording %s is a recording of the %s check", cliParams.replayFile, replayed.CheckName)
+	}
 
 	// TODO: (components) - Until the checks are components we set there context so they can depends on components.
 	check.InitializeInventoryChecksContext(invChecks)
@@ -301,13 +321,20 @@ func run(
 	// AutoDiscovery.
 	pkgcollector.InitCheckScheduler(collector, demultiplexer, logReceiver, tagger)
 
-	waitCtx, cancelTimeout := context.WithTimeout(
-		context.Background(), time.Duration(cliParams.discoveryTimeout)*time.Second)
+	var allConfigs []integration.Config
+	var err error
+	if replayed != nil {
+		// replay the runs with the configuration they were recorded with
+		allConfigs = replayed.Configs("replay:" + cliParams.replayFile)
+	} else {
+		waitCtx, cancelTimeout := context.WithTimeout(
+			context.Background(), time.Duration(cliParams.discoveryTimeout)*time.Second)
 
-	allConfigs, err := common.WaitForConfigsFromAD(waitCtx, []string{cliParams.checkName}, int(cliParams.discoveryMinInstances), cliParams.instanceFilter, ac)
-	cancelTimeout()
-	if err != nil {
-		return err
+		allConfigs, err = common.WaitForConfigsFromAD(waitCtx, []string{cliParams.checkName}, int(cliParams.discoveryMinInstances), cliParams.instanceFilter, ac)
+		cancelTimeout()
+		if err != nil {
+			return err
+		}
 	}
 
 	// make sure the checks in cs are not JMX checks
@@ -476,9 +503,26 @@ func run(
 		return err
 	}
 
+	var rec *recording.Recording
+	if cliParams.recordFile != "" || replayed != nil {
+		rec = recording.New(cliParams.checkName, version.AgentVersion)
+	}
+
 	checkRuns := collectorData["runnerStats"].(map[string]interface{})["Checks"].(map[string]interface{})
 	for _, c := range cs {
-		s := runCheck(cliParams, c, printer)
+		var recorder *recording.Sender
+		if rec != nil {
+			if recorder, err = recording.Record(demultiplexer, c.ID()); err != nil {
+				return err
+			}
+		}
+
+		s := runCheck(cliParams, c, printer, recorder)
+		if rec != nil {
+			if err := rec.Add(recorder, c.InitConfig(), c.InstanceConfig()); err != nil {
+				return err
+			}
+		}
 		resultBytes, err := json.Marshal(s)
 		if err != nil {
 			return err
@@ -624,10 +668,40 @@ func run(
 		pkgconfigsetup.Datadog().Set("integration_tracing_exhaustive", previousIntegrationTracingExhaustive, model.SourceAgentRuntime)
 	}
 
+	if cliParams.recordFile != "" {
+		if err := rec.Save(cliParams.recordFile); err != nil {
+			return fmt.Errorf("could not write the recording: %v", err)
+		}
+		fmt.Println("recording written to:", cliParams.recordFile)
+	}
+
+	if replayed != nil {
+		return printReplayDiff(replayed, rec)
+	}
+
 	return nil
 }
 
-func runCheck(cliParams *cliParams, c check.Check, _ aggregator.Demultiplexer) *stats.Stats {
+// printReplayDiff prints the submissions differing between a recording and its replay
+func printReplayDiff(recorded, replayed *recording.Recording) error {
+	diffs := recording.Diff(recorded, replayed)
+	if len(diffs) == 0 {
+		color.Green("The submissions of the check match the recording made with agent %s", recorded.AgentVersion)
+		return nil
+	}
+
+	fmt.Fprintf(color.Output, "\n%s: the submissions of the check differ from the recording made with agent %s\n", color.RedString("Error"), recorded.AgentVersion)
+	for _, d := range diffs {
+		if d.Missing {
+			fmt.Fprintln(color.Output, color.RedString(d.String()))
+		} else {
+			fmt.Fprintln(color.Output, color.GreenString(d.String()))
+		}
+	}
+	return fmt.Errorf("%d differences with the recording", len(diffs))
+}
+
+func runCheck(cliParams *cliParams, c check.Check, _ aggregator.Demultiplexer, recorder *recording.Sender) *stats.Stats {
 	s := stats.NewStats(c)
 	times := cliParams.checkTimes
 	pause := cliParams.checkPause
@@ -650,6 +724,9 @@ func runCheck(cliParams *cliParams, c check.Check, _ aggregator.Demultiplexer) *
 		if pause > 0 && i < times-1 {
 			time.Sleep(time.Duration(pause) * time.Millisecond)
 		}
+		if recorder != nil {
+			recorder.NextRun()
+		}
 	}
 
 	return s
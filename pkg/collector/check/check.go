@@ -7,6 +7,7 @@
 package check
 
 import (
+	"context"
 	"errors"
 	"time"
 
@@ -53,6 +54,19 @@ type Check interface {
 	GetDiagnoses() ([]diagnosis.Diagnosis, error)
 }
 
+// ContextRunner is implemented by the checks able to stop a run early when its context is
+// done, the runner uses it instead of Run to cancel the runs exceeding their timeout.
+type ContextRunner interface {
+	// RunContext runs the check until it completes or the context is done
+	RunContext(ctx context.Context) error
+}
+
+// TimeoutCheck is implemented by the checks whose runs can be bounded by a timeout
+type TimeoutCheck interface {
+	// Timeout returns the maximum duration of a run, 0 if the run isn't bounded
+	Timeout() time.Duration
+}
+
 // Info is an interface to pull information from types capable to run checks. This is a subsection from the Check
 // interface with only read only method.
 type Info interface {
@@ -0,0 +1,118 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package recording
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/metrics/servicecheck"
+)
+
+// Difference is a submission made by only one of the recording and the replay
+type Difference struct {
+	CheckID string
+	// Missing is true if the submission is only in the recording, false if it's only in the replay
+	Missing bool
+	// Submission describes the submission, it's empty when a whole instance is missing
+	Submission string
+}
+
+func (d Difference) String() string {
+	sign := "+"
+	if d.Missing {
+		sign = "-"
+	}
+	if d.Submission == "" {
+		return fmt.Sprintf("%s instance %s", sign, d.CheckID)
+	}
+	return fmt.Sprintf("%s [%s] %s", sign, d.CheckID, d.Submission)
+}
+
+// Diff compares the submissions of a replay with the ones of a recording. The instances
+// are matched by configuration, and the submissions by method, name, hostname and tags,
+// plus the status of the service checks: the values and the number of submissions are
+// ignored since they depend on the state of the monitored system.
+func Diff(recorded, replayed *Recording) []Difference {
+	var diffs []Difference
+	// several instances can have the same configuration, they are matched in order
+	replayedInstances := make(map[string][]int, len(replayed.Instances))
+	for i, instance := range replayed.Instances {
+		key := instance.configKey()
+		replayedInstances[key] = append(replayedInstances[key], i)
+	}
+	matched := make([]bool, len(replayed.Instances))
+
+	for _, instance := range recorded.Instances {
+		key := instance.configKey()
+		candidates := replayedInstances[key]
+		if len(candidates) == 0 {
+			diffs = append(diffs, Difference{CheckID: instance.CheckID, Missing: true})
+			continue
+		}
+		replayedInstance := replayed.Instances[candidates[0]]
+		matched[candidates[0]] = true
+		replayedInstances[key] = candidates[1:]
+
+		expected := submissions(instance.Calls)
+		actual := submissions(replayedInstance.Calls)
+		for _, s := range expected {
+			if _, found := slices.BinarySearch(actual, s); !found {
+				diffs = append(diffs, Difference{CheckID: instance.CheckID, Missing: true, Submission: s})
+			}
+		}
+		for _, s := range actual {
+			if _, found := slices.BinarySearch(expected, s); !found {
+				diffs = append(diffs, Difference{CheckID: instance.CheckID, Submission: s})
+			}
+		}
+	}
+
+	for i, instance := range replayed.Instances {
+		if !matched[i] {
+			diffs = append(diffs, Difference{CheckID: instance.CheckID})
+		}
+	}
+	return diffs
+}
+
+// submissions returns the sorted and deduplicated descriptions of the submissions
+func submissions(calls []Call) []string {
+	var result []string
+	for _, c := range calls {
+		if c.Method == "Commit" {
+			continue
+		}
+		result = append(result, describe(c))
+	}
+	sort.Strings(result)
+	return slices.Compact(result)
+}
+
+func describe(c Call) string {
+	var b strings.Builder
+	b.WriteString(c.Method)
+	if c.Name != "" {
+		b.WriteString(" " + c.Name)
+	}
+	if c.EventType != "" {
+		b.WriteString(" type:" + c.EventType)
+	}
+	if c.Method == "ServiceCheck" {
+		b.WriteString(" status:" + servicecheck.ServiceCheckStatus(c.Status).String())
+	}
+	if c.Hostname != "" {
+		b.WriteString(" host:" + c.Hostname)
+	}
+	if len(c.Tags) > 0 {
+		tags := append([]string{}, c.Tags...)
+		sort.Strings(tags)
+		b.WriteString(" tags:" + strings.Join(tags, ","))
+	}
+	return b.String()
+}
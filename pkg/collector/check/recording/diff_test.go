@@ -0,0 +1,57 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package recording
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	recorded := &Recording{Instances: []Instance{
+		{
+			CheckID:        "test:1",
+			InstanceConfig: "url: http://a\n",
+			Calls: []Call{
+				{Method: "Gauge", Name: "test.gauge", Value: 1, Tags: []string{"b:2", "a:1"}},
+				{Method: "Gauge", Name: "test.gauge", Value: 2, Tags: []string{"a:1", "b:2"}},
+				{Method: "Rate", Name: "test.rate", Value: 1},
+				{Method: "ServiceCheck", Name: "test.can_connect", Status: 0},
+				{Method: "Commit"},
+			},
+		},
+		{CheckID: "test:2", InstanceConfig: "url: http://b\n"},
+	}}
+	replayed := &Recording{Instances: []Instance{
+		{
+			// the check ID of the replayed instance differs
+			CheckID:        "test:4",
+			InstanceConfig: "url: http://a\n",
+			Calls: []Call{
+				{Method: "Gauge", Name: "test.gauge", Value: 5, Tags: []string{"a:1", "b:2"}},
+				{Method: "Gauge", Name: "test.rate", Value: 1},
+				{Method: "ServiceCheck", Name: "test.can_connect", Status: 2, Message: "down"},
+			},
+		},
+		{CheckID: "test:3", InstanceConfig: "url: http://c\n"},
+	}}
+
+	var diffs []string
+	for _, d := range Diff(recorded, replayed) {
+		diffs = append(diffs, d.String())
+	}
+	assert.Equal(t, []string{
+		"- [test:1] Rate test.rate",
+		"- [test:1] ServiceCheck test.can_connect status:OK",
+		"+ [test:1] Gauge test.rate",
+		"+ [test:1] ServiceCheck test.can_connect status:CRITICAL",
+		"- instance test:2",
+		"+ instance test:3",
+	}, diffs)
+
+	assert.Empty(t, Diff(recorded, recorded))
+}
@@ -0,0 +1,186 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+// Package recording records the sender calls made by check runs along with the
+// configuration of the checks, so that the runs can be replayed by another version
+// of the agent and their output compared with the recording.
+package recording
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/metrics/event"
+	"github.com/DataDog/datadog-agent/pkg/util/scrubber"
+)
+
+// formatVersion is the version of the format of the recording files
+const formatVersion = 1
+
+// Recording holds the runs of the instances of a check
+type Recording struct {
+	Version      int        `json:"version"`
+	AgentVersion string     `json:"agent_version"`
+	CheckName    string     `json:"check_name"`
+	RecordedAt   time.Time  `json:"recorded_at"`
+	Instances    []Instance `json:"instances"`
+}
+
+// Instance holds the configuration of a check instance and the sender calls of its runs.
+// The credentials are scrubbed from the configuration.
+type Instance struct {
+	CheckID        string `json:"check_id"`
+	InitConfig     string `json:"init_config"`
+	InstanceConfig string `json:"instance_config"`
+	Calls          []Call `json:"calls"`
+}
+
+// Call is a sender call made by a check
+type Call struct {
+	// Run is the index of the run of the check which made the call
+	Run int `json:"run"`
+	// Time is when the call was made, unix timestamp in seconds
+	Time     float64  `json:"time"`
+	Method   string   `json:"method"`
+	Name     string   `json:"name,omitempty"`
+	Value    Value    `json:"value,omitempty"`
+	Hostname string   `json:"hostname,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+	// Timestamp is the timestamp submitted with the metric, if any
+	Timestamp       float64      `json:"timestamp,omitempty"`
+	FlushFirstValue bool         `json:"flush_first_value,omitempty"`
+	Status          int          `json:"status,omitempty"`
+	Message         string       `json:"message,omitempty"`
+	LowerBound      Value        `json:"lower_bound,omitempty"`
+	UpperBound      Value        `json:"upper_bound,omitempty"`
+	Monotonic       bool         `json:"monotonic,omitempty"`
+	Event           *event.Event `json:"event,omitempty"`
+	EventType       string       `json:"event_type,omitempty"`
+	RawEvent        []byte       `json:"raw_event,omitempty"`
+}
+
+// Value is a float which is encoded as a string in JSON when it's not finite
+type Value float64
+
+// MarshalJSON implements json.Marshaler
+func (v Value) MarshalJSON() ([]byte, error) {
+	f := float64(v)
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return json.Marshal(strconv.FormatFloat(f, 'g', -1, 64))
+	}
+	return json.Marshal(f)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (v *Value) UnmarshalJSON(data []byte) error {
+	var f float64
+	if err := json.Unmarshal(data, &f); err == nil {
+		*v = Value(f)
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("invalid value %s", data)
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return fmt.Errorf("invalid value %q: %v", s, err)
+	}
+	*v = Value(f)
+	return nil
+}
+
+// New returns an empty recording of a check
+func New(checkName, agentVersion string) *Recording {
+	return &Recording{
+		Version:      formatVersion,
+		AgentVersion: agentVersion,
+		CheckName:    checkName,
+		RecordedAt:   time.Now().UTC(),
+	}
+}
+
+// Load reads a recording file
+func Load(path string) (*Recording, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var r Recording
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("could not parse the recording %s: %v", path, err)
+	}
+	if r.Version != formatVersion {
+		return nil, fmt.Errorf("unsupported version %d of the recording %s", r.Version, path)
+	}
+	return &r, nil
+}
+
+// Save writes the recording to a file
+func (r *Recording) Save(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// Add appends the calls recorded by a sender to the recording, with the configuration of the
+// instance scrubbed of its credentials since the recording is written to disk
+func (r *Recording) Add(s *Sender, initConfig, instanceConfig string) error {
+	scrubbedInitConfig, err := scrubConfig(initConfig)
+	if err != nil {
+		return err
+	}
+	scrubbedInstanceConfig, err := scrubConfig(instanceConfig)
+	if err != nil {
+		return err
+	}
+	r.Instances = append(r.Instances, Instance{
+		CheckID:        string(s.checkID),
+		InitConfig:     scrubbedInitConfig,
+		InstanceConfig: scrubbedInstanceConfig,
+		Calls:          s.Calls(),
+	})
+	return nil
+}
+
+func scrubConfig(data string) (string, error) {
+	if data == "" {
+		return "", nil
+	}
+	scrubbed, err := scrubber.ScrubYaml([]byte(data))
+	if err != nil {
+		return "", fmt.Errorf("could not scrub the configuration: %v", err)
+	}
+	return string(scrubbed), nil
+}
+
+// configKey identifies the instance in a recording and its replay. The check IDs can't be
+// used since they depend on the whole configuration of the check, which isn't recorded.
+func (i Instance) configKey() string {
+	return i.InitConfig + "\x00" + i.InstanceConfig
+}
+
+// Configs returns the configurations of the recorded instances, each instance gets its
+// own configuration since the instances may have different init configurations. The
+// instances authenticating with credentials scrubbed from the recording will fail.
+func (r *Recording) Configs(source string) []integration.Config {
+	configs := make([]integration.Config, 0, len(r.Instances))
+	for _, instance := range r.Instances {
+		configs = append(configs, integration.Config{
+			Name:       r.CheckName,
+			InitConfig: integration.Data(instance.InitConfig),
+			Instances:  []integration.Data{integration.Data(instance.InstanceConfig)},
+			Source:     source,
+		})
+	}
+	return configs
+}
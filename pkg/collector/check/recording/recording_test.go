@@ -0,0 +1,77 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package recording
+
+import (
+	"encoding/json"
+	"math"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+)
+
+func TestValueJSON(t *testing.T) {
+	for _, v := range []float64{0, 1.5, -3, math.NaN(), math.Inf(1), math.Inf(-1)} {
+		data, err := json.Marshal(Value(v))
+		require.NoError(t, err)
+
+		var decoded Value
+		require.NoError(t, json.Unmarshal(data, &decoded))
+		if math.IsNaN(v) {
+			assert.True(t, math.IsNaN(float64(decoded)))
+		} else {
+			assert.Equal(t, v, float64(decoded))
+		}
+	}
+
+	var v Value
+	assert.Error(t, json.Unmarshal([]byte(`"foo"`), &v))
+	assert.Error(t, json.Unmarshal([]byte(`true`), &v))
+}
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "recording.json")
+	r := New("http_check", "7.60.0")
+	r.Instances = []Instance{{
+		CheckID:        "http_check:abc",
+		InitConfig:     "timeout: 1",
+		InstanceConfig: "url: http://localhost",
+		Calls: []Call{
+			{Method: "Gauge", Name: "network.http.response_time", Value: Value(math.NaN()), Tags: []string{"url:http://localhost"}},
+			{Method: "ServiceCheck", Name: "http.can_connect", Status: 2, Message: "timeout"},
+		},
+	}}
+	require.NoError(t, r.Save(path))
+
+	loaded, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, "http_check", loaded.CheckName)
+	assert.Equal(t, "7.60.0", loaded.AgentVersion)
+	require.Len(t, loaded.Instances, 1)
+	assert.Equal(t, r.Instances[0].Calls[1], loaded.Instances[0].Calls[1])
+	assert.True(t, math.IsNaN(float64(loaded.Instances[0].Calls[0].Value)))
+
+	assert.Equal(t, []integration.Config{{
+		Name:       "http_check",
+		InitConfig: integration.Data("timeout: 1"),
+		Instances:  []integration.Data{integration.Data("url: http://localhost")},
+		Source:     "replay",
+	}}, loaded.Configs("replay"))
+}
+
+func TestLoadUnsupportedVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "recording.json")
+	r := New("http_check", "7.60.0")
+	r.Version = 42
+	require.NoError(t, r.Save(path))
+
+	_, err := Load(path)
+	assert.ErrorContains(t, err, "unsupported version 42")
+}
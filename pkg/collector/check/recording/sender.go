@@ -0,0 +1,192 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package recording
+
+import (
+	"sync"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator/sender"
+	checkid "github.com/DataDog/datadog-agent/pkg/collector/check/id"
+	"github.com/DataDog/datadog-agent/pkg/metrics/event"
+	"github.com/DataDog/datadog-agent/pkg/metrics/servicecheck"
+)
+
+// Sender records the calls made to the sender of a check before forwarding them to it
+type Sender struct {
+	sender.Sender
+	checkID checkid.ID
+
+	m     sync.Mutex
+	run   int
+	calls []Call
+	now   func() time.Time
+}
+
+// Record replaces the sender of the check by a Sender recording its calls. The senders
+// of the checks are looked up on each run, the runs following this call are recorded.
+func Record(senderManager sender.SenderManager, id checkid.ID) (*Sender, error) {
+	s, err := senderManager.GetSender(id)
+	if err != nil {
+		return nil, err
+	}
+	recorder := newSender(s, id)
+	if err := senderManager.SetSender(recorder, id); err != nil {
+		return nil, err
+	}
+	return recorder, nil
+}
+
+func newSender(s sender.Sender, id checkid.ID) *Sender {
+	return &Sender{
+		Sender:  s,
+		checkID: id,
+		now:     time.Now,
+	}
+}
+
+// NextRun makes the following calls be recorded as part of the next run of the check
+func (s *Sender) NextRun() {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.run++
+}
+
+// Calls returns the recorded calls
+func (s *Sender) Calls() []Call {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return append([]Call{}, s.calls...)
+}
+
+func (s *Sender) record(c Call) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	c.Run = s.run
+	c.Time = float64(s.now().UnixNano()) / float64(time.Second)
+	if c.Tags != nil {
+		// the checks may reuse the slice after the call
+		c.Tags = append([]string{}, c.Tags...)
+	}
+	s.calls = append(s.calls, c)
+}
+
+func (s *Sender) recordMetric(method, metric string, value float64, hostname string, tags []string) {
+	s.record(Call{Method: method, Name: metric, Value: Value(value), Hostname: hostname, Tags: tags})
+}
+
+// Commit records and forwards the call
+func (s *Sender) Commit() {
+	s.record(Call{Method: "Commit"})
+	s.Sender.Commit()
+}
+
+// Gauge records and forwards the call
+func (s *Sender) Gauge(metric string, value float64, hostname string, tags []string) {
+	s.recordMetric("Gauge", metric, value, hostname, tags)
+	s.Sender.Gauge(metric, value, hostname, tags)
+}
+
+// GaugeNoIndex records and forwards the call
+func (s *Sender) GaugeNoIndex(metric string, value float64, hostname string, tags []string) {
+	s.recordMetric("GaugeNoIndex", metric, value, hostname, tags)
+	s.Sender.GaugeNoIndex(metric, value, hostname, tags)
+}
+
+// Rate records and forwards the call
+func (s *Sender) Rate(metric string, value float64, hostname string, tags []string) {
+	s.recordMetric("Rate", metric, value, hostname, tags)
+	s.Sender.Rate(metric, value, hostname, tags)
+}
+
+// Count records and forwards the call
+func (s *Sender) Count(metric string, value float64, hostname string, tags []string) {
+	s.recordMetric("Count", metric, value, hostname, tags)
+	s.Sender.Count(metric, value, hostname, tags)
+}
+
+// MonotonicCount records and forwards the call
+func (s *Sender) MonotonicCount(metric string, value float64, hostname string, tags []string) {
+	s.recordMetric("MonotonicCount", metric, value, hostname, tags)
+	s.Sender.MonotonicCount(metric, value, hostname, tags)
+}
+
+// MonotonicCountWithFlushFirstValue records and forwards the call
+func (s *Sender) MonotonicCountWithFlushFirstValue(metric string, value float64, hostname string, tags []string, flushFirstValue bool) {
+	s.record(Call{Method: "MonotonicCountWithFlushFirstValue", Name: metric, Value: Value(value), Hostname: hostname, Tags: tags, FlushFirstValue: flushFirstValue})
+	s.Sender.MonotonicCountWithFlushFirstValue(metric, value, hostname, tags, flushFirstValue)
+}
+
+// Counter records and forwards the call
+func (s *Sender) Counter(metric string, value float64, hostname string, tags []string) {
+	s.recordMetric("Counter", metric, value, hostname, tags)
+	s.Sender.Counter(metric, value, hostname, tags)
+}
+
+// Histogram records and forwards the call
+func (s *Sender) Histogram(metric string, value float64, hostname string, tags []string) {
+	s.recordMetric("Histogram", metric, value, hostname, tags)
+	s.Sender.Histogram(metric, value, hostname, tags)
+}
+
+// Historate records and forwards the call
+func (s *Sender) Historate(metric string, value float64, hostname string, tags []string) {
+	s.recordMetric("Historate", metric, value, hostname, tags)
+	s.Sender.Historate(metric, value, hostname, tags)
+}
+
+// Distribution records and forwards the call
+func (s *Sender) Distribution(metric string, value float64, hostname string, tags []string) {
+	s.recordMetric("Distribution", metric, value, hostname, tags)
+	s.Sender.Distribution(metric, value, hostname, tags)
+}
+
+// HistogramBucket records and forwards the call
+func (s *Sender) HistogramBucket(metric string, value int64, lowerBound, upperBound float64, monotonic bool, hostname string, tags []string, flushFirstValue bool) {
+	s.record(Call{
+		Method:          "HistogramBucket",
+		Name:            metric,
+		Value:           Value(value),
+		Hostname:        hostname,
+		Tags:            tags,
+		LowerBound:      Value(lowerBound),
+		UpperBound:      Value(upperBound),
+		Monotonic:       monotonic,
+		FlushFirstValue: flushFirstValue,
+	})
+	s.Sender.HistogramBucket(metric, value, lowerBound, upperBound, monotonic, hostname, tags, flushFirstValue)
+}
+
+// GaugeWithTimestamp records and forwards the call
+func (s *Sender) GaugeWithTimestamp(metric string, value float64, hostname string, tags []string, timestamp float64) error {
+	s.record(Call{Method: "GaugeWithTimestamp", Name: metric, Value: Value(value), Hostname: hostname, Tags: tags, Timestamp: timestamp})
+	return s.Sender.GaugeWithTimestamp(metric, value, hostname, tags, timestamp)
+}
+
+// CountWithTimestamp records and forwards the call
+func (s *Sender) CountWithTimestamp(metric string, value float64, hostname string, tags []string, timestamp float64) error {
+	s.record(Call{Method: "CountWithTimestamp", Name: metric, Value: Value(value), Hostname: hostname, Tags: tags, Timestamp: timestamp})
+	return s.Sender.CountWithTimestamp(metric, value, hostname, tags, timestamp)
+}
+
+// ServiceCheck records and forwards the call
+func (s *Sender) ServiceCheck(checkName string, status servicecheck.ServiceCheckStatus, hostname string, tags []string, message string) {
+	s.record(Call{Method: "ServiceCheck", Name: checkName, Status: int(status), Hostname: hostname, Tags: tags, Message: message})
+	s.Sender.ServiceCheck(checkName, status, hostname, tags, message)
+}
+
+// Event records and forwards the call
+func (s *Sender) Event(e event.Event) {
+	recorded := e
+	s.record(Call{Method: "Event", Name: e.Title, Hostname: e.Host, Tags: e.Tags, Event: &recorded})
+	s.Sender.Event(e)
+}
+
+// EventPlatformEvent records and forwards the call
+func (s *Sender) EventPlatformEvent(rawEvent []byte, eventType string) {
+	s.record(Call{Method: "EventPlatformEvent", EventType: eventType, RawEvent: append([]byte{}, rawEvent...)})
+	s.Sender.EventPlatformEvent(rawEvent, eventType)
+}
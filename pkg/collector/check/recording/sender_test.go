@@ -0,0 +1,71 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package recording
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
+	checkid "github.com/DataDog/datadog-agent/pkg/collector/check/id"
+	"github.com/DataDog/datadog-agent/pkg/metrics/event"
+	"github.com/DataDog/datadog-agent/pkg/metrics/servicecheck"
+)
+
+func TestRecord(t *testing.T) {
+	id := checkid.ID("test:123")
+	mockSender := mocksender.NewMockSender(id)
+	mockSender.SetupAcceptAll()
+
+	recorder, err := Record(mockSender.GetSenderManager(), id)
+	require.NoError(t, err)
+	recorder.now = func() time.Time { return time.Unix(1700000000, 500000000) }
+
+	// the check gets the recorder when it looks its sender up
+	s, err := mockSender.GetSenderManager().GetSender(id)
+	require.NoError(t, err)
+	assert.Same(t, recorder, s)
+
+	tags := []string{"foo:bar"}
+	s.Gauge("test.gauge", 1, "host", tags)
+	tags[0] = "reused"
+	s.ServiceCheck("test.can_connect", servicecheck.ServiceCheckCritical, "", nil, "down")
+	s.Commit()
+	recorder.NextRun()
+	s.MonotonicCountWithFlushFirstValue("test.count", 3, "", nil, true)
+	s.Event(event.Event{Title: "restarted", Tags: []string{"a:b"}})
+	s.EventPlatformEvent([]byte("{}"), "dbm-samples")
+
+	// the calls are forwarded
+	mockSender.AssertMetric(t, "Gauge", "test.gauge", 1, "host", []string{"reused"})
+	mockSender.AssertServiceCheck(t, "test.can_connect", servicecheck.ServiceCheckCritical, "", nil, "down")
+	mockSender.AssertNumberOfCalls(t, "Commit", 1)
+
+	calls := recorder.Calls()
+	require.Len(t, calls, 6)
+	assert.Equal(t, Call{Run: 0, Time: 1700000000.5, Method: "Gauge", Name: "test.gauge", Value: 1, Hostname: "host", Tags: []string{"foo:bar"}}, calls[0])
+	assert.Equal(t, Call{Run: 0, Time: 1700000000.5, Method: "ServiceCheck", Name: "test.can_connect", Status: 2, Message: "down"}, calls[1])
+	assert.Equal(t, "Commit", calls[2].Method)
+	assert.Equal(t, Call{Run: 1, Time: 1700000000.5, Method: "MonotonicCountWithFlushFirstValue", Name: "test.count", Value: 3, FlushFirstValue: true}, calls[3])
+	assert.Equal(t, "restarted", calls[4].Name)
+	assert.Equal(t, []string{"a:b"}, calls[4].Tags)
+	assert.Equal(t, Call{Run: 1, Time: 1700000000.5, Method: "EventPlatformEvent", EventType: "dbm-samples", RawEvent: []byte("{}")}, calls[5])
+
+	r := New("test", "7.60.0")
+	require.NoError(t, r.Add(recorder, "init: 1", "host: db\npassword: secret"))
+	require.Len(t, r.Instances, 1)
+	assert.Equal(t, "test:123", r.Instances[0].CheckID)
+	assert.Equal(t, "init: 1", r.Instances[0].InitConfig)
+	// the credentials are scrubbed, and scrubbing the recorded configuration again on replay doesn't change it
+	assert.Equal(t, "host: db\npassword: \"********\"", r.Instances[0].InstanceConfig)
+	scrubbed, err := scrubConfig(r.Instances[0].InstanceConfig)
+	require.NoError(t, err)
+	assert.Equal(t, r.Instances[0].InstanceConfig, scrubbed)
+	assert.Len(t, r.Instances[0].Calls, 6)
+}
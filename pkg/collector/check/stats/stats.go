@@ -7,6 +7,7 @@
 package stats
 
 import (
+	"errors"
 	"sync"
 	"time"
 
@@ -22,8 +23,12 @@ import (
 const (
 	runCheckFailureTag = "fail"
 	runCheckSuccessTag = "ok"
+	runCheckTimeoutTag = "timeout"
 )
 
+// ErrTimeout is wrapped by the errors of the check runs which exceeded their timeout
+var ErrTimeout = errors.New("check run timed out")
+
 // EventPlatformNameTranslations contains human readable translations for event platform event types
 var EventPlatformNameTranslations = map[string]string{
 	"dbm-samples":                "Database Monitoring Query Samples",
@@ -100,6 +105,8 @@ type Stats struct {
 	Cancelling               bool
 	TotalRuns                uint64
 	TotalErrors              uint64
+	TotalTimeouts            uint64
+	LastRunTimedOut          bool // true if the last run exceeded the timeout of the check
 	TotalWarnings            uint64
 	MetricSamples            int64
 	Events                   int64
@@ -155,6 +162,7 @@ func NewStats(c StatsCheck) *Stats {
 	if stats.Telemetry && utils.IsTelemetryEnabled(pkgconfigsetup.Datadog()) {
 		tlmRuns.InitializeToZero(stats.CheckName, runCheckFailureTag)
 		tlmRuns.InitializeToZero(stats.CheckName, runCheckSuccessTag)
+		tlmRuns.InitializeToZero(stats.CheckName, runCheckTimeoutTag)
 	}
 
 	return &stats
@@ -188,7 +196,15 @@ func (cs *Stats) Add(t time.Duration, err error, warnings []error, metricStats S
 		totalExecutionTime += cs.ExecutionTimes[i]
 	}
 	cs.AverageExecutionTime = totalExecutionTime / int64(ringSize)
-	if err != nil {
+	cs.LastRunTimedOut = errors.Is(err, ErrTimeout)
+	if cs.LastRunTimedOut {
+		cs.TotalErrors++
+		cs.TotalTimeouts++
+		if cs.Telemetry {
+			tlmRuns.Inc(cs.CheckName, runCheckTimeoutTag)
+		}
+		cs.LastError = err.Error()
+	} else if err != nil {
 		cs.TotalErrors++
 		if cs.Telemetry {
 			tlmRuns.Inc(cs.CheckName, runCheckFailureTag)
@@ -6,6 +6,8 @@
 package stats
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -98,6 +100,30 @@ func TestNewStatsStateTelemetryInitialized(t *testing.T) {
 		tlmData,
 		"checks__runs{check_name=\"checkString\",state=\"ok\"} 0",
 	)
+	assert.Contains(
+		t,
+		tlmData,
+		"checks__runs{check_name=\"checkString\",state=\"timeout\"} 0",
+	)
+}
+
+func TestAddTimeout(t *testing.T) {
+	stats := NewStats(newMockCheck())
+
+	stats.Add(time.Second, fmt.Errorf("%w after 1s", ErrTimeout), nil, SenderStats{})
+	assert.True(t, stats.LastRunTimedOut)
+	assert.Equal(t, uint64(1), stats.TotalTimeouts)
+	assert.Equal(t, uint64(1), stats.TotalErrors)
+	assert.Equal(t, "check run timed out after 1s", stats.LastError)
+
+	stats.Add(time.Second, errors.New("failure"), nil, SenderStats{})
+	assert.False(t, stats.LastRunTimedOut)
+	assert.Equal(t, uint64(1), stats.TotalTimeouts)
+	assert.Equal(t, uint64(2), stats.TotalErrors)
+
+	stats.Add(time.Second, nil, nil, SenderStats{})
+	assert.False(t, stats.LastRunTimedOut)
+	assert.Equal(t, "", stats.LastError)
 }
 
 func TestTranslateEventPlatformEventTypes(t *testing.T) {
@@ -47,6 +47,7 @@ type CheckBase struct {
 	checkID        checkid.ID
 	latestWarnings []error
 	checkInterval  time.Duration
+	checkTimeout   time.Duration
 	source         string
 	telemetry      bool
 	initConfig     string
@@ -111,6 +112,11 @@ func (c *CheckBase) CommonConfigure(senderManager sender.SenderManager, initConf
 			c.checkInterval = time.Duration(commonOptions.MinCollectionInterval) * time.Second
 		}
 
+		// See if a run timeout was specified
+		if commonOptions.CheckTimeout > 0 {
+			c.checkTimeout = time.Duration(commonOptions.CheckTimeout) * time.Second
+		}
+
 		// Disable default hostname if specified
 		if commonOptions.EmptyDefaultHostname {
 			s, err := c.GetSender()
@@ -197,6 +203,11 @@ func (c *CheckBase) Interval() time.Duration {
 	return c.checkInterval
 }
 
+// Timeout returns the maximum duration of a run of the check, 0 if it isn't bounded.
+func (c *CheckBase) Timeout() time.Duration {
+	return c.checkTimeout
+}
+
 // String returns the name of the check, the same for every instance
 func (c *CheckBase) String() string {
 	return c.checkName
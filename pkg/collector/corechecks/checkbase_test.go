@@ -40,6 +40,7 @@ func TestCommonConfigure(t *testing.T) {
 	err := mycheck.CommonConfigure(mockSender.GetSenderManager(), nil, []byte(defaultsInstance), "test")
 	assert.NoError(t, err)
 	assert.Equal(t, defaults.DefaultCheckInterval, mycheck.Interval())
+	assert.Equal(t, time.Duration(0), mycheck.Timeout())
 	mockSender.AssertNumberOfCalls(t, "DisableDefaultHostname", 0)
 
 	mockSender.On("DisableDefaultHostname", true).Return().Once()
@@ -68,3 +69,14 @@ func TestCommonConfigureCustomID(t *testing.T) {
 	assert.Equal(t, string(mycheck.ID()), "test:foobar:a934df33209f45f4")
 	mockSender.AssertExpectations(t)
 }
+
+func TestCommonConfigureTimeout(t *testing.T) {
+	mycheck := &dummyCheck{
+		CheckBase: NewCheckBase("test"),
+	}
+	mockSender := mocksender.NewMockSender(mycheck.ID())
+
+	err := mycheck.CommonConfigure(mockSender.GetSenderManager(), nil, []byte("check_timeout: 30"), "test")
+	assert.NoError(t, err)
+	assert.Equal(t, 30*time.Second, mycheck.Timeout())
+}
@@ -143,6 +143,9 @@ func (p *Processor) processContainer(sender sender.Sender, tags []string, contai
 		p.sendMetric(sender.Rate, "container.cpu.throttled", containerStats.CPU.ThrottledTime, tags)
 		p.sendMetric(sender.Rate, "container.cpu.throttled.periods", containerStats.CPU.ThrottledPeriods, tags)
 		p.sendMetric(sender.Rate, "container.cpu.partial_stall", containerStats.CPU.PartialStallTime, tags)
+		p.sendMetric(sender.Rate, "container.cpu.full_stall", containerStats.CPU.FullStallTime, tags)
+		p.sendPressureAverages(sender, "container.cpu.partial_stall", containerStats.CPU.PartialStall, tags)
+		p.sendPressureAverages(sender, "container.cpu.full_stall", containerStats.CPU.FullStall, tags)
 		// Convert CPU Limit to nanoseconds to allow easy percentage computation in the App.
 		if containerStats.CPU.Limit != nil {
 			p.sendMetric(sender.Gauge, "container.cpu.limit", pointer.Ptr(*containerStats.CPU.Limit*float64(time.Second/100)), tags)
@@ -164,6 +167,9 @@ func (p *Processor) processContainer(sender sender.Sender, tags []string, contai
 		p.sendMetric(sender.Gauge, "container.memory.commit.peak", containerStats.Memory.CommitPeakBytes, tags)
 		p.sendMetric(sender.Gauge, "container.memory.usage.peak", containerStats.Memory.Peak, tags)
 		p.sendMetric(sender.Rate, "container.memory.partial_stall", containerStats.Memory.PartialStallTime, tags)
+		p.sendMetric(sender.Rate, "container.memory.full_stall", containerStats.Memory.FullStallTime, tags)
+		p.sendPressureAverages(sender, "container.memory.partial_stall", containerStats.Memory.PartialStall, tags)
+		p.sendPressureAverages(sender, "container.memory.full_stall", containerStats.Memory.FullStall, tags)
 		p.sendMetric(sender.MonotonicCount, "container.memory.page_faults", containerStats.Memory.Pgfault, tags)
 		p.sendMetric(sender.MonotonicCount, "container.memory.major_page_faults", containerStats.Memory.Pgmajfault, tags)
 	}
@@ -185,6 +191,9 @@ func (p *Processor) processContainer(sender sender.Sender, tags []string, contai
 		}
 
 		p.sendMetric(sender.Rate, "container.io.partial_stall", containerStats.IO.PartialStallTime, tags)
+		p.sendMetric(sender.Rate, "container.io.full_stall", containerStats.IO.FullStallTime, tags)
+		p.sendPressureAverages(sender, "container.io.partial_stall", containerStats.IO.PartialStall, tags)
+		p.sendPressureAverages(sender, "container.io.full_stall", containerStats.IO.FullStall, tags)
 	}
 
 	if containerStats.PID != nil {
@@ -209,3 +218,14 @@ func (p *Processor) sendMetric(senderFunc func(string, float64, string, []string
 		senderFunc(metricName, val, "", tags)
 	}
 }
+
+// sendPressureAverages sends the share of time, in percent, the tasks were stalled over the last 10, 60 and 300 seconds
+func (p *Processor) sendPressureAverages(sender sender.Sender, metricPrefix string, averages *metrics.PressureStallAverages, tags []string) {
+	if averages == nil {
+		return
+	}
+
+	p.sendMetric(sender.Gauge, metricPrefix+".avg10", averages.Avg10, tags)
+	p.sendMetric(sender.Gauge, metricPrefix+".avg60", averages.Avg60, tags)
+	p.sendMetric(sender.Gauge, metricPrefix+".avg300", averages.Avg300, tags)
+}
@@ -39,8 +39,8 @@ func TestProcessorRunFullStatsLinux(t *testing.T) {
 	assert.ErrorIs(t, err, nil)
 
 	expectedTags := []string{"runtime:docker"}
-	mockSender.AssertNumberOfCalls(t, "Rate", 20)
-	mockSender.AssertNumberOfCalls(t, "Gauge", 17)
+	mockSender.AssertNumberOfCalls(t, "Rate", 21)
+	mockSender.AssertNumberOfCalls(t, "Gauge", 23)
 
 	mockSender.AssertMetricInRange(t, "Gauge", "container.uptime", 0, 600, "", expectedTags)
 	mockSender.AssertMetric(t, "Rate", "container.cpu.usage", 100, "", expectedTags)
@@ -65,6 +65,13 @@ func TestProcessorRunFullStatsLinux(t *testing.T) {
 	mockSender.AssertMetric(t, "Gauge", "container.restarts", 42, "", expectedTags)
 
 	mockSender.AssertMetric(t, "Rate", "container.io.partial_stall", 98000, "", expectedTags)
+	mockSender.AssertMetric(t, "Rate", "container.io.full_stall", 48000, "", expectedTags)
+	mockSender.AssertMetric(t, "Gauge", "container.io.partial_stall.avg10", 2.5, "", expectedTags)
+	mockSender.AssertMetric(t, "Gauge", "container.io.partial_stall.avg60", 1.5, "", expectedTags)
+	mockSender.AssertMetric(t, "Gauge", "container.io.partial_stall.avg300", 0.5, "", expectedTags)
+	mockSender.AssertMetric(t, "Gauge", "container.io.full_stall.avg10", 1.25, "", expectedTags)
+	mockSender.AssertMetric(t, "Gauge", "container.io.full_stall.avg60", 0.75, "", expectedTags)
+	mockSender.AssertMetric(t, "Gauge", "container.io.full_stall.avg300", 0.25, "", expectedTags)
 	expectedFooTags := taggerUtils.ConcatenateStringTags(expectedTags, "device:/dev/foo", "device_name:/dev/foo")
 	mockSender.AssertMetric(t, "Rate", "container.io.read", 100, "", expectedFooTags)
 	mockSender.AssertMetric(t, "Rate", "container.io.read.operations", 10, "", expectedFooTags)
@@ -0,0 +1,79 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+// Package customqueries holds the code shared by the checks submitting the results of
+// custom queries as metrics, like the oracle and sql_query checks.
+package customqueries
+
+import (
+	"fmt"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator/sender"
+)
+
+// MetricType is the type of the metrics submitted for a column of a custom query
+type MetricType int
+
+// Metric types of the columns of the custom queries
+const (
+	UnknownMetricType MetricType = iota
+	Gauge
+	Count
+	Rate
+	MonotonicCount
+	Histogram
+	Historate
+)
+
+var metricTypes = map[string]MetricType{
+	"gauge":           Gauge,
+	"count":           Count,
+	"rate":            Rate,
+	"monotonic_count": MonotonicCount,
+	"histogram":       Histogram,
+	"historate":       Historate,
+}
+
+// MetricRow is a metric read from a row returned by a custom query
+type MetricRow struct {
+	Name   string
+	Value  float64
+	Method MetricType
+	Tags   []string
+}
+
+// MetricSender submits a metric with its value, hostname and tags
+type MetricSender func(string, float64, string, []string)
+
+// ParseMetricType returns the metric type named in the configuration of a column
+func ParseMetricType(name string) (MetricType, error) {
+	if t, ok := metricTypes[name]; ok {
+		return t, nil
+	}
+	return UnknownMetricType, fmt.Errorf("unknown metric type: %s", name)
+}
+
+// GetMetricFunction returns the function of the sender submitting the metrics of a type
+func GetMetricFunction(s sender.Sender, method MetricType) (MetricSender, error) {
+	if s == nil {
+		return nil, fmt.Errorf("sender is nil")
+	}
+	switch method {
+	case Gauge:
+		return s.Gauge, nil
+	case Count:
+		return s.Count, nil
+	case Rate:
+		return s.Rate, nil
+	case MonotonicCount:
+		return s.MonotonicCount, nil
+	case Histogram:
+		return s.Histogram, nil
+	case Historate:
+		return s.Historate, nil
+	default:
+		return nil, fmt.Errorf("invalid metric type %d", method)
+	}
+}
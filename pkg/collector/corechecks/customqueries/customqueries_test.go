@@ -0,0 +1,43 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package customqueries
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
+)
+
+func TestParseMetricType(t *testing.T) {
+	method, err := ParseMetricType("monotonic_count")
+	require.NoError(t, err)
+	assert.Equal(t, MonotonicCount, method)
+
+	_, err = ParseMetricType("tag")
+	assert.EqualError(t, err, "unknown metric type: tag")
+}
+
+func TestGetMetricFunction(t *testing.T) {
+	s := mocksender.NewMockSender("")
+	s.SetupAcceptAll()
+
+	for name, method := range metricTypes {
+		submit, err := GetMetricFunction(s, method)
+		require.NoError(t, err, name)
+		submit("metric."+name, 1, "host", []string{"foo:bar"})
+	}
+	s.AssertMetric(t, "Gauge", "metric.gauge", 1, "host", []string{"foo:bar"})
+	s.AssertMetric(t, "MonotonicCount", "metric.monotonic_count", 1, "host", []string{"foo:bar"})
+	s.AssertMetric(t, "Historate", "metric.historate", 1, "host", []string{"foo:bar"})
+
+	_, err := GetMetricFunction(s, UnknownMetricType)
+	assert.Error(t, err)
+	_, err = GetMetricFunction(nil, Gauge)
+	assert.Error(t, err)
+}
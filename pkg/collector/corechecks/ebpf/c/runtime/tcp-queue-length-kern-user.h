@@ -12,4 +12,19 @@ struct stats_value {
     __u32 write_buffer_max_usage;
 };
 
+struct conn_key {
+    char cgroup[129];
+    __u32 pid;
+    __u8 saddr[16];
+    __u8 daddr[16];
+    __u16 sport;
+    __u16 dport;
+    __u16 family;
+};
+
+struct zero_window_value {
+    __u32 local_zero_window;
+    __u32 peer_zero_window;
+};
+
 #endif /* defined(TCP_QUEUE_LENGTH_KERN_USER_H) */
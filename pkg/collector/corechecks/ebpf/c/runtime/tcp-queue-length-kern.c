@@ -16,6 +16,7 @@
 
 #include "bpf_tracing.h"
 #include "bpf_core_read.h"
+#include "bpf_endian.h"
 #include "map-defs.h"
 
 /*
@@ -24,6 +25,12 @@
  */
 BPF_PERCPU_HASH_MAP(tcp_queue_stats, struct stats_key, struct stats_value, 1024)
 
+/*
+ * The `tcp_zero_window` map is used to share with the userland program system-probe
+ * the connections which advertised, or whose peer advertised, a zero window
+ */
+BPF_PERCPU_HASH_MAP(tcp_zero_window, struct conn_key, struct zero_window_value, 1024)
+
 /*
  * the `who_recvmsg` and `who_sendmsg` maps are used to remind the sock pointer
  * received as input parameter when we are in the kretprobe of tcp_recvmsg and tcp_sendmsg.
@@ -32,6 +39,67 @@ BPF_HASH_MAP(who_recvmsg, u64, struct sock *, 100)
 
 BPF_HASH_MAP(who_sendmsg, u64, struct sock *, 100)
 
+#ifdef COMPILE_CORE
+#define TCP_QUEUE_LENGTH_IPV6 1
+#elif IS_ENABLED(CONFIG_IPV6)
+#define TCP_QUEUE_LENGTH_IPV6 1
+#endif
+
+#define TCP_ESTABLISHED_STATE 1
+#define AF_INET_FAMILY 2
+#define AF_INET6_FAMILY 10
+
+static __always_inline void check_zero_window(struct sock *sk) {
+    if (BPF_CORE_READ(sk, __sk_common.skc_state) != TCP_ESTABLISHED_STATE) {
+        return;
+    }
+
+    const struct tcp_sock *tp = (struct tcp_sock *)sk;
+    u32 rcv_wnd = BPF_CORE_READ(tp, rcv_wnd); // Window we advertised to the peer
+    u32 snd_wnd = BPF_CORE_READ(tp, snd_wnd); // Window the peer advertised to us
+    if (rcv_wnd != 0 && snd_wnd != 0) {
+        return;
+    }
+
+    struct conn_key k;
+    __builtin_memset(&k, 0, sizeof(k));
+    if (!get_cgroup_name(k.cgroup, sizeof(k.cgroup))) {
+        return;
+    }
+    k.pid = bpf_get_current_pid_tgid() >> 32;
+    k.family = BPF_CORE_READ(sk, __sk_common.skc_family);
+    if (k.family == AF_INET_FAMILY) {
+        BPF_CORE_READ_INTO((__u32 *)k.saddr, sk, __sk_common.skc_rcv_saddr);
+        BPF_CORE_READ_INTO((__u32 *)k.daddr, sk, __sk_common.skc_daddr);
+#ifdef TCP_QUEUE_LENGTH_IPV6
+    } else if (k.family == AF_INET6_FAMILY) {
+        BPF_CORE_READ_INTO(&k.saddr, sk, __sk_common.skc_v6_rcv_saddr);
+        BPF_CORE_READ_INTO(&k.daddr, sk, __sk_common.skc_v6_daddr);
+#endif
+    } else {
+        return;
+    }
+    k.sport = BPF_CORE_READ(sk, __sk_common.skc_num);
+    k.dport = bpf_ntohs(BPF_CORE_READ(sk, __sk_common.skc_dport));
+
+    struct zero_window_value zero = {
+        .local_zero_window = 0,
+        .peer_zero_window = 0
+    };
+    bpf_map_update_elem(&tcp_zero_window, &k, &zero, BPF_NOEXIST);
+    struct zero_window_value *v = bpf_map_lookup_elem(&tcp_zero_window, &k);
+    if (!v) {
+        return;
+    }
+    if (rcv_wnd == 0) {
+        v->local_zero_window++;
+    }
+    if (snd_wnd == 0) {
+        v->peer_zero_window++;
+    }
+    log_debug("check_zero_window: name=%s pid=%d local=%d", k.cgroup, k.pid, v->local_zero_window);
+}
+
 static __always_inline void check_sock(struct sock *sk) {
     struct stats_value zero = {
         .read_buffer_max_usage = 0,
@@ -74,6 +142,8 @@ static __always_inline void check_sock(struct sock *sk) {
         v->write_buffer_max_usage = wqueue_usage;
     }
     log_debug("check_sock: name=%s read_max=%d write_max=%d", k.cgroup, v->read_buffer_max_usage, v->write_buffer_max_usage);
+
+    check_zero_window(sk);
 }
 
 SEC("kprobe/tcp_recvmsg")
@@ -15,6 +15,23 @@ type TCPQueueLengthStatsKey struct {
 type TCPQueueLengthStatsValue struct {
 	ReadBufferMaxUsage  uint32 `json:"read_buffer_max_usage"`
 	WriteBufferMaxUsage uint32 `json:"write_buffer_max_usage"`
+	// ZeroWindowConnections are the connections of the container which advertised, or whose peer advertised, a zero
+	// window
+	ZeroWindowConnections []ZeroWindowConnection `json:"zero_window_connections,omitempty"`
+}
+
+// ZeroWindowConnection is a connection which advertised, or whose peer advertised, a zero window while its process was
+// reading or writing it
+type ZeroWindowConnection struct {
+	PID    uint32 `json:"pid"`
+	Source string `json:"source"`
+	Dest   string `json:"dest"`
+	// LocalZeroWindow is the number of reads and writes during which the connection advertised a zero window to its
+	// peer, because its read buffer was full
+	LocalZeroWindow uint32 `json:"local_zero_window"`
+	// PeerZeroWindow is the number of reads and writes during which the peer advertised a zero window to the
+	// connection, which can't send anymore
+	PeerZeroWindow uint32 `json:"peer_zero_window"`
 }
 
 // TCPQueueLengthStats is the map of the maximum fill rate of the read and write buffers per container
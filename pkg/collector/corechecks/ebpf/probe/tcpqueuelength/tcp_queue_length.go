@@ -13,6 +13,8 @@ package tcpqueuelength
 
 import (
 	"fmt"
+	"net/netip"
+
 	"golang.org/x/sys/unix"
 
 	manager "github.com/DataDog/ebpf-manager"
@@ -28,13 +30,17 @@ import (
 )
 
 const (
-	statsMapName = "tcp_queue_stats"
+	statsMapName      = "tcp_queue_stats"
+	zeroWindowMapName = "tcp_zero_window"
+
+	afInet6 = 10
 )
 
 // Tracer is the eBPF side of the TCP Queue Length check
 type Tracer struct {
-	m        *manager.Manager
-	statsMap *ebpfmaps.GenericMap[StructStatsKey, []StructStatsValue]
+	m             *manager.Manager
+	statsMap      *ebpfmaps.GenericMap[StructStatsKey, []StructStatsValue]
+	zeroWindowMap *ebpfmaps.GenericMap[StructConnKey, []StructZeroWindowValue]
 }
 
 // NewTracer creates a [Tracer]
@@ -64,6 +70,7 @@ func startTCPQueueLengthProbe(buf bytecode.AssetReader, managerOptions manager.O
 
 	maps := []*manager.Map{
 		{Name: "tcp_queue_stats"},
+		{Name: "tcp_zero_window"},
 		{Name: "who_recvmsg"},
 		{Name: "who_sendmsg"},
 	}
@@ -87,11 +94,16 @@ func startTCPQueueLengthProbe(buf bytecode.AssetReader, managerOptions manager.O
 	if err != nil {
 		return nil, fmt.Errorf("failed to get map '%s': %w", statsMapName, err)
 	}
+	zeroWindowMap, err := ebpfmaps.GetMap[StructConnKey, []StructZeroWindowValue](m, zeroWindowMapName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get map '%s': %w", zeroWindowMapName, err)
+	}
 	ebpf.AddNameMappings(m, "tcp_queue_length")
 
 	return &Tracer{
-		m:        m,
-		statsMap: statsMap,
+		m:             m,
+		statsMap:      statsMap,
+		zeroWindowMap: zeroWindowMap,
 	}, nil
 }
 
@@ -140,9 +152,49 @@ func (t *Tracer) GetAndFlush() model.TCPQueueLengthStats {
 		}
 	}
 
+	t.flushZeroWindows(nbCpus, result)
 	return result
 }
 
+// flushZeroWindows adds the connections which advertised a zero window to the stats of their container
+func (t *Tracer) flushZeroWindows(nbCpus int, result model.TCPQueueLengthStats) {
+	var connKey StructConnKey
+	var keys []StructConnKey
+	zeroWindowValue := make([]StructZeroWindowValue, nbCpus)
+	it := t.zeroWindowMap.Iterate()
+	for it.Next(&connKey, &zeroWindowValue) {
+		conn := model.ZeroWindowConnection{
+			PID:    connKey.Pid,
+			Source: connAddr(connKey.Family, connKey.Saddr, connKey.Sport),
+			Dest:   connAddr(connKey.Family, connKey.Daddr, connKey.Dport),
+		}
+		for cpu := 0; cpu < nbCpus; cpu++ {
+			conn.LocalZeroWindow += zeroWindowValue[cpu].Local_zero_window
+			conn.PeerZeroWindow += zeroWindowValue[cpu].Peer_zero_window
+		}
+		cgroupName := unix.ByteSliceToString(connKey.Cgroup[:])
+		stats := result[cgroupName]
+		stats.ZeroWindowConnections = append(stats.ZeroWindowConnections, conn)
+		result[cgroupName] = stats
+		keys = append(keys, connKey)
+	}
+	if err := it.Err(); err != nil {
+		log.Warnf("failed to iterate on TCP zero windows while flushing: %s", err)
+	}
+	for _, k := range keys {
+		if err := t.zeroWindowMap.Delete(&k); err != nil {
+			log.Warnf("failed to delete zero window: %s", err)
+		}
+	}
+}
+
+func connAddr(family uint16, addr [16]uint8, port uint16) string {
+	if family == afInet6 {
+		return netip.AddrPortFrom(netip.AddrFrom16(addr), port).String()
+	}
+	return netip.AddrPortFrom(netip.AddrFrom4([4]byte(addr[:4])), port).String()
+}
+
 func loadTCPQueueLengthCOREProbe(cfg *ebpf.Config) (*Tracer, error) {
 	kv, err := kernel.HostVersion()
 	if err != nil {
@@ -14,3 +14,5 @@ import "C"
 
 type StructStatsKey C.struct_stats_key
 type StructStatsValue C.struct_stats_value
+type StructConnKey C.struct_conn_key
+type StructZeroWindowValue C.struct_zero_window_value
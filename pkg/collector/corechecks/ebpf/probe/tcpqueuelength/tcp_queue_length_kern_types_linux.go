@@ -10,3 +10,18 @@ type StructStatsValue struct {
 	Read_buffer_max_usage  uint32
 	Write_buffer_max_usage uint32
 }
+type StructConnKey struct {
+	Cgroup    [129]byte
+	Pad_cgo_0 [3]byte
+	Pid       uint32
+	Saddr     [16]uint8
+	Daddr     [16]uint8
+	Sport     uint16
+	Dport     uint16
+	Family    uint16
+	Pad_cgo_1 [2]byte
+}
+type StructZeroWindowValue struct {
+	Local_zero_window uint32
+	Peer_zero_window  uint32
+}
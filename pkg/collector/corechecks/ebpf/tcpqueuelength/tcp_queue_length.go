@@ -9,7 +9,10 @@
 package tcpqueuelength
 
 import (
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"gopkg.in/yaml.v2"
 
@@ -23,6 +26,7 @@ import (
 	core "github.com/DataDog/datadog-agent/pkg/collector/corechecks"
 	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/ebpf/probe/tcpqueuelength/model"
 	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
+	"github.com/DataDog/datadog-agent/pkg/metrics/event"
 	"github.com/DataDog/datadog-agent/pkg/util/cgroups"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 	"github.com/DataDog/datadog-agent/pkg/util/optional"
@@ -36,6 +40,24 @@ const (
 // TCPQueueLengthConfig is the config of the TCP Queue Length check
 type TCPQueueLengthConfig struct {
 	CollectTCPQueueLength bool `yaml:"collect_tcp_queue_length"`
+	// SaturationThresholdPct is the usage of a buffer, in percent, above which the buffer is saturated
+	SaturationThresholdPct float64 `yaml:"saturation_threshold_pct"`
+	// SaturationMinRuns is the number of consecutive runs a buffer must be saturated for to be reported
+	SaturationMinRuns int `yaml:"saturation_min_runs"`
+}
+
+// queue is a buffer of the TCP sockets of a container
+type queue struct {
+	containerID string
+	name        string
+}
+
+// zeroWindowConn is a TCP connection of a container which advertised, or whose peer advertised, a zero window
+type zeroWindowConn struct {
+	containerID string
+	pid         uint32
+	source      string
+	dest        string
 }
 
 // TCPQueueLengthCheck grabs TCP queue length metrics
@@ -44,6 +66,10 @@ type TCPQueueLengthCheck struct {
 	instance       *TCPQueueLengthConfig
 	tagger         tagger.Component
 	sysProbeClient *http.Client
+	// saturatedRuns is the number of consecutive runs each queue has been saturated for
+	saturatedRuns map[queue]int
+	// zeroWindowRuns is the number of consecutive runs each connection has had a zero window for
+	zeroWindowRuns map[zeroWindowConn]int
 }
 
 // Factory creates a new check factory
@@ -55,9 +81,11 @@ func Factory(tagger tagger.Component) optional.Option[func() check.Check] {
 
 func newCheck(tagger tagger.Component) check.Check {
 	return &TCPQueueLengthCheck{
-		CheckBase: core.NewCheckBase(CheckName),
-		instance:  &TCPQueueLengthConfig{},
-		tagger:    tagger,
+		CheckBase:      core.NewCheckBase(CheckName),
+		instance:       &TCPQueueLengthConfig{},
+		tagger:         tagger,
+		saturatedRuns:  make(map[queue]int),
+		zeroWindowRuns: make(map[zeroWindowConn]int),
 	}
 }
 
@@ -65,6 +93,8 @@ func newCheck(tagger tagger.Component) check.Check {
 func (t *TCPQueueLengthConfig) Parse(data []byte) error {
 	// default values
 	t.CollectTCPQueueLength = true
+	t.SaturationThresholdPct = 90
+	t.SaturationMinRuns = 3
 
 	return yaml.Unmarshal(data, t)
 }
@@ -96,6 +126,8 @@ func (t *TCPQueueLengthCheck) Run() error {
 		return err
 	}
 
+	saturatedRuns := make(map[queue]int, len(t.saturatedRuns))
+	zeroWindowRuns := make(map[zeroWindowConn]int, len(t.zeroWindowRuns))
 	for k, v := range stats {
 		containerID, err := cgroups.ContainerFilter("", k)
 		if err != nil || containerID == "" {
@@ -114,8 +146,91 @@ func (t *TCPQueueLengthCheck) Run() error {
 
 		sender.Gauge("tcp_queue.read_buffer_max_usage_pct", float64(v.ReadBufferMaxUsage)/1000.0, "", tags)
 		sender.Gauge("tcp_queue.write_buffer_max_usage_pct", float64(v.WriteBufferMaxUsage)/1000.0, "", tags)
+
+		t.checkSaturation(sender, saturatedRuns, queue{containerID, "read"}, float64(v.ReadBufferMaxUsage)/1000.0, tags)
+		t.checkSaturation(sender, saturatedRuns, queue{containerID, "write"}, float64(v.WriteBufferMaxUsage)/1000.0, tags)
+		for _, conn := range v.ZeroWindowConnections {
+			t.checkZeroWindow(sender, zeroWindowRuns, containerID, conn, tags)
+		}
 	}
+	// The queues without traffic during the run are not saturated anymore, and the connections without zero window
+	// during the run have recovered
+	t.saturatedRuns = saturatedRuns
+	t.zeroWindowRuns = zeroWindowRuns
 
 	sender.Commit()
 	return nil
 }
+
+// checkSaturation reports the queues saturated for at least SaturationMinRuns consecutive runs. A saturated read
+// buffer makes the kernel advertise a zero receive window, which stalls the peers of the container.
+func (t *TCPQueueLengthCheck) checkSaturation(sender sender.Sender, saturatedRuns map[queue]int, q queue, usagePct float64, tags []string) {
+	tags = append(tags[:len(tags):len(tags)], "queue:"+q.name)
+	if usagePct < t.instance.SaturationThresholdPct {
+		sender.Gauge("tcp_queue.saturated", 0, "", tags)
+		return
+	}
+
+	runs := t.saturatedRuns[q] + 1
+	saturatedRuns[q] = runs
+	if runs < t.instance.SaturationMinRuns {
+		sender.Gauge("tcp_queue.saturated", 0, "", tags)
+		return
+	}
+	sender.Gauge("tcp_queue.saturated", 1, "", tags)
+
+	// The event is sent once, when the saturation becomes sustained
+	if runs != t.instance.SaturationMinRuns {
+		return
+	}
+	text := fmt.Sprintf("%%%%%% \nThe TCP %s buffers of the container %s have been over %.0f%% full for %d consecutive runs, up to %.1f%%.", q.name, q.containerID, t.instance.SaturationThresholdPct, runs, usagePct)
+	if q.name == "read" {
+		text += "\n The kernel advertises a zero receive window to the peers of the container while its read buffers are full."
+	}
+	sender.Event(event.Event{
+		AlertType:      event.AlertTypeWarning,
+		Priority:       event.PriorityNormal,
+		SourceTypeName: CheckName,
+		EventType:      CheckName,
+		AggregationKey: q.containerID + ":" + q.name,
+		Title:          fmt.Sprintf("Sustained TCP %s buffer saturation in container %s", q.name, q.containerID),
+		Text:           text + "\n %%%",
+		Tags:           tags,
+	})
+}
+
+// checkZeroWindow reports the zero windows of a connection, and sends an event once the connection has had a zero
+// window for SaturationMinRuns consecutive runs.
+func (t *TCPQueueLengthCheck) checkZeroWindow(sender sender.Sender, zeroWindowRuns map[zeroWindowConn]int, containerID string, conn model.ZeroWindowConnection, tags []string) {
+	tags = append(tags[:len(tags):len(tags)], "pid:"+strconv.FormatUint(uint64(conn.PID), 10))
+	sender.Count("tcp_queue.local_zero_window", float64(conn.LocalZeroWindow), "", tags)
+	sender.Count("tcp_queue.peer_zero_window", float64(conn.PeerZeroWindow), "", tags)
+
+	c := zeroWindowConn{containerID: containerID, pid: conn.PID, source: conn.Source, dest: conn.Dest}
+	runs := t.zeroWindowRuns[c] + 1
+	zeroWindowRuns[c] = runs
+
+	// The event is sent once, when the zero window becomes sustained
+	if runs != t.instance.SaturationMinRuns {
+		return
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%%%%%% \nThe TCP connection %s -> %s of the process %d of the container %s has had a zero window for %d consecutive runs.", c.source, c.dest, c.pid, containerID, runs)
+	if conn.LocalZeroWindow > 0 {
+		b.WriteString("\n The connection advertises a zero receive window to its peer because the process doesn't read it fast enough.")
+	}
+	if conn.PeerZeroWindow > 0 {
+		b.WriteString("\n The peer advertises a zero receive window to the connection, which can't send anymore.")
+	}
+	b.WriteString("\n %%%")
+	sender.Event(event.Event{
+		AlertType:      event.AlertTypeWarning,
+		Priority:       event.PriorityNormal,
+		SourceTypeName: CheckName,
+		EventType:      CheckName,
+		AggregationKey: containerID + ":" + c.source + "-" + c.dest,
+		Title:          fmt.Sprintf("Sustained TCP zero window on %s -> %s in container %s", c.source, c.dest, containerID),
+		Text:           b.String(),
+		Tags:           tags,
+	})
+}
@@ -0,0 +1,96 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+//go:build linux
+
+package tcpqueuelength
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/ebpf/probe/tcpqueuelength/model"
+)
+
+func TestParse(t *testing.T) {
+	cfg := &TCPQueueLengthConfig{}
+	require.NoError(t, cfg.Parse([]byte(`saturation_min_runs: 5`)))
+	assert.True(t, cfg.CollectTCPQueueLength)
+	assert.Equal(t, 90.0, cfg.SaturationThresholdPct)
+	assert.Equal(t, 5, cfg.SaturationMinRuns)
+}
+
+func TestCheckSaturation(t *testing.T) {
+	c := newCheck(nil).(*TCPQueueLengthCheck)
+	require.NoError(t, c.instance.Parse(nil))
+	s := mocksender.NewMockSender(c.ID())
+	s.SetupAcceptAll()
+
+	q := queue{containerID: "abc123", name: "read"}
+	run := func(usagePct float64) {
+		saturatedRuns := make(map[queue]int)
+		c.checkSaturation(s, saturatedRuns, q, usagePct, []string{"container_id:abc123"})
+		c.saturatedRuns = saturatedRuns
+	}
+
+	run(95)
+	run(99)
+	s.AssertMetric(t, "Gauge", "tcp_queue.saturated", 0, "", []string{"container_id:abc123", "queue:read"})
+	s.AssertNumberOfCalls(t, "Event", 0)
+
+	// The saturation is sustained on the third run
+	run(97)
+	s.AssertMetric(t, "Gauge", "tcp_queue.saturated", 1, "", []string{"container_id:abc123", "queue:read"})
+	s.AssertNumberOfCalls(t, "Event", 1)
+	run(97)
+	s.AssertNumberOfCalls(t, "Event", 1)
+
+	// A run below the threshold resets the saturation
+	run(50)
+	assert.NotContains(t, c.saturatedRuns, q)
+	run(95)
+	run(95)
+	run(95)
+	s.AssertNumberOfCalls(t, "Event", 2)
+}
+
+func TestCheckZeroWindow(t *testing.T) {
+	c := newCheck(nil).(*TCPQueueLengthCheck)
+	require.NoError(t, c.instance.Parse(nil))
+	s := mocksender.NewMockSender(c.ID())
+	s.SetupAcceptAll()
+
+	conn := model.ZeroWindowConnection{PID: 42, Source: "10.0.0.1:40000", Dest: "10.0.0.2:6379", LocalZeroWindow: 3}
+	run := func(conns ...model.ZeroWindowConnection) {
+		zeroWindowRuns := make(map[zeroWindowConn]int)
+		for _, conn := range conns {
+			c.checkZeroWindow(s, zeroWindowRuns, "abc123", conn, []string{"container_id:abc123"})
+		}
+		c.zeroWindowRuns = zeroWindowRuns
+	}
+
+	run(conn)
+	s.AssertMetric(t, "Count", "tcp_queue.local_zero_window", 3, "", []string{"container_id:abc123", "pid:42"})
+	s.AssertMetric(t, "Count", "tcp_queue.peer_zero_window", 0, "", []string{"container_id:abc123", "pid:42"})
+	run(conn)
+	s.AssertNumberOfCalls(t, "Event", 0)
+
+	// The zero window is sustained on the third run
+	run(conn)
+	s.AssertNumberOfCalls(t, "Event", 1)
+	run(conn)
+	s.AssertNumberOfCalls(t, "Event", 1)
+
+	// A run without zero window resets the connection
+	run()
+	assert.Empty(t, c.zeroWindowRuns)
+	run(conn)
+	run(conn)
+	run(conn)
+	s.AssertNumberOfCalls(t, "Event", 2)
+}
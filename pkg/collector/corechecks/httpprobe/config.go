@@ -0,0 +1,195 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package httpprobe
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+)
+
+const (
+	defaultTimeout          = 10 * time.Second
+	defaultStatusCodeRegexp = `(1|2|3)\d\d`
+	defaultDaysWarning      = 14
+	defaultDaysCritical     = 7
+)
+
+// InstanceConfig is used to deserialize the instance config
+type InstanceConfig struct {
+	Name    string            `yaml:"name"`
+	URL     string            `yaml:"url"`
+	Method  string            `yaml:"method"`
+	Headers map[string]string `yaml:"headers"`
+	Data    string            `yaml:"data"`
+	// Timeout is in seconds
+	Timeout float64 `yaml:"timeout"`
+
+	HTTPResponseStatusCode string `yaml:"http_response_status_code"`
+	ContentMatch           string `yaml:"content_match"`
+	ReverseContentMatch    bool   `yaml:"reverse_content_match"`
+	AllowRedirects         *bool  `yaml:"allow_redirects"`
+	SkipProxy              bool   `yaml:"skip_proxy"`
+
+	TLSVerify                  *bool  `yaml:"tls_verify"`
+	TLSCACert                  string `yaml:"tls_ca_cert"`
+	TLSCert                    string `yaml:"tls_cert"`
+	TLSPrivateKey              string `yaml:"tls_private_key"`
+	TLSServerName              string `yaml:"tls_server_name"`
+	CheckCertificateExpiration *bool  `yaml:"check_certificate_expiration"`
+	DaysWarning                int    `yaml:"days_warning"`
+	DaysCritical               int    `yaml:"days_critical"`
+}
+
+// CheckConfig is the validated configuration of an instance
+type CheckConfig struct {
+	Name string
+	URL  *url.URL
+	// DisplayURL is the URL without its credentials, used in the tags and messages
+	DisplayURL     string
+	Method         string
+	Headers        http.Header
+	Data           string
+	Timeout        time.Duration
+	StatusCode     *regexp.Regexp
+	ContentMatch   *regexp.Regexp
+	ReverseMatch   bool
+	AllowRedirects bool
+	SkipProxy      bool
+
+	TLSVerify                  bool
+	RootCAs                    *x509.CertPool
+	Certificates               []tls.Certificate
+	TLSServerName              string
+	CheckCertificateExpiration bool
+	DaysWarning                int
+	DaysCritical               int
+}
+
+// NewCheckConfig parses and validates the configuration of an instance
+func NewCheckConfig(rawInstance integration.Data) (*CheckConfig, error) {
+	var instance InstanceConfig
+	if err := yaml.Unmarshal(rawInstance, &instance); err != nil {
+		return nil, err
+	}
+
+	if instance.URL == "" {
+		return nil, fmt.Errorf("the url of the instance is missing")
+	}
+	u, err := url.Parse(instance.URL)
+	if err != nil {
+		// the errors of url.Parse contain the url, which may contain credentials
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return nil, fmt.Errorf("invalid url: %v", err)
+	}
+	displayURL := withoutUserInfo(u)
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid url %q: the scheme must be http or https", displayURL)
+	}
+
+	c := &CheckConfig{
+		Name:                       instance.Name,
+		URL:                        u,
+		DisplayURL:                 displayURL,
+		Method:                     strings.ToUpper(instance.Method),
+		Headers:                    http.Header{},
+		Data:                       instance.Data,
+		Timeout:                    defaultTimeout,
+		ReverseMatch:               instance.ReverseContentMatch,
+		AllowRedirects:             boolOrDefault(instance.AllowRedirects, true),
+		SkipProxy:                  instance.SkipProxy,
+		TLSVerify:                  boolOrDefault(instance.TLSVerify, true),
+		TLSServerName:              instance.TLSServerName,
+		CheckCertificateExpiration: boolOrDefault(instance.CheckCertificateExpiration, true),
+		DaysWarning:                firstNonZero(instance.DaysWarning, defaultDaysWarning),
+		DaysCritical:               firstNonZero(instance.DaysCritical, defaultDaysCritical),
+	}
+	if c.Method == "" {
+		c.Method = http.MethodGet
+	}
+	for name, value := range instance.Headers {
+		c.Headers.Set(name, value)
+	}
+	if instance.Timeout < 0 {
+		return nil, fmt.Errorf("invalid timeout %v, it must be positive", instance.Timeout)
+	} else if instance.Timeout > 0 {
+		c.Timeout = time.Duration(instance.Timeout * float64(time.Second))
+	}
+
+	statusCode := instance.HTTPResponseStatusCode
+	if statusCode == "" {
+		statusCode = defaultStatusCodeRegexp
+	}
+	// the whole status code must match
+	if c.StatusCode, err = regexp.Compile("^(?:" + statusCode + ")$"); err != nil {
+		return nil, fmt.Errorf("invalid http_response_status_code %q: %v", statusCode, err)
+	}
+	if instance.ContentMatch != "" {
+		if c.ContentMatch, err = regexp.Compile(instance.ContentMatch); err != nil {
+			return nil, fmt.Errorf("invalid content_match %q: %v", instance.ContentMatch, err)
+		}
+	}
+
+	if instance.TLSCACert != "" {
+		pem, err := os.ReadFile(instance.TLSCACert)
+		if err != nil {
+			return nil, fmt.Errorf("could not read tls_ca_cert: %v", err)
+		}
+		c.RootCAs = x509.NewCertPool()
+		if !c.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificate found in tls_ca_cert %s", instance.TLSCACert)
+		}
+	}
+	if instance.TLSCert != "" {
+		cert, err := tls.LoadX509KeyPair(instance.TLSCert, firstNonZero(instance.TLSPrivateKey, instance.TLSCert))
+		if err != nil {
+			return nil, fmt.Errorf("could not load the client certificate: %v", err)
+		}
+		c.Certificates = []tls.Certificate{cert}
+	}
+	if c.DaysCritical > c.DaysWarning {
+		return nil, fmt.Errorf("days_critical (%d) must be lower than days_warning (%d)", c.DaysCritical, c.DaysWarning)
+	}
+	return c, nil
+}
+
+// withoutUserInfo returns an url without its user name and password
+func withoutUserInfo(u *url.URL) string {
+	stripped := *u
+	stripped.User = nil
+	return stripped.String()
+}
+
+func boolOrDefault(value *bool, defaultValue bool) bool {
+	if value == nil {
+		return defaultValue
+	}
+	return *value
+}
+
+func firstNonZero[T comparable](values ...T) T {
+	var zero T
+	for _, value := range values {
+		if value != zero {
+			return value
+		}
+	}
+	return zero
+}
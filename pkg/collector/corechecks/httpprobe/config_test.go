@@ -0,0 +1,82 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package httpprobe
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCheckConfigDefaults(t *testing.T) {
+	config, err := NewCheckConfig([]byte(`url: https://example.com/health`))
+	require.NoError(t, err)
+
+	assert.Equal(t, "https://example.com/health", config.URL.String())
+	assert.Equal(t, http.MethodGet, config.Method)
+	assert.Equal(t, defaultTimeout, config.Timeout)
+	assert.True(t, config.StatusCode.MatchString("204"))
+	assert.False(t, config.StatusCode.MatchString("404"))
+	assert.False(t, config.StatusCode.MatchString("2000"))
+	assert.Nil(t, config.ContentMatch)
+	assert.True(t, config.AllowRedirects)
+	assert.True(t, config.TLSVerify)
+	assert.True(t, config.CheckCertificateExpiration)
+	assert.Equal(t, defaultDaysWarning, config.DaysWarning)
+	assert.Equal(t, defaultDaysCritical, config.DaysCritical)
+}
+
+func TestNewCheckConfig(t *testing.T) {
+	config, err := NewCheckConfig([]byte(`
+url: http://10.0.0.1:8080/api
+method: post
+headers:
+  content-type: application/json
+data: '{"ping":true}'
+timeout: 2.5
+http_response_status_code: "201|202"
+content_match: pong
+reverse_content_match: true
+allow_redirects: false
+tls_verify: false
+days_warning: 30
+days_critical: 10
+`))
+	require.NoError(t, err)
+
+	assert.Equal(t, http.MethodPost, config.Method)
+	assert.Equal(t, "application/json", config.Headers.Get("Content-Type"))
+	assert.Equal(t, `{"ping":true}`, config.Data)
+	assert.Equal(t, 2500*time.Millisecond, config.Timeout)
+	assert.True(t, config.StatusCode.MatchString("202"))
+	assert.False(t, config.StatusCode.MatchString("200"))
+	assert.True(t, config.ContentMatch.MatchString("pong"))
+	assert.True(t, config.ReverseMatch)
+	assert.False(t, config.AllowRedirects)
+	assert.False(t, config.TLSVerify)
+	assert.Equal(t, 30, config.DaysWarning)
+	assert.Equal(t, 10, config.DaysCritical)
+}
+
+func TestNewCheckConfigErrors(t *testing.T) {
+	for name, instance := range map[string]string{
+		"missing url":         `name: foo`,
+		"invalid scheme":      `url: ftp://example.com`,
+		"negative timeout":    "url: http://example.com\ntimeout: -1",
+		"invalid status code": "url: http://example.com\nhttp_response_status_code: '('",
+		"invalid content":     "url: http://example.com\ncontent_match: '['",
+		"missing ca cert":     "url: https://example.com\ntls_ca_cert: /does/not/exist.pem",
+		"invalid days":        "url: https://example.com\ndays_warning: 5\ndays_critical: 10",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewCheckConfig([]byte(instance))
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,370 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+// Package httpprobe implements a core check probing HTTP(S) endpoints, it reports
+// their availability, the timings of the requests and the expiration of their
+// TLS certificates. Its metrics and service checks are compatible with the ones of
+// the Python HTTP check, so it can be used by the agents built without Python.
+package httpprobe
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/aggregator/sender"
+	"github.com/DataDog/datadog-agent/pkg/collector/check"
+	core "github.com/DataDog/datadog-agent/pkg/collector/corechecks"
+	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
+	"github.com/DataDog/datadog-agent/pkg/metrics/servicecheck"
+	httputils "github.com/DataDog/datadog-agent/pkg/util/http"
+	"github.com/DataDog/datadog-agent/pkg/util/optional"
+)
+
+const (
+	// CheckName is the name of the check
+	CheckName = "http_probe"
+
+	defaultCheckInterval = 15 * time.Second
+	// maxBodySize is the maximum size of the response body read to match its content
+	maxBodySize = 10 * 1024 * 1024
+)
+
+// Check probes an HTTP(S) endpoint
+type Check struct {
+	core.CheckBase
+	config *CheckConfig
+	tags   []string
+	// proxy returns the proxy of the agent configuration to use for a request, if any
+	proxy func(*http.Request) (*url.URL, error)
+}
+
+// timings holds the durations of the phases of a request, measured on its first connection
+type timings struct {
+	mu                                    sync.Mutex
+	start                                 time.Time
+	dnsStart, connectStart, tlsStart      time.Time
+	dns, connect, tlsHandshake, firstByte time.Duration
+}
+
+// certificateResult holds the certificate presented by the endpoint on the first connection
+type certificateResult struct {
+	notAfter time.Time
+	chainErr error
+}
+
+// probeResult is the result of a request to the endpoint
+type probeResult struct {
+	err          error
+	statusCode   int
+	body         []byte
+	responseTime time.Duration
+	timings      *timings
+	certificate  *certificateResult
+}
+
+// Factory creates a new check factory
+func Factory() optional.Option[func() check.Check] {
+	return optional.NewOption(newCheck)
+}
+
+func newCheck() check.Check {
+	return &Check{
+		CheckBase: core.NewCheckBaseWithInterval(CheckName, defaultCheckInterval),
+	}
+}
+
+// Configure parses the check configuration and init the check
+func (c *Check) Configure(senderManager sender.SenderManager, integrationConfigDigest uint64, rawInstance integration.Data, rawInitConfig integration.Data, source string) error {
+	c.BuildID(integrationConfigDigest, rawInstance, rawInitConfig)
+	if err := c.CommonConfigure(senderManager, rawInitConfig, rawInstance, source); err != nil {
+		return err
+	}
+
+	config, err := NewCheckConfig(rawInstance)
+	if err != nil {
+		return err
+	}
+	c.config = config
+	c.tags = []string{"url:" + config.DisplayURL}
+	if config.Name != "" {
+		c.tags = append(c.tags, "instance:"+config.Name)
+	}
+	if proxies := pkgconfigsetup.Datadog().GetProxies(); proxies != nil && !config.SkipProxy {
+		c.proxy = httputils.GetProxyTransportFunc(proxies, pkgconfigsetup.Datadog())
+	}
+	return nil
+}
+
+// Run sends a request to the endpoint and reports the result
+func (c *Check) Run() error {
+	s, err := c.GetSender()
+	if err != nil {
+		return err
+	}
+
+	result := c.probe()
+	status, message := c.evaluate(result)
+	if result.err == nil {
+		s.Gauge("network.http.response_time", result.responseTime.Seconds(), "", c.tags)
+		c.submitTimings(s, result.timings)
+	}
+	canConnect := 0.0
+	if status == servicecheck.ServiceCheckOK {
+		canConnect = 1
+	}
+	s.Gauge("network.http.can_connect", canConnect, "", c.tags)
+	s.Gauge("network.http.cant_connect", 1-canConnect, "", c.tags)
+	s.ServiceCheck("http.can_connect", status, "", c.tags, message)
+
+	if c.config.URL.Scheme == "https" && c.config.CheckCertificateExpiration {
+		c.submitCertificate(s, result)
+	}
+
+	s.Commit()
+	return nil
+}
+
+// probe sends the request, measuring its timings and recording the certificate of the endpoint
+func (c *Check) probe() *probeResult {
+	result := &probeResult{timings: &timings{}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.config.Timeout)
+	defer cancel()
+
+	var body io.Reader
+	if c.config.Data != "" {
+		body = strings.NewReader(c.config.Data)
+	}
+	req, err := http.NewRequestWithContext(httptrace.WithClientTrace(ctx, result.timings.trace()), c.config.Method, c.config.URL.String(), body)
+	if err != nil {
+		result.err = err
+		return result
+	}
+	req.Header = c.config.Headers.Clone()
+	if host := req.Header.Get("Host"); host != "" {
+		req.Host = host
+	}
+
+	client := &http.Client{
+		Transport: c.transport(result),
+	}
+	if !c.config.AllowRedirects {
+		client.CheckRedirect = func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+	}
+	defer client.CloseIdleConnections()
+
+	result.timings.start = time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			err = fmt.Errorf("request timed out after %s", c.config.Timeout)
+		} else if errors.As(err, &urlErr) {
+			// the url of the error only has its password masked
+			urlErr.URL = c.config.DisplayURL
+		}
+		result.err = err
+		return result
+	}
+	defer resp.Body.Close()
+	result.statusCode = resp.StatusCode
+	if c.config.ContentMatch != nil {
+		if result.body, err = io.ReadAll(io.LimitReader(resp.Body, maxBodySize)); err != nil {
+			result.err = fmt.Errorf("could not read the response: %v", err)
+			return result
+		}
+	}
+	result.responseTime = time.Since(result.timings.start)
+	return result
+}
+
+// transport returns a transport opening a new connection for each request. The certificates
+// are always verified, to report their validity, but only rejected if tls_verify is set.
+func (c *Check) transport(result *probeResult) *http.Transport {
+	var once sync.Once
+	return &http.Transport{
+		DisableKeepAlives: true,
+		ForceAttemptHTTP2: true,
+		Proxy:             c.proxy,
+		TLSClientConfig: &tls.Config{
+			InsecureSkipVerify: true,
+			ServerName:         c.config.TLSServerName,
+			Certificates:       c.config.Certificates,
+			VerifyConnection: func(cs tls.ConnectionState) error {
+				if len(cs.PeerCertificates) == 0 {
+					return errors.New("no certificate presented by the endpoint")
+				}
+				err := c.verifyChain(cs)
+				once.Do(func() {
+					result.certificate = &certificateResult{
+						notAfter: cs.PeerCertificates[0].NotAfter,
+						chainErr: err,
+					}
+				})
+				if err != nil && c.config.TLSVerify {
+					return err
+				}
+				return nil
+			},
+		},
+	}
+}
+
+func (c *Check) verifyChain(cs tls.ConnectionState) error {
+	intermediates := x509.NewCertPool()
+	for _, cert := range cs.PeerCertificates[1:] {
+		intermediates.AddCert(cert)
+	}
+	serverName := cs.ServerName
+	if serverName == "" {
+		serverName = c.config.URL.Hostname()
+	}
+	_, err := cs.PeerCertificates[0].Verify(x509.VerifyOptions{
+		Roots:         c.config.RootCAs,
+		Intermediates: intermediates,
+		DNSName:       serverName,
+	})
+	return err
+}
+
+// evaluate returns the status of the endpoint, checking the status code and content of the response
+func (c *Check) evaluate(result *probeResult) (servicecheck.ServiceCheckStatus, string) {
+	if result.err != nil {
+		return servicecheck.ServiceCheckCritical, result.err.Error()
+	}
+	if !c.config.StatusCode.MatchString(fmt.Sprint(result.statusCode)) {
+		return servicecheck.ServiceCheckCritical, fmt.Sprintf("Incorrect HTTP return code for url %s. Expected %s, got %d.", c.config.DisplayURL, c.config.StatusCode, result.statusCode)
+	}
+	if c.config.ContentMatch != nil {
+		found := c.config.ContentMatch.Match(result.body)
+		if !found && !c.config.ReverseMatch {
+			return servicecheck.ServiceCheckCritical, fmt.Sprintf("Content %q not found in response.", c.config.ContentMatch)
+		}
+		if found && c.config.ReverseMatch {
+			return servicecheck.ServiceCheckCritical, fmt.Sprintf("Content %q found in response.", c.config.ContentMatch)
+		}
+	}
+	return servicecheck.ServiceCheckOK, ""
+}
+
+func (c *Check) submitTimings(s sender.Sender, t *timings) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for name, duration := range map[string]time.Duration{
+		"network.http.dns_lookup_time":    t.dns,
+		"network.http.connect_time":       t.connect,
+		"network.http.tls_handshake_time": t.tlsHandshake,
+		"network.http.time_to_first_byte": t.firstByte,
+	} {
+		// the phases which didn't happen, like the DNS lookup of an IP address, aren't reported
+		if duration > 0 {
+			s.Gauge(name, duration.Seconds(), "", c.tags)
+		}
+	}
+}
+
+// submitCertificate reports the expiration and validity of the certificate of the endpoint
+func (c *Check) submitCertificate(s sender.Sender, result *probeResult) {
+	cert := result.certificate
+	if cert == nil {
+		s.ServiceCheck("http.ssl_cert", servicecheck.ServiceCheckUnknown, "", c.tags, fmt.Sprintf("Could not get the certificate: %v", result.err))
+		return
+	}
+
+	secondsLeft := time.Until(cert.notAfter).Seconds()
+	daysLeft := secondsLeft / (24 * 60 * 60)
+	s.Gauge("http.ssl.seconds_left", secondsLeft, "", c.tags)
+	s.Gauge("http.ssl.days_left", daysLeft, "", c.tags)
+	chainValid := 1.0
+	if cert.chainErr != nil {
+		chainValid = 0
+	}
+	s.Gauge("http.ssl.chain_valid", chainValid, "", c.tags)
+
+	var status servicecheck.ServiceCheckStatus
+	var message string
+	switch {
+	case cert.chainErr != nil:
+		status, message = servicecheck.ServiceCheckCritical, fmt.Sprintf("Invalid certificate chain: %v", cert.chainErr)
+	case secondsLeft <= 0:
+		status, message = servicecheck.ServiceCheckCritical, "Certificate has expired"
+	case daysLeft < float64(c.config.DaysCritical):
+		status, message = servicecheck.ServiceCheckCritical, fmt.Sprintf("Days left for certificate %d < %d", int(math.Floor(daysLeft)), c.config.DaysCritical)
+	case daysLeft < float64(c.config.DaysWarning):
+		status, message = servicecheck.ServiceCheckWarning, fmt.Sprintf("Days left for certificate %d < %d", int(math.Floor(daysLeft)), c.config.DaysWarning)
+	default:
+		status, message = servicecheck.ServiceCheckOK, fmt.Sprintf("Days left: %d", int(math.Floor(daysLeft)))
+	}
+	s.ServiceCheck("http.ssl_cert", status, "", c.tags, message)
+}
+
+// trace returns the hooks measuring the phases of the first connection of a request
+func (t *timings) trace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			if t.dnsStart.IsZero() {
+				t.dnsStart = time.Now()
+			}
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			if t.dns == 0 {
+				t.dns = time.Since(t.dnsStart)
+			}
+		},
+		ConnectStart: func(string, string) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			if t.connectStart.IsZero() {
+				t.connectStart = time.Now()
+			}
+		},
+		ConnectDone: func(_, _ string, err error) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			if err == nil && t.connect == 0 {
+				t.connect = time.Since(t.connectStart)
+			}
+		},
+		TLSHandshakeStart: func() {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			if t.tlsStart.IsZero() {
+				t.tlsStart = time.Now()
+			}
+		},
+		TLSHandshakeDone: func(_ tls.ConnectionState, err error) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			if err == nil && t.tlsHandshake == 0 {
+				t.tlsHandshake = time.Since(t.tlsStart)
+			}
+		},
+		GotFirstResponseByte: func() {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			if t.firstByte == 0 {
+				t.firstByte = time.Since(t.start)
+			}
+		},
+	}
+}
@@ -0,0 +1,247 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package httpprobe
+
+import (
+	"crypto/tls"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
+	configmock "github.com/DataDog/datadog-agent/pkg/config/mock"
+	"github.com/DataDog/datadog-agent/pkg/metrics/servicecheck"
+)
+
+func runCheck(t *testing.T, instance string) *mocksender.MockSender {
+	c := newCheck().(*Check)
+	senderManager := mocksender.CreateDefaultDemultiplexer()
+	require.NoError(t, c.Configure(senderManager, 0, []byte(instance), nil, "test"))
+
+	s := mocksender.NewMockSenderWithSenderManager(c.ID(), senderManager)
+	s.SetupAcceptAll()
+	require.NoError(t, c.Run())
+	return s
+}
+
+func TestRunOK(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Method != http.MethodPost || r.Header.Get("X-Probe") != "1" || string(body) != "ping" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		fmt.Fprint(w, "status: pong")
+	}))
+	defer server.Close()
+
+	s := runCheck(t, fmt.Sprintf(`
+name: api
+url: %s
+method: POST
+headers:
+  X-Probe: "1"
+data: ping
+content_match: "status: \\w+"
+`, server.URL))
+
+	tags := []string{"url:" + server.URL, "instance:api"}
+	s.AssertServiceCheck(t, "http.can_connect", servicecheck.ServiceCheckOK, "", tags, "")
+	s.AssertMetric(t, "Gauge", "network.http.can_connect", 1, "", tags)
+	s.AssertMetric(t, "Gauge", "network.http.cant_connect", 0, "", tags)
+	s.AssertMetricTaggedWith(t, "Gauge", "network.http.response_time", tags)
+	s.AssertMetricTaggedWith(t, "Gauge", "network.http.connect_time", tags)
+	s.AssertMetricTaggedWith(t, "Gauge", "network.http.time_to_first_byte", tags)
+	s.AssertNotCalled(t, "Gauge", "network.http.tls_handshake_time", mock.Anything, mock.Anything, mock.Anything)
+	s.AssertNotCalled(t, "ServiceCheck", "http.ssl_cert", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRunCredentials(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, password, ok := r.BasicAuth(); !ok || user != "user" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+		} else if r.URL.Path == "/error" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+	u := strings.Replace(server.URL, "http://", "http://user:secret@", 1)
+
+	s := runCheck(t, "url: "+u)
+	s.AssertServiceCheck(t, "http.can_connect", servicecheck.ServiceCheckOK, "", []string{"url:" + server.URL}, "")
+
+	// the credentials are neither in the tags nor in the messages
+	s = runCheck(t, "url: "+u+"/error")
+	message := fmt.Sprintf(`Incorrect HTTP return code for url %s/error. Expected ^(?:(1|2|3)\d\d)$, got 500.`, server.URL)
+	s.AssertServiceCheck(t, "http.can_connect", servicecheck.ServiceCheckCritical, "", []string{"url:" + server.URL + "/error"}, message)
+}
+
+func TestRunProxy(t *testing.T) {
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		proxied = append(proxied, r.URL.String())
+	}))
+	defer proxy.Close()
+
+	cfg := configmock.New(t)
+	cfg.SetWithoutSource("proxy.http", proxy.URL)
+
+	s := runCheck(t, "url: http://probed.invalid/health")
+	require.Equal(t, []string{"http://probed.invalid/health"}, proxied)
+	s.AssertServiceCheck(t, "http.can_connect", servicecheck.ServiceCheckOK, "", nil, "")
+
+	s = runCheck(t, "url: http://probed.invalid/health\nskip_proxy: true")
+	require.Len(t, proxied, 1)
+	s.AssertCalled(t, "ServiceCheck", "http.can_connect", servicecheck.ServiceCheckCritical, "", mock.Anything, mock.Anything)
+}
+
+func TestRunFailures(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/error":
+			w.WriteHeader(http.StatusInternalServerError)
+		case "/slow":
+			time.Sleep(time.Second)
+		case "/redirect":
+			http.Redirect(w, r, "/error", http.StatusFound)
+		default:
+			fmt.Fprint(w, "maintenance in progress")
+		}
+	}))
+	defer server.Close()
+
+	for _, tc := range []struct {
+		name     string
+		instance string
+		status   servicecheck.ServiceCheckStatus
+		message  string
+	}{
+		{
+			name:     "status code",
+			instance: "url: " + server.URL + "/error",
+			status:   servicecheck.ServiceCheckCritical,
+			message:  fmt.Sprintf(`Incorrect HTTP return code for url %s/error. Expected ^(?:(1|2|3)\d\d)$, got 500.`, server.URL),
+		},
+		{
+			name:     "expected status code",
+			instance: "url: " + server.URL + "/error\nhttp_response_status_code: 5\\d\\d",
+			status:   servicecheck.ServiceCheckOK,
+		},
+		{
+			name:     "followed redirect",
+			instance: "url: " + server.URL + "/redirect",
+			status:   servicecheck.ServiceCheckCritical,
+			message:  fmt.Sprintf(`Incorrect HTTP return code for url %s/redirect. Expected ^(?:(1|2|3)\d\d)$, got 500.`, server.URL),
+		},
+		{
+			name:     "redirect not followed",
+			instance: "url: " + server.URL + "/redirect\nallow_redirects: false",
+			status:   servicecheck.ServiceCheckOK,
+		},
+		{
+			name:     "content not found",
+			instance: "url: " + server.URL + "\ncontent_match: healthy",
+			status:   servicecheck.ServiceCheckCritical,
+			message:  `Content "healthy" not found in response.`,
+		},
+		{
+			name:     "reverse content match",
+			instance: "url: " + server.URL + "\ncontent_match: maintenance\nreverse_content_match: true",
+			status:   servicecheck.ServiceCheckCritical,
+			message:  `Content "maintenance" found in response.`,
+		},
+		{
+			name:     "timeout",
+			instance: "url: " + server.URL + "/slow\ntimeout: 0.1",
+			status:   servicecheck.ServiceCheckCritical,
+			message:  "request timed out after 100ms",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s := runCheck(t, tc.instance)
+			canConnect := 0.0
+			if tc.status == servicecheck.ServiceCheckOK {
+				canConnect = 1
+			}
+			s.AssertCalled(t, "ServiceCheck", "http.can_connect", tc.status, "", mock.Anything, tc.message)
+			s.AssertCalled(t, "Gauge", "network.http.can_connect", canConnect, "", mock.Anything)
+		})
+	}
+}
+
+func TestRunCertificate(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, "ok")
+	}))
+	defer server.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	require.NoError(t, os.WriteFile(caFile, caPEM, 0600))
+	daysLeft := time.Until(server.Certificate().NotAfter).Hours() / 24
+	tags := []string{"url:" + server.URL}
+
+	t.Run("valid chain", func(t *testing.T) {
+		s := runCheck(t, fmt.Sprintf("url: %s\ntls_ca_cert: %s", server.URL, caFile))
+
+		s.AssertServiceCheck(t, "http.can_connect", servicecheck.ServiceCheckOK, "", tags, "")
+		s.AssertMetric(t, "Gauge", "http.ssl.chain_valid", 1, "", tags)
+		s.AssertMetricInRange(t, "Gauge", "http.ssl.days_left", daysLeft-1, daysLeft, "", tags)
+		s.AssertMetricTaggedWith(t, "Gauge", "network.http.tls_handshake_time", tags)
+		s.AssertCalled(t, "ServiceCheck", "http.ssl_cert", servicecheck.ServiceCheckOK, "", tags, mock.Anything)
+	})
+
+	t.Run("expiring certificate", func(t *testing.T) {
+		days := int(daysLeft) + 10
+		s := runCheck(t, fmt.Sprintf("url: %s\ntls_ca_cert: %s\ndays_warning: %d\ndays_critical: 1", server.URL, caFile, days))
+
+		s.AssertServiceCheck(t, "http.ssl_cert", servicecheck.ServiceCheckWarning, "", tags, fmt.Sprintf("Days left for certificate %d < %d", int(daysLeft), days))
+	})
+
+	t.Run("invalid chain rejected", func(t *testing.T) {
+		s := runCheck(t, "url: "+server.URL)
+
+		s.AssertCalled(t, "ServiceCheck", "http.can_connect", servicecheck.ServiceCheckCritical, "", tags, mock.MatchedBy(func(message string) bool {
+			return strings.Contains(message, "certificate")
+		}))
+		s.AssertMetric(t, "Gauge", "http.ssl.chain_valid", 0, "", tags)
+		s.AssertCalled(t, "ServiceCheck", "http.ssl_cert", servicecheck.ServiceCheckCritical, "", tags, mock.Anything)
+	})
+
+	t.Run("invalid chain allowed", func(t *testing.T) {
+		s := runCheck(t, fmt.Sprintf("url: %s\ntls_verify: false", server.URL))
+
+		s.AssertServiceCheck(t, "http.can_connect", servicecheck.ServiceCheckOK, "", tags, "")
+		s.AssertMetric(t, "Gauge", "http.ssl.chain_valid", 0, "", tags)
+		s.AssertCalled(t, "ServiceCheck", "http.ssl_cert", servicecheck.ServiceCheckCritical, "", tags, mock.Anything)
+	})
+
+	t.Run("expiration not checked", func(t *testing.T) {
+		s := runCheck(t, fmt.Sprintf("url: %s\ntls_verify: false\ncheck_certificate_expiration: false", server.URL))
+
+		s.AssertNotCalled(t, "ServiceCheck", "http.ssl_cert", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestVerifyConnectionWithoutCertificate(t *testing.T) {
+	c := newCheck().(*Check)
+	c.config = &CheckConfig{TLSVerify: false}
+	result := &probeResult{}
+	err := c.transport(result).TLSClientConfig.VerifyConnection(tls.ConnectionState{})
+
+	require.EqualError(t, err, "no certificate presented by the endpoint")
+	require.Nil(t, result.certificate)
+}
@@ -42,8 +42,17 @@ func GetRegisteredFactoryKeys() []string {
 	return factoryKeys
 }
 
+// LogsCheck is implemented by the checks sending logs through the logs pipeline. Their instances
+// need a `logs` section, like the python integrations sending logs.
+type LogsCheck interface {
+	// SetLogReceiver gives the check the component receiving its logs
+	SetLogReceiver(logReceiver integrations.Component)
+}
+
 // GoCheckLoader is a specific loader for checks living in this package
-type GoCheckLoader struct{}
+type GoCheckLoader struct {
+	logReceiver optional.Option[integrations.Component]
+}
 
 // NewGoCheckLoader creates a loader for go checks
 func NewGoCheckLoader() (*GoCheckLoader, error) {
@@ -75,6 +84,13 @@ func (gl *GoCheckLoader) Load(senderManger sender.SenderManager, config integrat
 		return c, errors.New(msg)
 	}
 
+	if logsCheck, ok := c.(LogsCheck); ok {
+		if v, ok := gl.logReceiver.Get(); ok {
+			v.RegisterIntegration(string(c.ID()), config)
+			logsCheck.SetLogReceiver(v)
+		}
+	}
+
 	return c, nil
 }
 
@@ -83,8 +99,13 @@ func (gl *GoCheckLoader) String() string {
 }
 
 func init() {
-	factory := func(sender.SenderManager, optional.Option[integrations.Component], tagger.Component) (check.Loader, error) {
-		return NewGoCheckLoader()
+	factory := func(_ sender.SenderManager, logReceiver optional.Option[integrations.Component], _ tagger.Component) (check.Loader, error) {
+		loader, err := NewGoCheckLoader()
+		if err != nil {
+			return nil, err
+		}
+		loader.logReceiver = logReceiver
+		return loader, nil
 	}
 
 	loaders.RegisterLoader(30, factory)
@@ -11,6 +11,8 @@ import (
 	"testing"
 
 	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	integrations "github.com/DataDog/datadog-agent/comp/logs/integrations/def"
+	integrationsmock "github.com/DataDog/datadog-agent/comp/logs/integrations/mock"
 	"github.com/DataDog/datadog-agent/pkg/aggregator"
 	"github.com/DataDog/datadog-agent/pkg/aggregator/sender"
 	"github.com/DataDog/datadog-agent/pkg/collector/check"
@@ -105,3 +107,41 @@ func TestLoad(t *testing.T) {
 		t.Fatal("Expected error, found: nil")
 	}
 }
+
+type testLogsCheck struct {
+	TestCheck
+	logReceiver integrations.Component
+}
+
+func (c *testLogsCheck) SetLogReceiver(logReceiver integrations.Component) {
+	c.logReceiver = logReceiver
+}
+
+func TestLoadLogsCheck(t *testing.T) {
+	RegisterCheck("logs", optional.NewOption(func() check.Check {
+		return &testLogsCheck{}
+	}))
+	logReceiver := integrationsmock.Mock()
+	registered := make(chan integrations.IntegrationConfig, 1)
+	go func() {
+		registered <- <-logReceiver.SubscribeIntegration()
+	}()
+
+	i := []integration.Data{
+		integration.Data("foo: bar"),
+	}
+	cc := integration.Config{Name: "logs", Instances: i, LogsConfig: integration.Data(`[{"type": "integration"}]`)}
+	l, _ := NewGoCheckLoader()
+	l.logReceiver = optional.NewOption(logReceiver)
+
+	c, err := l.Load(aggregator.NewNoOpSenderManager(), cc, i[0])
+	if err != nil {
+		t.Fatalf("Expected nil error, found: %v", err)
+	}
+	if c.(*testLogsCheck).logReceiver != logReceiver {
+		t.Fatal("Expected the check to get the log receiver")
+	}
+	if config := <-registered; config.IntegrationID != string(c.ID()) || config.Config.Name != "logs" {
+		t.Fatalf("Expected the check to be registered, found: %+v", config)
+	}
+}
@@ -0,0 +1,119 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+//go:build linux || windows
+
+// Package dnsevents contains the DNS events check, which sends the DNS lookups seen by
+// system-probe to the logs pipeline
+package dnsevents
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	sysprobeclient "github.com/DataDog/datadog-agent/cmd/system-probe/api/client"
+	sysconfig "github.com/DataDog/datadog-agent/cmd/system-probe/config"
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	integrations "github.com/DataDog/datadog-agent/comp/logs/integrations/def"
+	"github.com/DataDog/datadog-agent/pkg/aggregator/sender"
+	"github.com/DataDog/datadog-agent/pkg/collector/check"
+	core "github.com/DataDog/datadog-agent/pkg/collector/corechecks"
+	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
+	"github.com/DataDog/datadog-agent/pkg/util/optional"
+)
+
+const (
+	// CheckName is the name of the check
+	CheckName = "dns_events"
+)
+
+var errNoLogReceiver = errors.New("the logs of integrations are unavailable, `logs_enabled` must be set and the instance must have a `logs` section")
+
+// Check sends the DNS events of system-probe as logs
+type Check struct {
+	core.CheckBase
+	sysProbeClient *http.Client
+	logReceiver    integrations.Component
+}
+
+var _ core.LogsCheck = &Check{}
+
+// Factory creates a new check factory
+func Factory() optional.Option[func() check.Check] {
+	return optional.NewOption(newCheck)
+}
+
+func newCheck() check.Check {
+	return &Check{
+		CheckBase: core.NewCheckBase(CheckName),
+	}
+}
+
+// Configure parses the check configuration and init the check
+func (c *Check) Configure(senderManager sender.SenderManager, _ uint64, config, initConfig integration.Data, source string) error {
+	if err := c.CommonConfigure(senderManager, initConfig, config, source); err != nil {
+		return err
+	}
+	c.sysProbeClient = sysprobeclient.Get(pkgconfigsetup.SystemProbe().GetString("system_probe_config.sysprobe_socket"))
+	return nil
+}
+
+// SetLogReceiver sets the component receiving the DNS events
+func (c *Check) SetLogReceiver(logReceiver integrations.Component) {
+	c.logReceiver = logReceiver
+}
+
+// Run executes the check
+func (c *Check) Run() error {
+	if c.logReceiver == nil {
+		return errNoLogReceiver
+	}
+
+	events, err := c.getDNSEvents()
+	if err != nil {
+		return err
+	}
+	for _, event := range events {
+		c.logReceiver.SendLog(string(event), string(c.ID()))
+	}
+
+	sender, err := c.GetSender()
+	if err != nil {
+		return err
+	}
+	sender.Count("dns_events.sent", float64(len(events)), "", nil)
+	sender.Commit()
+	return nil
+}
+
+// getDNSEvents returns the DNS events of system-probe, as the JSON objects they are sent as
+func (c *Check) getDNSEvents() ([]json.RawMessage, error) {
+	req, err := http.NewRequest("GET", sysprobeclient.ModuleURL(sysconfig.NetworkTracerModule, "/dns_events"), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.sysProbeClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("non-ok status code: url %s, status_code: %d, response: `%s`", req.URL, resp.StatusCode, string(body))
+	}
+
+	var events []json.RawMessage
+	if err := json.Unmarshal(body, &events); err != nil {
+		return nil, fmt.Errorf("could not parse the DNS events: %w", err)
+	}
+	return events, nil
+}
@@ -0,0 +1,73 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+//go:build linux || windows
+
+package dnsevents
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	integrations "github.com/DataDog/datadog-agent/comp/logs/integrations/def"
+	integrationsmock "github.com/DataDog/datadog-agent/comp/logs/integrations/mock"
+	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
+)
+
+type roundTripper func(*http.Request) (*http.Response, error)
+
+func (f roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func newTestCheck(t *testing.T, status int, body string) (*Check, *mocksender.MockSender) {
+	c := newCheck().(*Check)
+	s := mocksender.NewMockSender(c.ID())
+	s.SetupAcceptAll()
+	require.NoError(t, c.CommonConfigure(s.GetSenderManager(), nil, nil, "test"))
+	c.sysProbeClient = &http.Client{Transport: roundTripper(func(req *http.Request) (*http.Response, error) {
+		assert.Equal(t, "/network_tracer/dns_events", req.URL.Path)
+		return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(body))}, nil
+	})}
+	return c, s
+}
+
+func TestRun(t *testing.T) {
+	c, s := newTestCheck(t, http.StatusOK, `[{"question":"example.com","rcode":"NOERROR"},{"question":"missing.example.com","rcode":"NXDOMAIN"}]`)
+	logReceiver := integrationsmock.Mock()
+	c.SetLogReceiver(logReceiver)
+
+	logs := make(chan integrations.IntegrationLog, 2)
+	go func() {
+		for i := 0; i < 2; i++ {
+			logs <- <-logReceiver.Subscribe()
+		}
+	}()
+
+	require.NoError(t, c.Run())
+	first, second := <-logs, <-logs
+	assert.Equal(t, `{"question":"example.com","rcode":"NOERROR"}`, first.Log)
+	assert.Equal(t, `{"question":"missing.example.com","rcode":"NXDOMAIN"}`, second.Log)
+	assert.Equal(t, string(c.ID()), first.IntegrationID)
+	s.AssertMetric(t, "Count", "dns_events.sent", 2, "", nil)
+}
+
+func TestRunErrors(t *testing.T) {
+	c, _ := newTestCheck(t, http.StatusOK, `[]`)
+	assert.ErrorIs(t, c.Run(), errNoLogReceiver)
+
+	c, _ = newTestCheck(t, http.StatusInternalServerError, ``)
+	c.SetLogReceiver(integrationsmock.Mock())
+	assert.ErrorContains(t, c.Run(), "non-ok status code")
+
+	c, _ = newTestCheck(t, http.StatusOK, `{}`)
+	c.SetLogReceiver(integrationsmock.Mock())
+	assert.ErrorContains(t, c.Run(), "could not parse the DNS events")
+}
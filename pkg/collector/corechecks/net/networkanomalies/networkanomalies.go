@@ -0,0 +1,189 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+//go:build linux || windows
+
+// Package networkanomalies contains the network anomalies check, which sends the retransmit spikes and RTT increases
+// of the TCP connections detected by system-probe as events and metrics
+package networkanomalies
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	sysprobeclient "github.com/DataDog/datadog-agent/cmd/system-probe/api/client"
+	sysconfig "github.com/DataDog/datadog-agent/cmd/system-probe/config"
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	tagger "github.com/DataDog/datadog-agent/comp/core/tagger/def"
+	"github.com/DataDog/datadog-agent/comp/core/tagger/types"
+	"github.com/DataDog/datadog-agent/pkg/aggregator/sender"
+	"github.com/DataDog/datadog-agent/pkg/collector/check"
+	core "github.com/DataDog/datadog-agent/pkg/collector/corechecks"
+	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
+	"github.com/DataDog/datadog-agent/pkg/metrics/event"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+	"github.com/DataDog/datadog-agent/pkg/util/optional"
+)
+
+const (
+	// CheckName is the name of the check
+	CheckName = "network_anomalies"
+
+	retransmitSpike = "retransmit_spike"
+	rttIncrease     = "rtt_increase"
+)
+
+// anomaly is an anomaly of a TCP connection, as detected by system-probe
+type anomaly struct {
+	Type        string    `json:"type"`
+	Timestamp   time.Time `json:"timestamp"`
+	Source      string    `json:"source"`
+	Dest        string    `json:"dest"`
+	Direction   string    `json:"direction"`
+	PID         uint32    `json:"pid"`
+	ContainerID string    `json:"container_id"`
+	Tags        []string  `json:"tags"`
+	Value       float64   `json:"value"`
+	Baseline    float64   `json:"baseline"`
+	Retransmits uint32    `json:"retransmits"`
+	SentPackets uint64    `json:"sent_packets"`
+}
+
+// Check sends the anomalies of the TCP connections detected by system-probe
+type Check struct {
+	core.CheckBase
+	tagger         tagger.Component
+	sysProbeClient *http.Client
+}
+
+// Factory creates a new check factory
+func Factory(tagger tagger.Component) optional.Option[func() check.Check] {
+	return optional.NewOption(func() check.Check {
+		return newCheck(tagger)
+	})
+}
+
+func newCheck(tagger tagger.Component) check.Check {
+	return &Check{
+		CheckBase: core.NewCheckBase(CheckName),
+		tagger:    tagger,
+	}
+}
+
+// Configure parses the check configuration and init the check
+func (c *Check) Configure(senderManager sender.SenderManager, _ uint64, config, initConfig integration.Data, source string) error {
+	if err := c.CommonConfigure(senderManager, initConfig, config, source); err != nil {
+		return err
+	}
+	c.sysProbeClient = sysprobeclient.Get(pkgconfigsetup.SystemProbe().GetString("system_probe_config.sysprobe_socket"))
+	return nil
+}
+
+// Run executes the check
+func (c *Check) Run() error {
+	anomalies, err := c.getAnomalies()
+	if err != nil {
+		return err
+	}
+
+	sender, err := c.GetSender()
+	if err != nil {
+		return err
+	}
+	for _, a := range anomalies {
+		tags := c.tags(a)
+		sender.Count("network_anomalies.count", 1, "", append(tags, "anomaly_type:"+a.Type))
+		switch a.Type {
+		case retransmitSpike:
+			sender.Gauge("network_anomalies.retransmit_rate", a.Value, "", tags)
+		case rttIncrease:
+			sender.Gauge("network_anomalies.rtt", a.Value, "", tags)
+			sender.Gauge("network_anomalies.rtt_baseline", a.Baseline, "", tags)
+		}
+		sender.Event(c.event(a, tags))
+	}
+	sender.Commit()
+	return nil
+}
+
+// tags returns the tags of the process and the container of the connection of an anomaly
+func (c *Check) tags(a anomaly) []string {
+	tags := append([]string{"direction:" + a.Direction, "pid:" + strconv.FormatUint(uint64(a.PID), 10)}, a.Tags...)
+	if a.ContainerID == "" {
+		return tags
+	}
+	containerTags, err := c.tagger.Tag(types.NewEntityID(types.ContainerID, a.ContainerID), c.tagger.ChecksCardinality())
+	if err != nil {
+		log.Debugf("Error collecting tags for container %s: %s", a.ContainerID, err)
+	}
+	return append(tags, containerTags...)
+}
+
+func (c *Check) event(a anomaly, tags []string) event.Event {
+	e := event.Event{
+		AlertType:      event.AlertTypeWarning,
+		Priority:       event.PriorityNormal,
+		SourceTypeName: CheckName,
+		EventType:      CheckName,
+		AggregationKey: a.Type + ":" + a.Source + "-" + a.Dest,
+		Ts:             a.Timestamp.Unix(),
+		Tags:           append([]string{"anomaly_type:" + a.Type}, tags...),
+	}
+
+	var b strings.Builder
+	b.WriteString("%%% \n")
+	switch a.Type {
+	case retransmitSpike:
+		e.Title = fmt.Sprintf("TCP retransmit spike on %s -> %s", a.Source, a.Dest)
+		fmt.Fprintf(&b, "The connection retransmitted %d of the %d packets it sent (%.1f%%), against %.1f%% usually.", a.Retransmits, a.SentPackets, a.Value*100, a.Baseline*100)
+	case rttIncrease:
+		e.Title = fmt.Sprintf("TCP RTT increase on %s -> %s", a.Source, a.Dest)
+		fmt.Fprintf(&b, "The round-trip time of the connection rose to %.1fms, against %.1fms usually.", a.Value, a.Baseline)
+	default:
+		e.Title = fmt.Sprintf("TCP anomaly %s on %s -> %s", a.Type, a.Source, a.Dest)
+	}
+	fmt.Fprintf(&b, "\n The %s connection belongs to the process %d", a.Direction, a.PID)
+	if a.ContainerID != "" {
+		fmt.Fprintf(&b, " of the container %s", a.ContainerID)
+	}
+	b.WriteString(".\n %%%")
+	e.Text = b.String()
+	return e
+}
+
+// getAnomalies returns the anomalies detected by system-probe since the last run
+func (c *Check) getAnomalies() ([]anomaly, error) {
+	req, err := http.NewRequest("GET", sysprobeclient.ModuleURL(sysconfig.NetworkTracerModule, "/anomalies"), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.sysProbeClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("the anomaly detection is disabled, network_config.anomaly_detection.enabled must be set in system-probe.yaml")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("non-ok status code: url %s, status_code: %d, response: `%s`", req.URL, resp.StatusCode, string(body))
+	}
+
+	var anomalies []anomaly
+	if err := json.Unmarshal(body, &anomalies); err != nil {
+		return nil, fmt.Errorf("could not parse the anomalies: %w", err)
+	}
+	return anomalies, nil
+}
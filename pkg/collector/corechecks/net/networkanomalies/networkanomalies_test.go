@@ -0,0 +1,85 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+//go:build linux || windows
+
+package networkanomalies
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	taggermock "github.com/DataDog/datadog-agent/comp/core/tagger/mock"
+	"github.com/DataDog/datadog-agent/comp/core/tagger/types"
+	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
+	"github.com/DataDog/datadog-agent/pkg/metrics/event"
+)
+
+type roundTripper func(*http.Request) (*http.Response, error)
+
+func (f roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func newTestCheck(t *testing.T, status int, body string) (*Check, *mocksender.MockSender) {
+	fakeTagger := taggermock.SetupFakeTagger(t)
+	fakeTagger.SetTags(types.NewEntityID(types.ContainerID, "abc123"), "workloadmeta", []string{"image_name:redis"}, nil, nil, nil)
+
+	c := newCheck(fakeTagger).(*Check)
+	s := mocksender.NewMockSender(c.ID())
+	s.SetupAcceptAll()
+	require.NoError(t, c.CommonConfigure(s.GetSenderManager(), nil, nil, "test"))
+	c.sysProbeClient = &http.Client{Transport: roundTripper(func(req *http.Request) (*http.Response, error) {
+		assert.Equal(t, "/network_tracer/anomalies", req.URL.Path)
+		return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(body))}, nil
+	})}
+	return c, s
+}
+
+func TestRun(t *testing.T) {
+	c, s := newTestCheck(t, http.StatusOK, `[
+		{"type":"retransmit_spike","timestamp":"2024-06-01T12:00:00Z","source":"10.0.0.1:40000","dest":"10.0.0.2:6379","direction":"outgoing","pid":42,"container_id":"abc123","tags":["service:web"],"value":0.25,"baseline":0.01,"retransmits":25,"sent_packets":100},
+		{"type":"rtt_increase","timestamp":"2024-06-01T12:00:00Z","source":"10.0.0.1:41000","dest":"10.0.0.3:443","direction":"outgoing","pid":7,"value":80,"baseline":20}
+	]`)
+
+	require.NoError(t, c.Run())
+
+	spikeTags := []string{"direction:outgoing", "pid:42", "service:web", "image_name:redis"}
+	s.AssertMetric(t, "Count", "network_anomalies.count", 1, "", append(spikeTags, "anomaly_type:retransmit_spike"))
+	s.AssertMetric(t, "Gauge", "network_anomalies.retransmit_rate", 0.25, "", spikeTags)
+	s.AssertMetric(t, "Count", "network_anomalies.count", 1, "", []string{"direction:outgoing", "pid:7", "anomaly_type:rtt_increase"})
+	s.AssertMetric(t, "Gauge", "network_anomalies.rtt", 80, "", []string{"direction:outgoing", "pid:7"})
+	s.AssertMetric(t, "Gauge", "network_anomalies.rtt_baseline", 20, "", []string{"direction:outgoing", "pid:7"})
+
+	s.AssertEvent(t, event.Event{
+		Title:          "TCP retransmit spike on 10.0.0.1:40000 -> 10.0.0.2:6379",
+		Text:           "%%% \nThe connection retransmitted 25 of the 100 packets it sent (25.0%), against 1.0% usually.\n The outgoing connection belongs to the process 42 of the container abc123.\n %%%",
+		Ts:             1717243200,
+		AlertType:      event.AlertTypeWarning,
+		Priority:       event.PriorityNormal,
+		SourceTypeName: CheckName,
+		EventType:      CheckName,
+		AggregationKey: "retransmit_spike:10.0.0.1:40000-10.0.0.2:6379",
+		Tags:           []string{"anomaly_type:retransmit_spike", "direction:outgoing", "pid:42", "service:web", "image_name:redis"},
+	}, 0)
+	s.AssertNumberOfCalls(t, "Event", 2)
+	s.AssertCalled(t, "Commit")
+}
+
+func TestRunErrors(t *testing.T) {
+	c, _ := newTestCheck(t, http.StatusNotFound, ``)
+	assert.ErrorContains(t, c.Run(), "anomaly detection is disabled")
+
+	c, _ = newTestCheck(t, http.StatusInternalServerError, ``)
+	assert.ErrorContains(t, c.Run(), "non-ok status code")
+
+	c, _ = newTestCheck(t, http.StatusOK, `{}`)
+	assert.ErrorContains(t, c.Run(), "could not parse the anomalies")
+}
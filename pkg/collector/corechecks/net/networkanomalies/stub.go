@@ -0,0 +1,26 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+//go:build !linux && !windows
+
+// Package networkanomalies contains the network anomalies check, which sends the retransmit spikes and RTT increases
+// of the TCP connections detected by system-probe as events and metrics
+package networkanomalies
+
+import (
+	tagger "github.com/DataDog/datadog-agent/comp/core/tagger/def"
+	"github.com/DataDog/datadog-agent/pkg/collector/check"
+	"github.com/DataDog/datadog-agent/pkg/util/optional"
+)
+
+const (
+	// CheckName is the name of the check
+	CheckName = "network_anomalies"
+)
+
+// Factory creates a new check factory
+func Factory(tagger.Component) optional.Option[func() check.Check] {
+	return optional.NewNoneOption[func() check.Check]()
+}
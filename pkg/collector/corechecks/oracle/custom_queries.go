@@ -12,18 +12,12 @@ import (
 	"reflect"
 	"strconv"
 
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/customqueries"
 	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/oracle/config"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 	godror "github.com/godror/godror"
 )
 
-type metricRow struct {
-	name   string
-	value  float64
-	method metricType
-	tags   []string
-}
-
 func concatenateTypeError(_ error, prefix string, expectedType string, column string, value interface{}, query string, err error) error {
 	return fmt.Errorf(
 		`Custom query %s encountered a type error during execution. A %s was expected for the column %s, but the query results returned the value "%v" of type %s. Query was: "%s". Error: %w`,
@@ -55,7 +49,7 @@ func (c *Check) CustomQueries() error {
 		c.dbCustomQueries = db
 	}
 
-	var metricRows []metricRow
+	var metricRows []customqueries.MetricRow
 	sender, err := c.GetSender()
 	if err != nil {
 		return fmt.Errorf("failed to get sender for custom queries %w", err)
@@ -109,7 +103,7 @@ func (c *Check) CustomQueries() error {
 			continue
 		}
 		for rows.Next() {
-			var metricsFromSingleRow []metricRow
+			var metricsFromSingleRow []customqueries.MetricRow
 			var tags []string
 			if pdb != "" {
 				tags = []string{fmt.Sprintf("pdb:%s", pdb)}
@@ -125,42 +119,42 @@ func (c *Check) CustomQueries() error {
 				errInQuery = true
 				break
 			}
-			var metricRow metricRow
+			var metricRow customqueries.MetricRow
 			for i, v := range cols {
 				if q.Columns[i].Type == "tag" {
 					if v != nil {
 						tags = append(tags, fmt.Sprintf("%s:%s", q.Columns[i].Name, v))
 					}
-				} else if method, err := getMetricFunctionCode(q.Columns[i].Type); err == nil {
-					metricRow.name = fmt.Sprintf("%s.%s", metricPrefix, q.Columns[i].Name)
+				} else if method, err := customqueries.ParseMetricType(q.Columns[i].Type); err == nil {
+					metricRow.Name = fmt.Sprintf("%s.%s", metricPrefix, q.Columns[i].Name)
 					if v_str, ok := v.(string); ok {
-						metricRow.value, err = strconv.ParseFloat(v_str, 64)
+						metricRow.Value, err = strconv.ParseFloat(v_str, 64)
 						if err != nil {
-							allErrors = concatenateTypeError(allErrors, metricPrefix, "number", metricRow.name, v, q.Query, err)
+							allErrors = concatenateTypeError(allErrors, metricPrefix, "number", metricRow.Name, v, q.Query, err)
 							errInQuery = true
 							break
 						}
 					} else if v_gn, ok := v.(godror.Number); ok { //nolint:revive // TODO(DBM) Fix revive linter
-						metricRow.value, err = strconv.ParseFloat(string(v_gn), 64)
+						metricRow.Value, err = strconv.ParseFloat(string(v_gn), 64)
 						if err != nil {
-							allErrors = concatenateTypeError(allErrors, metricPrefix, "godror.Number", metricRow.name, v, q.Query, err)
+							allErrors = concatenateTypeError(allErrors, metricPrefix, "godror.Number", metricRow.Name, v, q.Query, err)
 							errInQuery = true
 							break
 						}
 					} else if vInt64, ok := v.(int64); ok {
-						metricRow.value = float64(vInt64)
+						metricRow.Value = float64(vInt64)
 					} else if vFloat64, ok := v.(float64); ok {
-						metricRow.value = vFloat64
+						metricRow.Value = vFloat64
 					} else {
-						allErrors = concatenateTypeError(allErrors, metricPrefix, "UNKNOWN", metricRow.name, v, q.Query, err)
+						allErrors = concatenateTypeError(allErrors, metricPrefix, "UNKNOWN", metricRow.Name, v, q.Query, err)
 						errInQuery = true
 						break
 					}
 
-					metricRow.method = method
+					metricRow.Method = method
 					metricsFromSingleRow = append(metricsFromSingleRow, metricRow)
 				} else {
-					allErrors = concatenateError(allErrors, fmt.Sprintf("Unknown column type %s in custom query %s", q.Columns[i].Type, metricRow.name))
+					allErrors = concatenateError(allErrors, fmt.Sprintf("Unknown column type %s in custom query %s", q.Columns[i].Type, metricRow.Name))
 					errInQuery = true
 					break
 				}
@@ -174,8 +168,8 @@ func (c *Check) CustomQueries() error {
 			}
 			log.Debugf("%s Appended queried tags to check tags %v", c.logPrompt, tags)
 			for i := range metricsFromSingleRow {
-				metricsFromSingleRow[i].tags = make([]string, len(tags))
-				copy(metricsFromSingleRow[i].tags, tags)
+				metricsFromSingleRow[i].Tags = make([]string, len(tags))
+				copy(metricsFromSingleRow[i].Tags, tags)
 			}
 			metricRows = append(metricRows, metricsFromSingleRow...)
 		}
@@ -185,7 +179,7 @@ func (c *Check) CustomQueries() error {
 		}
 		for _, m := range metricRows {
 			log.Debugf("%s send metric %+v", c.logPrompt, m)
-			sendMetric(c, m.method, m.name, m.value, m.tags)
+			sendMetric(c, m.Method, m.Name, m.Value, m.Tags)
 		}
 		sender.Commit()
 	}
@@ -8,66 +8,28 @@
 package oracle
 
 import (
-	"fmt"
-
-	"github.com/DataDog/datadog-agent/pkg/aggregator/sender"
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/customqueries"
 	"github.com/DataDog/datadog-agent/pkg/metrics/servicecheck"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 )
 
-type metricType int
+type metricType = customqueries.MetricType
 
 const (
-	unknownMetricType metricType = iota
-	gauge
-	count
-	rate
-	monotonicCount
-	histogram
-	historate
+	gauge          = customqueries.Gauge
+	count          = customqueries.Count
+	rate           = customqueries.Rate
+	monotonicCount = customqueries.MonotonicCount
+	histogram      = customqueries.Histogram
+	historate      = customqueries.Historate
 )
 
-type metricSender func(string, float64, string, []string)
-
-func getMetricFunction(sender sender.Sender, method metricType) (metricSender, error) {
-	if sender == nil {
-		return nil, fmt.Errorf("sender is nil")
-	}
-	methods := map[metricType]metricSender{
-		gauge:          sender.Gauge,
-		count:          sender.Count,
-		rate:           sender.Rate,
-		monotonicCount: sender.MonotonicCount,
-		histogram:      sender.Histogram,
-		historate:      sender.Historate,
-	}
-	if val, ok := methods[method]; ok {
-		return val, nil
-	}
-	return nil, fmt.Errorf("Invalid metric function code %d", method)
-}
-
-func getMetricFunctionCode(name string) (metricType, error) {
-	metricTypes := map[string]metricType{
-		"gauge":           gauge,
-		"count":           count,
-		"rate":            rate,
-		"monotonic_count": monotonicCount,
-		"histogram":       histogram,
-		"historate":       historate,
-	}
-	if val, ok := metricTypes[name]; ok {
-		return val, nil
-	}
-	return unknownMetricType, fmt.Errorf("unknown metric type: %s", name)
-}
-
 func sendMetric(c *Check, method metricType, metric string, value float64, tags []string) {
 	sender, err := c.GetSender()
 	if err != nil {
 		log.Errorf("failed to get metric sender: %s", err)
 	}
-	metricFunction, err := getMetricFunction(sender, method)
+	metricFunction, err := customqueries.GetMetricFunction(sender, method)
 	if err != nil {
 		log.Errorf("failed to get metric function: %s", err)
 	}
@@ -0,0 +1,226 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package sqlquery
+
+import (
+	"fmt"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/customqueries"
+)
+
+const (
+	defaultMetricPrefix       = "sql"
+	defaultQueryTimeout       = 5 * time.Second
+	defaultMaxOpenConnections = 2
+	defaultConnMaxLifetime    = 5 * time.Minute
+)
+
+// CustomQueryColumn maps a column of the result of a query to a metric or a tag
+type CustomQueryColumn struct {
+	Name string `yaml:"name"`
+	Type string `yaml:"type"`
+}
+
+// CustomQuery is a query whose results are reported as metrics, with the model of the
+// custom queries of the Oracle check: each row produces one metric per metric column,
+// tagged with the values of its tag columns.
+type CustomQuery struct {
+	Name         string              `yaml:"name"`
+	MetricPrefix string              `yaml:"metric_prefix"`
+	Query        string              `yaml:"query"`
+	Columns      []CustomQueryColumn `yaml:"columns"`
+	Tags         []string            `yaml:"tags"`
+	// Timeout is in seconds, it defaults to the query_timeout of the instance
+	Timeout float64 `yaml:"timeout"`
+	// CollectionInterval is in seconds, the query runs at every run of the check by default
+	CollectionInterval float64 `yaml:"collection_interval"`
+}
+
+// InitConfig is used to deserialize the init config
+type InitConfig struct {
+	CustomQueries []CustomQuery `yaml:"global_custom_queries"`
+}
+
+// InstanceConfig is used to deserialize the instance config
+type InstanceConfig struct {
+	Driver       string   `yaml:"driver"`
+	DSN          string   `yaml:"dsn"`
+	MetricPrefix string   `yaml:"metric_prefix"`
+	Tags         []string `yaml:"tags"`
+	// QueryTimeout is in seconds
+	QueryTimeout       float64 `yaml:"query_timeout"`
+	MaxOpenConnections int     `yaml:"max_open_connections"`
+	MaxIdleConnections *int    `yaml:"max_idle_connections"`
+	// ConnectionMaxLifetime is in seconds
+	ConnectionMaxLifetime  float64       `yaml:"connection_max_lifetime"`
+	UseGlobalCustomQueries string        `yaml:"use_global_custom_queries"`
+	CustomQueries          []CustomQuery `yaml:"custom_queries"`
+}
+
+// query is a validated custom query
+type query struct {
+	name         string
+	metricPrefix string
+	query        string
+	columns      []column
+	tags         []string
+	timeout      time.Duration
+	interval     time.Duration
+}
+
+// column is a validated column mapping, its type is either "tag" or a metric type
+type column struct {
+	name   string
+	isTag  bool
+	method customqueries.MetricType
+}
+
+// CheckConfig is the validated configuration of an instance
+type CheckConfig struct {
+	Driver             string
+	DriverName         string
+	DSN                string
+	Tags               []string
+	MaxOpenConnections int
+	MaxIdleConnections int
+	ConnMaxLifetime    time.Duration
+	queries            []query
+}
+
+// NewCheckConfig parses and validates the configuration of an instance
+func NewCheckConfig(rawInstance integration.Data, rawInitConfig integration.Data) (*CheckConfig, error) {
+	var initConfig InitConfig
+	if err := yaml.Unmarshal(rawInitConfig, &initConfig); err != nil {
+		return nil, err
+	}
+	instance := InstanceConfig{
+		UseGlobalCustomQueries: "true",
+	}
+	if err := yaml.Unmarshal(rawInstance, &instance); err != nil {
+		return nil, err
+	}
+
+	driverName, found := drivers[instance.Driver]
+	if !found {
+		return nil, fmt.Errorf("unsupported driver %q, the supported drivers are postgres, mysql and sqlite", instance.Driver)
+	}
+	if instance.DSN == "" {
+		return nil, fmt.Errorf("the dsn of the instance is missing")
+	}
+	if instance.QueryTimeout < 0 || instance.ConnectionMaxLifetime < 0 {
+		return nil, fmt.Errorf("query_timeout and connection_max_lifetime must be positive")
+	}
+
+	c := &CheckConfig{
+		Driver:             instance.Driver,
+		DriverName:         driverName,
+		DSN:                instance.DSN,
+		Tags:               instance.Tags,
+		MaxOpenConnections: instance.MaxOpenConnections,
+		ConnMaxLifetime:    defaultConnMaxLifetime,
+	}
+	if c.MaxOpenConnections <= 0 {
+		c.MaxOpenConnections = defaultMaxOpenConnections
+	}
+	c.MaxIdleConnections = c.MaxOpenConnections
+	if instance.MaxIdleConnections != nil {
+		c.MaxIdleConnections = *instance.MaxIdleConnections
+	}
+	if instance.ConnectionMaxLifetime > 0 {
+		c.ConnMaxLifetime = seconds(instance.ConnectionMaxLifetime)
+	}
+	queryTimeout := defaultQueryTimeout
+	if instance.QueryTimeout > 0 {
+		queryTimeout = seconds(instance.QueryTimeout)
+	}
+	metricPrefix := instance.MetricPrefix
+	if metricPrefix == "" {
+		metricPrefix = defaultMetricPrefix
+	}
+
+	customQueries := instance.CustomQueries
+	switch instance.UseGlobalCustomQueries {
+	case "true":
+		if len(initConfig.CustomQueries) > 0 {
+			customQueries = initConfig.CustomQueries
+		}
+	case "false":
+	case "extend":
+		customQueries = append(customQueries, initConfig.CustomQueries...)
+	default:
+		return nil, fmt.Errorf(`wrong value %q for use_global_custom_queries, valid values are "true", "false" and "extend"`, instance.UseGlobalCustomQueries)
+	}
+	if len(customQueries) == 0 {
+		return nil, fmt.Errorf("no custom queries configured")
+	}
+
+	for i, cq := range customQueries {
+		q, err := newQuery(cq, metricPrefix, queryTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid custom query #%d: %v", i+1, err)
+		}
+		c.queries = append(c.queries, q)
+	}
+	return c, nil
+}
+
+func newQuery(cq CustomQuery, metricPrefix string, timeout time.Duration) (query, error) {
+	q := query{
+		name:         cq.Name,
+		metricPrefix: cq.MetricPrefix,
+		query:        cq.Query,
+		tags:         cq.Tags,
+		timeout:      timeout,
+		interval:     seconds(cq.CollectionInterval),
+	}
+	if q.query == "" {
+		return q, fmt.Errorf("the query is missing")
+	}
+	if len(cq.Columns) == 0 {
+		return q, fmt.Errorf("the columns are missing")
+	}
+	if cq.Timeout < 0 || cq.CollectionInterval < 0 {
+		return q, fmt.Errorf("timeout and collection_interval must be positive")
+	}
+	if cq.Timeout > 0 {
+		q.timeout = seconds(cq.Timeout)
+	}
+	if q.metricPrefix == "" {
+		q.metricPrefix = metricPrefix
+	}
+	if q.name == "" {
+		q.name = q.metricPrefix
+	}
+
+	hasMetric := false
+	for _, c := range cq.Columns {
+		if c.Name == "" {
+			return q, fmt.Errorf("a column has no name")
+		}
+		if c.Type == "tag" {
+			q.columns = append(q.columns, column{name: c.Name, isTag: true})
+			continue
+		}
+		method, err := customqueries.ParseMetricType(c.Type)
+		if err != nil {
+			return q, fmt.Errorf("column %s: %v", c.Name, err)
+		}
+		q.columns = append(q.columns, column{name: c.Name, method: method})
+		hasMetric = true
+	}
+	if !hasMetric {
+		return q, fmt.Errorf("no column is mapped to a metric")
+	}
+	return q, nil
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
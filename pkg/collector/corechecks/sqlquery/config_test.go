@@ -0,0 +1,141 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package sqlquery
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/customqueries"
+)
+
+func TestNewCheckConfig(t *testing.T) {
+	config, err := NewCheckConfig([]byte(`
+driver: postgres
+dsn: postgres://datadog@localhost:5432/app
+tags: [env:prod]
+query_timeout: 2
+max_open_connections: 4
+max_idle_connections: 1
+custom_queries:
+  - query: SELECT status, count(*) FROM orders GROUP BY status
+    metric_prefix: app.orders
+    columns:
+      - name: status
+        type: tag
+      - name: count
+        type: gauge
+  - name: queue
+    query: SELECT count(*) FROM jobs
+    timeout: 10
+    collection_interval: 60
+    tags: [queue:jobs]
+    columns:
+      - name: jobs.pending
+        type: monotonic_count
+`), nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, "pgx", config.DriverName)
+	assert.Equal(t, []string{"env:prod"}, config.Tags)
+	assert.Equal(t, 4, config.MaxOpenConnections)
+	assert.Equal(t, 1, config.MaxIdleConnections)
+	assert.Equal(t, defaultConnMaxLifetime, config.ConnMaxLifetime)
+	assert.Equal(t, []query{
+		{
+			name:         "app.orders",
+			metricPrefix: "app.orders",
+			query:        "SELECT status, count(*) FROM orders GROUP BY status",
+			columns:      []column{{name: "status", isTag: true}, {name: "count", method: customqueries.Gauge}},
+			timeout:      2 * time.Second,
+		},
+		{
+			name:         "queue",
+			metricPrefix: "sql",
+			query:        "SELECT count(*) FROM jobs",
+			columns:      []column{{name: "jobs.pending", method: customqueries.MonotonicCount}},
+			tags:         []string{"queue:jobs"},
+			timeout:      10 * time.Second,
+			interval:     time.Minute,
+		},
+	}, config.queries)
+}
+
+func TestNewCheckConfigGlobalCustomQueries(t *testing.T) {
+	initConfig := []byte(`
+global_custom_queries:
+  - query: SELECT 1
+    columns:
+      - name: global
+        type: gauge
+`)
+	instance := `
+driver: sqlite
+dsn: /tmp/app.db
+custom_queries:
+  - query: SELECT 2
+    columns:
+      - name: local
+        type: gauge
+`
+	for mode, expected := range map[string][]string{
+		"":       {"SELECT 1"},
+		"true":   {"SELECT 1"},
+		"false":  {"SELECT 2"},
+		"extend": {"SELECT 2", "SELECT 1"},
+	} {
+		t.Run(mode, func(t *testing.T) {
+			rawInstance := instance
+			if mode != "" {
+				rawInstance += "use_global_custom_queries: " + mode
+			}
+			config, err := NewCheckConfig([]byte(rawInstance), initConfig)
+			require.NoError(t, err)
+
+			var queries []string
+			for _, q := range config.queries {
+				queries = append(queries, q.query)
+			}
+			assert.Equal(t, expected, queries)
+		})
+	}
+}
+
+func TestNewCheckConfigErrors(t *testing.T) {
+	for name, instance := range map[string]string{
+		"unknown driver": "driver: oracle\ndsn: foo",
+		"missing dsn":    "driver: mysql",
+		"no queries":     "driver: mysql\ndsn: foo",
+		"missing query": `driver: mysql
+dsn: foo
+custom_queries:
+  - columns: [{name: foo, type: gauge}]`,
+		"unknown column type": `driver: mysql
+dsn: foo
+custom_queries:
+  - query: SELECT 1
+    columns: [{name: foo, type: set}]`,
+		"only tags": `driver: mysql
+dsn: foo
+custom_queries:
+  - query: SELECT 'a'
+    columns: [{name: foo, type: tag}]`,
+		"invalid global mode": `driver: mysql
+dsn: foo
+use_global_custom_queries: always
+custom_queries:
+  - query: SELECT 1
+    columns: [{name: foo, type: gauge}]`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewCheckConfig([]byte(instance), nil)
+			assert.Error(t, err)
+		})
+	}
+}
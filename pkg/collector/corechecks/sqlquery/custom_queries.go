@@ -0,0 +1,144 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package sqlquery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator/sender"
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/customqueries"
+)
+
+// runQuery runs a custom query and submits its metrics. They are only submitted once all
+// the rows were read, no metric of a query is submitted if an error happened.
+func (c *Check) runQuery(runCtx context.Context, s sender.Sender, q query) error {
+	ctx, cancel := context.WithTimeout(runCtx, q.timeout)
+	defer cancel()
+
+	metricRows, err := c.fetchRows(ctx, q)
+	if err != nil {
+		if runCtx.Err() != nil {
+			return runCtx.Err()
+		}
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("query timed out after %s", q.timeout)
+		}
+		return err
+	}
+	for _, m := range metricRows {
+		submit, err := customqueries.GetMetricFunction(s, m.Method)
+		if err != nil {
+			return err
+		}
+		submit(m.Name, m.Value, "", m.Tags)
+	}
+	return nil
+}
+
+func (c *Check) fetchRows(ctx context.Context, q query) ([]customqueries.MetricRow, error) {
+	rows, err := c.db.QueryContext(ctx, q.query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run the query: %w", err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	if len(columns) != len(q.columns) {
+		return nil, fmt.Errorf("the query returned %d columns but %d are mapped", len(columns), len(q.columns))
+	}
+
+	values := make([]any, len(columns))
+	pointers := make([]any, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+	var metricRows []customqueries.MetricRow
+	for rows.Next() {
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, fmt.Errorf("failed to read the row: %w", err)
+		}
+		rowMetrics, err := c.rowMetrics(q, values)
+		if err != nil {
+			return nil, err
+		}
+		metricRows = append(metricRows, rowMetrics...)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read the rows: %w", err)
+	}
+	return metricRows, nil
+}
+
+// rowMetrics returns the metrics of a row, tagged with its tag columns. The NULL metric
+// columns are skipped, as well as the NULL tag columns.
+func (c *Check) rowMetrics(q query, values []any) ([]customqueries.MetricRow, error) {
+	tags := append(append([]string{}, c.tags...), q.tags...)
+	for i, col := range q.columns {
+		if col.isTag && values[i] != nil {
+			tags = append(tags, col.name+":"+tagValue(values[i]))
+		}
+	}
+
+	var metricRows []customqueries.MetricRow
+	for i, col := range q.columns {
+		if col.isTag || values[i] == nil {
+			continue
+		}
+		value, err := metricValue(values[i])
+		if err != nil {
+			return nil, fmt.Errorf("the value %q of the column %s is not a number: %v", tagValue(values[i]), col.name, err)
+		}
+		metricRows = append(metricRows, customqueries.MetricRow{
+			Name:   q.metricPrefix + "." + col.name,
+			Value:  value,
+			Method: col.method,
+			Tags:   tags,
+		})
+	}
+	return metricRows, nil
+}
+
+// metricValue converts the value returned by a driver to a number, the times are
+// converted to Unix timestamps in seconds
+func metricValue(v any) (float64, error) {
+	switch v := v.(type) {
+	case int64:
+		return float64(v), nil
+	case float64:
+		return v, nil
+	case bool:
+		if v {
+			return 1, nil
+		}
+		return 0, nil
+	case []byte:
+		return strconv.ParseFloat(string(v), 64)
+	case string:
+		return strconv.ParseFloat(v, 64)
+	case time.Time:
+		return float64(v.UnixNano()) / float64(time.Second), nil
+	default:
+		return 0, fmt.Errorf("unsupported type %T", v)
+	}
+}
+
+func tagValue(v any) string {
+	switch v := v.(type) {
+	case []byte:
+		return string(v)
+	case time.Time:
+		return v.Format(time.RFC3339)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,21 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package sqlquery
+
+import (
+	// register the database/sql drivers
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	_ "modernc.org/sqlite"
+)
+
+// drivers maps the drivers of the configuration to the names of their database/sql drivers
+var drivers = map[string]string{
+	"postgres": "pgx",
+	"mysql":    "mysql",
+	// pure Go SQLite, it doesn't require cgo
+	"sqlite": "sqlite",
+}
@@ -0,0 +1,133 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+// Package sqlquery implements a core check running custom queries on Postgres, MySQL
+// and SQLite databases with database/sql, and reporting their results as metrics.
+package sqlquery
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/aggregator/sender"
+	"github.com/DataDog/datadog-agent/pkg/collector/check"
+	core "github.com/DataDog/datadog-agent/pkg/collector/corechecks"
+	"github.com/DataDog/datadog-agent/pkg/metrics/servicecheck"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+	"github.com/DataDog/datadog-agent/pkg/util/optional"
+)
+
+const (
+	// CheckName is the name of the check
+	CheckName = "sql_query"
+
+	canConnectServiceCheck = "sql.can_connect"
+	queryServiceCheck      = "sql.query"
+)
+
+// Check runs custom queries on a database
+type Check struct {
+	core.CheckBase
+	config *CheckConfig
+	db     *sql.DB
+	tags   []string
+	// lastRuns holds the time of the last run of each query, to honor their collection interval
+	lastRuns []time.Time
+}
+
+// Factory creates a new check factory
+func Factory() optional.Option[func() check.Check] {
+	return optional.NewOption(newCheck)
+}
+
+func newCheck() check.Check {
+	return &Check{
+		CheckBase: core.NewCheckBase(CheckName),
+	}
+}
+
+// Configure parses the check configuration and opens the connection pool
+func (c *Check) Configure(senderManager sender.SenderManager, integrationConfigDigest uint64, rawInstance integration.Data, rawInitConfig integration.Data, source string) error {
+	c.BuildID(integrationConfigDigest, rawInstance, rawInitConfig)
+	if err := c.CommonConfigure(senderManager, rawInitConfig, rawInstance, source); err != nil {
+		return err
+	}
+
+	config, err := NewCheckConfig(rawInstance, rawInitConfig)
+	if err != nil {
+		return err
+	}
+	// the connections are opened lazily, by the first run
+	db, err := sql.Open(config.DriverName, config.DSN)
+	if err != nil {
+		return fmt.Errorf("could not open the %s database: %w", config.Driver, err)
+	}
+	db.SetMaxOpenConns(config.MaxOpenConnections)
+	db.SetMaxIdleConns(config.MaxIdleConnections)
+	db.SetConnMaxLifetime(config.ConnMaxLifetime)
+
+	c.config = config
+	c.db = db
+	c.tags = append([]string{"driver:" + config.Driver}, config.Tags...)
+	c.lastRuns = make([]time.Time, len(config.queries))
+	return nil
+}
+
+// Run runs the custom queries whose collection interval is elapsed
+func (c *Check) Run() error {
+	return c.RunContext(context.Background())
+}
+
+// RunContext runs the check, the running query is cancelled and the remaining ones are
+// skipped when the context is done
+func (c *Check) RunContext(ctx context.Context) error {
+	s, err := c.GetSender()
+	if err != nil {
+		return err
+	}
+	defer s.Commit()
+
+	pingCtx, cancel := context.WithTimeout(ctx, defaultQueryTimeout)
+	defer cancel()
+	if err := c.db.PingContext(pingCtx); err != nil {
+		s.ServiceCheck(canConnectServiceCheck, servicecheck.ServiceCheckCritical, "", c.tags, err.Error())
+		return fmt.Errorf("could not connect to the %s database: %w", c.config.Driver, err)
+	}
+	s.ServiceCheck(canConnectServiceCheck, servicecheck.ServiceCheckOK, "", c.tags, "")
+
+	var errs []error
+	now := time.Now()
+	for i, q := range c.config.queries {
+		if ctx.Err() != nil {
+			break
+		}
+		if !c.lastRuns[i].IsZero() && now.Sub(c.lastRuns[i]) < q.interval {
+			continue
+		}
+		c.lastRuns[i] = now
+
+		tags := append(append([]string{}, c.tags...), "query:"+q.name)
+		if err := c.runQuery(ctx, s, q); err != nil {
+			log.Debugf("%s: custom query %s failed: %s", c.ID(), q.name, err)
+			s.ServiceCheck(queryServiceCheck, servicecheck.ServiceCheckCritical, "", tags, err.Error())
+			errs = append(errs, fmt.Errorf("custom query %s: %w", q.name, err))
+			continue
+		}
+		s.ServiceCheck(queryServiceCheck, servicecheck.ServiceCheckOK, "", tags, "")
+	}
+	return errors.Join(errs...)
+}
+
+// Cancel closes the connection pool
+func (c *Check) Cancel() {
+	if c.db != nil {
+		c.db.Close()
+	}
+	c.CheckBase.Cancel()
+}
@@ -0,0 +1,188 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package sqlquery
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
+	"github.com/DataDog/datadog-agent/pkg/metrics/servicecheck"
+)
+
+func createDatabase(t *testing.T) string {
+	path := filepath.Join(t.TempDir(), "app.db")
+	db, err := sql.Open("sqlite", path)
+	require.NoError(t, err)
+	defer db.Close()
+
+	_, err = db.Exec(`
+CREATE TABLE orders (id INTEGER PRIMARY KEY, status TEXT, amount REAL, shipped BOOLEAN);
+INSERT INTO orders (status, amount, shipped) VALUES
+	('paid', 10.5, 1),
+	('paid', 20, 0),
+	('cancelled', 5, 0),
+	(NULL, 1, 0);
+`)
+	require.NoError(t, err)
+	return path
+}
+
+func newTestCheck(t *testing.T, instance string) (*Check, *mocksender.MockSender) {
+	c := newCheck().(*Check)
+	senderManager := mocksender.CreateDefaultDemultiplexer()
+	require.NoError(t, c.Configure(senderManager, 0, []byte(instance), nil, "test"))
+	t.Cleanup(c.Cancel)
+
+	s := mocksender.NewMockSenderWithSenderManager(c.ID(), senderManager)
+	s.SetupAcceptAll()
+	return c, s
+}
+
+func TestRun(t *testing.T) {
+	c, s := newTestCheck(t, fmt.Sprintf(`
+driver: sqlite
+dsn: %s
+tags: [env:test]
+custom_queries:
+  - query: SELECT status, count(*), sum(amount) FROM orders GROUP BY status ORDER BY status
+    metric_prefix: app.orders
+    columns:
+      - name: status
+        type: tag
+      - name: count
+        type: gauge
+      - name: amount
+        type: monotonic_count
+  - name: shipping
+    query: SELECT max(shipped), NULL FROM orders
+    tags: [team:logistics]
+    columns:
+      - name: shipped
+        type: gauge
+      - name: missing
+        type: gauge
+`, createDatabase(t)))
+
+	require.NoError(t, c.Run())
+
+	tags := []string{"driver:sqlite", "env:test"}
+	s.AssertServiceCheck(t, "sql.can_connect", servicecheck.ServiceCheckOK, "", tags, "")
+	s.AssertMetric(t, "Gauge", "app.orders.count", 2, "", append(tags, "status:paid"))
+	s.AssertMetric(t, "MonotonicCount", "app.orders.amount", 30.5, "", append(tags, "status:paid"))
+	s.AssertMetric(t, "Gauge", "app.orders.count", 1, "", append(tags, "status:cancelled"))
+	s.AssertMetric(t, "Gauge", "app.orders.count", 1, "", tags)
+	s.AssertServiceCheck(t, "sql.query", servicecheck.ServiceCheckOK, "", append(tags, "query:app.orders"), "")
+
+	s.AssertMetric(t, "Gauge", "sql.shipped", 1, "", append(tags, "team:logistics"))
+	s.AssertNotCalled(t, "Gauge", "sql.missing", mock.Anything, mock.Anything, mock.Anything)
+	s.AssertServiceCheck(t, "sql.query", servicecheck.ServiceCheckOK, "", append(tags, "query:shipping"), "")
+}
+
+func TestRunQueryErrors(t *testing.T) {
+	c, s := newTestCheck(t, fmt.Sprintf(`
+driver: sqlite
+dsn: %s
+query_timeout: 0.1
+custom_queries:
+  - name: missing_table
+    query: SELECT count(*) FROM customers
+    columns: [{name: count, type: gauge}]
+  - name: not_a_number
+    query: SELECT status FROM orders WHERE status IS NOT NULL
+    columns: [{name: status, type: gauge}]
+  - name: columns
+    query: SELECT status, amount FROM orders
+    columns: [{name: amount, type: gauge}]
+  - name: slow
+    query: WITH RECURSIVE c(x) AS (SELECT 1 UNION ALL SELECT x + 1 FROM c LIMIT 1000000000) SELECT max(x) FROM c
+    columns: [{name: slow, type: gauge}]
+  - name: valid
+    query: SELECT count(*) FROM orders
+    columns: [{name: orders, type: gauge}]
+`, createDatabase(t)))
+
+	err := c.Run()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "custom query missing_table")
+	assert.Contains(t, err.Error(), "custom query slow: query timed out after 100ms")
+
+	for _, name := range []string{"missing_table", "not_a_number", "columns", "slow"} {
+		s.AssertCalled(t, "ServiceCheck", "sql.query", servicecheck.ServiceCheckCritical, "", []string{"driver:sqlite", "query:" + name}, mock.Anything)
+	}
+	s.AssertServiceCheck(t, "sql.query", servicecheck.ServiceCheckCritical, "", []string{"driver:sqlite", "query:columns"}, "the query returned 2 columns but 1 are mapped")
+	s.AssertServiceCheck(t, "sql.query", servicecheck.ServiceCheckOK, "", []string{"driver:sqlite", "query:valid"}, "")
+	s.AssertMetric(t, "Gauge", "sql.orders", 4, "", []string{"driver:sqlite"})
+	s.AssertNumberOfCalls(t, "Gauge", 1)
+}
+
+func TestRunContextCancelled(t *testing.T) {
+	c, s := newTestCheck(t, fmt.Sprintf(`
+driver: sqlite
+dsn: %s
+query_timeout: 60
+custom_queries:
+  - name: slow
+    query: WITH RECURSIVE c(x) AS (SELECT 1 UNION ALL SELECT x + 1 FROM c LIMIT 1000000000) SELECT max(x) FROM c
+    columns: [{name: slow, type: gauge}]
+  - name: skipped
+    query: SELECT count(*) FROM orders
+    columns: [{name: orders, type: gauge}]
+`, createDatabase(t)))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	err := c.RunContext(ctx)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "custom query slow: context deadline exceeded")
+
+	s.AssertNotCalled(t, "ServiceCheck", "sql.query", mock.Anything, "", []string{"driver:sqlite", "query:skipped"}, mock.Anything)
+	s.AssertNumberOfCalls(t, "Gauge", 0)
+}
+
+func TestRunCollectionInterval(t *testing.T) {
+	c, s := newTestCheck(t, fmt.Sprintf(`
+driver: sqlite
+dsn: %s
+custom_queries:
+  - query: SELECT count(*) FROM orders
+    columns: [{name: every_run, type: gauge}]
+  - query: SELECT count(*) FROM orders
+    collection_interval: 3600
+    columns: [{name: hourly, type: gauge}]
+`, createDatabase(t)))
+
+	require.NoError(t, c.Run())
+	require.NoError(t, c.Run())
+	s.AssertNumberOfCalls(t, "Gauge", 3)
+
+	// the interval of the hourly query is elapsed
+	c.lastRuns[1] = c.lastRuns[1].Add(-time.Hour)
+	require.NoError(t, c.Run())
+	s.AssertNumberOfCalls(t, "Gauge", 5)
+}
+
+func TestRunCantConnect(t *testing.T) {
+	c, s := newTestCheck(t, fmt.Sprintf(`
+driver: sqlite
+dsn: file:%s?mode=ro
+custom_queries:
+  - query: SELECT 1
+    columns: [{name: one, type: gauge}]
+`, filepath.Join(t.TempDir(), "missing", "app.db")))
+
+	assert.Error(t, c.Run())
+	s.AssertCalled(t, "ServiceCheck", "sql.can_connect", servicecheck.ServiceCheckCritical, "", []string{"driver:sqlite"}, mock.Anything)
+	s.AssertNotCalled(t, "ServiceCheck", "sql.query", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
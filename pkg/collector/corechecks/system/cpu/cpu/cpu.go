@@ -17,6 +17,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/aggregator/sender"
 	"github.com/DataDog/datadog-agent/pkg/collector/check"
 	core "github.com/DataDog/datadog-agent/pkg/collector/corechecks"
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/system/pressure"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 	"github.com/DataDog/datadog-agent/pkg/util/optional"
 )
@@ -27,6 +28,7 @@ const CheckName = "cpu"
 var getCpuTimes = cpu.Times
 var getCpuInfo = cpu.Info
 var getContextSwitches = GetContextSwitches
+var reportPressure = pressure.Report
 
 // Check doesn't need additional fields
 type Check struct {
@@ -47,6 +49,7 @@ func (c *Check) Run() error {
 		return err
 	}
 	c.reportContextSwitches(sender)
+	c.reportPressure(sender)
 	numCores, err := c.reportCpuInfo(sender)
 	if err != nil {
 		return err
@@ -75,6 +78,13 @@ func (c *Check) reportContextSwitches(sender sender.Sender) {
 	}
 }
 
+func (c *Check) reportPressure(sender sender.Sender) {
+	if err := reportPressure(sender, pressure.CPU, "system.cpu.pressure"); err != nil {
+		// PSI is only available since Linux 4.20 and can be disabled
+		log.Debugf("could not read cpu pressure: %s", err)
+	}
+}
+
 func (c *Check) reportCpuInfo(sender sender.Sender) (numCores int32, err error) {
 	cpuInfo, err := getCpuInfo()
 	if err != nil {
@@ -8,12 +8,15 @@ package cpu
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
 	"github.com/DataDog/datadog-agent/pkg/aggregator"
 	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
+	"github.com/DataDog/datadog-agent/pkg/aggregator/sender"
 	"github.com/DataDog/datadog-agent/pkg/collector/check"
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/system/pressure"
 	"github.com/shirou/gopsutil/v4/cpu"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -103,6 +106,13 @@ func setupDefaultMocks() {
 	getContextSwitches = func() (int64, error) {
 		return 4, nil
 	}
+	reportPressure = func(s sender.Sender, resource string, prefix string) error {
+		if resource != pressure.CPU {
+			return fmt.Errorf("unexpected resource %s", resource)
+		}
+		s.Gauge(prefix+".some.avg10", 2.5, "", nil)
+		return nil
+	}
 	getCpuInfo = func() ([]cpu.InfoStat, error) {
 		return cpuInfo, nil
 	}
@@ -177,6 +187,35 @@ func TestContextSwitchesOk(t *testing.T) {
 	m.AssertMetric(t, "MonotonicCount", "system.cpu.context_switches", 4, "", []string(nil))
 }
 
+func TestPressureError(t *testing.T) {
+	setupDefaultMocks()
+	reportPressure = func(sender.Sender, string, string) error {
+		return errors.New("pressure error")
+	}
+	cpuCheck := createCheck()
+	m := mocksender.NewMockSender(cpuCheck.ID())
+	m.SetupAcceptAll()
+
+	cpuCheck.Configure(m.GetSenderManager(), integration.FakeConfigHash, nil, nil, "test")
+	err := cpuCheck.Run()
+
+	assert.Nil(t, err)
+	m.AssertMetric(t, "MonotonicCount", "system.cpu.context_switches", 4, "", []string(nil))
+}
+
+func TestPressureOk(t *testing.T) {
+	setupDefaultMocks()
+	cpuCheck := createCheck()
+	m := mocksender.NewMockSender(cpuCheck.ID())
+	m.SetupAcceptAll()
+
+	cpuCheck.Configure(m.GetSenderManager(), integration.FakeConfigHash, nil, nil, "test")
+	err := cpuCheck.Run()
+
+	assert.Nil(t, err)
+	m.AssertMetric(t, "Gauge", "system.cpu.pressure.some.avg10", 2.5, "", []string(nil))
+}
+
 func TestNumCoresError(t *testing.T) {
 	setupDefaultMocks()
 	cpuInfoError := errors.New("cpu.Check: could not query CPU info")
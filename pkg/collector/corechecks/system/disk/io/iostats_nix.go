@@ -19,13 +19,15 @@ import (
 	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
 	"github.com/DataDog/datadog-agent/pkg/aggregator/sender"
 	core "github.com/DataDog/datadog-agent/pkg/collector/corechecks"
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/system/pressure"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 )
 
 // For testing purpose
 var (
-	ioCounters = disk.IOCounters
-	swapMemory = mem.SwapMemory
+	ioCounters     = disk.IOCounters
+	swapMemory     = mem.SwapMemory
+	reportPressure = pressure.Report
 
 	// for test purpose
 	nowNano = func() int64 { return time.Now().UnixNano() }
@@ -185,6 +187,11 @@ func (c *IOCheck) nixIO() error {
 		log.Errorf("system.IOCheck: could not retrieve I/O block stats: %s", errSwap)
 	}
 
+	if err := reportPressure(sender, pressure.IO, "system.io.pressure"); err != nil {
+		// PSI is only available since Linux 4.20 and can be disabled
+		log.Debugf("system.IOCheck: could not read io pressure: %s", err)
+	}
+
 	c.stats = iomap
 	c.ts = now
 	return nil
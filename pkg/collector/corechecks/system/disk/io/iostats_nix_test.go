@@ -17,6 +17,8 @@ import (
 
 	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
 	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
+	"github.com/DataDog/datadog-agent/pkg/aggregator/sender"
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/system/pressure"
 )
 
 var currentStats = map[string]disk.IOCountersStat{
@@ -92,6 +94,11 @@ func TestIoStatsOverflow(t *testing.T) {
 		return currentStats, nil
 	}
 	swapMemory = SwapMemory
+	reportPressure = func(s sender.Sender, resource string, prefix string) error {
+		assert.Equal(t, pressure.IO, resource)
+		s.Gauge(prefix+".full.avg10", 0.5, "", nil)
+		return nil
+	}
 
 	mock.On("Rate", "system.io.r_s", 41.0, "", []string{"device:sda", "device_name:sda"}).Return().Times(1)
 	mock.On("Rate", "system.io.w_s", 41.0, "", []string{"device:sda", "device_name:sda"}).Return().Times(1)
@@ -108,6 +115,7 @@ func TestIoStatsOverflow(t *testing.T) {
 	mock.On("Gauge", "system.io.svctm", 0.5, "", []string{"device:sda", "device_name:sda"}).Return().Times(1)
 	mock.On("Rate", "system.io.block_in", 23.0, "", []string(nil)).Return().Times(1)
 	mock.On("Rate", "system.io.block_out", 24.0, "", []string(nil)).Return().Times(1)
+	mock.On("Gauge", "system.io.pressure.full.avg10", 0.5, "", []string(nil)).Return().Times(1)
 	mock.On("Commit").Return().Times(1)
 
 	// simulate a 1s interval
@@ -18,6 +18,7 @@ import (
 	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
 	"github.com/DataDog/datadog-agent/pkg/aggregator"
 	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
+	"github.com/DataDog/datadog-agent/pkg/aggregator/sender"
 )
 
 var (
@@ -98,6 +99,10 @@ func SwapMemory() (*mem.SwapMemoryStat, error) {
 	}, nil
 }
 
+func NoPressureReport(sender.Sender, string, string) error {
+	return nil
+}
+
 //nolint:revive // TODO(PLINT) Fix revive linter
 func sampler(samples []map[string]disk.IOCountersStat, _ ...string) (map[string]disk.IOCountersStat, error) {
 	idx := sampleIdx
@@ -136,6 +141,7 @@ func TestIOCheck(t *testing.T) {
 
 	ioCounters = ioSampler
 	swapMemory = SwapMemory
+	reportPressure = NoPressureReport
 	ioCheck := new(IOCheck)
 	mock := mocksender.NewMockSender(ioCheck.ID())
 	ioCheck.Configure(mock.GetSenderManager(), integration.FakeConfigHash, nil, nil, "test")
@@ -203,6 +209,7 @@ func TestIOCheck(t *testing.T) {
 func TestIOCheckBlacklist(t *testing.T) {
 	ioCounters = ioSampler
 	swapMemory = SwapMemory
+	reportPressure = NoPressureReport
 	ioCheck := new(IOCheck)
 	mock := mocksender.NewMockSender(ioCheck.ID())
 	ioCheck.Configure(mock.GetSenderManager(), integration.FakeConfigHash, nil, nil, "test")
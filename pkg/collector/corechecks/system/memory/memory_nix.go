@@ -16,12 +16,14 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 
 	core "github.com/DataDog/datadog-agent/pkg/collector/corechecks"
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/system/pressure"
 )
 
 // For testing purpose
 var virtualMemory = mem.VirtualMemory
 var swapMemory = mem.SwapMemory
 var runtimeOS = runtime.GOOS
+var reportPressure = pressure.Report
 
 // Check doesn't need additional fields
 type Check struct {
@@ -77,6 +79,11 @@ func (c *Check) Run() error {
 		return fmt.Errorf("failed to gather any memory information")
 	}
 
+	if err := reportPressure(sender, pressure.Memory, "system.mem.pressure"); err != nil {
+		// PSI is only available since Linux 4.20 and can be disabled
+		log.Debugf("memory.Check: could not read memory pressure: %s", err)
+	}
+
 	sender.Commit()
 	return nil
 }
@@ -16,6 +16,8 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
+	"github.com/DataDog/datadog-agent/pkg/aggregator/sender"
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/system/pressure"
 )
 
 func VirtualMemory() (*mem.VirtualMemoryStat, error) {
@@ -56,9 +58,22 @@ func SwapMemory() (*mem.SwapMemoryStat, error) {
 	}, nil
 }
 
+func PressureReport(s sender.Sender, resource string, prefix string) error {
+	if resource != pressure.Memory {
+		return fmt.Errorf("unexpected resource %s", resource)
+	}
+	s.Gauge(prefix+".some.avg10", 1.5, "", nil)
+	return nil
+}
+
+func NoPressureReport(sender.Sender, string, string) error {
+	return nil
+}
+
 func TestMemoryCheckLinux(t *testing.T) {
 	virtualMemory = VirtualMemory
 	swapMemory = SwapMemory
+	reportPressure = PressureReport
 	memCheck := new(Check)
 
 	mock := mocksender.NewMockSender(memCheck.ID())
@@ -85,6 +100,7 @@ func TestMemoryCheckLinux(t *testing.T) {
 	mock.On("Gauge", "system.swap.cached", 25000000000.0/mbSize, "", []string(nil)).Return().Times(1)
 	mock.On("Rate", "system.swap.swap_in", 21.0/mbSize, "", []string(nil)).Return().Times(1)
 	mock.On("Rate", "system.swap.swap_out", 22.0/mbSize, "", []string(nil)).Return().Times(1)
+	mock.On("Gauge", "system.mem.pressure.some.avg10", 1.5, "", []string(nil)).Return().Times(1)
 	mock.On("FinalizeCheckServiceTag").Return().Times(1)
 	mock.On("Commit").Return().Times(1)
 	memCheck.Configure(mock.GetSenderManager(), 0, nil, nil, "")
@@ -92,7 +108,7 @@ func TestMemoryCheckLinux(t *testing.T) {
 	require.Nil(t, err)
 
 	mock.AssertExpectations(t)
-	mock.AssertNumberOfCalls(t, "Gauge", 18)
+	mock.AssertNumberOfCalls(t, "Gauge", 19)
 	mock.AssertNumberOfCalls(t, "Rate", 2)
 	mock.AssertNumberOfCalls(t, "Commit", 1)
 }
@@ -100,6 +116,7 @@ func TestMemoryCheckLinux(t *testing.T) {
 func TestMemoryCheckFreebsd(t *testing.T) {
 	virtualMemory = VirtualMemory
 	swapMemory = SwapMemory
+	reportPressure = NoPressureReport
 	memCheck := new(Check)
 
 	mock := mocksender.NewMockSender(memCheck.ID())
@@ -133,6 +150,7 @@ func TestMemoryCheckFreebsd(t *testing.T) {
 func TestMemoryCheckDarwin(t *testing.T) {
 	virtualMemory = VirtualMemory
 	swapMemory = SwapMemory
+	reportPressure = NoPressureReport
 	memCheck := new(Check)
 
 	mock := mocksender.NewMockSender(memCheck.ID())
@@ -166,6 +184,7 @@ func TestMemoryCheckDarwin(t *testing.T) {
 func TestMemoryError(t *testing.T) {
 	virtualMemory = func() (*mem.VirtualMemoryStat, error) { return nil, fmt.Errorf("some error") }
 	swapMemory = func() (*mem.SwapMemoryStat, error) { return nil, fmt.Errorf("some error") }
+	reportPressure = NoPressureReport
 	memCheck := new(Check)
 
 	mock := mocksender.NewMockSender(memCheck.ID())
@@ -184,6 +203,7 @@ func TestMemoryError(t *testing.T) {
 func TestSwapMemoryError(t *testing.T) {
 	virtualMemory = VirtualMemory
 	swapMemory = func() (*mem.SwapMemoryStat, error) { return nil, fmt.Errorf("some error") }
+	reportPressure = NoPressureReport
 	memCheck := new(Check)
 
 	mock := mocksender.NewMockSender(memCheck.ID())
@@ -219,6 +239,7 @@ func TestSwapMemoryError(t *testing.T) {
 func TestVirtualMemoryError(t *testing.T) {
 	virtualMemory = func() (*mem.VirtualMemoryStat, error) { return nil, fmt.Errorf("some error") }
 	swapMemory = SwapMemory
+	reportPressure = NoPressureReport
 	memCheck := new(Check)
 
 	mock := mocksender.NewMockSender(memCheck.ID())
@@ -0,0 +1,15 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+// Package pressure reports the Pressure Stall Information (PSI) of the host, from the
+// /proc/pressure/{cpu,memory,io} files of Linux 4.20+, for the system checks.
+package pressure
+
+// Resources whose pressure is reported
+const (
+	CPU    = "cpu"
+	Memory = "memory"
+	IO     = "io"
+)
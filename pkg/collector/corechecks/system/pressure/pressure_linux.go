@@ -0,0 +1,60 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+//go:build linux
+
+package pressure
+
+import (
+	"path/filepath"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator/sender"
+	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
+	"github.com/DataDog/datadog-agent/pkg/util/cgroups"
+)
+
+// Report submits the pressure of a resource of the host, the share of time some or all of
+// the tasks were stalled on it over the last 10, 60 and 300 seconds as gauges, in percent:
+//
+//	<prefix>.some.avg10, <prefix>.some.avg60, <prefix>.some.avg300
+//	<prefix>.full.avg10, <prefix>.full.avg60, <prefix>.full.avg300
+//
+// and the total stall time as monotonic counts, in microseconds:
+//
+//	<prefix>.some.total, <prefix>.full.total
+//
+// An error is returned if the pressure of the resource isn't available, when the kernel
+// is older than 4.20 or was built or booted without PSI.
+func Report(s sender.Sender, resource string, prefix string) error {
+	var some, full cgroups.PSIStats
+	if err := cgroups.ParsePSI(filepath.Join(procfsPath(), "pressure", resource), &some, &full); err != nil {
+		return err
+	}
+	submit(s, prefix+".some", some)
+	submit(s, prefix+".full", full)
+	return nil
+}
+
+func submit(s sender.Sender, prefix string, stats cgroups.PSIStats) {
+	for name, value := range map[string]*float64{
+		".avg10":  stats.Avg10,
+		".avg60":  stats.Avg60,
+		".avg300": stats.Avg300,
+	} {
+		if value != nil {
+			s.Gauge(prefix+name, *value, "", nil)
+		}
+	}
+	if stats.Total != nil {
+		s.MonotonicCount(prefix+".total", float64(*stats.Total), "", nil)
+	}
+}
+
+func procfsPath() string {
+	if pkgconfigsetup.Datadog().IsSet("procfs_path") {
+		return pkgconfigsetup.Datadog().GetString("procfs_path")
+	}
+	return "/proc"
+}
@@ -0,0 +1,73 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+//go:build linux
+
+package pressure
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
+	configmock "github.com/DataDog/datadog-agent/pkg/config/mock"
+)
+
+func setupProcfs(t *testing.T, files map[string]string) {
+	procfs := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(procfs, "pressure"), 0755))
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(procfs, "pressure", name), []byte(content), 0644))
+	}
+	configmock.New(t).SetWithoutSource("procfs_path", procfs)
+}
+
+func TestReport(t *testing.T) {
+	setupProcfs(t, map[string]string{
+		"memory": "some avg10=1.50 avg60=0.75 avg300=0.20 total=123456\nfull avg10=0.50 avg60=0.25 avg300=0.10 total=6543\n",
+	})
+
+	s := mocksender.NewMockSender("")
+	s.SetupAcceptAll()
+	require.NoError(t, Report(s, Memory, "system.mem.pressure"))
+
+	s.AssertMetric(t, "Gauge", "system.mem.pressure.some.avg10", 1.5, "", nil)
+	s.AssertMetric(t, "Gauge", "system.mem.pressure.some.avg60", 0.75, "", nil)
+	s.AssertMetric(t, "Gauge", "system.mem.pressure.some.avg300", 0.2, "", nil)
+	s.AssertMetric(t, "MonotonicCount", "system.mem.pressure.some.total", 123456, "", nil)
+	s.AssertMetric(t, "Gauge", "system.mem.pressure.full.avg10", 0.5, "", nil)
+	s.AssertMetric(t, "Gauge", "system.mem.pressure.full.avg60", 0.25, "", nil)
+	s.AssertMetric(t, "Gauge", "system.mem.pressure.full.avg300", 0.1, "", nil)
+	s.AssertMetric(t, "MonotonicCount", "system.mem.pressure.full.total", 6543, "", nil)
+}
+
+func TestReportSomeOnly(t *testing.T) {
+	// before Linux 5.13, /proc/pressure/cpu only has the "some" line
+	setupProcfs(t, map[string]string{
+		"cpu": "some avg10=3.00 avg60=2.00 avg300=1.00 total=42\n",
+	})
+
+	s := mocksender.NewMockSender("")
+	s.SetupAcceptAll()
+	require.NoError(t, Report(s, CPU, "system.cpu.pressure"))
+
+	s.AssertMetric(t, "Gauge", "system.cpu.pressure.some.avg10", 3, "", nil)
+	s.AssertMetric(t, "MonotonicCount", "system.cpu.pressure.some.total", 42, "", nil)
+	s.AssertNumberOfCalls(t, "Gauge", 3)
+	s.AssertNumberOfCalls(t, "MonotonicCount", 1)
+}
+
+func TestReportUnavailable(t *testing.T) {
+	setupProcfs(t, nil)
+
+	s := mocksender.NewMockSender("")
+	s.SetupAcceptAll()
+	assert.Error(t, Report(s, IO, "system.io.pressure"))
+	s.AssertNumberOfCalls(t, "Gauge", 0)
+}
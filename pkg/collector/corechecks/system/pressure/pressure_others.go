@@ -0,0 +1,17 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+//go:build !linux
+
+package pressure
+
+import (
+	"github.com/DataDog/datadog-agent/pkg/aggregator/sender"
+)
+
+// Report is a no-op, the pressure stall information is only available on Linux
+func Report(sender.Sender, string, string) error {
+	return nil
+}
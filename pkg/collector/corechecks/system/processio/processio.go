@@ -0,0 +1,124 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+// Package processio implements a core check reporting the IO, file descriptors and context
+// switches of groups of processes selected by name, command line or systemd unit, from
+// the /proc/<pid>/{io,status,fd} files of Linux.
+package processio
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+)
+
+const (
+	// CheckName is the name of the check
+	CheckName = "process_io"
+
+	metricPrefix     = "system.processes."
+	upServiceCheck   = "process.up"
+	processNameTag   = "process_name:"
+	serviceCheckTag  = "process:"
+	defaultUnitType  = ".service"
+	cmdlineSeparator = " "
+)
+
+// instanceConfig is the configuration of an instance, which reports the metrics of one
+// group of processes
+type instanceConfig struct {
+	Name           string   `yaml:"name"`
+	Names          []string `yaml:"names"`
+	CmdlineRegexes []string `yaml:"cmdline_regexes"`
+	SystemdUnits   []string `yaml:"systemd_units"`
+	PerProcess     bool     `yaml:"per_process"`
+}
+
+// selector matches the processes of a group, a process is part of the group if it matches
+// any of its criteria
+type selector struct {
+	names           map[string]struct{}
+	cmdlineRegexes  []*regexp.Regexp
+	systemdPatterns []string
+}
+
+func parseConfig(rawInstance integration.Data) (*instanceConfig, *selector, error) {
+	var config instanceConfig
+	if err := yaml.Unmarshal(rawInstance, &config); err != nil {
+		return nil, nil, err
+	}
+	if config.Name == "" {
+		return nil, nil, errors.New("instance config `name` must not be empty")
+	}
+	if len(config.Names) == 0 && len(config.CmdlineRegexes) == 0 && len(config.SystemdUnits) == 0 {
+		return nil, nil, errors.New("instance config `names`, `cmdline_regexes` or `systemd_units` must not be empty")
+	}
+
+	s := &selector{names: make(map[string]struct{}, len(config.Names))}
+	for _, name := range config.Names {
+		s.names[name] = struct{}{}
+	}
+	for _, re := range config.CmdlineRegexes {
+		compiled, err := regexp.Compile(re)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid cmdline regex %q: %v", re, err)
+		}
+		s.cmdlineRegexes = append(s.cmdlineRegexes, compiled)
+	}
+	for _, unit := range config.SystemdUnits {
+		if path.Ext(unit) == "" {
+			unit += defaultUnitType
+		}
+		if _, err := path.Match(unit, ""); err != nil {
+			return nil, nil, fmt.Errorf("invalid systemd unit pattern %q: %v", unit, err)
+		}
+		s.systemdPatterns = append(s.systemdPatterns, unit)
+	}
+	return &config, s, nil
+}
+
+// matchesName returns whether the process name, or the base name of its executable since
+// the kernel truncates the names to 15 characters, is one of the selected names
+func (s *selector) matchesName(name string, cmdline []string) bool {
+	if _, found := s.names[name]; found {
+		return true
+	}
+	if len(cmdline) == 0 {
+		return false
+	}
+	_, found := s.names[path.Base(cmdline[0])]
+	return found
+}
+
+func (s *selector) matchesCmdline(cmdline []string) bool {
+	if len(s.cmdlineRegexes) == 0 || len(cmdline) == 0 {
+		return false
+	}
+	joined := strings.Join(cmdline, cmdlineSeparator)
+	for _, re := range s.cmdlineRegexes {
+		if re.MatchString(joined) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *selector) matchesUnit(unit string) bool {
+	if unit == "" {
+		return false
+	}
+	for _, pattern := range s.systemdPatterns {
+		if matched, _ := path.Match(pattern, unit); matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,323 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+//go:build linux
+
+package processio
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/aggregator/sender"
+	"github.com/DataDog/datadog-agent/pkg/collector/check"
+	core "github.com/DataDog/datadog-agent/pkg/collector/corechecks"
+	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
+	"github.com/DataDog/datadog-agent/pkg/metrics/servicecheck"
+	"github.com/DataDog/datadog-agent/pkg/process/procutil"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+	"github.com/DataDog/datadog-agent/pkg/util/optional"
+)
+
+// Check reports the IO, file descriptors and context switches of a group of processes
+type Check struct {
+	core.CheckBase
+	config   *instanceConfig
+	selector *selector
+	probe    procutil.Probe
+	procRoot string
+	tags     []string
+
+	// previous holds the counters of the processes at the last run, to report their increase
+	previous map[int32]counters
+	lastRun  time.Time
+	now      func() time.Time
+}
+
+// counters are the cumulative counters of a process
+type counters struct {
+	createTime  int64
+	readBytes   int64
+	writeBytes  int64
+	voluntary   int64
+	involuntary int64
+}
+
+// sample aggregates the stats of one or several processes
+type sample struct {
+	processes int
+	threads   int64
+	openFDs   int64
+	hasFDs    bool
+	io        procutil.IOCountersStat
+	hasIO     bool
+	ctx       procutil.NumCtxSwitchesStat
+	// increase of the counters since the last run
+	delta counters
+}
+
+// Factory creates a new check factory
+func Factory() optional.Option[func() check.Check] {
+	return optional.NewOption(newCheck)
+}
+
+func newCheck() check.Check {
+	return &Check{
+		CheckBase: core.NewCheckBase(CheckName),
+		previous:  make(map[int32]counters),
+		now:       time.Now,
+	}
+}
+
+// Configure parses the check configuration and creates the process probe
+func (c *Check) Configure(senderManager sender.SenderManager, integrationConfigDigest uint64, rawInstance integration.Data, rawInitConfig integration.Data, source string) error {
+	c.BuildID(integrationConfigDigest, rawInstance, rawInitConfig)
+	if err := c.CommonConfigure(senderManager, rawInitConfig, rawInstance, source); err != nil {
+		return err
+	}
+
+	config, selector, err := parseConfig(rawInstance)
+	if err != nil {
+		return err
+	}
+	c.config = config
+	c.selector = selector
+	c.tags = []string{processNameTag + config.Name}
+	if c.procRoot == "" {
+		c.procRoot = procfsPath()
+	}
+	if c.probe == nil {
+		// the stats requiring permissions are only read for the selected processes
+		c.probe = procutil.NewProcessProbe(
+			procutil.WithProcFSRoot(c.procRoot),
+			procutil.WithPermission(false),
+			procutil.WithReturnZeroPermStats(true),
+		)
+	}
+	return nil
+}
+
+// Run reports the stats of the selected processes
+func (c *Check) Run() error {
+	s, err := c.GetSender()
+	if err != nil {
+		return err
+	}
+	defer s.Commit()
+
+	now := c.now()
+	procs, err := c.probe.ProcessesByPID(now, false)
+	if err != nil {
+		return fmt.Errorf("could not list the processes: %w", err)
+	}
+
+	selected := c.selectProcesses(procs)
+	pids := make([]int32, 0, len(selected))
+	for _, p := range selected {
+		pids = append(pids, p.Pid)
+	}
+	statsWithPerm, err := c.probe.StatsWithPermByPID(pids)
+	if err != nil {
+		log.Debugf("%s: could not read the file descriptors and IO of the processes: %s", c.ID(), err)
+	}
+
+	var total sample
+	current := make(map[int32]counters, len(selected))
+	for _, p := range selected {
+		processSample := c.processSample(p, statsWithPerm[p.Pid], current)
+		total.add(processSample)
+		if c.config.PerProcess {
+			submitSample(s, processSample, append(c.processTags(), "pid:"+strconv.Itoa(int(p.Pid))))
+		}
+	}
+	c.previous = current
+	c.lastRun = now
+
+	s.Gauge(metricPrefix+"number", float64(total.processes), "", c.processTags())
+	if total.processes == 0 {
+		s.ServiceCheck(upServiceCheck, servicecheck.ServiceCheckCritical, "", []string{serviceCheckTag + c.config.Name}, "no process matches the selection")
+		return nil
+	}
+	s.ServiceCheck(upServiceCheck, servicecheck.ServiceCheckOK, "", []string{serviceCheckTag + c.config.Name}, "")
+	if !c.config.PerProcess {
+		submitSample(s, total, c.processTags())
+	}
+	return nil
+}
+
+// Cancel closes the process probe
+func (c *Check) Cancel() {
+	if c.probe != nil {
+		c.probe.Close()
+	}
+	c.CheckBase.Cancel()
+}
+
+func (c *Check) processTags() []string {
+	return append([]string{}, c.tags...)
+}
+
+// selectProcesses returns the processes of the group, sorted by PID
+func (c *Check) selectProcesses(procs map[int32]*procutil.Process) []*procutil.Process {
+	var selected []*procutil.Process
+	for _, p := range procs {
+		if c.selector.matchesName(p.Name, p.Cmdline) || c.selector.matchesCmdline(p.Cmdline) ||
+			(len(c.selector.systemdPatterns) > 0 && c.selector.matchesUnit(systemdUnit(c.procRoot, p.Pid))) {
+			selected = append(selected, p)
+		}
+	}
+	slices.SortFunc(selected, func(a, b *procutil.Process) int { return cmp.Compare(a.Pid, b.Pid) })
+	return selected
+}
+
+// processSample builds the sample of a process and records its counters in current
+func (c *Check) processSample(p *procutil.Process, statsWithPerm *procutil.StatsWithPerm, current map[int32]counters) sample {
+	processSample := sample{processes: 1}
+	cur := counters{readBytes: -1, writeBytes: -1}
+	if p.Stats != nil {
+		cur.createTime = p.Stats.CreateTime
+		processSample.threads = int64(p.Stats.NumThreads)
+		if p.Stats.CtxSwitches != nil {
+			processSample.ctx = *p.Stats.CtxSwitches
+			cur.voluntary = p.Stats.CtxSwitches.Voluntary
+			cur.involuntary = p.Stats.CtxSwitches.Involuntary
+		}
+	}
+	// the file descriptors and IO of the processes of other users can't be read without
+	// elevated permissions, they're reported as -1
+	if statsWithPerm != nil {
+		if statsWithPerm.OpenFdCount >= 0 {
+			processSample.openFDs = int64(statsWithPerm.OpenFdCount)
+			processSample.hasFDs = true
+		}
+		if io := statsWithPerm.IOStat; io != nil && io.ReadBytes >= 0 && io.WriteBytes >= 0 {
+			processSample.io = *io
+			processSample.hasIO = true
+			cur.readBytes = io.ReadBytes
+			cur.writeBytes = io.WriteBytes
+		}
+	}
+	current[p.Pid] = cur
+
+	prev, found := c.previous[p.Pid]
+	switch {
+	case found && prev.createTime == cur.createTime:
+	case !c.lastRun.IsZero() && cur.createTime > c.lastRun.UnixMilli():
+		// the process started since the last run, all its activity happened in the interval
+		prev = counters{}
+	default:
+		// the activity of the process before the first run of the check is unknown
+		return processSample
+	}
+	processSample.delta = counters{
+		voluntary:   increase(prev.voluntary, cur.voluntary),
+		involuntary: increase(prev.involuntary, cur.involuntary),
+	}
+	if cur.readBytes >= 0 && prev.readBytes >= 0 {
+		processSample.delta.readBytes = increase(prev.readBytes, cur.readBytes)
+		processSample.delta.writeBytes = increase(prev.writeBytes, cur.writeBytes)
+	}
+	return processSample
+}
+
+func increase(previous, current int64) int64 {
+	return max(current-previous, 0)
+}
+
+func (s *sample) add(other sample) {
+	s.processes += other.processes
+	s.threads += other.threads
+	if other.hasFDs {
+		s.openFDs += other.openFDs
+		s.hasFDs = true
+	}
+	if other.hasIO {
+		s.io.ReadCount += other.io.ReadCount
+		s.io.WriteCount += other.io.WriteCount
+		s.io.ReadBytes += other.io.ReadBytes
+		s.io.WriteBytes += other.io.WriteBytes
+		s.hasIO = true
+	}
+	s.ctx.Voluntary += other.ctx.Voluntary
+	s.ctx.Involuntary += other.ctx.Involuntary
+	s.delta.readBytes += other.delta.readBytes
+	s.delta.writeBytes += other.delta.writeBytes
+	s.delta.voluntary += other.delta.voluntary
+	s.delta.involuntary += other.delta.involuntary
+}
+
+// submitSample submits the stats of a sample, the cumulative values as gauges and their
+// increase since the last run as counts, with the names of the process integration
+func submitSample(s sender.Sender, stats sample, tags []string) {
+	s.Gauge(metricPrefix+"threads", float64(stats.threads), "", tags)
+	s.Gauge(metricPrefix+"voluntary_ctx_switches", float64(stats.ctx.Voluntary), "", tags)
+	s.Gauge(metricPrefix+"involuntary_ctx_switches", float64(stats.ctx.Involuntary), "", tags)
+	s.Count(metricPrefix+"voluntary_ctx_switches_count", float64(stats.delta.voluntary), "", tags)
+	s.Count(metricPrefix+"involuntary_ctx_switches_count", float64(stats.delta.involuntary), "", tags)
+	if stats.hasFDs {
+		s.Gauge(metricPrefix+"open_file_descriptors", float64(stats.openFDs), "", tags)
+	}
+	if stats.hasIO {
+		s.Gauge(metricPrefix+"ioread_count", float64(stats.io.ReadCount), "", tags)
+		s.Gauge(metricPrefix+"iowrite_count", float64(stats.io.WriteCount), "", tags)
+		s.Gauge(metricPrefix+"ioread_bytes", float64(stats.io.ReadBytes), "", tags)
+		s.Gauge(metricPrefix+"iowrite_bytes", float64(stats.io.WriteBytes), "", tags)
+		s.Count(metricPrefix+"ioread_bytes_count", float64(stats.delta.readBytes), "", tags)
+		s.Count(metricPrefix+"iowrite_bytes_count", float64(stats.delta.writeBytes), "", tags)
+	}
+}
+
+// systemdUnit returns the systemd unit of a process: the last service or scope of its
+// cgroup in the systemd hierarchy, or in the unified hierarchy of cgroup v2
+func systemdUnit(procRoot string, pid int32) string {
+	f, err := os.Open(filepath.Join(procRoot, strconv.Itoa(int(pid)), "cgroup"))
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	var unified, systemd string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// hierarchy-ID:controller-list:cgroup-path
+		fields := strings.SplitN(scanner.Text(), ":", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		switch {
+		case fields[1] == "name=systemd":
+			systemd = fields[2]
+		case fields[0] == "0" && fields[1] == "":
+			unified = fields[2]
+		}
+	}
+	cgroup := systemd
+	if cgroup == "" {
+		cgroup = unified
+	}
+
+	components := strings.Split(cgroup, "/")
+	for i := len(components) - 1; i >= 0; i-- {
+		if strings.HasSuffix(components[i], ".service") || strings.HasSuffix(components[i], ".scope") {
+			return components[i]
+		}
+	}
+	return ""
+}
+
+func procfsPath() string {
+	if pkgconfigsetup.Datadog().IsSet("procfs_path") {
+		return pkgconfigsetup.Datadog().GetString("procfs_path")
+	}
+	return "/proc"
+}
@@ -0,0 +1,219 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+//go:build linux
+
+package processio
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
+	"github.com/DataDog/datadog-agent/pkg/metrics/servicecheck"
+	"github.com/DataDog/datadog-agent/pkg/process/procutil"
+	"github.com/DataDog/datadog-agent/pkg/process/procutil/mocks"
+)
+
+func newProcess(pid int32, name string, cmdline []string, createTime int64, voluntary int64) *procutil.Process {
+	return &procutil.Process{
+		Pid:     pid,
+		Name:    name,
+		Cmdline: cmdline,
+		Stats: &procutil.Stats{
+			CreateTime:  createTime,
+			NumThreads:  2,
+			CtxSwitches: &procutil.NumCtxSwitchesStat{Voluntary: voluntary, Involuntary: 1},
+		},
+	}
+}
+
+func newStatsWithPerm(fds int32, readBytes, writeBytes int64) *procutil.StatsWithPerm {
+	return &procutil.StatsWithPerm{
+		OpenFdCount: fds,
+		IOStat:      &procutil.IOCountersStat{ReadCount: 10, WriteCount: 20, ReadBytes: readBytes, WriteBytes: writeBytes},
+	}
+}
+
+func setupCheck(t *testing.T, probe *mocks.Probe, procRoot string, instance string) (*Check, *mocksender.MockSender) {
+	c := newCheck().(*Check)
+	c.probe = probe
+	c.procRoot = procRoot
+	start := time.UnixMilli(100000)
+	c.now = func() time.Time {
+		start = start.Add(15 * time.Second)
+		return start
+	}
+
+	sm := mocksender.CreateDefaultDemultiplexer()
+	require.NoError(t, c.Configure(sm, integration.FakeConfigHash, integration.Data(instance), nil, "test"))
+	s := mocksender.NewMockSenderWithSenderManager(c.ID(), sm)
+	s.SetupAcceptAll()
+	return c, s
+}
+
+func TestConfigure(t *testing.T) {
+	for name, tc := range map[string]struct {
+		instance string
+		err      string
+	}{
+		"missing name":      {instance: "names: [nginx]", err: "instance config `name` must not be empty"},
+		"missing selection": {instance: "name: web", err: "instance config `names`, `cmdline_regexes` or `systemd_units` must not be empty"},
+		"invalid regex":     {instance: "name: web\ncmdline_regexes: ['(']", err: "invalid cmdline regex"},
+		"invalid unit":      {instance: "name: web\nsystemd_units: ['[']", err: "invalid systemd unit pattern"},
+		"valid":             {instance: "name: web\nnames: [nginx]\nsystemd_units: [nginx, 'php-fpm@*']"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			c := newCheck().(*Check)
+			c.probe = mocks.NewProbe(t)
+			err := c.Configure(mocksender.CreateDefaultDemultiplexer(), integration.FakeConfigHash, integration.Data(tc.instance), nil, "test")
+			if tc.err == "" {
+				require.NoError(t, err)
+				assert.Equal(t, []string{"nginx.service", "php-fpm@*.service"}, c.selector.systemdPatterns)
+			} else {
+				assert.ErrorContains(t, err, tc.err)
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	probe := mocks.NewProbe(t)
+	c, s := setupCheck(t, probe, t.TempDir(), "name: web\nnames: [nginx]\ncmdline_regexes: ['^/usr/bin/python3 .*gunicorn']")
+	tags := []string{"process_name:web"}
+
+	procs := map[int32]*procutil.Process{
+		1:  newProcess(1, "systemd", []string{"/sbin/init"}, 1000, 5),
+		10: newProcess(10, "nginx", []string{"nginx: master process"}, 1000, 100),
+		11: newProcess(11, "nginx", []string{"nginx: worker process"}, 1000, 200),
+		20: newProcess(20, "python3", []string{"/usr/bin/python3", "/usr/bin/gunicorn", "app:app"}, 1000, 300),
+	}
+	probe.On("ProcessesByPID", mock.Anything, false).Return(procs, nil).Once()
+	probe.On("StatsWithPermByPID", []int32{10, 11, 20}).Return(map[int32]*procutil.StatsWithPerm{
+		10: newStatsWithPerm(5, 1000, 2000),
+		11: newStatsWithPerm(7, 3000, 4000),
+		// the IO of a process of another user isn't readable
+		20: {OpenFdCount: -1, IOStat: &procutil.IOCountersStat{ReadCount: -1, WriteCount: -1, ReadBytes: -1, WriteBytes: -1}},
+	}, nil).Once()
+	require.NoError(t, c.Run())
+
+	s.AssertMetric(t, "Gauge", "system.processes.number", 3, "", tags)
+	s.AssertMetric(t, "Gauge", "system.processes.threads", 6, "", tags)
+	s.AssertMetric(t, "Gauge", "system.processes.open_file_descriptors", 12, "", tags)
+	s.AssertMetric(t, "Gauge", "system.processes.ioread_count", 20, "", tags)
+	s.AssertMetric(t, "Gauge", "system.processes.iowrite_count", 40, "", tags)
+	s.AssertMetric(t, "Gauge", "system.processes.ioread_bytes", 4000, "", tags)
+	s.AssertMetric(t, "Gauge", "system.processes.iowrite_bytes", 6000, "", tags)
+	s.AssertMetric(t, "Gauge", "system.processes.voluntary_ctx_switches", 600, "", tags)
+	s.AssertMetric(t, "Gauge", "system.processes.involuntary_ctx_switches", 3, "", tags)
+	// the increase of the counters is unknown on the first run
+	s.AssertMetric(t, "Count", "system.processes.ioread_bytes_count", 0, "", tags)
+	s.AssertMetric(t, "Count", "system.processes.voluntary_ctx_switches_count", 0, "", tags)
+	s.AssertServiceCheck(t, "process.up", servicecheck.ServiceCheckOK, "", []string{"process:web"}, "")
+
+	// the worker 11 exited and was replaced by the worker 12
+	delete(procs, 11)
+	procs[10] = newProcess(10, "nginx", []string{"nginx: master process"}, 1000, 150)
+	procs[12] = newProcess(12, "nginx", []string{"nginx: worker process"}, 120000, 20)
+	s.ResetCalls()
+	probe.On("ProcessesByPID", mock.Anything, false).Return(procs, nil).Once()
+	probe.On("StatsWithPermByPID", []int32{10, 12, 20}).Return(map[int32]*procutil.StatsWithPerm{
+		10: newStatsWithPerm(5, 1500, 2100),
+		12: newStatsWithPerm(3, 100, 200),
+	}, nil).Once()
+	require.NoError(t, c.Run())
+
+	s.AssertMetric(t, "Gauge", "system.processes.number", 3, "", tags)
+	s.AssertMetric(t, "Gauge", "system.processes.open_file_descriptors", 8, "", tags)
+	s.AssertMetric(t, "Count", "system.processes.ioread_bytes_count", 500+100, "", tags)
+	s.AssertMetric(t, "Count", "system.processes.iowrite_bytes_count", 100+200, "", tags)
+	s.AssertMetric(t, "Count", "system.processes.voluntary_ctx_switches_count", 50+20, "", tags)
+	s.AssertMetric(t, "Count", "system.processes.involuntary_ctx_switches_count", 1, "", tags)
+}
+
+func TestRunPerProcess(t *testing.T) {
+	probe := mocks.NewProbe(t)
+	c, s := setupCheck(t, probe, t.TempDir(), "name: web\nnames: [nginx]\nper_process: true")
+
+	probe.On("ProcessesByPID", mock.Anything, false).Return(map[int32]*procutil.Process{
+		10: newProcess(10, "nginx", []string{"nginx: master process"}, 1000, 100),
+		11: newProcess(11, "nginx", []string{"nginx: worker process"}, 1000, 200),
+	}, nil)
+	probe.On("StatsWithPermByPID", []int32{10, 11}).Return(map[int32]*procutil.StatsWithPerm{
+		10: newStatsWithPerm(5, 1000, 2000),
+		11: newStatsWithPerm(7, 3000, 4000),
+	}, nil)
+	require.NoError(t, c.Run())
+
+	s.AssertMetric(t, "Gauge", "system.processes.number", 2, "", []string{"process_name:web"})
+	s.AssertMetric(t, "Gauge", "system.processes.open_file_descriptors", 5, "", []string{"process_name:web", "pid:10"})
+	s.AssertMetric(t, "Gauge", "system.processes.open_file_descriptors", 7, "", []string{"process_name:web", "pid:11"})
+	s.AssertMetric(t, "Gauge", "system.processes.ioread_bytes", 3000, "", []string{"process_name:web", "pid:11"})
+	s.AssertNotCalled(t, "Gauge", "system.processes.open_file_descriptors", 12.0, "", []string{"process_name:web"})
+}
+
+func TestRunNoProcess(t *testing.T) {
+	probe := mocks.NewProbe(t)
+	c, s := setupCheck(t, probe, t.TempDir(), "name: web\nnames: [nginx]")
+
+	probe.On("ProcessesByPID", mock.Anything, false).Return(map[int32]*procutil.Process{
+		1: newProcess(1, "systemd", []string{"/sbin/init"}, 1000, 5),
+	}, nil)
+	probe.On("StatsWithPermByPID", []int32{}).Return(map[int32]*procutil.StatsWithPerm{}, nil)
+	require.NoError(t, c.Run())
+
+	s.AssertMetric(t, "Gauge", "system.processes.number", 0, "", []string{"process_name:web"})
+	s.AssertServiceCheck(t, "process.up", servicecheck.ServiceCheckCritical, "", []string{"process:web"}, "no process matches the selection")
+	s.AssertNumberOfCalls(t, "Gauge", 1)
+}
+
+func TestRunSystemdUnits(t *testing.T) {
+	procRoot := t.TempDir()
+	for pid, cgroup := range map[string]string{
+		// cgroup v2
+		"10": "0::/system.slice/nginx.service\n",
+		// cgroup v1, the unit is in the systemd hierarchy
+		"11": "12:memory:/system.slice\n1:name=systemd:/system.slice/nginx.service\n0::/system.slice/nginx.service\n",
+		"20": "0::/system.slice/cron.service\n",
+		"30": "0::/user.slice/user-1000.slice/session-2.scope\n",
+	} {
+		require.NoError(t, os.Mkdir(filepath.Join(procRoot, pid), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(procRoot, pid, "cgroup"), []byte(cgroup), 0644))
+	}
+
+	probe := mocks.NewProbe(t)
+	c, s := setupCheck(t, probe, procRoot, "name: web\nsystemd_units: [nginx, 'session-*.scope']")
+
+	probe.On("ProcessesByPID", mock.Anything, false).Return(map[int32]*procutil.Process{
+		10: newProcess(10, "nginx", []string{"nginx: master process"}, 1000, 100),
+		11: newProcess(11, "nginx", []string{"nginx: worker process"}, 1000, 200),
+		20: newProcess(20, "cron", []string{"/usr/sbin/cron"}, 1000, 300),
+		30: newProcess(30, "bash", []string{"-bash"}, 1000, 400),
+		40: newProcess(40, "sleep", []string{"sleep", "60"}, 1000, 400),
+	}, nil)
+	probe.On("StatsWithPermByPID", []int32{10, 11, 30}).Return(map[int32]*procutil.StatsWithPerm{}, nil)
+	require.NoError(t, c.Run())
+
+	s.AssertMetric(t, "Gauge", "system.processes.number", 3, "", []string{"process_name:web"})
+	// no file descriptors nor IO stats could be read
+	s.AssertNotCalled(t, "Gauge", "system.processes.open_file_descriptors", mock.Anything, mock.Anything, mock.Anything)
+	s.AssertNotCalled(t, "Gauge", "system.processes.ioread_bytes", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSystemdUnit(t *testing.T) {
+	procRoot := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(procRoot, "1"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(procRoot, "1", "cgroup"), []byte("0::/system.slice/docker.service/abc\n"), 0644))
+
+	assert.Equal(t, "docker.service", systemdUnit(procRoot, 1))
+	assert.Equal(t, "", systemdUnit(procRoot, 2))
+}
@@ -0,0 +1,61 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+//go:build systemd
+
+package systemd
+
+import (
+	"slices"
+
+	"github.com/coreos/go-systemd/v22/sdjournal"
+
+	"github.com/DataDog/datadog-agent/pkg/config/env"
+)
+
+// hostJournalPath is where the journal of the host is expected to be mounted in the
+// Docker Agent
+const hostJournalPath = "/host/var/log/journal"
+
+// readJournalLines returns the last messages logged by a unit in journald, oldest first
+func readJournalLines(unitName string, count int) ([]string, error) {
+	var journal *sdjournal.Journal
+	var err error
+	if env.IsContainerized() {
+		journal, err = sdjournal.NewJournalFromDir(hostJournalPath)
+	} else {
+		journal, err = sdjournal.NewJournal()
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer journal.Close()
+
+	if err := journal.AddMatch(sdjournal.SD_JOURNAL_FIELD_SYSTEMD_UNIT + "=" + unitName); err != nil {
+		return nil, err
+	}
+	if err := journal.SeekTail(); err != nil {
+		return nil, err
+	}
+
+	var lines []string
+	for len(lines) < count {
+		n, err := journal.Previous()
+		if err != nil {
+			return nil, err
+		}
+		if n == 0 {
+			break
+		}
+		message, err := journal.GetDataValue(sdjournal.SD_JOURNAL_FIELD_MESSAGE)
+		if err != nil {
+			// entries without message are skipped
+			continue
+		}
+		lines = append(lines, message)
+	}
+	slices.Reverse(lines)
+	return lines, nil
+}
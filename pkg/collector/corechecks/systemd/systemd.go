@@ -11,6 +11,8 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"path"
+	"regexp"
 	"strings"
 	"time"
 
@@ -141,15 +143,21 @@ var systemdStatusMapping = map[string]servicecheck.ServiceCheckStatus{
 // SystemdCheck aggregates metrics from one SystemdCheck instance
 type SystemdCheck struct {
 	core.CheckBase
-	stats  systemdStats
-	config systemdConfig
+	stats        systemdStats
+	config       systemdConfig
+	unitRegexes  []*regexp.Regexp
+	unitsHistory map[string]*unitHistory
 }
 type unitSubstateMapping = map[string]string
 
 type systemdInstanceConfig struct {
 	PrivateSocket         string                         `yaml:"private_socket"`
 	UnitNames             []string                       `yaml:"unit_names"`
+	UnitRegexes           []string                       `yaml:"unit_regexes"`
 	SubstateStatusMapping map[string]unitSubstateMapping `yaml:"substate_status_mapping"`
+	RestartLoopThreshold  int                            `yaml:"restart_loop_threshold"`
+	RestartLoopWindow     int                            `yaml:"restart_loop_window"`
+	JournalLines          int                            `yaml:"journal_lines"`
 }
 
 type systemdInitConfig struct{}
@@ -171,6 +179,9 @@ type systemdStats interface {
 	GetUnitTypeProperties(c *dbus.Conn, unitName string, unitType string) (map[string]interface{}, error)
 	GetVersion(c *dbus.Conn) (string, error)
 
+	// Journal
+	JournalLines(unitName string, count int) ([]string, error)
+
 	// Misc
 	UnixNow() int64
 }
@@ -205,6 +216,10 @@ func (s *defaultSystemdStats) GetVersion(c *dbus.Conn) (string, error) {
 	return c.GetManagerProperty("Version")
 }
 
+func (s *defaultSystemdStats) JournalLines(unitName string, count int) ([]string, error) {
+	return readJournalLines(unitName, count)
+}
+
 func (s *defaultSystemdStats) UnixNow() int64 {
 	return time.Now().Unix()
 }
@@ -323,6 +338,7 @@ func (c *SystemdCheck) submitMetrics(sender sender.Sender, conn *dbus.Conn) erro
 
 	loadedCount := 0
 	monitoredCount := 0
+	seenUnits := make(map[string]struct{})
 	for _, unit := range units {
 		if unit.LoadState == unitLoadedState {
 			loadedCount++
@@ -344,8 +360,11 @@ func (c *SystemdCheck) submitMetrics(sender sender.Sender, conn *dbus.Conn) erro
 		}
 
 		c.submitBasicUnitMetrics(sender, conn, unit, tags)
-		c.submitPropertyMetricsAsGauge(sender, conn, unit, tags)
+		serviceProperties := c.submitPropertyMetricsAsGauge(sender, conn, unit, tags)
+		c.trackUnit(sender, unit, serviceProperties, tags)
+		seenUnits[unit.Name] = struct{}{}
 	}
+	c.forgetUnits(seenUnits)
 
 	sender.Gauge("systemd.units_total", float64(len(units)), "", nil)
 	sender.Gauge("systemd.units_loaded_count", float64(loadedCount), "", nil)
@@ -396,7 +415,9 @@ func (c *SystemdCheck) submitCountMetrics(sender sender.Sender, units []dbus.Uni
 	}
 }
 
-func (c *SystemdCheck) submitPropertyMetricsAsGauge(sender sender.Sender, conn *dbus.Conn, unit dbus.UnitStatus, tags []string) {
+// submitPropertyMetricsAsGauge submits the metrics of the type of the unit, and returns
+// the properties of the unit specific to its type, if any
+func (c *SystemdCheck) submitPropertyMetricsAsGauge(sender sender.Sender, conn *dbus.Conn, unit dbus.UnitStatus, tags []string) map[string]interface{} {
 	for unitType := range metricConfigs {
 		if !strings.HasSuffix(unit.Name, "."+unitType) {
 			continue
@@ -404,7 +425,7 @@ func (c *SystemdCheck) submitPropertyMetricsAsGauge(sender sender.Sender, conn *
 		serviceProperties, err := c.stats.GetUnitTypeProperties(conn, unit.Name, dbusTypeMap[unitType])
 		if err != nil {
 			log.Warnf("Error getting detailed properties for unit %s", unit.Name)
-			return
+			return nil
 		}
 		for _, service := range metricConfigs[unitType] {
 			err := sendServicePropertyAsGauge(sender, serviceProperties, service, tags)
@@ -417,7 +438,9 @@ func (c *SystemdCheck) submitPropertyMetricsAsGauge(sender sender.Sender, conn *
 				}
 			}
 		}
+		return serviceProperties
 	}
+	return nil
 }
 
 func sendServicePropertyAsGauge(sender sender.Sender, properties map[string]interface{}, service metricConfigItem, tags []string) error {
@@ -509,12 +532,21 @@ func getServiceCheckStatus(state string, mapping map[string]string) servicecheck
 	return servicecheck.ServiceCheckUnknown
 }
 
-// isMonitored verifies if a unit should be monitored.
+// isMonitored verifies if a unit should be monitored. The unit names of the
+// configuration can be glob patterns, and the unit regexes must match the whole name.
 func (c *SystemdCheck) isMonitored(unitName string) bool {
 	for _, name := range c.config.instance.UnitNames {
 		if name == unitName {
 			return true
 		}
+		if matched, _ := path.Match(name, unitName); matched {
+			return true
+		}
+	}
+	for _, re := range c.unitRegexes {
+		if re.MatchString(unitName) {
+			return true
+		}
 	}
 	return false
 }
@@ -547,8 +579,30 @@ func (c *SystemdCheck) Configure(senderManager sender.SenderManager, integration
 		return err
 	}
 
-	if len(c.config.instance.UnitNames) == 0 {
-		return fmt.Errorf("instance config `unit_names` must not be empty")
+	if len(c.config.instance.UnitNames) == 0 && len(c.config.instance.UnitRegexes) == 0 {
+		return fmt.Errorf("instance config `unit_names` or `unit_regexes` must not be empty")
+	}
+
+	for _, name := range c.config.instance.UnitNames {
+		if _, err := path.Match(name, ""); err != nil {
+			return fmt.Errorf("invalid pattern '%s' in 'unit_names': %v", name, err)
+		}
+	}
+
+	c.unitRegexes = nil
+	for _, unitRegex := range c.config.instance.UnitRegexes {
+		re, err := regexp.Compile("^(?:" + unitRegex + ")$")
+		if err != nil {
+			return fmt.Errorf("invalid regex '%s' in 'unit_regexes': %v", unitRegex, err)
+		}
+		c.unitRegexes = append(c.unitRegexes, re)
+	}
+
+	if c.config.instance.RestartLoopThreshold <= 0 {
+		c.config.instance.RestartLoopThreshold = defaultRestartLoopThreshold
+	}
+	if c.config.instance.RestartLoopWindow <= 0 {
+		c.config.instance.RestartLoopWindow = defaultRestartLoopWindow
 	}
 
 	for unitNameInMapping := range c.config.instance.SubstateStatusMapping {
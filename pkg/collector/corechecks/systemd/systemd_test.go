@@ -73,6 +73,11 @@ func (s *mockSystemdStats) GetVersion(conn *dbus.Conn) (string, error) {
 	return args.Get(0).(string), nil
 }
 
+func (s *mockSystemdStats) JournalLines(unitName string, count int) ([]string, error) {
+	args := s.Mock.Called(unitName, count)
+	return args.Get(0).([]string), args.Error(1)
+}
+
 func (s *mockSystemdStats) UnixNow() int64 {
 	args := s.Mock.Called()
 	return args.Get(0).(int64)
@@ -112,7 +117,7 @@ func TestMissingUnitNamesShouldRaiseError(t *testing.T) {
 	check := SystemdCheck{}
 	err := check.Configure(aggregator.NewNoOpSenderManager(), integration.FakeConfigHash, []byte(``), []byte(``), "test")
 
-	expectedErrorMsg := "instance config `unit_names` must not be empty"
+	expectedErrorMsg := "instance config `unit_names` or `unit_regexes` must not be empty"
 	assert.EqualError(t, err, expectedErrorMsg)
 }
 
@@ -465,7 +470,8 @@ unit_names:
 	expectedGaugeCalls += 2 * 8 /* unit/service metrics */
 	mockSender.AssertNumberOfCalls(t, "Gauge", expectedGaugeCalls)
 	mockSender.AssertNumberOfCalls(t, "Commit", 1)
-	mockSender.AssertNumberOfCalls(t, "ServiceCheck", 4)
+	mockSender.AssertNumberOfCalls(t, "ServiceCheck", 4+2 /* restart loop */)
+	mockSender.AssertCalled(t, "ServiceCheck", unitRestartLoopServiceCheck, servicecheck.ServiceCheckOK, "", tags, "")
 }
 
 // When a value is not set (`[Not set]` when running `systemctl show my.service`), dbus returns MaxUint64
@@ -520,7 +526,7 @@ unit_names:
 	expectedGaugeCalls += 7 /* unit/service metrics */
 	mockSender.AssertNumberOfCalls(t, "Gauge", expectedGaugeCalls)
 	mockSender.AssertNumberOfCalls(t, "Commit", 1)
-	mockSender.AssertNumberOfCalls(t, "ServiceCheck", 3)
+	mockSender.AssertNumberOfCalls(t, "ServiceCheck", 3+1 /* restart loop */)
 }
 
 func TestSubmitMetricsConditionals(t *testing.T) {
@@ -0,0 +1,138 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+//go:build systemd
+
+package systemd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/coreos/go-systemd/v22/dbus"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator/sender"
+	"github.com/DataDog/datadog-agent/pkg/metrics/event"
+	"github.com/DataDog/datadog-agent/pkg/metrics/servicecheck"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	unitRestartLoopServiceCheck = "systemd.unit.restart_loop"
+	unitFailedState             = "failed"
+
+	defaultRestartLoopThreshold = 3
+	defaultRestartLoopWindow    = 300 // seconds
+)
+
+// unitHistory is the state of a monitored unit seen by the previous runs of the check
+type unitHistory struct {
+	activeState   string
+	restarts      []restartSample // oldest first, the first sample is the baseline of the window
+	inRestartLoop bool
+}
+
+// restartSample is the NRestarts property of a service at a given time
+type restartSample struct {
+	timestamp int64
+	count     uint64
+}
+
+// trackUnit compares the state of a unit with the one seen by the previous runs, to
+// report its state transitions, its entry in the failed state and its restart loops.
+func (c *SystemdCheck) trackUnit(sender sender.Sender, unit dbus.UnitStatus, serviceProperties map[string]interface{}, tags []string) {
+	if c.unitsHistory == nil {
+		c.unitsHistory = make(map[string]*unitHistory)
+	}
+
+	history, found := c.unitsHistory[unit.Name]
+	if !found {
+		history = &unitHistory{activeState: unit.ActiveState}
+		c.unitsHistory[unit.Name] = history
+	}
+
+	if history.activeState != unit.ActiveState {
+		transitionTags := append([]string{"previous_state:" + history.activeState, "state:" + unit.ActiveState}, tags...)
+		sender.Count("systemd.unit.state_transitions", 1, "", transitionTags)
+		if unit.ActiveState == unitFailedState {
+			c.submitUnitEvent(sender, unit.Name, c.stats.UnixNow(), fmt.Sprintf("Unit %s entered the failed state", unit.Name),
+				fmt.Sprintf("The unit %s went from the %s state to the failed state (%s).", unit.Name, history.activeState, unit.SubState), tags)
+		}
+		history.activeState = unit.ActiveState
+	}
+
+	nRestarts, err := getPropertyUint64(serviceProperties, "NRestarts")
+	if err != nil {
+		// not a service, or systemd older than v235
+		return
+	}
+
+	now := c.stats.UnixNow()
+	restarts := history.addRestartSample(now, nRestarts, int64(c.config.instance.RestartLoopWindow))
+	inRestartLoop := restarts >= uint64(c.config.instance.RestartLoopThreshold)
+	if inRestartLoop {
+		message := fmt.Sprintf("Unit %s restarted %d times in the last %d seconds", unit.Name, restarts, c.config.instance.RestartLoopWindow)
+		sender.ServiceCheck(unitRestartLoopServiceCheck, servicecheck.ServiceCheckCritical, "", tags, message)
+		if !history.inRestartLoop {
+			c.submitUnitEvent(sender, unit.Name, now, fmt.Sprintf("Unit %s is in a restart loop", unit.Name), message+".", tags)
+		}
+	} else {
+		sender.ServiceCheck(unitRestartLoopServiceCheck, servicecheck.ServiceCheckOK, "", tags, "")
+	}
+	history.inRestartLoop = inRestartLoop
+}
+
+// addRestartSample records the NRestarts property of the service and returns the
+// number of restarts within the window
+func (h *unitHistory) addRestartSample(now int64, count uint64, window int64) uint64 {
+	if len(h.restarts) > 0 && count < h.restarts[len(h.restarts)-1].count {
+		// the counter was reset, e.g. by `systemctl reset-failed`
+		h.restarts = nil
+	}
+	h.restarts = append(h.restarts, restartSample{timestamp: now, count: count})
+
+	// keep the last sample taken before the window as its baseline
+	windowStart := now - window
+	drop := 0
+	for drop+1 < len(h.restarts) && h.restarts[drop+1].timestamp <= windowStart {
+		drop++
+	}
+	h.restarts = h.restarts[drop:]
+
+	return count - h.restarts[0].count
+}
+
+// forgetUnits drops the history of the units which are no longer monitored
+func (c *SystemdCheck) forgetUnits(seenUnits map[string]struct{}) {
+	for name := range c.unitsHistory {
+		if _, found := seenUnits[name]; !found {
+			delete(c.unitsHistory, name)
+		}
+	}
+}
+
+// submitUnitEvent sends an error event about a unit, with its last journal lines
+// when the check is configured to collect them
+func (c *SystemdCheck) submitUnitEvent(sender sender.Sender, unitName string, now int64, title string, text string, tags []string) {
+	if count := c.config.instance.JournalLines; count > 0 {
+		lines, err := c.stats.JournalLines(unitName, count)
+		if err != nil {
+			log.Debugf("Error reading the journal of the unit %s: %v", unitName, err)
+		} else if len(lines) > 0 {
+			text = fmt.Sprintf("%%%%%% \n%s\n\nLast journal lines:\n```\n%s\n```\n %%%%%%", text, strings.Join(lines, "\n"))
+		}
+	}
+
+	sender.Event(event.Event{
+		Title:          title,
+		Text:           text,
+		Ts:             now,
+		AlertType:      event.AlertTypeError,
+		Tags:           tags,
+		AggregationKey: unitName,
+		SourceTypeName: CheckName,
+		EventType:      CheckName,
+	})
+}
@@ -0,0 +1,141 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+//go:build systemd
+
+package systemd
+
+import (
+	"testing"
+
+	"github.com/coreos/go-systemd/v22/dbus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/aggregator"
+	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
+	"github.com/DataDog/datadog-agent/pkg/metrics/event"
+	"github.com/DataDog/datadog-agent/pkg/metrics/servicecheck"
+)
+
+func TestIsMonitoredPatterns(t *testing.T) {
+	rawInstanceConfig := []byte(`
+unit_names:
+  - ssh.service
+  - docker-*.scope
+unit_regexes:
+  - 'kube(let|-proxy)\.service'
+`)
+
+	check := SystemdCheck{}
+	require.NoError(t, check.Configure(aggregator.NewNoOpSenderManager(), integration.FakeConfigHash, rawInstanceConfig, nil, "test"))
+
+	assert.True(t, check.isMonitored("ssh.service"))
+	assert.True(t, check.isMonitored("docker-1234.scope"))
+	assert.True(t, check.isMonitored("kubelet.service"))
+	assert.True(t, check.isMonitored("kube-proxy.service"))
+	assert.False(t, check.isMonitored("sshd.service"))
+	assert.False(t, check.isMonitored("docker.service"))
+	// the regexes must match the whole unit name
+	assert.False(t, check.isMonitored("my-kubelet.service.d"))
+}
+
+func TestInvalidUnitPatterns(t *testing.T) {
+	check := SystemdCheck{}
+	err := check.Configure(aggregator.NewNoOpSenderManager(), integration.FakeConfigHash, []byte("unit_regexes: ['kube(']"), nil, "test")
+	assert.ErrorContains(t, err, "invalid regex 'kube(' in 'unit_regexes'")
+
+	err = check.Configure(aggregator.NewNoOpSenderManager(), integration.FakeConfigHash, []byte("unit_names: ['ssh[.service']"), nil, "test")
+	assert.ErrorContains(t, err, "invalid pattern 'ssh[.service' in 'unit_names'")
+}
+
+func TestAddRestartSample(t *testing.T) {
+	h := &unitHistory{}
+
+	assert.Equal(t, uint64(0), h.addRestartSample(0, 5, 60))
+	assert.Equal(t, uint64(1), h.addRestartSample(15, 6, 60))
+	assert.Equal(t, uint64(3), h.addRestartSample(30, 8, 60))
+	assert.Equal(t, uint64(3), h.addRestartSample(60, 8, 60))
+	// the sample at 0 is no longer the baseline of the window
+	assert.Equal(t, uint64(2), h.addRestartSample(75, 8, 60))
+	assert.Equal(t, uint64(0), h.addRestartSample(150, 8, 60))
+	assert.Len(t, h.restarts, 2)
+
+	// the counter was reset
+	assert.Equal(t, uint64(0), h.addRestartSample(165, 1, 60))
+	assert.Len(t, h.restarts, 1)
+}
+
+func TestUnitTransitionsAndRestartLoop(t *testing.T) {
+	rawInstanceConfig := []byte(`
+unit_names:
+  - unit1.service
+restart_loop_threshold: 2
+restart_loop_window: 60
+journal_lines: 2
+`)
+	// mockStats returns the stats of the host at a given time
+	mockStats := func(now int64, activeState string, nRestarts uint32) *mockSystemdStats {
+		stats := createDefaultMockSystemdStats()
+		stats.On("GetVersion", mock.Anything).Return(systemdVersion)
+		stats.On("ListUnits", mock.Anything).Return([]dbus.UnitStatus{
+			{Name: "unit1.service", ActiveState: activeState, SubState: "failed", LoadState: "loaded"},
+		}, nil)
+		stats.On("UnixNow").Return(now)
+		stats.On("GetUnitTypeProperties", mock.Anything, "unit1.service", dbusTypeMap[typeUnit]).Return(map[string]interface{}{}, nil)
+		stats.On("GetUnitTypeProperties", mock.Anything, "unit1.service", dbusTypeMap[typeService]).Return(map[string]interface{}{
+			"NRestarts": nRestarts,
+		}, nil)
+		stats.On("JournalLines", "unit1.service", 2).Return([]string{"starting", "segfault"}, nil)
+		return stats
+	}
+
+	check := SystemdCheck{stats: mockStats(1000, "active", 0)}
+	senderManager := mocksender.CreateDefaultDemultiplexer()
+	require.NoError(t, check.Configure(senderManager, integration.FakeConfigHash, rawInstanceConfig, nil, "test"))
+
+	mockSender := mocksender.NewMockSenderWithSenderManager(check.ID(), senderManager)
+	mockSender.SetupAcceptAll()
+	tags := []string{"unit:unit1.service"}
+
+	require.NoError(t, check.Run())
+	mockSender.AssertServiceCheck(t, unitRestartLoopServiceCheck, servicecheck.ServiceCheckOK, "", tags, "")
+	mockSender.AssertNotCalled(t, "Count", "systemd.unit.state_transitions", mock.Anything, mock.Anything, mock.Anything)
+	mockSender.AssertNotCalled(t, "Event", mock.Anything)
+
+	// the unit restarted twice and failed
+	check.stats = mockStats(1015, "failed", 2)
+	mockSender.ResetCalls()
+	require.NoError(t, check.Run())
+
+	mockSender.AssertMetric(t, "Count", "systemd.unit.state_transitions", 1, "", []string{"previous_state:active", "state:failed", "unit:unit1.service"})
+	mockSender.AssertServiceCheck(t, unitRestartLoopServiceCheck, servicecheck.ServiceCheckCritical, "", tags, "Unit unit1.service restarted 2 times in the last 60 seconds")
+	mockSender.AssertNumberOfCalls(t, "Event", 2)
+	mockSender.AssertEvent(t, event.Event{
+		Title:          "Unit unit1.service entered the failed state",
+		Text:           "%%% \nThe unit unit1.service went from the active state to the failed state (failed).\n\nLast journal lines:\n```\nstarting\nsegfault\n```\n %%%",
+		Ts:             1015,
+		AlertType:      event.AlertTypeError,
+		Tags:           tags,
+		AggregationKey: "unit1.service",
+		SourceTypeName: CheckName,
+		EventType:      CheckName,
+	}, 0)
+
+	// still in the restart loop: no new event
+	check.stats = mockStats(1030, "failed", 2)
+	mockSender.ResetCalls()
+	require.NoError(t, check.Run())
+	mockSender.AssertServiceCheck(t, unitRestartLoopServiceCheck, servicecheck.ServiceCheckCritical, "", tags, "Unit unit1.service restarted 2 times in the last 60 seconds")
+	mockSender.AssertNotCalled(t, "Event", mock.Anything)
+
+	// no restart during the window
+	check.stats = mockStats(1090, "failed", 2)
+	mockSender.ResetCalls()
+	require.NoError(t, check.Run())
+	mockSender.AssertServiceCheck(t, unitRestartLoopServiceCheck, servicecheck.ServiceCheckOK, "", tags, "")
+}
@@ -0,0 +1,318 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package exec
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/aggregator/sender"
+	core "github.com/DataDog/datadog-agent/pkg/collector/corechecks"
+	"github.com/DataDog/datadog-agent/pkg/metrics/servicecheck"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// Output formats of the commands
+const (
+	NagiosFormat     = "nagios"
+	InfluxFormat     = "influx"
+	PrometheusFormat = "prometheus"
+)
+
+const (
+	defaultTimeout = 10 * time.Second
+	// maxOutputSize is the maximum size of the standard output and error of a command
+	maxOutputSize = 4 * 1024 * 1024
+	// maxMessageSize is the maximum size of the message of the service checks
+	maxMessageSize = 1024
+	// secretMask replaces the values of the environment variables in the logged output
+	secretMask = "********"
+)
+
+// instanceConfig is the configuration of an exec check instance
+type instanceConfig struct {
+	Command          []string          `yaml:"command"`
+	Format           string            `yaml:"format"`
+	Timeout          int               `yaml:"timeout"`
+	Env              map[string]string `yaml:"env"`
+	PassEnv          []string          `yaml:"pass_env"`
+	Namespace        string            `yaml:"namespace"`
+	ServiceCheckName string            `yaml:"service_check_name"`
+}
+
+// Check runs a command and submits the metrics parsed from its output
+type Check struct {
+	core.CheckBase
+	config  instanceConfig
+	timeout time.Duration
+	env     []string
+	secrets []string
+
+	cancelLock sync.Mutex
+	cancelRun  context.CancelFunc
+}
+
+// commandResult is the result of the execution of a command
+type commandResult struct {
+	stdout   []byte
+	stderr   []byte
+	exitCode int
+	timedOut bool
+}
+
+// NewCheck returns a new exec check
+func NewCheck(name string) *Check {
+	return &Check{
+		CheckBase: core.NewCheckBase(name),
+	}
+}
+
+// Configure parses the configuration of the check instance
+func (c *Check) Configure(senderManager sender.SenderManager, integrationConfigDigest uint64, data integration.Data, initConfig integration.Data, source string) error {
+	c.BuildID(integrationConfigDigest, data, initConfig)
+	if err := c.CommonConfigure(senderManager, initConfig, data, source); err != nil {
+		return err
+	}
+
+	if err := yaml.Unmarshal(data, &c.config); err != nil {
+		return err
+	}
+	if len(c.config.Command) == 0 || c.config.Command[0] == "" {
+		return errors.New("the command of the instance is missing")
+	}
+	switch c.config.Format {
+	case "":
+		c.config.Format = NagiosFormat
+	case NagiosFormat, InfluxFormat, PrometheusFormat:
+	default:
+		return fmt.Errorf("unsupported format %q, it must be one of %s, %s or %s", c.config.Format, NagiosFormat, InfluxFormat, PrometheusFormat)
+	}
+	if c.config.Format == NagiosFormat {
+		// the performance data labels are not namespaced
+		if c.config.Namespace == "" {
+			c.config.Namespace = c.String()
+		}
+		if c.config.ServiceCheckName == "" {
+			c.config.ServiceCheckName = c.config.Namespace + ".status"
+		}
+	}
+
+	c.timeout = defaultTimeout
+	if c.config.Timeout < 0 {
+		return fmt.Errorf("invalid timeout %d, it must be positive", c.config.Timeout)
+	} else if c.config.Timeout > 0 {
+		c.timeout = time.Duration(c.config.Timeout) * time.Second
+	}
+
+	c.env, c.secrets = buildEnv(c.config.Env, c.config.PassEnv)
+	return nil
+}
+
+// buildEnv returns the sanitized environment of the command: the default environment,
+// the variables of the agent listed in passEnv and the configured ones. It also returns
+// the configured values, which may be secrets.
+func buildEnv(configured map[string]string, passEnv []string) ([]string, []string) {
+	vars := defaultEnv()
+	for _, name := range passEnv {
+		if value, found := os.LookupEnv(name); found {
+			vars[name] = value
+		}
+	}
+	var secrets []string
+	for name, value := range configured {
+		vars[name] = value
+		if value != "" {
+			secrets = append(secrets, value)
+		}
+	}
+
+	env := make([]string, 0, len(vars))
+	for name, value := range vars {
+		env = append(env, name+"="+value)
+	}
+	sort.Strings(env)
+	// the longest values are masked first, in case they contain shorter ones
+	sort.Slice(secrets, func(i, j int) bool { return len(secrets[i]) > len(secrets[j]) })
+	return env, secrets
+}
+
+// Run runs the command and submits the metrics parsed from its output
+func (c *Check) Run() error {
+	s, err := c.GetSender()
+	if err != nil {
+		return fmt.Errorf("failed to retrieve a sender: %v", err)
+	}
+
+	result, err := c.execute()
+	if err != nil {
+		if c.config.Format == NagiosFormat {
+			s.ServiceCheck(c.config.ServiceCheckName, servicecheck.ServiceCheckUnknown, "", nil, truncate(err.Error()))
+			s.Commit()
+		}
+		return err
+	}
+	if len(result.stderr) > 0 {
+		log.Debugf("check %s: stderr of the command: %s", c.ID(), c.scrub(result.stderr))
+	}
+
+	switch c.config.Format {
+	case NagiosFormat:
+		err = c.submitNagios(s, result)
+	case InfluxFormat:
+		err = c.submitSamples(s, result, parseInflux)
+	case PrometheusFormat:
+		err = c.submitSamples(s, result, parsePrometheus)
+	}
+	s.Commit()
+	return err
+}
+
+// execute runs the command with the sanitized environment until it exits or times out
+func (c *Check) execute() (*commandResult, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+	c.cancelLock.Lock()
+	c.cancelRun = cancel
+	c.cancelLock.Unlock()
+
+	cmd := commandContext(ctx, c.config.Command[0], c.config.Command[1:]...)
+	cmd.Env = c.env
+	stdout := limitBuffer{max: maxOutputSize}
+	stderr := limitBuffer{max: maxOutputSize}
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	result := &commandResult{
+		stdout: stdout.buf.Bytes(),
+		stderr: stderr.buf.Bytes(),
+	}
+	var exitErr *exec.ExitError
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return nil, fmt.Errorf("command timed out after %s", c.timeout)
+	case ctx.Err() != nil:
+		return nil, errors.New("command was stopped")
+	case errors.As(err, &exitErr):
+		result.exitCode = exitErr.ExitCode()
+	case err != nil:
+		return nil, fmt.Errorf("could not run command %s: %v", c.config.Command[0], err)
+	}
+	return result, nil
+}
+
+func (c *Check) submitNagios(s sender.Sender, result *commandResult) error {
+	output := parseNagios(result.stdout)
+	status := nagiosStatus(result.exitCode)
+	message := output.text
+	if message == "" && status != servicecheck.ServiceCheckOK {
+		message = fmt.Sprintf("command exited with code %d", result.exitCode)
+	}
+	s.ServiceCheck(c.config.ServiceCheckName, status, "", nil, truncate(c.scrub([]byte(message))))
+
+	for _, err := range output.errors {
+		c.Warnf("Invalid performance data: %v", err) //nolint:errcheck
+	}
+	c.submit(s, output.samples)
+	return nil
+}
+
+func (c *Check) submitSamples(s sender.Sender, result *commandResult, parse func([]byte) ([]sample, []error)) error {
+	if result.exitCode != 0 {
+		return fmt.Errorf("command exited with code %d: %s", result.exitCode, truncate(c.scrub(result.stderr)))
+	}
+	samples, errs := parse(result.stdout)
+	for _, err := range errs {
+		c.Warnf("Invalid %s output: %v", c.config.Format, err) //nolint:errcheck
+	}
+	if len(samples) == 0 && len(errs) > 0 {
+		return fmt.Errorf("could not parse the %s output of the command", c.config.Format)
+	}
+	c.submit(s, samples)
+	return nil
+}
+
+func (c *Check) submit(s sender.Sender, samples []sample) {
+	for _, smp := range samples {
+		name := smp.name
+		if c.config.Namespace != "" {
+			name = c.config.Namespace + "." + name
+		}
+		switch {
+		case smp.monotonic:
+			s.MonotonicCount(name, smp.value, "", smp.tags)
+		case smp.timestamp > 0:
+			if err := s.GaugeWithTimestamp(name, smp.value, "", smp.tags, smp.timestamp); err != nil {
+				log.Debugf("check %s: could not submit %s: %v", c.ID(), name, err)
+			}
+		default:
+			s.Gauge(name, smp.value, "", smp.tags)
+		}
+	}
+}
+
+// scrub masks the values of the configured environment variables in an output of the command
+func (c *Check) scrub(output []byte) string {
+	scrubbed := strings.TrimSpace(string(output))
+	for _, secret := range c.secrets {
+		scrubbed = strings.ReplaceAll(scrubbed, secret, secretMask)
+	}
+	return scrubbed
+}
+
+// Stop kills the command if it's running
+func (c *Check) Stop() {
+	c.cancelLock.Lock()
+	defer c.cancelLock.Unlock()
+	if c.cancelRun != nil {
+		c.cancelRun()
+	}
+}
+
+// nagiosStatus returns the status of a service check from the exit code of a Nagios plugin
+func nagiosStatus(exitCode int) servicecheck.ServiceCheckStatus {
+	switch exitCode {
+	case 0:
+		return servicecheck.ServiceCheckOK
+	case 1:
+		return servicecheck.ServiceCheckWarning
+	case 2:
+		return servicecheck.ServiceCheckCritical
+	default:
+		return servicecheck.ServiceCheckUnknown
+	}
+}
+
+func truncate(message string) string {
+	if len(message) > maxMessageSize {
+		return message[:maxMessageSize] + "..."
+	}
+	return message
+}
+
+// limitBuffer is a buffer refusing to grow beyond its maximum size
+type limitBuffer struct {
+	max int
+	buf bytes.Buffer
+}
+
+func (b *limitBuffer) Write(p []byte) (n int, err error) {
+	if len(p)+b.buf.Len() > b.max {
+		return 0, fmt.Errorf("command output was too long: exceeded %d bytes", b.max)
+	}
+	return b.buf.Write(p)
+}
@@ -0,0 +1,121 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+//go:build !windows
+
+package exec
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
+	"github.com/DataDog/datadog-agent/pkg/metrics/servicecheck"
+)
+
+func writeScript(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "script.sh")
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"+content), 0o755))
+	return path
+}
+
+func newCheck(t *testing.T, instance string) (*Check, *mocksender.MockSender) {
+	mockSender := mocksender.NewMockSender("")
+	mockSender.SetupAcceptAll()
+
+	loader, err := NewLoader()
+	require.NoError(t, err)
+	c, err := loader.Load(mockSender.GetSenderManager(), integration.Config{Name: "legacy"}, integration.Data("loader: exec\n"+instance))
+	require.NoError(t, err)
+	mocksender.SetSender(mockSender, c.ID())
+	return c.(*Check), mockSender
+}
+
+func TestRunNagios(t *testing.T) {
+	script := writeScript(t, `echo "WARNING - load is high | load1=4.5;4;8 'rx bytes'=2KB"; exit 1`)
+	c, mockSender := newCheck(t, fmt.Sprintf("command: [%s]", script))
+
+	require.NoError(t, c.Run())
+	mockSender.AssertServiceCheck(t, "legacy.status", servicecheck.ServiceCheckWarning, "", nil, "WARNING - load is high")
+	mockSender.AssertMetric(t, "Gauge", "legacy.load1", 4.5, "", nil)
+	mockSender.AssertMetric(t, "Gauge", "legacy.rx_bytes", 2048, "", nil)
+	mockSender.AssertNumberOfCalls(t, "Commit", 1)
+}
+
+func TestRunNagiosTimeout(t *testing.T) {
+	script := writeScript(t, "sleep 10 & sleep 10")
+	c, mockSender := newCheck(t, fmt.Sprintf("command: [%s]\ntimeout: 1\nservice_check_name: legacy.can_run", script))
+
+	start := time.Now()
+	assert.EqualError(t, c.Run(), "command timed out after 1s")
+	// the children of the command are killed with it
+	assert.Less(t, time.Since(start), 5*time.Second)
+	mockSender.AssertServiceCheck(t, "legacy.can_run", servicecheck.ServiceCheckUnknown, "", nil, "command timed out after 1s")
+}
+
+func TestRunInflux(t *testing.T) {
+	script := writeScript(t, `echo "queue,name=$1 size=$QUEUE_SIZE"`)
+	c, mockSender := newCheck(t, fmt.Sprintf("command: [%s, jobs]\nformat: influx\nnamespace: app\nenv:\n  QUEUE_SIZE: '12'", script))
+
+	require.NoError(t, c.Run())
+	mockSender.AssertMetric(t, "Gauge", "app.queue.size", 12, "", []string{"name:jobs"})
+}
+
+func TestRunPrometheus(t *testing.T) {
+	script := writeScript(t, `printf '# TYPE jobs_total counter\njobs_total 3\n'`)
+	c, mockSender := newCheck(t, fmt.Sprintf("command: [%s]\nformat: prometheus", script))
+
+	require.NoError(t, c.Run())
+	mockSender.AssertMetric(t, "MonotonicCount", "jobs_total", 3, "", nil)
+}
+
+func TestRunFailureScrubsSecrets(t *testing.T) {
+	script := writeScript(t, `echo "could not connect with password $DB_PASSWORD" >&2; exit 3`)
+	c, _ := newCheck(t, fmt.Sprintf("command: [%s]\nformat: prometheus\nenv:\n  DB_PASSWORD: s3cr3t", script))
+
+	assert.EqualError(t, c.Run(), "command exited with code 3: could not connect with password ********")
+}
+
+func TestSanitizedEnvironment(t *testing.T) {
+	t.Setenv("DD_API_KEY", "abcdef")
+	t.Setenv("ALLOWED", "yes")
+	script := writeScript(t, `echo "env api_key=${#DD_API_KEY}i,allowed=\"$ALLOWED\",path=${#PATH}i"`)
+	c, mockSender := newCheck(t, fmt.Sprintf("command: [%s]\nformat: influx\npass_env: [ALLOWED]", script))
+
+	require.NoError(t, c.Run())
+	mockSender.AssertMetric(t, "Gauge", "env.api_key", 0, "", nil)
+	mockSender.AssertCalled(t, "Gauge", "env.path", float64(len(defaultEnv()["PATH"])), "", []string(nil))
+	assert.Contains(t, c.env, "ALLOWED=yes")
+}
+
+func TestInvalidConfiguration(t *testing.T) {
+	loader, err := NewLoader()
+	require.NoError(t, err)
+	senderManager := mocksender.NewMockSender("").GetSenderManager()
+
+	for _, instance := range []string{"loader: exec", "loader: exec\ncommand: [ls]\nformat: json", "loader: exec\ncommand: [ls]\ntimeout: -1"} {
+		_, err = loader.Load(senderManager, integration.Config{Name: "legacy"}, integration.Data(instance))
+		assert.Error(t, err, instance)
+	}
+}
+
+func TestLoaderSelection(t *testing.T) {
+	assert.True(t, isSelected(nil, integration.Data("loader: exec")))
+	assert.True(t, isSelected(integration.Data("loader: exec"), integration.Data("command: [ls]")))
+	assert.False(t, isSelected(integration.Data("loader: exec"), integration.Data("loader: python")))
+	assert.False(t, isSelected(nil, integration.Data("command: [ls]")))
+
+	loader, err := NewLoader()
+	require.NoError(t, err)
+	_, err = loader.Load(mocksender.NewMockSender("").GetSenderManager(), integration.Config{Name: "legacy"}, integration.Data("command: [ls]"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,37 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+//go:build windows
+
+package exec
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"time"
+)
+
+// waitDelay is the time given to the processes of a killed command to release its output
+const waitDelay = time.Second
+
+// commandContext returns a command which is killed when the context is done
+func commandContext(ctx context.Context, name string, arg ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, name, arg...)
+	cmd.WaitDelay = waitDelay
+	return cmd
+}
+
+// defaultEnv returns the environment variables always set for the commands, the
+// programs usually fail to start without the system ones
+func defaultEnv() map[string]string {
+	env := map[string]string{}
+	for _, name := range []string{"SYSTEMROOT", "WINDIR", "PATH", "PATHEXT", "TEMP", "TMP"} {
+		if value, found := os.LookupEnv(name); found {
+			env[name] = value
+		}
+	}
+	return env
+}
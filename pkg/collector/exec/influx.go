@@ -0,0 +1,144 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package exec
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parseInflux parses InfluxDB line protocol, one sample is returned for each numeric or
+// boolean field, named `<measurement>.<field>` and tagged with the tags of the line:
+//
+//	measurement[,tag=value...] field=value[,field=value...] [timestamp in nanoseconds]
+//
+// The string fields are ignored, the invalid lines are reported as errors.
+func parseInflux(output []byte) ([]sample, []error) {
+	var samples []sample
+	var errs []error
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	scanner.Buffer(make([]byte, 0, 64*1024), maxOutputSize)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		lineSamples, err := parseInfluxLine(line)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		samples = append(samples, lineSamples...)
+	}
+	if err := scanner.Err(); err != nil {
+		errs = append(errs, err)
+	}
+	return samples, errs
+}
+
+func parseInfluxLine(line string) ([]sample, error) {
+	sections := splitEscaped(line, ' ', true)
+	if len(sections) < 2 || len(sections) > 3 {
+		return nil, fmt.Errorf("invalid line %q", line)
+	}
+
+	series := splitEscaped(sections[0], ',', false)
+	measurement := unescapeInflux(series[0])
+	if measurement == "" {
+		return nil, fmt.Errorf("missing measurement in %q", line)
+	}
+	var tags []string
+	for _, tag := range series[1:] {
+		kv := splitEscaped(tag, '=', false)
+		if len(kv) != 2 || kv[0] == "" {
+			return nil, fmt.Errorf("invalid tag %q in %q", tag, line)
+		}
+		tags = append(tags, unescapeInflux(kv[0])+":"+unescapeInflux(kv[1]))
+	}
+
+	var timestamp float64
+	if len(sections) == 3 {
+		ns, err := strconv.ParseInt(sections[2], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timestamp in %q: %v", line, err)
+		}
+		timestamp = float64(ns) / 1e9
+	}
+
+	var samples []sample
+	for _, field := range splitEscaped(sections[1], ',', true) {
+		kv := splitEscaped(field, '=', true)
+		if len(kv) != 2 || kv[0] == "" {
+			return nil, fmt.Errorf("invalid field %q in %q", field, line)
+		}
+		value, numeric, err := parseInfluxValue(kv[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid field %q in %q: %v", field, line, err)
+		}
+		if !numeric {
+			continue
+		}
+		samples = append(samples, sample{
+			name:      measurement + "." + unescapeInflux(kv[0]),
+			value:     value,
+			tags:      tags,
+			timestamp: timestamp,
+		})
+	}
+	return samples, nil
+}
+
+// parseInfluxValue parses the value of a field, it returns false for the string values
+func parseInfluxValue(value string) (float64, bool, error) {
+	switch value {
+	case "t", "T", "true", "True", "TRUE":
+		return 1, true, nil
+	case "f", "F", "false", "False", "FALSE":
+		return 0, true, nil
+	}
+	if strings.HasPrefix(value, `"`) {
+		return 0, false, nil
+	}
+	if strings.HasSuffix(value, "i") {
+		v, err := strconv.ParseInt(strings.TrimSuffix(value, "i"), 10, 64)
+		return float64(v), true, err
+	}
+	if strings.HasSuffix(value, "u") {
+		v, err := strconv.ParseUint(strings.TrimSuffix(value, "u"), 10, 64)
+		return float64(v), true, err
+	}
+	v, err := strconv.ParseFloat(value, 64)
+	return v, true, err
+}
+
+// splitEscaped splits a string on the separators which are not escaped with a backslash,
+// nor in a double quoted string if quotes is set.
+func splitEscaped(s string, sep byte, quotes bool) []string {
+	var parts []string
+	start := 0
+	quoted := false
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\':
+			i++
+		case s[i] == '"' && quotes:
+			quoted = !quoted
+		case s[i] == sep && !quoted:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+var influxUnescaper = strings.NewReplacer(`\,`, ",", `\ `, " ", `\=`, "=", `\\`, `\`)
+
+func unescapeInflux(s string) string {
+	return influxUnescaper.Replace(s)
+}
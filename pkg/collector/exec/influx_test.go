@@ -0,0 +1,42 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package exec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseInflux(t *testing.T) {
+	samples, errs := parseInflux([]byte(`# comment
+cpu,host=server01,region=us\ west usage_idle=92.5,usage_user=3i,online=true,model="Xeon, 8 cores" 1465839830100400200
+disk\,io reads=10u,errors=F
+
+`))
+	assert.Empty(t, errs)
+	assert.Equal(t, []sample{
+		{name: "cpu.usage_idle", value: 92.5, tags: []string{"host:server01", "region:us west"}, timestamp: 1465839830.1004002},
+		{name: "cpu.usage_user", value: 3, tags: []string{"host:server01", "region:us west"}, timestamp: 1465839830.1004002},
+		{name: "cpu.online", value: 1, tags: []string{"host:server01", "region:us west"}, timestamp: 1465839830.1004002},
+		{name: "disk,io.reads", value: 10},
+		{name: "disk,io.errors", value: 0},
+	}, samples)
+}
+
+func TestParseInfluxInvalidLines(t *testing.T) {
+	samples, errs := parseInflux([]byte(`valid value=1
+missing_fields
+,tag=a value=1
+cpu,tag value=1
+cpu value=abc
+cpu value=1 notatimestamp
+`))
+	require.Len(t, samples, 1)
+	assert.Equal(t, "valid.value", samples[0].name)
+	assert.Len(t, errs, 5)
+}
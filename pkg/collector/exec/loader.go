@@ -0,0 +1,95 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+// Package exec implements a loader for the checks running an executable, such as a
+// Nagios plugin, and parsing its output.
+//
+// The loader must be selected with `loader: exec` in the init_config or instance
+// configuration. Each instance runs its `command` at every check run and parses its
+// standard output according to its `format`:
+//   - nagios (default): the exit code of the command is submitted as a service check
+//     and its performance data as metrics
+//   - influx: the output is parsed as InfluxDB line protocol, the fields are submitted as gauges
+//   - prometheus: the output is parsed as Prometheus text format
+//
+// The command doesn't inherit the environment of the agent, it only receives a default
+// PATH, the variables listed in `pass_env` and the ones set in `env`. Secrets are injected
+// through `env`, using `ENC[]` handles resolved by the secrets backend, for instance with
+// the `secret-helper` of the agent, so that they never appear on the command line.
+package exec
+
+import (
+	"errors"
+	"fmt"
+
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	tagger "github.com/DataDog/datadog-agent/comp/core/tagger/def"
+	integrations "github.com/DataDog/datadog-agent/comp/logs/integrations/def"
+	"github.com/DataDog/datadog-agent/pkg/aggregator/sender"
+	"github.com/DataDog/datadog-agent/pkg/collector/check"
+	"github.com/DataDog/datadog-agent/pkg/collector/loaders"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+	"github.com/DataDog/datadog-agent/pkg/util/optional"
+)
+
+// LoaderName is the name to set in the `loader` option to select the exec loader
+const LoaderName = "exec"
+
+func init() {
+	factory := func(sender.SenderManager, optional.Option[integrations.Component], tagger.Component) (check.Loader, error) {
+		return NewLoader()
+	}
+
+	loaders.RegisterLoader(40, factory)
+}
+
+// Loader is a specific loader for checks running an executable
+type Loader struct{}
+
+// NewLoader creates a loader for exec checks
+func NewLoader() (*Loader, error) {
+	return &Loader{}, nil
+}
+
+// Name returns the exec loader name
+func (l *Loader) Name() string {
+	return LoaderName
+}
+
+// Load returns a check running the command of the instance. The loader has to be
+// selected explicitly, not to run the commands of the configurations meant for other loaders.
+func (l *Loader) Load(senderManager sender.SenderManager, config integration.Config, instance integration.Data) (check.Check, error) {
+	if !isSelected(config.InitConfig, instance) {
+		return nil, errors.New("the exec loader must be selected with `loader: exec`")
+	}
+
+	c := NewCheck(config.Name)
+	if err := c.Configure(senderManager, config.FastDigest(), instance, config.InitConfig, config.Source); err != nil {
+		log.Errorf("exec.loader: could not configure check %s: %s", c, err)
+		return nil, fmt.Errorf("could not configure check %s: %s", c, err)
+	}
+	return c, nil
+}
+
+func (l *Loader) String() string {
+	return "Exec Check Loader"
+}
+
+// isSelected returns whether the exec loader is selected by the instance, or by the
+// init_config if the instance doesn't select a loader
+func isSelected(initConfig, instance integration.Data) bool {
+	var config struct {
+		LoaderName string `yaml:"loader"`
+	}
+	if err := yaml.Unmarshal(instance, &config); err == nil && config.LoaderName != "" {
+		return config.LoaderName == LoaderName
+	}
+	if err := yaml.Unmarshal(initConfig, &config); err == nil {
+		return config.LoaderName == LoaderName
+	}
+	return false
+}
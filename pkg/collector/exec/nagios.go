@@ -0,0 +1,179 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package exec
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// sample is a metric parsed from the output of a command
+type sample struct {
+	name      string
+	value     float64
+	tags      []string
+	monotonic bool
+	// timestamp is the time of the sample in seconds, 0 if it was not set
+	timestamp float64
+}
+
+// nagiosOutput is the parsed output of a Nagios plugin
+type nagiosOutput struct {
+	// text is the first line of the output, without the performance data
+	text    string
+	samples []sample
+	errors  []error
+}
+
+var (
+	perfdataValueRe   = regexp.MustCompile(`^([-+]?(?:[0-9]+\.?[0-9]*|\.[0-9]+)(?:[eE][-+]?[0-9]+)?)([a-zA-Z%]*)$`)
+	invalidMetricChar = regexp.MustCompile(`[^a-zA-Z0-9_.]+`)
+)
+
+// perfdataUnits holds the factors converting the values of the performance data to
+// seconds and bytes
+var perfdataUnits = map[string]float64{
+	"":   1,
+	"%":  1,
+	"s":  1,
+	"ms": 1e-3,
+	"us": 1e-6,
+	"B":  1,
+	"KB": 1 << 10,
+	"MB": 1 << 20,
+	"GB": 1 << 30,
+	"TB": 1 << 40,
+	"c":  1,
+}
+
+// parseNagios parses the output of a Nagios plugin. The performance data follows the
+// `|` of the first line, and the `|` of the long text, in which case it spans until
+// the end of the output:
+//
+//	TEXT | PERFDATA
+//	LONG TEXT | PERFDATA
+//	PERFDATA
+func parseNagios(output []byte) nagiosOutput {
+	var result nagiosOutput
+	lines := strings.Split(strings.TrimRight(string(bytes.ReplaceAll(output, []byte("\r\n"), []byte("\n"))), "\n"), "\n")
+
+	text, perfdata, _ := strings.Cut(lines[0], "|")
+	result.text = strings.TrimSpace(text)
+	perfdataLines := []string{perfdata}
+	for i, line := range lines[1:] {
+		if _, perfdata, found := strings.Cut(line, "|"); found {
+			perfdataLines = append(perfdataLines, perfdata)
+			perfdataLines = append(perfdataLines, lines[i+2:]...)
+			break
+		}
+	}
+
+	for _, line := range perfdataLines {
+		for _, field := range splitPerfdata(line) {
+			s, err := parsePerfdata(field)
+			if err != nil {
+				result.errors = append(result.errors, err)
+			} else if s != nil {
+				result.samples = append(result.samples, *s)
+			}
+		}
+	}
+	return result
+}
+
+// splitPerfdata splits a line of performance data on the spaces, except in the quoted labels
+func splitPerfdata(line string) []string {
+	var fields []string
+	var field strings.Builder
+	quoted := false
+	for _, r := range line {
+		switch {
+		case r == '\'':
+			quoted = !quoted
+			field.WriteRune(r)
+		case (r == ' ' || r == '\t') && !quoted:
+			if field.Len() > 0 {
+				fields = append(fields, field.String())
+				field.Reset()
+			}
+		default:
+			field.WriteRune(r)
+		}
+	}
+	if field.Len() > 0 {
+		fields = append(fields, field.String())
+	}
+	return fields
+}
+
+// parsePerfdata parses a performance data field: 'label'=value[UOM];[warn];[crit];[min];[max].
+// It returns nil for an undetermined value.
+func parsePerfdata(field string) (*sample, error) {
+	label, data, found := strings.Cut(field, "=")
+	if strings.HasPrefix(field, "'") {
+		// the quoted labels may contain spaces and equal signs, their quotes are doubled
+		label, data, found = cutQuotedLabel(field)
+	}
+	if !found || label == "" {
+		return nil, fmt.Errorf("missing label in %q", field)
+	}
+
+	value, _, _ := strings.Cut(data, ";")
+	if value == "U" {
+		return nil, nil
+	}
+	match := perfdataValueRe.FindStringSubmatch(value)
+	if match == nil {
+		return nil, fmt.Errorf("invalid value in %q", field)
+	}
+	v, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value in %q: %v", field, err)
+	}
+	unit := match[2]
+	if factor, found := perfdataUnits[unit]; found {
+		v *= factor
+	}
+
+	name := metricName(label)
+	if name == "" {
+		return nil, fmt.Errorf("label %q can't be used as a metric name", label)
+	}
+	return &sample{
+		name:      name,
+		value:     v,
+		monotonic: unit == "c",
+	}, nil
+}
+
+// cutQuotedLabel returns the quoted label of a performance data field and the data following it
+func cutQuotedLabel(field string) (label string, data string, found bool) {
+	var b strings.Builder
+	for i := 1; i < len(field); i++ {
+		if field[i] != '\'' {
+			b.WriteByte(field[i])
+			continue
+		}
+		if i+1 < len(field) && field[i+1] == '\'' {
+			b.WriteByte('\'')
+			i++
+			continue
+		}
+		if i+1 < len(field) && field[i+1] == '=' {
+			return b.String(), field[i+2:], true
+		}
+		break
+	}
+	return "", "", false
+}
+
+// metricName sanitizes a label to be used as a metric name
+func metricName(label string) string {
+	return strings.Trim(invalidMetricChar.ReplaceAllString(strings.ToLower(label), "_"), "_.")
+}
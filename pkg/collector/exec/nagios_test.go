@@ -0,0 +1,60 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package exec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/metrics/servicecheck"
+)
+
+func TestParseNagios(t *testing.T) {
+	output := parseNagios([]byte(`DISK OK - free space: /boot 3326 MB (56%); | /boot=2643MB;5948;5958;0;5968
+/home 15272 MB (77%);
+/var/log 819 MB (84%); | /home=69357MB;253404;253409;0;253414
+'time spent'=12ms;;;; requests=42c
+'it''s'=0.5 load=U
+`))
+
+	assert.Equal(t, "DISK OK - free space: /boot 3326 MB (56%);", output.text)
+	assert.Empty(t, output.errors)
+	assert.Equal(t, []sample{
+		{name: "boot", value: 2643 * (1 << 20)},
+		{name: "home", value: 69357 * (1 << 20)},
+		{name: "time_spent", value: 0.012},
+		{name: "requests", value: 42, monotonic: true},
+		{name: "it_s", value: 0.5},
+	}, output.samples)
+}
+
+func TestParseNagiosWithoutPerfdata(t *testing.T) {
+	output := parseNagios([]byte("PING CRITICAL - Packet loss = 100%\n"))
+	assert.Equal(t, "PING CRITICAL - Packet loss = 100%", output.text)
+	assert.Empty(t, output.samples)
+	assert.Empty(t, output.errors)
+
+	output = parseNagios(nil)
+	assert.Empty(t, output.text)
+	assert.Empty(t, output.samples)
+}
+
+func TestParseNagiosInvalidPerfdata(t *testing.T) {
+	output := parseNagios([]byte("OK | valid=1 =2 invalid=abc /=4 'unterminated=3"))
+	require.Len(t, output.samples, 1)
+	assert.Equal(t, "valid", output.samples[0].name)
+	assert.Len(t, output.errors, 4)
+}
+
+func TestNagiosStatus(t *testing.T) {
+	assert.Equal(t, servicecheck.ServiceCheckOK, nagiosStatus(0))
+	assert.Equal(t, servicecheck.ServiceCheckWarning, nagiosStatus(1))
+	assert.Equal(t, servicecheck.ServiceCheckCritical, nagiosStatus(2))
+	assert.Equal(t, servicecheck.ServiceCheckUnknown, nagiosStatus(3))
+	assert.Equal(t, servicecheck.ServiceCheckUnknown, nagiosStatus(127))
+}
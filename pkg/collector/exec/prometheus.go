@@ -0,0 +1,64 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package exec
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/DataDog/datadog-agent/pkg/util/prometheus"
+)
+
+// parsePrometheus parses Prometheus text format. The counters, and the buckets, sums and
+// counts of the histograms and summaries are cumulative, they are submitted as monotonic
+// counts, the other samples as gauges. The labels of the samples become tags.
+func parsePrometheus(output []byte) ([]sample, []error) {
+	families, err := prometheus.ParseMetrics(output)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	var samples []sample
+	for _, family := range families {
+		for _, s := range family.Samples {
+			if s == nil || math.IsNaN(float64(s.Value)) {
+				continue
+			}
+			name := string(s.Metric[model.MetricNameLabel])
+			samples = append(samples, sample{
+				name:      name,
+				value:     float64(s.Value),
+				tags:      prometheusTags(s.Metric),
+				monotonic: isCumulative(family.Type, name),
+			})
+		}
+	}
+	return samples, nil
+}
+
+func isCumulative(familyType string, name string) bool {
+	switch familyType {
+	case "COUNTER":
+		return true
+	case "HISTOGRAM", "SUMMARY":
+		return strings.HasSuffix(name, "_bucket") || strings.HasSuffix(name, "_sum") || strings.HasSuffix(name, "_count")
+	}
+	return false
+}
+
+func prometheusTags(metric model.Metric) []string {
+	var tags []string
+	for name, value := range metric {
+		if name != model.MetricNameLabel {
+			tags = append(tags, string(name)+":"+string(value))
+		}
+	}
+	sort.Strings(tags)
+	return tags
+}
@@ -0,0 +1,48 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package exec
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePrometheus(t *testing.T) {
+	samples, errs := parsePrometheus([]byte(`# TYPE jobs_total counter
+jobs_total{queue="default",status="done"} 42
+# TYPE temperature gauge
+temperature 21.5
+# TYPE latency histogram
+latency_bucket{le="0.1"} 3
+latency_bucket{le="+Inf"} 5
+latency_sum 0.7
+latency_count 5
+untyped_metric NaN
+`))
+	assert.Empty(t, errs)
+	sort.Slice(samples, func(i, j int) bool {
+		if samples[i].name != samples[j].name {
+			return samples[i].name < samples[j].name
+		}
+		return samples[i].value < samples[j].value
+	})
+	assert.Equal(t, []sample{
+		{name: "jobs_total", value: 42, tags: []string{"queue:default", "status:done"}, monotonic: true},
+		{name: "latency_bucket", value: 3, tags: []string{"le:0.1"}, monotonic: true},
+		{name: "latency_bucket", value: 5, tags: []string{"le:+Inf"}, monotonic: true},
+		{name: "latency_count", value: 5, monotonic: true},
+		{name: "latency_sum", value: 0.7, monotonic: true},
+		{name: "temperature", value: 21.5},
+	}, samples)
+}
+
+func TestParsePrometheusInvalid(t *testing.T) {
+	samples, errs := parsePrometheus([]byte("metric{label=unquoted} 1\n"))
+	assert.Empty(t, samples)
+	assert.Len(t, errs, 1)
+}
@@ -58,6 +58,7 @@ type PythonCheck struct {
 	class          *C.rtloader_pyobject_t
 	ModuleName     string
 	interval       time.Duration
+	timeout        time.Duration
 	lastWarnings   []error
 	source         string
 	telemetry      bool // whether or not the telemetry is enabled for this check
@@ -140,6 +141,29 @@ func (c *PythonCheck) Run() error {
 	return c.runCheck(true)
 }
 
+// RunContext runs a Python check, the run is interrupted when the context is done
+func (c *PythonCheck) RunContext(ctx context.Context) error {
+	stop := context.AfterFunc(ctx, c.interrupt)
+	defer stop()
+	return c.runCheck(true)
+}
+
+// interrupt raises a TimeoutError in the running check. The exception is only raised
+// once the interpreter runs the code of the check again, a check blocked in a call
+// to native code can't be interrupted until the call returns.
+func (c *PythonCheck) interrupt() {
+	gstate, err := newStickyLock()
+	if err != nil {
+		log.Warnf("failed to interrupt check %s: %s", c.id, err)
+		return
+	}
+	defer gstate.unlock()
+
+	if C.interrupt_check(rtloader, c.instance) == 1 {
+		log.Infof("interrupted the run of check %s", c.id)
+	}
+}
+
 // RunSimple runs a Python check without sending data to the aggregator
 func (c *PythonCheck) RunSimple() error {
 	return c.runCheck(false)
@@ -267,6 +291,11 @@ func (c *PythonCheck) Configure(senderManager sender.SenderManager, integrationC
 		c.interval = time.Duration(commonOptions.MinCollectionInterval) * time.Second
 	}
 
+	// See if a run timeout was specified
+	if commonOptions.CheckTimeout > 0 {
+		c.timeout = time.Duration(commonOptions.CheckTimeout) * time.Second
+	}
+
 	// Disable default hostname if specified
 	if commonOptions.EmptyDefaultHostname {
 		s, err := c.senderManager.GetSender(c.id)
@@ -363,6 +392,11 @@ func (c *PythonCheck) Interval() time.Duration {
 	return c.interval
 }
 
+// Timeout returns the maximum duration of a run of the check, 0 if it isn't bounded
+func (c *PythonCheck) Timeout() time.Duration {
+	return c.timeout
+}
+
 // ID returns the ID of the check
 func (c *PythonCheck) ID() checkid.ID {
 	return c.id
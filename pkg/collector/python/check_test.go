@@ -31,6 +31,10 @@ func TestCheckCancel(t *testing.T) {
 	testCheckCancel(t)
 }
 
+func TestCheckInterrupt(t *testing.T) {
+	testCheckInterrupt(t)
+}
+
 func TestCheckCancelWhenRuntimeUnloaded(t *testing.T) {
 	testCheckCancelWhenRuntimeUnloaded(t)
 }
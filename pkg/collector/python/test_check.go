@@ -8,6 +8,7 @@
 package python
 
 import (
+	"context"
 	"fmt"
 	"runtime"
 	"testing"
@@ -96,6 +97,15 @@ void cancel_check(rtloader_t *s, rtloader_pyobject_t *check) {
 	return;
 }
 
+int interrupt_check_calls = 0;
+int interrupt_check_return = 0;
+rtloader_pyobject_t *interrupt_check_instance = NULL;
+int interrupt_check(rtloader_t *s, rtloader_pyobject_t *check) {
+	interrupt_check_instance = check;
+	interrupt_check_calls++;
+	return interrupt_check_return;
+}
+
 char *get_check_diagnoses_return = NULL;
 int get_check_diagnoses_calls = 0;
 char *get_check_diagnoses(rtloader_t *s, rtloader_pyobject_t *check) {
@@ -183,6 +193,9 @@ void reset_check_mock() {
 	get_check_check = NULL;
 	cancel_check_calls = 0;
 	cancel_check_instance = NULL;
+	interrupt_check_calls = 0;
+	interrupt_check_return = 0;
+	interrupt_check_instance = NULL;
 
 	get_check_deprecated_calls = 0;
 	get_check_deprecated_return = 0;
@@ -310,6 +323,39 @@ func testCheckCancel(t *testing.T) {
 	assert.Equal(t, check.instance, C.cancel_check_instance)
 }
 
+func testCheckInterrupt(t *testing.T) {
+	rtloader = newMockRtLoaderPtr()
+	defer func() { rtloader = nil }()
+
+	check, err := NewPythonFakeCheck(aggregator.NewNoOpSenderManager())
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	C.reset_check_mock()
+	check.instance = newMockPyObjectPtr()
+	C.run_check_return = C.CString("")
+
+	// the run completes before the context is done
+	err = check.RunContext(context.Background())
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, C.int(0), C.interrupt_check_calls)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	C.interrupt_check_return = 1
+	err = check.RunContext(ctx)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	// Check that the call was passed to C with the lock acquired
+	assert.Eventually(t, func() bool { return C.interrupt_check_calls == 1 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, check.instance, C.interrupt_check_instance)
+}
+
 func testCheckCancelWhenRuntimeUnloaded(t *testing.T) {
 	rtloader = newMockRtLoaderPtr()
 	defer func() { rtloader = nil }()
@@ -12,6 +12,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/spf13/cast"
 	"go.uber.org/atomic"
 
 	haagent "github.com/DataDog/datadog-agent/comp/haagent/def"
@@ -67,6 +68,7 @@ func NewRunner(senderManager sender.SenderManager, haAgent haagent.Component) *R
 		pendingChecksChan:   make(chan check.Check),
 		checksTracker:       tracker.NewRunningChecksTracker(),
 	}
+	r.checksTracker.SetConcurrencyLimits(concurrencyLimits())
 
 	if !r.isStaticWorkerCount {
 		numWorkers = pkgconfigsetup.DefaultNumWorkers
@@ -77,6 +79,21 @@ func NewRunner(senderManager sender.SenderManager, haAgent haagent.Component) *R
 	return r
 }
 
+// concurrencyLimits returns the maximum number of instances of the checks that
+// can run at the same time, by check name
+func concurrencyLimits() map[string]int {
+	limits := make(map[string]int)
+	for name, value := range pkgconfigsetup.Datadog().GetStringMap("check_concurrency_limits") {
+		limit, err := cast.ToIntE(value)
+		if err != nil || limit < 0 {
+			log.Warnf("Invalid concurrency limit %v for the %s check, ignoring it", value, name)
+			continue
+		}
+		limits[name] = limit
+	}
+	return limits
+}
+
 // EnsureMinWorkers increases the number of workers to match the
 // `desiredNumWorkers` parameter
 func (r *Runner) ensureMinWorkers(desiredNumWorkers int) {
@@ -260,8 +277,10 @@ func (r *Runner) ShouldAddCheckStats(id checkid.ID) bool {
 }
 
 // StopCheck invokes the `Stop` method on a check if it's running. If the check
-// is not running, this is a noop
+// is not running, this is a noop. The timeout backoff of the check is dropped.
 func (r *Runner) StopCheck(id checkid.ID) error {
+	worker.ForgetCheck(id)
+
 	done := make(chan bool)
 
 	stopFunc := func(c check.Check) {
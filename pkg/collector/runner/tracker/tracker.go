@@ -7,6 +7,7 @@
 package tracker
 
 import (
+	"slices"
 	"sync"
 
 	"github.com/DataDog/datadog-agent/pkg/collector/check"
@@ -23,14 +24,34 @@ type withRunningChecksFunc func(map[checkid.ID]check.Check)
 // RunningChecksTracker is an object that keeps a thread-safe track of
 // all the running checks
 type RunningChecksTracker struct {
-	runningChecks map[checkid.ID]check.Check // The list of checks running
-	accessLock    sync.RWMutex               // To control races on runningChecks
+	runningChecks     map[checkid.ID]check.Check // The list of checks running
+	concurrencyLimits map[string]int             // The maximum number of running instances, by check name
+	runningByName     map[string]int             // The number of running instances, by check name
+	deferredChecks    map[string][]check.Check   // The checks waiting for their concurrency limit, by check name
+	accessLock        sync.RWMutex               // To control races on runningChecks
 }
 
 // NewRunningChecksTracker is a contructor for a RunningChecksTracker
 func NewRunningChecksTracker() *RunningChecksTracker {
 	return &RunningChecksTracker{
-		runningChecks: make(map[checkid.ID]check.Check),
+		runningChecks:     make(map[checkid.ID]check.Check),
+		concurrencyLimits: make(map[string]int),
+		runningByName:     make(map[string]int),
+		deferredChecks:    make(map[string][]check.Check),
+	}
+}
+
+// SetConcurrencyLimits sets the maximum number of instances of a check that can
+// run at the same time, by check name. The checks without a limit aren't bounded.
+func (t *RunningChecksTracker) SetConcurrencyLimits(limits map[string]int) {
+	t.accessLock.Lock()
+	defer t.accessLock.Unlock()
+
+	t.concurrencyLimits = make(map[string]int, len(limits))
+	for name, limit := range limits {
+		if limit > 0 {
+			t.concurrencyLimits[name] = limit
+		}
 	}
 }
 
@@ -46,6 +67,10 @@ func (t *RunningChecksTracker) Check(id checkid.ID) (check.Check, bool) {
 // AddCheck adds a check to the list of running checks if the check
 // isn't already added. Method returns a boolean if the addition was
 // successful.
+//
+// When the concurrency limit of the check name is reached, the check isn't
+// added but deferred: it's returned by NextDeferredCheck once another instance
+// of the check completes.
 func (t *RunningChecksTracker) AddCheck(check check.Check) bool {
 	t.accessLock.Lock()
 	defer t.accessLock.Unlock()
@@ -54,7 +79,15 @@ func (t *RunningChecksTracker) AddCheck(check check.Check) bool {
 		return false
 	}
 
+	name := check.String()
+	if limit, found := t.concurrencyLimits[name]; found && t.runningByName[name] >= limit {
+		t.deferCheck(name, check)
+		return false
+	}
+
 	t.runningChecks[check.ID()] = check
+	t.runningByName[name]++
+	t.removeDeferredCheck(name, check.ID())
 	return true
 }
 
@@ -63,7 +96,83 @@ func (t *RunningChecksTracker) DeleteCheck(id checkid.ID) {
 	t.accessLock.Lock()
 	defer t.accessLock.Unlock()
 
+	check, found := t.runningChecks[id]
+	if !found {
+		return
+	}
 	delete(t.runningChecks, id)
+
+	name := check.String()
+	t.runningByName[name]--
+	if t.runningByName[name] <= 0 {
+		delete(t.runningByName, name)
+	}
+}
+
+// NextDeferredCheck returns the oldest check with the given name deferred because
+// of its concurrency limit, removing it from the deferred checks. No check is
+// returned while the concurrency limit is still reached, since it would be
+// deferred again.
+func (t *RunningChecksTracker) NextDeferredCheck(name string) (check.Check, bool) {
+	t.accessLock.Lock()
+	defer t.accessLock.Unlock()
+
+	deferred := t.deferredChecks[name]
+	if len(deferred) == 0 {
+		return nil, false
+	}
+	if limit, found := t.concurrencyLimits[name]; found && t.runningByName[name] >= limit {
+		return nil, false
+	}
+
+	check := deferred[0]
+	deferred[0] = nil
+	if len(deferred) == 1 {
+		delete(t.deferredChecks, name)
+	} else {
+		t.deferredChecks[name] = deferred[1:]
+	}
+	return check, true
+}
+
+// IsDeferred returns whether the check is waiting for its concurrency limit
+func (t *RunningChecksTracker) IsDeferred(check check.Check) bool {
+	t.accessLock.RLock()
+	defer t.accessLock.RUnlock()
+
+	for _, c := range t.deferredChecks[check.String()] {
+		if c.ID() == check.ID() {
+			return true
+		}
+	}
+	return false
+}
+
+// deferCheck adds the check to the deferred checks, unless it's already deferred
+func (t *RunningChecksTracker) deferCheck(name string, check check.Check) {
+	for _, c := range t.deferredChecks[name] {
+		if c.ID() == check.ID() {
+			return
+		}
+	}
+	t.deferredChecks[name] = append(t.deferredChecks[name], check)
+}
+
+// removeDeferredCheck removes the check from the deferred checks, if it was
+// deferred, when one of its scheduled runs could be started
+func (t *RunningChecksTracker) removeDeferredCheck(name string, id checkid.ID) {
+	deferred := t.deferredChecks[name]
+	for i, c := range deferred {
+		if c.ID() == id {
+			deferred = slices.Delete(deferred, i, i+1)
+			if len(deferred) == 0 {
+				delete(t.deferredChecks, name)
+			} else {
+				t.deferredChecks[name] = deferred
+			}
+			return
+		}
+	}
 }
 
 // WithRunningChecks takes in a function to execute in the context of a locked
@@ -213,3 +213,58 @@ func TestRunningChecksTrackerWithCheck(t *testing.T) {
 
 	wg.Wait()
 }
+
+func TestRunningChecksTrackerConcurrencyLimits(t *testing.T) {
+	tracker := NewRunningChecksTracker()
+	tracker.SetConcurrencyLimits(map[string]int{"snmp": 2, "disk": 0})
+
+	snmp1 := newTestCheck("snmp:1")
+	snmp2 := newTestCheck("snmp:2")
+	snmp3 := newTestCheck("snmp:3")
+	snmp4 := newTestCheck("snmp:4")
+
+	assert.True(t, tracker.AddCheck(snmp1))
+	assert.True(t, tracker.AddCheck(snmp2))
+
+	// the limit is reached, the checks are deferred once
+	assert.False(t, tracker.AddCheck(snmp3))
+	assert.False(t, tracker.AddCheck(snmp4))
+	assert.False(t, tracker.AddCheck(snmp3))
+	assert.True(t, tracker.IsDeferred(snmp3))
+	assert.False(t, tracker.IsDeferred(snmp1))
+
+	// the checks without a limit, or with a null one, aren't bounded
+	for i := 0; i < 5; i++ {
+		assert.True(t, tracker.AddCheck(newTestCheck(fmt.Sprintf("disk:%d", i))))
+		assert.True(t, tracker.AddCheck(newTestCheck(fmt.Sprintf("cpu:%d", i))))
+	}
+
+	// an already running check isn't deferred
+	assert.False(t, tracker.AddCheck(snmp1))
+	assert.False(t, tracker.IsDeferred(snmp1))
+
+	_, found := tracker.NextDeferredCheck("cpu")
+	assert.False(t, found)
+
+	tracker.DeleteCheck(snmp1.ID())
+	next, found := tracker.NextDeferredCheck("snmp")
+	require.True(t, found)
+	assert.Equal(t, snmp3, next)
+	assert.True(t, tracker.AddCheck(next))
+	assert.False(t, tracker.AddCheck(snmp1))
+
+	// a deferred check is no longer deferred once one of its runs starts
+	tracker.DeleteCheck(snmp2.ID())
+	assert.True(t, tracker.AddCheck(snmp4))
+	assert.False(t, tracker.IsDeferred(snmp4))
+
+	// the deferred checks are kept while the limit is reached
+	_, found = tracker.NextDeferredCheck("snmp")
+	assert.False(t, found)
+	assert.True(t, tracker.IsDeferred(snmp1))
+	tracker.DeleteCheck(snmp3.ID())
+	_, found = tracker.NextDeferredCheck("snmp")
+	assert.True(t, found) // snmp1, deferred above
+	_, found = tracker.NextDeferredCheck("snmp")
+	assert.False(t, found)
+}
@@ -8,6 +8,8 @@ package scheduler
 
 import (
 	"fmt"
+	"hash/fnv"
+	"sort"
 	"sync"
 	"time"
 
@@ -69,14 +71,16 @@ type jobQueue struct {
 	currentBucketIdx    uint
 	schedulingBucketIdx uint
 	running             bool
+	spread              bool // whether the jobs are placed according to their offsets, see jobOffset
 	health              *health.Handle
 	mu                  sync.RWMutex // to protect critical sections in struct's fields
 }
 
 // newJobQueue creates a new jobQueue instance
-func newJobQueue(interval time.Duration) *jobQueue {
+func newJobQueue(interval time.Duration, spread bool) *jobQueue {
 	jq := &jobQueue{
 		interval:     interval,
+		spread:       spread,
 		stop:         make(chan bool),
 		stopped:      make(chan bool),
 		health:       health.RegisterLiveness(fmt.Sprintf("collector-queue-%vs", interval.Seconds())),
@@ -120,11 +124,31 @@ func (jq *jobQueue) addJob(c check.Check) {
 	jq.mu.Lock()
 	defer jq.mu.Unlock()
 
+	if jq.spread {
+		jq.buckets[jq.jobOffset(c.ID())/time.Second].addJob(c)
+		return
+	}
+
 	// Checks scheduled to buckets scheduled with sparse round-robin
 	jq.buckets[jq.schedulingBucketIdx].addJob(c)
 	jq.schedulingBucketIdx = (jq.schedulingBucketIdx + jq.sparseStep) % uint(len(jq.buckets))
 }
 
+// jobOffset returns the offset of a check within the interval of the queue. It's
+// derived from the ID of the check so that the instances are spread uniformly over
+// the interval, and keep the same offset across restarts.
+// The offset gives both the bucket of the check and its delay within the bucket.
+func (jq *jobQueue) jobOffset(id checkid.ID) time.Duration {
+	span := time.Duration(len(jq.buckets)) * time.Second
+	if jq.interval < span {
+		span = jq.interval
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(id)) //nolint:errcheck
+	return time.Duration(h.Sum64() % uint64(span))
+}
+
 func (jq *jobQueue) removeJob(id checkid.ID) error {
 	jq.mu.Lock()
 	defer jq.mu.Unlock()
@@ -197,11 +221,32 @@ func (jq *jobQueue) process(s *Scheduler) bool {
 
 		log.Tracef("Jobs in bucket: %v", jobs)
 
-		for _, check := range jobs {
+		// the jobs are delayed by their offset within the bucket when they're spread
+		delays := make([]time.Duration, len(jobs))
+		if jq.spread {
+			for i, check := range jobs {
+				delays[i] = jq.jobOffset(check.ID()) % time.Second
+			}
+			sort.Sort(jobsByDelay{jobs: jobs, delays: delays})
+		}
+
+		for i, check := range jobs {
 			if !s.IsCheckScheduled(check.ID()) {
 				continue
 			}
 
+			scheduledAt := t.Add(delays[i])
+			if wait := time.Until(scheduledAt); wait > 0 {
+				timer := time.NewTimer(wait)
+				select {
+				case <-timer.C:
+				case <-jq.stop:
+					timer.Stop()
+					jq.health.Deregister() //nolint:errcheck
+					return false
+				}
+			}
+
 			select {
 			// blocking, we'll be here as long as it takes
 			case s.checksPipe <- check:
@@ -210,6 +255,11 @@ func (jq *jobQueue) process(s *Scheduler) bool {
 				return false
 			}
 
+			// the pipe isn't buffered: the lag is the time the check waited for a worker
+			if check.IsTelemetryEnabled() {
+				tlmSchedulingLag.Observe(time.Since(scheduledAt).Seconds(), check.String())
+			}
+
 			select {
 			// we were able to schedule a check so we're not stuck, therefore poll the health chan
 			case <-jq.health.C:
@@ -225,3 +275,16 @@ func (jq *jobQueue) process(s *Scheduler) bool {
 
 	return true
 }
+
+// jobsByDelay sorts the jobs of a bucket by their delay within the bucket
+type jobsByDelay struct {
+	jobs   []check.Check
+	delays []time.Duration
+}
+
+func (j jobsByDelay) Len() int           { return len(j.jobs) }
+func (j jobsByDelay) Less(a, b int) bool { return j.delays[a] < j.delays[b] }
+func (j jobsByDelay) Swap(a, b int) {
+	j.jobs[a], j.jobs[b] = j.jobs[b], j.jobs[a]
+	j.delays[a], j.delays[b] = j.delays[b], j.delays[a]
+}
@@ -6,12 +6,15 @@
 package scheduler
 
 import (
+	"fmt"
 	"runtime"
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/DataDog/datadog-agent/pkg/collector/check"
 	checkid "github.com/DataDog/datadog-agent/pkg/collector/check/id"
 	"github.com/DataDog/datadog-agent/pkg/util/testutil"
 )
@@ -66,3 +69,73 @@ func TestBucket_RemoveJob(t *testing.T) {
 	// use the bucket, just to keep it alive during the earlier GC run
 	bucket.addJob(&TestJobCheck{id: "here so the GC doesn't GC the entire bucket"})
 }
+
+func TestJobQueueSpread(t *testing.T) {
+	jq := newJobQueue(15*time.Second, true)
+
+	for i := 0; i < 1500; i++ {
+		jq.addJob(&TestJobCheck{id: fmt.Sprintf("snmp:%d", i)})
+	}
+
+	// the checks are spread over the buckets
+	for _, bucket := range jq.buckets {
+		assert.InDelta(t, 100, bucket.size(), 40)
+	}
+
+	// the offsets are deterministic, and bounded by the interval
+	other := newJobQueue(15*time.Second, true)
+	for i := 0; i < 100; i++ {
+		id := checkid.ID(fmt.Sprintf("snmp:%d", i))
+		offset := jq.jobOffset(id)
+		assert.Equal(t, offset, other.jobOffset(id))
+		assert.GreaterOrEqual(t, offset, time.Duration(0))
+		assert.Less(t, offset, 15*time.Second)
+	}
+
+	// sub-second intervals use a single bucket
+	jq = newJobQueue(500*time.Millisecond, true)
+	require.Len(t, jq.buckets, 1)
+	assert.Less(t, jq.jobOffset("snmp:1"), 500*time.Millisecond)
+}
+
+func TestJobQueueRoundRobin(t *testing.T) {
+	jq := newJobQueue(4*time.Second, false)
+
+	for i := 0; i < 4; i++ {
+		jq.addJob(&TestJobCheck{id: fmt.Sprintf("check:%d", i)})
+	}
+	for _, bucket := range jq.buckets {
+		assert.Equal(t, 1, bucket.size())
+	}
+}
+
+func TestJobQueueProcessDelays(t *testing.T) {
+	pipe := make(chan check.Check, 10)
+	s := NewScheduler(pipe)
+	jq := newJobQueue(time.Second, true)
+	defer jq.bucketTicker.Stop()
+
+	var checks []*TestJobCheck
+	for i := 0; i < 5; i++ {
+		c := &TestJobCheck{id: fmt.Sprintf("check:%d", i)}
+		checks = append(checks, c)
+		jq.addJob(c)
+		s.checkToQueue[c.ID()] = jq
+	}
+
+	start := time.Now()
+	// the queue may process a health check before the tick of the bucket
+	for len(pipe) < len(checks) {
+		require.True(t, jq.process(s))
+	}
+
+	// the checks are sent in the order of their offsets, after their delay
+	var previous time.Duration
+	for range checks {
+		c := <-pipe
+		offset := jq.jobOffset(c.ID())
+		assert.GreaterOrEqual(t, offset, previous)
+		previous = offset
+	}
+	assert.GreaterOrEqual(t, time.Since(start), previous)
+}
@@ -13,6 +13,7 @@ import (
 
 	"go.uber.org/atomic"
 
+	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
 	"github.com/DataDog/datadog-agent/pkg/telemetry"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 
@@ -30,6 +31,9 @@ var (
 		[]string{"check_name"}, "How many checks are currently tracked by the scheduler")
 	tlmQueuesCount = telemetry.NewCounter("scheduler", "queues_count",
 		nil, "How many queues were opened")
+	tlmSchedulingLag = telemetry.NewHistogram("scheduler", "scheduling_lag",
+		[]string{"check_name"}, "Time in seconds between the scheduled time of a check run and its pick up by a worker",
+		[]float64{0.01, 0.05, 0.1, 0.5, 1, 5, 10, 30, 60})
 )
 
 func init() {
@@ -50,6 +54,8 @@ type Scheduler struct {
 	tlmTrackedChecks map[checkid.ID]string       // Keep track of the checks that are tracked with telemetry
 	mu               sync.Mutex                  // To protect critical sections in struct's fields
 
+	spread bool // Whether the checks are spread over their interval according to their ID
+
 	checkToQueue map[checkid.ID]*jobQueue // Keep track of what is the queue for any Check
 	// To protect checkToQueue. Using mu would create a deadlock when stopping the Scheduler. 'jobQueue' is calling
 	// 'IsCheckScheduled' right when then 'Stop' function is called and mu is already lock. for this reason we have
@@ -72,6 +78,7 @@ func NewScheduler(checksPipe chan<- check.Check) *Scheduler {
 		checkToQueue:     make(map[checkid.ID]*jobQueue),
 		tlmTrackedChecks: make(map[checkid.ID]string),
 		running:          atomic.NewBool(false),
+		spread:           pkgconfigsetup.Datadog().GetBool("check_scheduling_spread"),
 		cancelOneTime:    make(chan bool),
 		wgOneTime:        sync.WaitGroup{},
 	}
@@ -97,7 +104,7 @@ func (s *Scheduler) Enter(check check.Check) error {
 	defer s.mu.Unlock()
 
 	if _, ok := s.jobQueues[check.Interval()]; !ok {
-		s.jobQueues[check.Interval()] = newJobQueue(check.Interval())
+		s.jobQueues[check.Interval()] = newJobQueue(check.Interval(), s.spread)
 		s.startQueue(s.jobQueues[check.Interval()])
 		if check.IsTelemetryEnabled() {
 			tlmQueuesCount.Inc()
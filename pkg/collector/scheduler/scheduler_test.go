@@ -94,7 +94,9 @@ func TestEnter(t *testing.T) {
 	assert.Len(t, s.jobQueues, 2)
 	assert.Len(t, s.jobQueues[1*time.Second].buckets[0].jobs, 3)
 	assert.Len(t, s.jobQueues[c.intl].buckets, 20)
-	assert.Len(t, s.jobQueues[c.intl].buckets[0].jobs, 1)
+	// the check is placed in the bucket of its offset
+	q := s.jobQueues[c.intl]
+	assert.Len(t, q.buckets[q.jobOffset(c.ID())/time.Second].jobs, 1)
 
 	stop <- true
 }
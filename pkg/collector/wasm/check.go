@@ -0,0 +1,288 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package wasm
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+	yaml "gopkg.in/yaml.v2"
+	yamlv3 "gopkg.in/yaml.v3"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/aggregator/sender"
+	core "github.com/DataDog/datadog-agent/pkg/collector/corechecks"
+	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	// wasmPageSize is the size of a page of WebAssembly memory
+	wasmPageSize = 64 * 1024
+	// maxMemoryPages is the maximum number of pages of a 32-bit memory
+	maxMemoryPages = 65536
+)
+
+// compilationCache shares the compiled modules between the runtimes of all the check instances
+var compilationCache = wazero.NewCompilationCache()
+
+// limitsConfig holds the resources limits of a check instance
+type limitsConfig struct {
+	MaxMemoryMB int `yaml:"wasm_max_memory_mb"`
+	MaxRunTime  int `yaml:"wasm_max_run_time"`
+}
+
+// Check runs a check module in its own WebAssembly runtime
+type Check struct {
+	core.CheckBase
+	path string
+	code []byte
+
+	maxMemoryMB int
+	maxRunTime  time.Duration
+	instance    []byte
+	initConfig  []byte
+
+	// mu protects the runtime, which is closed when the check is cancelled
+	mu        sync.Mutex
+	runtime   wazero.Runtime
+	module    api.Module
+	run       api.Function
+	sender    sender.Sender
+	cancelled bool
+
+	cancelLock sync.Mutex
+	cancelRun  context.CancelFunc
+}
+
+// NewCheck returns a check running the given module
+func NewCheck(name string, path string, code []byte) *Check {
+	return &Check{
+		CheckBase: core.NewCheckBase(name),
+		path:      path,
+		code:      code,
+	}
+}
+
+// Configure configures the check instance and instantiates its module
+func (c *Check) Configure(senderManager sender.SenderManager, integrationConfigDigest uint64, data integration.Data, initConfig integration.Data, source string) error {
+	c.BuildID(integrationConfigDigest, data, initConfig)
+	if err := c.CommonConfigure(senderManager, initConfig, data, source); err != nil {
+		return err
+	}
+
+	limits := limitsConfig{
+		MaxMemoryMB: pkgconfigsetup.Datadog().GetInt("wasm_checks.max_memory_mb"),
+		MaxRunTime:  pkgconfigsetup.Datadog().GetInt("wasm_checks.max_run_time"),
+	}
+	if err := yaml.Unmarshal(data, &limits); err != nil {
+		return err
+	}
+	if limits.MaxMemoryMB <= 0 || limits.MaxMemoryMB*1024*1024/wasmPageSize > maxMemoryPages {
+		return fmt.Errorf("invalid wasm_max_memory_mb %d, it must be between 1 and %d", limits.MaxMemoryMB, maxMemoryPages*wasmPageSize/1024/1024)
+	}
+	if limits.MaxRunTime <= 0 {
+		return fmt.Errorf("invalid wasm_max_run_time %d, it must be positive", limits.MaxRunTime)
+	}
+	c.maxMemoryMB = limits.MaxMemoryMB
+	c.maxRunTime = time.Duration(limits.MaxRunTime) * time.Second
+
+	var err error
+	if c.instance, err = configJSON(data); err != nil {
+		return fmt.Errorf("invalid instance configuration: %v", err)
+	}
+	if c.initConfig, err = configJSON(initConfig); err != nil {
+		return fmt.Errorf("invalid init_config configuration: %v", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.instantiate()
+}
+
+// instantiate creates the runtime of the check and instantiates its module, it is
+// called with mu held.
+func (c *Check) instantiate() error {
+	s, err := c.GetSender()
+	if err != nil {
+		return fmt.Errorf("failed to retrieve a sender: %v", err)
+	}
+	c.sender = s
+
+	runtimeConfig := wazero.NewRuntimeConfig().
+		WithCompilationCache(compilationCache).
+		WithMemoryLimitPages(uint32(c.maxMemoryMB * 1024 * 1024 / wasmPageSize)).
+		WithCloseOnContextDone(true)
+	r := wazero.NewRuntimeWithConfig(context.Background(), runtimeConfig)
+
+	module, err := c.instantiateModule(r)
+	if err != nil {
+		r.Close(context.Background())
+		return err
+	}
+	run := module.ExportedFunction("run")
+	if run == nil {
+		r.Close(context.Background())
+		return fmt.Errorf("module %s doesn't export a run function", c.path)
+	}
+	if module.ExportedMemory(memoryExport) == nil {
+		r.Close(context.Background())
+		return fmt.Errorf("module %s doesn't export a memory", c.path)
+	}
+
+	c.runtime = r
+	c.module = module
+	c.run = run
+	return nil
+}
+
+func (c *Check) instantiateModule(r wazero.Runtime) (api.Module, error) {
+	if _, err := wasi_snapshot_preview1.Instantiate(context.Background(), r); err != nil {
+		return nil, err
+	}
+	if err := c.instantiateHostModule(context.Background(), r); err != nil {
+		return nil, err
+	}
+	compiled, err := r.CompileModule(context.Background(), c.code)
+	if err != nil {
+		return nil, fmt.Errorf("could not compile module %s: %v", c.path, err)
+	}
+
+	moduleConfig := wazero.NewModuleConfig().
+		WithName("check").
+		WithStartFunctions("_initialize").
+		WithStdout(&logWriter{check: c, level: logLevelInfo}).
+		WithStderr(&logWriter{check: c, level: logLevelWarning}).
+		WithSysWalltime().
+		WithSysNanotime().
+		WithSysNanosleep().
+		WithRandSource(rand.Reader)
+	// the initialization of the module is limited like its runs, unlike its compilation
+	ctx, cancel := context.WithTimeout(context.Background(), c.maxRunTime)
+	defer cancel()
+	module, err := r.InstantiateModule(ctx, compiled, moduleConfig)
+	if err != nil {
+		return nil, fmt.Errorf("could not instantiate module %s: %v", c.path, err)
+	}
+	return module, nil
+}
+
+// close closes the runtime of the check, it is called with mu held.
+func (c *Check) close() {
+	if c.runtime == nil {
+		return
+	}
+	if err := c.runtime.Close(context.Background()); err != nil {
+		log.Debugf("Could not close the runtime of check %s: %v", c.ID(), err)
+	}
+	c.runtime = nil
+	c.module = nil
+	c.run = nil
+}
+
+// Run calls the run function of the module. The module can't be used anymore after
+// a failed call, it is instantiated again at the next run.
+func (c *Check) Run() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.cancelled {
+		return errors.New("check was cancelled")
+	}
+	if c.module == nil {
+		if err := c.instantiate(); err != nil {
+			return err
+		}
+	}
+	s, err := c.GetSender()
+	if err != nil {
+		return fmt.Errorf("failed to retrieve a sender: %v", err)
+	}
+	c.sender = s
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.maxRunTime)
+	defer cancel()
+	c.cancelLock.Lock()
+	c.cancelRun = cancel
+	c.cancelLock.Unlock()
+
+	results, err := c.run.Call(ctx)
+	if err != nil {
+		var memorySize uint32
+		if memory := c.module.ExportedMemory(memoryExport); memory != nil {
+			memorySize = memory.Size()
+		}
+		c.close()
+		switch {
+		case errors.Is(ctx.Err(), context.DeadlineExceeded):
+			return fmt.Errorf("check run was interrupted after %s", c.maxRunTime)
+		case ctx.Err() != nil:
+			return errors.New("check run was stopped")
+		case uint64(memorySize) >= uint64(c.maxMemoryMB)*1024*1024*9/10:
+			// the module likely couldn't grow its memory anymore
+			return fmt.Errorf("check run failed with its memory close to its limit of %d MiB: %v", c.maxMemoryMB, err)
+		}
+		return fmt.Errorf("check run failed: %v", err)
+	}
+
+	s.Commit()
+	if len(results) == 1 && api.DecodeI32(results[0]) != 0 {
+		return fmt.Errorf("check run returned %d", api.DecodeI32(results[0]))
+	}
+	return nil
+}
+
+// Stop interrupts the current run of the check
+func (c *Check) Stop() {
+	c.cancelLock.Lock()
+	defer c.cancelLock.Unlock()
+	if c.cancelRun != nil {
+		c.cancelRun()
+	}
+}
+
+// Cancel closes the runtime of the check
+func (c *Check) Cancel() {
+	c.Stop()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cancelled = true
+	c.close()
+}
+
+// configJSON converts a configuration to JSON
+func configJSON(data integration.Data) ([]byte, error) {
+	var config interface{}
+	if err := yamlv3.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+	if config == nil {
+		config = map[string]interface{}{}
+	}
+	return json.Marshal(config)
+}
+
+// logWriter logs the output of a module
+type logWriter struct {
+	check *Check
+	level int32
+}
+
+func (w *logWriter) Write(p []byte) (int, error) {
+	if message := strings.TrimRight(string(p), "\n"); message != "" {
+		w.check.log(w.level, message)
+	}
+	return len(p), nil
+}
@@ -0,0 +1,193 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package wasm
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
+	configmock "github.com/DataDog/datadog-agent/pkg/config/mock"
+	"github.com/DataDog/datadog-agent/pkg/metrics/event"
+	"github.com/DataDog/datadog-agent/pkg/metrics/servicecheck"
+)
+
+var checksDir string
+
+func TestMain(m *testing.M) {
+	var err error
+	checksDir, err = os.MkdirTemp("", "wasm-checks")
+	if err != nil {
+		fmt.Printf("Could not create the checks directory: %s", err)
+		os.Exit(1)
+	}
+
+	cmd := exec.Command("go", "build", "-buildmode=c-shared", "-o", filepath.Join(checksDir, "sample"+moduleExtension), "./testdata/sample")
+	cmd.Env = append(os.Environ(), "GOOS=wasip1", "GOARCH=wasm")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		fmt.Printf("Could not compile the sample check module: %s\n%s", err, out)
+		os.RemoveAll(checksDir)
+		os.Exit(1)
+	}
+
+	code := m.Run()
+	os.RemoveAll(checksDir)
+	os.Exit(code)
+}
+
+func loadSample(t *testing.T, instance string) (*Check, *mocksender.MockSender) {
+	cfg := configmock.New(t)
+	cfg.SetWithoutSource("additional_checksd", checksDir)
+
+	mockSender := mocksender.NewMockSender("")
+	loader, err := NewLoader()
+	require.NoError(t, err)
+	c, err := loader.Load(mockSender.GetSenderManager(), integration.Config{Name: "sample"}, integration.Data(instance))
+	require.NoError(t, err)
+	t.Cleanup(c.Cancel)
+
+	mocksender.SetSender(mockSender, c.ID())
+	return c.(*Check), mockSender
+}
+
+func TestLoadMissingModule(t *testing.T) {
+	cfg := configmock.New(t)
+	cfg.SetWithoutSource("additional_checksd", checksDir)
+
+	loader, err := NewLoader()
+	require.NoError(t, err)
+	_, err = loader.Load(mocksender.NewMockSender("").GetSenderManager(), integration.Config{Name: "missing"}, nil)
+	assert.ErrorContains(t, err, "not found")
+}
+
+func TestLoadModuleWithoutMemory(t *testing.T) {
+	cfg := configmock.New(t)
+	cfg.SetWithoutSource("additional_checksd", checksDir)
+
+	// a module exporting a run function returning 0, without memory
+	code := []byte{
+		0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00, // header
+		0x01, 0x05, 0x01, 0x60, 0x00, 0x01, 0x7f, // type section: () -> i32
+		0x03, 0x02, 0x01, 0x00, // function section
+		0x07, 0x07, 0x01, 0x03, 'r', 'u', 'n', 0x00, 0x00, // export section
+		0x0a, 0x06, 0x01, 0x04, 0x00, 0x41, 0x00, 0x0b, // code section
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(checksDir, "nomemory"+moduleExtension), code, 0o644))
+
+	loader, err := NewLoader()
+	require.NoError(t, err)
+	_, err = loader.Load(mocksender.NewMockSender("").GetSenderManager(), integration.Config{Name: "nomemory"}, nil)
+	assert.ErrorContains(t, err, "doesn't export a memory")
+}
+
+func TestRun(t *testing.T) {
+	c, mockSender := loadSample(t, "mode: metrics")
+	mockSender.SetupAcceptAll()
+
+	require.NoError(t, c.Run())
+	mockSender.AssertMetric(t, "Gauge", "sample.gauge", 1, "", []string{"foo:bar", "baz"})
+	mockSender.AssertMetric(t, "Count", "sample.count", 2, "host", nil)
+	mockSender.AssertMetric(t, "Distribution", "sample.distribution", 3, "", nil)
+	mockSender.AssertServiceCheck(t, "sample.can_connect", servicecheck.ServiceCheckWarning, "", []string{"foo:bar"}, "warning message")
+	mockSender.AssertEvent(t, event.Event{
+		Title:     "title",
+		Text:      "text",
+		AlertType: event.AlertTypeWarning,
+		Tags:      []string{"foo:bar"},
+	}, 0)
+	mockSender.AssertNumberOfCalls(t, "Commit", 1)
+
+	warnings := c.GetWarnings()
+	require.Len(t, warnings, 1)
+	assert.Equal(t, "instance warning", warnings[0].Error())
+
+	// the state of the module is kept between the runs
+	require.NoError(t, c.Run())
+	mockSender.AssertMetric(t, "Gauge", "sample.gauge", 2, "", []string{"foo:bar", "baz"})
+}
+
+func TestRunFailure(t *testing.T) {
+	c, mockSender := loadSample(t, "mode: fail")
+	mockSender.SetupAcceptAll()
+
+	assert.EqualError(t, c.Run(), "check run returned 1")
+	mockSender.AssertNumberOfCalls(t, "Commit", 1)
+}
+
+func TestRunInvalidMemoryAccess(t *testing.T) {
+	c, mockSender := loadSample(t, "mode: trap")
+	mockSender.SetupAcceptAll()
+
+	err := c.Run()
+	assert.ErrorContains(t, err, "out of range memory access")
+	mockSender.AssertNotCalled(t, "Commit")
+
+	// the module is instantiated again at the next run
+	assert.Nil(t, c.module)
+	assert.Error(t, c.Run())
+}
+
+func TestRunTimeLimit(t *testing.T) {
+	c, mockSender := loadSample(t, "mode: loop\nwasm_max_run_time: 1")
+	mockSender.SetupAcceptAll()
+
+	assert.EqualError(t, c.Run(), "check run was interrupted after 1s")
+	mockSender.AssertNotCalled(t, "Commit")
+}
+
+func TestRunMemoryLimit(t *testing.T) {
+	c, mockSender := loadSample(t, "mode: memory\nwasm_max_memory_mb: 32")
+	mockSender.SetupAcceptAll()
+
+	assert.ErrorContains(t, c.Run(), "memory close to its limit of 32 MiB")
+}
+
+func TestStop(t *testing.T) {
+	c, mockSender := loadSample(t, "mode: loop")
+	mockSender.SetupAcceptAll()
+
+	errs := make(chan error)
+	go func() {
+		errs <- c.Run()
+	}()
+	assert.Eventually(t, func() bool {
+		c.cancelLock.Lock()
+		defer c.cancelLock.Unlock()
+		return c.cancelRun != nil
+	}, 5*time.Second, 10*time.Millisecond)
+	c.Stop()
+	assert.EqualError(t, <-errs, "check run was stopped")
+}
+
+func TestInvalidLimits(t *testing.T) {
+	cfg := configmock.New(t)
+	cfg.SetWithoutSource("additional_checksd", checksDir)
+
+	loader, err := NewLoader()
+	require.NoError(t, err)
+	for _, instance := range []string{"wasm_max_memory_mb: 0", "wasm_max_memory_mb: 5000", "wasm_max_run_time: -1"} {
+		_, err = loader.Load(mocksender.NewMockSender("").GetSenderManager(), integration.Config{Name: "sample"}, integration.Data(instance))
+		assert.Error(t, err, instance)
+	}
+}
+
+func TestConfigJSON(t *testing.T) {
+	data, err := configJSON(integration.Data("host: localhost\nport: 8080\ntags:\n  - foo:bar\n"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"host":"localhost","port":8080,"tags":["foo:bar"]}`, string(data))
+
+	data, err = configJSON(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "{}", string(data))
+}
@@ -0,0 +1,159 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package wasm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+
+	"github.com/DataDog/datadog-agent/pkg/metrics/event"
+	"github.com/DataDog/datadog-agent/pkg/metrics/servicecheck"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// hostModuleName is the name of the module providing the functions of the agent to the check modules
+const hostModuleName = "datadog"
+
+// memoryExport is the name of the memory exported by the check modules, through which the host
+// functions exchange their arguments
+const memoryExport = "memory"
+
+// Log levels of the log function
+const (
+	logLevelDebug int32 = iota
+	logLevelInfo
+	logLevelWarning
+	logLevelError
+)
+
+// metricFunc is the signature of the host functions submitting metrics
+type metricFunc func(ctx context.Context, m api.Module, namePtr, nameLen uint32, value float64, hostnamePtr, hostnameLen, tagsPtr, tagsLen uint32)
+
+// instantiateHostModule instantiates the host module in the runtime of the check
+func (c *Check) instantiateHostModule(ctx context.Context, r wazero.Runtime) error {
+	_, err := r.NewHostModuleBuilder(hostModuleName).
+		NewFunctionBuilder().WithFunc(c.metricFunc(func(name string, value float64, hostname string, tags []string) {
+		c.sender.Gauge(name, value, hostname, tags)
+	})).Export("gauge").
+		NewFunctionBuilder().WithFunc(c.metricFunc(func(name string, value float64, hostname string, tags []string) {
+		c.sender.Count(name, value, hostname, tags)
+	})).Export("count").
+		NewFunctionBuilder().WithFunc(c.metricFunc(func(name string, value float64, hostname string, tags []string) {
+		c.sender.Distribution(name, value, hostname, tags)
+	})).Export("distribution").
+		NewFunctionBuilder().WithFunc(c.serviceCheck).Export("service_check").
+		NewFunctionBuilder().WithFunc(c.event).Export("event").
+		NewFunctionBuilder().WithFunc(c.logFunc).Export("log").
+		NewFunctionBuilder().WithFunc(c.warning).Export("warning").
+		NewFunctionBuilder().WithFunc(func(_ context.Context, m api.Module, bufPtr, bufLen uint32) uint32 {
+		return writeBuffer(m, bufPtr, bufLen, c.instance)
+	}).Export("get_instance").
+		NewFunctionBuilder().WithFunc(func(_ context.Context, m api.Module, bufPtr, bufLen uint32) uint32 {
+		return writeBuffer(m, bufPtr, bufLen, c.initConfig)
+	}).Export("get_init_config").
+		Instantiate(ctx)
+	return err
+}
+
+func (c *Check) metricFunc(submit func(name string, value float64, hostname string, tags []string)) metricFunc {
+	return func(_ context.Context, m api.Module, namePtr, nameLen uint32, value float64, hostnamePtr, hostnameLen, tagsPtr, tagsLen uint32) {
+		submit(readString(m, namePtr, nameLen), value, readString(m, hostnamePtr, hostnameLen), readTags(m, tagsPtr, tagsLen))
+	}
+}
+
+func (c *Check) serviceCheck(_ context.Context, m api.Module, namePtr, nameLen uint32, status int32, hostnamePtr, hostnameLen, tagsPtr, tagsLen, messagePtr, messageLen uint32) {
+	name := readString(m, namePtr, nameLen)
+	if status < int32(servicecheck.ServiceCheckOK) || status > int32(servicecheck.ServiceCheckUnknown) {
+		c.Warnf("Invalid status %d of service check %s", status, name) //nolint:errcheck
+		return
+	}
+	c.sender.ServiceCheck(name, servicecheck.ServiceCheckStatus(status), readString(m, hostnamePtr, hostnameLen), readTags(m, tagsPtr, tagsLen), readString(m, messagePtr, messageLen))
+}
+
+func (c *Check) event(_ context.Context, m api.Module, eventPtr, eventLen uint32) {
+	var e event.Event
+	if err := json.Unmarshal(readBytes(m, eventPtr, eventLen), &e); err != nil {
+		c.Warnf("Invalid event: %v", err) //nolint:errcheck
+		return
+	}
+	c.sender.Event(e)
+}
+
+func (c *Check) logFunc(_ context.Context, m api.Module, level int32, messagePtr, messageLen uint32) {
+	c.log(level, readString(m, messagePtr, messageLen))
+}
+
+func (c *Check) log(level int32, message string) {
+	message = fmt.Sprintf("check %s: %s", c.ID(), message)
+	switch level {
+	case logLevelDebug:
+		log.Debug(message)
+	case logLevelInfo:
+		log.Info(message)
+	case logLevelWarning:
+		log.Warn(message)
+	default:
+		log.Error(message)
+	}
+}
+
+func (c *Check) warning(_ context.Context, m api.Module, messagePtr, messageLen uint32) {
+	c.Warn(readString(m, messagePtr, messageLen)) //nolint:errcheck
+}
+
+// moduleMemory returns the exported memory of a module. A module without memory makes the
+// call of the host function fail.
+func moduleMemory(m api.Module) api.Memory {
+	// Memory() returns a non-nil interface even when the module has no memory
+	memory := m.ExportedMemory(memoryExport)
+	if memory == nil {
+		panic(errors.New("module doesn't export a memory"))
+	}
+	return memory
+}
+
+// readBytes returns a copy of a range of the memory of a module. An invalid range makes
+// the call of the host function fail.
+func readBytes(m api.Module, ptr, length uint32) []byte {
+	if length == 0 {
+		return nil
+	}
+	buf, ok := moduleMemory(m).Read(ptr, length)
+	if !ok {
+		panic(fmt.Errorf("out of range memory access of %d bytes at offset %d", length, ptr))
+	}
+	return append([]byte(nil), buf...)
+}
+
+func readString(m api.Module, ptr, length uint32) string {
+	return string(readBytes(m, ptr, length))
+}
+
+// readTags returns the comma separated tags of a range of the memory of a module
+func readTags(m api.Module, ptr, length uint32) []string {
+	var tags []string
+	for _, tag := range strings.Split(readString(m, ptr, length), ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// writeBuffer writes data to a buffer of a module if it's large enough, and returns the
+// size of the data.
+func writeBuffer(m api.Module, bufPtr, bufLen uint32, data []byte) uint32 {
+	if bufLen >= uint32(len(data)) && !moduleMemory(m).Write(bufPtr, data) {
+		panic(fmt.Errorf("out of range memory access of %d bytes at offset %d", len(data), bufPtr))
+	}
+	return uint32(len(data))
+}
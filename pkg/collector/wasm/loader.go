@@ -0,0 +1,102 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+// Package wasm implements a loader for the checks compiled to WebAssembly modules,
+// which run in a sandboxed runtime embedded in the agent.
+//
+// A check module is a `<check name>.wasm` file of the `additional_checksd` directory.
+// It exports its memory and a `run` function, without parameters and returning an
+// i32, called at each run of the check: a non-zero result fails the run. An optional
+// `_initialize` function is called once, when the check instance is configured.
+//
+// The modules submit their data through the functions of the `datadog` host module,
+// which mirror the sender methods. The strings are passed as a pointer and a length
+// in the memory of the module, the tags are joined with commas:
+//
+//	gauge(name, name_len, value f64, hostname, hostname_len, tags, tags_len)
+//	count(name, name_len, value f64, hostname, hostname_len, tags, tags_len)
+//	distribution(name, name_len, value f64, hostname, hostname_len, tags, tags_len)
+//	service_check(name, name_len, status, hostname, hostname_len, tags, tags_len, message, message_len)
+//	event(event, event_len)  the event is a JSON object, as sent by the agent to the intake
+//	log(level, message, message_len)  with level 0: debug, 1: info, 2: warning, 3: error
+//	warning(message, message_len)  reports a warning of the check on the status page
+//	get_instance(buf, buf_len) -> i32
+//	get_init_config(buf, buf_len) -> i32
+//
+// The configuration functions write the instance or init_config configuration, as
+// JSON, to the buffer when it's large enough, and return its length.
+//
+// Modules targeting WASI can use its clocks and random source, they have no access to
+// the filesystem, the network or the environment of the agent. Their standard output
+// and error are logged. The memory of each instance is limited to `wasm_max_memory_mb`
+// and a run is interrupted after `wasm_max_run_time` seconds, both defaulting to the
+// `wasm_checks` settings.
+package wasm
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	tagger "github.com/DataDog/datadog-agent/comp/core/tagger/def"
+	integrations "github.com/DataDog/datadog-agent/comp/logs/integrations/def"
+	"github.com/DataDog/datadog-agent/pkg/aggregator/sender"
+	"github.com/DataDog/datadog-agent/pkg/collector/check"
+	"github.com/DataDog/datadog-agent/pkg/collector/loaders"
+	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+	"github.com/DataDog/datadog-agent/pkg/util/optional"
+)
+
+// moduleExtension is the extension of the check modules
+const moduleExtension = ".wasm"
+
+func init() {
+	factory := func(sender.SenderManager, optional.Option[integrations.Component], tagger.Component) (check.Loader, error) {
+		return NewLoader()
+	}
+
+	// the modules are looked up before the Python and Go checks, like the Python checks
+	// of additional_checksd are looked up before the Go checks
+	loaders.RegisterLoader(10, factory)
+}
+
+// Loader is a specific loader for checks compiled to WebAssembly modules
+type Loader struct{}
+
+// NewLoader creates a loader for WebAssembly checks
+func NewLoader() (*Loader, error) {
+	return &Loader{}, nil
+}
+
+// Name returns the WebAssembly loader name
+func (l *Loader) Name() string {
+	return "wasm"
+}
+
+// Load returns a check running the module named after the check
+func (l *Loader) Load(senderManager sender.SenderManager, config integration.Config, instance integration.Data) (check.Check, error) {
+	path := filepath.Join(pkgconfigsetup.Datadog().GetString("additional_checksd"), config.Name+moduleExtension)
+	code, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("module %s not found", path)
+		}
+		return nil, fmt.Errorf("could not read module %s: %v", path, err)
+	}
+
+	c := NewCheck(config.Name, path, code)
+	if err := c.Configure(senderManager, config.FastDigest(), instance, config.InitConfig, config.Source); err != nil {
+		log.Errorf("wasm.loader: could not configure check %s: %s", c, err)
+		return nil, fmt.Errorf("could not configure check %s: %s", c, err)
+	}
+	return c, nil
+}
+
+func (l *Loader) String() string {
+	return "WebAssembly Check Loader"
+}
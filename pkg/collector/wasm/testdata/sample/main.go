@@ -0,0 +1,111 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+//go:build wasip1
+
+// Package main is a sample check module, built with
+// GOOS=wasip1 GOARCH=wasm go build -buildmode=c-shared -o sample.wasm
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"unsafe"
+)
+
+//go:wasmimport datadog gauge
+func gauge(name unsafe.Pointer, nameLen uint32, value float64, hostname unsafe.Pointer, hostnameLen uint32, tags unsafe.Pointer, tagsLen uint32)
+
+//go:wasmimport datadog count
+func count(name unsafe.Pointer, nameLen uint32, value float64, hostname unsafe.Pointer, hostnameLen uint32, tags unsafe.Pointer, tagsLen uint32)
+
+//go:wasmimport datadog distribution
+func distribution(name unsafe.Pointer, nameLen uint32, value float64, hostname unsafe.Pointer, hostnameLen uint32, tags unsafe.Pointer, tagsLen uint32)
+
+//go:wasmimport datadog service_check
+func serviceCheck(name unsafe.Pointer, nameLen uint32, status int32, hostname unsafe.Pointer, hostnameLen uint32, tags unsafe.Pointer, tagsLen uint32, message unsafe.Pointer, messageLen uint32)
+
+//go:wasmimport datadog event
+func event(event unsafe.Pointer, eventLen uint32)
+
+//go:wasmimport datadog log
+func log(level int32, message unsafe.Pointer, messageLen uint32)
+
+//go:wasmimport datadog warning
+func warning(message unsafe.Pointer, messageLen uint32)
+
+//go:wasmimport datadog get_instance
+func getInstance(buf unsafe.Pointer, bufLen uint32) uint32
+
+func str(s string) (unsafe.Pointer, uint32) {
+	return unsafe.Pointer(unsafe.StringData(s)), uint32(len(s))
+}
+
+type instance struct {
+	Mode string `json:"mode"`
+}
+
+var runs float64
+
+func readInstance() instance {
+	buf := make([]byte, getInstance(nil, 0))
+	getInstance(unsafe.Pointer(unsafe.SliceData(buf)), uint32(len(buf)))
+	var inst instance
+	if err := json.Unmarshal(buf, &inst); err != nil {
+		panic(err)
+	}
+	return inst
+}
+
+func submit(fn func(unsafe.Pointer, uint32, float64, unsafe.Pointer, uint32, unsafe.Pointer, uint32), name string, value float64, hostname string, tags string) {
+	namePtr, nameLen := str(name)
+	hostnamePtr, hostnameLen := str(hostname)
+	tagsPtr, tagsLen := str(tags)
+	fn(namePtr, nameLen, value, hostnamePtr, hostnameLen, tagsPtr, tagsLen)
+}
+
+//go:wasmexport run
+func run() int32 {
+	runs++
+	switch readInstance().Mode {
+	case "loop":
+		for {
+		}
+	case "memory":
+		var chunks [][]byte
+		for {
+			chunks = append(chunks, make([]byte, 1<<20))
+		}
+	case "fail":
+		msg, msgLen := str("something went wrong")
+		log(3, msg, msgLen)
+		return 1
+	case "trap":
+		gauge(unsafe.Pointer(uintptr(0xfffffff0)), 64, 1, nil, 0, nil, 0)
+		return 0
+	}
+
+	submit(gauge, "sample.gauge", runs, "", "foo:bar,baz")
+	submit(count, "sample.count", 2, "host", "")
+	submit(distribution, "sample.distribution", 3, "", "")
+
+	name, nameLen := str("sample.can_connect")
+	hostname, hostnameLen := str("")
+	tags, tagsLen := str("foo:bar")
+	message, messageLen := str("warning message")
+	serviceCheck(name, nameLen, 1, hostname, hostnameLen, tags, tagsLen, message, messageLen)
+
+	ev, evLen := str(`{"msg_title":"title","msg_text":"text","alert_type":"warning","tags":["foo:bar"]}`)
+	event(ev, evLen)
+
+	msg, msgLen := str("instance warning")
+	warning(msg, msgLen)
+
+	fmt.Fprintln(os.Stdout, "written to stdout")
+	return 0
+}
+
+func main() {}
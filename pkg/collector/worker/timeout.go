@@ -0,0 +1,146 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package worker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/collector/check"
+	checkid "github.com/DataDog/datadog-agent/pkg/collector/check/id"
+	"github.com/DataDog/datadog-agent/pkg/collector/check/stats"
+)
+
+const (
+	// maxBackoffExponent caps the number of runs skipped after consecutive timeouts to 2^5-1
+	maxBackoffExponent = 5
+)
+
+// cancellationGracePeriod is how long a check implementing check.ContextRunner is given
+// to return once its run was cancelled, before the worker stops waiting for it.
+var cancellationGracePeriod = 5 * time.Second
+
+// timeouts tracks the consecutive timeouts of the checks. It's shared by all the workers
+// since the runs of a check can be picked by any of them.
+var timeouts = newTimeoutBackoff()
+
+// ForgetCheck drops the timeout backoff of an unscheduled check, so that the backoffs
+// don't accumulate and a check scheduled again doesn't inherit the backoff of its
+// previous instance.
+func ForgetCheck(id checkid.ID) {
+	timeouts.completed(id)
+}
+
+// checkTimeout returns the timeout of the check, 0 if its runs aren't bounded
+func checkTimeout(c check.Check) time.Duration {
+	if timeoutCheck, ok := c.(check.TimeoutCheck); ok {
+		return timeoutCheck.Timeout()
+	}
+	return 0
+}
+
+// runCheck runs the check, bounding the run by the timeout of the check if it has one.
+//
+// When the timeout is exceeded, the checks implementing check.ContextRunner see their
+// context cancelled and get a grace period to return. A check that doesn't return in
+// time is abandoned: the run is reported as timed out and the error of the run is
+// eventually sent on the returned channel, which is nil if the run completed.
+func runCheck(c check.Check, timeout time.Duration) (timedOut bool, pending <-chan error, err error) {
+	if timeout <= 0 {
+		return false, nil, c.Run()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	contextRunner, isContextRunner := c.(check.ContextRunner)
+	done := make(chan error, 1)
+	go func() {
+		defer cancel()
+		if isContextRunner {
+			done <- contextRunner.RunContext(ctx)
+		} else {
+			done <- c.Run()
+		}
+	}()
+
+	select {
+	case err := <-done:
+		return false, nil, err
+	case <-ctx.Done():
+	}
+
+	timeoutErr := fmt.Errorf("%w after %s", stats.ErrTimeout, timeout)
+	select {
+	case err := <-done:
+		// the run completed right when the timeout expired
+		return false, nil, err
+	default:
+	}
+	if isContextRunner {
+		select {
+		case <-done:
+			return true, nil, timeoutErr
+		case <-time.After(cancellationGracePeriod):
+		}
+	}
+	return true, done, timeoutErr
+}
+
+// timeoutBackoff makes the checks that time out repeatedly skip some of their runs:
+// after the nth consecutive timeout of a check, its next 2^(n-1)-1 runs are skipped.
+type timeoutBackoff struct {
+	m      sync.Mutex
+	checks map[checkid.ID]*backoffState
+}
+
+type backoffState struct {
+	timeouts int
+	skip     int
+}
+
+func newTimeoutBackoff() *timeoutBackoff {
+	return &timeoutBackoff{
+		checks: make(map[checkid.ID]*backoffState),
+	}
+}
+
+// timedOut records a timeout of the check and returns the number of runs to skip
+func (b *timeoutBackoff) timedOut(id checkid.ID) int {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	state, found := b.checks[id]
+	if !found {
+		state = &backoffState{}
+		b.checks[id] = state
+	}
+	state.timeouts++
+	state.skip = 1<<min(state.timeouts-1, maxBackoffExponent) - 1
+	return state.skip
+}
+
+// completed resets the backoff of the check after a run that didn't time out
+func (b *timeoutBackoff) completed(id checkid.ID) {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	delete(b.checks, id)
+}
+
+// shouldSkip returns whether the run of the check must be skipped, consuming a
+// skipped run when it does
+func (b *timeoutBackoff) shouldSkip(id checkid.ID) bool {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	state, found := b.checks[id]
+	if !found || state.skip == 0 {
+		return false
+	}
+	state.skip--
+	return true
+}
@@ -0,0 +1,268 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package worker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	haagentmock "github.com/DataDog/datadog-agent/comp/haagent/mock"
+	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
+	"github.com/DataDog/datadog-agent/pkg/aggregator/sender"
+	"github.com/DataDog/datadog-agent/pkg/collector/check"
+	checkid "github.com/DataDog/datadog-agent/pkg/collector/check/id"
+	"github.com/DataDog/datadog-agent/pkg/collector/check/stats"
+	"github.com/DataDog/datadog-agent/pkg/collector/check/stub"
+	"github.com/DataDog/datadog-agent/pkg/collector/runner/expvars"
+	"github.com/DataDog/datadog-agent/pkg/collector/runner/tracker"
+	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
+)
+
+// blockingCheck is a check whose runs block until it's released
+type blockingCheck struct {
+	stub.StubCheck
+	id      string
+	timeout time.Duration
+	release chan struct{}
+}
+
+func (c *blockingCheck) ID() checkid.ID          { return checkid.ID(c.id) }
+func (c *blockingCheck) String() string          { return checkid.IDToCheckName(c.ID()) }
+func (c *blockingCheck) Interval() time.Duration { return time.Minute }
+func (c *blockingCheck) Timeout() time.Duration  { return c.timeout }
+
+func (c *blockingCheck) Run() error {
+	<-c.release
+	return nil
+}
+
+// contextCheck is a check whose runs block until their context is done
+type contextCheck struct {
+	blockingCheck
+}
+
+func (c *contextCheck) RunContext(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestRunCheckWithoutTimeout(t *testing.T) {
+	c := &blockingCheck{id: "blocking", release: make(chan struct{})}
+	close(c.release)
+
+	timedOut, pending, err := runCheck(c, 0)
+	assert.NoError(t, err)
+	assert.False(t, timedOut)
+	assert.Nil(t, pending)
+}
+
+func TestRunCheckCompletedBeforeTimeout(t *testing.T) {
+	c := &blockingCheck{id: "blocking", release: make(chan struct{})}
+	close(c.release)
+
+	timedOut, pending, err := runCheck(c, time.Minute)
+	assert.NoError(t, err)
+	assert.False(t, timedOut)
+	assert.Nil(t, pending)
+}
+
+func TestRunCheckAbandoned(t *testing.T) {
+	c := &blockingCheck{id: "blocking", release: make(chan struct{})}
+
+	timedOut, pending, err := runCheck(c, 10*time.Millisecond)
+	assert.True(t, timedOut)
+	assert.ErrorIs(t, err, stats.ErrTimeout)
+	assert.Equal(t, "check run timed out after 10ms", err.Error())
+	require.NotNil(t, pending)
+
+	close(c.release)
+	select {
+	case err := <-pending:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		assert.Fail(t, "the abandoned run didn't return")
+	}
+}
+
+func TestRunCheckCancelled(t *testing.T) {
+	c := &contextCheck{blockingCheck{id: "context"}}
+
+	timedOut, pending, err := runCheck(c, 10*time.Millisecond)
+	assert.True(t, timedOut)
+	assert.ErrorIs(t, err, stats.ErrTimeout)
+	assert.Nil(t, pending)
+}
+
+func TestTimeoutBackoff(t *testing.T) {
+	b := newTimeoutBackoff()
+	id := checkid.ID("check")
+
+	assert.False(t, b.shouldSkip(id))
+
+	skipped := func() int {
+		n := 0
+		for b.shouldSkip(id) {
+			n++
+		}
+		return n
+	}
+
+	for i, expected := range []int{0, 1, 3, 7, 15, 31, 31} {
+		assert.Equal(t, expected, b.timedOut(id), "timeout %d", i+1)
+		assert.Equal(t, expected, skipped(), "timeout %d", i+1)
+	}
+
+	b.timedOut(id)
+	b.timedOut(id)
+	b.completed(id)
+	assert.False(t, b.shouldSkip(id))
+	assert.Equal(t, 0, b.timedOut(id))
+}
+
+func TestWorkerTimeout(t *testing.T) {
+	expvars.Reset()
+	pkgconfigsetup.Datadog().SetWithoutSource("hostname", "myhost")
+	timeouts = newTimeoutBackoff()
+	defer func() { timeouts = newTimeoutBackoff() }()
+
+	checksTracker := tracker.NewRunningChecksTracker()
+	pendingChecksChan := make(chan check.Check, 10)
+	mockSender := mocksender.NewMockSender("")
+	mockSender.SetupAcceptAll()
+
+	worker, err := newWorkerWithOptions(
+		100,
+		200,
+		pendingChecksChan,
+		checksTracker,
+		func(checkid.ID) bool { return true },
+		func() (sender.Sender, error) { return mockSender, nil },
+		haagentmock.NewMockHaAgent(),
+		pollingInterval,
+	)
+	require.NoError(t, err)
+
+	c := &blockingCheck{id: "blocking", timeout: 10 * time.Millisecond, release: make(chan struct{})}
+	pendingChecksChan <- c
+	pendingChecksChan <- c
+	close(pendingChecksChan)
+	worker.Run()
+
+	// the first run timed out, the second one was skipped since the check was still running
+	s, found := expvars.CheckStats(c.ID())
+	require.True(t, found)
+	assert.Equal(t, uint64(1), s.TotalRuns)
+	assert.Equal(t, uint64(1), s.TotalTimeouts)
+	assert.True(t, s.LastRunTimedOut)
+	_, running := checksTracker.Check(c.ID())
+	assert.True(t, running)
+
+	close(c.release)
+	assert.Eventually(t, func() bool {
+		_, running := checksTracker.Check(c.ID())
+		return !running
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, 0, int(expvars.GetRunningCheckCount()))
+}
+
+func TestForgetCheck(t *testing.T) {
+	timeouts = newTimeoutBackoff()
+	defer func() { timeouts = newTimeoutBackoff() }()
+	id := checkid.ID("check")
+
+	timeouts.timedOut(id)
+	timeouts.timedOut(id)
+	ForgetCheck(id)
+	assert.Empty(t, timeouts.checks)
+	assert.False(t, timeouts.shouldSkip(id))
+}
+
+func TestWorkerTimeoutUnscheduled(t *testing.T) {
+	expvars.Reset()
+	pkgconfigsetup.Datadog().SetWithoutSource("hostname", "myhost")
+	timeouts = newTimeoutBackoff()
+	defer func() { timeouts = newTimeoutBackoff() }()
+
+	pendingChecksChan := make(chan check.Check, 1)
+	mockSender := mocksender.NewMockSender("")
+	mockSender.SetupAcceptAll()
+
+	// the check is unscheduled during its run
+	worker, err := newWorkerWithOptions(
+		100,
+		200,
+		pendingChecksChan,
+		tracker.NewRunningChecksTracker(),
+		func(checkid.ID) bool { return false },
+		func() (sender.Sender, error) { return mockSender, nil },
+		haagentmock.NewMockHaAgent(),
+		pollingInterval,
+	)
+	require.NoError(t, err)
+
+	c := &contextCheck{blockingCheck{id: "context", timeout: 10 * time.Millisecond}}
+	pendingChecksChan <- c
+	close(pendingChecksChan)
+	worker.Run()
+
+	assert.Empty(t, timeouts.checks)
+}
+
+func TestWorkerTimeoutReleasesDeferredCheck(t *testing.T) {
+	expvars.Reset()
+	pkgconfigsetup.Datadog().SetWithoutSource("hostname", "myhost")
+	timeouts = newTimeoutBackoff()
+	defer func() { timeouts = newTimeoutBackoff() }()
+
+	checksTracker := tracker.NewRunningChecksTracker()
+	checksTracker.SetConcurrencyLimits(map[string]int{"testing": 1})
+	pendingChecksChan := make(chan check.Check, 10)
+	mockSender := mocksender.NewMockSender("")
+	mockSender.SetupAcceptAll()
+
+	worker, err := newWorkerWithOptions(
+		100,
+		200,
+		pendingChecksChan,
+		checksTracker,
+		func(checkid.ID) bool { return true },
+		func() (sender.Sender, error) { return mockSender, nil },
+		haagentmock.NewMockHaAgent(),
+		pollingInterval,
+	)
+	require.NoError(t, err)
+	done := make(chan struct{})
+	go func() {
+		worker.Run()
+		close(done)
+	}()
+	defer func() {
+		close(pendingChecksChan)
+		<-done
+	}()
+
+	abandoned := &blockingCheck{id: "testing:1", timeout: 10 * time.Millisecond, release: make(chan struct{})}
+	deferred := newCheck(t, "testing:2", false, nil)
+	pendingChecksChan <- abandoned
+	assert.Eventually(t, func() bool {
+		s, found := expvars.CheckStats(abandoned.ID())
+		return found && s.TotalTimeouts == 1
+	}, time.Second, 10*time.Millisecond)
+
+	// the abandoned run still holds the concurrency limit
+	pendingChecksChan <- deferred
+	assert.Eventually(t, func() bool { return checksTracker.IsDeferred(deferred) }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, 0, deferred.RunCount())
+
+	// the deferred check runs once the abandoned run returns
+	close(abandoned.release)
+	assert.Eventually(t, func() bool { return deferred.RunCount() == 1 }, time.Second, 10*time.Millisecond)
+	assert.False(t, checksTracker.IsDeferred(deferred))
+}
@@ -42,6 +42,13 @@ var workerUtilization = telemetry.NewGauge(
 	"Worker utilization. It's a value between 0 and 1 that represents the share of time that the check runner worker is running checks",
 )
 
+var tlmDeferredRuns = telemetry.NewCounter(
+	"collector",
+	"deferred_check_runs",
+	[]string{"check_name"},
+	"Number of check runs deferred because the concurrency limit of the check name was reached",
+)
+
 // Worker is an object that encapsulates the logic to manage a loop of processing
 // checks over the provided `PendingCheckChan`
 type Worker struct {
@@ -55,6 +62,12 @@ type Worker struct {
 	shouldAddCheckStatsFunc func(id checkid.ID) bool
 	utilizationTickInterval time.Duration
 	haAgent                 haagent.Component
+
+	// releasedChecks receives the deferred checks whose concurrency limit was freed
+	// by the return of an abandoned run
+	releasedChecks chan check.Check
+	// stopped is closed when the worker stops processing checks
+	stopped chan struct{}
 }
 
 // NewWorker returns an instance of a `Worker` after parameter sanity checks are passed
@@ -122,6 +135,8 @@ func newWorkerWithOptions(
 		getDefaultSenderFunc:    getDefaultSenderFunc,
 		haAgent:                 haAgent,
 		utilizationTickInterval: utilizationTickInterval,
+		releasedChecks:          make(chan check.Check),
+		stopped:                 make(chan struct{}),
 	}, nil
 }
 
@@ -136,91 +151,180 @@ func (w *Worker) Run() {
 	startUtilizationUpdater(w.Name, utilizationTracker)
 	cancel := startTrackerTicker(utilizationTracker, w.utilizationTickInterval)
 	defer cancel()
+	defer close(w.stopped)
+
+	for {
+		var check check.Check
+		select {
+		case pending, ok := <-w.pendingChecksChan:
+			if !ok {
+				log.Debugf("Runner %d, worker %d: Finished processing checks.", w.runnerID, w.ID)
+				return
+			}
+			check = pending
+		case check = <-w.releasedChecks:
+		}
 
-	for check := range w.pendingChecksChan {
-		checkLogger := CheckLogger{Check: check}
-		longRunning := check.Interval() == 0
-
-		if !w.haAgent.ShouldRunIntegration(check.String()) {
-			checkLogger.Debug("Check is an HA integration and current agent is not leader, skipping execution...")
-			continue
+		// the checks deferred because of the concurrency limit of their name are
+		// run by the worker that completed a check with the same name
+		for check != nil {
+			check = w.process(check, utilizationTracker)
 		}
+	}
+}
+
+// process runs a check, and returns the next check to run: a deferred check with
+// the same name, or nil
+func (w *Worker) process(check check.Check, utilizationTracker *utilizationtracker.UtilizationTracker) check.Check {
+	checkLogger := CheckLogger{Check: check}
+	longRunning := check.Interval() == 0
 
-		// Add check to tracker if it's not already running
-		if !w.checksTracker.AddCheck(check) {
+	if !w.haAgent.ShouldRunIntegration(check.String()) {
+		checkLogger.Debug("Check is an HA integration and current agent is not leader, skipping execution...")
+		return nil
+	}
+
+	if !longRunning && timeouts.shouldSkip(check.ID()) {
+		checkLogger.Debug("Check timed out repeatedly, skipping execution...")
+		return nil
+	}
+
+	// Add check to tracker if it's not already running
+	if !w.checksTracker.AddCheck(check) {
+		if w.checksTracker.IsDeferred(check) {
+			checkLogger.Debug("Check reached the concurrency limit of its name, deferring execution...")
+			tlmDeferredRuns.Inc(check.String())
+		} else {
 			checkLogger.Debug("Check is already running, skipping execution...")
-			continue
 		}
+		return nil
+	}
 
-		checkStartTime := time.Now()
+	checkStartTime := time.Now()
 
-		checkLogger.CheckStarted()
+	checkLogger.CheckStarted()
 
-		expvars.AddRunningCheckCount(1)
-		expvars.SetRunningStats(check.ID(), checkStartTime)
+	expvars.AddRunningCheckCount(1)
+	expvars.SetRunningStats(check.ID(), checkStartTime)
 
-		utilizationTracker.Started()
+	utilizationTracker.Started()
 
-		// Run the check
-		checkErr := check.Run()
+	// Run the check
+	var timeout time.Duration
+	if !longRunning {
+		timeout = checkTimeout(check)
+	}
+	timedOut, pending, checkErr := runCheck(check, timeout)
 
-		utilizationTracker.Finished()
+	utilizationTracker.Finished()
 
+	// the backoff of a check unscheduled during its run was already dropped
+	if timedOut && w.shouldAddCheckStatsFunc(check.ID()) {
+		if skippedRuns := timeouts.timedOut(check.ID()); skippedRuns > 0 {
+			log.Warnc(fmt.Sprintf("Check timed out repeatedly, skipping its next %d runs", skippedRuns), "check", check)
+		}
+	} else if !timedOut {
+		timeouts.completed(check.ID())
+	}
+
+	if pending == nil {
 		expvars.DeleteRunningStats(check.ID())
+	}
 
-		checkWarnings := check.GetWarnings()
+	checkWarnings := check.GetWarnings()
 
-		// Use the default sender for the service checks
-		sender, err := w.getDefaultSenderFunc()
-		if err != nil {
-			log.Errorf("Error getting default sender: %v. Not sending status check for %s", err, check)
-		}
-		serviceCheckTags := []string{fmt.Sprintf("check:%s", check.String()), "dd_enable_check_intake:true"}
-		serviceCheckStatus := servicecheck.ServiceCheckOK
+	// Use the default sender for the service checks
+	sender, err := w.getDefaultSenderFunc()
+	if err != nil {
+		log.Errorf("Error getting default sender: %v. Not sending status check for %s", err, check)
+	}
+	serviceCheckTags := []string{fmt.Sprintf("check:%s", check.String()), "dd_enable_check_intake:true"}
+	serviceCheckStatus := servicecheck.ServiceCheckOK
 
-		hname, _ := hostname.Get(context.TODO())
+	hname, _ := hostname.Get(context.TODO())
 
-		if len(checkWarnings) != 0 {
-			expvars.AddWarningsCount(len(checkWarnings))
-			serviceCheckStatus = servicecheck.ServiceCheckWarning
-		}
+	if len(checkWarnings) != 0 {
+		expvars.AddWarningsCount(len(checkWarnings))
+		serviceCheckStatus = servicecheck.ServiceCheckWarning
+	}
 
-		if checkErr != nil {
-			checkLogger.Error(checkErr)
-			expvars.AddErrorsCount(1)
-			serviceCheckStatus = servicecheck.ServiceCheckCritical
-		}
+	if checkErr != nil {
+		checkLogger.Error(checkErr)
+		expvars.AddErrorsCount(1)
+		serviceCheckStatus = servicecheck.ServiceCheckCritical
+	}
 
-		if sender != nil && !longRunning {
-			if pkgconfigsetup.Datadog().GetBool("integration_check_status_enabled") {
-				sender.ServiceCheck(serviceCheckStatusKey, serviceCheckStatus, hname, serviceCheckTags, "")
-			}
-			// FIXME(remy): this `Commit()` should be part of the `if` above, we keep
-			// it here for now to make sure it's not breaking any historical behavior
-			// with the shared default sender.
-			sender.Commit()
+	if sender != nil && !longRunning {
+		if pkgconfigsetup.Datadog().GetBool("integration_check_status_enabled") {
+			sender.ServiceCheck(serviceCheckStatusKey, serviceCheckStatus, hname, serviceCheckTags, "")
 		}
+		// FIXME(remy): this `Commit()` should be part of the `if` above, we keep
+		// it here for now to make sure it's not breaking any historical behavior
+		// with the shared default sender.
+		sender.Commit()
+	}
 
+	if pending == nil {
 		// Remove the check from the running list
 		w.checksTracker.DeleteCheck(check.ID())
-
-		// Publish statistics about this run
 		expvars.AddRunningCheckCount(-1)
-		expvars.AddRunsCount(1)
-
-		if !longRunning || len(checkWarnings) != 0 || checkErr != nil {
-			// If the scheduler isn't assigned (it should), just add stats
-			// otherwise only do so if the check is in the scheduler
-			if w.shouldAddCheckStatsFunc(check.ID()) {
-				sStats, _ := check.GetSenderStats()
-				expvars.AddCheckStats(check, time.Since(checkStartTime), checkErr, checkWarnings, sStats)
-			}
+	} else {
+		// The check is still running: keep it in the running list so that it isn't
+		// run again before its abandoned run returns
+		go w.releaseAbandonedCheck(check, pending)
+	}
+
+	// Publish statistics about this run
+	expvars.AddRunsCount(1)
+
+	if !longRunning || len(checkWarnings) != 0 || checkErr != nil {
+		// If the scheduler isn't assigned (it should), just add stats
+		// otherwise only do so if the check is in the scheduler
+		if w.shouldAddCheckStatsFunc(check.ID()) {
+			sStats, _ := check.GetSenderStats()
+			expvars.AddCheckStats(check, time.Since(checkStartTime), checkErr, checkWarnings, sStats)
 		}
+	}
+
+	checkLogger.CheckFinished()
+
+	return w.nextDeferredCheck(check.String())
+}
 
-		checkLogger.CheckFinished()
+// nextDeferredCheck returns the next deferred check with the given name which is
+// still scheduled, or nil
+func (w *Worker) nextDeferredCheck(name string) check.Check {
+	for {
+		next, found := w.checksTracker.NextDeferredCheck(name)
+		if !found {
+			return nil
+		}
+		// skip the deferred checks which were unscheduled in the meantime
+		if w.shouldAddCheckStatsFunc(next.ID()) {
+			return next
+		}
 	}
+}
+
+// releaseAbandonedCheck waits for the run of a check that exceeded its timeout to
+// return, then removes it from the running list and hands the next deferred check
+// with the same name to the worker.
+func (w *Worker) releaseAbandonedCheck(c check.Check, pending <-chan error) {
+	err := <-pending
+	log.Infof("Runner %d, worker %d: abandoned run of check %s returned: %v", w.runnerID, w.ID, c.ID(), err)
+
+	expvars.DeleteRunningStats(c.ID())
+	w.checksTracker.DeleteCheck(c.ID())
+	expvars.AddRunningCheckCount(-1)
 
-	log.Debugf("Runner %d, worker %d: Finished processing checks.", w.runnerID, w.ID)
+	next := w.nextDeferredCheck(c.String())
+	if next == nil {
+		return
+	}
+	select {
+	case w.releasedChecks <- next:
+	case <-w.stopped:
+	}
 }
 
 func startUtilizationUpdater(name string, ut *utilizationtracker.UtilizationTracker) {
@@ -417,6 +417,54 @@ func TestWorkerConcurrentCheckScheduling(t *testing.T) {
 	assert.Equal(t, 0, int(expvars.GetWarningsCount()))
 }
 
+func TestWorkerConcurrencyLimit(t *testing.T) {
+	expvars.Reset()
+	pkgconfigsetup.Datadog().SetWithoutSource("hostname", "myhost")
+
+	checksTracker := tracker.NewRunningChecksTracker()
+	checksTracker.SetConcurrencyLimits(map[string]int{"testing": 1})
+	pendingChecksChan := make(chan check.Check, 10)
+	scheduled := map[checkid.ID]bool{"testing:1": true, "testing:2": true, "testing:3": true}
+	mockShouldAddStatsFunc := func(id checkid.ID) bool { return scheduled[id] }
+
+	running := newCheck(t, "testing:1", false, nil)
+	deferred := newCheck(t, "testing:2", false, nil)
+	unscheduled := newCheck(t, "testing:4", false, nil)
+	next := newCheck(t, "testing:3", false, nil)
+
+	// Make it appear as though an instance of the check is already running
+	checksTracker.AddCheck(running)
+
+	pendingChecksChan <- unscheduled
+	pendingChecksChan <- deferred
+	close(pendingChecksChan)
+
+	worker, err := NewWorker(aggregator.NewNoOpSenderManager(), haagentmock.NewMockHaAgent(), 100, 200, pendingChecksChan, checksTracker, mockShouldAddStatsFunc)
+	require.Nil(t, err)
+	worker.Run()
+
+	// the checks are deferred until an instance completes
+	assert.Equal(t, 0, deferred.RunCount())
+	assert.True(t, checksTracker.IsDeferred(deferred))
+	assert.True(t, checksTracker.IsDeferred(unscheduled))
+
+	checksTracker.DeleteCheck(running.ID())
+	pendingChecksChan = make(chan check.Check, 10)
+	pendingChecksChan <- next
+	close(pendingChecksChan)
+
+	worker, err = NewWorker(aggregator.NewNoOpSenderManager(), haagentmock.NewMockHaAgent(), 100, 200, pendingChecksChan, checksTracker, mockShouldAddStatsFunc)
+	require.Nil(t, err)
+	worker.Run()
+
+	// the deferred check ran once the other instance completed, the unscheduled one was dropped
+	assert.Equal(t, 1, next.RunCount())
+	assert.Equal(t, 1, deferred.RunCount())
+	assert.Equal(t, 0, unscheduled.RunCount())
+	assert.False(t, checksTracker.IsDeferred(unscheduled))
+	assert.Equal(t, 2, int(expvars.GetRunsCount()))
+}
+
 func TestWorkerStatsAddition(t *testing.T) {
 	expvars.Reset()
 	pkgconfigsetup.Datadog().SetWithoutSource("hostname", "myhost")
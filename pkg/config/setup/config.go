@@ -1091,6 +1091,11 @@ func agent(config pkgconfigmodel.Setup) {
 	config.BindEnvAndSetDefault("metadata_provider_stop_timeout", 30*time.Second)
 	config.BindEnvAndSetDefault("check_runners", int64(4))
 	config.BindEnvAndSetDefault("check_cancel_timeout", 500*time.Millisecond)
+	config.BindEnvAndSetDefault("check_scheduling_spread", true)
+	config.BindEnvAndSetDefault("check_concurrency_limits", map[string]int{})
+	// Resources limits of the WebAssembly checks, overridable per instance
+	config.BindEnvAndSetDefault("wasm_checks.max_memory_mb", 64)
+	config.BindEnvAndSetDefault("wasm_checks.max_run_time", 30)
 	config.BindEnvAndSetDefault("auth_token_file_path", "")
 	// used to override the path where the IPC cert/key files are stored/retrieved
 	config.BindEnvAndSetDefault("ipc_cert_file_path", "")
@@ -1260,6 +1265,8 @@ func debugging(config pkgconfigmodel.Setup) {
 	config.BindEnvAndSetDefault("tracemalloc_whitelist", "") // deprecated
 	config.BindEnvAndSetDefault("tracemalloc_blacklist", "") // deprecated
 	config.BindEnvAndSetDefault("run_path", defaultRunPath)
+	// Location of the packet captures taken with `agent network-capture --flare`, which are included in the next flare. Defaults to <run_path>/network_capture
+	config.BindEnvAndSetDefault("network_capture_path", "")
 	config.BindEnv("no_proxy_nonexact_match")
 }
 
@@ -1618,6 +1625,17 @@ func logsagent(config pkgconfigmodel.Setup) {
 	// SDS logs blocking mechanism
 	config.BindEnvAndSetDefault("logs_config.sds.wait_for_configuration", "")
 	config.BindEnvAndSetDefault("logs_config.sds.buffer_max_size", 0)
+
+	// Disk buffer used to store payloads when the intake is unreachable
+	config.BindEnvAndSetDefault("logs_config.disk_buffer.enabled", false)
+	// Defaults to a `disk_buffer` directory under `logs_config.run_path`
+	config.BindEnvAndSetDefault("logs_config.disk_buffer.path", "")
+	// Max size in bytes each pipeline can store on disk
+	config.BindEnvAndSetDefault("logs_config.disk_buffer.max_size_bytes", 256*1024*1024)
+	// Choices are 'drop_oldest' and 'block'
+	config.BindEnvAndSetDefault("logs_config.disk_buffer.eviction_policy", "drop_oldest")
+	// How long a payload waits for the destinations before being written to disk
+	config.BindEnvAndSetDefault("logs_config.disk_buffer.spill_timeout", 2*time.Second)
 }
 
 func vector(config pkgconfigmodel.Setup) {
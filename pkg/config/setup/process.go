@@ -160,6 +160,8 @@ func setupProcesses(config pkgconfigmodel.Setup) {
 		"DD_SCRUB_ARGS",
 		"DD_PROCESS_CONFIG_SCRUB_ARGS",
 		"DD_PROCESS_AGENT_SCRUB_ARGS")
+	procBindEnvAndSetDefault(config, "process_config.scrub_args_disabled_detectors", []string{})
+	config.SetKnown("process_config.custom_sensitive_arguments")
 	config.BindEnv("process_config.strip_proc_arguments",
 		"DD_STRIP_PROCESS_ARGS",
 		"DD_PROCESS_CONFIG_STRIP_PROC_ARGUMENTS",
@@ -181,6 +183,7 @@ func setupProcesses(config pkgconfigmodel.Setup) {
 	procBindEnvAndSetDefault(config, "process_config.remote_workloadmeta", false) // This flag might change. It's still being tested.
 	procBindEnvAndSetDefault(config, "process_config.disable_realtime_checks", false)
 	procBindEnvAndSetDefault(config, "process_config.ignore_zombie_processes", false)
+	procBindEnvAndSetDefault(config, "process_config.process_collection.lineage.enabled", true)
 
 	// Process Discovery Check
 	config.BindEnvAndSetDefault("process_config.process_discovery.enabled", true,
@@ -62,6 +62,14 @@ func TestProcessDefaultConfig(t *testing.T) {
 			key:          "process_config.process_collection.enabled",
 			defaultValue: false,
 		},
+		{
+			key:          "process_config.process_collection.lineage.enabled",
+			defaultValue: true,
+		},
+		{
+			key:          "process_config.scrub_args_disabled_detectors",
+			defaultValue: []string{},
+		},
 		{
 			key:          "process_config.container_collection.enabled",
 			defaultValue: true,
@@ -330,6 +338,19 @@ func TestEnvVarOverride(t *testing.T) {
 			value:    "true",
 			expected: true,
 		},
+		{
+			key:      "process_config.process_collection.lineage.enabled",
+			env:      "DD_PROCESS_CONFIG_PROCESS_COLLECTION_LINEAGE_ENABLED",
+			value:    "false",
+			expected: false,
+		},
+		{
+			key:      "process_config.scrub_args_disabled_detectors",
+			env:      "DD_PROCESS_CONFIG_SCRUB_ARGS_DISABLED_DETECTORS",
+			value:    "jwt base64_secret",
+			expType:  "stringSlice",
+			expected: []string{"jwt", "base64_secret"},
+		},
 		{
 			key:      "process_config.internal_profiling.enabled",
 			env:      "DD_PROCESS_CONFIG_INTERNAL_PROFILING_ENABLED",
@@ -253,6 +253,8 @@ func InitSystemProbeConfig(cfg pkgconfigmodel.Config) {
 	cfg.BindEnvAndSetDefault(join(smNS, "enable_kafka_monitoring"), false)
 	cfg.BindEnv(join(smNS, "enable_postgres_monitoring"))
 	cfg.BindEnv(join(smNS, "enable_redis_monitoring"))
+	cfg.BindEnv(join(smNS, "enable_memcached_monitoring"))
+	cfg.BindEnv(join(smNS, "enable_cassandra_monitoring"))
 	cfg.BindEnvAndSetDefault(join(smNS, "tls", "istio", "enabled"), true)
 	cfg.BindEnvAndSetDefault(join(smNS, "tls", "istio", "envoy_path"), defaultEnvoyPath)
 	cfg.BindEnv(join(smNS, "tls", "nodejs", "enabled"))
@@ -271,6 +273,8 @@ func InitSystemProbeConfig(cfg pkgconfigmodel.Config) {
 	cfg.BindEnv(join(smNS, "max_postgres_stats_buffered"))
 	cfg.BindEnvAndSetDefault(join(smNS, "max_postgres_telemetry_buffer"), 160)
 	cfg.BindEnv(join(smNS, "max_redis_stats_buffered"))
+	cfg.BindEnv(join(smNS, "max_memcached_stats_buffered"))
+	cfg.BindEnv(join(smNS, "max_cassandra_stats_buffered"))
 	cfg.BindEnv(join(smNS, "max_concurrent_requests"))
 	cfg.BindEnv(join(smNS, "enable_quantization"))
 	cfg.BindEnv(join(smNS, "enable_connection_rollup"))
@@ -294,6 +298,17 @@ func InitSystemProbeConfig(cfg pkgconfigmodel.Config) {
 	cfg.ParseEnvAsSliceMapString(oldHTTPRules, httpRulesTransformer(oldHTTPRules))
 	cfg.ParseEnvAsSliceMapString(newHTTPRules, httpRulesTransformer(newHTTPRules))
 
+	openAPISpecs := join(smNS, "http_openapi_specs")
+	cfg.BindEnv(openAPISpecs)
+	cfg.ParseEnvAsSlice(openAPISpecs, func(in string) []interface{} {
+		var out []interface{}
+		if err := json.Unmarshal([]byte(in), &out); err != nil {
+			log.Warnf(`%q can not be parsed: %v`, openAPISpecs, err)
+		}
+		return out
+	})
+	cfg.BindEnvAndSetDefault(join(smNS, "enable_http_openapi_annotations"), true)
+
 	// Default value (1024) is set in `adjustUSM`, to avoid having "deprecation warning", due to the default value.
 	cfg.BindEnv(join(netNS, "max_tracked_http_connections"))
 	cfg.BindEnv(join(smNS, "max_tracked_http_connections"))
@@ -308,6 +323,32 @@ func InitSystemProbeConfig(cfg pkgconfigmodel.Config) {
 	cfg.BindEnvAndSetDefault(join(netNS, "dns_recorded_query_types"), []string{})
 	// (temporary) enable submitting DNS stats by query type.
 	cfg.BindEnvAndSetDefault(join(netNS, "enable_dns_by_querytype"), false)
+	// export each DNS query and its response as an event
+	cfg.BindEnvAndSetDefault(join(netNS, "dns_events", "enabled"), false)
+	cfg.BindEnvAndSetDefault(join(netNS, "dns_events", "rate_limit"), 100)
+	cfg.BindEnvAndSetDefault(join(netNS, "dns_events", "max_buffered"), 10000)
+	cfg.BindEnvAndSetDefault(join(netNS, "dns_events", "allowed_domains"), []string{})
+	cfg.BindEnvAndSetDefault(join(netNS, "dns_events", "denied_domains"), []string{})
+	// export of the connections as IPFIX or NetFlow v9 records
+	cfg.BindEnvAndSetDefault(join(netNS, "flow_export", "enabled"), false)
+	cfg.BindEnvAndSetDefault(join(netNS, "flow_export", "collector"), "")
+	cfg.BindEnvAndSetDefault(join(netNS, "flow_export", "protocol"), "ipfix")
+	cfg.BindEnvAndSetDefault(join(netNS, "flow_export", "interval"), 30*time.Second)
+	cfg.BindEnvAndSetDefault(join(netNS, "flow_export", "observation_domain_id"), 0)
+	cfg.BindEnvAndSetDefault(join(netNS, "flow_export", "max_packet_size"), 1400)
+	cfg.BindEnvAndSetDefault(join(netNS, "flow_export", "enterprise_number"), 0)
+
+	cfg.BindEnvAndSetDefault(join(netNS, "packet_capture", "enabled"), true)
+	cfg.BindEnvAndSetDefault(join(netNS, "packet_capture", "max_duration"), 5*time.Minute)
+	cfg.BindEnvAndSetDefault(join(netNS, "packet_capture", "max_size"), 100*1024*1024)
+
+	cfg.BindEnvAndSetDefault(join(netNS, "anomaly_detection", "enabled"), false)
+	cfg.BindEnvAndSetDefault(join(netNS, "anomaly_detection", "interval"), 30*time.Second)
+	cfg.BindEnvAndSetDefault(join(netNS, "anomaly_detection", "retransmit_rate_threshold"), 0.05)
+	cfg.BindEnvAndSetDefault(join(netNS, "anomaly_detection", "min_retransmits"), 10)
+	cfg.BindEnvAndSetDefault(join(netNS, "anomaly_detection", "rtt_increase_factor"), 2.0)
+	cfg.BindEnvAndSetDefault(join(netNS, "anomaly_detection", "rtt_min_increase"), 20*time.Millisecond)
+	cfg.BindEnvAndSetDefault(join(netNS, "anomaly_detection", "max_buffered"), 1000)
 	// connection aggregation with port rollups
 	cfg.BindEnvAndSetDefault(join(netNS, "enable_connection_rollup"), false)
 
@@ -11,8 +11,10 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"syscall"
 
 	"github.com/DataDog/ebpf-manager/tracefs"
@@ -21,6 +23,7 @@ import (
 	sysconfig "github.com/DataDog/datadog-agent/cmd/system-probe/config"
 	flaretypes "github.com/DataDog/datadog-agent/comp/core/flare/types"
 	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
 )
 
 func addSystemProbePlatformSpecificEntries(fb flaretypes.FlareBuilder) {
@@ -40,9 +43,66 @@ func addSystemProbePlatformSpecificEntries(fb flaretypes.FlareBuilder) {
 		_ = fb.AddFileFromFunc(filepath.Join("system-probe", "ebpf_btf_loader.log"), getSystemProbeBTFLoaderInfo)
 		_ = fb.AddFileFromFunc(filepath.Join("system-probe", "selinux_sestatus.log"), getSystemProbeSelinuxSestatus)
 		_ = fb.AddFileFromFunc(filepath.Join("system-probe", "selinux_semodule_list.log"), getSystemProbeSelinuxSemoduleList)
+		getNetworkCaptures(fb)
 	}
 }
 
+// maxNetworkCapturesSize is the maximum total size of the network captures added to a flare
+var maxNetworkCapturesSize int64 = 100 << 20
+
+// getNetworkCaptures adds the packet captures taken for the flare with `agent network-capture --flare`, newest first
+// and up to maxNetworkCapturesSize, and removes them once the flare is created so that they're only included in one
+// flare. The captures that don't fit are left for the next flare. The scrubber would corrupt the binary pcapng files,
+// so they're added as is: the users opt into sending their packets with the --flare flag.
+func getNetworkCaptures(fb flaretypes.FlareBuilder) {
+	captureDir := pkgconfigsetup.Datadog().GetString("network_capture_path")
+	if captureDir == "" {
+		captureDir = filepath.Join(pkgconfigsetup.Datadog().GetString("run_path"), "network_capture")
+	}
+	paths, err := filepath.Glob(filepath.Join(captureDir, "*.pcapng"))
+	if err != nil {
+		fb.Logf("unable to list the network captures: %s", err) //nolint:errcheck
+		return
+	}
+	// the capture names start with their UTC timestamp
+	sort.Sort(sort.Reverse(sort.StringSlice(paths)))
+
+	var added []string
+	var totalSize int64
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			fb.Logf("unable to read the network capture %s: %s", path, err) //nolint:errcheck
+			continue
+		}
+		if totalSize+info.Size() > maxNetworkCapturesSize {
+			fb.Logf("skipping the network capture %s: the flare already contains %d bytes of captures", path, totalSize) //nolint:errcheck
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			fb.Logf("unable to read the network capture %s: %s", path, err) //nolint:errcheck
+			continue
+		}
+		if err := fb.AddFileWithoutScrubbing(filepath.Join("system-probe", "network_capture", filepath.Base(path)), data); err != nil {
+			continue
+		}
+		totalSize += int64(len(data))
+		added = append(added, path)
+	}
+
+	if len(added) == 0 {
+		return
+	}
+	fb.RegisterSaveCallback(func() {
+		for _, path := range added {
+			if err := os.Remove(path); err != nil {
+				log.Warnf("unable to remove the network capture %s: %s", path, err)
+			}
+		}
+	})
+}
+
 func getLinuxKernelSymbols(fb flaretypes.FlareBuilder) error {
 	return fb.CopyFile("/proc/kallsyms")
 }
@@ -0,0 +1,77 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+//go:build linux
+
+package flare
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	flarehelpers "github.com/DataDog/datadog-agent/comp/core/flare/helpers"
+	configmock "github.com/DataDog/datadog-agent/pkg/config/mock"
+)
+
+func TestNetworkCaptures(t *testing.T) {
+	runPath := t.TempDir()
+	captureDir := filepath.Join(runPath, "network_capture")
+	require.NoError(t, os.MkdirAll(captureDir, 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(captureDir, "network-capture-20240101T000000Z.pcapng"), []byte("mockfilecontent"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(captureDir, "notes.txt"), []byte("mockfilecontent"), 0600))
+
+	confMock := configmock.New(t)
+	confMock.SetWithoutSource("run_path", runPath)
+
+	mock := flarehelpers.NewFlareBuilderMock(t, false)
+	getNetworkCaptures(mock.Fb)
+
+	mock.AssertFileContent("mockfilecontent", "system-probe", "network_capture", "network-capture-20240101T000000Z.pcapng")
+	mock.AssertNoFileExists("system-probe", "network_capture", "notes.txt")
+
+	// the capture is only removed once the flare is created
+	require.FileExists(t, filepath.Join(captureDir, "network-capture-20240101T000000Z.pcapng"))
+	archivePath, err := mock.Fb.Save()
+	require.NoError(t, err)
+	defer os.Remove(archivePath)
+	require.NoFileExists(t, filepath.Join(captureDir, "network-capture-20240101T000000Z.pcapng"))
+	require.FileExists(t, filepath.Join(captureDir, "notes.txt"))
+}
+
+func TestNetworkCapturesMaxSize(t *testing.T) {
+	runPath := t.TempDir()
+	captureDir := filepath.Join(runPath, "network_capture")
+	require.NoError(t, os.MkdirAll(captureDir, 0700))
+	for _, name := range []string{"network-capture-20240101T000000Z.pcapng", "network-capture-20240102T000000Z.pcapng", "network-capture-20240103T000000Z.pcapng"} {
+		require.NoError(t, os.WriteFile(filepath.Join(captureDir, name), []byte("mockfilecontent"), 0600))
+	}
+
+	confMock := configmock.New(t)
+	confMock.SetWithoutSource("run_path", runPath)
+
+	defer func(size int64) { maxNetworkCapturesSize = size }(maxNetworkCapturesSize)
+	maxNetworkCapturesSize = 2 * int64(len("mockfilecontent"))
+
+	mock := flarehelpers.NewFlareBuilderMock(t, false)
+	getNetworkCaptures(mock.Fb)
+
+	// the newest captures are added first
+	mock.AssertFileContent("mockfilecontent", "system-probe", "network_capture", "network-capture-20240103T000000Z.pcapng")
+	mock.AssertFileContent("mockfilecontent", "system-probe", "network_capture", "network-capture-20240102T000000Z.pcapng")
+	mock.AssertNoFileExists("system-probe", "network_capture", "network-capture-20240101T000000Z.pcapng")
+
+	archivePath, err := mock.Fb.Save()
+	require.NoError(t, err)
+	defer os.Remove(archivePath)
+
+	// the capture that didn't fit is kept for the next flare
+	entries, err := os.ReadDir(captureDir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, "network-capture-20240101T000000Z.pcapng", entries[0].Name())
+}
@@ -0,0 +1,250 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+// Package local provides the destinations writing logs locally, to rotating files or to the standard output,
+// instead of sending them to an intake.
+package local
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/DataDog/datadog-agent/comp/logs/agent/config"
+	"github.com/DataDog/datadog-agent/pkg/logs/client"
+	"github.com/DataDog/datadog-agent/pkg/logs/message"
+	"github.com/DataDog/datadog-agent/pkg/logs/metrics"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// retryInterval is the time waited before writing a payload again after an error.
+const retryInterval = time.Second
+
+// stdoutLock prevents the destinations of the different pipelines from interleaving their logs.
+var stdoutLock sync.Mutex
+
+// lineWriter writes logs, one per line.
+type lineWriter interface {
+	writeLines(lines [][]byte) error
+	close() error
+}
+
+// Destination writes the logs of the payloads locally, one JSON object per line.
+type Destination struct {
+	target              string
+	writer              lineWriter
+	destinationsContext *client.DestinationsContext
+	shouldRetry         bool
+	retryLock           sync.Mutex
+	lastRetryError      error
+}
+
+// NewDestination returns a new destination for a local endpoint.
+func NewDestination(endpoint config.Endpoint, destinationsContext *client.DestinationsContext, shouldRetry bool) *Destination {
+	var target string
+	var writer lineWriter
+	switch endpoint.Type {
+	case config.FileEndpointType:
+		target = endpoint.Path
+		writer = newRotatingWriter(endpoint.Path, endpoint.MaxSizeBytes, time.Duration(endpoint.RotationInterval)*time.Second, endpoint.MaxFiles, endpoint.UseCompression)
+	default:
+		target = config.StdoutEndpointType
+		writer = &streamWriter{w: os.Stdout, mu: &stdoutLock}
+	}
+	metrics.DestinationLogsDropped.Set(target, &expvar.Int{})
+	return &Destination{
+		target:              target,
+		writer:              writer,
+		destinationsContext: destinationsContext,
+		shouldRetry:         shouldRetry,
+	}
+}
+
+// IsMRF returns false, local destinations are not used for Multi-Region Failover.
+func (d *Destination) IsMRF() bool {
+	return false
+}
+
+// Target is the file the logs are written to, or stdout.
+func (d *Destination) Target() string {
+	return d.target
+}
+
+// Metadata is not supported for local destinations
+func (d *Destination) Metadata() *client.DestinationMetadata {
+	return client.NewNoopDestinationMetadata()
+}
+
+// Start reads the payloads from the input and writes their logs until the input is closed.
+func (d *Destination) Start(input chan *message.Payload, output chan *message.Payload, isRetrying chan bool) (stopChan <-chan struct{}) {
+	stop := make(chan struct{})
+	go func() {
+		for payload := range input {
+			d.writeAndRetry(payload, output, isRetrying)
+		}
+		d.updateRetryState(nil, isRetrying)
+		if err := d.writer.close(); err != nil {
+			log.Warnf("Could not close logs destination %s: %v", d.target, err)
+		}
+		stop <- struct{}{}
+	}()
+	return stop
+}
+
+func (d *Destination) writeAndRetry(payload *message.Payload, output chan *message.Payload, isRetrying chan bool) {
+	lines, err := payloadLines(payload)
+	if err != nil {
+		log.Warnf("Could not decode payload for logs destination %s: %v", d.target, err)
+		d.incrementErrors(true)
+		return
+	}
+
+	for {
+		err := d.writer.writeLines(lines)
+		if err == nil {
+			break
+		}
+		log.Warnf("Could not write logs to %s: %v", d.target, err)
+		if !d.shouldRetry {
+			d.incrementErrors(true)
+			return
+		}
+		d.updateRetryState(err, isRetrying)
+		d.incrementErrors(false)
+
+		ctx := d.destinationsContext.Context()
+		if ctx == nil {
+			d.incrementErrors(true)
+			return
+		}
+		select {
+		case <-time.After(retryInterval):
+		case <-ctx.Done():
+			d.incrementErrors(true)
+			return
+		}
+	}
+
+	d.updateRetryState(nil, isRetrying)
+	output <- payload
+}
+
+func (d *Destination) incrementErrors(drop bool) {
+	if drop {
+		metrics.DestinationLogsDropped.Add(d.target, 1)
+		metrics.TlmLogsDropped.Inc(d.target)
+	}
+	metrics.DestinationErrors.Add(1)
+	metrics.TlmDestinationErrors.Inc()
+}
+
+func (d *Destination) updateRetryState(err error, isRetrying chan bool) {
+	d.retryLock.Lock()
+	defer d.retryLock.Unlock()
+
+	if err != nil {
+		if isRetrying != nil && d.lastRetryError == nil {
+			isRetrying <- true
+		}
+	} else {
+		if isRetrying != nil && d.lastRetryError != nil {
+			isRetrying <- false
+		}
+	}
+	d.lastRetryError = err
+}
+
+// payloadLines returns the logs of a payload as JSON objects.
+// The payloads replayed from the disk buffer don't hold their messages anymore, their
+// logs are decoded from the encoded payload.
+func payloadLines(payload *message.Payload) ([][]byte, error) {
+	var contents [][]byte
+	if len(payload.Messages) > 0 {
+		for _, msg := range payload.Messages {
+			contents = append(contents, msg.GetContent())
+		}
+	} else {
+		encoded := payload.Encoded
+		if payload.Encoding == "gzip" {
+			r, err := gzip.NewReader(bytes.NewReader(encoded))
+			if err != nil {
+				return nil, err
+			}
+			if encoded, err = io.ReadAll(r); err != nil {
+				return nil, err
+			}
+		}
+		encoded = bytes.TrimSpace(encoded)
+		var array []json.RawMessage
+		if len(encoded) > 0 && encoded[0] == '[' && json.Unmarshal(encoded, &array) == nil {
+			for _, content := range array {
+				contents = append(contents, content)
+			}
+		} else {
+			contents = bytes.Split(encoded, []byte{'\n'})
+		}
+	}
+
+	lines := make([][]byte, 0, len(contents))
+	for _, content := range contents {
+		line, err := jsonLine(content)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, line)
+	}
+	return lines, nil
+}
+
+// jsonLine returns a log as a single line JSON object, the logs which are not encoded
+// in JSON for the main endpoint are wrapped in a JSON object.
+func jsonLine(content []byte) ([]byte, error) {
+	trimmed := bytes.TrimSpace(content)
+	if len(trimmed) > 0 && trimmed[0] == '{' && json.Valid(trimmed) {
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, trimmed); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	err := encoder.Encode(struct {
+		Message string `json:"message"`
+	}{string(content)})
+	if err != nil {
+		return nil, fmt.Errorf("could not encode log: %v", err)
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte{'\n'}), nil
+}
+
+// streamWriter writes the logs to a stream, such as the standard output.
+type streamWriter struct {
+	mu *sync.Mutex
+	w  io.Writer
+}
+
+func (s *streamWriter) writeLines(lines [][]byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var buf bytes.Buffer
+	for _, line := range lines {
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	_, err := s.w.Write(buf.Bytes())
+	return err
+}
+
+func (s *streamWriter) close() error {
+	return nil
+}
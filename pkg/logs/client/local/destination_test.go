@@ -0,0 +1,170 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package local
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/comp/logs/agent/config"
+	"github.com/DataDog/datadog-agent/pkg/logs/client"
+	"github.com/DataDog/datadog-agent/pkg/logs/message"
+)
+
+type failingWriter struct {
+	failures int
+	lines    [][]byte
+}
+
+func (w *failingWriter) writeLines(lines [][]byte) error {
+	if w.failures > 0 {
+		w.failures--
+		return errors.New("disk full")
+	}
+	w.lines = append(w.lines, lines...)
+	return nil
+}
+
+func (w *failingWriter) close() error {
+	return nil
+}
+
+func newPayload(contents ...string) *message.Payload {
+	payload := &message.Payload{}
+	for _, content := range contents {
+		payload.Messages = append(payload.Messages, message.NewMessage([]byte(content), nil, "", 0))
+	}
+	return payload
+}
+
+func TestPayloadLines(t *testing.T) {
+	lines, err := payloadLines(newPayload(`{"message": "a"}`, "<46>0 raw log"))
+	require.NoError(t, err)
+	require.Len(t, lines, 2)
+	assert.Equal(t, `{"message":"a"}`, string(lines[0]))
+	assert.Equal(t, `{"message":"<46>0 raw log"}`, string(lines[1]))
+}
+
+func TestPayloadLinesWithoutMessages(t *testing.T) {
+	// payloads replayed from the disk buffer only hold their encoded content
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	_, err := w.Write([]byte(`[{"message":"a"},{"message":"b"}]`))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	lines, err := payloadLines(&message.Payload{Encoded: buf.Bytes(), Encoding: "gzip"})
+	require.NoError(t, err)
+	require.Len(t, lines, 2)
+	assert.Equal(t, `{"message":"a"}`, string(lines[0]))
+	assert.Equal(t, `{"message":"b"}`, string(lines[1]))
+
+	lines, err = payloadLines(&message.Payload{Encoded: []byte("raw log\n"), Encoding: "identity"})
+	require.NoError(t, err)
+	require.Len(t, lines, 1)
+	assert.Equal(t, `{"message":"raw log"}`, string(lines[0]))
+}
+
+func TestStdoutDestination(t *testing.T) {
+	var out bytes.Buffer
+	d := NewDestination(config.Endpoint{Type: config.StdoutEndpointType}, client.NewDestinationsContext(), true)
+	d.writer = &streamWriter{w: &out, mu: &sync.Mutex{}}
+	assert.Equal(t, "stdout", d.Target())
+
+	input := make(chan *message.Payload)
+	output := make(chan *message.Payload, 1)
+	stop := d.Start(input, output, nil)
+
+	payload := newPayload(`{"message":"a"}`, `{"message":"b"}`)
+	input <- payload
+	assert.Same(t, payload, <-output)
+	close(input)
+	<-stop
+
+	assert.Equal(t, "{\"message\":\"a\"}\n{\"message\":\"b\"}\n", out.String())
+}
+
+func TestDestinationRetries(t *testing.T) {
+	destinationsContext := client.NewDestinationsContext()
+	destinationsContext.Start()
+	defer destinationsContext.Stop()
+
+	writer := &failingWriter{failures: 1}
+	d := NewDestination(config.Endpoint{Type: config.StdoutEndpointType}, destinationsContext, true)
+	d.writer = writer
+
+	input := make(chan *message.Payload)
+	output := make(chan *message.Payload, 1)
+	isRetrying := make(chan bool, 2)
+	stop := d.Start(input, output, isRetrying)
+
+	payload := newPayload("a")
+	input <- payload
+	assert.True(t, <-isRetrying)
+	assert.False(t, <-isRetrying)
+	assert.Same(t, payload, <-output)
+	assert.Len(t, writer.lines, 1)
+	close(input)
+	<-stop
+}
+
+func TestUnreliableDestinationDrops(t *testing.T) {
+	writer := &failingWriter{failures: 1}
+	d := NewDestination(config.Endpoint{Type: config.StdoutEndpointType}, client.NewDestinationsContext(), false)
+	d.writer = writer
+
+	input := make(chan *message.Payload)
+	output := make(chan *message.Payload, 2)
+	stop := d.Start(input, output, nil)
+
+	input <- newPayload("a")
+	input <- newPayload("b")
+	close(input)
+	<-stop
+
+	// the first payload was dropped
+	require.Len(t, output, 1)
+	assert.Equal(t, "b", string((<-output).Messages[0].GetContent()))
+}
+
+func TestFileDestination(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "logs.json")
+	endpoint := config.Endpoint{Type: config.FileEndpointType, Path: path}
+
+	// the destinations of the pipelines share the same file
+	first := NewDestination(endpoint, client.NewDestinationsContext(), true)
+	second := NewDestination(endpoint, client.NewDestinationsContext(), true)
+	assert.Same(t, first.writer, second.writer)
+	assert.Equal(t, path, first.Target())
+
+	for _, d := range []*Destination{first, second} {
+		input := make(chan *message.Payload)
+		output := make(chan *message.Payload, 1)
+		stop := d.Start(input, output, nil)
+		input <- newPayload(`{"message":"a"}`)
+		<-output
+		close(input)
+		<-stop
+	}
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(string(content), `{"message":"a"}`+"\n"))
+
+	// the file was closed with the last destination
+	rotatingWriters.Lock()
+	assert.Empty(t, rotatingWriters.writers)
+	rotatingWriters.Unlock()
+}
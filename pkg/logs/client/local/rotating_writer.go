@@ -0,0 +1,235 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package local
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// Default rotation settings of the file endpoints
+const (
+	DefaultMaxSizeBytes = 100 * 1024 * 1024
+	DefaultMaxFiles     = 10
+)
+
+// rotatedTimeFormat is the format of the timestamp suffixed to the rotated files,
+// it sorts the rotated files from the oldest to the newest.
+const rotatedTimeFormat = "20060102T150405.000000000"
+
+// rotatingWriters holds the writers shared by the destinations of all the pipelines
+// writing to a same file.
+var rotatingWriters = struct {
+	sync.Mutex
+	writers map[string]*rotatingWriter
+}{writers: make(map[string]*rotatingWriter)}
+
+// rotatingWriter appends logs to a file which is rotated once it reaches a maximum size
+// or age. The rotated files are suffixed with their rotation time, and optionally compressed,
+// only the most recent ones are kept.
+type rotatingWriter struct {
+	mu sync.Mutex
+	// refs is the number of destinations using the writer, protected by rotatingWriters
+	refs int
+
+	path             string
+	maxSizeBytes     int64
+	rotationInterval time.Duration
+	maxFiles         int
+	compress         bool
+
+	file     *os.File
+	size     int64
+	openedAt time.Time
+	now      func() time.Time
+}
+
+// newRotatingWriter returns the writer of a file, shared with the other destinations writing to it.
+func newRotatingWriter(path string, maxSizeBytes int64, rotationInterval time.Duration, maxFiles int, compress bool) *rotatingWriter {
+	rotatingWriters.Lock()
+	defer rotatingWriters.Unlock()
+
+	if w, found := rotatingWriters.writers[path]; found {
+		w.refs++
+		return w
+	}
+	if maxSizeBytes <= 0 {
+		maxSizeBytes = DefaultMaxSizeBytes
+	}
+	if maxFiles <= 0 {
+		maxFiles = DefaultMaxFiles
+	}
+	w := &rotatingWriter{
+		refs:             1,
+		path:             path,
+		maxSizeBytes:     maxSizeBytes,
+		rotationInterval: rotationInterval,
+		maxFiles:         maxFiles,
+		compress:         compress,
+		now:              time.Now,
+	}
+	rotatingWriters.writers[path] = w
+	return w
+}
+
+func (w *rotatingWriter) writeLines(lines [][]byte) error {
+	var buf bytes.Buffer
+	for _, line := range lines {
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file != nil && w.shouldRotate(int64(buf.Len())) {
+		if err := w.rotate(); err != nil {
+			return err
+		}
+	}
+	if w.file == nil {
+		if err := w.open(); err != nil {
+			return err
+		}
+	}
+	n, err := w.file.Write(buf.Bytes())
+	w.size += int64(n)
+	return err
+}
+
+func (w *rotatingWriter) shouldRotate(size int64) bool {
+	if w.size == 0 {
+		return false
+	}
+	if w.size+size > w.maxSizeBytes {
+		return true
+	}
+	return w.rotationInterval > 0 && w.now().Sub(w.openedAt) >= w.rotationInterval
+}
+
+func (w *rotatingWriter) open() error {
+	if err := os.MkdirAll(filepath.Dir(w.path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(w.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o640)
+	if err != nil {
+		return err
+	}
+	stat, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	w.file = f
+	w.size = stat.Size()
+	w.openedAt = w.now()
+	return nil
+}
+
+// rotate renames the current file, compresses it if needed and removes the oldest rotated files.
+func (w *rotatingWriter) rotate() error {
+	if err := w.file.Close(); err != nil {
+		log.Warnf("Could not close %s: %v", w.path, err)
+	}
+	w.file = nil
+	w.size = 0
+
+	rotated := w.path + "." + w.now().UTC().Format(rotatedTimeFormat)
+	if err := os.Rename(w.path, rotated); err != nil {
+		return fmt.Errorf("could not rotate %s: %v", w.path, err)
+	}
+	if w.compress {
+		if err := compressFile(rotated); err != nil {
+			// the rotated file is kept uncompressed
+			log.Warnf("Could not compress %s: %v", rotated, err)
+		}
+	}
+	w.removeOldFiles()
+	return nil
+}
+
+// removeOldFiles removes the oldest rotated files to keep at most maxFiles of them.
+func (w *rotatingWriter) removeOldFiles() {
+	matches, err := filepath.Glob(w.path + ".*")
+	if err != nil {
+		return
+	}
+	var rotated []string
+	for _, match := range matches {
+		if !strings.HasSuffix(match, ".tmp") {
+			rotated = append(rotated, match)
+		}
+	}
+	sort.Strings(rotated)
+	for len(rotated) > w.maxFiles {
+		if err := os.Remove(rotated[0]); err != nil {
+			log.Warnf("Could not remove %s: %v", rotated[0], err)
+		}
+		rotated = rotated[1:]
+	}
+}
+
+// close closes the file once no destination uses the writer anymore.
+func (w *rotatingWriter) close() error {
+	rotatingWriters.Lock()
+	w.refs--
+	if w.refs > 0 {
+		rotatingWriters.Unlock()
+		return nil
+	}
+	delete(rotatingWriters.writers, w.path)
+	rotatingWriters.Unlock()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.file == nil {
+		return nil
+	}
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
+
+// compressFile replaces a file by its gzip compressed version.
+func compressFile(path string) error {
+	in, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	tmp := path + ".gz.tmp"
+	out, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o640)
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(out)
+	_, err = io.Copy(gz, in)
+	if closeErr := gz.Close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp, path+".gz")
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Remove(path)
+}
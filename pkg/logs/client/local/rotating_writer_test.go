@@ -0,0 +1,102 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package local
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func rotatedFiles(t *testing.T, path string) []string {
+	matches, err := filepath.Glob(path + ".*")
+	require.NoError(t, err)
+	sort.Strings(matches)
+	return matches
+}
+
+func TestRotatingWriterSizeRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs.json")
+	w := newRotatingWriter(path, 10, 0, 2, false)
+	now := time.Now()
+	w.now = func() time.Time { return now }
+	defer w.close()
+
+	for _, line := range []string{"aaaa", "bbbb", "cccc", "dddd", "eeee", "ffff", "gggg"} {
+		now = now.Add(time.Second)
+		require.NoError(t, w.writeLines([][]byte{[]byte(line)}))
+	}
+
+	// each file holds two lines, only the two most recent rotated files are kept
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "gggg\n", string(content))
+	rotated := rotatedFiles(t, path)
+	require.Len(t, rotated, 2)
+	content, err = os.ReadFile(rotated[1])
+	require.NoError(t, err)
+	assert.Equal(t, "eeee\nffff\n", string(content))
+}
+
+func TestRotatingWriterTimeRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs.json")
+	w := newRotatingWriter(path, 1024, time.Hour, 10, false)
+	now := time.Now()
+	w.now = func() time.Time { return now }
+	defer w.close()
+
+	require.NoError(t, w.writeLines([][]byte{[]byte("a")}))
+	now = now.Add(30 * time.Minute)
+	require.NoError(t, w.writeLines([][]byte{[]byte("b")}))
+	assert.Empty(t, rotatedFiles(t, path))
+
+	now = now.Add(30 * time.Minute)
+	require.NoError(t, w.writeLines([][]byte{[]byte("c")}))
+	assert.Len(t, rotatedFiles(t, path), 1)
+}
+
+func TestRotatingWriterCompression(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs.json")
+	w := newRotatingWriter(path, 5, 0, 10, true)
+	defer w.close()
+
+	require.NoError(t, w.writeLines([][]byte{[]byte("aaaa")}))
+	require.NoError(t, w.writeLines([][]byte{[]byte("bbbb")}))
+
+	rotated := rotatedFiles(t, path)
+	require.Len(t, rotated, 1)
+	assert.True(t, strings.HasSuffix(rotated[0], ".gz"))
+
+	f, err := os.Open(rotated[0])
+	require.NoError(t, err)
+	defer f.Close()
+	r, err := gzip.NewReader(f)
+	require.NoError(t, err)
+	content, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, "aaaa\n", string(content))
+}
+
+func TestRotatingWriterAppendsToExistingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs.json")
+	require.NoError(t, os.WriteFile(path, []byte("before\n"), 0o640))
+
+	w := newRotatingWriter(path, 1024, 0, 10, false)
+	require.NoError(t, w.writeLines([][]byte{[]byte("after")}))
+	require.NoError(t, w.close())
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "before\nafter\n", string(content))
+}
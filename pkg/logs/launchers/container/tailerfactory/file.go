@@ -96,6 +96,9 @@ func (tf *factory) attachChildSource(source, childSource *sources.LogSource) (Ta
 	childSource.ParentSource = source
 	source.HideFromStatus()
 
+	// the logs of the container are limited by the rate limit of its source
+	childSource.InheritRateLimit(source)
+
 	// return a "tailer" that will schedule and unschedule this source
 	// when started and stopped
 	return &tailers.WrappedSource{
@@ -26,6 +26,7 @@ import (
 	"github.com/DataDog/datadog-agent/comp/logs/agent/config"
 	configmock "github.com/DataDog/datadog-agent/pkg/config/mock"
 	"github.com/DataDog/datadog-agent/pkg/logs/internal/util/containersorpods"
+	"github.com/DataDog/datadog-agent/pkg/logs/launchers/container/tailerfactory/tailers"
 	"github.com/DataDog/datadog-agent/pkg/logs/pipeline"
 	"github.com/DataDog/datadog-agent/pkg/logs/sources"
 	dockerutilPkg "github.com/DataDog/datadog-agent/pkg/util/docker"
@@ -133,6 +134,31 @@ func TestMakeFileSource_docker_success(t *testing.T) {
 	require.Equal(t, source.Config.AutoMultiLineMatchThreshold, 0.123)
 }
 
+func TestMakeFileTailer_rate_limit(t *testing.T) {
+	fileTestSetup(t)
+
+	p := filepath.Join(platformDockerLogsBasePath, filepath.FromSlash("containers/abc/abc-json.log"))
+	require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o777))
+	require.NoError(t, os.WriteFile(p, []byte("{}"), 0o666))
+
+	tf := &factory{
+		pipelineProvider: pipeline.NewMockProvider(),
+		cop:              containersorpods.NewDecidedChooser(containersorpods.LogContainers),
+	}
+	source := sources.NewLogSource("test", &config.LogsConfig{
+		Type:       "docker",
+		Identifier: "abc",
+		Service:    "svc",
+		RateLimit:  &config.RateLimitConfig{MaxEventsPerSecond: 10, Mode: config.RateLimitDrop},
+	})
+	tailer, err := tf.makeFileTailer(source)
+	require.NoError(t, err)
+	child := tailer.(*tailers.WrappedSource).Source
+	require.NotNil(t, child.RateLimiter)
+	require.Same(t, source.RateLimiter, child.RateLimiter)
+	require.Equal(t, source.Config.RateLimit, child.Config.RateLimit)
+}
+
 func TestMakeFileSource_podman_success(t *testing.T) {
 	fileTestSetup(t)
 	mockConfig := configmock.New(t)
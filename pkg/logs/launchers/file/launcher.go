@@ -7,9 +7,11 @@
 package file
 
 import (
+	"os"
 	"regexp"
 	"time"
 
+	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
 	"github.com/DataDog/datadog-agent/pkg/util"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 
@@ -45,6 +47,7 @@ type Launcher struct {
 	tailers             *tailers.TailerContainer[*tailer.Tailer]
 	rotatedTailers      []*tailer.Tailer
 	registry            auditor.Registry
+	auditorChan         chan *message.Payload
 	tailerSleepDuration time.Duration
 	stop                chan struct{}
 	done                chan struct{}
@@ -55,6 +58,20 @@ type Launcher struct {
 	scanPeriod             time.Duration
 	flarecontroller        *flareController.FlareController
 	tagger                 tagger.Component
+	// archives caches the archives found by the scans, per scan key.
+	archives map[string]*archiveInfo
+	// doneArchives holds the identifiers of the archives which have been read.
+	doneArchives map[string]bool
+	// archiveMaxAge is the age after which archives are not read anymore, since the registry
+	// forgets about the archives which were read once this TTL expires.
+	archiveMaxAge time.Duration
+}
+
+// archiveInfo holds the Archive of a file, along with the state of the file when it was computed.
+type archiveInfo struct {
+	archive *tailer.Archive
+	size    int64
+	modTime time.Time
 }
 
 // NewLauncher returns a new launcher.
@@ -83,6 +100,9 @@ func NewLauncher(tailingLimit int, tailerSleepDuration time.Duration, validatePo
 		scanPeriod:             scanPeriod,
 		flarecontroller:        flarecontroller,
 		tagger:                 tagger,
+		archives:               make(map[string]*archiveInfo),
+		doneArchives:           make(map[string]bool),
+		archiveMaxAge:          time.Duration(pkgconfigsetup.Datadog().GetInt("logs_config.auditor_ttl")) * time.Hour,
 	}
 }
 
@@ -91,6 +111,10 @@ func (s *Launcher) Start(sourceProvider launchers.SourceProvider, pipelineProvid
 	s.pipelineProvider = pipelineProvider
 	s.addedSources, s.removedSources = sourceProvider.SubscribeForType(config.FileType)
 	s.registry = registry
+	// the archives which were read until the end are registered directly to the auditor
+	if a, ok := registry.(auditor.Auditor); ok {
+		s.auditorChan = a.Channel()
+	}
 	tracker.Add(s.tailers)
 	go s.run()
 }
@@ -172,10 +196,20 @@ func (s *Launcher) scan() {
 		scanKey := file.GetScanKey()
 		tailer, isTailed := s.tailers.Get(scanKey)
 		if isTailed && tailer.IsFinished() {
+			if tailer.IsArchive() {
+				// archives are read once, even when they could not be read until the end
+				s.doneArchives[tailer.Identifier()] = true
+			}
 			// skip this tailer as it must be stopped
 			continue
 		}
 
+		// Archives are not rotated, they are read until the end and then left alone.
+		if isTailed && tailer.IsArchive() {
+			filesTailed[scanKey] = true
+			continue
+		}
+
 		// If the file is currently being tailed, check for rotation and handle it appropriately.
 		if isTailed {
 			didRotate, err := tailer.DidRotate()
@@ -312,15 +346,33 @@ func (s *Launcher) startNewTailer(file *tailer.File, m config.TailingMode) bool
 		return false
 	}
 
+	var archive *tailer.Archive
+	if tailer.IsArchive(file.Path, file.Source.Config().ArchivePatterns) {
+		archive = s.getArchive(file)
+		if archive == nil {
+			return false
+		}
+	}
+
 	channel, monitor := s.pipelineProvider.NextPipelineChanWithMonitor()
-	tailer := s.createTailer(file, channel, monitor)
+	tailer := s.createTailer(file, channel, monitor, archive)
 
 	var offset int64
 	var whence int
-	mode := s.handleTailingModeChange(tailer.Identifier(), m)
-	offset, whence, err := Position(s.registry, tailer.Identifier(), mode)
-	if err != nil {
-		log.Warnf("Could not recover offset for file with path %v: %v", file.Path, err)
+	var err error
+	if archive != nil {
+		var done bool
+		offset, whence, done = ArchivePosition(s.registry, tailer.Identifier())
+		if done {
+			s.doneArchives[archive.Identifier] = true
+			return false
+		}
+	} else {
+		mode := s.handleTailingModeChange(tailer.Identifier(), m)
+		offset, whence, err = Position(s.registry, tailer.Identifier(), mode)
+		if err != nil {
+			log.Warnf("Could not recover offset for file with path %v: %v", file.Path, err)
+		}
 	}
 
 	log.Infof("Starting a new tailer for: %s (offset: %d, whence: %d) for tailer key %s", file.Path, offset, whence, file.GetScanKey())
@@ -334,6 +386,36 @@ func (s *Launcher) startNewTailer(file *tailer.File, m config.TailingMode) bool
 	return true
 }
 
+// getArchive returns the Archive of a file, or nil if the archive must not be read
+// because it was already read, it is too old or it can't be read.
+func (s *Launcher) getArchive(file *tailer.File) *tailer.Archive {
+	stat, err := os.Stat(file.Path)
+	if err != nil {
+		log.Debugf("Could not stat archive %s: %v", file.Path, err)
+		return nil
+	}
+	if s.archiveMaxAge > 0 && time.Since(stat.ModTime()) > s.archiveMaxAge {
+		return nil
+	}
+
+	scanKey := file.GetScanKey()
+	info, found := s.archives[scanKey]
+	if !found || info.size != stat.Size() || !info.modTime.Equal(stat.ModTime()) {
+		archive, err := tailer.NewArchive(file.Path)
+		if err != nil {
+			// keep the error to not open the file again until it changes
+			log.Warnf("Could not read archive %s: %v", file.Path, err)
+			file.Source.Status().Error(err)
+		}
+		info = &archiveInfo{archive: archive, size: stat.Size(), modTime: stat.ModTime()}
+		s.archives[scanKey] = info
+	}
+	if info.archive == nil || s.doneArchives[info.archive.Identifier] {
+		return nil
+	}
+	return info.archive
+}
+
 // handleTailingModeChange determines the tailing behaviour when the tailing mode for a given file has its
 // configuration change. Two case may happen we can switch from "end" to "beginning" (1) and from "beginning" to
 // "end" (2). If the tailing mode is set to forceEnd or forceBeginning it will remain unchanged.
@@ -384,7 +466,7 @@ func (s *Launcher) restartTailerAfterFileRotation(oldTailer *tailer.Tailer, file
 }
 
 // createTailer returns a new initialized tailer
-func (s *Launcher) createTailer(file *tailer.File, outputChan chan *message.Message, pipelineMonitor metrics.PipelineMonitor) *tailer.Tailer {
+func (s *Launcher) createTailer(file *tailer.File, outputChan chan *message.Message, pipelineMonitor metrics.PipelineMonitor, archive *tailer.Archive) *tailer.Tailer {
 	tailerInfo := status.NewInfoRegistry()
 
 	tailerOptions := &tailer.TailerOptions{
@@ -393,6 +475,8 @@ func (s *Launcher) createTailer(file *tailer.File, outputChan chan *message.Mess
 		SleepDuration:   s.tailerSleepDuration,
 		Decoder:         decoder.NewDecoderFromSource(file.Source, tailerInfo),
 		Info:            tailerInfo,
+		Archive:         archive,
+		AuditorChan:     s.auditorChan,
 		TagAdder:        s.tagger,
 		PipelineMonitor: pipelineMonitor,
 	}
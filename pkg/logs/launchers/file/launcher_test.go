@@ -8,6 +8,8 @@
 package file
 
 import (
+	"bytes"
+	"compress/gzip"
 	"fmt"
 	"os"
 	"testing"
@@ -745,3 +747,54 @@ func TestLauncherFileDetectionSingleScan(t *testing.T) {
 func getScanKey(path string, source *sources.LogSource) string {
 	return filetailer.NewFile(path, source, false).GetScanKey()
 }
+
+func TestLauncherReadsArchivesOnce(t *testing.T) {
+	testDir := t.TempDir()
+	fakeTagger := taggerMock.SetupFakeTagger(t)
+
+	fc := flareController.NewFlareController()
+	launcher := NewLauncher(3, 20*time.Millisecond, false, 10*time.Second, "by_name", fc, fakeTagger)
+	launcher.pipelineProvider = mock.NewMockProvider()
+	launcher.registry = auditor.NewRegistry()
+	launcher.auditorChan = make(chan *message.Payload, 1)
+	outputChan := launcher.pipelineProvider.NextPipelineChan()
+	source := sources.NewLogSource("", &config.LogsConfig{Type: config.FileType, Path: fmt.Sprintf("%s/app.log*", testDir), ArchivePatterns: []string{"*.gz"}})
+	launcher.activeSources = append(launcher.activeSources, source)
+
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	_, err := w.Write([]byte("archived\n"))
+	assert.Nil(t, err)
+	assert.Nil(t, w.Close())
+	archivePath := fmt.Sprintf("%s/app.log.1.gz", testDir)
+	assert.Nil(t, os.WriteFile(archivePath, buf.Bytes(), 0o644))
+
+	launcher.scan()
+	assert.Equal(t, 1, launcher.tailers.Count())
+	msg := <-outputChan
+	assert.Equal(t, "archived", string(msg.GetContent()))
+
+	archiveTailer, _ := launcher.tailers.Get(archivePath)
+	assert.Eventually(t, archiveTailer.IsFinished, 5*time.Second, 10*time.Millisecond)
+	// the tailer registers the archive as done to the auditor
+	done := <-launcher.auditorChan
+	assert.Equal(t, archiveTailer.Identifier(), done.Messages[0].Origin.Identifier)
+	assert.Equal(t, filetailer.ArchiveDoneOffset, done.Messages[0].Origin.Offset)
+
+	// the finished tailer is removed and the archive is not read again, even once renamed
+	launcher.scan()
+	assert.Equal(t, 0, launcher.tailers.Count())
+	assert.Nil(t, os.Rename(archivePath, fmt.Sprintf("%s/app.log.2.gz", testDir)))
+	launcher.scan()
+	assert.Equal(t, 0, launcher.tailers.Count())
+
+	// an archive registered as done is not read after a restart
+	launcher = NewLauncher(3, 20*time.Millisecond, false, 10*time.Second, "by_name", fc, fakeTagger)
+	launcher.pipelineProvider = mock.NewMockProvider()
+	registry := auditor.NewRegistry()
+	registry.SetOffset(filetailer.ArchiveDoneOffset)
+	launcher.registry = registry
+	launcher.activeSources = append(launcher.activeSources, source)
+	launcher.scan()
+	assert.Equal(t, 0, launcher.tailers.Count())
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/DataDog/datadog-agent/comp/logs/agent/config"
 	"github.com/DataDog/datadog-agent/pkg/logs/auditor"
+	tailer "github.com/DataDog/datadog-agent/pkg/logs/tailers/file"
 )
 
 // Position returns the position from where logs should be collected.
@@ -47,3 +48,17 @@ func Position(registry auditor.Registry, identifier string, mode config.TailingM
 	}
 	return offset, whence, err
 }
+
+// ArchivePosition returns the position from where an archive should be read, archives are always
+// read from the beginning unless an offset was registered. done is true if the archive was already read.
+func ArchivePosition(registry auditor.Registry, identifier string) (offset int64, whence int, done bool) {
+	value := registry.GetOffset(identifier)
+	if value == tailer.ArchiveDoneOffset {
+		return 0, io.SeekStart, true
+	}
+	offset, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		offset = 0
+	}
+	return offset, io.SeekStart, false
+}
@@ -13,6 +13,7 @@ import (
 
 	"github.com/DataDog/datadog-agent/comp/logs/agent/config"
 	"github.com/DataDog/datadog-agent/pkg/logs/auditor/mock"
+	tailer "github.com/DataDog/datadog-agent/pkg/logs/tailers/file"
 )
 
 func TestPosition(t *testing.T) {
@@ -68,3 +69,22 @@ func TestPosition(t *testing.T) {
 	assert.Equal(t, int64(0), offset)
 	assert.Equal(t, io.SeekEnd, whence)
 }
+
+func TestArchivePosition(t *testing.T) {
+	registry := mock.NewRegistry()
+
+	offset, whence, done := ArchivePosition(registry, "")
+	assert.False(t, done)
+	assert.Equal(t, int64(0), offset)
+	assert.Equal(t, io.SeekStart, whence)
+
+	registry.SetOffset("42")
+	offset, whence, done = ArchivePosition(registry, "")
+	assert.False(t, done)
+	assert.Equal(t, int64(42), offset)
+	assert.Equal(t, io.SeekStart, whence)
+
+	registry.SetOffset(tailer.ArchiveDoneOffset)
+	_, _, done = ArchivePosition(registry, "")
+	assert.True(t, done)
+}
@@ -313,6 +313,7 @@ func (s *Launcher) makeFileSource(source *sources.LogSource, logFilePath string)
 	})
 
 	fileSource.SetSourceType(sources.IntegrationSourceType)
+	fileSource.InheritRateLimit(source)
 	return fileSource
 }
 
@@ -82,6 +82,17 @@ var (
 	// TlmLogsDiscardedFromSDSBuffer how many messages were dropped when waiting for an SDS configuration because the buffer is full
 	TlmLogsDiscardedFromSDSBuffer = telemetry.NewCounter("logs", "sds__dropped_from_buffer", nil, "Count of messages dropped from the buffer while waiting for an SDS configuration")
 
+	// TlmLogsDeduplicated is the number of messages not sent because they were collapsed by a deduplicate processing rule
+	TlmLogsDeduplicated = telemetry.NewCounter("logs", "deduplicated", nil, "Count of messages collapsed by deduplicate processing rules")
+	// TlmBytesDeduplicated is the number of bytes not sent because they were collapsed by a deduplicate processing rule
+	TlmBytesDeduplicated = telemetry.NewCounter("logs", "bytes_deduplicated", nil, "Count of bytes collapsed by deduplicate processing rules")
+
+	// LogsRateLimited is the total number of logs dropped because their source was over its rate limit
+	LogsRateLimited = expvar.Int{}
+	// TlmLogsRateLimited is the total number of logs dropped because their source was over its rate limit
+	TlmLogsRateLimited = telemetry.NewCounter("logs", "rate_limited",
+		nil, "Total number of logs dropped because their source was over its rate limit")
+
 	// TlmUtilizationRatio is the utilization ratio of a component.
 	// Utilization ratio is calculated as the ratio of time spent in use to the total time.
 	// This metric is internally sampled and exposed as an ewma in order to produce a useable value.
@@ -106,5 +117,6 @@ func init() {
 	LogsExpvars.Set("EncodedBytesSent", &EncodedBytesSent)
 	LogsExpvars.Set("BytesMissed", &BytesMissed)
 	LogsExpvars.Set("SenderLatency", &SenderLatency)
+	LogsExpvars.Set("LogsRateLimited", &LogsRateLimited)
 	LogsExpvars.Set("HttpDestinationStats", &DestinationExpVars)
 }
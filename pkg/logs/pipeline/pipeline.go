@@ -8,6 +8,7 @@ package pipeline
 
 import (
 	"context"
+	"path/filepath"
 	"strconv"
 	"sync"
 
@@ -17,6 +18,7 @@ import (
 	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
 	"github.com/DataDog/datadog-agent/pkg/logs/client"
 	"github.com/DataDog/datadog-agent/pkg/logs/client/http"
+	"github.com/DataDog/datadog-agent/pkg/logs/client/local"
 	"github.com/DataDog/datadog-agent/pkg/logs/client/tcp"
 	"github.com/DataDog/datadog-agent/pkg/logs/diagnostic"
 	"github.com/DataDog/datadog-agent/pkg/logs/message"
@@ -24,6 +26,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/logs/processor"
 	"github.com/DataDog/datadog-agent/pkg/logs/sender"
 	"github.com/DataDog/datadog-agent/pkg/logs/status/statusinterface"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
 )
 
 // Pipeline processes and sends messages to the backend
@@ -32,6 +35,7 @@ type Pipeline struct {
 	flushChan       chan struct{}
 	processor       *processor.Processor
 	strategy        sender.Strategy
+	diskBuffer      *sender.DiskBuffer
 	sender          *sender.Sender
 	serverless      bool
 	flushWg         *sync.WaitGroup
@@ -77,7 +81,21 @@ func NewPipeline(outputChan chan *message.Payload,
 		encoder = processor.RawEncoder
 	}
 
-	strategy := getStrategy(strategyInput, senderInput, flushChan, endpoints, serverless, flushWg, pipelineMonitor)
+	// The disk buffer is not supported in serverless, payloads must be sent before the flush returns
+	var diskBuffer *sender.DiskBuffer
+	strategyOutput := senderInput
+	if !serverless && pkgconfigsetup.Datadog().GetBool("logs_config.disk_buffer.enabled") {
+		strategyOutput = make(chan *message.Payload, 1)
+		var err error
+		diskBuffer, err = sender.NewDiskBuffer(strategyOutput, senderInput, outputChan, getDiskBufferConfig(pkgconfigsetup.Datadog(), pipelineID), strconv.Itoa(pipelineID))
+		if err != nil {
+			log.Errorf("Unable to set up the logs disk buffer, payloads will only be buffered in memory: %v", err)
+			diskBuffer = nil
+			strategyOutput = senderInput
+		}
+	}
+
+	strategy := getStrategy(strategyInput, strategyOutput, flushChan, endpoints, serverless, flushWg, pipelineMonitor)
 	logsSender = sender.NewSender(cfg, senderInput, outputChan, mainDestinations, pkgconfigsetup.Datadog().GetInt("logs_config.payload_channel_size"), senderDoneChan, flushWg, pipelineMonitor)
 
 	inputChan := make(chan *message.Message, pkgconfigsetup.Datadog().GetInt("logs_config.message_channel_size"))
@@ -90,6 +108,7 @@ func NewPipeline(outputChan chan *message.Payload,
 		flushChan:       flushChan,
 		processor:       processor,
 		strategy:        strategy,
+		diskBuffer:      diskBuffer,
 		sender:          logsSender,
 		serverless:      serverless,
 		flushWg:         flushWg,
@@ -100,6 +119,9 @@ func NewPipeline(outputChan chan *message.Payload,
 // Start launches the pipeline
 func (p *Pipeline) Start() {
 	p.sender.Start()
+	if p.diskBuffer != nil {
+		p.diskBuffer.Start()
+	}
 	p.strategy.Start()
 	p.processor.Start()
 }
@@ -108,6 +130,9 @@ func (p *Pipeline) Start() {
 func (p *Pipeline) Stop() {
 	p.processor.Stop()
 	p.strategy.Stop()
+	if p.diskBuffer != nil {
+		p.diskBuffer.Stop()
+	}
 	p.sender.Stop()
 }
 
@@ -128,6 +153,10 @@ func getDestinations(endpoints *config.Endpoints, destinationsContext *client.De
 
 	if endpoints.UseHTTP {
 		for i, endpoint := range endpoints.GetReliableEndpoints() {
+			if endpoint.IsLocal() {
+				reliable = appendLocalDestination(reliable, endpoint, destinationsContext, true, serverless)
+				continue
+			}
 			destMeta := client.NewDestinationMetadata("logs", pipelineMonitor.ID(), "reliable", strconv.Itoa(i))
 			if serverless {
 				reliable = append(reliable, http.NewSyncDestination(endpoint, http.JSONContentType, destinationsContext, senderDoneChan, destMeta, cfg))
@@ -136,6 +165,10 @@ func getDestinations(endpoints *config.Endpoints, destinationsContext *client.De
 			}
 		}
 		for i, endpoint := range endpoints.GetUnReliableEndpoints() {
+			if endpoint.IsLocal() {
+				additionals = appendLocalDestination(additionals, endpoint, destinationsContext, false, serverless)
+				continue
+			}
 			destMeta := client.NewDestinationMetadata("logs", pipelineMonitor.ID(), "unreliable", strconv.Itoa(i))
 			if serverless {
 				additionals = append(additionals, http.NewSyncDestination(endpoint, http.JSONContentType, destinationsContext, senderDoneChan, destMeta, cfg))
@@ -146,15 +179,47 @@ func getDestinations(endpoints *config.Endpoints, destinationsContext *client.De
 		return client.NewDestinations(reliable, additionals)
 	}
 	for _, endpoint := range endpoints.GetReliableEndpoints() {
+		if endpoint.IsLocal() {
+			reliable = appendLocalDestination(reliable, endpoint, destinationsContext, true, serverless)
+			continue
+		}
 		reliable = append(reliable, tcp.NewDestination(endpoint, endpoints.UseProto, destinationsContext, !serverless, status))
 	}
 	for _, endpoint := range endpoints.GetUnReliableEndpoints() {
+		if endpoint.IsLocal() {
+			additionals = appendLocalDestination(additionals, endpoint, destinationsContext, false, serverless)
+			continue
+		}
 		additionals = append(additionals, tcp.NewDestination(endpoint, endpoints.UseProto, destinationsContext, false, status))
 	}
 
 	return client.NewDestinations(reliable, additionals)
 }
 
+// appendLocalDestination adds the destination of a local endpoint. Local destinations are not
+// supported in serverless, where flushes only wait for the intake destinations.
+func appendLocalDestination(destinations []client.Destination, endpoint config.Endpoint, destinationsContext *client.DestinationsContext, reliable bool, serverless bool) []client.Destination {
+	if serverless {
+		return destinations
+	}
+	return append(destinations, local.NewDestination(endpoint, destinationsContext, reliable))
+}
+
+// getDiskBufferConfig returns the disk buffer settings of a pipeline, each pipeline
+// stores its payloads in its own directory.
+func getDiskBufferConfig(cfg pkgconfigmodel.Reader, pipelineID int) sender.DiskBufferConfig {
+	path := cfg.GetString("logs_config.disk_buffer.path")
+	if path == "" {
+		path = filepath.Join(cfg.GetString("logs_config.run_path"), "disk_buffer")
+	}
+	return sender.DiskBufferConfig{
+		Path:           filepath.Join(path, strconv.Itoa(pipelineID)),
+		MaxSizeBytes:   cfg.GetInt64("logs_config.disk_buffer.max_size_bytes"),
+		EvictionPolicy: cfg.GetString("logs_config.disk_buffer.eviction_policy"),
+		SpillTimeout:   cfg.GetDuration("logs_config.disk_buffer.spill_timeout"),
+	}
+}
+
 //nolint:revive // TODO(AML) Fix revive linter
 func getStrategy(inputChan chan *message.Message, outputChan chan *message.Payload, flushChan chan struct{}, endpoints *config.Endpoints, serverless bool, flushWg *sync.WaitGroup, pipelineMonitor metrics.PipelineMonitor) sender.Strategy {
 	if endpoints.UseHTTP || serverless {
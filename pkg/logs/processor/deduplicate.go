@@ -0,0 +1,158 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package processor
+
+import (
+	"container/list"
+	"hash/fnv"
+	"time"
+
+	"github.com/DataDog/datadog-agent/comp/logs/agent/config"
+	"github.com/DataDog/datadog-agent/pkg/logs/message"
+	"github.com/DataDog/datadog-agent/pkg/logs/metrics"
+	"github.com/DataDog/datadog-agent/pkg/logs/sources"
+)
+
+// maxDeduplicationEntries bounds the number of distinct messages tracked by a processor,
+// the oldest windows are closed early when it is reached.
+const maxDeduplicationEntries = 10000
+
+// RepeatCountTagPrefix prefixes the tag added to a deduplicated message, with the
+// number of identical messages it stands for.
+const RepeatCountTagPrefix = "repeat_count:"
+
+type dedupKey struct {
+	source     *sources.LogSource
+	identifier string
+	rule       *config.ProcessingRule
+	hash       uint64
+}
+
+type dedupEntry struct {
+	key      dedupKey
+	expireAt time.Time
+	// count is the number of duplicates seen during the window, not including the first
+	// occurrence which has already been sent.
+	count int
+	// last is the last duplicate seen, it is sent with the repeat count when the window closes.
+	last *message.Message
+}
+
+// deduplicator keeps track of the messages seen by the deduplicate processing rules.
+// The first occurrence of a message is sent right away, the following identical
+// messages from the same source are held back until the end of the window, when
+// only the last one is sent, tagged with the number of duplicates it replaces.
+type deduplicator struct {
+	entries map[dedupKey]*list.Element
+	// order holds the entries from the oldest to the newest
+	order *list.List
+	// closed holds the entries whose window closed outside of a flush
+	closed []*dedupEntry
+	// flushing is the message being sent at the end of its window, rules must let it through
+	flushing *message.Message
+	now      func() time.Time
+}
+
+func (d *deduplicator) init() {
+	if d.entries == nil {
+		d.entries = make(map[dedupKey]*list.Element)
+		d.order = list.New()
+	}
+	if d.now == nil {
+		d.now = time.Now
+	}
+}
+
+// isDuplicate returns true if the message is a duplicate which must not be sent now,
+// content is the message content after applying the previous processing rules.
+func (d *deduplicator) isDuplicate(msg *message.Message, content []byte, rule *config.ProcessingRule) bool {
+	if msg == d.flushing {
+		return false
+	}
+	d.init()
+
+	if rule.Regex != nil {
+		content = rule.Regex.ReplaceAll(content, rule.Placeholder)
+	}
+	h := fnv.New64a()
+	_, _ = h.Write(content)
+	key := dedupKey{
+		source:     msg.Origin.LogSource,
+		identifier: msg.Origin.Identifier,
+		rule:       rule,
+		hash:       h.Sum64(),
+	}
+
+	now := d.now()
+	if elem, found := d.entries[key]; found {
+		entry := elem.Value.(*dedupEntry)
+		if now.Before(entry.expireAt) {
+			if entry.last != nil {
+				// the previous duplicate is replaced by this one, it will never be sent
+				suppress(entry.last)
+			}
+			entry.last = msg
+			entry.count++
+			return true
+		}
+		// the window is over, this message opens a new one
+		d.close(elem)
+	}
+
+	if d.order.Len() >= maxDeduplicationEntries {
+		d.close(d.order.Front())
+	}
+	entry := &dedupEntry{key: key, expireAt: now.Add(rule.WindowDuration)}
+	d.entries[key] = d.order.PushBack(entry)
+	return false
+}
+
+// close stops tracking an entry, keeping it to be flushed if it held back a duplicate.
+func (d *deduplicator) close(elem *list.Element) {
+	entry := d.order.Remove(elem).(*dedupEntry)
+	delete(d.entries, entry.key)
+	if entry.last != nil {
+		d.closed = append(d.closed, entry)
+	}
+}
+
+// expired closes the windows which are over and returns all the closed entries.
+func (d *deduplicator) expired() []*dedupEntry {
+	if d.order == nil {
+		return nil
+	}
+	now := d.now()
+	for elem := d.order.Front(); elem != nil; {
+		next := elem.Next()
+		if !now.Before(elem.Value.(*dedupEntry).expireAt) {
+			d.close(elem)
+		}
+		elem = next
+	}
+	return d.takeClosed()
+}
+
+// all closes every window and returns all the closed entries.
+func (d *deduplicator) all() []*dedupEntry {
+	if d.order == nil {
+		return nil
+	}
+	for d.order.Len() > 0 {
+		d.close(d.order.Front())
+	}
+	return d.takeClosed()
+}
+
+func (d *deduplicator) takeClosed() []*dedupEntry {
+	closed := d.closed
+	d.closed = nil
+	return closed
+}
+
+func suppress(msg *message.Message) {
+	metrics.TlmLogsDeduplicated.Inc()
+	metrics.TlmBytesDeduplicated.Add(float64(len(msg.GetContent())))
+}
@@ -0,0 +1,134 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package processor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/comp/core/hostname/hostnameinterface"
+	"github.com/DataDog/datadog-agent/comp/logs/agent/config"
+	"github.com/DataDog/datadog-agent/pkg/logs/diagnostic"
+	"github.com/DataDog/datadog-agent/pkg/logs/message"
+	"github.com/DataDog/datadog-agent/pkg/logs/metrics"
+	"github.com/DataDog/datadog-agent/pkg/logs/sds"
+	"github.com/DataDog/datadog-agent/pkg/logs/sources"
+)
+
+func newDeduplicateSource(t *testing.T, pattern string) *sources.LogSource {
+	rule := &config.ProcessingRule{Type: config.Deduplicate, Name: "dedup", Pattern: pattern, Window: "10s"}
+	require.NoError(t, config.CompileProcessingRules([]*config.ProcessingRule{rule}))
+	return sources.NewLogSource("test", &config.LogsConfig{ProcessingRules: []*config.ProcessingRule{rule}})
+}
+
+func newDeduplicateProcessor(now *time.Time) *Processor {
+	hostnameComponent, _ := hostnameinterface.NewMock("testHostname")
+	pm := metrics.NewNoopPipelineMonitor("")
+	return &Processor{
+		encoder:                   RawEncoder,
+		outputChan:                make(chan *message.Message, 100),
+		diagnosticMessageReceiver: diagnostic.NewBufferedMessageReceiver(nil, hostnameComponent),
+		hostname:                  hostnameComponent,
+		sds:                       sdsProcessor{scanner: sds.CreateScanner("42")},
+		dedup:                     deduplicator{now: func() time.Time { return *now }},
+		pipelineMonitor:           pm,
+		utilization:               pm.MakeUtilizationMonitor("processor"),
+	}
+}
+
+func TestDeduplicateWithinWindow(t *testing.T) {
+	now := time.Now()
+	p := newDeduplicateProcessor(&now)
+	source := newDeduplicateSource(t, "")
+
+	for i := 0; i < 5; i++ {
+		p.processMessage(newMessage([]byte("crash"), source, ""))
+	}
+	p.processMessage(newMessage([]byte("other"), source, ""))
+
+	// the first occurrence of each message is sent right away
+	require.Len(t, p.outputChan, 2)
+	assert.Contains(t, string((<-p.outputChan).GetContent()), "crash")
+	assert.Contains(t, string((<-p.outputChan).GetContent()), "other")
+
+	// the duplicates are held back until the end of the window
+	now = now.Add(5 * time.Second)
+	p.flushDeduplicated(p.dedup.expired())
+	assert.Len(t, p.outputChan, 0)
+
+	now = now.Add(5 * time.Second)
+	p.flushDeduplicated(p.dedup.expired())
+	require.Len(t, p.outputChan, 1)
+	msg := <-p.outputChan
+	assert.Contains(t, string(msg.GetContent()), "crash")
+	assert.Contains(t, msg.ProcessingTags, RepeatCountTagPrefix+"4")
+
+	// a new window starts
+	p.processMessage(newMessage([]byte("crash"), source, ""))
+	require.Len(t, p.outputChan, 1)
+	assert.Empty(t, (<-p.outputChan).ProcessingTags)
+}
+
+func TestDeduplicateClosedByNewOccurrence(t *testing.T) {
+	now := time.Now()
+	p := newDeduplicateProcessor(&now)
+	source := newDeduplicateSource(t, "")
+
+	p.processMessage(newMessage([]byte("crash"), source, ""))
+	p.processMessage(newMessage([]byte("crash"), source, ""))
+	<-p.outputChan
+
+	// the window is over but wasn't flushed yet, the held back duplicate comes first
+	now = now.Add(time.Minute)
+	p.processMessage(newMessage([]byte("crash"), source, ""))
+	require.Len(t, p.outputChan, 2)
+	assert.Contains(t, (<-p.outputChan).ProcessingTags, RepeatCountTagPrefix+"1")
+	assert.Empty(t, (<-p.outputChan).ProcessingTags)
+}
+
+func TestDeduplicateAfterMasking(t *testing.T) {
+	now := time.Now()
+	p := newDeduplicateProcessor(&now)
+	source := newDeduplicateSource(t, `pid=\d+`)
+	other := newDeduplicateSource(t, `pid=\d+`)
+
+	p.processMessage(newMessage([]byte("worker pid=1 exited"), source, ""))
+	p.processMessage(newMessage([]byte("worker pid=2 exited"), source, ""))
+	// the same message from another source is not a duplicate
+	p.processMessage(newMessage([]byte("worker pid=3 exited"), other, ""))
+	require.Len(t, p.outputChan, 2)
+	<-p.outputChan
+	<-p.outputChan
+
+	// the original content is sent, the pattern is only used to compare messages
+	p.flushDeduplicated(p.dedup.all())
+	require.Len(t, p.outputChan, 1)
+	assert.Contains(t, string((<-p.outputChan).GetContent()), "worker pid=2 exited")
+}
+
+func TestDeduplicateBoundedEntries(t *testing.T) {
+	now := time.Now()
+	d := &deduplicator{now: func() time.Time { return now }}
+	source := newDeduplicateSource(t, "")
+	rule := source.Config.ProcessingRules[0]
+
+	first := newMessage([]byte("first"), source, "")
+	assert.False(t, d.isDuplicate(first, first.GetContent(), rule))
+	assert.True(t, d.isDuplicate(first, first.GetContent(), rule))
+	for i := 0; i < maxDeduplicationEntries; i++ {
+		msg := newMessage([]byte{byte(i), byte(i >> 8)}, source, "")
+		d.isDuplicate(msg, msg.GetContent(), rule)
+	}
+
+	assert.Equal(t, maxDeduplicationEntries, d.order.Len())
+	// the oldest window was closed early to make room
+	closed := d.takeClosed()
+	require.Len(t, closed, 1)
+	assert.Same(t, first, closed[0].last)
+}
@@ -7,7 +7,9 @@ package processor
 
 import (
 	"context"
+	"strconv"
 	"sync"
+	"time"
 
 	"github.com/DataDog/datadog-agent/comp/core/hostname/hostnameinterface"
 	"github.com/DataDog/datadog-agent/comp/logs/agent/config"
@@ -23,6 +25,9 @@ import (
 // content for tailers capable of processing both unstructured and structured content.
 const UnstructuredProcessingMetricName = "datadog.logs_agent.tailer.unstructured_processing"
 
+// deduplicationFlushPeriod is how often the processor looks for deduplication windows which are over.
+const deduplicationFlushPeriod = time.Second
+
 // A Processor updates messages from an inputChan and pushes
 // in an outputChan.
 type Processor struct {
@@ -40,6 +45,8 @@ type Processor struct {
 
 	sds sdsProcessor
 
+	dedup deduplicator
+
 	// Telemetry
 	pipelineMonitor metrics.PipelineMonitor
 	utilization     metrics.UtilizationMonitor
@@ -116,6 +123,8 @@ func (p *Processor) Flush(ctx context.Context) {
 			return
 		default:
 			if len(p.inputChan) == 0 {
+				// the duplicates held back must be sent before the flush completes
+				p.flushDeduplicated(p.dedup.all())
 				return
 			}
 			msg := <-p.inputChan
@@ -126,7 +135,11 @@ func (p *Processor) Flush(ctx context.Context) {
 
 // run starts the processing of the inputChan
 func (p *Processor) run() {
+	dedupTicker := time.NewTicker(deduplicationFlushPeriod)
 	defer func() {
+		dedupTicker.Stop()
+		// send the duplicates still held back, their windows won't be over
+		p.flushDeduplicated(p.dedup.all())
 		p.done <- struct{}{}
 	}()
 
@@ -161,6 +174,14 @@ func (p *Processor) run() {
 			p.mu.Lock()
 			p.applySDSReconfiguration(order)
 			p.mu.Unlock()
+
+		// Deduplication windows
+		// ---------------------
+
+		case <-dedupTicker.C:
+			p.mu.Lock()
+			p.flushDeduplicated(p.dedup.expired())
+			p.mu.Unlock()
 		}
 	}
 }
@@ -227,32 +248,63 @@ func (p *Processor) processMessage(msg *message.Message) {
 	metrics.LogsDecoded.Add(1)
 	metrics.TlmLogsDecoded.Inc()
 
-	if toSend := p.applyRedactingRules(msg); toSend {
-		metrics.LogsProcessed.Add(1)
-		metrics.TlmLogsProcessed.Inc()
+	toSend := p.applyRedactingRules(msg)
+	// duplicates whose window was closed by this message were received before it
+	p.flushDeduplicated(p.dedup.takeClosed())
+	if toSend {
+		p.sendMessage(msg)
+	}
+}
 
-		// render the message
-		rendered, err := msg.Render()
-		if err != nil {
-			log.Error("can't render the msg", err)
-			return
-		}
-		msg.SetRendered(rendered)
+// sendMessage renders and encodes a message which passed the processing rules,
+// and forwards it to the strategy.
+func (p *Processor) sendMessage(msg *message.Message) {
+	if p.isRateLimited(msg) {
+		return
+	}
 
-		// report this message to diagnostic receivers (e.g. `stream-logs` command)
-		p.diagnosticMessageReceiver.HandleMessage(msg, rendered, "")
+	metrics.LogsProcessed.Add(1)
+	metrics.TlmLogsProcessed.Inc()
 
-		// encode the message to its final format, it is done in-place
-		if err := p.encoder.Encode(msg, p.GetHostname(msg)); err != nil {
-			log.Error("unable to encode msg ", err)
-			return
-		}
+	// render the message
+	rendered, err := msg.Render()
+	if err != nil {
+		log.Error("can't render the msg", err)
+		return
+	}
+	msg.SetRendered(rendered)
+
+	// report this message to diagnostic receivers (e.g. `stream-logs` command)
+	p.diagnosticMessageReceiver.HandleMessage(msg, rendered, "")
 
-		p.utilization.Stop() // Explicitly call stop here to avoid counting writing on the output channel as processing time
-		p.outputChan <- msg
-		p.pipelineMonitor.ReportComponentIngress(msg, "strategy")
+	// encode the message to its final format, it is done in-place
+	if err := p.encoder.Encode(msg, p.GetHostname(msg)); err != nil {
+		log.Error("unable to encode msg ", err)
+		return
 	}
 
+	p.utilization.Stop() // Explicitly call stop here to avoid counting writing on the output channel as processing time
+	p.outputChan <- msg
+	p.pipelineMonitor.ReportComponentIngress(msg, "strategy")
+}
+
+// flushDeduplicated sends the last duplicate held back by each closed deduplication
+// window, tagged with the number of messages it stands for.
+func (p *Processor) flushDeduplicated(entries []*dedupEntry) {
+	for _, entry := range entries {
+		msg := entry.last
+		msg.ProcessingTags = append(msg.ProcessingTags, RepeatCountTagPrefix+strconv.Itoa(entry.count))
+
+		p.utilization.Start()
+		// the message content is still the original one, the rules are applied again
+		p.dedup.flushing = msg
+		toSend := p.applyRedactingRules(msg)
+		p.dedup.flushing = nil
+		if toSend {
+			p.sendMessage(msg)
+		}
+		p.utilization.Stop()
+	}
 }
 
 // applyRedactingRules returns given a message if we should process it or not,
@@ -278,6 +330,11 @@ func (p *Processor) applyRedactingRules(msg *message.Message) bool {
 			}
 		case config.MaskSequences:
 			content = rule.Regex.ReplaceAll(content, rule.Placeholder)
+		case config.Deduplicate:
+			// if this message was already seen during the window, it is held back
+			if p.dedup.isDuplicate(msg, content, rule) {
+				return false
+			}
 		}
 	}
 
@@ -298,6 +355,29 @@ func (p *Processor) applyRedactingRules(msg *message.Message) bool {
 	return true // we want to send this message
 }
 
+// isRateLimited returns true if the message is over the throughput limit of its source and must be dropped.
+func (p *Processor) isRateLimited(msg *message.Message) bool {
+	source := msg.Origin.LogSource
+	if source.RateLimiter == nil {
+		return false
+	}
+	if source.RateLimiter.Allow(len(msg.GetContent()), isErrorStatus(msg.GetStatus())) {
+		return false
+	}
+	source.RecordRateLimited()
+	metrics.LogsRateLimited.Add(1)
+	metrics.TlmLogsRateLimited.Inc()
+	return true
+}
+
+func isErrorStatus(status string) bool {
+	switch status {
+	case message.StatusEmergency, message.StatusAlert, message.StatusCritical, message.StatusError:
+		return true
+	}
+	return false
+}
+
 // GetHostname returns the hostname to applied the given log message
 func (p *Processor) GetHostname(msg *message.Message) string {
 	if msg.Hostname != "" {
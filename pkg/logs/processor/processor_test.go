@@ -495,3 +495,24 @@ func newStructuredMessage(content []byte, source *sources.LogSource, status stri
 	msg.SetContent(content)
 	return msg
 }
+
+func TestRateLimit(t *testing.T) {
+	hostnameComponent, _ := hostnameinterface.NewMock("testHostname")
+	pm := metrics.NewNoopPipelineMonitor("")
+	p := &Processor{
+		encoder:                   RawEncoder,
+		outputChan:                make(chan *message.Message, 10),
+		diagnosticMessageReceiver: diagnostic.NewBufferedMessageReceiver(nil, hostnameComponent),
+		hostname:                  hostnameComponent,
+		sds:                       sdsProcessor{scanner: sds.CreateScanner("42")},
+		pipelineMonitor:           pm,
+		utilization:               pm.MakeUtilizationMonitor("processor"),
+	}
+	source := sources.NewLogSource("", &config.LogsConfig{RateLimit: &config.RateLimitConfig{MaxEventsPerSecond: 1, Mode: config.RateLimitDrop}})
+
+	p.processMessage(newMessage([]byte("hello"), source, ""))
+	p.processMessage(newMessage([]byte("world"), source, ""))
+
+	assert.Len(t, p.outputChan, 1)
+	assert.Equal(t, int64(1), source.RateLimited.Get())
+}
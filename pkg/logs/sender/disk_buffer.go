@@ -0,0 +1,400 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package sender
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/logs/message"
+	"github.com/DataDog/datadog-agent/pkg/telemetry"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	// DiskBufferEvictionDropOldest removes the oldest payloads from disk to make room for new ones.
+	DiskBufferEvictionDropOldest = "drop_oldest"
+	// DiskBufferEvictionBlock waits for the destinations to drain the disk buffer when it is full,
+	// applying backpressure to the pipeline like the in-memory buffers do.
+	DiskBufferEvictionBlock = "block"
+
+	diskBufferFileExt     = ".payload"
+	diskBufferTmpExt      = ".tmp"
+	diskBufferFormatV1    = byte(1)
+	diskBufferHeaderSize  = 1 + 2 + 8 + 4 // version, encoding length, unencoded size, crc32
+	diskBufferFilePattern = "%020d" + diskBufferFileExt
+)
+
+var (
+	tlmDiskBufferSpilled  = telemetry.NewCounter("logs_sender_disk_buffer", "payloads_spilled", []string{"pipeline"}, "Payloads written to the disk buffer")
+	tlmDiskBufferReplayed = telemetry.NewCounter("logs_sender_disk_buffer", "payloads_replayed", []string{"pipeline"}, "Payloads replayed from the disk buffer")
+	tlmDiskBufferEvicted  = telemetry.NewCounter("logs_sender_disk_buffer", "payloads_evicted", []string{"pipeline"}, "Payloads evicted from the disk buffer because it was full")
+	tlmDiskBufferErrors   = telemetry.NewCounter("logs_sender_disk_buffer", "errors", []string{"pipeline", "operation"}, "Errors encountered while reading or writing the disk buffer")
+	tlmDiskBufferBytes    = telemetry.NewGauge("logs_sender_disk_buffer", "bytes", []string{"pipeline"}, "Size in bytes of the payloads currently stored in the disk buffer")
+)
+
+// DiskBufferConfig holds the settings of a DiskBuffer.
+type DiskBufferConfig struct {
+	// Path is the directory where payloads are stored. It must be unique per pipeline.
+	Path string
+	// MaxSizeBytes is the maximum size the stored payloads can use on disk.
+	MaxSizeBytes int64
+	// EvictionPolicy is applied when the buffer is full, one of DiskBufferEvictionDropOldest
+	// or DiskBufferEvictionBlock.
+	EvictionPolicy string
+	// SpillTimeout is how long a payload may wait for the sender before it is written to disk.
+	SpillTimeout time.Duration
+}
+
+type diskBufferSegment struct {
+	path string
+	size int64
+}
+
+// DiskBuffer sits between a strategy and the sender. As long as the sender accepts
+// payloads it is a passthrough. When the sender is blocked for longer than the spill
+// timeout (all reliable destinations are retrying), payloads are written to disk and
+// acknowledged to the auditor, so that tailers are not stopped by backpressure.
+// Stored payloads are replayed in order once the sender accepts payloads again, and
+// new payloads keep going to disk until the backlog is drained so ordering is preserved.
+//
+// Payloads replayed from disk carry no messages: they were already acknowledged to the
+// auditor when they were written, and must not move registry offsets a second time.
+// A payload is removed from disk once it has been handed to the sender, so payloads held
+// in the sender and destination in-memory buffers can still be lost if the agent crashes.
+type DiskBuffer struct {
+	inputChan    chan *message.Payload
+	outputChan   chan *message.Payload
+	auditor      chan *message.Payload
+	config       DiskBufferConfig
+	pipelineName string
+
+	segments []diskBufferSegment
+	size     int64
+	nextID   uint64
+	head     *message.Payload
+	quit     chan struct{}
+	stopChan chan struct{}
+}
+
+// NewDiskBuffer returns a new DiskBuffer reading from inputChan and forwarding to outputChan.
+// Payloads persisted to disk are acknowledged on the auditor channel. Payloads left over by
+// a previous run in the configured path are replayed first.
+func NewDiskBuffer(inputChan chan *message.Payload, outputChan chan *message.Payload, auditor chan *message.Payload, config DiskBufferConfig, pipelineName string) (*DiskBuffer, error) {
+	if config.MaxSizeBytes <= 0 {
+		return nil, fmt.Errorf("invalid disk buffer max size: %d", config.MaxSizeBytes)
+	}
+	switch config.EvictionPolicy {
+	case DiskBufferEvictionDropOldest, DiskBufferEvictionBlock:
+	default:
+		return nil, fmt.Errorf("invalid disk buffer eviction policy: %q", config.EvictionPolicy)
+	}
+	if err := os.MkdirAll(config.Path, 0o700); err != nil {
+		return nil, fmt.Errorf("unable to create disk buffer directory %s: %w", config.Path, err)
+	}
+
+	b := &DiskBuffer{
+		inputChan:    inputChan,
+		outputChan:   outputChan,
+		auditor:      auditor,
+		config:       config,
+		pipelineName: pipelineName,
+		quit:         make(chan struct{}),
+		stopChan:     make(chan struct{}),
+	}
+	if err := b.recover(); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// Start starts the DiskBuffer.
+func (b *DiskBuffer) Start() {
+	go b.run()
+}
+
+// Stop stops the DiskBuffer, payloads still stored on disk are kept for the next run.
+// It doesn't wait for the sender: a payload that couldn't be stored because the buffer
+// is full is not acknowledged, and is tailed again on the next run.
+func (b *DiskBuffer) Stop() {
+	close(b.quit)
+	close(b.inputChan)
+	<-b.stopChan
+}
+
+func (b *DiskBuffer) run() {
+	defer close(b.stopChan)
+	for {
+		if len(b.segments) == 0 {
+			payload, isOpen := <-b.inputChan
+			if !isOpen {
+				return
+			}
+			b.forward(payload)
+			continue
+		}
+
+		next := b.peek()
+		if next == nil {
+			// the oldest segment was unreadable and has been discarded
+			continue
+		}
+		select {
+		case payload, isOpen := <-b.inputChan:
+			if !isOpen {
+				return
+			}
+			b.spill(payload)
+		case b.outputChan <- next:
+			b.pop()
+			tlmDiskBufferReplayed.Inc(b.pipelineName)
+		}
+	}
+}
+
+// forward hands the payload to the sender, spilling it to disk if the sender
+// doesn't accept it within the spill timeout.
+func (b *DiskBuffer) forward(payload *message.Payload) {
+	timer := time.NewTimer(b.config.SpillTimeout)
+	defer timer.Stop()
+	select {
+	case b.outputChan <- payload:
+	case <-timer.C:
+		b.spill(payload)
+	}
+}
+
+// spill writes the payload to disk and acknowledges it to the auditor.
+func (b *DiskBuffer) spill(payload *message.Payload) {
+	record := encodeDiskBufferRecord(payload)
+	recordSize := int64(len(record))
+	if recordSize > b.config.MaxSizeBytes {
+		log.Warnf("Payload of %d bytes does not fit in the disk buffer of pipeline %s, waiting for the sender", recordSize, b.pipelineName)
+		if b.drain() {
+			b.send(payload)
+		}
+		return
+	}
+
+	for b.size+recordSize > b.config.MaxSizeBytes {
+		if b.config.EvictionPolicy == DiskBufferEvictionBlock {
+			if !b.replayOne() {
+				return
+			}
+		} else {
+			b.evictOldest()
+		}
+	}
+
+	if err := b.write(record); err != nil {
+		log.Warnf("Unable to write payload to the disk buffer of pipeline %s, waiting for the sender: %v", b.pipelineName, err)
+		tlmDiskBufferErrors.Inc(b.pipelineName, "write")
+		if b.drain() {
+			b.send(payload)
+		}
+		return
+	}
+	tlmDiskBufferSpilled.Inc(b.pipelineName)
+
+	// the payload is durable, the tailers can move on
+	b.auditor <- payload
+}
+
+// drain replays every stored payload, blocking until the sender accepted all of them.
+// It returns false if the buffer was stopped first.
+func (b *DiskBuffer) drain() bool {
+	for len(b.segments) > 0 {
+		if !b.replayOne() {
+			return false
+		}
+	}
+	return true
+}
+
+// replayOne blocks until the oldest stored payload is accepted by the sender.
+// It returns false if the buffer was stopped first.
+func (b *DiskBuffer) replayOne() bool {
+	if next := b.peek(); next != nil {
+		if !b.send(next) {
+			return false
+		}
+		b.pop()
+		tlmDiskBufferReplayed.Inc(b.pipelineName)
+	}
+	return true
+}
+
+// send blocks until the payload is accepted by the sender, it returns false if the
+// buffer was stopped first.
+func (b *DiskBuffer) send(payload *message.Payload) bool {
+	select {
+	case b.outputChan <- payload:
+		return true
+	case <-b.quit:
+		return false
+	}
+}
+
+func (b *DiskBuffer) evictOldest() {
+	if len(b.segments) == 0 {
+		return
+	}
+	log.Debugf("Disk buffer of pipeline %s is full, evicting %s", b.pipelineName, b.segments[0].path)
+	b.pop()
+	tlmDiskBufferEvicted.Inc(b.pipelineName)
+}
+
+// peek returns the oldest stored payload, loading it from disk if needed.
+// It returns nil and discards the segment if it can't be read.
+func (b *DiskBuffer) peek() *message.Payload {
+	if b.head != nil {
+		return b.head
+	}
+	segment := b.segments[0]
+	record, err := os.ReadFile(segment.path)
+	if err == nil {
+		b.head, err = decodeDiskBufferRecord(record)
+	}
+	if err != nil {
+		log.Warnf("Discarding unreadable disk buffer file %s: %v", segment.path, err)
+		tlmDiskBufferErrors.Inc(b.pipelineName, "read")
+		b.pop()
+		return nil
+	}
+	return b.head
+}
+
+// pop removes the oldest stored payload.
+func (b *DiskBuffer) pop() {
+	segment := b.segments[0]
+	if err := os.Remove(segment.path); err != nil && !os.IsNotExist(err) {
+		log.Warnf("Unable to remove disk buffer file %s: %v", segment.path, err)
+		tlmDiskBufferErrors.Inc(b.pipelineName, "remove")
+	}
+	b.segments = b.segments[1:]
+	b.size -= segment.size
+	b.head = nil
+	tlmDiskBufferBytes.Set(float64(b.size), b.pipelineName)
+}
+
+// write durably stores a record: it is fully written and synced to a temporary
+// file before being renamed, so that a crash never leaves a partial payload behind.
+func (b *DiskBuffer) write(record []byte) error {
+	path := filepath.Join(b.config.Path, fmt.Sprintf(diskBufferFilePattern, b.nextID))
+	tmpPath := path + diskBufferTmpExt
+
+	f, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(record)
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmpPath, path)
+	}
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+
+	b.nextID++
+	b.segments = append(b.segments, diskBufferSegment{path: path, size: int64(len(record))})
+	b.size += int64(len(record))
+	tlmDiskBufferBytes.Set(float64(b.size), b.pipelineName)
+	return nil
+}
+
+// recover loads the payloads stored by a previous run.
+func (b *DiskBuffer) recover() error {
+	entries, err := os.ReadDir(b.config.Path)
+	if err != nil {
+		return fmt.Errorf("unable to read disk buffer directory %s: %w", b.config.Path, err)
+	}
+
+	ids := []uint64{}
+	sizes := map[uint64]int64{}
+	for _, entry := range entries {
+		name := entry.Name()
+		path := filepath.Join(b.config.Path, name)
+		if strings.HasSuffix(name, diskBufferTmpExt) {
+			// leftover of an interrupted write, it was never acknowledged
+			_ = os.Remove(path)
+			continue
+		}
+		if entry.IsDir() || !strings.HasSuffix(name, diskBufferFileExt) {
+			continue
+		}
+		id, err := strconv.ParseUint(strings.TrimSuffix(name, diskBufferFileExt), 10, 64)
+		if err != nil {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		ids = append(ids, id)
+		sizes[id] = info.Size()
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	for _, id := range ids {
+		path := filepath.Join(b.config.Path, fmt.Sprintf(diskBufferFilePattern, id))
+		b.segments = append(b.segments, diskBufferSegment{path: path, size: sizes[id]})
+		b.size += sizes[id]
+		b.nextID = id + 1
+	}
+	if len(b.segments) > 0 {
+		log.Infof("Recovered %d payloads (%d bytes) from the disk buffer of pipeline %s", len(b.segments), b.size, b.pipelineName)
+	}
+	tlmDiskBufferBytes.Set(float64(b.size), b.pipelineName)
+	return nil
+}
+
+// encodeDiskBufferRecord serializes the parts of a payload needed to send it again.
+func encodeDiskBufferRecord(payload *message.Payload) []byte {
+	record := make([]byte, diskBufferHeaderSize, diskBufferHeaderSize+len(payload.Encoding)+len(payload.Encoded))
+	record[0] = diskBufferFormatV1
+	binary.BigEndian.PutUint16(record[1:3], uint16(len(payload.Encoding)))
+	binary.BigEndian.PutUint64(record[3:11], uint64(payload.UnencodedSize))
+	binary.BigEndian.PutUint32(record[11:15], crc32.ChecksumIEEE(payload.Encoded))
+	record = append(record, payload.Encoding...)
+	return append(record, payload.Encoded...)
+}
+
+func decodeDiskBufferRecord(record []byte) (*message.Payload, error) {
+	if len(record) < diskBufferHeaderSize {
+		return nil, errors.New("truncated header")
+	}
+	if record[0] != diskBufferFormatV1 {
+		return nil, fmt.Errorf("unknown format version %d", record[0])
+	}
+	encodingLen := int(binary.BigEndian.Uint16(record[1:3]))
+	if len(record) < diskBufferHeaderSize+encodingLen {
+		return nil, errors.New("truncated encoding")
+	}
+	encoded := record[diskBufferHeaderSize+encodingLen:]
+	if crc32.ChecksumIEEE(encoded) != binary.BigEndian.Uint32(record[11:15]) {
+		return nil, errors.New("checksum mismatch")
+	}
+	return &message.Payload{
+		Encoded:       encoded,
+		Encoding:      string(record[diskBufferHeaderSize : diskBufferHeaderSize+encodingLen]),
+		UnencodedSize: int(binary.BigEndian.Uint64(record[3:11])),
+	}, nil
+}
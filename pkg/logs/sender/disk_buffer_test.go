@@ -0,0 +1,188 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package sender
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/logs/message"
+)
+
+func newTestDiskBuffer(t *testing.T, path string, maxSize int64, policy string) (*DiskBuffer, chan *message.Payload, chan *message.Payload, chan *message.Payload) {
+	input := make(chan *message.Payload)
+	output := make(chan *message.Payload)
+	auditor := make(chan *message.Payload, 100)
+	b, err := NewDiskBuffer(input, output, auditor, DiskBufferConfig{
+		Path:           path,
+		MaxSizeBytes:   maxSize,
+		EvictionPolicy: policy,
+		SpillTimeout:   10 * time.Millisecond,
+	}, "test")
+	require.NoError(t, err)
+	return b, input, output, auditor
+}
+
+func newTestPayload(content string) *message.Payload {
+	return &message.Payload{
+		Messages:      []*message.Message{message.NewMessage([]byte(content), nil, "", 0)},
+		Encoded:       []byte(content),
+		Encoding:      "gzip",
+		UnencodedSize: len(content),
+	}
+}
+
+func TestDiskBufferPassthrough(t *testing.T) {
+	b, input, output, auditor := newTestDiskBuffer(t, t.TempDir(), 1024, DiskBufferEvictionDropOldest)
+	b.Start()
+
+	payload := newTestPayload("a")
+	input <- payload
+	assert.Same(t, payload, <-output)
+	assert.Len(t, auditor, 0)
+
+	b.Stop()
+}
+
+func TestDiskBufferSpillAndReplayInOrder(t *testing.T) {
+	dir := t.TempDir()
+	b, input, output, auditor := newTestDiskBuffer(t, dir, 1024, DiskBufferEvictionDropOldest)
+	b.Start()
+
+	// nobody reads the output, every payload ends up on disk and is acknowledged
+	for _, content := range []string{"a", "b", "c"} {
+		input <- newTestPayload(content)
+	}
+	for _, content := range []string{"a", "b", "c"} {
+		acked := <-auditor
+		assert.Equal(t, content, string(acked.Encoded))
+		assert.Len(t, acked.Messages, 1)
+	}
+
+	for _, content := range []string{"a", "b", "c"} {
+		replayed := <-output
+		assert.Equal(t, content, string(replayed.Encoded))
+		assert.Equal(t, "gzip", replayed.Encoding)
+		assert.Equal(t, 1, replayed.UnencodedSize)
+		assert.Empty(t, replayed.Messages)
+	}
+
+	b.Stop()
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestDiskBufferDropOldest(t *testing.T) {
+	recordSize := int64(len(encodeDiskBufferRecord(newTestPayload("a"))))
+	b, input, output, auditor := newTestDiskBuffer(t, t.TempDir(), 2*recordSize, DiskBufferEvictionDropOldest)
+	b.Start()
+
+	for _, content := range []string{"a", "b", "c"} {
+		input <- newTestPayload(content)
+		<-auditor
+	}
+
+	assert.Equal(t, "b", string((<-output).Encoded))
+	assert.Equal(t, "c", string((<-output).Encoded))
+	b.Stop()
+}
+
+func TestDiskBufferBlock(t *testing.T) {
+	recordSize := int64(len(encodeDiskBufferRecord(newTestPayload("a"))))
+	b, input, output, auditor := newTestDiskBuffer(t, t.TempDir(), recordSize, DiskBufferEvictionBlock)
+	b.Start()
+
+	input <- newTestPayload("a")
+	<-auditor
+
+	// the buffer is full, "b" can only be stored once "a" is replayed
+	go func() { input <- newTestPayload("b") }()
+	assert.Equal(t, "a", string((<-output).Encoded))
+	assert.Equal(t, "b", string((<-auditor).Encoded))
+	assert.Equal(t, "b", string((<-output).Encoded))
+	b.Stop()
+}
+
+func TestDiskBufferBlockStop(t *testing.T) {
+	dir := t.TempDir()
+	recordSize := int64(len(encodeDiskBufferRecord(newTestPayload("a"))))
+	b, input, _, auditor := newTestDiskBuffer(t, dir, recordSize, DiskBufferEvictionBlock)
+	b.Start()
+
+	input <- newTestPayload("a")
+	<-auditor
+
+	// the buffer is full and nobody reads the output, "b" waits for "a" to be replayed
+	input <- newTestPayload("b")
+	stopped := make(chan struct{})
+	go func() {
+		b.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		require.Fail(t, "the disk buffer didn't stop")
+	}
+
+	// "a" is kept for the next run, "b" was never acknowledged
+	assert.Len(t, auditor, 0)
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestDiskBufferRecover(t *testing.T) {
+	dir := t.TempDir()
+	b, input, _, auditor := newTestDiskBuffer(t, dir, 1024, DiskBufferEvictionDropOldest)
+	b.Start()
+	input <- newTestPayload("a")
+	input <- newTestPayload("b")
+	<-auditor
+	<-auditor
+	b.Stop()
+
+	// a partial write and a corrupted file are ignored
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "00000000000000000002.payload.tmp"), []byte("partial"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "00000000000000000003.payload"), []byte("corrupted"), 0o600))
+
+	b, _, output, _ := newTestDiskBuffer(t, dir, 1024, DiskBufferEvictionDropOldest)
+	b.Start()
+	assert.Equal(t, "a", string((<-output).Encoded))
+	assert.Equal(t, "b", string((<-output).Encoded))
+	b.Stop()
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestDiskBufferInvalidConfig(t *testing.T) {
+	_, err := NewDiskBuffer(nil, nil, nil, DiskBufferConfig{Path: t.TempDir(), MaxSizeBytes: 0, EvictionPolicy: DiskBufferEvictionBlock}, "test")
+	assert.Error(t, err)
+	_, err = NewDiskBuffer(nil, nil, nil, DiskBufferConfig{Path: t.TempDir(), MaxSizeBytes: 10, EvictionPolicy: "drop_everything"}, "test")
+	assert.Error(t, err)
+}
+
+func TestDiskBufferRecordChecksum(t *testing.T) {
+	record := encodeDiskBufferRecord(newTestPayload("hello"))
+	payload, err := decodeDiskBufferRecord(record)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(payload.Encoded))
+
+	record[len(record)-1] = 'X'
+	_, err = decodeDiskBufferRecord(record)
+	assert.Error(t, err)
+
+	_, err = decodeDiskBufferRecord(record[:3])
+	assert.Error(t, err)
+}
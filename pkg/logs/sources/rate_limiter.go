@@ -0,0 +1,128 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package sources
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/DataDog/datadog-agent/comp/logs/agent/config"
+)
+
+// rateLimiterKey identifies the rate limiter shared by the sources of a same service with the same rate limit.
+type rateLimiterKey struct {
+	service string
+	config  config.RateLimitConfig
+}
+
+// sharedRateLimiter is a rate limiter with the number of sources using it.
+type sharedRateLimiter struct {
+	limiter *RateLimiter
+	refs    int
+}
+
+// serviceRateLimiters holds the rate limiters shared by the sources of a same service.
+var serviceRateLimiters = struct {
+	sync.Mutex
+	limiters map[rateLimiterKey]*sharedRateLimiter
+}{limiters: make(map[rateLimiterKey]*sharedRateLimiter)}
+
+// RateLimiter caps the throughput of one or several log sources with token buckets
+// refilled every second, with a burst of one second worth of logs.
+// It is safe for concurrent use, so that it can be shared by several pipelines.
+type RateLimiter struct {
+	mu     sync.Mutex
+	config config.RateLimitConfig
+	events float64
+	bytes  float64
+	last   time.Time
+	now    func() time.Time
+	random func() float64
+}
+
+// NewRateLimiter returns a new RateLimiter.
+func NewRateLimiter(cfg config.RateLimitConfig) *RateLimiter {
+	return newRateLimiterWithClock(cfg, time.Now)
+}
+
+func newRateLimiterWithClock(cfg config.RateLimitConfig, now func() time.Time) *RateLimiter {
+	return &RateLimiter{
+		config: cfg,
+		events: cfg.MaxEventsPerSecond,
+		bytes:  cfg.MaxBytesPerSecond,
+		last:   now(),
+		now:    now,
+		random: rand.Float64,
+	}
+}
+
+// getRateLimiter returns the rate limiter of a source config, or nil if it isn't rate limited.
+// Sources with a service scope share the rate limiter of the sources with the same service and rate limit,
+// the returned key must then be released with releaseRateLimiter when the source is removed.
+func getRateLimiter(cfg *config.LogsConfig) (*RateLimiter, *rateLimiterKey) {
+	if cfg == nil || cfg.RateLimit == nil {
+		return nil, nil
+	}
+	// sources without a service have nothing to share
+	if cfg.RateLimit.Scope != config.RateLimitScopeService || cfg.Service == "" {
+		return NewRateLimiter(*cfg.RateLimit), nil
+	}
+
+	key := rateLimiterKey{service: cfg.Service, config: *cfg.RateLimit}
+	serviceRateLimiters.Lock()
+	defer serviceRateLimiters.Unlock()
+	shared, found := serviceRateLimiters.limiters[key]
+	if !found {
+		shared = &sharedRateLimiter{limiter: NewRateLimiter(*cfg.RateLimit)}
+		serviceRateLimiters.limiters[key] = shared
+	}
+	shared.refs++
+	return shared.limiter, &key
+}
+
+// releaseRateLimiter releases a shared rate limiter, which is deleted once no source uses it.
+func releaseRateLimiter(key rateLimiterKey) {
+	serviceRateLimiters.Lock()
+	defer serviceRateLimiters.Unlock()
+	shared, found := serviceRateLimiters.limiters[key]
+	if !found {
+		return
+	}
+	shared.refs--
+	if shared.refs <= 0 {
+		delete(serviceRateLimiters.limiters, key)
+	}
+}
+
+// Allow returns true if a log of the given size can be sent.
+// Over the limit, logs are dropped or, in sample mode, randomly sampled
+// while the error logs are always kept.
+func (r *RateLimiter) Allow(size int, isError bool) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	elapsed := now.Sub(r.last).Seconds()
+	r.last = now
+	// the burst is at least one event so that limits under one event per second are honored
+	r.events = min(r.events+elapsed*r.config.MaxEventsPerSecond, max(r.config.MaxEventsPerSecond, 1))
+	r.bytes = min(r.bytes+elapsed*r.config.MaxBytesPerSecond, r.config.MaxBytesPerSecond)
+
+	eventsAllowed := r.config.MaxEventsPerSecond == 0 || r.events >= 1
+	// a log larger than the remaining bytes is let through, the difference is paid back by the next ones
+	bytesAllowed := r.config.MaxBytesPerSecond == 0 || r.bytes > 0
+	if eventsAllowed && bytesAllowed {
+		r.events--
+		r.bytes -= float64(size)
+		return true
+	}
+
+	if r.config.Mode == config.RateLimitSample {
+		return isError || r.random() < r.config.SampleRate
+	}
+	return false
+}
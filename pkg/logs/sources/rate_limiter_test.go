@@ -0,0 +1,126 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package sources
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DataDog/datadog-agent/comp/logs/agent/config"
+)
+
+func TestRateLimiterEvents(t *testing.T) {
+	now := time.Now()
+	limiter := newRateLimiterWithClock(config.RateLimitConfig{MaxEventsPerSecond: 2, Mode: config.RateLimitDrop}, func() time.Time { return now })
+
+	assert.True(t, limiter.Allow(10, false))
+	assert.True(t, limiter.Allow(10, false))
+	assert.False(t, limiter.Allow(10, false))
+	assert.False(t, limiter.Allow(10, true))
+
+	now = now.Add(500 * time.Millisecond)
+	assert.True(t, limiter.Allow(10, false))
+	assert.False(t, limiter.Allow(10, false))
+
+	// the burst is capped to one second
+	now = now.Add(time.Minute)
+	assert.True(t, limiter.Allow(10, false))
+	assert.True(t, limiter.Allow(10, false))
+	assert.False(t, limiter.Allow(10, false))
+}
+
+func TestRateLimiterBytes(t *testing.T) {
+	now := time.Now()
+	limiter := newRateLimiterWithClock(config.RateLimitConfig{MaxBytesPerSecond: 100, Mode: config.RateLimitDrop}, func() time.Time { return now })
+
+	assert.True(t, limiter.Allow(60, false))
+	assert.True(t, limiter.Allow(60, false))
+	assert.False(t, limiter.Allow(1, false))
+
+	// the 20 bytes over the limit are paid back first
+	now = now.Add(100 * time.Millisecond)
+	assert.False(t, limiter.Allow(1, false))
+	now = now.Add(200 * time.Millisecond)
+	assert.True(t, limiter.Allow(1, false))
+}
+
+func TestRateLimiterSample(t *testing.T) {
+	now := time.Now()
+	limiter := newRateLimiterWithClock(config.RateLimitConfig{MaxEventsPerSecond: 1, Mode: config.RateLimitSample, SampleRate: 0.5}, func() time.Time { return now })
+	random := 0.0
+	limiter.random = func() float64 { return random }
+
+	assert.True(t, limiter.Allow(1, false))
+	random = 0.7
+	assert.False(t, limiter.Allow(1, false))
+	assert.True(t, limiter.Allow(1, true))
+	random = 0.2
+	assert.True(t, limiter.Allow(1, false))
+}
+
+func TestSourceRateLimiter(t *testing.T) {
+	source := NewLogSource("", &config.LogsConfig{})
+	assert.Nil(t, source.RateLimiter)
+	assert.Nil(t, source.GetInfo("Rate Limited Logs"))
+
+	rateLimit := &config.RateLimitConfig{MaxEventsPerSecond: 1, Scope: config.RateLimitScopeSource}
+	sourceA := NewLogSource("", &config.LogsConfig{Service: "svc-source", RateLimit: rateLimit})
+	sourceB := NewLogSource("", &config.LogsConfig{Service: "svc-source", RateLimit: rateLimit})
+	assert.NotNil(t, sourceA.RateLimiter)
+	assert.NotSame(t, sourceA.RateLimiter, sourceB.RateLimiter)
+	assert.NotNil(t, sourceA.GetInfo("Rate Limited Logs"))
+
+	rateLimit = &config.RateLimitConfig{MaxEventsPerSecond: 1, Scope: config.RateLimitScopeService}
+	sourceA = NewLogSource("", &config.LogsConfig{Service: "svc-service", RateLimit: rateLimit})
+	sourceB = NewLogSource("", &config.LogsConfig{Service: "svc-service", RateLimit: rateLimit})
+	assert.Same(t, sourceA.RateLimiter, sourceB.RateLimiter)
+
+	parent := NewLogSource("", &config.LogsConfig{RateLimit: rateLimit})
+	child := NewLogSource("", &config.LogsConfig{RateLimit: rateLimit})
+	child.ParentSource = parent
+	child.RecordRateLimited()
+	assert.Equal(t, int64(1), child.RateLimited.Get())
+	assert.Equal(t, int64(1), parent.RateLimited.Get())
+}
+
+func TestSourceRateLimiterSharing(t *testing.T) {
+	rateLimit := &config.RateLimitConfig{MaxEventsPerSecond: 1, Scope: config.RateLimitScopeService}
+
+	// sources without a service don't share their rate limiter
+	sourceA := NewLogSource("", &config.LogsConfig{RateLimit: rateLimit})
+	sourceB := NewLogSource("", &config.LogsConfig{RateLimit: rateLimit})
+	assert.NotSame(t, sourceA.RateLimiter, sourceB.RateLimiter)
+
+	// a changed rate limit gets a new rate limiter
+	sourceA = NewLogSource("", &config.LogsConfig{Service: "svc-changed", RateLimit: rateLimit})
+	sourceB = NewLogSource("", &config.LogsConfig{Service: "svc-changed", RateLimit: &config.RateLimitConfig{MaxEventsPerSecond: 2, Scope: config.RateLimitScopeService}})
+	assert.NotSame(t, sourceA.RateLimiter, sourceB.RateLimiter)
+	assert.Equal(t, 2.0, sourceB.RateLimiter.config.MaxEventsPerSecond)
+
+	// the rate limiter is released once all its sources are removed
+	sources := NewLogSources()
+	sourceA = NewLogSource("", &config.LogsConfig{Type: "file", Service: "svc-removed", RateLimit: rateLimit})
+	sourceB = NewLogSource("", &config.LogsConfig{Type: "file", Service: "svc-removed", RateLimit: rateLimit})
+	sources.AddSource(sourceA)
+	sources.AddSource(sourceB)
+	key := rateLimiterKey{service: "svc-removed", config: *rateLimit}
+	sources.RemoveSource(sourceA)
+	assert.Contains(t, serviceRateLimiters.limiters, key)
+	sources.RemoveSource(sourceB)
+	assert.NotContains(t, serviceRateLimiters.limiters, key)
+	sourceC := NewLogSource("", &config.LogsConfig{Type: "file", Service: "svc-removed", RateLimit: rateLimit})
+	assert.NotSame(t, sourceA.RateLimiter, sourceC.RateLimiter)
+
+	// children share the rate limiter of their parent
+	parent := NewLogSource("", &config.LogsConfig{RateLimit: &config.RateLimitConfig{MaxEventsPerSecond: 1, Scope: config.RateLimitScopeSource}})
+	child := NewLogSource("", &config.LogsConfig{})
+	child.InheritRateLimit(parent)
+	assert.Same(t, parent.RateLimiter, child.RateLimiter)
+	assert.Equal(t, parent.Config.RateLimit, child.Config.RateLimit)
+	assert.NotNil(t, child.GetInfo("Rate Limited Logs"))
+}
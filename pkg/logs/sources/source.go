@@ -48,8 +48,14 @@ type LogSource struct {
 	ParentSource *LogSource
 	// LatencyStats tracks internal stats on the time spent by messages from this source in a processing pipeline, i.e.
 	// the duration between when a message is decoded by the tailer/listener/decoder and when the message is handled by a sender
-	LatencyStats     *statstracker.Tracker
-	BytesRead        *status.CountInfo
+	LatencyStats *statstracker.Tracker
+	BytesRead    *status.CountInfo
+	// RateLimiter caps the throughput of the source, it is nil when the source isn't rate limited
+	RateLimiter *RateLimiter
+	// RateLimited counts the logs dropped by the rate limiter
+	RateLimited *status.CountInfo
+	// rateLimiterKey is the key of the shared rate limiter of the source, to release it when the source is removed
+	rateLimiterKey   *rateLimiterKey
 	hiddenFromStatus bool
 }
 
@@ -65,10 +71,15 @@ func NewLogSource(name string, cfg *config.LogsConfig) *LogSource {
 		BytesRead:        status.NewCountInfo("Bytes Read"),
 		info:             status.NewInfoRegistry(),
 		LatencyStats:     statstracker.NewTracker(time.Hour*24, time.Hour),
+		RateLimited:      status.NewCountInfo("Rate Limited Logs"),
 		hiddenFromStatus: false,
 	}
+	source.RateLimiter, source.rateLimiterKey = getRateLimiter(cfg)
 	source.RegisterInfo(source.BytesRead)
 	source.RegisterInfo(source.LatencyStats)
+	if source.RateLimiter != nil {
+		source.RegisterInfo(source.RateLimited)
+	}
 	return source
 }
 
@@ -159,6 +170,37 @@ func (s *LogSource) RecordBytes(n int64) {
 	}
 }
 
+// InheritRateLimit makes a source created for its parent, like the file source of a container, share the rate
+// limit of the parent, so that the logs of all its children are limited together.
+func (s *LogSource) InheritRateLimit(parent *LogSource) {
+	if parent.RateLimiter == nil {
+		return
+	}
+	s.Config.RateLimit = parent.Config.RateLimit
+	s.RateLimiter = parent.RateLimiter
+	s.RegisterInfo(s.RateLimited)
+}
+
+// releaseRateLimiter releases the rate limiter shared with the other sources of the service, if any.
+func (s *LogSource) releaseRateLimiter() {
+	s.lock.Lock()
+	key := s.rateLimiterKey
+	s.rateLimiterKey = nil
+	s.lock.Unlock()
+	if key != nil {
+		releaseRateLimiter(*key)
+	}
+}
+
+// RecordRateLimited reports a log dropped by the rate limiter to the source status,
+// and to the parent source used to populate the status page if any.
+func (s *LogSource) RecordRateLimited() {
+	s.RateLimited.Add(1)
+	if s.ParentSource != nil {
+		s.ParentSource.RateLimited.Add(1)
+	}
+}
+
 // Dump provides a dump of the LogSource contents, for debugging purposes.  If
 // multiline is true, the result contains newlines for readability.
 func (s *LogSource) Dump(multiline bool) string {
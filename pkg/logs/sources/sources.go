@@ -90,6 +90,7 @@ func (s *LogSources) RemoveSource(source *LogSource) {
 		for _, stream := range streamsForType {
 			stream <- source
 		}
+		source.releaseRateLimiter()
 	}
 }
 
@@ -0,0 +1,221 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package file
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"path/filepath"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/DataDog/datadog-agent/pkg/logs/internal/decoder"
+	"github.com/DataDog/datadog-agent/pkg/logs/message"
+	"github.com/DataDog/datadog-agent/pkg/util/filesystem"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// Supported archive compressions
+const (
+	GzipCompression = "gzip"
+	ZstdCompression = "zstd"
+)
+
+// ArchiveDoneOffset is the offset registered for an archive once it has been read until the end,
+// so that it is never read again. It is registered even if no message was sent for the end of the
+// archive, e.g. when the archive is empty or when its last lines are filtered out. The messages of
+// the archive which are still in the pipeline are lost if the agent crashes.
+const ArchiveDoneOffset = "done"
+
+// archiveHeaderSize is the number of bytes of an archive used to identify it.
+const archiveHeaderSize = 4096
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// Archive is a compressed file, which is decompressed and read once from the beginning to the end
+// instead of being tailed.
+type Archive struct {
+	// Compression is the compression algorithm of the archive.
+	Compression string
+	// Identifier identifies the archive in the registry. It is computed from the content of the
+	// archive so that an archive renamed by a log rotation is not read again.
+	Identifier string
+}
+
+// IsArchive returns true if the name of the file matches one of the archive patterns.
+func IsArchive(path string, patterns []string) bool {
+	name := filepath.Base(path)
+	for _, pattern := range patterns {
+		if matched, _ := filepath.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// NewArchive returns the Archive of the file at path, detecting its compression from its header.
+func NewArchive(path string) (*Archive, error) {
+	f, err := filesystem.OpenShared(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	header := make([]byte, archiveHeaderSize)
+	n, err := io.ReadFull(f, header)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+	header = header[:n]
+
+	var compression string
+	switch {
+	case bytes.HasPrefix(header, gzipMagic):
+		compression = GzipCompression
+	case bytes.HasPrefix(header, zstdMagic):
+		compression = ZstdCompression
+	default:
+		return nil, fmt.Errorf("unsupported compression for archive %s, only gzip and zstd are supported", path)
+	}
+
+	stat, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	h := fnv.New64a()
+	_, _ = h.Write(header)
+	return &Archive{
+		Compression: compression,
+		Identifier:  fmt.Sprintf("archive:%x-%d", h.Sum64(), stat.Size()),
+	}, nil
+}
+
+// IsArchive returns true if the tailer reads an archive.
+func (t *Tailer) IsArchive() bool {
+	return t.archive != nil
+}
+
+// IsArchiveDone returns true if the tailer has read its archive until the end.
+func (t *Tailer) IsArchiveDone() bool {
+	return t.archiveDone.Load()
+}
+
+// setupArchive opens the archive and skips the decompressed bytes which were already sent.
+func (t *Tailer) setupArchive(offset int64) error {
+	fullpath, err := filepath.Abs(t.file.Path)
+	if err != nil {
+		return err
+	}
+	t.fullpath = fullpath
+
+	// adds metadata to enable users to filter logs by filename
+	t.tags = t.buildTailerTags()
+
+	// unlike plain files, archives are kept open on all platforms until they are read
+	log.Info("Opening archive", t.file.Path, "for tailer key", t.file.GetScanKey())
+	t.osFile, err = filesystem.OpenShared(fullpath)
+	if err != nil {
+		return err
+	}
+
+	switch t.archive.Compression {
+	case GzipCompression:
+		var r *gzip.Reader
+		if r, err = gzip.NewReader(t.osFile); err == nil {
+			t.archiveReader = r
+		}
+	case ZstdCompression:
+		var r *zstd.Decoder
+		if r, err = zstd.NewReader(t.osFile, zstd.WithDecoderConcurrency(1)); err == nil {
+			t.archiveReader = r.IOReadCloser()
+		}
+	default:
+		err = fmt.Errorf("unsupported compression %q", t.archive.Compression)
+	}
+	if err == nil && offset > 0 {
+		_, err = io.CopyN(io.Discard, t.archiveReader, offset)
+	}
+	if err != nil {
+		if t.archiveReader != nil {
+			t.archiveReader.Close()
+		}
+		t.osFile.Close()
+		return fmt.Errorf("could not read archive %s: %v", t.file.Path, err)
+	}
+
+	t.lastReadOffset.Store(offset)
+	t.decodedOffset.Store(offset)
+	return nil
+}
+
+// registerArchiveDone sends the completion of the archive to the auditor. The registered offset
+// is more recent than the offsets of the messages of the archive, so they don't override it.
+func (t *Tailer) registerArchiveDone() {
+	if t.auditorChan == nil {
+		return
+	}
+	origin := message.NewOrigin(t.file.Source.UnderlyingSource())
+	origin.Identifier = t.Identifier()
+	origin.Offset = ArchiveDoneOffset
+	payload := &message.Payload{
+		Messages: []*message.Message{message.NewMessage(nil, origin, "", time.Now().UnixNano())},
+	}
+	select {
+	case t.auditorChan <- payload:
+	case <-t.forwardContext.Done():
+	}
+}
+
+// readArchive decompresses the archive and passes its content to the decoder,
+// until the end of the archive is reached or the tailer is stopped.
+func (t *Tailer) readArchive() {
+	defer func() {
+		t.archiveReader.Close()
+		t.osFile.Close()
+		t.decoder.Stop()
+		log.Info("Closed archive", t.file.Path, "for tailer key", t.file.GetScanKey(), "read", t.Source().BytesRead.Get(), "bytes and", t.decoder.GetLineCount(), "lines")
+	}()
+
+	last := byte('\n')
+	for {
+		select {
+		case <-t.stop:
+			return
+		default:
+		}
+
+		inBuf := make([]byte, 4096)
+		n, err := t.archiveReader.Read(inBuf)
+		if n > 0 {
+			last = inBuf[n-1]
+			t.lastReadOffset.Add(int64(n))
+			t.decoder.InputChan <- decoder.NewInput(inBuf[:n])
+			t.recordBytes(int64(n))
+			t.movingSum.Add(int64(n))
+		}
+		if errors.Is(err, io.EOF) {
+			// the decoder only sends complete lines, terminate the last one
+			if last != '\n' && t.file.Source.Config().Encoding == "" {
+				t.decoder.InputChan <- decoder.NewInput([]byte{'\n'})
+			}
+			t.archiveDone.Store(true)
+			return
+		}
+		if err != nil {
+			t.file.Source.Status().Error(fmt.Errorf("could not read archive %s: %v", t.file.Path, err))
+			log.Warnf("Could not read archive %s: %v", t.file.Path, err)
+			return
+		}
+	}
+}
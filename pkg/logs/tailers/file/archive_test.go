@@ -0,0 +1,207 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package file
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/comp/logs/agent/config"
+	"github.com/DataDog/datadog-agent/pkg/logs/internal/decoder"
+	"github.com/DataDog/datadog-agent/pkg/logs/message"
+	"github.com/DataDog/datadog-agent/pkg/logs/metrics"
+	"github.com/DataDog/datadog-agent/pkg/logs/sources"
+	status "github.com/DataDog/datadog-agent/pkg/logs/status/utils"
+)
+
+func writeArchive(t *testing.T, path string, compression string, content string) {
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	switch compression {
+	case GzipCompression:
+		w = gzip.NewWriter(&buf)
+	case ZstdCompression:
+		var err error
+		w, err = zstd.NewWriter(&buf)
+		require.NoError(t, err)
+	}
+	_, err := w.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0o644))
+}
+
+func newArchiveTailer(t *testing.T, path string, lines int) (*Tailer, chan *message.Message, chan *message.Payload) {
+	archive, err := NewArchive(path)
+	require.NoError(t, err)
+
+	source := sources.NewReplaceableSource(sources.NewLogSource("", &config.LogsConfig{
+		Type:            config.FileType,
+		Path:            path,
+		ArchivePatterns: []string{"*.gz", "*.zst"},
+	}))
+	info := status.NewInfoRegistry()
+	outputChan := make(chan *message.Message, lines)
+	auditorChan := make(chan *message.Payload, 1)
+	return NewTailer(&TailerOptions{
+		OutputChan:      outputChan,
+		File:            NewFile(path, source.UnderlyingSource(), false),
+		SleepDuration:   10 * time.Millisecond,
+		Decoder:         decoder.NewDecoderFromSource(source, info),
+		Info:            info,
+		Archive:         archive,
+		AuditorChan:     auditorChan,
+		PipelineMonitor: metrics.NewNoopPipelineMonitor(""),
+	}), outputChan, auditorChan
+}
+
+// assertArchiveDone asserts that the completion of the archive was registered after the given messages.
+func assertArchiveDone(t *testing.T, tailer *Tailer, auditorChan chan *message.Payload, msgs ...*message.Message) {
+	var payload *message.Payload
+	select {
+	case payload = <-auditorChan:
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "the archive was not registered as done")
+	}
+	require.Len(t, payload.Messages, 1)
+	done := payload.Messages[0]
+	assert.Equal(t, tailer.Identifier(), done.Origin.Identifier)
+	assert.Equal(t, ArchiveDoneOffset, done.Origin.Offset)
+	for _, msg := range msgs {
+		assert.Greater(t, done.IngestionTimestamp, msg.IngestionTimestamp)
+	}
+	assert.True(t, tailer.IsArchiveDone())
+}
+
+func TestIsArchive(t *testing.T) {
+	patterns := []string{"*.gz", "app.log.*.zst"}
+	assert.True(t, IsArchive("/var/log/app.log.1.gz", patterns))
+	assert.True(t, IsArchive("/var/log/app.log.2.zst", patterns))
+	assert.False(t, IsArchive("/var/log/app.log", patterns))
+	assert.False(t, IsArchive("/var/log/other.log.2.zst", patterns))
+	assert.False(t, IsArchive("/var/log/app.log.1.gz", nil))
+}
+
+func TestNewArchive(t *testing.T) {
+	dir := t.TempDir()
+
+	gzipPath := filepath.Join(dir, "app.log.1.gz")
+	writeArchive(t, gzipPath, GzipCompression, "hello\n")
+	archive, err := NewArchive(gzipPath)
+	require.NoError(t, err)
+	assert.Equal(t, GzipCompression, archive.Compression)
+
+	// the identifier only depends on the content of the archive
+	renamedPath := filepath.Join(dir, "app.log.2.gz")
+	require.NoError(t, os.Rename(gzipPath, renamedPath))
+	renamed, err := NewArchive(renamedPath)
+	require.NoError(t, err)
+	assert.Equal(t, archive.Identifier, renamed.Identifier)
+
+	// the compression is detected from the content, not from the extension
+	zstdPath := filepath.Join(dir, "app.log.3.gz")
+	writeArchive(t, zstdPath, ZstdCompression, "hello\n")
+	archive, err = NewArchive(zstdPath)
+	require.NoError(t, err)
+	assert.Equal(t, ZstdCompression, archive.Compression)
+	assert.NotEqual(t, renamed.Identifier, archive.Identifier)
+
+	plainPath := filepath.Join(dir, "app.log")
+	require.NoError(t, os.WriteFile(plainPath, []byte("hello\n"), 0o644))
+	_, err = NewArchive(plainPath)
+	assert.Error(t, err)
+}
+
+func TestTailArchive(t *testing.T) {
+	for _, compression := range []string{GzipCompression, ZstdCompression} {
+		t.Run(compression, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "app.log.1.gz")
+			// the last line is not terminated
+			writeArchive(t, path, compression, "first\nsecond\nthird")
+
+			tailer, outputChan, auditorChan := newArchiveTailer(t, path, 3)
+			require.NoError(t, tailer.StartFromBeginning())
+
+			first := <-outputChan
+			assert.Equal(t, "first", string(first.GetContent()))
+			assert.Equal(t, "6", first.Origin.Offset)
+			assert.Equal(t, tailer.Identifier(), first.Origin.Identifier)
+			assert.Equal(t, "second", string((<-outputChan).GetContent()))
+			third := <-outputChan
+			assert.Equal(t, "third", string(third.GetContent()))
+			assert.Equal(t, "19", third.Origin.Offset)
+
+			assert.Eventually(t, tailer.IsFinished, 5*time.Second, 10*time.Millisecond)
+			assertArchiveDone(t, tailer, auditorChan, first, third)
+		})
+	}
+}
+
+func TestTailArchiveFromOffset(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log.1.gz")
+	writeArchive(t, path, GzipCompression, "first\nsecond\nthird\n")
+
+	tailer, outputChan, _ := newArchiveTailer(t, path, 2)
+	require.NoError(t, tailer.Start(6, io.SeekStart))
+
+	assert.Equal(t, "second", string((<-outputChan).GetContent()))
+	assert.Equal(t, "third", string((<-outputChan).GetContent()))
+	assert.Eventually(t, tailer.IsFinished, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestTailCorruptedArchive(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log.1.gz")
+	writeArchive(t, path, GzipCompression, "first\nsecond\nthird\n")
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, content[:len(content)-8], 0o644))
+
+	tailer, _, auditorChan := newArchiveTailer(t, path, 3)
+	require.NoError(t, tailer.StartFromBeginning())
+
+	assert.Eventually(t, tailer.IsFinished, 5*time.Second, 10*time.Millisecond)
+	assert.False(t, tailer.IsArchiveDone())
+	assert.Len(t, auditorChan, 0)
+}
+
+func TestTailEmptyArchive(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log.1.gz")
+	writeArchive(t, path, GzipCompression, "")
+
+	tailer, outputChan, auditorChan := newArchiveTailer(t, path, 1)
+	require.NoError(t, tailer.StartFromBeginning())
+
+	assert.Eventually(t, tailer.IsFinished, 5*time.Second, 10*time.Millisecond)
+	assertArchiveDone(t, tailer, auditorChan)
+	assert.Len(t, outputChan, 0)
+}
+
+func TestTailArchiveFilteredLastLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log.1.gz")
+	writeArchive(t, path, GzipCompression, "first\nexcluded\n\n")
+
+	tailer, outputChan, auditorChan := newArchiveTailer(t, path, 2)
+	require.NoError(t, tailer.StartFromBeginning())
+
+	// the last message is dropped by the pipeline, it never reaches the auditor
+	first := <-outputChan
+	assert.Equal(t, "first", string(first.GetContent()))
+	excluded := <-outputChan
+	assert.Equal(t, "excluded", string(excluded.GetContent()))
+
+	assert.Eventually(t, tailer.IsFinished, 5*time.Second, 10*time.Millisecond)
+	assertArchiveDone(t, tailer, auditorChan, first, excluded)
+	assert.Len(t, outputChan, 0)
+}
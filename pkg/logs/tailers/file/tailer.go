@@ -99,6 +99,18 @@ type Tailer struct {
 	// didFileRotate is true when we are tailing a file after it has been rotated
 	didFileRotate *atomic.Bool
 
+	// archive is set when the file is a compressed archive, read once instead of being tailed.
+	archive *Archive
+
+	// archiveReader decompresses the content of osFile when reading an archive.
+	archiveReader io.ReadCloser
+
+	// archiveDone is true when the archive has been read until the end.
+	archiveDone *atomic.Bool
+
+	// auditorChan receives the completion of the archive, once it has been read until the end.
+	auditorChan chan *message.Payload
+
 	// stop is monitored by the readForever component, and causes it to stop reading
 	// and close the channel to the decoder.
 	stop chan struct{}
@@ -130,6 +142,8 @@ type TailerOptions struct {
 	Decoder         *decoder.Decoder        // Required
 	Info            *status.InfoRegistry    // Required
 	Rotated         bool                    // Optional
+	Archive         *Archive                // Optional
+	AuditorChan     chan *message.Payload   // Optional
 	TagAdder        tag.EntityTagAdder      // Required
 	PipelineMonitor metrics.PipelineMonitor // Required
 }
@@ -181,6 +195,9 @@ func NewTailer(opts *TailerOptions) *Tailer {
 		stopForward:            stopForward,
 		isFinished:             atomic.NewBool(false),
 		didFileRotate:          atomic.NewBool(false),
+		archive:                opts.Archive,
+		archiveDone:            atomic.NewBool(false),
+		auditorChan:            opts.AuditorChan,
 		info:                   opts.Info,
 		bytesRead:              bytesRead,
 		movingSum:              movingSum,
@@ -227,12 +244,22 @@ func (t *Tailer) Identifier() string {
 	//
 	// This is the identifier used in the registry, so changing it will invalidate existing
 	// registry entries on upgrade.
+	if t.archive != nil {
+		return t.archive.Identifier
+	}
 	return fmt.Sprintf("file:%s", t.file.Path)
 }
 
 // Start begins the tailer's operation in a dedicated goroutine.
+//
+// Archives are always read from the given offset of their decompressed content.
 func (t *Tailer) Start(offset int64, whence int) error {
-	err := t.setup(offset, whence)
+	var err error
+	if t.archive != nil {
+		err = t.setupArchive(offset)
+	} else {
+		err = t.setup(offset, whence)
+	}
 	if err != nil {
 		t.file.Source.Status().Error(err)
 		return err
@@ -242,7 +269,11 @@ func (t *Tailer) Start(offset int64, whence int) error {
 
 	go t.forwardMessages()
 	t.decoder.Start()
-	go t.readForever()
+	if t.archive != nil {
+		go t.readArchive()
+	} else {
+		go t.readForever()
+	}
 
 	return nil
 }
@@ -342,6 +373,13 @@ func (t *Tailer) forwardMessages() {
 		t.isFinished.Store(true)
 		close(t.done)
 	}()
+
+	defer func() {
+		if t.archiveDone.Load() {
+			t.registerArchiveDone()
+		}
+	}()
+
 	for output := range t.decoder.OutputChan {
 		offset := t.decodedOffset.Load() + int64(output.RawDataLen)
 		identifier := t.Identifier()
@@ -365,15 +403,20 @@ func (t *Tailer) forwardMessages() {
 		}
 
 		msg := message.NewMessage(output.GetContent(), origin, output.Status, output.IngestionTimestamp)
-		// Make the write to the output chan cancellable to be able to stop the tailer
-		// after a file rotation when it is stuck on it.
-		// We don't return directly to keep the same shutdown sequence that in the
-		// normal case.
-		select {
-		case t.outputChan <- msg:
-			t.PipelineMonitor.ReportComponentIngress(msg, "processor")
-		case <-t.forwardContext.Done():
-		}
+		t.forwardMessage(msg)
+	}
+}
+
+// forwardMessage sends a message to the output channel.
+func (t *Tailer) forwardMessage(msg *message.Message) {
+	// Make the write to the output chan cancellable to be able to stop the tailer
+	// after a file rotation when it is stuck on it.
+	// We don't return directly to keep the same shutdown sequence that in the
+	// normal case.
+	select {
+	case t.outputChan <- msg:
+		t.PipelineMonitor.ReportComponentIngress(msg, "processor")
+	case <-t.forwardContext.Done():
 	}
 }
 
@@ -0,0 +1,172 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package anomaly
+
+import (
+	"net/netip"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/network"
+)
+
+// Type is the kind of an anomaly
+type Type string
+
+const (
+	// RetransmitSpike is a connection retransmitting a large share of its packets during an interval
+	RetransmitSpike Type = "retransmit_spike"
+	// RTTIncrease is a connection whose round-trip time jumped above its baseline
+	RTTIncrease Type = "rtt_increase"
+)
+
+const (
+	// baselineWeight is the weight of the last interval in the baselines, which are exponentially weighted moving
+	// averages
+	baselineWeight = 0.2
+	// minBaselineSamples is the number of intervals with traffic a connection needs before its RTT is compared to
+	// its baseline
+	minBaselineSamples = 3
+)
+
+// Anomaly is an abnormal behavior of a TCP connection during an interval
+type Anomaly struct {
+	Type      Type      `json:"type"`
+	Timestamp time.Time `json:"timestamp"`
+
+	Source      netip.AddrPort `json:"source"`
+	Dest        netip.AddrPort `json:"dest"`
+	Direction   string         `json:"direction"`
+	PID         uint32         `json:"pid"`
+	ContainerID string         `json:"container_id,omitempty"`
+	// Tags are the tags of the process of the connection, like its service
+	Tags []string `json:"tags,omitempty"`
+
+	// Value is the retransmit rate of the interval for the retransmit spikes, or the RTT in milliseconds for the
+	// RTT increases
+	Value float64 `json:"value"`
+	// Baseline is the value the connection usually has
+	Baseline float64 `json:"baseline"`
+
+	Retransmits uint32 `json:"retransmits,omitempty"`
+	SentPackets uint64 `json:"sent_packets,omitempty"`
+}
+
+// Thresholds configure the detection of the anomalies
+type Thresholds struct {
+	// RetransmitRate is the share of the packets sent during an interval which are retransmitted, above which the
+	// connection is anomalous
+	RetransmitRate float64
+	// MinRetransmits is the number of retransmits during an interval below which the retransmit rate is ignored
+	MinRetransmits uint32
+	// RTTIncreaseFactor is the ratio of the RTT to its baseline above which the connection is anomalous
+	RTTIncreaseFactor float64
+	// RTTMinIncrease is the increase of the RTT over its baseline below which the RTT is ignored
+	RTTMinIncrease time.Duration
+}
+
+// baseline holds the usual behavior of a connection
+type baseline struct {
+	// rtt is the usual RTT, in microseconds
+	rtt            float64
+	retransmitRate float64
+	samples        int
+}
+
+// Detector detects the anomalies of the TCP connections, by comparing their behavior during each interval to their
+// thresholds and baselines
+type Detector struct {
+	thresholds Thresholds
+	baselines  map[network.StatCookie]*baseline
+}
+
+// NewDetector returns a Detector using the given thresholds
+func NewDetector(thresholds Thresholds) *Detector {
+	return &Detector{
+		thresholds: thresholds,
+		baselines:  make(map[network.StatCookie]*baseline),
+	}
+}
+
+// Detect returns the anomalies of the connections given, which are the connections of the last interval, and updates
+// their baselines. The baselines of the connections which are gone are dropped.
+func (d *Detector) Detect(conns []network.ConnectionStats, now time.Time) []Anomaly {
+	var anomalies []Anomaly
+	seen := make(map[network.StatCookie]struct{}, len(conns))
+	for i := range conns {
+		c := &conns[i]
+		if c.Type != network.TCP {
+			continue
+		}
+		seen[c.Cookie] = struct{}{}
+		// The RTT of the connections without traffic during the interval is stale
+		if c.Last.SentPackets == 0 && c.Last.RecvPackets == 0 {
+			continue
+		}
+
+		b, ok := d.baselines[c.Cookie]
+		if !ok {
+			b = &baseline{}
+			d.baselines[c.Cookie] = b
+		}
+
+		retransmitRate := 0.0
+		if c.Last.SentPackets > 0 {
+			retransmitRate = float64(c.Last.Retransmits) / float64(c.Last.SentPackets)
+		}
+		if c.Last.Retransmits >= d.thresholds.MinRetransmits && retransmitRate >= d.thresholds.RetransmitRate {
+			a := newAnomaly(RetransmitSpike, c, now)
+			a.Value = retransmitRate
+			a.Baseline = b.retransmitRate
+			a.Retransmits = c.Last.Retransmits
+			a.SentPackets = c.Last.SentPackets
+			anomalies = append(anomalies, a)
+		}
+
+		rtt := float64(c.RTT)
+		if b.samples >= minBaselineSamples && rtt >= b.rtt*d.thresholds.RTTIncreaseFactor &&
+			rtt-b.rtt >= float64(d.thresholds.RTTMinIncrease.Microseconds()) {
+			a := newAnomaly(RTTIncrease, c, now)
+			a.Value = rtt / 1000
+			a.Baseline = b.rtt / 1000
+			anomalies = append(anomalies, a)
+		}
+
+		if b.samples == 0 {
+			b.rtt, b.retransmitRate = rtt, retransmitRate
+		} else {
+			b.rtt += baselineWeight * (rtt - b.rtt)
+			b.retransmitRate += baselineWeight * (retransmitRate - b.retransmitRate)
+		}
+		b.samples++
+	}
+
+	for cookie := range d.baselines {
+		if _, ok := seen[cookie]; !ok {
+			delete(d.baselines, cookie)
+		}
+	}
+	return anomalies
+}
+
+func newAnomaly(t Type, c *network.ConnectionStats, now time.Time) Anomaly {
+	a := Anomaly{
+		Type:      t,
+		Timestamp: now,
+		Source:    netip.AddrPortFrom(c.Source.Addr.Unmap(), c.SPort),
+		Dest:      netip.AddrPortFrom(c.Dest.Addr.Unmap(), c.DPort),
+		Direction: c.Direction.String(),
+		PID:       c.Pid,
+	}
+	if c.ContainerID.Source != nil {
+		a.ContainerID, _ = c.ContainerID.Source.Get().(string)
+	}
+	for _, tag := range c.Tags {
+		if s, ok := tag.Get().(string); ok {
+			a.Tags = append(a.Tags, s)
+		}
+	}
+	return a
+}
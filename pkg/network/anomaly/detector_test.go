@@ -0,0 +1,114 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package anomaly
+
+import (
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go4.org/intern"
+
+	"github.com/DataDog/datadog-agent/pkg/network"
+	"github.com/DataDog/datadog-agent/pkg/process/util"
+)
+
+var testThresholds = Thresholds{
+	RetransmitRate:    0.05,
+	MinRetransmits:    10,
+	RTTIncreaseFactor: 2,
+	RTTMinIncrease:    20 * time.Millisecond,
+}
+
+func testConn(cookie network.StatCookie, sent uint64, retransmits uint32, rtt time.Duration) network.ConnectionStats {
+	return network.ConnectionStats{
+		ConnectionTuple: network.ConnectionTuple{
+			Source:    util.AddressFromString("10.0.0.1"),
+			Dest:      util.AddressFromString("10.0.0.2"),
+			SPort:     40000 + uint16(cookie),
+			DPort:     6379,
+			Pid:       42,
+			Type:      network.TCP,
+			Direction: network.OUTGOING,
+		},
+		Last:   network.StatCounters{SentPackets: sent, RecvPackets: sent, Retransmits: retransmits},
+		Cookie: cookie,
+		RTT:    uint32(rtt.Microseconds()),
+	}
+}
+
+func TestRetransmitSpike(t *testing.T) {
+	d := NewDetector(testThresholds)
+	now := time.Now()
+
+	conn := testConn(1, 1000, 5, time.Millisecond)
+	conn.ContainerID.Source = intern.GetByString("abc123")
+	conn.Tags = []*intern.Value{intern.GetByString("service:redis")}
+	assert.Empty(t, d.Detect([]network.ConnectionStats{conn}, now))
+
+	// Too few retransmits, despite the rate
+	conn.Last = network.StatCounters{SentPackets: 20, RecvPackets: 20, Retransmits: 5}
+	assert.Empty(t, d.Detect([]network.ConnectionStats{conn}, now))
+
+	conn.Last = network.StatCounters{SentPackets: 100, RecvPackets: 100, Retransmits: 30}
+	anomalies := d.Detect([]network.ConnectionStats{conn}, now)
+	require.Len(t, anomalies, 1)
+	a := anomalies[0]
+	assert.Equal(t, RetransmitSpike, a.Type)
+	assert.Equal(t, now, a.Timestamp)
+	assert.Equal(t, netip.MustParseAddrPort("10.0.0.1:40001"), a.Source)
+	assert.Equal(t, netip.MustParseAddrPort("10.0.0.2:6379"), a.Dest)
+	assert.Equal(t, "outgoing", a.Direction)
+	assert.Equal(t, uint32(42), a.PID)
+	assert.Equal(t, "abc123", a.ContainerID)
+	assert.Equal(t, []string{"service:redis"}, a.Tags)
+	assert.Equal(t, 0.3, a.Value)
+	assert.InDelta(t, 0.054, a.Baseline, 0.0001)
+	assert.Equal(t, uint32(30), a.Retransmits)
+	assert.Equal(t, uint64(100), a.SentPackets)
+}
+
+func TestRTTIncrease(t *testing.T) {
+	d := NewDetector(testThresholds)
+	now := time.Now()
+
+	// The RTT is not compared before the baseline has enough samples
+	for _, rtt := range []time.Duration{10 * time.Millisecond, 100 * time.Millisecond, 10 * time.Millisecond} {
+		assert.Empty(t, d.Detect([]network.ConnectionStats{testConn(1, 10, 0, rtt)}, now))
+	}
+	baseline := d.baselines[1].rtt
+
+	// A small increase is ignored, even when the RTT doubles
+	d.baselines[1].rtt = 5000
+	assert.Empty(t, d.Detect([]network.ConnectionStats{testConn(1, 10, 0, 15*time.Millisecond)}, now))
+
+	d.baselines[1].rtt = baseline
+	anomalies := d.Detect([]network.ConnectionStats{testConn(1, 10, 0, 200*time.Millisecond)}, now)
+	require.Len(t, anomalies, 1)
+	assert.Equal(t, RTTIncrease, anomalies[0].Type)
+	assert.Equal(t, 200.0, anomalies[0].Value)
+	assert.InDelta(t, baseline/1000, anomalies[0].Baseline, 0.001)
+}
+
+func TestDetectSkipsConnections(t *testing.T) {
+	d := NewDetector(testThresholds)
+	now := time.Now()
+
+	udp := testConn(1, 100, 50, time.Millisecond)
+	udp.Type = network.UDP
+	idle := testConn(2, 0, 0, time.Second)
+	idle.Last.RecvPackets = 0
+	assert.Empty(t, d.Detect([]network.ConnectionStats{udp, idle}, now))
+	assert.Empty(t, d.baselines)
+
+	// The baselines of the connections which are gone are dropped
+	assert.Empty(t, d.Detect([]network.ConnectionStats{testConn(3, 10, 0, time.Millisecond)}, now))
+	assert.Contains(t, d.baselines, network.StatCookie(3))
+	assert.Empty(t, d.Detect(nil, now))
+	assert.Empty(t, d.baselines)
+}
@@ -0,0 +1,140 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+// Package anomaly detects the retransmit spikes and RTT increases of the TCP connections of the network tracer, so
+// that they can be reported as events and metrics without NPM
+package anomaly
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/network"
+	"github.com/DataDog/datadog-agent/pkg/network/config"
+	"github.com/DataDog/datadog-agent/pkg/telemetry"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	// clientID is the client of the network tracer the anomalies are detected for
+	clientID = "anomaly-detector"
+
+	moduleName = "network_tracer__anomaly"
+)
+
+var monitorTelemetry = struct {
+	detected telemetry.Counter
+	dropped  telemetry.Counter
+	errors   telemetry.Counter
+}{
+	telemetry.NewCounter(moduleName, "detected", []string{"type"}, "Counter measuring the number of anomalies detected"),
+	telemetry.NewCounter(moduleName, "dropped", []string{}, "Counter measuring the number of anomalies dropped because the buffer was full"),
+	telemetry.NewCounter(moduleName, "errors", []string{}, "Counter measuring the number of errors while retrieving the connections"),
+}
+
+// ConnectionSource is the network tracer whose connections are monitored
+type ConnectionSource interface {
+	RegisterClient(clientID string) error
+	GetActiveConnections(clientID string) (*network.Connections, error)
+}
+
+// Monitor detects the anomalies of the connections of the network tracer at every interval, and buffers them until
+// they are retrieved
+type Monitor struct {
+	source      ConnectionSource
+	detector    *Detector
+	interval    time.Duration
+	maxBuffered int
+
+	mu        sync.Mutex
+	anomalies []Anomaly
+
+	exit chan struct{}
+	wg   sync.WaitGroup
+	once sync.Once
+}
+
+// NewMonitor returns a Monitor of the connections of the source, as configured
+func NewMonitor(cfg *config.Config, source ConnectionSource) (*Monitor, error) {
+	if cfg.AnomalyDetectionInterval <= 0 {
+		return nil, fmt.Errorf("invalid anomaly detection interval %s", cfg.AnomalyDetectionInterval)
+	}
+	if err := source.RegisterClient(clientID); err != nil {
+		return nil, fmt.Errorf("could not register the anomaly detector: %w", err)
+	}
+	return &Monitor{
+		source: source,
+		detector: NewDetector(Thresholds{
+			RetransmitRate:    cfg.AnomalyRetransmitRateThreshold,
+			MinRetransmits:    cfg.AnomalyMinRetransmits,
+			RTTIncreaseFactor: cfg.AnomalyRTTIncreaseFactor,
+			RTTMinIncrease:    cfg.AnomalyRTTMinIncrease,
+		}),
+		interval:    cfg.AnomalyDetectionInterval,
+		maxBuffered: cfg.MaxAnomaliesBuffered,
+		exit:        make(chan struct{}),
+	}, nil
+}
+
+// Start detects the anomalies at every interval
+func (m *Monitor) Start() {
+	log.Infof("detecting the anomalies of the TCP connections every %s", m.interval)
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		ticker := time.NewTicker(m.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case now := <-ticker.C:
+				if err := m.detect(now); err != nil {
+					log.Warnf("could not detect the anomalies of the connections: %s", err)
+				}
+			case <-m.exit:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the detection of the anomalies
+func (m *Monitor) Stop() {
+	m.once.Do(func() {
+		close(m.exit)
+		m.wg.Wait()
+	})
+}
+
+// GetAnomalies returns the anomalies detected since the last call
+func (m *Monitor) GetAnomalies() []Anomaly {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	anomalies := m.anomalies
+	m.anomalies = nil
+	return anomalies
+}
+
+func (m *Monitor) detect(now time.Time) error {
+	conns, err := m.source.GetActiveConnections(clientID)
+	if err != nil {
+		monitorTelemetry.errors.Inc()
+		return err
+	}
+	anomalies := m.detector.Detect(conns.Conns, now)
+	network.Reclaim(conns)
+
+	for _, a := range anomalies {
+		monitorTelemetry.detected.Inc(string(a.Type))
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if free := m.maxBuffered - len(m.anomalies); len(anomalies) > free {
+		monitorTelemetry.dropped.Add(float64(len(anomalies) - max(free, 0)))
+		anomalies = anomalies[:max(free, 0)]
+	}
+	m.anomalies = append(m.anomalies, anomalies...)
+	return nil
+}
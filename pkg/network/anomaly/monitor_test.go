@@ -0,0 +1,66 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package anomaly
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/network"
+	"github.com/DataDog/datadog-agent/pkg/network/config"
+)
+
+type fakeSource struct {
+	registered []string
+	conns      []network.ConnectionStats
+}
+
+func (s *fakeSource) RegisterClient(clientID string) error {
+	s.registered = append(s.registered, clientID)
+	return nil
+}
+
+func (s *fakeSource) GetActiveConnections(_ string) (*network.Connections, error) {
+	return &network.Connections{BufferedData: network.BufferedData{Conns: s.conns}}, nil
+}
+
+func TestMonitor(t *testing.T) {
+	source := &fakeSource{conns: []network.ConnectionStats{
+		testConn(1, 100, 30, time.Millisecond),
+		testConn(2, 100, 20, time.Millisecond),
+	}}
+	cfg := &config.Config{
+		AnomalyDetectionInterval:       time.Minute,
+		AnomalyRetransmitRateThreshold: 0.05,
+		AnomalyMinRetransmits:          10,
+		AnomalyRTTIncreaseFactor:       2,
+		AnomalyRTTMinIncrease:          20 * time.Millisecond,
+		MaxAnomaliesBuffered:           3,
+	}
+	m, err := NewMonitor(cfg, source)
+	require.NoError(t, err)
+	m.Start()
+	defer m.Stop()
+	assert.Equal(t, []string{clientID}, source.registered)
+
+	require.NoError(t, m.detect(time.Now()))
+	// The anomalies beyond the buffer are dropped
+	require.NoError(t, m.detect(time.Now()))
+	anomalies := m.GetAnomalies()
+	require.Len(t, anomalies, 3)
+	for _, a := range anomalies {
+		assert.Equal(t, RetransmitSpike, a.Type)
+	}
+	assert.Empty(t, m.GetAnomalies())
+}
+
+func TestNewMonitorInvalidInterval(t *testing.T) {
+	_, err := NewMonitor(&config.Config{}, &fakeSource{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,35 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+// Package capture captures the packets of the connections of the host into pcapng files, on demand, for
+// troubleshooting the connections reported by NPM and USM
+package capture
+
+import (
+	"errors"
+
+	"github.com/DataDog/datadog-agent/pkg/network"
+)
+
+// ErrNotSupported is returned by NewCapture on the platforms packet captures are not supported on
+var ErrNotSupported = errors.New("packet capture is not supported on this platform")
+
+// ConnectionSource provides the connections tracked by the network tracer, which are used to select the packets of
+// the PID and container filters, and to annotate the captured packets with their process and container
+type ConnectionSource interface {
+	DebugNetworkMaps() (*network.Connections, error)
+}
+
+// Stats summarizes a capture
+type Stats struct {
+	// Packets is the number of packets written to the capture
+	Packets int
+	// Bytes is the size of the pcapng capture
+	Bytes int64
+	// Connections is the number of distinct connections of the packets captured
+	Connections int
+	// StopReason is why the capture stopped
+	StopReason string
+}
@@ -0,0 +1,189 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+//go:build linux
+
+package capture
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/DataDog/datadog-agent/pkg/network/filter"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+	"github.com/DataDog/datadog-agent/pkg/version"
+)
+
+const (
+	// sourceBufferSize is the size of the ring buffer of the AF_PACKET socket
+	sourceBufferSize = 8 << 20 // 8 MB
+	// connectionsRefreshInterval is how often the connections of the PID and container filters are refreshed. The
+	// first packets of the connections opened during the capture may be missed until then.
+	connectionsRefreshInterval = 2 * time.Second
+)
+
+// errLimitReached stops the capture when one of its limits is reached
+var errLimitReached = errors.New("capture limit reached")
+
+// Capture captures the packets of the host with a raw AF_PACKET socket, like the one of the DNS monitor
+type Capture struct {
+	opts    Options
+	source  *filter.AFPacketSource
+	conns   ConnectionSource
+	matcher *matcher
+}
+
+// NewCapture opens the socket of a capture, and resolves the connections of its PID or container
+func NewCapture(opts Options, conns ConnectionSource) (*Capture, error) {
+	c := &Capture{
+		opts:    opts,
+		conns:   conns,
+		matcher: newMatcher(opts.Filter),
+	}
+	if err := c.refreshConnections(); err != nil {
+		if opts.PID != 0 || opts.ContainerID != "" {
+			return nil, err
+		}
+		log.Warnf("packets captured won't be annotated with their process: %s", err)
+	}
+
+	source, err := filter.NewAFPacketSource(sourceBufferSize, filter.OptSnapLen(opts.SnapLen))
+	if err != nil {
+		return nil, fmt.Errorf("error creating packet source: %w", err)
+	}
+	c.source = source
+	return c, nil
+}
+
+// Run writes the packets captured to w in the pcapng format, until the context is done, the duration of the capture
+// elapses or one of its limits is reached.
+func (c *Capture) Run(ctx context.Context, w io.Writer) (Stats, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.opts.Duration)
+	defer cancel()
+	go c.refreshLoop(ctx)
+
+	var stats Stats
+	out := bufio.NewWriter(w)
+	writer := newPcapngWriter(out)
+	if err := writer.writeHeader(c.sectionInfo(), "ethernet frames of all the interfaces of the host", c.opts.SnapLen); err != nil {
+		return stats, err
+	}
+
+	start := time.Now()
+	dec := newDecoder()
+	connections := make(map[connKey]string)
+	visit := func(data []byte, _ filter.PacketInfo, t time.Time) error {
+		p, ok := dec.decode(data)
+		if !ok {
+			return nil
+		}
+		key, description, ok := c.matcher.match(p)
+		if !ok {
+			return nil
+		}
+		if writer.written+packetBlockSize(len(data), description) > c.opts.MaxSize {
+			stats.StopReason = fmt.Sprintf("the maximum size of %d bytes was reached", c.opts.MaxSize)
+			return errLimitReached
+		}
+		if err := writer.writePacket(t, data, p.wireLen, description); err != nil {
+			return err
+		}
+		connections[key] = description
+		stats.Packets++
+		if c.opts.MaxPackets > 0 && stats.Packets >= c.opts.MaxPackets {
+			stats.StopReason = fmt.Sprintf("the maximum of %d packets was reached", c.opts.MaxPackets)
+			return errLimitReached
+		}
+		return nil
+	}
+
+	// VisitPackets returns when no packet was received for a second, hence the loop
+	for ctx.Err() == nil {
+		if err := c.source.VisitPackets(ctx.Done(), visit); err != nil {
+			if errors.Is(err, errLimitReached) {
+				break
+			}
+			return stats, err
+		}
+	}
+	if stats.StopReason == "" {
+		stats.StopReason = "the capture ended"
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			stats.StopReason = fmt.Sprintf("the duration of %s elapsed", c.opts.Duration)
+		}
+	}
+
+	stats.Connections = len(connections)
+	if err := writer.writeStatistics(start, time.Now(), uint64(stats.Packets), summary(stats, connections)); err != nil {
+		return stats, err
+	}
+	stats.Bytes = writer.written
+	return stats, out.Flush()
+}
+
+// Close closes the socket of the capture
+func (c *Capture) Close() {
+	c.source.Close()
+}
+
+func (c *Capture) refreshLoop(ctx context.Context) {
+	ticker := time.NewTicker(connectionsRefreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := c.refreshConnections(); err != nil {
+				log.Debugf("error refreshing the connections of the packet capture: %s", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (c *Capture) refreshConnections() error {
+	conns, err := c.conns.DebugNetworkMaps()
+	if err != nil {
+		return fmt.Errorf("error retrieving the connections: %w", err)
+	}
+	c.matcher.update(conns.Conns)
+	return nil
+}
+
+func (c *Capture) sectionInfo() sectionInfo {
+	info := sectionInfo{
+		hardware:    runtime.GOARCH,
+		application: "datadog system-probe " + version.AgentVersion,
+		comment:     "filter: " + c.opts.Filter.String(),
+	}
+	var uname unix.Utsname
+	if err := unix.Uname(&uname); err == nil {
+		info.os = unix.ByteSliceToString(uname.Sysname[:]) + " " + unix.ByteSliceToString(uname.Release[:])
+	}
+	return info
+}
+
+// summary describes the capture and the connections of its packets, along with their process and container
+func summary(stats Stats, connections map[connKey]string) string {
+	lines := make([]string, 0, len(connections))
+	for key, description := range connections {
+		if description == "" {
+			description = "unknown process"
+		}
+		lines = append(lines, fmt.Sprintf("%s (%s)", key, description))
+	}
+	sort.Strings(lines)
+	header := fmt.Sprintf("%d packets of %d connections captured, %s", stats.Packets, len(connections), stats.StopReason)
+	return strings.Join(append([]string{header}, lines...), "\n")
+}
@@ -0,0 +1,29 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+//go:build !linux
+
+package capture
+
+import (
+	"context"
+	"io"
+)
+
+// Capture is not implemented on this platform
+type Capture struct{}
+
+// NewCapture returns ErrNotSupported on this platform
+func NewCapture(Options, ConnectionSource) (*Capture, error) {
+	return nil, ErrNotSupported
+}
+
+// Run returns ErrNotSupported on this platform
+func (c *Capture) Run(context.Context, io.Writer) (Stats, error) {
+	return Stats{}, ErrNotSupported
+}
+
+// Close is a no-op on this platform
+func (c *Capture) Close() {}
@@ -0,0 +1,180 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package capture
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+	"sync/atomic"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+
+	"github.com/DataDog/datadog-agent/pkg/network"
+)
+
+// packet holds the 5-tuple of a captured packet
+type packet struct {
+	proto Protocol
+	src   netip.AddrPort
+	dst   netip.AddrPort
+	// wireLen is the length of the packet on the wire, according to the IP header, which may be larger than the
+	// captured data
+	wireLen int
+}
+
+// connKey identifies the packets of a connection, regardless of their direction
+type connKey struct {
+	proto Protocol
+	a, b  netip.AddrPort
+}
+
+func newConnKey(proto Protocol, src, dst netip.AddrPort) connKey {
+	if src.Compare(dst) > 0 {
+		src, dst = dst, src
+	}
+	return connKey{proto: proto, a: src, b: dst}
+}
+
+func (k connKey) String() string {
+	return fmt.Sprintf("%s %s <-> %s", k.proto, k.a, k.b)
+}
+
+// connectionSet maps the connections tracked by the network tracer to the description of their process
+type connectionSet map[connKey]string
+
+// newConnectionSet returns the connections matching the PID and container of the filter, along with the connections
+// of the previous set, so that the packets of the connections closed during the capture keep matching.
+func newConnectionSet(previous connectionSet, conns []network.ConnectionStats, f Filter) connectionSet {
+	set := make(connectionSet, len(previous))
+	for k, v := range previous {
+		set[k] = v
+	}
+	for i := range conns {
+		c := &conns[i]
+		if f.PID != 0 && c.Pid != f.PID {
+			continue
+		}
+		var containerID string
+		if c.ContainerID.Source != nil {
+			containerID, _ = c.ContainerID.Source.Get().(string)
+		}
+		// Allowing the short ids of the containers
+		if f.ContainerID != "" && (containerID == "" || !strings.HasPrefix(containerID, f.ContainerID)) {
+			continue
+		}
+
+		proto := ProtocolTCP
+		if c.Type == network.UDP {
+			proto = ProtocolUDP
+		}
+		description := fmt.Sprintf("pid=%d", c.Pid)
+		if containerID != "" {
+			description += " container_id=" + containerID
+		}
+		set[newConnKey(proto, netip.AddrPortFrom(c.Source.Addr.Unmap(), c.SPort), netip.AddrPortFrom(c.Dest.Addr.Unmap(), c.DPort))] = description
+		// The packets of the NAT'ed connections are seen on the wire with their translated addresses
+		if t := c.IPTranslation; t != nil {
+			set[newConnKey(proto, netip.AddrPortFrom(t.ReplDstIP.Addr.Unmap(), t.ReplDstPort), netip.AddrPortFrom(t.ReplSrcIP.Addr.Unmap(), t.ReplSrcPort))] = description
+		}
+	}
+	return set
+}
+
+// matches returns whether the packet matches the addresses, ports and protocol of the filter, in either direction
+func (f Filter) matches(p packet) bool {
+	if f.Protocol != ProtocolAny && f.Protocol != p.proto {
+		return false
+	}
+	return (matchesEndpoint(f.SrcIP, f.SrcPort, p.src) && matchesEndpoint(f.DstIP, f.DstPort, p.dst)) ||
+		(matchesEndpoint(f.SrcIP, f.SrcPort, p.dst) && matchesEndpoint(f.DstIP, f.DstPort, p.src))
+}
+
+func matchesEndpoint(ip netip.Addr, port uint16, endpoint netip.AddrPort) bool {
+	return (!ip.IsValid() || ip == endpoint.Addr()) && (port == 0 || port == endpoint.Port())
+}
+
+// matcher selects the packets of a capture
+type matcher struct {
+	filter      Filter
+	connections atomic.Pointer[connectionSet]
+}
+
+func newMatcher(f Filter) *matcher {
+	m := &matcher{filter: f}
+	m.connections.Store(&connectionSet{})
+	return m
+}
+
+// update adds the connections given to the connections known by the matcher
+func (m *matcher) update(conns []network.ConnectionStats) {
+	set := newConnectionSet(*m.connections.Load(), conns, m.filter)
+	m.connections.Store(&set)
+}
+
+// match returns whether the packet matches the filter, along with the key of its connection and the description of
+// its process, if the connection is known
+func (m *matcher) match(p packet) (key connKey, description string, ok bool) {
+	if !m.filter.matches(p) {
+		return key, "", false
+	}
+	key = newConnKey(p.proto, p.src, p.dst)
+	description, known := (*m.connections.Load())[key]
+	if m.filter.PID != 0 || m.filter.ContainerID != "" {
+		return key, description, known
+	}
+	return key, description, true
+}
+
+// decoder decodes the 5-tuple of the ethernet frames captured
+type decoder struct {
+	parser  *gopacket.DecodingLayerParser
+	decoded []gopacket.LayerType
+	eth     layers.Ethernet
+	ipv4    layers.IPv4
+	ipv6    layers.IPv6
+	tcp     layers.TCP
+	udp     layers.UDP
+}
+
+func newDecoder() *decoder {
+	d := &decoder{}
+	d.parser = gopacket.NewDecodingLayerParser(layers.LayerTypeEthernet, &d.eth, &d.ipv4, &d.ipv6, &d.tcp, &d.udp)
+	d.parser.IgnoreUnsupported = true
+	return d
+}
+
+// decode returns the 5-tuple of the frame given, if it is a TCP or UDP packet
+func (d *decoder) decode(data []byte) (packet, bool) {
+	var p packet
+	if err := d.parser.DecodeLayers(data, &d.decoded); err != nil {
+		return p, false
+	}
+
+	var srcIP, dstIP netip.Addr
+	for _, layer := range d.decoded {
+		switch layer {
+		case layers.LayerTypeIPv4:
+			srcIP, _ = netip.AddrFromSlice(d.ipv4.SrcIP)
+			dstIP, _ = netip.AddrFromSlice(d.ipv4.DstIP)
+			p.wireLen = len(d.eth.Contents) + int(d.ipv4.Length)
+		case layers.LayerTypeIPv6:
+			srcIP, _ = netip.AddrFromSlice(d.ipv6.SrcIP)
+			dstIP, _ = netip.AddrFromSlice(d.ipv6.DstIP)
+			p.wireLen = len(d.eth.Contents) + len(d.ipv6.Contents) + int(d.ipv6.Length)
+		case layers.LayerTypeTCP:
+			p.proto = ProtocolTCP
+			p.src = netip.AddrPortFrom(srcIP.Unmap(), uint16(d.tcp.SrcPort))
+			p.dst = netip.AddrPortFrom(dstIP.Unmap(), uint16(d.tcp.DstPort))
+		case layers.LayerTypeUDP:
+			p.proto = ProtocolUDP
+			p.src = netip.AddrPortFrom(srcIP.Unmap(), uint16(d.udp.SrcPort))
+			p.dst = netip.AddrPortFrom(dstIP.Unmap(), uint16(d.udp.DstPort))
+		}
+	}
+	return p, p.proto != ProtocolAny
+}
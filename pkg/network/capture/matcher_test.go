@@ -0,0 +1,192 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package capture
+
+import (
+	"net"
+	"net/netip"
+	"testing"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go4.org/intern"
+
+	"github.com/DataDog/datadog-agent/pkg/network"
+	"github.com/DataDog/datadog-agent/pkg/process/util"
+)
+
+func tcpFrame(t *testing.T, src, dst netip.AddrPort, payloadLen int) []byte {
+	eth := &layers.Ethernet{
+		SrcMAC:       net.HardwareAddr{0, 1, 2, 3, 4, 5},
+		DstMAC:       net.HardwareAddr{0, 1, 2, 3, 4, 6},
+		EthernetType: layers.EthernetTypeIPv4,
+	}
+	ip := &layers.IPv4{
+		Version:  4,
+		TTL:      64,
+		Protocol: layers.IPProtocolTCP,
+		SrcIP:    src.Addr().AsSlice(),
+		DstIP:    dst.Addr().AsSlice(),
+	}
+	if src.Addr().Is6() {
+		eth.EthernetType = layers.EthernetTypeIPv6
+	}
+	tcp := &layers.TCP{SrcPort: layers.TCPPort(src.Port()), DstPort: layers.TCPPort(dst.Port()), ACK: true}
+
+	buf := gopacket.NewSerializeBuffer()
+	opts := gopacket.SerializeOptions{FixLengths: true, ComputeChecksums: true}
+	var ipLayer gopacket.SerializableLayer = ip
+	if src.Addr().Is6() {
+		ip6 := &layers.IPv6{
+			Version:    6,
+			HopLimit:   64,
+			NextHeader: layers.IPProtocolTCP,
+			SrcIP:      src.Addr().AsSlice(),
+			DstIP:      dst.Addr().AsSlice(),
+		}
+		require.NoError(t, tcp.SetNetworkLayerForChecksum(ip6))
+		ipLayer = ip6
+	} else {
+		require.NoError(t, tcp.SetNetworkLayerForChecksum(ip))
+	}
+	require.NoError(t, gopacket.SerializeLayers(buf, opts, eth, ipLayer, tcp, gopacket.Payload(make([]byte, payloadLen))))
+	return buf.Bytes()
+}
+
+func TestDecoder(t *testing.T) {
+	src := netip.MustParseAddrPort("10.0.0.1:40000")
+	dst := netip.MustParseAddrPort("10.0.0.2:8080")
+	frame := tcpFrame(t, src, dst, 100)
+
+	d := newDecoder()
+	p, ok := d.decode(frame)
+	require.True(t, ok)
+	assert.Equal(t, ProtocolTCP, p.proto)
+	assert.Equal(t, src, p.src)
+	assert.Equal(t, dst, p.dst)
+	assert.Equal(t, len(frame), p.wireLen)
+
+	// The length on the wire comes from the IP header when the frame is truncated by the snap length
+	p, ok = d.decode(frame[:60])
+	require.True(t, ok)
+	assert.Equal(t, len(frame), p.wireLen)
+
+	src6 := netip.MustParseAddrPort("[fd00::1]:40000")
+	dst6 := netip.MustParseAddrPort("[fd00::2]:443")
+	p, ok = d.decode(tcpFrame(t, src6, dst6, 0))
+	require.True(t, ok)
+	assert.Equal(t, src6, p.src)
+	assert.Equal(t, dst6, p.dst)
+
+	_, ok = d.decode([]byte{1, 2, 3})
+	assert.False(t, ok)
+}
+
+func TestFilterMatches(t *testing.T) {
+	p := packet{
+		proto: ProtocolTCP,
+		src:   netip.MustParseAddrPort("10.0.0.1:40000"),
+		dst:   netip.MustParseAddrPort("10.0.0.2:8080"),
+	}
+	tests := []struct {
+		name    string
+		filter  Filter
+		matches bool
+	}{
+		{"destination port", Filter{DstPort: 8080}, true},
+		{"destination port of the reply", Filter{SrcPort: 8080}, true},
+		{"other port", Filter{DstPort: 80}, false},
+		{"protocol", Filter{Protocol: ProtocolTCP}, true},
+		{"other protocol", Filter{Protocol: ProtocolUDP, DstPort: 8080}, false},
+		{"5-tuple", Filter{
+			SrcIP: netip.MustParseAddr("10.0.0.1"), SrcPort: 40000,
+			DstIP: netip.MustParseAddr("10.0.0.2"), DstPort: 8080,
+			Protocol: ProtocolTCP,
+		}, true},
+		{"reversed 5-tuple", Filter{
+			SrcIP: netip.MustParseAddr("10.0.0.2"), SrcPort: 8080,
+			DstIP: netip.MustParseAddr("10.0.0.1"), DstPort: 40000,
+		}, true},
+		{"mismatched address and port", Filter{SrcIP: netip.MustParseAddr("10.0.0.1"), SrcPort: 8080}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.matches, tt.filter.matches(p))
+		})
+	}
+}
+
+func TestMatcherConnections(t *testing.T) {
+	containerID := "3f2a9c7d1b0e6f5a4c3b2a1908f7e6d5c4b3a2918f7e6d5c4b3a29180f7e6d5c"
+	conns := []network.ConnectionStats{
+		{ConnectionTuple: network.ConnectionTuple{
+			Source: util.AddressFromString("10.0.0.1"), SPort: 40000,
+			Dest: util.AddressFromString("10.96.0.10"), DPort: 80,
+			Pid: 42, Type: network.TCP,
+		}},
+		{ConnectionTuple: network.ConnectionTuple{
+			Source: util.AddressFromString("10.0.0.1"), SPort: 53000,
+			Dest: util.AddressFromString("10.0.0.53"), DPort: 53,
+			Pid: 7, Type: network.UDP,
+		}},
+	}
+	// The first connection is NAT'ed to a backend of the service
+	conns[0].IPTranslation = &network.IPTranslation{
+		ReplSrcIP: util.AddressFromString("10.0.1.5"), ReplSrcPort: 8080,
+		ReplDstIP: util.AddressFromString("10.0.0.1"), ReplDstPort: 40000,
+	}
+	conns[0].ContainerID.Source = intern.GetByString(containerID)
+
+	service := packet{proto: ProtocolTCP, src: netip.MustParseAddrPort("10.96.0.10:80"), dst: netip.MustParseAddrPort("10.0.0.1:40000")}
+	backend := packet{proto: ProtocolTCP, src: netip.MustParseAddrPort("10.0.0.1:40000"), dst: netip.MustParseAddrPort("10.0.1.5:8080")}
+	dns := packet{proto: ProtocolUDP, src: netip.MustParseAddrPort("10.0.0.1:53000"), dst: netip.MustParseAddrPort("10.0.0.53:53")}
+	unknown := packet{proto: ProtocolTCP, src: netip.MustParseAddrPort("10.0.0.1:41000"), dst: netip.MustParseAddrPort("10.0.0.3:22")}
+
+	t.Run("pid", func(t *testing.T) {
+		m := newMatcher(Filter{PID: 42})
+		m.update(conns)
+		_, description, ok := m.match(service)
+		assert.True(t, ok)
+		assert.Equal(t, "pid=42 container_id="+containerID, description)
+		_, _, ok = m.match(backend)
+		assert.True(t, ok)
+		_, _, ok = m.match(dns)
+		assert.False(t, ok)
+
+		// The connections closed during the capture keep matching
+		m.update(nil)
+		_, _, ok = m.match(backend)
+		assert.True(t, ok)
+	})
+
+	t.Run("container", func(t *testing.T) {
+		m := newMatcher(Filter{ContainerID: containerID[:12]})
+		m.update(conns)
+		_, _, ok := m.match(service)
+		assert.True(t, ok)
+		_, _, ok = m.match(dns)
+		assert.False(t, ok)
+	})
+
+	t.Run("annotations", func(t *testing.T) {
+		m := newMatcher(Filter{Protocol: ProtocolUDP})
+		m.update(conns)
+		key, description, ok := m.match(dns)
+		assert.True(t, ok)
+		assert.Equal(t, "pid=7", description)
+		assert.Equal(t, "udp 10.0.0.1:53000 <-> 10.0.0.53:53", key.String())
+		_, _, ok = m.match(service)
+		assert.False(t, ok)
+
+		m = newMatcher(Filter{Protocol: ProtocolTCP})
+		m.update(conns)
+		_, description, ok = m.match(unknown)
+		assert.True(t, ok)
+		assert.Empty(t, description)
+	})
+}
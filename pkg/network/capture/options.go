@@ -0,0 +1,230 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package capture
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultDuration is the duration of the captures which don't specify one
+	DefaultDuration = 30 * time.Second
+	// DefaultSnapLen is the number of bytes captured of each packet, unless specified otherwise
+	DefaultSnapLen = 4096
+	// MaxSnapLen is the maximum number of bytes captured of each packet
+	MaxSnapLen = 65535
+)
+
+// Protocol is the transport protocol of the packets captured
+type Protocol string
+
+const (
+	// ProtocolAny matches both TCP and UDP packets
+	ProtocolAny Protocol = ""
+	// ProtocolTCP matches TCP packets
+	ProtocolTCP Protocol = "tcp"
+	// ProtocolUDP matches UDP packets
+	ProtocolUDP Protocol = "udp"
+)
+
+// Filter selects the packets of a capture. The zero value of each field matches any packet, and the packets must
+// match all the fields set. The addresses and ports match the packets flowing in both directions.
+type Filter struct {
+	SrcIP    netip.Addr
+	SrcPort  uint16
+	DstIP    netip.Addr
+	DstPort  uint16
+	Protocol Protocol
+	// PID selects the packets of the connections of the process of the given PID
+	PID uint32
+	// ContainerID selects the packets of the connections of the processes of the given container
+	ContainerID string
+}
+
+// IsEmpty returns whether the filter matches every packet
+func (f Filter) IsEmpty() bool {
+	return f == Filter{}
+}
+
+// String returns a description of the filter, in the same format as the query parameters of the capture endpoint
+func (f Filter) String() string {
+	values := url.Values{}
+	f.encode(values)
+	var parts []string
+	for _, key := range filterKeys {
+		if v := values.Get(key); v != "" {
+			parts = append(parts, key+"="+v)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// filterKeys are the query parameters of the filters, in the order they are described in
+var filterKeys = []string{"src_ip", "src_port", "dst_ip", "dst_port", "proto", "pid", "container_id"}
+
+func (f Filter) encode(values url.Values) {
+	if f.SrcIP.IsValid() {
+		values.Set("src_ip", f.SrcIP.String())
+	}
+	if f.SrcPort != 0 {
+		values.Set("src_port", strconv.Itoa(int(f.SrcPort)))
+	}
+	if f.DstIP.IsValid() {
+		values.Set("dst_ip", f.DstIP.String())
+	}
+	if f.DstPort != 0 {
+		values.Set("dst_port", strconv.Itoa(int(f.DstPort)))
+	}
+	if f.Protocol != ProtocolAny {
+		values.Set("proto", string(f.Protocol))
+	}
+	if f.PID != 0 {
+		values.Set("pid", strconv.FormatUint(uint64(f.PID), 10))
+	}
+	if f.ContainerID != "" {
+		values.Set("container_id", f.ContainerID)
+	}
+}
+
+// Options are the parameters of a capture
+type Options struct {
+	Filter
+	// Duration is how long the capture lasts, unless one of the limits below is reached first
+	Duration time.Duration
+	// MaxSize is the maximum size, in bytes, of the pcapng capture
+	MaxSize int64
+	// MaxPackets is the maximum number of packets captured, or 0 for no limit
+	MaxPackets int
+	// SnapLen is the number of bytes captured of each packet
+	SnapLen int
+}
+
+// Limits are the bounds the system-probe enforces on the captures
+type Limits struct {
+	MaxDuration time.Duration
+	MaxSize     int64
+}
+
+// Values encodes the options as the query parameters of the capture endpoint
+func (o Options) Values() url.Values {
+	values := url.Values{}
+	o.Filter.encode(values)
+	if o.Duration != 0 {
+		values.Set("duration", o.Duration.String())
+	}
+	if o.MaxSize != 0 {
+		values.Set("max_size", strconv.FormatInt(o.MaxSize, 10))
+	}
+	if o.MaxPackets != 0 {
+		values.Set("max_packets", strconv.Itoa(o.MaxPackets))
+	}
+	if o.SnapLen != 0 {
+		values.Set("snaplen", strconv.Itoa(o.SnapLen))
+	}
+	return values
+}
+
+// ParseOptions decodes the query parameters of the capture endpoint. The unspecified durations and sizes default to
+// the limits given, and the options exceeding them are rejected.
+func ParseOptions(values url.Values, limits Limits) (Options, error) {
+	var (
+		opts Options
+		err  error
+	)
+	if opts.SrcIP, err = parseAddr(values, "src_ip"); err != nil {
+		return opts, err
+	}
+	if opts.SrcPort, err = parsePort(values, "src_port"); err != nil {
+		return opts, err
+	}
+	if opts.DstIP, err = parseAddr(values, "dst_ip"); err != nil {
+		return opts, err
+	}
+	if opts.DstPort, err = parsePort(values, "dst_port"); err != nil {
+		return opts, err
+	}
+	switch proto := Protocol(strings.ToLower(values.Get("proto"))); proto {
+	case ProtocolAny, ProtocolTCP, ProtocolUDP:
+		opts.Protocol = proto
+	default:
+		return opts, fmt.Errorf("invalid proto %q: should be tcp or udp", values.Get("proto"))
+	}
+	if v := values.Get("pid"); v != "" {
+		pid, err := strconv.ParseUint(v, 10, 32)
+		if err != nil || pid == 0 {
+			return opts, fmt.Errorf("invalid pid %q", v)
+		}
+		opts.PID = uint32(pid)
+	}
+	opts.ContainerID = values.Get("container_id")
+	if opts.Filter.IsEmpty() {
+		return opts, errors.New("a filter is required: specify at least an address, a port, a protocol, a pid or a container id")
+	}
+
+	opts.Duration = min(DefaultDuration, limits.MaxDuration)
+	if v := values.Get("duration"); v != "" {
+		if opts.Duration, err = time.ParseDuration(v); err != nil || opts.Duration <= 0 {
+			return opts, fmt.Errorf("invalid duration %q", v)
+		}
+		if opts.Duration > limits.MaxDuration {
+			return opts, fmt.Errorf("duration %s exceeds the maximum of %s", opts.Duration, limits.MaxDuration)
+		}
+	}
+
+	opts.MaxSize = limits.MaxSize
+	if v := values.Get("max_size"); v != "" {
+		if opts.MaxSize, err = strconv.ParseInt(v, 10, 64); err != nil || opts.MaxSize <= 0 {
+			return opts, fmt.Errorf("invalid max_size %q", v)
+		}
+		if opts.MaxSize > limits.MaxSize {
+			return opts, fmt.Errorf("max_size %d exceeds the maximum of %d bytes", opts.MaxSize, limits.MaxSize)
+		}
+	}
+
+	if v := values.Get("max_packets"); v != "" {
+		if opts.MaxPackets, err = strconv.Atoi(v); err != nil || opts.MaxPackets < 0 {
+			return opts, fmt.Errorf("invalid max_packets %q", v)
+		}
+	}
+
+	opts.SnapLen = DefaultSnapLen
+	if v := values.Get("snaplen"); v != "" {
+		if opts.SnapLen, err = strconv.Atoi(v); err != nil || opts.SnapLen <= 0 || opts.SnapLen > MaxSnapLen {
+			return opts, fmt.Errorf("invalid snaplen %q: should be between 1 and %d", v, MaxSnapLen)
+		}
+	}
+	return opts, nil
+}
+
+func parseAddr(values url.Values, key string) (netip.Addr, error) {
+	v := values.Get(key)
+	if v == "" {
+		return netip.Addr{}, nil
+	}
+	addr, err := netip.ParseAddr(v)
+	if err != nil {
+		return addr, fmt.Errorf("invalid %s %q: %w", key, v, err)
+	}
+	return addr.Unmap(), nil
+}
+
+func parsePort(values url.Values, key string) (uint16, error) {
+	v := values.Get(key)
+	if v == "" {
+		return 0, nil
+	}
+	port, err := strconv.ParseUint(v, 10, 16)
+	if err != nil || port == 0 {
+		return 0, fmt.Errorf("invalid %s %q", key, v)
+	}
+	return uint16(port), nil
+}
@@ -0,0 +1,77 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package capture
+
+import (
+	"net/netip"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testLimits = Limits{MaxDuration: 5 * time.Minute, MaxSize: 100 << 20}
+
+func TestParseOptions(t *testing.T) {
+	opts := Options{
+		Filter: Filter{
+			SrcIP:       netip.MustParseAddr("10.0.0.1"),
+			SrcPort:     40000,
+			DstIP:       netip.MustParseAddr("fd00::2"),
+			DstPort:     443,
+			Protocol:    ProtocolTCP,
+			PID:         42,
+			ContainerID: "abcdef",
+		},
+		Duration:   time.Minute,
+		MaxSize:    1 << 20,
+		MaxPackets: 1000,
+		SnapLen:    128,
+	}
+	parsed, err := ParseOptions(opts.Values(), testLimits)
+	require.NoError(t, err)
+	assert.Equal(t, opts, parsed)
+	assert.Equal(t, "src_ip=10.0.0.1 src_port=40000 dst_ip=fd00::2 dst_port=443 proto=tcp pid=42 container_id=abcdef", parsed.Filter.String())
+}
+
+func TestParseOptionsDefaults(t *testing.T) {
+	opts, err := ParseOptions(url.Values{"dst_port": {"8080"}, "proto": {"UDP"}}, testLimits)
+	require.NoError(t, err)
+	assert.Equal(t, Options{
+		Filter:   Filter{DstPort: 8080, Protocol: ProtocolUDP},
+		Duration: DefaultDuration,
+		MaxSize:  testLimits.MaxSize,
+		SnapLen:  DefaultSnapLen,
+	}, opts)
+
+	opts, err = ParseOptions(url.Values{"src_ip": {"::ffff:10.0.0.1"}}, Limits{MaxDuration: 10 * time.Second, MaxSize: 1024})
+	require.NoError(t, err)
+	assert.Equal(t, netip.MustParseAddr("10.0.0.1"), opts.SrcIP)
+	assert.Equal(t, 10*time.Second, opts.Duration)
+}
+
+func TestParseOptionsErrors(t *testing.T) {
+	tests := map[string]url.Values{
+		"no filter":          {"duration": {"10s"}},
+		"invalid ip":         {"src_ip": {"10.0.0"}},
+		"invalid port":       {"dst_port": {"70000"}},
+		"invalid proto":      {"proto": {"icmp"}},
+		"invalid pid":        {"pid": {"-1"}},
+		"invalid duration":   {"pid": {"1"}, "duration": {"ten"}},
+		"duration too long":  {"pid": {"1"}, "duration": {"1h"}},
+		"size too large":     {"pid": {"1"}, "max_size": {"1000000000"}},
+		"invalid snaplen":    {"pid": {"1"}, "snaplen": {"0"}},
+		"invalid maxpackets": {"pid": {"1"}, "max_packets": {"many"}},
+	}
+	for name, values := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseOptions(values, testLimits)
+			assert.Error(t, err)
+		})
+	}
+}
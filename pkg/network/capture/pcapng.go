@@ -0,0 +1,182 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package capture
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+	"time"
+)
+
+// The pcapng blocks and options we write, see https://www.ietf.org/archive/id/draft-ietf-opsawg-pcapng-02.html
+const (
+	blockSectionHeader        = 0x0A0D0D0A
+	blockInterfaceDescription = 0x00000001
+	blockInterfaceStatistics  = 0x00000005
+	blockEnhancedPacket       = 0x00000006
+
+	byteOrderMagic = 0x1A2B3C4D
+
+	optionEnd     = 0
+	optionComment = 1
+
+	optionSectionHardware = 2
+	optionSectionOS       = 3
+	optionSectionUserAppl = 4
+
+	optionInterfaceName        = 2
+	optionInterfaceTSResol     = 9
+	optionStatisticsStartTime  = 2
+	optionStatisticsEndTime    = 3
+	optionStatisticsPacketsRcv = 4
+
+	linkTypeEthernet = 1
+	// nanosecondResolution is the if_tsresol value of the timestamps in nanoseconds
+	nanosecondResolution = 9
+
+	// blockOverhead is the size of the type and of the two length fields of the blocks
+	blockOverhead = 12
+	// enhancedPacketHeader is the size of the fixed fields of the Enhanced Packet Blocks, after the block type and
+	// length
+	enhancedPacketHeader = 20
+)
+
+// sectionInfo describes the capture in the Section Header Block of a pcapng file
+type sectionInfo struct {
+	hardware    string
+	os          string
+	application string
+	comment     string
+}
+
+// pcapngWriter writes packets in the pcapng format. Unlike the writer of gopacket, it supports the comments of the
+// packets, which hold the process and the container of their connection.
+type pcapngWriter struct {
+	w       io.Writer
+	buf     []byte
+	written int64
+}
+
+func newPcapngWriter(w io.Writer) *pcapngWriter {
+	return &pcapngWriter{w: w}
+}
+
+// writeHeader writes the Section Header Block and the Interface Description Block of the capture, which is made of a
+// single interface capturing the ethernet frames of all the interfaces of the host.
+func (p *pcapngWriter) writeHeader(section sectionInfo, interfaceComment string, snapLen int) error {
+	b := p.startBlock(blockSectionHeader)
+	b = binary.LittleEndian.AppendUint32(b, byteOrderMagic)
+	b = binary.LittleEndian.AppendUint16(b, 1) // major version
+	b = binary.LittleEndian.AppendUint16(b, 0) // minor version
+	b = binary.LittleEndian.AppendUint64(b, math.MaxUint64)
+	b = appendStringOption(b, optionComment, section.comment)
+	b = appendStringOption(b, optionSectionHardware, section.hardware)
+	b = appendStringOption(b, optionSectionOS, section.os)
+	b = appendStringOption(b, optionSectionUserAppl, section.application)
+	b = appendOption(b, optionEnd, nil)
+	if err := p.endBlock(b); err != nil {
+		return err
+	}
+
+	b = p.startBlock(blockInterfaceDescription)
+	b = binary.LittleEndian.AppendUint16(b, linkTypeEthernet)
+	b = binary.LittleEndian.AppendUint16(b, 0) // reserved
+	b = binary.LittleEndian.AppendUint32(b, uint32(snapLen))
+	b = appendStringOption(b, optionComment, interfaceComment)
+	b = appendStringOption(b, optionInterfaceName, "any")
+	b = appendOption(b, optionInterfaceTSResol, []byte{nanosecondResolution})
+	b = appendOption(b, optionEnd, nil)
+	return p.endBlock(b)
+}
+
+// writePacket writes an Enhanced Packet Block. wireLen is the length of the packet on the wire, which is larger than
+// the data given when the packet was truncated by the capture.
+func (p *pcapngWriter) writePacket(t time.Time, data []byte, wireLen int, comment string) error {
+	b := p.startBlock(blockEnhancedPacket)
+	b = binary.LittleEndian.AppendUint32(b, 0) // interface id
+	b = appendTimestamp(b, t)
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(data)))
+	b = binary.LittleEndian.AppendUint32(b, uint32(max(wireLen, len(data))))
+	b = append(b, data...)
+	b = appendPadding(b, len(data))
+	if comment != "" {
+		b = appendStringOption(b, optionComment, comment)
+		b = appendOption(b, optionEnd, nil)
+	}
+	return p.endBlock(b)
+}
+
+// writeStatistics writes the Interface Statistics Block closing the capture
+func (p *pcapngWriter) writeStatistics(start, end time.Time, packets uint64, comment string) error {
+	b := p.startBlock(blockInterfaceStatistics)
+	b = binary.LittleEndian.AppendUint32(b, 0) // interface id
+	b = appendTimestamp(b, end)
+	b = appendStringOption(b, optionComment, comment)
+	b = appendOption(b, optionStatisticsStartTime, appendTimestamp(nil, start))
+	b = appendOption(b, optionStatisticsEndTime, appendTimestamp(nil, end))
+	b = appendOption(b, optionStatisticsPacketsRcv, binary.LittleEndian.AppendUint64(nil, packets))
+	b = appendOption(b, optionEnd, nil)
+	return p.endBlock(b)
+}
+
+// packetBlockSize returns the size of the Enhanced Packet Block of a packet
+func packetBlockSize(dataLen int, comment string) int64 {
+	size := blockOverhead + enhancedPacketHeader + dataLen + padding(dataLen)
+	if comment != "" {
+		size += 4 + len(comment) + padding(len(comment)) + 4
+	}
+	return int64(size)
+}
+
+// startBlock returns the buffer of a new block, with its type and a placeholder for its length
+func (p *pcapngWriter) startBlock(blockType uint32) []byte {
+	b := binary.LittleEndian.AppendUint32(p.buf[:0], blockType)
+	return append(b, 0, 0, 0, 0)
+}
+
+// endBlock fills the lengths of the block and writes it
+func (p *pcapngWriter) endBlock(b []byte) error {
+	length := uint32(len(b) + 4)
+	binary.LittleEndian.PutUint32(b[4:], length)
+	b = binary.LittleEndian.AppendUint32(b, length)
+	p.buf = b
+	n, err := p.w.Write(b)
+	p.written += int64(n)
+	return err
+}
+
+func appendTimestamp(b []byte, t time.Time) []byte {
+	ts := uint64(t.UnixNano())
+	b = binary.LittleEndian.AppendUint32(b, uint32(ts>>32))
+	return binary.LittleEndian.AppendUint32(b, uint32(ts))
+}
+
+func appendStringOption(b []byte, code uint16, value string) []byte {
+	if value == "" {
+		return b
+	}
+	if len(value) > math.MaxUint16 {
+		value = value[:math.MaxUint16]
+	}
+	return appendOption(b, code, []byte(value))
+}
+
+func appendOption(b []byte, code uint16, value []byte) []byte {
+	b = binary.LittleEndian.AppendUint16(b, code)
+	b = binary.LittleEndian.AppendUint16(b, uint16(len(value)))
+	b = append(b, value...)
+	return appendPadding(b, len(value))
+}
+
+func appendPadding(b []byte, length int) []byte {
+	return append(b, make([]byte, padding(length))...)
+}
+
+// padding returns the number of bytes aligning a field of the given length on 32 bits
+func padding(length int) int {
+	return (4 - length%4) % 4
+}
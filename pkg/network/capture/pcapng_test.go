@@ -0,0 +1,81 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package capture
+
+import (
+	"bytes"
+	"io"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/google/gopacket/layers"
+	"github.com/google/gopacket/pcapgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPcapngWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := newPcapngWriter(&buf)
+	section := sectionInfo{
+		hardware:    "amd64",
+		os:          "Linux 6.1.0",
+		application: "datadog system-probe",
+		comment:     "filter: dst_port=8080",
+	}
+	require.NoError(t, w.writeHeader(section, "all interfaces", 128))
+
+	start := time.Unix(1700000000, 123456789)
+	first := tcpFrame(t, netip.MustParseAddrPort("10.0.0.1:40000"), netip.MustParseAddrPort("10.0.0.2:8080"), 3)
+	second := tcpFrame(t, netip.MustParseAddrPort("10.0.0.2:8080"), netip.MustParseAddrPort("10.0.0.1:40000"), 10)
+
+	before := w.written
+	require.NoError(t, w.writePacket(start, first, 1500, "pid=42 container_id=abcdef"))
+	assert.Equal(t, packetBlockSize(len(first), "pid=42 container_id=abcdef"), w.written-before)
+	before = w.written
+	require.NoError(t, w.writePacket(start.Add(time.Millisecond), second, 0, ""))
+	assert.Equal(t, packetBlockSize(len(second), ""), w.written-before)
+	require.NoError(t, w.writeStatistics(start, start.Add(time.Second), 2, "2 packets captured"))
+	assert.EqualValues(t, buf.Len(), w.written)
+	assert.True(t, bytes.Contains(buf.Bytes(), []byte("pid=42 container_id=abcdef")))
+
+	var stats pcapgo.NgInterfaceStatistics
+	r, err := pcapgo.NewNgReader(bytes.NewReader(buf.Bytes()), pcapgo.NgReaderOptions{
+		StatisticsCallback: func(_ int, s pcapgo.NgInterfaceStatistics) { stats = s },
+	})
+	require.NoError(t, err)
+	assert.Equal(t, section.comment, r.SectionInfo().Comment)
+	assert.Equal(t, section.hardware, r.SectionInfo().Hardware)
+	assert.Equal(t, section.os, r.SectionInfo().OS)
+	assert.Equal(t, section.application, r.SectionInfo().Application)
+	assert.Equal(t, layers.LinkTypeEthernet, r.LinkType())
+	intf, err := r.Interface(0)
+	require.NoError(t, err)
+	assert.Equal(t, "any", intf.Name)
+	assert.Equal(t, "all interfaces", intf.Comment)
+	assert.EqualValues(t, 128, intf.SnapLength)
+
+	data, ci, err := r.ReadPacketData()
+	require.NoError(t, err)
+	assert.Equal(t, first, data)
+	assert.True(t, start.Equal(ci.Timestamp))
+	assert.Equal(t, len(first), ci.CaptureLength)
+	assert.Equal(t, 1500, ci.Length)
+
+	data, ci, err = r.ReadPacketData()
+	require.NoError(t, err)
+	assert.Equal(t, second, data)
+	assert.True(t, start.Add(time.Millisecond).Equal(ci.Timestamp))
+	assert.Equal(t, len(second), ci.Length)
+
+	_, _, err = r.ReadPacketData()
+	assert.ErrorIs(t, err, io.EOF)
+	assert.Equal(t, "2 packets captured", stats.Comment)
+	assert.EqualValues(t, 2, stats.PacketsReceived)
+	assert.True(t, start.Equal(stats.StartTime))
+	assert.True(t, start.Add(time.Second).Equal(stats.EndTime))
+}
@@ -7,6 +7,7 @@
 package config
 
 import (
+	"strings"
 	"time"
 
 	cebpf "github.com/cilium/ebpf"
@@ -87,6 +88,14 @@ type Config struct {
 	// EnableRedisMonitoring specifies whether the tracer should monitor Redis traffic.
 	EnableRedisMonitoring bool
 
+	// EnableMemcachedMonitoring specifies whether the tracer should monitor Memcached traffic. The stats are only
+	// exposed by the debug endpoints of system-probe.
+	EnableMemcachedMonitoring bool
+
+	// EnableCassandraMonitoring specifies whether the tracer should monitor Cassandra traffic. The stats are only
+	// exposed by the debug endpoints of system-probe.
+	EnableCassandraMonitoring bool
+
 	// EnableNativeTLSMonitoring specifies whether the USM should monitor HTTPS traffic via native libraries.
 	// Supported libraries: OpenSSL, GnuTLS, LibCrypto.
 	EnableNativeTLSMonitoring bool
@@ -178,6 +187,14 @@ type Config struct {
 	// get flushed on every client request (default 30s check interval)
 	MaxRedisStatsBuffered int
 
+	// MaxMemcachedStatsBuffered represents the maximum number of Memcached stats we'll buffer in memory. These stats
+	// get flushed on every request to the /debug/memcached_monitoring endpoint of system-probe
+	MaxMemcachedStatsBuffered int
+
+	// MaxCassandraStatsBuffered represents the maximum number of Cassandra stats we'll buffer in memory. These stats
+	// get flushed on every request to the /debug/cassandra_monitoring endpoint of system-probe
+	MaxCassandraStatsBuffered int
+
 	// MaxConnectionsStateBuffered represents the maximum number of state objects that we'll store in memory. These state objects store
 	// the stats for a connection so we can accurately determine traffic change between client requests.
 	MaxConnectionsStateBuffered int
@@ -236,9 +253,86 @@ type Config struct {
 	// RecordedQueryTypes enables specific DNS query types to be recorded
 	RecordedQueryTypes []string
 
+	// CollectDNSEvents specifies whether the tracer should export each DNS query and its response as an event
+	// It is relevant *only* when DNSInspection is enabled.
+	CollectDNSEvents bool
+
+	// DNSEventsRateLimit is the maximum number of DNS events exported per second
+	DNSEventsRateLimit int
+
+	// MaxDNSEventsBuffered is the maximum number of DNS events buffered in memory between two polls
+	MaxDNSEventsBuffered int
+
+	// DNSEventsAllowedDomains restricts the DNS events to the queries of these domains and their subdomains
+	DNSEventsAllowedDomains []string
+
+	// DNSEventsDeniedDomains excludes the queries of these domains and their subdomains from the DNS events
+	DNSEventsDeniedDomains []string
+
+	// EnableFlowExport enables the export of the connections as IPFIX or NetFlow v9 records
+	EnableFlowExport bool
+
+	// FlowExportCollector is the `host:port` UDP address the flow records are sent to
+	FlowExportCollector string
+
+	// FlowExportProtocol is the protocol of the flow records, `ipfix` or `netflow9`
+	FlowExportProtocol string
+
+	// FlowExportInterval is how often the connections are exported
+	FlowExportInterval time.Duration
+
+	// FlowExportObservationDomainID is the observation domain ID (IPFIX), or source ID (NetFlow v9), of the records
+	FlowExportObservationDomainID uint32
+
+	// FlowExportMaxPacketSize is the maximum size of the UDP packets sent to the collector
+	FlowExportMaxPacketSize int
+
+	// FlowExportEnterpriseNumber is the private enterprise number of the process and container fields of the
+	// IPFIX records. Those fields are not exported when it's 0.
+	FlowExportEnterpriseNumber uint32
+
+	// EnablePacketCapture enables the debugging endpoint capturing the packets of a connection, process or container
+	EnablePacketCapture bool
+
+	// PacketCaptureMaxDuration is the maximum duration of the packet captures
+	PacketCaptureMaxDuration time.Duration
+
+	// PacketCaptureMaxSize is the maximum size, in bytes, of the packet captures
+	PacketCaptureMaxSize int64
+
+	// EnableAnomalyDetection enables the detection of the retransmit spikes and RTT increases of the TCP connections
+	EnableAnomalyDetection bool
+
+	// AnomalyDetectionInterval is how often the anomalies of the connections are detected
+	AnomalyDetectionInterval time.Duration
+
+	// AnomalyRetransmitRateThreshold is the share of the packets sent during an interval which are retransmitted,
+	// above which a connection is anomalous
+	AnomalyRetransmitRateThreshold float64
+
+	// AnomalyMinRetransmits is the number of retransmits during an interval below which the retransmit rate of a
+	// connection is ignored
+	AnomalyMinRetransmits uint32
+
+	// AnomalyRTTIncreaseFactor is the ratio of the RTT of a connection to its baseline above which it is anomalous
+	AnomalyRTTIncreaseFactor float64
+
+	// AnomalyRTTMinIncrease is the increase of the RTT of a connection over its baseline below which it is ignored
+	AnomalyRTTMinIncrease time.Duration
+
+	// MaxAnomaliesBuffered is the maximum number of anomalies buffered between two retrievals
+	MaxAnomaliesBuffered int
+
 	// HTTP replace rules
 	HTTPReplaceRules []*ReplaceRule
 
+	// HTTPOpenAPISpecs are the OpenAPI specifications whose route templates replace the HTTP paths
+	HTTPOpenAPISpecs []*OpenAPISpec
+
+	// EnableHTTPOpenAPIAnnotations enables loading the OpenAPI specifications given by the annotations
+	// of the pods and the labels of the containers
+	EnableHTTPOpenAPIAnnotations bool
+
 	// EnableProcessEventMonitoring enables consuming CWS process monitoring events from the runtime security module
 	EnableProcessEventMonitoring bool
 
@@ -340,6 +434,8 @@ func New() *Config {
 		EnableKafkaMonitoring:      cfg.GetBool(sysconfig.FullKeyPath(smNS, "enable_kafka_monitoring")),
 		EnablePostgresMonitoring:   cfg.GetBool(sysconfig.FullKeyPath(smNS, "enable_postgres_monitoring")),
 		EnableRedisMonitoring:      cfg.GetBool(sysconfig.FullKeyPath(smNS, "enable_redis_monitoring")),
+		EnableMemcachedMonitoring:  cfg.GetBool(sysconfig.FullKeyPath(smNS, "enable_memcached_monitoring")),
+		EnableCassandraMonitoring:  cfg.GetBool(sysconfig.FullKeyPath(smNS, "enable_cassandra_monitoring")),
 		EnableNativeTLSMonitoring:  cfg.GetBool(sysconfig.FullKeyPath(smNS, "tls", "native", "enabled")),
 		EnableIstioMonitoring:      cfg.GetBool(sysconfig.FullKeyPath(smNS, "tls", "istio", "enabled")),
 		EnvoyPath:                  cfg.GetString(sysconfig.FullKeyPath(smNS, "tls", "istio", "envoy_path")),
@@ -350,6 +446,8 @@ func New() *Config {
 		MaxPostgresStatsBuffered:   cfg.GetInt(sysconfig.FullKeyPath(smNS, "max_postgres_stats_buffered")),
 		MaxPostgresTelemetryBuffer: cfg.GetInt(sysconfig.FullKeyPath(smNS, "max_postgres_telemetry_buffer")),
 		MaxRedisStatsBuffered:      cfg.GetInt(sysconfig.FullKeyPath(smNS, "max_redis_stats_buffered")),
+		MaxMemcachedStatsBuffered:  cfg.GetInt(sysconfig.FullKeyPath(smNS, "max_memcached_stats_buffered")),
+		MaxCassandraStatsBuffered:  cfg.GetInt(sysconfig.FullKeyPath(smNS, "max_cassandra_stats_buffered")),
 
 		MaxTrackedHTTPConnections: cfg.GetInt64(sysconfig.FullKeyPath(smNS, "max_tracked_http_connections")),
 		HTTPNotificationThreshold: cfg.GetInt64(sysconfig.FullKeyPath(smNS, "http_notification_threshold")),
@@ -371,6 +469,32 @@ func New() *Config {
 
 		RecordedQueryTypes: cfg.GetStringSlice(sysconfig.FullKeyPath(netNS, "dns_recorded_query_types")),
 
+		CollectDNSEvents:        cfg.GetBool(sysconfig.FullKeyPath(netNS, "dns_events", "enabled")),
+		DNSEventsRateLimit:      cfg.GetInt(sysconfig.FullKeyPath(netNS, "dns_events", "rate_limit")),
+		MaxDNSEventsBuffered:    cfg.GetInt(sysconfig.FullKeyPath(netNS, "dns_events", "max_buffered")),
+		DNSEventsAllowedDomains: cfg.GetStringSlice(sysconfig.FullKeyPath(netNS, "dns_events", "allowed_domains")),
+		DNSEventsDeniedDomains:  cfg.GetStringSlice(sysconfig.FullKeyPath(netNS, "dns_events", "denied_domains")),
+
+		EnableFlowExport:              cfg.GetBool(sysconfig.FullKeyPath(netNS, "flow_export", "enabled")),
+		FlowExportCollector:           cfg.GetString(sysconfig.FullKeyPath(netNS, "flow_export", "collector")),
+		FlowExportProtocol:            strings.ToLower(cfg.GetString(sysconfig.FullKeyPath(netNS, "flow_export", "protocol"))),
+		FlowExportInterval:            cfg.GetDuration(sysconfig.FullKeyPath(netNS, "flow_export", "interval")),
+		FlowExportObservationDomainID: uint32(cfg.GetInt64(sysconfig.FullKeyPath(netNS, "flow_export", "observation_domain_id"))),
+		FlowExportMaxPacketSize:       cfg.GetInt(sysconfig.FullKeyPath(netNS, "flow_export", "max_packet_size")),
+		FlowExportEnterpriseNumber:    uint32(cfg.GetInt64(sysconfig.FullKeyPath(netNS, "flow_export", "enterprise_number"))),
+
+		EnablePacketCapture:      cfg.GetBool(sysconfig.FullKeyPath(netNS, "packet_capture", "enabled")),
+		PacketCaptureMaxDuration: cfg.GetDuration(sysconfig.FullKeyPath(netNS, "packet_capture", "max_duration")),
+		PacketCaptureMaxSize:     cfg.GetInt64(sysconfig.FullKeyPath(netNS, "packet_capture", "max_size")),
+
+		EnableAnomalyDetection:         cfg.GetBool(sysconfig.FullKeyPath(netNS, "anomaly_detection", "enabled")),
+		AnomalyDetectionInterval:       cfg.GetDuration(sysconfig.FullKeyPath(netNS, "anomaly_detection", "interval")),
+		AnomalyRetransmitRateThreshold: cfg.GetFloat64(sysconfig.FullKeyPath(netNS, "anomaly_detection", "retransmit_rate_threshold")),
+		AnomalyMinRetransmits:          uint32(cfg.GetInt64(sysconfig.FullKeyPath(netNS, "anomaly_detection", "min_retransmits"))),
+		AnomalyRTTIncreaseFactor:       cfg.GetFloat64(sysconfig.FullKeyPath(netNS, "anomaly_detection", "rtt_increase_factor")),
+		AnomalyRTTMinIncrease:          cfg.GetDuration(sysconfig.FullKeyPath(netNS, "anomaly_detection", "rtt_min_increase")),
+		MaxAnomaliesBuffered:           cfg.GetInt(sysconfig.FullKeyPath(netNS, "anomaly_detection", "max_buffered")),
+
 		EnableProcessEventMonitoring: cfg.GetBool(sysconfig.FullKeyPath(evNS, "network_process", "enabled")),
 		MaxProcessesTracked:          cfg.GetInt(sysconfig.FullKeyPath(evNS, "network_process", "max_processes_tracked")),
 
@@ -392,6 +516,8 @@ func New() *Config {
 		EnableUSMConnectionRollup: cfg.GetBool(sysconfig.FullKeyPath(smNS, "enable_connection_rollup")),
 		EnableUSMRingBuffers:      cfg.GetBool(sysconfig.FullKeyPath(smNS, "enable_ring_buffers")),
 		EnableUSMEventStream:      cfg.GetBool(sysconfig.FullKeyPath(smNS, "enable_event_stream")),
+
+		EnableHTTPOpenAPIAnnotations: cfg.GetBool(sysconfig.FullKeyPath(smNS, "enable_http_openapi_annotations")),
 	}
 
 	httpRRKey := sysconfig.FullKeyPath(smNS, "http_replace_rules")
@@ -402,6 +528,14 @@ func New() *Config {
 		c.HTTPReplaceRules = rr
 	}
 
+	openAPISpecsKey := sysconfig.FullKeyPath(smNS, "http_openapi_specs")
+	specs, err := parseOpenAPISpecs(cfg, openAPISpecsKey)
+	if err != nil {
+		log.Errorf("error parsing %q: %v", openAPISpecsKey, err)
+	} else {
+		c.HTTPOpenAPISpecs = specs
+	}
+
 	if !c.CollectTCPv4Conns {
 		log.Info("network tracer TCPv4 tracing disabled")
 	}
@@ -416,11 +550,19 @@ func New() *Config {
 	}
 	if !c.DNSInspection {
 		log.Info("network tracer DNS inspection disabled by configuration")
+		if c.CollectDNSEvents {
+			log.Warn("DNS events require DNS inspection, they will not be collected")
+			c.CollectDNSEvents = false
+		}
 	}
 
 	if !c.EnableProcessEventMonitoring {
 		log.Info("network process event monitoring disabled")
 	}
+	if c.EnableFlowExport && c.FlowExportCollector == "" {
+		log.Warn("the flow export requires network_config.flow_export.collector, the connections will not be exported")
+		c.EnableFlowExport = false
+	}
 	return c
 }
 
@@ -254,6 +254,62 @@ func TestEnableRedisMonitoring(t *testing.T) {
 	})
 }
 
+func TestEnableMemcachedMonitoring(t *testing.T) {
+	t.Run("via YAML", func(t *testing.T) {
+		mockSystemProbe := mock.NewSystemProbe(t)
+		mockSystemProbe.SetWithoutSource("service_monitoring_config.enable_memcached_monitoring", true)
+		cfg := New()
+
+		assert.True(t, cfg.EnableMemcachedMonitoring)
+	})
+
+	t.Run("via ENV variable", func(t *testing.T) {
+		mock.NewSystemProbe(t)
+		t.Setenv("DD_SERVICE_MONITORING_CONFIG_ENABLE_MEMCACHED_MONITORING", "true")
+		cfg := New()
+
+		_, err := sysconfig.New("", "")
+		require.NoError(t, err)
+
+		assert.True(t, cfg.EnableMemcachedMonitoring)
+	})
+
+	t.Run("default", func(t *testing.T) {
+		mock.NewSystemProbe(t)
+		cfg := New()
+
+		assert.False(t, cfg.EnableMemcachedMonitoring)
+	})
+}
+
+func TestEnableCassandraMonitoring(t *testing.T) {
+	t.Run("via YAML", func(t *testing.T) {
+		mockSystemProbe := mock.NewSystemProbe(t)
+		mockSystemProbe.SetWithoutSource("service_monitoring_config.enable_cassandra_monitoring", true)
+		cfg := New()
+
+		assert.True(t, cfg.EnableCassandraMonitoring)
+	})
+
+	t.Run("via ENV variable", func(t *testing.T) {
+		mock.NewSystemProbe(t)
+		t.Setenv("DD_SERVICE_MONITORING_CONFIG_ENABLE_CASSANDRA_MONITORING", "true")
+		cfg := New()
+
+		_, err := sysconfig.New("", "")
+		require.NoError(t, err)
+
+		assert.True(t, cfg.EnableCassandraMonitoring)
+	})
+
+	t.Run("default", func(t *testing.T) {
+		mock.NewSystemProbe(t)
+		cfg := New()
+
+		assert.False(t, cfg.EnableCassandraMonitoring)
+	})
+}
+
 func TestDefaultDisabledHTTP2Support(t *testing.T) {
 	mock.NewSystemProbe(t)
 	cfg := New()
@@ -371,6 +427,175 @@ func TestDisablingDNSDomainCollection(t *testing.T) {
 	})
 }
 
+func TestDNSEvents(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		mock.NewSystemProbe(t)
+		cfg := New()
+
+		assert.False(t, cfg.CollectDNSEvents)
+		assert.Equal(t, 100, cfg.DNSEventsRateLimit)
+		assert.Equal(t, 10000, cfg.MaxDNSEventsBuffered)
+		assert.Empty(t, cfg.DNSEventsAllowedDomains)
+		assert.Empty(t, cfg.DNSEventsDeniedDomains)
+	})
+
+	t.Run("via YAML", func(t *testing.T) {
+		mockSystemProbe := mock.NewSystemProbe(t)
+		mockSystemProbe.SetWithoutSource("network_config.dns_events.enabled", true)
+		mockSystemProbe.SetWithoutSource("network_config.dns_events.rate_limit", 10)
+		mockSystemProbe.SetWithoutSource("network_config.dns_events.denied_domains", []string{"svc.cluster.local"})
+		cfg := New()
+
+		assert.True(t, cfg.CollectDNSEvents)
+		assert.Equal(t, 10, cfg.DNSEventsRateLimit)
+		assert.Equal(t, []string{"svc.cluster.local"}, cfg.DNSEventsDeniedDomains)
+	})
+
+	t.Run("via ENV variable", func(t *testing.T) {
+		mock.NewSystemProbe(t)
+		t.Setenv("DD_NETWORK_CONFIG_DNS_EVENTS_ENABLED", "true")
+		t.Setenv("DD_NETWORK_CONFIG_DNS_EVENTS_ALLOWED_DOMAINS", "example.com corp.internal")
+		cfg := New()
+
+		assert.True(t, cfg.CollectDNSEvents)
+		assert.Equal(t, []string{"example.com", "corp.internal"}, cfg.DNSEventsAllowedDomains)
+	})
+
+	t.Run("requires DNS inspection", func(t *testing.T) {
+		mockSystemProbe := mock.NewSystemProbe(t)
+		mockSystemProbe.SetWithoutSource("network_config.dns_events.enabled", true)
+		mockSystemProbe.SetWithoutSource("system_probe_config.disable_dns_inspection", true)
+		cfg := New()
+
+		assert.False(t, cfg.CollectDNSEvents)
+	})
+}
+
+func TestFlowExport(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		mock.NewSystemProbe(t)
+		cfg := New()
+
+		assert.False(t, cfg.EnableFlowExport)
+		assert.Equal(t, "ipfix", cfg.FlowExportProtocol)
+		assert.Equal(t, 30*time.Second, cfg.FlowExportInterval)
+		assert.Equal(t, 1400, cfg.FlowExportMaxPacketSize)
+		assert.Zero(t, cfg.FlowExportEnterpriseNumber)
+	})
+
+	t.Run("via YAML", func(t *testing.T) {
+		mockSystemProbe := mock.NewSystemProbe(t)
+		mockSystemProbe.SetWithoutSource("network_config.flow_export.enabled", true)
+		mockSystemProbe.SetWithoutSource("network_config.flow_export.collector", "collector:4739")
+		mockSystemProbe.SetWithoutSource("network_config.flow_export.protocol", "NetFlow9")
+		mockSystemProbe.SetWithoutSource("network_config.flow_export.interval", "10s")
+		mockSystemProbe.SetWithoutSource("network_config.flow_export.observation_domain_id", 12)
+		mockSystemProbe.SetWithoutSource("network_config.flow_export.enterprise_number", 32473)
+		cfg := New()
+
+		assert.True(t, cfg.EnableFlowExport)
+		assert.Equal(t, "collector:4739", cfg.FlowExportCollector)
+		assert.Equal(t, "netflow9", cfg.FlowExportProtocol)
+		assert.Equal(t, 10*time.Second, cfg.FlowExportInterval)
+		assert.Equal(t, uint32(12), cfg.FlowExportObservationDomainID)
+		assert.Equal(t, uint32(32473), cfg.FlowExportEnterpriseNumber)
+	})
+
+	t.Run("via ENV variable", func(t *testing.T) {
+		mock.NewSystemProbe(t)
+		t.Setenv("DD_NETWORK_CONFIG_FLOW_EXPORT_ENABLED", "true")
+		t.Setenv("DD_NETWORK_CONFIG_FLOW_EXPORT_COLLECTOR", "10.0.0.1:2055")
+		cfg := New()
+
+		assert.True(t, cfg.EnableFlowExport)
+		assert.Equal(t, "10.0.0.1:2055", cfg.FlowExportCollector)
+	})
+
+	t.Run("requires a collector", func(t *testing.T) {
+		mockSystemProbe := mock.NewSystemProbe(t)
+		mockSystemProbe.SetWithoutSource("network_config.flow_export.enabled", true)
+		cfg := New()
+
+		assert.False(t, cfg.EnableFlowExport)
+	})
+}
+
+func TestPacketCapture(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		mock.NewSystemProbe(t)
+		cfg := New()
+
+		assert.True(t, cfg.EnablePacketCapture)
+		assert.Equal(t, 5*time.Minute, cfg.PacketCaptureMaxDuration)
+		assert.Equal(t, int64(100*1024*1024), cfg.PacketCaptureMaxSize)
+	})
+
+	t.Run("via YAML", func(t *testing.T) {
+		mockSystemProbe := mock.NewSystemProbe(t)
+		mockSystemProbe.SetWithoutSource("network_config.packet_capture.enabled", false)
+		mockSystemProbe.SetWithoutSource("network_config.packet_capture.max_duration", "1m")
+		mockSystemProbe.SetWithoutSource("network_config.packet_capture.max_size", 1024)
+		cfg := New()
+
+		assert.False(t, cfg.EnablePacketCapture)
+		assert.Equal(t, time.Minute, cfg.PacketCaptureMaxDuration)
+		assert.Equal(t, int64(1024), cfg.PacketCaptureMaxSize)
+	})
+
+	t.Run("via ENV variable", func(t *testing.T) {
+		mock.NewSystemProbe(t)
+		t.Setenv("DD_NETWORK_CONFIG_PACKET_CAPTURE_ENABLED", "false")
+		t.Setenv("DD_NETWORK_CONFIG_PACKET_CAPTURE_MAX_DURATION", "30s")
+		cfg := New()
+
+		assert.False(t, cfg.EnablePacketCapture)
+		assert.Equal(t, 30*time.Second, cfg.PacketCaptureMaxDuration)
+	})
+}
+
+func TestAnomalyDetection(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		mock.NewSystemProbe(t)
+		cfg := New()
+
+		assert.False(t, cfg.EnableAnomalyDetection)
+		assert.Equal(t, 30*time.Second, cfg.AnomalyDetectionInterval)
+		assert.Equal(t, 0.05, cfg.AnomalyRetransmitRateThreshold)
+		assert.Equal(t, uint32(10), cfg.AnomalyMinRetransmits)
+		assert.Equal(t, 2.0, cfg.AnomalyRTTIncreaseFactor)
+		assert.Equal(t, 20*time.Millisecond, cfg.AnomalyRTTMinIncrease)
+		assert.Equal(t, 1000, cfg.MaxAnomaliesBuffered)
+	})
+
+	t.Run("via YAML", func(t *testing.T) {
+		mockSystemProbe := mock.NewSystemProbe(t)
+		mockSystemProbe.SetWithoutSource("network_config.anomaly_detection.enabled", true)
+		mockSystemProbe.SetWithoutSource("network_config.anomaly_detection.interval", "1m")
+		mockSystemProbe.SetWithoutSource("network_config.anomaly_detection.retransmit_rate_threshold", 0.1)
+		mockSystemProbe.SetWithoutSource("network_config.anomaly_detection.min_retransmits", 5)
+		mockSystemProbe.SetWithoutSource("network_config.anomaly_detection.rtt_increase_factor", 3)
+		mockSystemProbe.SetWithoutSource("network_config.anomaly_detection.rtt_min_increase", "50ms")
+		cfg := New()
+
+		assert.True(t, cfg.EnableAnomalyDetection)
+		assert.Equal(t, time.Minute, cfg.AnomalyDetectionInterval)
+		assert.Equal(t, 0.1, cfg.AnomalyRetransmitRateThreshold)
+		assert.Equal(t, uint32(5), cfg.AnomalyMinRetransmits)
+		assert.Equal(t, 3.0, cfg.AnomalyRTTIncreaseFactor)
+		assert.Equal(t, 50*time.Millisecond, cfg.AnomalyRTTMinIncrease)
+	})
+
+	t.Run("via ENV variable", func(t *testing.T) {
+		mock.NewSystemProbe(t)
+		t.Setenv("DD_NETWORK_CONFIG_ANOMALY_DETECTION_ENABLED", "true")
+		t.Setenv("DD_NETWORK_CONFIG_ANOMALY_DETECTION_RETRANSMIT_RATE_THRESHOLD", "0.2")
+		cfg := New()
+
+		assert.True(t, cfg.EnableAnomalyDetection)
+		assert.Equal(t, 0.2, cfg.AnomalyRetransmitRateThreshold)
+	})
+}
+
 func TestSettingMaxDNSStats(t *testing.T) {
 	t.Run("via YAML", func(t *testing.T) {
 		mockSystemProbe := mock.NewSystemProbe(t)
@@ -535,6 +760,55 @@ func TestHTTPReplaceRules(t *testing.T) {
 	})
 }
 
+func TestHTTPOpenAPISpecs(t *testing.T) {
+	expected := []*OpenAPISpec{
+		{Service: "users", Path: "/etc/specs/users.yaml", Ports: []uint16{8080, 8443}},
+		{Service: "pets", Path: "/etc/specs/pets.json"},
+	}
+
+	t.Run("via YAML", func(t *testing.T) {
+		mockSystemProbe := mock.NewSystemProbe(t)
+		mockSystemProbe.SetWithoutSource("service_monitoring_config.http_openapi_specs", []map[string]interface{}{
+			{"service": "users", "path": "/etc/specs/users.yaml", "ports": []int{8080, 8443}},
+			{"service": "pets", "path": "/etc/specs/pets.json"},
+		})
+		cfg := New()
+
+		assert.Equal(t, expected, cfg.HTTPOpenAPISpecs)
+		assert.True(t, cfg.EnableHTTPOpenAPIAnnotations)
+	})
+
+	t.Run("via ENV variable", func(t *testing.T) {
+		mock.NewSystemProbe(t)
+		t.Setenv("DD_SERVICE_MONITORING_CONFIG_HTTP_OPENAPI_SPECS", `[
+			{"service": "users", "path": "/etc/specs/users.yaml", "ports": [8080, 8443]},
+			{"service": "pets", "path": "/etc/specs/pets.json"}
+		]`)
+		t.Setenv("DD_SERVICE_MONITORING_CONFIG_ENABLE_HTTP_OPENAPI_ANNOTATIONS", "false")
+		cfg := New()
+
+		assert.Equal(t, expected, cfg.HTTPOpenAPISpecs)
+		assert.False(t, cfg.EnableHTTPOpenAPIAnnotations)
+	})
+
+	t.Run("missing path", func(t *testing.T) {
+		mockSystemProbe := mock.NewSystemProbe(t)
+		mockSystemProbe.SetWithoutSource("service_monitoring_config.http_openapi_specs", []map[string]interface{}{
+			{"service": "users"},
+		})
+		cfg := New()
+
+		assert.Empty(t, cfg.HTTPOpenAPISpecs)
+	})
+
+	t.Run("Not enabled", func(t *testing.T) {
+		mock.NewSystemProbe(t)
+		cfg := New()
+
+		assert.Empty(t, cfg.HTTPOpenAPISpecs)
+	})
+}
+
 func TestMaxTrackedHTTPConnections(t *testing.T) {
 	t.Run("via deprecated YAML", func(t *testing.T) {
 		mockSystemProbe := mock.NewSystemProbe(t)
@@ -1221,6 +1495,56 @@ func TestMaxRedisStatsBuffered(t *testing.T) {
 	})
 }
 
+func TestMaxMemcachedStatsBuffered(t *testing.T) {
+	t.Run("value set through env var", func(t *testing.T) {
+		mock.NewSystemProbe(t)
+		t.Setenv("DD_SERVICE_MONITORING_CONFIG_MAX_MEMCACHED_STATS_BUFFERED", "50000")
+		cfg := New()
+
+		assert.Equal(t, 50000, cfg.MaxMemcachedStatsBuffered)
+	})
+
+	t.Run("value set through yaml", func(t *testing.T) {
+		mockSystemProbe := mock.NewSystemProbe(t)
+		mockSystemProbe.SetWithoutSource("service_monitoring_config.max_memcached_stats_buffered", 30000)
+		cfg := New()
+
+		assert.Equal(t, 30000, cfg.MaxMemcachedStatsBuffered)
+	})
+
+	t.Run("default", func(t *testing.T) {
+		mock.NewSystemProbe(t)
+		cfg := New()
+
+		assert.Equal(t, 100000, cfg.MaxMemcachedStatsBuffered)
+	})
+}
+
+func TestMaxCassandraStatsBuffered(t *testing.T) {
+	t.Run("value set through env var", func(t *testing.T) {
+		mock.NewSystemProbe(t)
+		t.Setenv("DD_SERVICE_MONITORING_CONFIG_MAX_CASSANDRA_STATS_BUFFERED", "50000")
+		cfg := New()
+
+		assert.Equal(t, 50000, cfg.MaxCassandraStatsBuffered)
+	})
+
+	t.Run("value set through yaml", func(t *testing.T) {
+		mockSystemProbe := mock.NewSystemProbe(t)
+		mockSystemProbe.SetWithoutSource("service_monitoring_config.max_cassandra_stats_buffered", 30000)
+		cfg := New()
+
+		assert.Equal(t, 30000, cfg.MaxCassandraStatsBuffered)
+	})
+
+	t.Run("default", func(t *testing.T) {
+		mock.NewSystemProbe(t)
+		cfg := New()
+
+		assert.Equal(t, 100000, cfg.MaxCassandraStatsBuffered)
+	})
+}
+
 func TestNetworkConfigEnabled(t *testing.T) {
 	ys := true
 
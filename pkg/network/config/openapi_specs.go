@@ -0,0 +1,47 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/DataDog/datadog-agent/pkg/config/model"
+	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
+	"github.com/DataDog/datadog-agent/pkg/config/structure"
+)
+
+// OpenAPISpec specifies the OpenAPI (or Swagger) specification of a service, whose route templates
+// replace the HTTP paths of the service.
+type OpenAPISpec struct {
+	// Service is the name of the service, only used to report errors.
+	Service string `mapstructure:"service"`
+
+	// Path is the path of the specification file, in JSON or YAML.
+	Path string `mapstructure:"path"`
+
+	// Ports are the ports the service listens on. The specification applies to every service when empty.
+	Ports []uint16 `mapstructure:"ports"`
+}
+
+func parseOpenAPISpecs(cfg model.Config, key string) ([]*OpenAPISpec, error) {
+	if !pkgconfigsetup.SystemProbe().IsSet(key) {
+		return nil, nil
+	}
+
+	specs := make([]*OpenAPISpec, 0)
+	if err := structure.UnmarshalKey(cfg, key, &specs); err != nil {
+		return nil, fmt.Errorf("specs format should be of the form '[{\"service\":\"name\",\"path\":\"/path/to/spec.yaml\",\"ports\":[8080]}]', error: %w", err)
+	}
+
+	for _, s := range specs {
+		if s.Path == "" {
+			return nil, errors.New(`all specs must have a "path"`)
+		}
+	}
+
+	return specs, nil
+}
@@ -0,0 +1,222 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+//go:build (windows && npm) || linux_bpf
+
+package dns
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/google/gopacket/layers"
+	"golang.org/x/time/rate"
+
+	"github.com/DataDog/datadog-agent/pkg/network/config"
+	"github.com/DataDog/datadog-agent/pkg/telemetry"
+)
+
+const (
+	dnsEventKeeperModuleName = "network_tracer__dns_events"
+
+	// timeoutRcode is the rcode of the queries without response
+	timeoutRcode = "TIMEOUT"
+)
+
+var eventsTelemetry = struct {
+	events   telemetry.Counter
+	dropped  telemetry.Counter
+	filtered telemetry.Counter
+}{
+	telemetry.NewCounter(dnsEventKeeperModuleName, "events", []string{}, "Counter measuring the number of DNS events buffered"),
+	telemetry.NewCounter(dnsEventKeeperModuleName, "dropped", []string{"reason"}, "Counter measuring the number of DNS events dropped, by reason"),
+	telemetry.NewCounter(dnsEventKeeperModuleName, "filtered", []string{}, "Counter measuring the number of DNS queries excluded by the domain lists"),
+}
+
+// rcodeNames holds the mnemonics of the common DNS response codes
+var rcodeNames = map[uint8]string{
+	0: "NOERROR",
+	1: "FORMERR",
+	2: "SERVFAIL",
+	3: "NXDOMAIN",
+	4: "NOTIMP",
+	5: "REFUSED",
+}
+
+type pendingQuery struct {
+	ts       time.Time
+	question string
+	qtype    QueryType
+}
+
+// dnsEventKeeper matches the DNS queries with their response and buffers them as events
+type dnsEventKeeper struct {
+	mux         sync.Mutex
+	pending     map[stateKey]pendingQuery
+	events      []Event
+	timeout     time.Duration
+	maxPending  int
+	maxBuffered int
+	limiter     *rate.Limiter
+	filter      *domainFilter
+}
+
+func newDNSEventKeeper(cfg *config.Config) *dnsEventKeeper {
+	limit := rate.Inf
+	if cfg.DNSEventsRateLimit > 0 {
+		limit = rate.Limit(cfg.DNSEventsRateLimit)
+	}
+	return &dnsEventKeeper{
+		pending:     make(map[stateKey]pendingQuery),
+		timeout:     cfg.DNSTimeout,
+		maxPending:  maxStateMapSize,
+		maxBuffered: cfg.MaxDNSEventsBuffered,
+		limiter:     rate.NewLimiter(limit, max(cfg.DNSEventsRateLimit, 1)),
+		filter:      newDomainFilter(cfg.DNSEventsAllowedDomains, cfg.DNSEventsDeniedDomains),
+	}
+}
+
+// ProcessPacketInfo records a query, or turns a response to a recorded query into an event
+func (k *dnsEventKeeper) ProcessPacketInfo(info dnsPacketInfo, t *translation, ts time.Time) {
+	k.mux.Lock()
+	defer k.mux.Unlock()
+	sk := stateKey{key: info.key, id: info.transactionID}
+
+	if info.pktType == query {
+		if _, ok := k.pending[sk]; ok {
+			return
+		}
+		question := strings.ToLower(ToString(info.question))
+		if !k.filter.matches(question) {
+			eventsTelemetry.filtered.Inc()
+			return
+		}
+		if len(k.pending) >= k.maxPending {
+			eventsTelemetry.dropped.Inc("pending_full")
+			return
+		}
+		k.pending[sk] = pendingQuery{ts: ts, question: question, qtype: info.queryType}
+		return
+	}
+
+	q, ok := k.pending[sk]
+	if !ok {
+		return
+	}
+	delete(k.pending, sk)
+
+	event := newEvent(info.key, q)
+	event.Rcode = rcodeName(info.rCode)
+	if ts.After(q.ts) {
+		event.LatencyMicros = uint64(ts.Sub(q.ts).Microseconds())
+	}
+	if info.pktType == successfulResponse {
+		for ip := range t.ips {
+			event.Answers = append(event.Answers, ip.String())
+		}
+		slices.Sort(event.Answers)
+	}
+	k.add(event)
+}
+
+func (k *dnsEventKeeper) add(event Event) {
+	if len(k.events) >= k.maxBuffered {
+		eventsTelemetry.dropped.Inc("buffer_full")
+		return
+	}
+	if !k.limiter.Allow() {
+		eventsTelemetry.dropped.Inc("rate_limit")
+		return
+	}
+	eventsTelemetry.events.Inc()
+	k.events = append(k.events, event)
+}
+
+// GetAndResetEvents returns the events buffered since the last call, after turning the queries
+// without response for longer than the DNS timeout into events
+func (k *dnsEventKeeper) GetAndResetEvents(now time.Time) []Event {
+	k.mux.Lock()
+	defer k.mux.Unlock()
+	for sk, q := range k.pending {
+		if now.Sub(q.ts) <= k.timeout {
+			continue
+		}
+		delete(k.pending, sk)
+		event := newEvent(sk.key, q)
+		event.Rcode = timeoutRcode
+		k.add(event)
+	}
+
+	events := k.events
+	k.events = nil
+	return events
+}
+
+func newEvent(key Key, q pendingQuery) Event {
+	protocol := "udp"
+	if key.Protocol == syscall.IPPROTO_TCP {
+		protocol = "tcp"
+	}
+	return Event{
+		Timestamp:  q.ts,
+		ClientIP:   key.ClientIP.String(),
+		ClientPort: key.ClientPort,
+		ServerIP:   key.ServerIP.String(),
+		Protocol:   protocol,
+		Question:   q.question,
+		QueryType:  layers.DNSType(q.qtype).String(),
+		Key:        key,
+	}
+}
+
+func rcodeName(rcode uint8) string {
+	if name, ok := rcodeNames[rcode]; ok {
+		return name
+	}
+	return fmt.Sprintf("RCODE%d", rcode)
+}
+
+// domainFilter selects the domains of the DNS events with allow and deny lists of domains,
+// which match the domains and their subdomains
+type domainFilter struct {
+	allowed []string
+	denied  []string
+}
+
+func newDomainFilter(allowed, denied []string) *domainFilter {
+	normalize := func(domains []string) []string {
+		var normalized []string
+		for _, d := range domains {
+			d = strings.Trim(strings.TrimPrefix(strings.ToLower(strings.TrimSpace(d)), "*."), ".")
+			if d != "" {
+				normalized = append(normalized, d)
+			}
+		}
+		return normalized
+	}
+	return &domainFilter{allowed: normalize(allowed), denied: normalize(denied)}
+}
+
+// matches returns whether the DNS events of the domain are exported
+func (f *domainFilter) matches(domain string) bool {
+	domain = strings.TrimSuffix(domain, ".")
+	if matchesAnyDomain(domain, f.denied) {
+		return false
+	}
+	return len(f.allowed) == 0 || matchesAnyDomain(domain, f.allowed)
+}
+
+func matchesAnyDomain(domain string, domains []string) bool {
+	for _, d := range domains {
+		if domain == d || strings.HasSuffix(domain, "."+d) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,131 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+//go:build (windows && npm) || linux_bpf
+
+package dns
+
+import (
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/network/config"
+	"github.com/DataDog/datadog-agent/pkg/process/util"
+)
+
+func newTestEventKeeper(allowed, denied []string) *dnsEventKeeper {
+	return newDNSEventKeeper(&config.Config{
+		DNSTimeout:              time.Second,
+		DNSEventsRateLimit:      0,
+		MaxDNSEventsBuffered:    10,
+		DNSEventsAllowedDomains: allowed,
+		DNSEventsDeniedDomains:  denied,
+	})
+}
+
+func testEventPacketInfo(id uint16, pktType packetType, question string) dnsPacketInfo {
+	return dnsPacketInfo{
+		transactionID: id,
+		key: Key{
+			ServerIP:   util.AddressFromString("8.8.8.8"),
+			ClientIP:   util.AddressFromString("10.0.0.1"),
+			ClientPort: 40000,
+			Protocol:   syscall.IPPROTO_UDP,
+		},
+		pktType:   pktType,
+		question:  ToHostname(question),
+		queryType: QueryType(1),
+	}
+}
+
+func TestDNSEventKeeper(t *testing.T) {
+	k := newTestEventKeeper(nil, nil)
+	start := time.Now()
+
+	// successful lookup
+	k.ProcessPacketInfo(testEventPacketInfo(1, query, "Example.COM"), nil, start)
+	tr := &translation{ips: map[util.Address]time.Time{
+		util.AddressFromString("93.184.216.35"): {},
+		util.AddressFromString("93.184.216.34"): {},
+	}}
+	resp := testEventPacketInfo(1, successfulResponse, "")
+	k.ProcessPacketInfo(resp, tr, start.Add(2*time.Millisecond))
+
+	// failed lookup
+	k.ProcessPacketInfo(testEventPacketInfo(2, query, "missing.example.com"), nil, start)
+	resp = testEventPacketInfo(2, failedResponse, "")
+	resp.rCode = 3
+	k.ProcessPacketInfo(resp, nil, start.Add(time.Millisecond))
+
+	// response without query
+	k.ProcessPacketInfo(testEventPacketInfo(3, successfulResponse, ""), tr, start)
+
+	// lookup without response
+	k.ProcessPacketInfo(testEventPacketInfo(4, query, "slow.example.com"), nil, start)
+
+	events := k.GetAndResetEvents(start.Add(500 * time.Millisecond))
+	require.Len(t, events, 2)
+	assert.Equal(t, "example.com", events[0].Question)
+	assert.Equal(t, "A", events[0].QueryType)
+	assert.Equal(t, "NOERROR", events[0].Rcode)
+	assert.Equal(t, "udp", events[0].Protocol)
+	assert.Equal(t, "10.0.0.1", events[0].ClientIP)
+	assert.Equal(t, "8.8.8.8", events[0].ServerIP)
+	assert.Equal(t, []string{"93.184.216.34", "93.184.216.35"}, events[0].Answers)
+	assert.Equal(t, uint64(2000), events[0].LatencyMicros)
+	assert.Equal(t, "NXDOMAIN", events[1].Rcode)
+	assert.Empty(t, events[1].Answers)
+
+	// the query is pending until the DNS timeout
+	events = k.GetAndResetEvents(start.Add(2 * time.Second))
+	require.Len(t, events, 1)
+	assert.Equal(t, "slow.example.com", events[0].Question)
+	assert.Equal(t, timeoutRcode, events[0].Rcode)
+	assert.Empty(t, k.GetAndResetEvents(start.Add(3*time.Second)))
+}
+
+func TestDNSEventKeeperLimits(t *testing.T) {
+	lookup := func(k *dnsEventKeeper, id uint16, question string) {
+		now := time.Now()
+		k.ProcessPacketInfo(testEventPacketInfo(id, query, question), nil, now)
+		k.ProcessPacketInfo(testEventPacketInfo(id, failedResponse, ""), nil, now)
+	}
+
+	t.Run("buffer", func(t *testing.T) {
+		k := newTestEventKeeper(nil, nil)
+		for i := 0; i < 15; i++ {
+			lookup(k, uint16(i), "example.com")
+		}
+		assert.Len(t, k.GetAndResetEvents(time.Now()), 10)
+	})
+
+	t.Run("rate limit", func(t *testing.T) {
+		k := newDNSEventKeeper(&config.Config{DNSTimeout: time.Second, DNSEventsRateLimit: 3, MaxDNSEventsBuffered: 10})
+		for i := 0; i < 5; i++ {
+			lookup(k, uint16(i), "example.com")
+		}
+		assert.Len(t, k.GetAndResetEvents(time.Now()), 3)
+	})
+
+	t.Run("domains", func(t *testing.T) {
+		k := newTestEventKeeper([]string{"*.example.com", "datadoghq.com."}, []string{"internal.example.com"})
+		lookup(k, 1, "example.com")
+		lookup(k, 2, "www.example.com")
+		lookup(k, 3, "db.internal.example.com")
+		lookup(k, 4, "app.datadoghq.com")
+		lookup(k, 5, "notexample.com")
+		lookup(k, 6, "example.org")
+
+		var questions []string
+		for _, e := range k.GetAndResetEvents(time.Now()) {
+			questions = append(questions, e.Question)
+		}
+		assert.Equal(t, []string{"example.com", "www.example.com", "app.datadoghq.com"}, questions)
+	})
+}
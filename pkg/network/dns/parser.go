@@ -68,6 +68,7 @@ type dnsParser struct {
 	dnsPayload         *layers.DNS
 	collectDNSStats    bool
 	collectDNSDomains  bool
+	collectDNSEvents   bool
 	recordedQueryTypes map[layers.DNSType]struct{}
 }
 
@@ -104,6 +105,7 @@ func newDNSParser(layerType gopacket.LayerType, cfg *config.Config) *dnsParser {
 		dnsPayload:         dnsPayload,
 		collectDNSStats:    cfg.CollectDNSStats,
 		collectDNSDomains:  cfg.CollectDNSDomains,
+		collectDNSEvents:   cfg.CollectDNSEvents,
 		recordedQueryTypes: queryTypes,
 	}
 }
@@ -128,7 +130,7 @@ func (p *dnsParser) ParseInto(data []byte, t *translation, pktInfo *dnsPacketInf
 		return err
 	}
 
-	if !p.collectDNSStats {
+	if !p.collectDNSStats && !p.collectDNSEvents {
 		return nil
 	}
 
@@ -193,7 +195,8 @@ func (p *dnsParser) parseAnswerInto(
 	if !dns.QR {
 		pktInfo.pktType = query
 		pktInfo.queryType = QueryType(question.Type)
-		if p.collectDNSDomains {
+		// the DNS events always carry the question, the stats only when the domains are collected
+		if p.collectDNSDomains || p.collectDNSEvents {
 			pktInfo.question = ToHostname(string(question.Name))
 		} else {
 			pktInfo.question = ToHostname("")
@@ -48,9 +48,11 @@ type socketFilterSnooper struct {
 	parser          *dnsParser
 	cache           *reverseDNSCache
 	statKeeper      *dnsStatKeeper
+	eventKeeper     *dnsEventKeeper
 	exit            chan struct{}
 	wg              sync.WaitGroup
 	collectLocalDNS bool
+	collectDomains  bool
 	once            sync.Once
 
 	// cache translation object to avoid allocations
@@ -74,14 +76,21 @@ func newSocketFilterSnooper(cfg *config.Config, source filter.PacketSource) (*so
 	} else {
 		log.Infof("DNS Stats Collection has been disabled.")
 	}
+	var eventKeeper *dnsEventKeeper
+	if cfg.CollectDNSEvents {
+		eventKeeper = newDNSEventKeeper(cfg)
+		log.Infof("DNS events collection has been enabled. Maximum number of events buffered: %d", cfg.MaxDNSEventsBuffered)
+	}
 	snooper := &socketFilterSnooper{
 		source:          source,
 		parser:          newDNSParser(source.LayerType(), cfg),
 		cache:           cache,
 		statKeeper:      statKeeper,
+		eventKeeper:     eventKeeper,
 		translation:     new(translation),
 		exit:            make(chan struct{}),
 		collectLocalDNS: cfg.CollectLocalDNS,
+		collectDomains:  cfg.CollectDNSDomains,
 	}
 
 	// Start consuming packets
@@ -113,6 +122,14 @@ func (s *socketFilterSnooper) GetDNSStats() StatsByKeyByNameByType {
 	return s.statKeeper.GetAndResetAllStats()
 }
 
+// GetDNSEvents returns the DNS lookups seen since the last call
+func (s *socketFilterSnooper) GetDNSEvents() []Event {
+	if s.eventKeeper == nil {
+		return nil
+	}
+	return s.eventKeeper.GetAndResetEvents(time.Now())
+}
+
 // Start starts the snooper (no-op currently)
 func (s *socketFilterSnooper) Start() error {
 	return nil // no-op as this is done in newSocketFilterSnooper above
@@ -152,8 +169,17 @@ func (s *socketFilterSnooper) processPacket(data []byte, _ filter.PacketInfo, ts
 		return nil
 	}
 
-	if s.statKeeper != nil && (s.collectLocalDNS || !pktInfo.key.ServerIP.IsLoopback()) {
-		s.statKeeper.ProcessPacketInfo(pktInfo, ts)
+	if s.collectLocalDNS || !pktInfo.key.ServerIP.IsLoopback() {
+		if s.eventKeeper != nil {
+			s.eventKeeper.ProcessPacketInfo(pktInfo, t, ts)
+		}
+		if s.statKeeper != nil {
+			if !s.collectDomains {
+				// the question is only parsed for the DNS events
+				pktInfo.question = ToHostname("")
+			}
+			s.statKeeper.ProcessPacketInfo(pktInfo, ts)
+		}
 	}
 
 	if pktInfo.pktType == successfulResponse {
@@ -7,6 +7,8 @@
 package dns
 
 import (
+	"time"
+
 	"github.com/DataDog/datadog-agent/pkg/process/util"
 	"github.com/DataDog/datadog-agent/pkg/util/intern"
 )
@@ -66,6 +68,8 @@ type StatsByKeyByNameByType map[Key]map[Hostname]map[QueryType]Stats
 type ReverseDNS interface {
 	Resolve(map[util.Address]struct{}) map[util.Address][]Hostname
 	GetDNSStats() StatsByKeyByNameByType
+	// GetDNSEvents returns the DNS lookups completed since the last call, when DNS events are enabled
+	GetDNSEvents() []Event
 
 	// WaitForDomain is used in tests to ensure a domain has been
 	// seen by the ReverseDNS.
@@ -91,3 +95,24 @@ type Stats struct {
 	FailureLatencySum uint64
 	CountByRcode      map[uint32]uint32
 }
+
+// Event is a DNS lookup, a query and its response, seen by the snooper
+type Event struct {
+	Timestamp   time.Time `json:"timestamp"`
+	PID         uint32    `json:"pid,omitempty"`
+	ContainerID string    `json:"container_id,omitempty"`
+	ClientIP    string    `json:"client_ip"`
+	ClientPort  uint16    `json:"client_port"`
+	ServerIP    string    `json:"server_ip"`
+	Protocol    string    `json:"protocol"`
+	Question    string    `json:"question"`
+	QueryType   string    `json:"query_type"`
+	// Rcode is the response code, like NOERROR or NXDOMAIN, or TIMEOUT when no response was seen
+	Rcode string `json:"rcode"`
+	// Answers holds the addresses of the successful responses
+	Answers       []string `json:"answers,omitempty"`
+	LatencyMicros uint64   `json:"latency_us"`
+
+	// Key identifies the connection of the lookup, to find the process that made it
+	Key Key `json:"-"`
+}
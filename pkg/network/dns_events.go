@@ -0,0 +1,83 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package network
+
+import (
+	"sync"
+
+	"github.com/DataDog/datadog-agent/pkg/network/dns"
+)
+
+type dnsProcess struct {
+	pid         uint32
+	containerID string
+}
+
+// DNSEventResolver attributes the DNS events to the processes which made the lookups, with the DNS
+// connections of the tracer. The DNS connections are short-lived, so the closed ones are kept until
+// the next resolution.
+type DNSEventResolver struct {
+	mux     sync.Mutex
+	closed  map[dns.Key]dnsProcess
+	maxSize int
+}
+
+// NewDNSEventResolver returns a DNSEventResolver keeping up to maxSize closed connections
+func NewDNSEventResolver(maxSize int) *DNSEventResolver {
+	return &DNSEventResolver{
+		closed:  make(map[dns.Key]dnsProcess),
+		maxSize: maxSize,
+	}
+}
+
+// AddClosedConnection keeps the process of a closed connection if it's a DNS one
+func (r *DNSEventResolver) AddClosedConnection(c *ConnectionStats) {
+	key, ok := DNSKey(c)
+	if !ok {
+		return
+	}
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	if len(r.closed) >= r.maxSize {
+		return
+	}
+	r.closed[key] = newDNSProcess(c)
+}
+
+// Resolve sets the PID and the container ID of the events, with the active connections given and the
+// closed connections added since the last call
+func (r *DNSEventResolver) Resolve(events []dns.Event, active []ConnectionStats) {
+	r.mux.Lock()
+	closed := r.closed
+	r.closed = make(map[dns.Key]dnsProcess)
+	r.mux.Unlock()
+
+	processes := make(map[dns.Key]dnsProcess, len(active))
+	for i := range active {
+		if key, ok := DNSKey(&active[i]); ok {
+			processes[key] = newDNSProcess(&active[i])
+		}
+	}
+
+	for i := range events {
+		p, ok := processes[events[i].Key]
+		if !ok {
+			if p, ok = closed[events[i].Key]; !ok {
+				continue
+			}
+		}
+		events[i].PID = p.pid
+		events[i].ContainerID = p.containerID
+	}
+}
+
+func newDNSProcess(c *ConnectionStats) dnsProcess {
+	p := dnsProcess{pid: c.Pid}
+	if c.ContainerID.Source != nil {
+		p.containerID, _ = c.ContainerID.Source.Get().(string)
+	}
+	return p
+}
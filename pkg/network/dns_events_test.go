@@ -0,0 +1,70 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package network
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go4.org/intern"
+
+	"github.com/DataDog/datadog-agent/pkg/network/dns"
+	"github.com/DataDog/datadog-agent/pkg/process/util"
+)
+
+func TestDNSEventResolver(t *testing.T) {
+	dnsConn := func(sport uint16, pid uint32, containerID string) ConnectionStats {
+		c := ConnectionStats{ConnectionTuple: ConnectionTuple{
+			Source: util.AddressFromString("10.1.1.1"),
+			Dest:   util.AddressFromString("10.2.2.2"),
+			SPort:  sport,
+			DPort:  53,
+			Type:   UDP,
+			Pid:    pid,
+		}}
+		if containerID != "" {
+			c.ContainerID.Source = intern.GetByString(containerID)
+		}
+		return c
+	}
+	event := func(c ConnectionStats) dns.Event {
+		key, ok := DNSKey(&c)
+		assert.True(t, ok)
+		return dns.Event{Key: key}
+	}
+
+	active := dnsConn(40000, 42, "abc")
+	closed := dnsConn(40001, 43, "")
+	unknown := dnsConn(40002, 44, "")
+	http := dnsConn(40003, 45, "")
+	http.DPort = 80
+
+	r := NewDNSEventResolver(10)
+	r.AddClosedConnection(&closed)
+	r.AddClosedConnection(&http)
+	events := []dns.Event{event(active), event(closed), event(unknown)}
+	r.Resolve(events, []ConnectionStats{active, http})
+
+	assert.Equal(t, uint32(42), events[0].PID)
+	assert.Equal(t, "abc", events[0].ContainerID)
+	assert.Equal(t, uint32(43), events[1].PID)
+	assert.Empty(t, events[1].ContainerID)
+	assert.Zero(t, events[2].PID)
+
+	// the closed connections are only kept until the next resolution
+	events = []dns.Event{event(closed)}
+	r.Resolve(events, nil)
+	assert.Zero(t, events[0].PID)
+
+	// the closed connections are bounded
+	r = NewDNSEventResolver(1)
+	r.AddClosedConnection(&closed)
+	r.AddClosedConnection(&unknown)
+	events = []dns.Event{event(closed), event(unknown)}
+	r.Resolve(events, nil)
+	assert.Equal(t, uint32(43), events[0].PID)
+	assert.Zero(t, events[1].PID)
+}
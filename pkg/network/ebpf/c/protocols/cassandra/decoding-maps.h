@@ -0,0 +1,15 @@
+#ifndef __CASSANDRA_MAPS_H
+#define __CASSANDRA_MAPS_H
+
+#include "bpf_helpers.h"
+#include "map-defs.h"
+
+#include "protocols/cassandra/types.h"
+
+// Keeps track of in-flight Cassandra transactions
+BPF_HASH_MAP(cassandra_in_flight, cassandra_stream_key_t, cassandra_transaction_t, 0)
+
+// Acts as a scratch buffer for Cassandra events, for preparing events before they are sent to userspace.
+BPF_PERCPU_ARRAY_MAP(cassandra_scratch_buffer, cassandra_event_t, 1)
+
+#endif
@@ -0,0 +1,212 @@
+#ifndef __CASSANDRA_DECODING_H
+#define __CASSANDRA_DECODING_H
+
+#include "bpf_builtins.h"
+#include "bpf_telemetry.h"
+
+#include "protocols/sockfd.h"
+
+#include "protocols/helpers/pktbuf.h"
+#include "protocols/cassandra/decoding-maps.h"
+#include "protocols/cassandra/defs.h"
+#include "protocols/cassandra/helpers.h"
+#include "protocols/cassandra/types.h"
+#include "protocols/cassandra/usm-events.h"
+#include "protocols/read_into_buffer.h"
+
+PKTBUF_READ_INTO_BUFFER(cassandra_request, CASSANDRA_BUFFER_SIZE, BLK_SIZE)
+PKTBUF_READ_INTO_BUFFER(cassandra_response, CASSANDRA_RESPONSE_FRAGMENT_SIZE, BLK_SIZE)
+
+// Reads a frame header from the given context. Returns true if a valid header was read successfully, false otherwise.
+// The fields of the header are converted to host byte order.
+static __always_inline bool cassandra_read_header(pktbuf_t pkt, struct cassandra_header *header) {
+    u32 data_off = pktbuf_data_offset(pkt);
+    u32 data_end = pktbuf_data_end(pkt);
+    // Ensuring that the header is in the buffer.
+    if (data_off + CASSANDRA_HEADER_SIZE > data_end) {
+        return false;
+    }
+    if (pktbuf_load_bytes(pkt, data_off, header, CASSANDRA_HEADER_SIZE) < 0) {
+        return false;
+    }
+    header->stream_id = bpf_ntohs(header->stream_id);
+    header->body_len = bpf_ntohl(header->body_len);
+    return is_valid_cassandra_header(header);
+}
+
+// Reads the consistency of a QUERY or an EXECUTE request, whose body starts at the current offset. The consistency
+// follows the statement of QUERY requests, and the id of the prepared statement of EXECUTE requests (and as of v5, the
+// id of the metadata of its result).
+static __always_inline __u16 cassandra_read_consistency(pktbuf_t pkt, struct cassandra_header *header) {
+    u32 offset = pktbuf_data_offset(pkt);
+    const u32 body_end = offset + header->body_len;
+
+    if (header->opcode == CASSANDRA_OPCODE_QUERY) {
+        s32 query_len = 0;
+        if (!pktbuf_read_big_endian_s32(pkt, offset, &query_len) || query_len < 0) {
+            return CASSANDRA_CONSISTENCY_UNKNOWN;
+        }
+        offset += sizeof(s32) + query_len;
+    } else if (header->opcode == CASSANDRA_OPCODE_EXECUTE) {
+        s16 id_len = 0;
+        if (!pktbuf_read_big_endian_s16(pkt, offset, &id_len) || id_len < 0) {
+            return CASSANDRA_CONSISTENCY_UNKNOWN;
+        }
+        offset += sizeof(s16) + id_len;
+        if ((header->version & CASSANDRA_VERSION_MASK) >= 5) {
+            s16 metadata_id_len = 0;
+            if (!pktbuf_read_big_endian_s16(pkt, offset, &metadata_id_len) || metadata_id_len < 0) {
+                return CASSANDRA_CONSISTENCY_UNKNOWN;
+            }
+            offset += sizeof(s16) + metadata_id_len;
+        }
+    } else {
+        return CASSANDRA_CONSISTENCY_UNKNOWN;
+    }
+
+    s16 consistency = 0;
+    if (offset + sizeof(s16) > body_end || !pktbuf_read_big_endian_s16(pkt, offset, &consistency)) {
+        return CASSANDRA_CONSISTENCY_UNKNOWN;
+    }
+    return (__u16)consistency;
+}
+
+// Handles a request by creating a new transaction for its stream and storing it in the map. We only keep track of
+// the requests running statements, and ignore those establishing the connection. If a transaction already exists for
+// the stream, it is aborted.
+static __always_inline void cassandra_handle_request(pktbuf_t pkt, cassandra_stream_key_t *key, struct cassandra_header *header, __u8 tags) {
+    switch (header->opcode) {
+    case CASSANDRA_OPCODE_QUERY:
+    case CASSANDRA_OPCODE_PREPARE:
+    case CASSANDRA_OPCODE_EXECUTE:
+    case CASSANDRA_OPCODE_BATCH:
+        break;
+    default:
+        return;
+    }
+
+    u32 zero = 0;
+    cassandra_event_t *event = bpf_map_lookup_elem(&cassandra_scratch_buffer, &zero);
+    if (!event) {
+        return;
+    }
+    cassandra_transaction_t *new_transaction = &event->tx;
+    bpf_memset(new_transaction, 0, sizeof(cassandra_transaction_t));
+
+    new_transaction->request_started = bpf_ktime_get_ns();
+    new_transaction->original_request_size = header->body_len;
+    new_transaction->version = header->version & CASSANDRA_VERSION_MASK;
+    new_transaction->request_opcode = header->opcode;
+    new_transaction->tags = tags;
+    new_transaction->consistency = CASSANDRA_CONSISTENCY_UNKNOWN;
+    // The body of compressed frames, or of frames carrying a custom payload, can't be read as is.
+    if ((header->flags & (CASSANDRA_FLAG_COMPRESSION | CASSANDRA_FLAG_CUSTOM_PAYLOAD)) == 0) {
+        pktbuf_read_into_buffer_cassandra_request(new_transaction->request_fragment, pkt, pktbuf_data_offset(pkt));
+        new_transaction->consistency = cassandra_read_consistency(pkt, header);
+    }
+    bpf_map_update_elem(&cassandra_in_flight, key, new_transaction, BPF_ANY);
+}
+
+// Handles a response by completing the transaction of its stream, enqueuing it and deleting it from the in-flight
+// map.
+static __always_inline void cassandra_handle_response(pktbuf_t pkt, cassandra_stream_key_t *key, struct cassandra_header *header) {
+    cassandra_transaction_t *transaction = bpf_map_lookup_elem(&cassandra_in_flight, key);
+    if (!transaction) {
+        return;
+    }
+
+    transaction->response_last_seen = bpf_ktime_get_ns();
+    transaction->response_opcode = header->opcode;
+    // The body of the responses with the tracing flag starts with the tracing session id. The body of compressed
+    // frames, or of frames carrying warnings or a custom payload, can't be read as is.
+    if ((header->flags & (CASSANDRA_FLAG_COMPRESSION | CASSANDRA_FLAG_WARNING | CASSANDRA_FLAG_CUSTOM_PAYLOAD)) == 0) {
+        u32 offset = pktbuf_data_offset(pkt);
+        if (header->flags & CASSANDRA_FLAG_TRACING) {
+            offset += CASSANDRA_TRACING_ID_SIZE;
+        }
+        pktbuf_read_into_buffer_cassandra_response(transaction->response_fragment, pkt, offset);
+    }
+
+    u32 zero = 0;
+    cassandra_event_t *event = bpf_map_lookup_elem(&cassandra_scratch_buffer, &zero);
+    if (event) {
+        bpf_memcpy(&event->tuple, &key->tup, sizeof(conn_tuple_t));
+        bpf_memcpy(&event->tx, transaction, sizeof(cassandra_transaction_t));
+        cassandra_batch_enqueue(event);
+    }
+    bpf_map_delete_elem(&cassandra_in_flight, key);
+}
+
+// Processes the frames of a packet. Each frame is either a request creating a transaction for its stream, or the
+// response completing it. We stop at the first frame whose header can't be read, such as frames spanning several
+// packets.
+static __always_inline void cassandra_process(pktbuf_t pkt, conn_tuple_t *conn_tuple, __u8 tags) {
+    cassandra_stream_key_t key;
+    bpf_memset(&key, 0, sizeof(cassandra_stream_key_t));
+    key.tup = *conn_tuple;
+
+    struct cassandra_header header;
+#pragma unroll(CASSANDRA_MAX_FRAMES_PER_PACKET)
+    for (int i = 0; i < CASSANDRA_MAX_FRAMES_PER_PACKET; i++) {
+        if (!cassandra_read_header(pkt, &header)) {
+            return;
+        }
+        pktbuf_advance(pkt, CASSANDRA_HEADER_SIZE);
+
+        // Responses with a negative stream id are events pushed by the server.
+        key.stream_id = (__u16)header.stream_id;
+        if (header.version & CASSANDRA_RESPONSE_BIT) {
+            if (header.stream_id >= 0) {
+                cassandra_handle_response(pkt, &key, &header);
+            }
+        } else {
+            cassandra_handle_request(pkt, &key, &header, tags);
+        }
+
+        pktbuf_advance(pkt, header.body_len);
+    }
+}
+
+// Entrypoint to process plaintext Cassandra traffic. Pulls the connection tuple and the packet buffer from the map
+// and calls the main processing function. In-flight transactions of terminated connections are removed by the map
+// cleaner, as they are keyed by stream.
+SEC("socket/cassandra_process")
+int socket__cassandra_process(struct __sk_buff *skb) {
+    skb_info_t skb_info = {};
+    conn_tuple_t conn_tuple = {};
+
+    if (!fetch_dispatching_arguments(&conn_tuple, &skb_info)) {
+        return 0;
+    }
+
+    if (is_tcp_termination(&skb_info)) {
+        return 0;
+    }
+
+    normalize_tuple(&conn_tuple);
+
+    pktbuf_t pkt = pktbuf_from_skb(skb, &skb_info);
+    cassandra_process(pkt, &conn_tuple, NO_TAGS);
+    return 0;
+}
+
+// Entrypoint to process TLS Cassandra traffic. Pulls the connection tuple and the packet buffer from the map and
+// calls the main processing function.
+SEC("uprobe/cassandra_tls_process")
+int uprobe__cassandra_tls_process(struct pt_regs *ctx) {
+    const __u32 zero = 0;
+
+    tls_dispatcher_arguments_t *args = bpf_map_lookup_elem(&tls_dispatcher_arguments, &zero);
+    if (args == NULL) {
+        return 0;
+    }
+
+    // Copying the tuple to the stack to handle verifier issues on kernel 4.14.
+    conn_tuple_t tup = args->tup;
+
+    pktbuf_t pkt = pktbuf_from_tls(ctx, args);
+    cassandra_process(pkt, &tup, (__u8)args->tags);
+    return 0;
+}
+
+#endif
@@ -0,0 +1,69 @@
+#ifndef __CASSANDRA_DEFS_H
+#define __CASSANDRA_DEFS_H
+
+// CQL native protocol - https://github.com/apache/cassandra/blob/trunk/doc/native_protocol_v4.spec
+// Every frame starts with a 9 bytes header.
+#define CASSANDRA_HEADER_SIZE 9
+
+// We support the versions 3 to 5 of the protocol, which share the same frame header. The highest bit of the version
+// tells the responses apart from the requests.
+#define CASSANDRA_MIN_VERSION 3
+#define CASSANDRA_MAX_VERSION 5
+#define CASSANDRA_RESPONSE_BIT 0x80
+#define CASSANDRA_VERSION_MASK 0x7f
+
+// Frame flags.
+#define CASSANDRA_FLAG_COMPRESSION 0x01
+#define CASSANDRA_FLAG_TRACING 0x02
+#define CASSANDRA_FLAG_CUSTOM_PAYLOAD 0x04
+#define CASSANDRA_FLAG_WARNING 0x08
+#define CASSANDRA_FLAG_USE_BETA 0x10
+#define CASSANDRA_FLAGS_MASK 0x1f
+
+// The protocol limits the frames to 256MB.
+#define CASSANDRA_MAX_BODY_LEN (256 * 1024 * 1024)
+
+// The size of the tracing session id prepended to the body of the responses with the tracing flag.
+#define CASSANDRA_TRACING_ID_SIZE 16
+
+// The size of the ids of the prepared statements, which are MD5 digests of the statements.
+#define CASSANDRA_PREPARED_ID_SIZE 16
+
+// The number of entries in the options map of a STARTUP message: CQL_VERSION, COMPRESSION, DRIVER_NAME, etc.
+#define CASSANDRA_STARTUP_MAX_OPTIONS 16
+
+#define CASSANDRA_OPCODE_ERROR 0x00
+#define CASSANDRA_OPCODE_STARTUP 0x01
+#define CASSANDRA_OPCODE_READY 0x02
+#define CASSANDRA_OPCODE_AUTHENTICATE 0x03
+#define CASSANDRA_OPCODE_OPTIONS 0x05
+#define CASSANDRA_OPCODE_SUPPORTED 0x06
+#define CASSANDRA_OPCODE_QUERY 0x07
+#define CASSANDRA_OPCODE_RESULT 0x08
+#define CASSANDRA_OPCODE_PREPARE 0x09
+#define CASSANDRA_OPCODE_EXECUTE 0x0A
+#define CASSANDRA_OPCODE_REGISTER 0x0B
+#define CASSANDRA_OPCODE_EVENT 0x0C
+#define CASSANDRA_OPCODE_BATCH 0x0D
+#define CASSANDRA_OPCODE_AUTH_CHALLENGE 0x0E
+#define CASSANDRA_OPCODE_AUTH_RESPONSE 0x0F
+#define CASSANDRA_OPCODE_AUTH_SUCCESS 0x10
+
+// The kinds of the RESULT messages.
+#define CASSANDRA_RESULT_VOID 0x0001
+#define CASSANDRA_RESULT_PREPARED 0x0004
+#define CASSANDRA_RESULT_SCHEMA_CHANGE 0x0005
+
+// Value of the consistency of the transactions for which it is unknown, as the consistency levels start at 0 (ANY).
+#define CASSANDRA_CONSISTENCY_UNKNOWN 0xFFFF
+
+// Header of the CQL frames. Multi-byte fields are big-endian.
+struct cassandra_header {
+    __u8 version;
+    __u8 flags;
+    __s16 stream_id;
+    __u8 opcode;
+    __u32 body_len;
+} __attribute__((packed));
+
+#endif // __CASSANDRA_DEFS_H
@@ -0,0 +1,117 @@
+#ifndef __CASSANDRA_HELPERS_H
+#define __CASSANDRA_HELPERS_H
+
+#include "protocols/classification/common.h"
+#include "protocols/cassandra/defs.h"
+
+// Reads a big-endian [short] from the buffer. The bytes are read one by one, as the buffer may not be aligned.
+static __always_inline __u16 cassandra_read_short(const char *buf) {
+    return ((__u16)(__u8)buf[0] << 8) | (__u8)buf[1];
+}
+
+// Reads a big-endian [int] from the buffer. The bytes are read one by one, as the buffer may not be aligned.
+static __always_inline __u32 cassandra_read_int(const char *buf) {
+    return ((__u32)(__u8)buf[0] << 24) | ((__u32)(__u8)buf[1] << 16) | ((__u32)(__u8)buf[2] << 8) | (__u8)buf[3];
+}
+
+// Checks the header (whose fields are in host byte order) is the header of a CQL frame.
+static __always_inline bool is_valid_cassandra_header(struct cassandra_header *hdr) {
+    __u8 version = hdr->version & CASSANDRA_VERSION_MASK;
+    if (version < CASSANDRA_MIN_VERSION || version > CASSANDRA_MAX_VERSION) {
+        return false;
+    }
+
+    if ((hdr->flags & ~CASSANDRA_FLAGS_MASK) != 0 || hdr->body_len > CASSANDRA_MAX_BODY_LEN) {
+        return false;
+    }
+
+    bool is_response = hdr->version & CASSANDRA_RESPONSE_BIT;
+    switch (hdr->opcode) {
+    case CASSANDRA_OPCODE_STARTUP:
+    case CASSANDRA_OPCODE_OPTIONS:
+    case CASSANDRA_OPCODE_QUERY:
+    case CASSANDRA_OPCODE_PREPARE:
+    case CASSANDRA_OPCODE_EXECUTE:
+    case CASSANDRA_OPCODE_REGISTER:
+    case CASSANDRA_OPCODE_BATCH:
+    case CASSANDRA_OPCODE_AUTH_RESPONSE:
+        return !is_response;
+    case CASSANDRA_OPCODE_ERROR:
+    case CASSANDRA_OPCODE_READY:
+    case CASSANDRA_OPCODE_AUTHENTICATE:
+    case CASSANDRA_OPCODE_SUPPORTED:
+    case CASSANDRA_OPCODE_RESULT:
+    case CASSANDRA_OPCODE_EVENT:
+    case CASSANDRA_OPCODE_AUTH_CHALLENGE:
+    case CASSANDRA_OPCODE_AUTH_SUCCESS:
+        return is_response;
+    default:
+        return false;
+    }
+}
+
+// Checks the buffer starts with a CQL frame. As the header is only 9 bytes long, we also check the beginning of the
+// body of the messages exchanged when a connection is established, and of the most common queries and results.
+static __always_inline bool is_cassandra(const char *buf, __u32 buf_size) {
+    CHECK_PRELIMINARY_BUFFER_CONDITIONS(buf, buf_size, CASSANDRA_HEADER_SIZE);
+
+    struct cassandra_header hdr = {
+        .version = buf[0],
+        .flags = buf[1],
+        .stream_id = cassandra_read_short(buf + 2),
+        .opcode = buf[4],
+        .body_len = cassandra_read_int(buf + 5),
+    };
+    if (!is_valid_cassandra_header(&hdr)) {
+        return false;
+    }
+
+    // The flags prepend data to the body (or compress it), we don't classify such frames.
+    if (hdr.flags != 0) {
+        return false;
+    }
+
+    const char *body = buf + CASSANDRA_HEADER_SIZE;
+    __u32 body_size = buf_size - CASSANDRA_HEADER_SIZE;
+    switch (hdr.opcode) {
+    case CASSANDRA_OPCODE_OPTIONS:
+    case CASSANDRA_OPCODE_READY:
+        return hdr.body_len == 0;
+    case CASSANDRA_OPCODE_STARTUP:
+    case CASSANDRA_OPCODE_SUPPORTED: {
+        // [string map] or [string multimap], starting with the number of entries.
+        if (body_size < sizeof(__u16)) {
+            return false;
+        }
+        __u16 entries = cassandra_read_short(body);
+        return entries > 0 && entries <= CASSANDRA_STARTUP_MAX_OPTIONS;
+    }
+    case CASSANDRA_OPCODE_QUERY:
+    case CASSANDRA_OPCODE_PREPARE: {
+        // [long string] holding the statement.
+        if (body_size < sizeof(__u32) || hdr.body_len < sizeof(__u32)) {
+            return false;
+        }
+        __u32 query_len = cassandra_read_int(body);
+        return query_len > 0 && query_len <= hdr.body_len - sizeof(__u32);
+    }
+    case CASSANDRA_OPCODE_EXECUTE:
+        // [short bytes] holding the id of the prepared statement.
+        if (body_size < sizeof(__u16)) {
+            return false;
+        }
+        return cassandra_read_short(body) == CASSANDRA_PREPARED_ID_SIZE;
+    case CASSANDRA_OPCODE_RESULT: {
+        // [int] holding the kind of the result.
+        if (body_size < sizeof(__u32)) {
+            return false;
+        }
+        __u32 kind = cassandra_read_int(body);
+        return kind >= CASSANDRA_RESULT_VOID && kind <= CASSANDRA_RESULT_SCHEMA_CHANGE;
+    }
+    default:
+        return false;
+    }
+}
+
+#endif // __CASSANDRA_HELPERS_H
@@ -0,0 +1,45 @@
+#ifndef __CASSANDRA_TYPES_H
+#define __CASSANDRA_TYPES_H
+
+#include "conn_tuple.h"
+
+// Maximum length of the body of the requests sent to userspace, holding the statement of QUERY and PREPARE
+// requests, or the id of the prepared statement of EXECUTE requests.
+#define CASSANDRA_BUFFER_SIZE 160
+
+// Maximum length of the body of the responses sent to userspace, holding the code of the errors, or the kind of the
+// results and the keyspace or the id of the statement they carry.
+#define CASSANDRA_RESPONSE_FRAGMENT_SIZE 64
+
+// Maximum number of frames we process in a single packet, as drivers multiplex requests over the connections.
+#define CASSANDRA_MAX_FRAMES_PER_PACKET 4
+
+// The key of the in-flight transactions, as the requests of a connection are told apart by their stream id.
+typedef struct {
+    conn_tuple_t tup;
+    __u32 stream_id;
+} cassandra_stream_key_t;
+
+// Cassandra transaction information we store in the kernel.
+typedef struct {
+    char request_fragment[CASSANDRA_BUFFER_SIZE];
+    char response_fragment[CASSANDRA_RESPONSE_FRAGMENT_SIZE];
+    __u64 request_started;
+    __u64 response_last_seen;
+    // The actual size of the body of the request.
+    __u32 original_request_size;
+    // The consistency of QUERY and EXECUTE requests, or CASSANDRA_CONSISTENCY_UNKNOWN.
+    __u16 consistency;
+    __u8 version;
+    __u8 request_opcode;
+    __u8 response_opcode;
+    __u8 tags;
+} cassandra_transaction_t;
+
+// The struct we send to userspace, containing the connection tuple and the transaction information.
+typedef struct {
+    conn_tuple_t tuple;
+    cassandra_transaction_t tx;
+} cassandra_event_t;
+
+#endif
@@ -3,9 +3,11 @@
 
 #include "bpf_bypass.h"
 
+#include "protocols/cassandra/decoding.h"
 #include "protocols/http/http.h"
 #include "protocols/http2/decoding.h"
 #include "protocols/kafka/kafka-parsing.h"
+#include "protocols/memcached/decoding.h"
 #include "protocols/postgres/decoding.h"
 #include "protocols/redis/decoding.h"
 
@@ -18,6 +20,8 @@ static __always_inline void flush(void *ctx) {
     kafka_batch_flush(ctx);
     postgres_batch_flush(ctx);
     redis_batch_flush(ctx);
+    memcached_batch_flush(ctx);
+    cassandra_batch_flush(ctx);
 }
 
 SEC("tracepoint/net/netif_receive_skb")
@@ -0,0 +1,16 @@
+#ifndef __MEMCACHED_MAPS_H
+#define __MEMCACHED_MAPS_H
+
+#include "bpf_helpers.h"
+#include "map-defs.h"
+
+#include "protocols/memcached/types.h"
+
+// Keeps track of in-flight memcached transactions. Memcached answers the requests of a connection in order, so we
+// keep a single transaction per connection.
+BPF_HASH_MAP(memcached_in_flight, conn_tuple_t, memcached_transaction_t, 0)
+
+// Acts as a scratch buffer for memcached events, for preparing events before they are sent to userspace.
+BPF_PERCPU_ARRAY_MAP(memcached_scratch_buffer, memcached_event_t, 1)
+
+#endif
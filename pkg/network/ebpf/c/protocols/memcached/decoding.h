@@ -0,0 +1,165 @@
+#ifndef __MEMCACHED_DECODING_H
+#define __MEMCACHED_DECODING_H
+
+#include "bpf_builtins.h"
+#include "bpf_telemetry.h"
+
+#include "protocols/sockfd.h"
+
+#include "protocols/helpers/pktbuf.h"
+#include "protocols/memcached/decoding-maps.h"
+#include "protocols/memcached/defs.h"
+#include "protocols/memcached/helpers.h"
+#include "protocols/memcached/types.h"
+#include "protocols/memcached/usm-events.h"
+#include "protocols/read_into_buffer.h"
+
+PKTBUF_READ_INTO_BUFFER(memcached_request, MEMCACHED_REQUEST_FRAGMENT_SIZE, BLK_SIZE)
+PKTBUF_READ_INTO_BUFFER(memcached_response, MEMCACHED_RESPONSE_FRAGMENT_SIZE, BLK_SIZE)
+
+// Enqueues a batch of events to the user-space. To spare stack size, we take a scratch buffer from the map, copy
+// the connection tuple and the transaction to it, and then enqueue the event.
+static __always_inline void memcached_batch_enqueue_wrapper(conn_tuple_t *tuple, memcached_transaction_t *tx) {
+    u32 zero = 0;
+    memcached_event_t *event = bpf_map_lookup_elem(&memcached_scratch_buffer, &zero);
+    if (!event) {
+        return;
+    }
+
+    bpf_memcpy(&event->tuple, tuple, sizeof(conn_tuple_t));
+    bpf_memcpy(&event->tx, tx, sizeof(memcached_transaction_t));
+    memcached_batch_enqueue(event);
+}
+
+// Checks whether the given fragment is the response to the in-flight request. The responses of the binary protocol
+// echo the opcode and the opaque value of the request. The replies of the incr and decr commands of the text protocol
+// are the new values of the items.
+static __always_inline bool is_memcached_response_of(memcached_transaction_t *tx, const char *buf, __u32 buf_size) {
+    if (is_memcached_binary(buf, buf_size, MEMCACHED_BINARY_RESPONSE_MAGIC)) {
+        struct memcached_binary_header *request = (struct memcached_binary_header *)tx->request_fragment;
+        struct memcached_binary_header *response = (struct memcached_binary_header *)buf;
+        return request->magic == MEMCACHED_BINARY_REQUEST_MAGIC
+            && request->opcode == response->opcode
+            && request->opaque == response->opaque;
+    }
+
+    if (is_memcached_text_response(buf, buf_size)) {
+        return true;
+    }
+
+    if (buf[0] < '0' || buf[0] > '9') {
+        return false;
+    }
+    return check_command(tx->request_fragment, MEMCACHED_CMD_INCR, MEMCACHED_REQUEST_FRAGMENT_SIZE)
+        || check_command(tx->request_fragment, MEMCACHED_CMD_DECR, MEMCACHED_REQUEST_FRAGMENT_SIZE);
+}
+
+// Handles a memcached packet. Requests create a new transaction for the connection, overriding the previous one if
+// it is still in-flight (eg. the noreply commands of the text protocol, or the quiet commands of the binary protocol).
+// The first response packet completes the transaction, as the latency we report is the time to the first byte of
+// the response.
+static __always_inline void memcached_process(pktbuf_t pkt, conn_tuple_t *conn_tuple, __u8 tags) {
+    u32 data_off = pktbuf_data_offset(pkt);
+    u32 data_end = pktbuf_data_end(pkt);
+    if (data_off >= data_end) {
+        return;
+    }
+
+    u32 zero = 0;
+    memcached_event_t *event = bpf_map_lookup_elem(&memcached_scratch_buffer, &zero);
+    if (!event) {
+        return;
+    }
+    memcached_transaction_t *new_transaction = &event->tx;
+    bpf_memset(new_transaction, 0, sizeof(memcached_transaction_t));
+
+    pktbuf_read_into_buffer_memcached_response(new_transaction->response_fragment, pkt, data_off);
+    if (is_memcached_request(new_transaction->response_fragment, data_end - data_off)) {
+        bpf_memset(new_transaction->response_fragment, 0, MEMCACHED_RESPONSE_FRAGMENT_SIZE);
+        pktbuf_read_into_buffer_memcached_request(new_transaction->request_fragment, pkt, data_off);
+        new_transaction->request_started = bpf_ktime_get_ns();
+        new_transaction->tags = tags;
+        bpf_map_update_elem(&memcached_in_flight, conn_tuple, new_transaction, BPF_ANY);
+        return;
+    }
+
+    memcached_transaction_t *transaction = bpf_map_lookup_elem(&memcached_in_flight, conn_tuple);
+    if (!transaction) {
+        return;
+    }
+    if (!is_memcached_response_of(transaction, new_transaction->response_fragment, data_end - data_off)) {
+        return;
+    }
+
+    bpf_memcpy(transaction->response_fragment, new_transaction->response_fragment, MEMCACHED_RESPONSE_FRAGMENT_SIZE);
+    transaction->response_last_seen = bpf_ktime_get_ns();
+    memcached_batch_enqueue_wrapper(conn_tuple, transaction);
+    bpf_map_delete_elem(&memcached_in_flight, conn_tuple);
+}
+
+// Handles a TCP termination event by deleting the connection tuple from the in-flight map.
+static void __always_inline memcached_tcp_termination(conn_tuple_t *tup) {
+    bpf_map_delete_elem(&memcached_in_flight, tup);
+    flip_tuple(tup);
+    bpf_map_delete_elem(&memcached_in_flight, tup);
+}
+
+// Entrypoint to process plaintext memcached traffic. Pulls the connection tuple and the packet buffer from the map
+// and calls the main processing function. If the packet is a TCP termination, it calls the termination function.
+SEC("socket/memcached_process")
+int socket__memcached_process(struct __sk_buff *skb) {
+    skb_info_t skb_info = {};
+    conn_tuple_t conn_tuple = {};
+
+    if (!fetch_dispatching_arguments(&conn_tuple, &skb_info)) {
+        return 0;
+    }
+
+    if (is_tcp_termination(&skb_info)) {
+        memcached_tcp_termination(&conn_tuple);
+        return 0;
+    }
+
+    normalize_tuple(&conn_tuple);
+
+    pktbuf_t pkt = pktbuf_from_skb(skb, &skb_info);
+    memcached_process(pkt, &conn_tuple, NO_TAGS);
+    return 0;
+}
+
+// Entrypoint to process TLS memcached traffic. Pulls the connection tuple and the packet buffer from the map and
+// calls the main processing function.
+SEC("uprobe/memcached_tls_process")
+int uprobe__memcached_tls_process(struct pt_regs *ctx) {
+    const __u32 zero = 0;
+
+    tls_dispatcher_arguments_t *args = bpf_map_lookup_elem(&tls_dispatcher_arguments, &zero);
+    if (args == NULL) {
+        return 0;
+    }
+
+    // Copying the tuple to the stack to handle verifier issues on kernel 4.14.
+    conn_tuple_t tup = args->tup;
+
+    pktbuf_t pkt = pktbuf_from_tls(ctx, args);
+    memcached_process(pkt, &tup, (__u8)args->tags);
+    return 0;
+}
+
+// Handles connection termination for a TLS memcached connection.
+SEC("uprobe/memcached_tls_termination")
+int uprobe__memcached_tls_termination(struct pt_regs *ctx) {
+    const __u32 zero = 0;
+
+    tls_dispatcher_arguments_t *args = bpf_map_lookup_elem(&tls_dispatcher_arguments, &zero);
+    if (args == NULL) {
+        return 0;
+    }
+
+    // Copying the tuple to the stack to handle verifier issues on kernel 4.14.
+    conn_tuple_t tup = args->tup;
+    memcached_tcp_termination(&tup);
+    return 0;
+}
+
+#endif
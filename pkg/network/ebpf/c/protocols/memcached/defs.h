@@ -0,0 +1,64 @@
+#ifndef __MEMCACHED_DEFS_H
+#define __MEMCACHED_DEFS_H
+
+// The shortest frames of the text protocol are the "OK\r\n" response and the "get k" request.
+#define MEMCACHED_TEXT_MIN_FRAME_LENGTH 4
+
+// Binary protocol - https://github.com/memcached/memcached/wiki/BinaryProtocolRevamped
+#define MEMCACHED_BINARY_REQUEST_MAGIC 0x80
+#define MEMCACHED_BINARY_RESPONSE_MAGIC 0x81
+#define MEMCACHED_BINARY_HEADER_SIZE 24
+// The highest opcode defined by the binary protocol.
+#define MEMCACHED_BINARY_MAX_OPCODE 0x48
+// Memcached items are limited to 1MB by default, but the limit can be raised up to 1GB.
+#define MEMCACHED_BINARY_MAX_BODY_LEN (1 << 30)
+
+// Commands of the text protocol - https://github.com/memcached/memcached/blob/master/doc/protocol.txt
+// The trailing space makes sure we match the whole command name.
+#define MEMCACHED_CMD_GET "get "
+#define MEMCACHED_CMD_GETS "gets "
+#define MEMCACHED_CMD_GAT "gat "
+#define MEMCACHED_CMD_GATS "gats "
+#define MEMCACHED_CMD_SET "set "
+#define MEMCACHED_CMD_ADD "add "
+#define MEMCACHED_CMD_REPLACE "replace "
+#define MEMCACHED_CMD_APPEND "append "
+#define MEMCACHED_CMD_PREPEND "prepend "
+#define MEMCACHED_CMD_CAS "cas "
+#define MEMCACHED_CMD_DELETE "delete "
+#define MEMCACHED_CMD_INCR "incr "
+#define MEMCACHED_CMD_DECR "decr "
+#define MEMCACHED_CMD_TOUCH "touch "
+#define MEMCACHED_CMD_VERSION "version\r\n"
+#define MEMCACHED_CMD_STATS "stats"
+
+// Replies of the text protocol.
+#define MEMCACHED_REPLY_VALUE "VALUE "
+#define MEMCACHED_REPLY_END "END\r\n"
+#define MEMCACHED_REPLY_STORED "STORED\r\n"
+#define MEMCACHED_REPLY_NOT_PREFIX "NOT_"
+#define MEMCACHED_REPLY_EXISTS "EXISTS\r\n"
+#define MEMCACHED_REPLY_DELETED "DELETED\r\n"
+#define MEMCACHED_REPLY_TOUCHED "TOUCHED\r\n"
+#define MEMCACHED_REPLY_OK "OK\r\n"
+#define MEMCACHED_REPLY_ERROR "ERROR"
+#define MEMCACHED_REPLY_CLIENT_ERROR "CLIENT_ERROR "
+#define MEMCACHED_REPLY_SERVER_ERROR "SERVER_ERROR "
+#define MEMCACHED_REPLY_VERSION "VERSION "
+#define MEMCACHED_REPLY_STAT "STAT "
+
+// Header of the packets of the binary protocol. Multi-byte fields are big-endian.
+struct memcached_binary_header {
+    __u8 magic;
+    __u8 opcode;
+    __u16 key_len;
+    __u8 extras_len;
+    __u8 data_type;
+    // The virtual bucket of the requests, and the status of the responses.
+    __u16 status;
+    __u32 total_body_len;
+    __u32 opaque;
+    __u64 cas;
+} __attribute__((packed));
+
+#endif // __MEMCACHED_DEFS_H
@@ -0,0 +1,80 @@
+#ifndef __MEMCACHED_HELPERS_H
+#define __MEMCACHED_HELPERS_H
+
+#include "protocols/classification/common.h"
+#include "protocols/memcached/defs.h"
+#include "protocols/sql/helpers.h"
+
+// Checks the buffer starts with the header of a packet of the binary protocol, with the given magic byte.
+static __always_inline bool is_memcached_binary(const char *buf, __u32 buf_size, __u8 magic) {
+    CHECK_PRELIMINARY_BUFFER_CONDITIONS(buf, buf_size, MEMCACHED_BINARY_HEADER_SIZE);
+
+    struct memcached_binary_header *hdr = (struct memcached_binary_header *)buf;
+    if (hdr->magic != magic || hdr->opcode > MEMCACHED_BINARY_MAX_OPCODE) {
+        return false;
+    }
+
+    // The only data type defined by the protocol is raw bytes.
+    if (hdr->data_type != 0) {
+        return false;
+    }
+
+    __u32 body_len = bpf_ntohl(hdr->total_body_len);
+    return body_len <= MEMCACHED_BINARY_MAX_BODY_LEN && hdr->extras_len + bpf_ntohs(hdr->key_len) <= body_len;
+}
+
+// Checks the buffer starts with a command of the text protocol.
+static __always_inline bool is_memcached_text_request(const char *buf, __u32 buf_size) {
+    CHECK_PRELIMINARY_BUFFER_CONDITIONS(buf, buf_size, MEMCACHED_TEXT_MIN_FRAME_LENGTH);
+
+    return check_command(buf, MEMCACHED_CMD_GET, buf_size)
+        || check_command(buf, MEMCACHED_CMD_GETS, buf_size)
+        || check_command(buf, MEMCACHED_CMD_GAT, buf_size)
+        || check_command(buf, MEMCACHED_CMD_GATS, buf_size)
+        || check_command(buf, MEMCACHED_CMD_SET, buf_size)
+        || check_command(buf, MEMCACHED_CMD_ADD, buf_size)
+        || check_command(buf, MEMCACHED_CMD_REPLACE, buf_size)
+        || check_command(buf, MEMCACHED_CMD_APPEND, buf_size)
+        || check_command(buf, MEMCACHED_CMD_PREPEND, buf_size)
+        || check_command(buf, MEMCACHED_CMD_CAS, buf_size)
+        || check_command(buf, MEMCACHED_CMD_DELETE, buf_size)
+        || check_command(buf, MEMCACHED_CMD_INCR, buf_size)
+        || check_command(buf, MEMCACHED_CMD_DECR, buf_size)
+        || check_command(buf, MEMCACHED_CMD_TOUCH, buf_size)
+        || check_command(buf, MEMCACHED_CMD_VERSION, buf_size)
+        || check_command(buf, MEMCACHED_CMD_STATS, buf_size);
+}
+
+// Checks the buffer starts with a reply of the text protocol. The replies to the incr and decr commands are the
+// new values of the items, which are too ambiguous to be told apart from the data blocks of the storage commands,
+// so they are left to the caller.
+static __always_inline bool is_memcached_text_response(const char *buf, __u32 buf_size) {
+    CHECK_PRELIMINARY_BUFFER_CONDITIONS(buf, buf_size, MEMCACHED_TEXT_MIN_FRAME_LENGTH);
+
+    return check_command(buf, MEMCACHED_REPLY_VALUE, buf_size)
+        || check_command(buf, MEMCACHED_REPLY_END, buf_size)
+        || check_command(buf, MEMCACHED_REPLY_STORED, buf_size)
+        || check_command(buf, MEMCACHED_REPLY_NOT_PREFIX, buf_size)
+        || check_command(buf, MEMCACHED_REPLY_EXISTS, buf_size)
+        || check_command(buf, MEMCACHED_REPLY_DELETED, buf_size)
+        || check_command(buf, MEMCACHED_REPLY_TOUCHED, buf_size)
+        || check_command(buf, MEMCACHED_REPLY_OK, buf_size)
+        || check_command(buf, MEMCACHED_REPLY_ERROR, buf_size)
+        || check_command(buf, MEMCACHED_REPLY_CLIENT_ERROR, buf_size)
+        || check_command(buf, MEMCACHED_REPLY_SERVER_ERROR, buf_size)
+        || check_command(buf, MEMCACHED_REPLY_VERSION, buf_size)
+        || check_command(buf, MEMCACHED_REPLY_STAT, buf_size);
+}
+
+// Checks the buffer starts with a request of the text or the binary protocol.
+static __always_inline bool is_memcached_request(const char *buf, __u32 buf_size) {
+    return is_memcached_binary(buf, buf_size, MEMCACHED_BINARY_REQUEST_MAGIC) || is_memcached_text_request(buf, buf_size);
+}
+
+// Classifies memcached traffic. Memcached clients speak first, but the responses of the binary protocol are
+// distinctive enough to classify connections we only see the server side of.
+static __always_inline bool is_memcached(const char *buf, __u32 buf_size) {
+    return is_memcached_request(buf, buf_size) || is_memcached_binary(buf, buf_size, MEMCACHED_BINARY_RESPONSE_MAGIC);
+}
+
+#endif // __MEMCACHED_HELPERS_H
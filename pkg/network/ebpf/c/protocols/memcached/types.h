@@ -0,0 +1,29 @@
+#ifndef __MEMCACHED_TYPES_H
+#define __MEMCACHED_TYPES_H
+
+#include "conn_tuple.h"
+
+// Maximum length of the request sent to userspace. Enough for the command line of the text protocol, or the header
+// of the binary protocol followed by the key.
+#define MEMCACHED_REQUEST_FRAGMENT_SIZE 64
+
+// Maximum length of the response sent to userspace. Enough for the replies of the text protocol, or the header of
+// the binary protocol.
+#define MEMCACHED_RESPONSE_FRAGMENT_SIZE 32
+
+// Memcached transaction information we store in the kernel.
+typedef struct {
+    char request_fragment[MEMCACHED_REQUEST_FRAGMENT_SIZE];
+    char response_fragment[MEMCACHED_RESPONSE_FRAGMENT_SIZE];
+    __u64 request_started;
+    __u64 response_last_seen;
+    __u8 tags;
+} memcached_transaction_t;
+
+// The struct we send to userspace, containing the connection tuple and the transaction information.
+typedef struct {
+    conn_tuple_t tuple;
+    memcached_transaction_t tx;
+} memcached_event_t;
+
+#endif
@@ -124,6 +124,14 @@ static __always_inline void tls_process(struct pt_regs *ctx, conn_tuple_t *t, vo
         prog = PROG_POSTGRES;
         final_tuple = normalized_tuple;
         break;
+    case PROTOCOL_MEMCACHED:
+        prog = PROG_MEMCACHED;
+        final_tuple = normalized_tuple;
+        break;
+    case PROTOCOL_CASSANDRA:
+        prog = PROG_CASSANDRA;
+        final_tuple = normalized_tuple;
+        break;
     default:
         return;
     }
@@ -212,6 +220,10 @@ static __always_inline void tls_finish(struct pt_regs *ctx, conn_tuple_t *t, boo
         prog = PROG_POSTGRES_TERMINATION;
         final_tuple = normalized_tuple;
         break;
+    case PROTOCOL_MEMCACHED:
+        prog = PROG_MEMCACHED_TERMINATION;
+        final_tuple = normalized_tuple;
+        break;
     default:
         return;
     }
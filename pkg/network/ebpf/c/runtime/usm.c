@@ -16,12 +16,14 @@
 #include "pid_tgid.h"
 
 #include "protocols/classification/dispatcher-helpers.h"
+#include "protocols/cassandra/decoding.h"
 #include "protocols/flush.h"
 #include "protocols/http/buffer.h"
 #include "protocols/http/http.h"
 #include "protocols/http2/decoding.h"
 #include "protocols/http2/decoding-tls.h"
 #include "protocols/kafka/kafka-parsing.h"
+#include "protocols/memcached/decoding.h"
 #include "protocols/postgres/decoding.h"
 #include "protocols/redis/decoding.h"
 #include "protocols/sockfd-probes.h"
@@ -0,0 +1,190 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+// Package flowexport exports the connections of the network tracer as IPFIX or NetFlow v9 records
+// to a UDP collector
+package flowexport
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/network"
+	"github.com/DataDog/datadog-agent/pkg/network/config"
+	"github.com/DataDog/datadog-agent/pkg/telemetry"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	// clientID is the client of the network tracer the connections are exported for
+	clientID = "flow-exporter"
+
+	moduleName = "network_tracer__flow_export"
+
+	minPacketSize = 512
+	// maxPacketSize is the maximum size of the IPFIX and NetFlow v9 messages, whose length is a 16 bits field
+	maxPacketSize = 65535
+)
+
+var exporterTelemetry = struct {
+	messages telemetry.Counter
+	records  telemetry.Counter
+	errors   telemetry.Counter
+}{
+	telemetry.NewCounter(moduleName, "messages", []string{}, "Counter measuring the number of flow messages sent"),
+	telemetry.NewCounter(moduleName, "records", []string{}, "Counter measuring the number of flow records sent"),
+	telemetry.NewCounter(moduleName, "errors", []string{"step"}, "Counter measuring the number of errors while exporting the flows"),
+}
+
+// ConnectionSource is the network tracer the connections are exported from
+type ConnectionSource interface {
+	RegisterClient(clientID string) error
+	GetActiveConnections(clientID string) (*network.Connections, error)
+}
+
+// Exporter sends the traffic of the connections of each interval to a collector
+type Exporter struct {
+	source   ConnectionSource
+	conn     net.Conn
+	encoder  *messageEncoder
+	interval time.Duration
+
+	lastExport time.Time
+	exit       chan struct{}
+	wg         sync.WaitGroup
+	once       sync.Once
+}
+
+// NewExporter returns an Exporter of the connections of the source, as configured
+func NewExporter(cfg *config.Config, source ConnectionSource) (*Exporter, error) {
+	protocol := Protocol(cfg.FlowExportProtocol)
+	if protocol != IPFIX && protocol != NetFlowV9 {
+		return nil, fmt.Errorf("invalid flow export protocol %q, it must be %q or %q", cfg.FlowExportProtocol, IPFIX, NetFlowV9)
+	}
+	if cfg.FlowExportInterval <= 0 {
+		return nil, fmt.Errorf("invalid flow export interval %s", cfg.FlowExportInterval)
+	}
+	packetSize := min(max(cfg.FlowExportMaxPacketSize, minPacketSize), maxPacketSize)
+
+	conn, err := net.Dial("udp", cfg.FlowExportCollector)
+	if err != nil {
+		return nil, fmt.Errorf("could not connect to the flow collector: %w", err)
+	}
+	if err := source.RegisterClient(clientID); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("could not register the flow exporter: %w", err)
+	}
+
+	now := time.Now()
+	return &Exporter{
+		source:     source,
+		conn:       conn,
+		encoder:    newMessageEncoder(protocol, packetSize, cfg.FlowExportObservationDomainID, cfg.FlowExportEnterpriseNumber, now),
+		interval:   cfg.FlowExportInterval,
+		lastExport: now,
+		exit:       make(chan struct{}),
+	}, nil
+}
+
+// Start exports the connections at every interval, onExport is called after each successful
+// export if it isn't nil
+func (e *Exporter) Start(onExport func()) {
+	log.Infof("exporting the connections as %s records to %s every %s", e.encoder.protocol, e.conn.RemoteAddr(), e.interval)
+	e.wg.Add(1)
+	go func() {
+		defer e.wg.Done()
+		ticker := time.NewTicker(e.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case now := <-ticker.C:
+				if err := e.export(now); err != nil {
+					log.Warnf("could not export the connections: %s", err)
+				} else if onExport != nil {
+					onExport()
+				}
+			case <-e.exit:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the export of the connections
+func (e *Exporter) Stop() {
+	e.once.Do(func() {
+		close(e.exit)
+		e.wg.Wait()
+		e.conn.Close()
+	})
+}
+
+func (e *Exporter) export(now time.Time) error {
+	conns, err := e.source.GetActiveConnections(clientID)
+	if err != nil {
+		exporterTelemetry.errors.Inc("connections")
+		return err
+	}
+	records := flowRecords(conns.Conns, e.lastExport, now)
+	network.Reclaim(conns)
+	e.lastExport = now
+
+	for _, msg := range e.encoder.encode(records, now) {
+		if _, err := e.conn.Write(msg); err != nil {
+			exporterTelemetry.errors.Inc("send")
+			return err
+		}
+		exporterTelemetry.messages.Inc()
+	}
+	exporterTelemetry.records.Add(float64(len(records)))
+	return nil
+}
+
+// flowRecords returns the records of the connections with traffic since the last export
+func flowRecords(conns []network.ConnectionStats, lastExport, now time.Time) []flowRecord {
+	records := make([]flowRecord, 0, len(conns))
+	for i := range conns {
+		c := &conns[i]
+		if c.Last.SentBytes == 0 && c.Last.RecvBytes == 0 && c.Last.SentPackets == 0 && c.Last.RecvPackets == 0 {
+			continue
+		}
+
+		r := flowRecord{
+			ipv6:        !c.Source.Is4() || !c.Dest.Is4(),
+			source:      c.Source.Addr,
+			dest:        c.Dest.Addr,
+			sourcePort:  c.SPort,
+			destPort:    c.DPort,
+			protocol:    6,
+			sentBytes:   c.Last.SentBytes,
+			sentPackets: c.Last.SentPackets,
+			recvBytes:   c.Last.RecvBytes,
+			recvPackets: c.Last.RecvPackets,
+			start:       lastExport,
+			end:         now,
+			pid:         c.Pid,
+			rtt:         c.RTT,
+			rttVar:      c.RTTVar,
+			retransmits: c.Last.Retransmits,
+		}
+		if c.Type == network.UDP {
+			r.protocol = 17
+		}
+		// the flow direction is ingress (0) for the incoming connections, egress (1) otherwise
+		if c.Direction != network.INCOMING {
+			r.direction = 1
+		}
+		if c.Duration > 0 && now.Add(-c.Duration).After(lastExport) {
+			r.start = now.Add(-c.Duration)
+		}
+		if c.ContainerID.Source != nil {
+			r.containerID, _ = c.ContainerID.Source.Get().(string)
+		}
+		records = append(records, r)
+	}
+	return records
+}
@@ -0,0 +1,162 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package flowexport
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go4.org/intern"
+
+	"github.com/DataDog/datadog-agent/pkg/network"
+	"github.com/DataDog/datadog-agent/pkg/network/config"
+	"github.com/DataDog/datadog-agent/pkg/process/util"
+)
+
+type fakeSource struct {
+	registered []string
+	conns      []network.ConnectionStats
+}
+
+func (s *fakeSource) RegisterClient(clientID string) error {
+	s.registered = append(s.registered, clientID)
+	return nil
+}
+
+func (s *fakeSource) GetActiveConnections(_ string) (*network.Connections, error) {
+	return &network.Connections{BufferedData: network.BufferedData{Conns: s.conns}}, nil
+}
+
+func TestExport(t *testing.T) {
+	collector, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer collector.Close()
+
+	conn := network.ConnectionStats{
+		ConnectionTuple: network.ConnectionTuple{
+			Source:    util.AddressFromString("10.0.0.1"),
+			Dest:      util.AddressFromString("10.0.0.2"),
+			SPort:     40000,
+			DPort:     443,
+			Pid:       42,
+			Type:      network.TCP,
+			Direction: network.OUTGOING,
+		},
+		Last: network.StatCounters{SentBytes: 100, RecvBytes: 200, SentPackets: 1, RecvPackets: 2},
+	}
+	conn.ContainerID.Source = intern.GetByString("abc")
+	idle := conn
+	idle.SPort = 40001
+	idle.Last = network.StatCounters{}
+	source := &fakeSource{conns: []network.ConnectionStats{conn, idle}}
+
+	cfg := &config.Config{
+		FlowExportCollector:        collector.LocalAddr().String(),
+		FlowExportProtocol:         "ipfix",
+		FlowExportInterval:         time.Second,
+		FlowExportMaxPacketSize:    1400,
+		FlowExportEnterpriseNumber: 32473,
+	}
+	e, err := NewExporter(cfg, source)
+	require.NoError(t, err)
+	defer e.Stop()
+	assert.Equal(t, []string{clientID}, source.registered)
+
+	require.NoError(t, e.export(time.Now()))
+	buf := make([]byte, 65535)
+	require.NoError(t, collector.SetReadDeadline(time.Now().Add(5*time.Second)))
+	n, _, err := collector.ReadFrom(buf)
+	require.NoError(t, err)
+
+	_, records := decodeMessages(t, [][]byte{buf[:n]})
+	// the connection without traffic is not exported
+	require.Len(t, records, 1)
+	assert.Equal(t, []byte{10, 0, 0, 1}, records[0][[2]uint32{8, 0}])
+	assert.Equal(t, []byte("abc"), records[0][[2]uint32{2, 32473}])
+}
+
+func TestStart(t *testing.T) {
+	collector, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer collector.Close()
+
+	cfg := &config.Config{
+		FlowExportCollector: collector.LocalAddr().String(),
+		FlowExportProtocol:  "ipfix",
+		FlowExportInterval:  10 * time.Millisecond,
+	}
+	e, err := NewExporter(cfg, &fakeSource{})
+	require.NoError(t, err)
+
+	exported := make(chan struct{}, 1)
+	e.Start(func() {
+		select {
+		case exported <- struct{}{}:
+		default:
+		}
+	})
+	defer e.Stop()
+
+	select {
+	case <-exported:
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "the export callback wasn't called")
+	}
+}
+
+func TestNewExporterErrors(t *testing.T) {
+	_, err := NewExporter(&config.Config{FlowExportCollector: "127.0.0.1:4739", FlowExportProtocol: "sflow", FlowExportInterval: time.Second}, &fakeSource{})
+	assert.ErrorContains(t, err, "invalid flow export protocol")
+
+	_, err = NewExporter(&config.Config{FlowExportCollector: "127.0.0.1:4739", FlowExportProtocol: "ipfix"}, &fakeSource{})
+	assert.ErrorContains(t, err, "invalid flow export interval")
+}
+
+func TestFlowRecords(t *testing.T) {
+	lastExport := time.Now()
+	now := lastExport.Add(30 * time.Second)
+	conns := []network.ConnectionStats{
+		{
+			// a connection opened during the interval
+			ConnectionTuple: network.ConnectionTuple{
+				Source:    util.AddressFromString("10.0.0.1"),
+				Dest:      util.AddressFromString("10.0.0.2"),
+				Type:      network.UDP,
+				Direction: network.INCOMING,
+			},
+			Last:     network.StatCounters{RecvPackets: 1, RecvBytes: 60},
+			Duration: 10 * time.Second,
+		},
+		{
+			ConnectionTuple: network.ConnectionTuple{
+				Source: util.AddressFromString("10.0.0.1"),
+				Dest:   util.AddressFromString("fd00::2"),
+				Type:   network.TCP,
+			},
+			Last:     network.StatCounters{SentPackets: 1, SentBytes: 60, Retransmits: 2},
+			Duration: time.Hour,
+			RTT:      100,
+		},
+	}
+
+	records := flowRecords(conns, lastExport, now)
+	require.Len(t, records, 2)
+	assert.False(t, records[0].ipv6)
+	assert.Equal(t, uint8(17), records[0].protocol)
+	assert.Equal(t, uint8(0), records[0].direction)
+	assert.Equal(t, now.Add(-10*time.Second), records[0].start)
+	assert.Equal(t, now, records[0].end)
+
+	assert.True(t, records[1].ipv6)
+	assert.Equal(t, uint8(6), records[1].protocol)
+	assert.Equal(t, uint8(1), records[1].direction)
+	assert.Equal(t, lastExport, records[1].start)
+	assert.Equal(t, uint32(2), records[1].retransmits)
+	assert.Equal(t, uint32(100), records[1].rtt)
+}
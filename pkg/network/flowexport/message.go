@@ -0,0 +1,156 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package flowexport
+
+import (
+	"encoding/binary"
+	"time"
+)
+
+const (
+	ipfixVersion       uint16 = 10
+	ipfixHeaderLength         = 16
+	ipfixTemplateSetID uint16 = 2
+
+	netflowV9Version       uint16 = 9
+	netflowV9HeaderLength         = 20
+	netflowV9TemplateSetID uint16 = 0
+
+	setHeaderLength = 4
+)
+
+// messageEncoder splits flow records in IPFIX or NetFlow v9 messages. The templates are sent at the
+// beginning of every export, the records being sent over UDP.
+type messageEncoder struct {
+	protocol      Protocol
+	maxSize       int
+	domainID      uint32
+	ipv4, ipv6    template
+	bootTime      time.Time
+	headerLength  int
+	templateSetID uint16
+
+	// sequence is the number of data records sent for IPFIX, and the number of messages for NetFlow v9
+	sequence uint32
+}
+
+func newMessageEncoder(protocol Protocol, maxSize int, domainID, enterpriseNumber uint32, bootTime time.Time) *messageEncoder {
+	e := &messageEncoder{
+		protocol:      protocol,
+		maxSize:       maxSize,
+		domainID:      domainID,
+		bootTime:      bootTime,
+		headerLength:  ipfixHeaderLength,
+		templateSetID: ipfixTemplateSetID,
+	}
+	if protocol == NetFlowV9 {
+		e.headerLength = netflowV9HeaderLength
+		e.templateSetID = netflowV9TemplateSetID
+	}
+	e.ipv4, e.ipv6 = newTemplates(protocol, enterpriseNumber)
+	return e
+}
+
+// message is a message being built
+type message struct {
+	b []byte
+	// records is the number of template and data records of the message
+	records     int
+	dataRecords int
+	// set is the offset of the set being written, or -1
+	set   int
+	setID uint16
+}
+
+// encode returns the messages holding the templates and the flow records
+func (e *messageEncoder) encode(records []flowRecord, now time.Time) [][]byte {
+	var messages [][]byte
+	m := e.newMessage()
+
+	// the templates are sent first, in their own set
+	m.openSet(e.templateSetID)
+	m.b = e.ipv4.appendTemplateRecord(m.b)
+	m.b = e.ipv6.appendTemplateRecord(m.b)
+	m.records += 2
+	m.closeSet(e.protocol)
+
+	for i := range records {
+		r := &records[i]
+		t := e.ipv4
+		if r.ipv6 {
+			t = e.ipv6
+		}
+
+		length := t.recordLength(r)
+		if m.setID != t.id || m.set < 0 {
+			length += setHeaderLength
+		}
+		// the data sets of NetFlow v9 are padded to 4 bytes
+		if len(m.b)+length+3 > e.maxSize && m.records > 0 {
+			m.closeSet(e.protocol)
+			messages = append(messages, e.finish(m, now))
+			m = e.newMessage()
+		}
+
+		if m.set < 0 || m.setID != t.id {
+			m.closeSet(e.protocol)
+			m.openSet(t.id)
+		}
+		m.b = t.appendDataRecord(m.b, r, e.bootTime)
+		m.records++
+		m.dataRecords++
+	}
+	m.closeSet(e.protocol)
+	return append(messages, e.finish(m, now))
+}
+
+func (e *messageEncoder) newMessage() *message {
+	b := make([]byte, e.headerLength, e.maxSize)
+	return &message{b: b, set: -1}
+}
+
+func (m *message) openSet(id uint16) {
+	m.set = len(m.b)
+	m.setID = id
+	m.b = binary.BigEndian.AppendUint16(m.b, id)
+	m.b = binary.BigEndian.AppendUint16(m.b, 0)
+}
+
+func (m *message) closeSet(protocol Protocol) {
+	if m.set < 0 {
+		return
+	}
+	if protocol == NetFlowV9 {
+		for (len(m.b)-m.set)%4 != 0 {
+			m.b = append(m.b, 0)
+		}
+	}
+	binary.BigEndian.PutUint16(m.b[m.set+2:], uint16(len(m.b)-m.set))
+	m.set = -1
+}
+
+// finish writes the header of the message
+func (e *messageEncoder) finish(m *message, now time.Time) []byte {
+	b := m.b
+	if e.protocol == NetFlowV9 {
+		binary.BigEndian.PutUint16(b[0:], netflowV9Version)
+		binary.BigEndian.PutUint16(b[2:], uint16(m.records))
+		binary.BigEndian.PutUint32(b[4:], uptimeMillis(now, e.bootTime))
+		binary.BigEndian.PutUint32(b[8:], uint32(now.Unix()))
+		binary.BigEndian.PutUint32(b[12:], e.sequence)
+		binary.BigEndian.PutUint32(b[16:], e.domainID)
+		e.sequence++
+		return b
+	}
+
+	binary.BigEndian.PutUint16(b[0:], ipfixVersion)
+	binary.BigEndian.PutUint16(b[2:], uint16(len(b)))
+	binary.BigEndian.PutUint32(b[4:], uint32(now.Unix()))
+	binary.BigEndian.PutUint32(b[8:], e.sequence)
+	binary.BigEndian.PutUint32(b[12:], e.domainID)
+	e.sequence += uint32(m.dataRecords)
+	return b
+}
@@ -0,0 +1,187 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package flowexport
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/netsampler/goflow2/decoders/netflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testBootTime = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func testRecords() []flowRecord {
+	start := testBootTime.Add(time.Minute)
+	return []flowRecord{
+		{
+			source:      netip.MustParseAddr("10.0.0.1"),
+			dest:        netip.MustParseAddr("10.0.0.2"),
+			sourcePort:  40000,
+			destPort:    443,
+			protocol:    6,
+			direction:   1,
+			sentBytes:   1000,
+			sentPackets: 10,
+			recvBytes:   2000,
+			recvPackets: 20,
+			start:       start,
+			end:         start.Add(30 * time.Second),
+			pid:         42,
+			containerID: "abc",
+			rtt:         150,
+			rttVar:      20,
+			retransmits: 1,
+		},
+		{
+			ipv6:        true,
+			source:      netip.MustParseAddr("fd00::1"),
+			dest:        netip.MustParseAddr("fd00::2"),
+			sourcePort:  53000,
+			destPort:    53,
+			protocol:    17,
+			direction:   1,
+			sentBytes:   60,
+			sentPackets: 1,
+			start:       start,
+			end:         start.Add(30 * time.Second),
+		},
+	}
+}
+
+// decodeMessages returns the decoded messages, and the values of their data records by field type and enterprise number
+func decodeMessages(t *testing.T, messages [][]byte) (packets []interface{}, records []map[[2]uint32][]byte) {
+	templates := netflow.CreateTemplateSystem()
+	for _, msg := range messages {
+		packet, err := netflow.DecodeMessage(bytes.NewBuffer(msg), templates)
+		require.NoError(t, err)
+		packets = append(packets, packet)
+
+		var flowSets []interface{}
+		switch p := packet.(type) {
+		case netflow.IPFIXPacket:
+			flowSets = p.FlowSets
+		case netflow.NFv9Packet:
+			flowSets = p.FlowSets
+		}
+		for _, set := range flowSets {
+			dataSet, ok := set.(netflow.DataFlowSet)
+			if !ok {
+				continue
+			}
+			for _, record := range dataSet.Records {
+				values := make(map[[2]uint32][]byte)
+				for _, v := range record.Values {
+					values[[2]uint32{uint32(v.Type), v.Pen}] = v.Value.([]byte)
+				}
+				records = append(records, values)
+			}
+		}
+	}
+	return packets, records
+}
+
+func TestEncodeIPFIX(t *testing.T) {
+	e := newMessageEncoder(IPFIX, 1400, 7, 32473, testBootTime)
+	now := testBootTime.Add(2 * time.Minute)
+	messages := e.encode(testRecords(), now)
+	require.Len(t, messages, 1)
+
+	packets, records := decodeMessages(t, messages)
+	header := packets[0].(netflow.IPFIXPacket)
+	assert.Equal(t, uint16(10), header.Version)
+	assert.Equal(t, uint16(len(messages[0])), header.Length)
+	assert.Equal(t, uint32(now.Unix()), header.ExportTime)
+	assert.Equal(t, uint32(0), header.SequenceNumber)
+	assert.Equal(t, uint32(7), header.ObservationDomainId)
+
+	require.Len(t, records, 2)
+	v4 := records[0]
+	assert.Equal(t, []byte{10, 0, 0, 1}, v4[[2]uint32{8, 0}])
+	assert.Equal(t, []byte{10, 0, 0, 2}, v4[[2]uint32{12, 0}])
+	assert.Equal(t, uint16(443), binary.BigEndian.Uint16(v4[[2]uint32{11, 0}]))
+	assert.Equal(t, []byte{6}, v4[[2]uint32{4, 0}])
+	assert.Equal(t, []byte{1}, v4[[2]uint32{61, 0}])
+	assert.Equal(t, uint64(1000), binary.BigEndian.Uint64(v4[[2]uint32{1, 0}]))
+	assert.Equal(t, uint64(2000), binary.BigEndian.Uint64(v4[[2]uint32{1, reverseEnterpriseNumber}]))
+	assert.Equal(t, uint64(20), binary.BigEndian.Uint64(v4[[2]uint32{2, reverseEnterpriseNumber}]))
+	assert.Equal(t, uint64(testBootTime.Add(time.Minute).UnixMilli()), binary.BigEndian.Uint64(v4[[2]uint32{152, 0}]))
+	assert.Equal(t, uint32(42), binary.BigEndian.Uint32(v4[[2]uint32{1, 32473}]))
+	assert.Equal(t, []byte("abc"), v4[[2]uint32{2, 32473}])
+	assert.Equal(t, uint32(150), binary.BigEndian.Uint32(v4[[2]uint32{3, 32473}]))
+	assert.Equal(t, uint32(1), binary.BigEndian.Uint32(v4[[2]uint32{5, 32473}]))
+
+	v6 := records[1]
+	assert.Equal(t, netip.MustParseAddr("fd00::1").AsSlice(), v6[[2]uint32{27, 0}])
+	assert.Equal(t, []byte{17}, v6[[2]uint32{4, 0}])
+	assert.Equal(t, []byte{}, v6[[2]uint32{2, 32473}])
+
+	// the sequence number counts the data records
+	messages = e.encode(testRecords(), now)
+	packets, _ = decodeMessages(t, messages)
+	assert.Equal(t, uint32(2), packets[0].(netflow.IPFIXPacket).SequenceNumber)
+}
+
+func TestEncodeIPFIXWithoutEnterpriseNumber(t *testing.T) {
+	e := newMessageEncoder(IPFIX, 1400, 0, 0, testBootTime)
+	_, records := decodeMessages(t, e.encode(testRecords(), testBootTime.Add(2*time.Minute)))
+	require.Len(t, records, 2)
+	assert.Len(t, records[0], 12)
+}
+
+func TestEncodeNetFlowV9(t *testing.T) {
+	e := newMessageEncoder(NetFlowV9, 1400, 7, 32473, testBootTime)
+	now := testBootTime.Add(2 * time.Minute)
+	messages := e.encode(testRecords(), now)
+	require.Len(t, messages, 1)
+
+	packets, records := decodeMessages(t, messages)
+	header := packets[0].(netflow.NFv9Packet)
+	assert.Equal(t, uint16(9), header.Version)
+	// two templates and two data records
+	assert.Equal(t, uint16(4), header.Count)
+	assert.Equal(t, uint32(2*time.Minute/time.Millisecond), header.SystemUptime)
+	assert.Equal(t, uint32(0), header.SequenceNumber)
+	assert.Equal(t, uint32(7), header.SourceId)
+
+	require.Len(t, records, 2)
+	v4 := records[0]
+	// no enterprise fields in NetFlow v9
+	assert.Len(t, v4, 12)
+	assert.Equal(t, uint64(1000), binary.BigEndian.Uint64(v4[[2]uint32{1, 0}]))
+	assert.Equal(t, uint64(2000), binary.BigEndian.Uint64(v4[[2]uint32{23, 0}]))
+	assert.Equal(t, uint32(time.Minute/time.Millisecond), binary.BigEndian.Uint32(v4[[2]uint32{22, 0}]))
+	assert.Equal(t, uint32(90*time.Second/time.Millisecond), binary.BigEndian.Uint32(v4[[2]uint32{21, 0}]))
+
+	// the sequence number counts the messages
+	packets, _ = decodeMessages(t, e.encode(nil, now))
+	assert.Equal(t, uint32(1), packets[0].(netflow.NFv9Packet).SequenceNumber)
+}
+
+func TestEncodeSplitsMessages(t *testing.T) {
+	for _, protocol := range []Protocol{IPFIX, NetFlowV9} {
+		t.Run(string(protocol), func(t *testing.T) {
+			var records []flowRecord
+			for i := 0; i < 50; i++ {
+				records = append(records, testRecords()...)
+			}
+			e := newMessageEncoder(protocol, 512, 0, 32473, testBootTime)
+			messages := e.encode(records, testBootTime.Add(time.Minute))
+			assert.Greater(t, len(messages), 1)
+			for _, msg := range messages {
+				assert.LessOrEqual(t, len(msg), 512)
+			}
+
+			_, decoded := decodeMessages(t, messages)
+			assert.Len(t, decoded, 100)
+		})
+	}
+}
@@ -0,0 +1,259 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package flowexport
+
+import (
+	"encoding/binary"
+	"net/netip"
+	"time"
+)
+
+// Protocol is the protocol of the flow records
+type Protocol string
+
+const (
+	// IPFIX is the IP Flow Information Export protocol (RFC 7011)
+	IPFIX Protocol = "ipfix"
+	// NetFlowV9 is the NetFlow version 9 protocol (RFC 3954)
+	NetFlowV9 Protocol = "netflow9"
+)
+
+const (
+	ipv4TemplateID uint16 = 256
+	ipv6TemplateID uint16 = 257
+
+	// variableLength is the length of the variable-length fields in the IPFIX templates
+	variableLength uint16 = 65535
+
+	// reverseEnterpriseNumber is the enterprise number of the reverse information elements (RFC 5103)
+	reverseEnterpriseNumber uint32 = 29305
+)
+
+// fieldKind is the value of a flow record held by a field
+type fieldKind uint8
+
+const (
+	sourceAddress fieldKind = iota
+	destinationAddress
+	sourcePort
+	destinationPort
+	protocolIdentifier
+	flowDirection
+	sentBytes
+	sentPackets
+	receivedBytes
+	receivedPackets
+	startMilliseconds
+	endMilliseconds
+	startUptime
+	endUptime
+	processID
+	containerID
+	rtt
+	rttVar
+	retransmits
+)
+
+// field is a field of a template, an information element of IPFIX or a field type of NetFlow v9
+type field struct {
+	kind       fieldKind
+	id         uint16
+	length     uint16
+	enterprise uint32
+}
+
+// template describes the records of a data set
+type template struct {
+	id     uint16
+	fields []field
+}
+
+// flowRecord is the traffic of a connection during an export interval
+type flowRecord struct {
+	// ipv6 tells the template of the record, the IPv4 addresses are mapped when the other one is IPv6
+	ipv6        bool
+	source      netip.Addr
+	dest        netip.Addr
+	sourcePort  uint16
+	destPort    uint16
+	protocol    uint8
+	direction   uint8
+	sentBytes   uint64
+	sentPackets uint64
+	recvBytes   uint64
+	recvPackets uint64
+	start, end  time.Time
+	pid         uint32
+	containerID string
+	rtt         uint32
+	rttVar      uint32
+	retransmits uint32
+}
+
+// newTemplates returns the IPv4 and IPv6 templates of the protocol. The process and container fields
+// are only part of the IPFIX templates, when an enterprise number is given.
+func newTemplates(protocol Protocol, enterpriseNumber uint32) (ipv4, ipv6 template) {
+	ipv4Addresses := []field{
+		{kind: sourceAddress, id: 8, length: 4},
+		{kind: destinationAddress, id: 12, length: 4},
+	}
+	ipv6Addresses := []field{
+		{kind: sourceAddress, id: 27, length: 16},
+		{kind: destinationAddress, id: 28, length: 16},
+	}
+	common := []field{
+		{kind: sourcePort, id: 7, length: 2},
+		{kind: destinationPort, id: 11, length: 2},
+		{kind: protocolIdentifier, id: 4, length: 1},
+		{kind: flowDirection, id: 61, length: 1},
+	}
+
+	if protocol == NetFlowV9 {
+		// the source of the flows is the host, IN_BYTES counts what it sent and OUT_BYTES what it received
+		common = append(common,
+			field{kind: sentBytes, id: 1, length: 8},
+			field{kind: sentPackets, id: 2, length: 8},
+			field{kind: receivedBytes, id: 23, length: 8},
+			field{kind: receivedPackets, id: 24, length: 8},
+			field{kind: startUptime, id: 22, length: 4},
+			field{kind: endUptime, id: 21, length: 4},
+		)
+	} else {
+		common = append(common,
+			field{kind: sentBytes, id: 1, length: 8},
+			field{kind: sentPackets, id: 2, length: 8},
+			field{kind: receivedBytes, id: 1, length: 8, enterprise: reverseEnterpriseNumber},
+			field{kind: receivedPackets, id: 2, length: 8, enterprise: reverseEnterpriseNumber},
+			field{kind: startMilliseconds, id: 152, length: 8},
+			field{kind: endMilliseconds, id: 153, length: 8},
+		)
+		if enterpriseNumber != 0 {
+			common = append(common,
+				field{kind: processID, id: 1, length: 4, enterprise: enterpriseNumber},
+				field{kind: containerID, id: 2, length: variableLength, enterprise: enterpriseNumber},
+				field{kind: rtt, id: 3, length: 4, enterprise: enterpriseNumber},
+				field{kind: rttVar, id: 4, length: 4, enterprise: enterpriseNumber},
+				field{kind: retransmits, id: 5, length: 4, enterprise: enterpriseNumber},
+			)
+		}
+	}
+
+	ipv4 = template{id: ipv4TemplateID, fields: append(ipv4Addresses, common...)}
+	ipv6 = template{id: ipv6TemplateID, fields: append(ipv6Addresses, common...)}
+	return ipv4, ipv6
+}
+
+// appendTemplateRecord appends the template record, in the same format for IPFIX and NetFlow v9
+func (t template) appendTemplateRecord(b []byte) []byte {
+	b = binary.BigEndian.AppendUint16(b, t.id)
+	b = binary.BigEndian.AppendUint16(b, uint16(len(t.fields)))
+	for _, f := range t.fields {
+		if f.enterprise != 0 {
+			b = binary.BigEndian.AppendUint16(b, f.id|0x8000)
+			b = binary.BigEndian.AppendUint16(b, f.length)
+			b = binary.BigEndian.AppendUint32(b, f.enterprise)
+			continue
+		}
+		b = binary.BigEndian.AppendUint16(b, f.id)
+		b = binary.BigEndian.AppendUint16(b, f.length)
+	}
+	return b
+}
+
+// recordLength returns the length of the data record of a flow
+func (t template) recordLength(r *flowRecord) int {
+	length := 0
+	for _, f := range t.fields {
+		if f.length != variableLength {
+			length += int(f.length)
+			continue
+		}
+		length += variableFieldLength(r.containerID)
+	}
+	return length
+}
+
+// appendDataRecord appends the data record of a flow. The NetFlow v9 timestamps are relative to the boot time.
+func (t template) appendDataRecord(b []byte, r *flowRecord, bootTime time.Time) []byte {
+	for _, f := range t.fields {
+		switch f.kind {
+		case sourceAddress:
+			b = appendAddress(b, r.source, r.ipv6)
+		case destinationAddress:
+			b = appendAddress(b, r.dest, r.ipv6)
+		case sourcePort:
+			b = binary.BigEndian.AppendUint16(b, r.sourcePort)
+		case destinationPort:
+			b = binary.BigEndian.AppendUint16(b, r.destPort)
+		case protocolIdentifier:
+			b = append(b, r.protocol)
+		case flowDirection:
+			b = append(b, r.direction)
+		case sentBytes:
+			b = binary.BigEndian.AppendUint64(b, r.sentBytes)
+		case sentPackets:
+			b = binary.BigEndian.AppendUint64(b, r.sentPackets)
+		case receivedBytes:
+			b = binary.BigEndian.AppendUint64(b, r.recvBytes)
+		case receivedPackets:
+			b = binary.BigEndian.AppendUint64(b, r.recvPackets)
+		case startMilliseconds:
+			b = binary.BigEndian.AppendUint64(b, uint64(r.start.UnixMilli()))
+		case endMilliseconds:
+			b = binary.BigEndian.AppendUint64(b, uint64(r.end.UnixMilli()))
+		case startUptime:
+			b = binary.BigEndian.AppendUint32(b, uptimeMillis(r.start, bootTime))
+		case endUptime:
+			b = binary.BigEndian.AppendUint32(b, uptimeMillis(r.end, bootTime))
+		case processID:
+			b = binary.BigEndian.AppendUint32(b, r.pid)
+		case containerID:
+			b = appendVariableField(b, r.containerID)
+		case rtt:
+			b = binary.BigEndian.AppendUint32(b, r.rtt)
+		case rttVar:
+			b = binary.BigEndian.AppendUint32(b, r.rttVar)
+		case retransmits:
+			b = binary.BigEndian.AppendUint32(b, r.retransmits)
+		}
+	}
+	return b
+}
+
+func appendAddress(b []byte, addr netip.Addr, ipv6 bool) []byte {
+	if ipv6 {
+		a := addr.As16()
+		return append(b, a[:]...)
+	}
+	a := addr.As4()
+	return append(b, a[:]...)
+}
+
+// uptimeMillis returns the milliseconds elapsed between the boot time and t, as a NetFlow v9 timestamp
+func uptimeMillis(t, bootTime time.Time) uint32 {
+	if t.Before(bootTime) {
+		return 0
+	}
+	return uint32(t.Sub(bootTime).Milliseconds())
+}
+
+// variableFieldLength returns the encoded length of a variable-length field (RFC 7011, section 7)
+func variableFieldLength(value string) int {
+	if len(value) < 255 {
+		return 1 + len(value)
+	}
+	return 3 + len(value)
+}
+
+func appendVariableField(b []byte, value string) []byte {
+	if len(value) < 255 {
+		b = append(b, uint8(len(value)))
+	} else {
+		b = append(b, 255)
+		b = binary.BigEndian.AppendUint16(b, uint16(len(value)))
+	}
+	return append(b, value...)
+}
@@ -0,0 +1,135 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+//go:build test
+
+package cassandra
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+)
+
+const (
+	clientProtocolVersion = 0x04
+	clientHeaderSize      = 9
+)
+
+// Client is a minimal client of the version 4 of the CQL native protocol, used to generate traffic in tests. It
+// sends a single request at a time.
+type Client struct {
+	conn   net.Conn
+	stream int16
+}
+
+// NewClient returns a new Cassandra client connected to the given server, after completing the startup of the
+// connection.
+func NewClient(serverAddress string, dialer *net.Dialer) (*Client, error) {
+	conn, err := dialer.Dial("tcp", serverAddress)
+	if err != nil {
+		return nil, err
+	}
+	c := &Client{conn: conn}
+
+	// STARTUP body: a [string map] with the CQL version.
+	body := appendShort(nil, 1)
+	body = appendString(body, "CQL_VERSION")
+	body = appendString(body, "3.0.0")
+	opcode, _, err := c.roundTrip(StartupOpcode, body)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if opcode != ReadyOpcode {
+		conn.Close()
+		return nil, fmt.Errorf("unexpected response to STARTUP: %s", opcode)
+	}
+	return c, nil
+}
+
+// Query runs the given statement with the given consistency level.
+func (c *Client) Query(statement string, consistency Consistency) error {
+	body := appendLongString(nil, statement)
+	body = appendShort(body, uint16(consistency))
+	body = append(body, 0) // query flags
+	_, _, err := c.roundTrip(QueryOpcode, body)
+	return err
+}
+
+// Prepare prepares the given statement, and returns the id of the prepared statement.
+func (c *Client) Prepare(statement string) ([]byte, error) {
+	_, result, err := c.roundTrip(PrepareOpcode, appendLongString(nil, statement))
+	if err != nil {
+		return nil, err
+	}
+	// <kind><id>...
+	if len(result) < 6 || binary.BigEndian.Uint32(result) != resultPrepared {
+		return nil, fmt.Errorf("unexpected result to PREPARE")
+	}
+	idLen := int(binary.BigEndian.Uint16(result[4:]))
+	if len(result) < 6+idLen {
+		return nil, fmt.Errorf("truncated result to PREPARE")
+	}
+	return result[6 : 6+idLen], nil
+}
+
+// Execute executes the prepared statement of the given id, without values, with the given consistency level.
+func (c *Client) Execute(id []byte, consistency Consistency) error {
+	body := appendShort(nil, uint16(len(id)))
+	body = append(body, id...)
+	body = appendShort(body, uint16(consistency))
+	body = append(body, 0) // query flags
+	_, _, err := c.roundTrip(ExecuteOpcode, body)
+	return err
+}
+
+// Close closes the connection to the server.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// roundTrip sends a request and returns the opcode and the body of its response. ERROR responses are returned as
+// errors.
+func (c *Client) roundTrip(opcode Opcode, body []byte) (Opcode, []byte, error) {
+	c.stream++
+	header := make([]byte, clientHeaderSize)
+	header[0] = clientProtocolVersion
+	binary.BigEndian.PutUint16(header[2:], uint16(c.stream))
+	header[4] = byte(opcode)
+	binary.BigEndian.PutUint32(header[5:], uint32(len(body)))
+	if _, err := c.conn.Write(append(header, body...)); err != nil {
+		return 0, nil, err
+	}
+
+	if _, err := io.ReadFull(c.conn, header); err != nil {
+		return 0, nil, err
+	}
+	response := make([]byte, binary.BigEndian.Uint32(header[5:]))
+	if _, err := io.ReadFull(c.conn, response); err != nil {
+		return 0, nil, err
+	}
+	responseOpcode := Opcode(header[4])
+	if responseOpcode == ErrorOpcode {
+		if len(response) < 6 {
+			return responseOpcode, response, fmt.Errorf("cassandra error")
+		}
+		return responseOpcode, response, fmt.Errorf("cassandra error 0x%x: %s", binary.BigEndian.Uint32(response), readShortString(response[4:]))
+	}
+	return responseOpcode, response, nil
+}
+
+func appendShort(b []byte, v uint16) []byte {
+	return binary.BigEndian.AppendUint16(b, v)
+}
+
+func appendString(b []byte, s string) []byte {
+	return append(appendShort(b, uint16(len(s))), s...)
+}
+
+func appendLongString(b []byte, s string) []byte {
+	return append(binary.BigEndian.AppendUint32(b, uint32(len(s))), s...)
+}
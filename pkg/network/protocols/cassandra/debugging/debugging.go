@@ -0,0 +1,115 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+// Package debugging provides debug-friendly representations of internal data structures
+package debugging
+
+import (
+	"github.com/DataDog/sketches-go/ddsketch"
+
+	"github.com/DataDog/datadog-agent/pkg/network/protocols/cassandra"
+	"github.com/DataDog/datadog-agent/pkg/process/util"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// address represents represents a IP:Port
+type address struct {
+	IP   string
+	Port uint16
+}
+
+// key represents a (client, server, keyspace, consistency) tuple.
+type key struct {
+	Client      address
+	Server      address
+	Keyspace    string
+	Consistency string
+}
+
+// Stats consolidates request count, error count and latency information for a certain opcode
+type Stats struct {
+	Count              int
+	ErrorCount         int
+	FirstLatencySample float64
+	LatencyP50         float64
+	latencies          *ddsketch.DDSketch
+}
+
+// RequestSummary represents a (debug-friendly) aggregated view of requests
+// matching a (client, server, keyspace, consistency, opcode) tuple
+type RequestSummary struct {
+	key
+	ByOpcode map[string]Stats
+}
+
+// Cassandra returns a debug-friendly representation of map[cassandra.Key]cassandra.RequestStat
+func Cassandra(stats map[cassandra.Key]*cassandra.RequestStat) []RequestSummary {
+	resMap := make(map[key]map[string]Stats)
+	for k, requestStat := range stats {
+		clientAddr := formatIP(k.SrcIPLow, k.SrcIPHigh)
+		serverAddr := formatIP(k.DstIPLow, k.DstIPHigh)
+
+		tempKey := key{
+			Client: address{
+				IP:   clientAddr.String(),
+				Port: k.SrcPort,
+			},
+			Server: address{
+				IP:   serverAddr.String(),
+				Port: k.DstPort,
+			},
+			Keyspace:    k.Keyspace,
+			Consistency: k.Consistency.String(),
+		}
+		if _, ok := resMap[tempKey]; !ok {
+			resMap[tempKey] = make(map[string]Stats)
+		}
+		currentStats := resMap[tempKey][k.Opcode.String()]
+		currentStats.Count += requestStat.Count
+		currentStats.ErrorCount += requestStat.ErrorCount
+		if currentStats.FirstLatencySample == 0 {
+			currentStats.FirstLatencySample = requestStat.FirstLatencySample
+		}
+		if requestStat.Latencies != nil {
+			if currentStats.latencies == nil {
+				currentStats.latencies = requestStat.Latencies.Copy()
+			} else if err := currentStats.latencies.MergeWith(requestStat.Latencies); err != nil {
+				log.Debugf("could not add request latency to ddsketch: %v", err)
+			}
+		}
+
+		resMap[tempKey][k.Opcode.String()] = currentStats
+	}
+
+	all := make([]RequestSummary, 0, len(resMap))
+	for key, value := range resMap {
+		for opcode, stats := range value {
+			stats.LatencyP50 = getSketchQuantile(stats.latencies, 0.5)
+			value[opcode] = stats
+		}
+		all = append(all, RequestSummary{
+			key:      key,
+			ByOpcode: value,
+		})
+	}
+	return all
+}
+
+func formatIP(low, high uint64) util.Address {
+	if high > 0 || (low>>32) > 0 {
+		return util.V6Address(low, high)
+	}
+
+	return util.V4Address(uint32(low))
+}
+
+func getSketchQuantile(sketch *ddsketch.DDSketch, percentile float64) float64 {
+	if sketch == nil {
+		return 0.0
+	}
+
+	val, _ := sketch.GetValueAtQuantile(percentile)
+	return val
+}
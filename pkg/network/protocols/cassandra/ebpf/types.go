@@ -0,0 +1,27 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+//go:build ignore
+
+package ebpf
+
+/*
+#include "../../ebpf/c/protocols/cassandra/types.h"
+#include "../../ebpf/c/protocols/cassandra/defs.h"
+#include "../../ebpf/c/protocols/classification/defs.h"
+*/
+import "C"
+
+type ConnTuple = C.conn_tuple_t
+
+type StreamKey C.cassandra_stream_key_t
+type EbpfEvent C.cassandra_event_t
+type EbpfTx C.cassandra_transaction_t
+
+const (
+	BufferSize           = C.CASSANDRA_BUFFER_SIZE
+	ResponseFragmentSize = C.CASSANDRA_RESPONSE_FRAGMENT_SIZE
+	ConsistencyUnknown   = C.CASSANDRA_CONSISTENCY_UNKNOWN
+)
@@ -0,0 +1,117 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+//go:build linux_bpf
+
+package cassandra
+
+import (
+	"fmt"
+
+	"github.com/DataDog/datadog-agent/pkg/network/protocols"
+	"github.com/DataDog/datadog-agent/pkg/network/protocols/cassandra/ebpf"
+	"github.com/DataDog/datadog-agent/pkg/network/types"
+)
+
+// EventWrapper wraps an ebpf event and provides additional methods to extract information from it.
+// We use this wrapper to avoid decoding the fragments of the request and the response multiple times.
+type EventWrapper struct {
+	*ebpf.EbpfEvent
+
+	requestSet  bool
+	request     request
+	responseSet bool
+	response    response
+}
+
+// NewEventWrapper creates a new EventWrapper from an ebpf event.
+func NewEventWrapper(e *ebpf.EbpfEvent) *EventWrapper {
+	return &EventWrapper{EbpfEvent: e}
+}
+
+// ConnTuple returns the connection tuple for the transaction
+func (e *EventWrapper) ConnTuple() types.ConnectionKey {
+	return types.ConnectionKey{
+		SrcIPHigh: e.Tuple.Saddr_h,
+		SrcIPLow:  e.Tuple.Saddr_l,
+		DstIPHigh: e.Tuple.Daddr_h,
+		DstIPLow:  e.Tuple.Daddr_l,
+		SrcPort:   e.Tuple.Sport,
+		DstPort:   e.Tuple.Dport,
+	}
+}
+
+// Opcode returns the opcode of the request (QUERY, PREPARE, EXECUTE or BATCH)
+func (e *EventWrapper) Opcode() Opcode {
+	return Opcode(e.Tx.Request_opcode)
+}
+
+// Consistency returns the consistency level of the request
+func (e *EventWrapper) Consistency() Consistency {
+	return Consistency(e.Tx.Consistency)
+}
+
+// Statement returns the statement of the request, if any. The statement may be truncated.
+func (e *EventWrapper) Statement() string {
+	return e.parsedRequest().statement
+}
+
+// PreparedID returns the id of the prepared statement executed by the request, if any.
+func (e *EventWrapper) PreparedID() string {
+	return e.parsedRequest().preparedID
+}
+
+// IsError returns whether the response is an ERROR response
+func (e *EventWrapper) IsError() bool {
+	return e.parsedResponse().isError
+}
+
+// ResultKeyspace returns the keyspace set by the request, if it is a USE statement.
+func (e *EventWrapper) ResultKeyspace() string {
+	return e.parsedResponse().keyspace
+}
+
+// ResultPreparedID returns the id of the statement prepared by the request, if it is a PREPARE request.
+func (e *EventWrapper) ResultPreparedID() string {
+	return e.parsedResponse().preparedID
+}
+
+// RequestLatency returns the latency of the request in nanoseconds
+func (e *EventWrapper) RequestLatency() float64 {
+	if uint64(e.Tx.Request_started) == 0 || uint64(e.Tx.Response_last_seen) == 0 {
+		return 0
+	}
+	return protocols.NSTimestampToFloat(e.Tx.Response_last_seen - e.Tx.Request_started)
+}
+
+func (e *EventWrapper) parsedRequest() request {
+	if !e.requestSet {
+		e.request = parseRequest(e.Opcode(), e.Tx.Request_fragment[:])
+		e.requestSet = true
+	}
+	return e.request
+}
+
+func (e *EventWrapper) parsedResponse() response {
+	if !e.responseSet {
+		e.response = parseResponse(Opcode(e.Tx.Response_opcode), e.Tx.Response_fragment[:])
+		e.responseSet = true
+	}
+	return e.response
+}
+
+const template = `
+ebpfTx{
+	Opcode: %q,
+	Consistency: %q,
+	Statement: %q,
+	Error: %t,
+	Latency: %f
+}`
+
+// String returns a string representation of the underlying event
+func (e *EventWrapper) String() string {
+	return fmt.Sprintf(template, e.Opcode(), e.Consistency(), e.Statement(), e.IsError(), e.RequestLatency())
+}
@@ -0,0 +1,161 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package cassandra
+
+import (
+	"encoding/binary"
+	"strings"
+)
+
+// The kinds of the RESULT responses we decode.
+const (
+	resultSetKeyspace = 0x0003
+	resultPrepared    = 0x0004
+)
+
+// The kinds of the statements of BATCH requests.
+const (
+	batchQueryKind    = 0
+	batchPreparedKind = 1
+)
+
+// request holds the information decoded from the fragment of a request.
+type request struct {
+	// statement is the statement of QUERY and PREPARE requests, or of the first statement of BATCH requests. It may be
+	// truncated.
+	statement string
+	// preparedID is the id of the prepared statement of EXECUTE requests, or of the first statement of BATCH requests.
+	preparedID string
+}
+
+// response holds the information decoded from the fragment of a response.
+type response struct {
+	isError bool
+	// keyspace is the keyspace of the results of USE statements.
+	keyspace string
+	// preparedID is the id of the statements of PREPARE requests.
+	preparedID string
+}
+
+// parseRequest decodes the fragment of the body of a request.
+func parseRequest(opcode Opcode, fragment []byte) request {
+	switch opcode {
+	case QueryOpcode, PrepareOpcode:
+		return request{statement: readLongString(fragment)}
+	case ExecuteOpcode:
+		return request{preparedID: readShortBytes(fragment)}
+	case BatchOpcode:
+		// <type><n><kind><string_or_id>...
+		if len(fragment) < 4 {
+			return request{}
+		}
+		switch fragment[3] {
+		case batchQueryKind:
+			return request{statement: readLongString(fragment[4:])}
+		case batchPreparedKind:
+			return request{preparedID: readShortBytes(fragment[4:])}
+		}
+	}
+	return request{}
+}
+
+// parseResponse decodes the fragment of the body of a response.
+func parseResponse(opcode Opcode, fragment []byte) response {
+	switch opcode {
+	case ErrorOpcode:
+		return response{isError: true}
+	case ResultOpcode:
+		if len(fragment) < 4 {
+			return response{}
+		}
+		switch binary.BigEndian.Uint32(fragment) {
+		case resultSetKeyspace:
+			return response{keyspace: readShortString(fragment[4:])}
+		case resultPrepared:
+			return response{preparedID: readShortBytes(fragment[4:])}
+		}
+	}
+	return response{}
+}
+
+// readLongString reads a [long string], truncating it to the length of the fragment.
+func readLongString(fragment []byte) string {
+	if len(fragment) < 4 {
+		return ""
+	}
+	length := int(int32(binary.BigEndian.Uint32(fragment)))
+	if length <= 0 {
+		return ""
+	}
+	return string(fragment[4:min(4+length, len(fragment))])
+}
+
+// readShortString reads a [string], which is at most 65535 bytes long. We don't return truncated strings.
+func readShortString(fragment []byte) string {
+	if len(fragment) < 2 {
+		return ""
+	}
+	length := int(binary.BigEndian.Uint16(fragment))
+	if 2+length > len(fragment) {
+		return ""
+	}
+	return string(fragment[2 : 2+length])
+}
+
+// readShortBytes reads a [short bytes], which is encoded as a [string]. We don't return truncated values.
+func readShortBytes(fragment []byte) string {
+	return readShortString(fragment)
+}
+
+// keyspaceFromStatement returns the keyspace of the statement given, if the statement specifies it. The keyspace is
+// either the operand of USE statements, or qualifies the table of the statement (eg. `SELECT * FROM ks.table`).
+func keyspaceFromStatement(statement string) string {
+	tokens := strings.Fields(statement)
+	if len(tokens) == 0 {
+		return ""
+	}
+	if strings.EqualFold(tokens[0], "USE") {
+		if len(tokens) < 2 {
+			return ""
+		}
+		return unquote(strings.TrimSuffix(tokens[1], ";"))
+	}
+
+	for i := 0; i < len(tokens)-1; i++ {
+		switch strings.ToUpper(tokens[i]) {
+		case "FROM", "INTO", "UPDATE", "TABLE":
+		default:
+			continue
+		}
+		next := i + 1
+		// Skipping the IF [NOT] EXISTS clause of the CREATE and DROP statements.
+		if strings.EqualFold(tokens[next], "IF") {
+			next += 2
+			if next < len(tokens) && strings.EqualFold(tokens[next-1], "NOT") {
+				next++
+			}
+			if next >= len(tokens) {
+				return ""
+			}
+		}
+		table := tokens[next]
+		dot := strings.IndexByte(table, '.')
+		if dot <= 0 {
+			return ""
+		}
+		return unquote(table[:dot])
+	}
+	return ""
+}
+
+// unquote returns the name given without its double quotes, or in lower case if it isn't quoted, as CQL identifiers
+// are case-insensitive unless quoted.
+func unquote(name string) string {
+	if len(name) >= 2 && name[0] == '"' && name[len(name)-1] == '"' {
+		return name[1 : len(name)-1]
+	}
+	return strings.ToLower(name)
+}
@@ -0,0 +1,112 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package cassandra
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func longString(s string) []byte {
+	return append(binary.BigEndian.AppendUint32(nil, uint32(len(s))), s...)
+}
+
+func shortString(s string) []byte {
+	return append(binary.BigEndian.AppendUint16(nil, uint16(len(s))), s...)
+}
+
+func TestKeyspaceFromStatement(t *testing.T) {
+	tests := []struct {
+		statement string
+		expected  string
+	}{
+		{statement: "SELECT * FROM shop.orders WHERE id = ?", expected: "shop"},
+		{statement: "select id from Shop.orders", expected: "shop"},
+		{statement: `SELECT * FROM "Shop".orders`, expected: "Shop"},
+		{statement: "INSERT INTO shop.orders(id, total) VALUES (?, ?)", expected: "shop"},
+		{statement: "UPDATE shop.orders SET total = ? WHERE id = ?", expected: "shop"},
+		{statement: "DELETE FROM shop.orders WHERE id = ?", expected: "shop"},
+		{statement: "CREATE TABLE IF NOT EXISTS shop.orders (id int PRIMARY KEY)", expected: "shop"},
+		{statement: "DROP TABLE IF EXISTS shop.orders", expected: "shop"},
+		{statement: "USE shop;", expected: "shop"},
+		{statement: `USE "Shop"`, expected: "Shop"},
+		{statement: "SELECT * FROM orders", expected: ""},
+		{statement: "CREATE KEYSPACE shop WITH replication = {}", expected: ""},
+		{statement: "SELECT * FROM", expected: ""},
+		{statement: "", expected: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.statement, func(t *testing.T) {
+			assert.Equal(t, tt.expected, keyspaceFromStatement(tt.statement))
+		})
+	}
+}
+
+func TestParseRequest(t *testing.T) {
+	id := "0123456789abcdef"
+
+	t.Run("query", func(t *testing.T) {
+		fragment := append(longString("SELECT * FROM shop.orders"), 0x00, 0x01)
+		assert.Equal(t, request{statement: "SELECT * FROM shop.orders"}, parseRequest(QueryOpcode, fragment))
+	})
+
+	t.Run("truncated query", func(t *testing.T) {
+		fragment := longString("SELECT * FROM shop.orders")[:20]
+		assert.Equal(t, request{statement: "SELECT * FROM sh"}, parseRequest(QueryOpcode, fragment))
+	})
+
+	t.Run("prepare", func(t *testing.T) {
+		fragment := longString("SELECT * FROM shop.orders WHERE id = ?")
+		assert.Equal(t, request{statement: "SELECT * FROM shop.orders WHERE id = ?"}, parseRequest(PrepareOpcode, fragment))
+	})
+
+	t.Run("execute", func(t *testing.T) {
+		fragment := append(shortString(id), 0x00, 0x06)
+		assert.Equal(t, request{preparedID: id}, parseRequest(ExecuteOpcode, fragment))
+	})
+
+	t.Run("batch of queries", func(t *testing.T) {
+		fragment := append([]byte{0x00, 0x00, 0x02, batchQueryKind}, longString("INSERT INTO shop.orders(id) VALUES (1)")...)
+		assert.Equal(t, request{statement: "INSERT INTO shop.orders(id) VALUES (1)"}, parseRequest(BatchOpcode, fragment))
+	})
+
+	t.Run("batch of prepared statements", func(t *testing.T) {
+		fragment := append([]byte{0x00, 0x00, 0x02, batchPreparedKind}, shortString(id)...)
+		assert.Equal(t, request{preparedID: id}, parseRequest(BatchOpcode, fragment))
+	})
+
+	t.Run("empty fragment", func(t *testing.T) {
+		assert.Equal(t, request{}, parseRequest(QueryOpcode, make([]byte, 16)))
+	})
+}
+
+func TestParseResponse(t *testing.T) {
+	t.Run("error", func(t *testing.T) {
+		fragment := append(binary.BigEndian.AppendUint32(nil, 0x2200), shortString("Invalid query")...)
+		assert.Equal(t, response{isError: true}, parseResponse(ErrorOpcode, fragment))
+	})
+
+	t.Run("compressed error", func(t *testing.T) {
+		assert.Equal(t, response{isError: true}, parseResponse(ErrorOpcode, nil))
+	})
+
+	t.Run("set keyspace", func(t *testing.T) {
+		fragment := append(binary.BigEndian.AppendUint32(nil, resultSetKeyspace), shortString("shop")...)
+		assert.Equal(t, response{keyspace: "shop"}, parseResponse(ResultOpcode, fragment))
+	})
+
+	t.Run("prepared", func(t *testing.T) {
+		fragment := append(binary.BigEndian.AppendUint32(nil, resultPrepared), shortString("0123456789abcdef")...)
+		assert.Equal(t, response{preparedID: "0123456789abcdef"}, parseResponse(ResultOpcode, fragment))
+	})
+
+	t.Run("rows", func(t *testing.T) {
+		fragment := binary.BigEndian.AppendUint32(nil, 0x0002)
+		assert.Equal(t, response{}, parseResponse(ResultOpcode, fragment))
+	})
+}
@@ -0,0 +1,149 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package cassandra
+
+import "strconv"
+
+// Opcode represents the opcode of a frame of the CQL native protocol.
+// https://github.com/apache/cassandra/blob/trunk/doc/native_protocol_v4.spec#L183
+type Opcode uint8
+
+const (
+	// ErrorOpcode is the opcode of the ERROR responses.
+	ErrorOpcode Opcode = 0x00
+	// StartupOpcode is the opcode of the STARTUP requests.
+	StartupOpcode Opcode = 0x01
+	// ReadyOpcode is the opcode of the READY responses.
+	ReadyOpcode Opcode = 0x02
+	// AuthenticateOpcode is the opcode of the AUTHENTICATE responses.
+	AuthenticateOpcode Opcode = 0x03
+	// OptionsOpcode is the opcode of the OPTIONS requests.
+	OptionsOpcode Opcode = 0x05
+	// SupportedOpcode is the opcode of the SUPPORTED responses.
+	SupportedOpcode Opcode = 0x06
+	// QueryOpcode is the opcode of the QUERY requests.
+	QueryOpcode Opcode = 0x07
+	// ResultOpcode is the opcode of the RESULT responses.
+	ResultOpcode Opcode = 0x08
+	// PrepareOpcode is the opcode of the PREPARE requests.
+	PrepareOpcode Opcode = 0x09
+	// ExecuteOpcode is the opcode of the EXECUTE requests.
+	ExecuteOpcode Opcode = 0x0A
+	// RegisterOpcode is the opcode of the REGISTER requests.
+	RegisterOpcode Opcode = 0x0B
+	// EventOpcode is the opcode of the EVENT responses.
+	EventOpcode Opcode = 0x0C
+	// BatchOpcode is the opcode of the BATCH requests.
+	BatchOpcode Opcode = 0x0D
+	// AuthChallengeOpcode is the opcode of the AUTH_CHALLENGE responses.
+	AuthChallengeOpcode Opcode = 0x0E
+	// AuthResponseOpcode is the opcode of the AUTH_RESPONSE requests.
+	AuthResponseOpcode Opcode = 0x0F
+	// AuthSuccessOpcode is the opcode of the AUTH_SUCCESS responses.
+	AuthSuccessOpcode Opcode = 0x10
+)
+
+// String returns the string representation of the opcode.
+func (o Opcode) String() string {
+	switch o {
+	case ErrorOpcode:
+		return "ERROR"
+	case StartupOpcode:
+		return "STARTUP"
+	case ReadyOpcode:
+		return "READY"
+	case AuthenticateOpcode:
+		return "AUTHENTICATE"
+	case OptionsOpcode:
+		return "OPTIONS"
+	case SupportedOpcode:
+		return "SUPPORTED"
+	case QueryOpcode:
+		return "QUERY"
+	case ResultOpcode:
+		return "RESULT"
+	case PrepareOpcode:
+		return "PREPARE"
+	case ExecuteOpcode:
+		return "EXECUTE"
+	case RegisterOpcode:
+		return "REGISTER"
+	case EventOpcode:
+		return "EVENT"
+	case BatchOpcode:
+		return "BATCH"
+	case AuthChallengeOpcode:
+		return "AUTH_CHALLENGE"
+	case AuthResponseOpcode:
+		return "AUTH_RESPONSE"
+	case AuthSuccessOpcode:
+		return "AUTH_SUCCESS"
+	default:
+		return "UNKNOWN_OPCODE_" + strconv.Itoa(int(o))
+	}
+}
+
+// Consistency represents the consistency level of a request.
+// https://github.com/apache/cassandra/blob/trunk/doc/native_protocol_v4.spec#L244
+type Consistency uint16
+
+const (
+	// ConsistencyAny is the ANY consistency level.
+	ConsistencyAny Consistency = 0x0000
+	// ConsistencyOne is the ONE consistency level.
+	ConsistencyOne Consistency = 0x0001
+	// ConsistencyTwo is the TWO consistency level.
+	ConsistencyTwo Consistency = 0x0002
+	// ConsistencyThree is the THREE consistency level.
+	ConsistencyThree Consistency = 0x0003
+	// ConsistencyQuorum is the QUORUM consistency level.
+	ConsistencyQuorum Consistency = 0x0004
+	// ConsistencyAll is the ALL consistency level.
+	ConsistencyAll Consistency = 0x0005
+	// ConsistencyLocalQuorum is the LOCAL_QUORUM consistency level.
+	ConsistencyLocalQuorum Consistency = 0x0006
+	// ConsistencyEachQuorum is the EACH_QUORUM consistency level.
+	ConsistencyEachQuorum Consistency = 0x0007
+	// ConsistencySerial is the SERIAL consistency level.
+	ConsistencySerial Consistency = 0x0008
+	// ConsistencyLocalSerial is the LOCAL_SERIAL consistency level.
+	ConsistencyLocalSerial Consistency = 0x0009
+	// ConsistencyLocalOne is the LOCAL_ONE consistency level.
+	ConsistencyLocalOne Consistency = 0x000A
+	// ConsistencyUnknown is used for the requests whose consistency level is unknown, such as PREPARE requests which
+	// don't have one, or the requests whose body could not be decoded by the kernel.
+	ConsistencyUnknown Consistency = 0xFFFF
+)
+
+// String returns the string representation of the consistency level.
+func (c Consistency) String() string {
+	switch c {
+	case ConsistencyAny:
+		return "ANY"
+	case ConsistencyOne:
+		return "ONE"
+	case ConsistencyTwo:
+		return "TWO"
+	case ConsistencyThree:
+		return "THREE"
+	case ConsistencyQuorum:
+		return "QUORUM"
+	case ConsistencyAll:
+		return "ALL"
+	case ConsistencyLocalQuorum:
+		return "LOCAL_QUORUM"
+	case ConsistencyEachQuorum:
+		return "EACH_QUORUM"
+	case ConsistencySerial:
+		return "SERIAL"
+	case ConsistencyLocalSerial:
+		return "LOCAL_SERIAL"
+	case ConsistencyLocalOne:
+		return "LOCAL_ONE"
+	default:
+		return "UNKNOWN"
+	}
+}
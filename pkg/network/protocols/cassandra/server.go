@@ -0,0 +1,46 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+//go:build test
+
+package cassandra
+
+import (
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/network/protocols/http/testutil"
+	globalutils "github.com/DataDog/datadog-agent/pkg/util/testutil"
+	dockerutils "github.com/DataDog/datadog-agent/pkg/util/testutil/docker"
+)
+
+// startupTimeout is the time we wait for the server to accept CQL clients, as Cassandra takes longer to start than
+// the other servers we test against.
+const startupTimeout = 3 * time.Minute
+
+// RunServer runs a Cassandra server in a docker container
+func RunServer(t testing.TB, serverAddr, serverPort string) error {
+	t.Helper()
+	dir, _ := testutil.CurDir()
+
+	env := []string{
+		"CASSANDRA_ADDR=" + serverAddr,
+		"CASSANDRA_PORT=" + serverPort,
+	}
+
+	scanner, err := globalutils.NewScanner(regexp.MustCompile(".*Starting listening for CQL clients"), globalutils.NoPattern)
+	require.NoError(t, err, "failed to create pattern scanner")
+	dockerCfg := dockerutils.NewComposeConfig("cassandra",
+		startupTimeout,
+		dockerutils.DefaultRetries,
+		scanner,
+		env,
+		filepath.Join(dir, "testdata", "docker-compose.yml"))
+	return dockerutils.Run(t, dockerCfg)
+}
@@ -0,0 +1,68 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package cassandra
+
+import (
+	"github.com/DataDog/sketches-go/ddsketch"
+
+	"github.com/DataDog/datadog-agent/pkg/network/types"
+	"github.com/DataDog/datadog-agent/pkg/process/util"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// This file contains the structs used to store and combine the stats for the Cassandra protocol.
+// The file does not have any build tag, so it can be used in any build as it is used by the tracer package.
+
+// Key is an identifier for a group of Cassandra transactions
+type Key struct {
+	Opcode      Opcode
+	Keyspace    string
+	Consistency Consistency
+	types.ConnectionKey
+}
+
+// NewKey creates a new Cassandra key
+func NewKey(saddr, daddr util.Address, sport, dport uint16, opcode Opcode, keyspace string, consistency Consistency) Key {
+	return Key{
+		ConnectionKey: types.NewConnectionKey(saddr, daddr, sport, dport),
+		Opcode:        opcode,
+		Keyspace:      keyspace,
+		Consistency:   consistency,
+	}
+}
+
+// RequestStat represents a group of Cassandra transactions that has a shared key.
+type RequestStat struct {
+	// this field order is intentional to help the GC pointer tracking
+	Latencies          *ddsketch.DDSketch
+	FirstLatencySample float64
+	Count              int
+	// ErrorCount is the number of transactions answered with an ERROR response.
+	ErrorCount int
+	StaticTags uint64
+}
+
+// CombineWith merges the data in 2 RequestStats objects
+// newStats is kept as it is, while the method receiver gets mutated
+func (r *RequestStat) CombineWith(newStats *RequestStat) {
+	r.Count += newStats.Count
+	r.ErrorCount += newStats.ErrorCount
+	r.StaticTags |= newStats.StaticTags
+	// If the receiver has no latency sample, use the newStats sample
+	if r.FirstLatencySample == 0 {
+		r.FirstLatencySample = newStats.FirstLatencySample
+	}
+	// If newStats has no ddsketch latency, we have nothing to merge
+	if newStats.Latencies == nil {
+		return
+	}
+	// If the receiver has no ddsketch latency, use the newStats latency
+	if r.Latencies == nil {
+		r.Latencies = newStats.Latencies.Copy()
+	} else if err := r.Latencies.MergeWith(newStats.Latencies); err != nil {
+		log.Debugf("could not add request latency to ddsketch: %v", err)
+	}
+}
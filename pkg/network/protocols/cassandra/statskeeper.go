@@ -0,0 +1,131 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+//go:build linux_bpf
+
+package cassandra
+
+import (
+	"sync"
+
+	"github.com/DataDog/datadog-agent/pkg/network/config"
+	"github.com/DataDog/datadog-agent/pkg/network/types"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// StatKeeper is a struct to hold the records for the Cassandra protocol
+type StatKeeper struct {
+	stats map[Key]*RequestStat
+	// preparedKeyspaces maps the ids of the prepared statements to their keyspace, as EXECUTE requests only carry
+	// the ids of the statements.
+	preparedKeyspaces map[string]string
+	// connectionKeyspaces maps the connections to the keyspace set by their last USE statement, which is the keyspace
+	// of the statements not qualifying their tables.
+	connectionKeyspaces map[types.ConnectionKey]string
+	statsMutex          sync.RWMutex
+	maxEntries          int
+}
+
+// NewStatkeeper creates a new StatKeeper
+func NewStatkeeper(c *config.Config) *StatKeeper {
+	newStatKeeper := &StatKeeper{
+		maxEntries:          c.MaxCassandraStatsBuffered,
+		preparedKeyspaces:   make(map[string]string),
+		connectionKeyspaces: make(map[types.ConnectionKey]string),
+	}
+	newStatKeeper.resetNoLock()
+	return newStatKeeper
+}
+
+// Process processes the Cassandra transaction
+func (s *StatKeeper) Process(tx *EventWrapper) {
+	latency := tx.RequestLatency()
+	if latency <= 0 {
+		return
+	}
+
+	s.statsMutex.Lock()
+	defer s.statsMutex.Unlock()
+
+	connection := tx.ConnTuple()
+	keyspace := s.resolveKeyspaceNoLock(tx, connection)
+
+	key := Key{
+		Opcode:        tx.Opcode(),
+		Keyspace:      keyspace,
+		Consistency:   tx.Consistency(),
+		ConnectionKey: connection,
+	}
+	requestStats, ok := s.stats[key]
+	if !ok {
+		if len(s.stats) >= s.maxEntries {
+			return
+		}
+		requestStats = new(RequestStat)
+		s.stats[key] = requestStats
+	}
+	requestStats.StaticTags = uint64(tx.Tx.Tags)
+	requestStats.Count++
+	if tx.IsError() {
+		requestStats.ErrorCount++
+	}
+	if requestStats.Count == 1 {
+		requestStats.FirstLatencySample = latency
+		return
+	}
+	if requestStats.Latencies == nil {
+		if err := requestStats.initSketch(); err != nil {
+			return
+		}
+		if err := requestStats.Latencies.Add(requestStats.FirstLatencySample); err != nil {
+			return
+		}
+	}
+	if err := requestStats.Latencies.Add(latency); err != nil {
+		log.Debugf("could not add request latency to ddsketch: %v", err)
+	}
+}
+
+// resolveKeyspaceNoLock returns the keyspace of the transaction, and records the keyspaces of the prepared
+// statements and of the connections the transaction reveals.
+func (s *StatKeeper) resolveKeyspaceNoLock(tx *EventWrapper, connection types.ConnectionKey) string {
+	var keyspace string
+	if statement := tx.Statement(); statement != "" {
+		keyspace = keyspaceFromStatement(statement)
+	} else if preparedID := tx.PreparedID(); preparedID != "" {
+		keyspace = s.preparedKeyspaces[preparedID]
+	}
+	if keyspace == "" {
+		keyspace = s.connectionKeyspaces[connection]
+	}
+
+	if resultKeyspace := tx.ResultKeyspace(); resultKeyspace != "" {
+		// Bounding the memory used by the keyspaces, as the connections are never removed from the map.
+		if _, ok := s.connectionKeyspaces[connection]; !ok && len(s.connectionKeyspaces) >= s.maxEntries {
+			clear(s.connectionKeyspaces)
+		}
+		s.connectionKeyspaces[connection] = resultKeyspace
+	}
+	if preparedID := tx.ResultPreparedID(); preparedID != "" && keyspace != "" {
+		if _, ok := s.preparedKeyspaces[preparedID]; !ok && len(s.preparedKeyspaces) >= s.maxEntries {
+			clear(s.preparedKeyspaces)
+		}
+		s.preparedKeyspaces[preparedID] = keyspace
+	}
+	return keyspace
+}
+
+// GetAndResetAllStats returns all the records and resets the statskeeper
+func (s *StatKeeper) GetAndResetAllStats() map[Key]*RequestStat {
+	s.statsMutex.Lock()
+	defer s.statsMutex.Unlock()
+	ret := s.stats // No deep copy needed since `s.stats` gets reset
+	s.resetNoLock()
+	return ret
+}
+
+func (s *StatKeeper) resetNoLock() {
+	s.stats = make(map[Key]*RequestStat)
+}
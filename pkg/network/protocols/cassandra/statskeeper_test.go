@@ -0,0 +1,82 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+//go:build linux_bpf
+
+package cassandra
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/network/config"
+	"github.com/DataDog/datadog-agent/pkg/network/protocols/cassandra/ebpf"
+)
+
+func newEvent(requestOpcode Opcode, request []byte, consistency Consistency, responseOpcode Opcode, response []byte) *EventWrapper {
+	tx := ebpf.EbpfTx{
+		Request_started:    1,
+		Response_last_seen: 10,
+		Request_opcode:     uint8(requestOpcode),
+		Response_opcode:    uint8(responseOpcode),
+		Consistency:        uint16(consistency),
+	}
+	copy(tx.Request_fragment[:], request)
+	copy(tx.Response_fragment[:], response)
+	return NewEventWrapper(&ebpf.EbpfEvent{Tx: tx})
+}
+
+func result(kind uint32, value string) []byte {
+	return append(binary.BigEndian.AppendUint32(nil, kind), shortString(value)...)
+}
+
+func TestStatKeeperProcess(t *testing.T) {
+	cfg := config.New()
+	cfg.MaxCassandraStatsBuffered = 100
+	s := NewStatkeeper(cfg)
+	for i := 0; i < 20; i++ {
+		s.Process(newEvent(QueryOpcode, longString("SELECT * FROM shop.orders"), ConsistencyLocalQuorum, ResultOpcode, nil))
+	}
+	s.Process(newEvent(QueryOpcode, longString("SELECT * FROM shop.orders"), ConsistencyLocalQuorum, ErrorOpcode, nil))
+
+	stats := s.GetAndResetAllStats()
+	require.Len(t, stats, 1)
+	for k, stat := range stats {
+		require.Equal(t, QueryOpcode, k.Opcode)
+		require.Equal(t, "shop", k.Keyspace)
+		require.Equal(t, ConsistencyLocalQuorum, k.Consistency)
+		require.Equal(t, 21, stat.Count)
+		require.Equal(t, 1, stat.ErrorCount)
+		require.Equal(t, float64(21), stat.Latencies.GetCount())
+	}
+	require.Empty(t, s.GetAndResetAllStats())
+}
+
+func TestStatKeeperKeyspaceResolution(t *testing.T) {
+	cfg := config.New()
+	cfg.MaxCassandraStatsBuffered = 100
+	s := NewStatkeeper(cfg)
+	id := "0123456789abcdef"
+
+	// The keyspace of the connection is set by USE statements.
+	s.Process(newEvent(QueryOpcode, longString("USE shop"), ConsistencyOne, ResultOpcode, result(resultSetKeyspace, "shop")))
+	s.Process(newEvent(QueryOpcode, longString("SELECT * FROM orders"), ConsistencyOne, ResultOpcode, nil))
+	// The keyspace of EXECUTE requests is the one of the statement prepared.
+	s.Process(newEvent(PrepareOpcode, longString("SELECT * FROM inventory.items WHERE id = ?"), ConsistencyUnknown, ResultOpcode, result(resultPrepared, id)))
+	s.Process(newEvent(ExecuteOpcode, shortString(id), ConsistencyQuorum, ResultOpcode, nil))
+
+	stats := s.GetAndResetAllStats()
+	require.Len(t, stats, 3)
+	keyspaces := make(map[Opcode]string)
+	counts := make(map[Opcode]int)
+	for k, stat := range stats {
+		keyspaces[k.Opcode] = k.Keyspace
+		counts[k.Opcode] += stat.Count
+	}
+	require.Equal(t, map[Opcode]string{QueryOpcode: "shop", PrepareOpcode: "inventory", ExecuteOpcode: "inventory"}, keyspaces)
+	require.Equal(t, 2, counts[QueryOpcode])
+}
@@ -79,6 +79,12 @@ const (
 	ProgramRedis ProgramType = C.PROG_REDIS
 	// ProgramRedisTermination is the Golang representation of the C.PROG_REDIS_TERMINATION enum
 	ProgramRedisTermination ProgramType = C.PROG_REDIS_TERMINATION
+	// ProgramMemcached is the Golang representation of the C.PROG_MEMCACHED enum
+	ProgramMemcached ProgramType = C.PROG_MEMCACHED
+	// ProgramMemcachedTermination is the Golang representation of the C.PROG_MEMCACHED_TERMINATION enum
+	ProgramMemcachedTermination ProgramType = C.PROG_MEMCACHED_TERMINATION
+	// ProgramCassandra is the Golang representation of the C.PROG_CASSANDRA enum
+	ProgramCassandra ProgramType = C.PROG_CASSANDRA
 )
 
 type ebpfProtocolType C.protocol_t
@@ -104,6 +110,10 @@ const (
 	ebpfRedis ebpfProtocolType = C.PROTOCOL_REDIS
 	// MySQL protocol
 	ebpfMySQL ebpfProtocolType = C.PROTOCOL_MYSQL
+	// Memcached protocol
+	ebpfMemcached ebpfProtocolType = C.PROTOCOL_MEMCACHED
+	// Cassandra protocol
+	ebpfCassandra ebpfProtocolType = C.PROTOCOL_CASSANDRA
 	// GRPC protocol
 	ebpfGRPC ebpfProtocolType = C.PROTOCOL_GRPC
 )
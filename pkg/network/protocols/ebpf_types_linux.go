@@ -65,6 +65,12 @@ const (
 	ProgramRedis ProgramType = 0x16
 
 	ProgramRedisTermination ProgramType = 0x17
+
+	ProgramMemcached ProgramType = 0x18
+
+	ProgramMemcachedTermination ProgramType = 0x19
+
+	ProgramCassandra ProgramType = 0x1a
 )
 
 type ebpfProtocolType uint16
@@ -90,5 +96,9 @@ const (
 
 	ebpfMySQL ebpfProtocolType = 0x4008
 
+	ebpfMemcached ebpfProtocolType = 0x4009
+
+	ebpfCassandra ebpfProtocolType = 0x400a
+
 	ebpfGRPC ebpfProtocolType = 0x2001
 )
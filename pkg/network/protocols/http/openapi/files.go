@@ -0,0 +1,37 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package openapi
+
+import (
+	"os"
+
+	"github.com/DataDog/datadog-agent/pkg/network/config"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// configSource is the source of the services of the configuration files
+const configSource = "config"
+
+// LoadSpecFiles replaces the services of the configuration with the specification files given. The
+// invalid specifications are skipped.
+func (r *Registry) LoadSpecFiles(specs []*config.OpenAPISpec) {
+	services := make([]*Service, 0, len(specs))
+	for _, spec := range specs {
+		data, err := os.ReadFile(spec.Path)
+		if err != nil {
+			log.Errorf("could not read the OpenAPI specification of service %q: %s", spec.Service, err)
+			continue
+		}
+		routes, err := ParseSpec(data)
+		if err != nil {
+			log.Errorf("could not load the OpenAPI specification %s of service %q: %s", spec.Path, spec.Service, err)
+			continue
+		}
+		log.Infof("loaded %d HTTP route templates of service %q from %s", routes.Len(), spec.Service, spec.Path)
+		services = append(services, NewService(spec.Service, routes, nil, spec.Ports))
+	}
+	r.Set(configSource, services...)
+}
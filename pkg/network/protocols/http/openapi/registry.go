@@ -0,0 +1,142 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package openapi
+
+import (
+	"net/netip"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"github.com/DataDog/datadog-agent/pkg/network/types"
+	"github.com/DataDog/datadog-agent/pkg/process/util"
+)
+
+// DefaultRegistry holds the routes of the services shared by the HTTP stat keepers
+var DefaultRegistry = NewRegistry()
+
+// Service is a service whose routes are declared by a specification. The service is identified by the
+// server side of the connections, with the addresses and the ports given. A service without addresses
+// or ports matches any of them.
+type Service struct {
+	Name      string
+	routes    *Routes
+	addresses []address
+	ports     []uint16
+}
+
+type address struct {
+	low, high uint64
+}
+
+// NewService returns a Service with the routes given, listening on the addresses and ports given
+func NewService(name string, routes *Routes, addresses []netip.Addr, ports []uint16) *Service {
+	s := &Service{
+		Name:   name,
+		routes: routes,
+		ports:  ports,
+	}
+	for _, addr := range addresses {
+		low, high := util.ToLowHighIP(addr.Unmap())
+		s.addresses = append(s.addresses, address{low: low, high: high})
+	}
+	return s
+}
+
+func (s *Service) hasAddress(low, high uint64) bool {
+	return len(s.addresses) == 0 || slices.Contains(s.addresses, address{low: low, high: high})
+}
+
+// Registry holds the services of the sources of specifications, the configuration files or the
+// annotations of the workloads. The lookups don't take any lock, the index being rebuilt when the
+// services of a source change.
+type Registry struct {
+	mux     sync.Mutex
+	sources map[string][]*Service
+	index   atomic.Pointer[index]
+}
+
+type index struct {
+	byPort  map[uint16][]*Service
+	anyPort []*Service
+}
+
+// NewRegistry returns an empty Registry
+func NewRegistry() *Registry {
+	return &Registry{sources: make(map[string][]*Service)}
+}
+
+// Set replaces the services of a source
+func (r *Registry) Set(source string, services ...*Service) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	if len(services) == 0 {
+		delete(r.sources, source)
+	} else {
+		r.sources[source] = services
+	}
+	r.rebuild()
+}
+
+// Delete removes the services of a source
+func (r *Registry) Delete(source string) {
+	r.Set(source)
+}
+
+// rebuild indexes the services by port, in the order of their sources so that the overlapping services
+// are always matched in the same order
+func (r *Registry) rebuild() {
+	if len(r.sources) == 0 {
+		r.index.Store(nil)
+		return
+	}
+
+	idx := &index{byPort: make(map[uint16][]*Service)}
+	sources := make([]string, 0, len(r.sources))
+	for source := range r.sources {
+		sources = append(sources, source)
+	}
+	slices.Sort(sources)
+	for _, source := range sources {
+		for _, s := range r.sources[source] {
+			if len(s.ports) == 0 {
+				idx.anyPort = append(idx.anyPort, s)
+				continue
+			}
+			for _, port := range s.ports {
+				idx.byPort[port] = append(idx.byPort[port], s)
+			}
+		}
+	}
+	r.index.Store(idx)
+}
+
+// Match returns the route template of a path sent over a connection. Both ends of the connection are
+// looked up, the server being either of them depending on where the traffic was captured.
+func (r *Registry) Match(conn types.ConnectionKey, path []byte) ([]byte, bool) {
+	idx := r.index.Load()
+	if idx == nil {
+		return nil, false
+	}
+	if template, ok := idx.match(conn.DstIPLow, conn.DstIPHigh, conn.DstPort, path); ok {
+		return template, true
+	}
+	return idx.match(conn.SrcIPLow, conn.SrcIPHigh, conn.SrcPort, path)
+}
+
+func (idx *index) match(low, high uint64, port uint16, path []byte) ([]byte, bool) {
+	for _, services := range [][]*Service{idx.byPort[port], idx.anyPort} {
+		for _, s := range services {
+			if !s.hasAddress(low, high) {
+				continue
+			}
+			if template, ok := s.routes.Match(path); ok {
+				return template, true
+			}
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,136 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package openapi
+
+import (
+	"net/netip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	workloadmeta "github.com/DataDog/datadog-agent/comp/core/workloadmeta/def"
+	"github.com/DataDog/datadog-agent/pkg/network/config"
+	"github.com/DataDog/datadog-agent/pkg/network/types"
+	"github.com/DataDog/datadog-agent/pkg/process/util"
+)
+
+func connection(client, server string, clientPort, serverPort uint16) types.ConnectionKey {
+	return types.NewConnectionKey(util.AddressFromString(client), util.AddressFromString(server), clientPort, serverPort)
+}
+
+func TestRegistryMatch(t *testing.T) {
+	users, err := ParseSpec([]byte("openapi: 3.0.0\npaths: {\"/users/{id}\": {}}"))
+	require.NoError(t, err)
+	orders, err := ParseSpec([]byte("openapi: 3.0.0\npaths: {\"/orders/{id}\": {}, \"/users/{name}\": {}}"))
+	require.NoError(t, err)
+
+	r := NewRegistry()
+	_, ok := r.Match(connection("10.0.0.1", "10.0.0.2", 40000, 8080), []byte("/users/1"))
+	assert.False(t, ok)
+
+	r.Set("b", NewService("users", users, nil, []uint16{8080}))
+	r.Set("a", NewService("orders", orders, []netip.Addr{netip.MustParseAddr("10.0.0.3")}, nil))
+
+	for _, tc := range []struct {
+		conn     types.ConnectionKey
+		path     string
+		template string
+	}{
+		{connection("10.0.0.1", "10.0.0.2", 40000, 8080), "/users/1", "/users/{id}"},
+		// the server is the source of the connection
+		{connection("10.0.0.2", "10.0.0.1", 8080, 40000), "/users/1", "/users/{id}"},
+		{connection("10.0.0.1", "10.0.0.3", 40000, 8080), "/orders/1", "/orders/{id}"},
+		// the services listening on the port are matched first
+		{connection("10.0.0.1", "10.0.0.3", 40000, 8080), "/users/1", "/users/{id}"},
+		{connection("10.0.0.1", "10.0.0.3", 40000, 80), "/users/1", "/users/{name}"},
+		{connection("10.0.0.1", "10.0.0.2", 40000, 80), "/users/1", ""},
+		{connection("10.0.0.1", "10.0.0.2", 40000, 8080), "/orders/1", ""},
+	} {
+		template, ok := r.Match(tc.conn, []byte(tc.path))
+		assert.Equal(t, tc.template != "", ok, "%s %s", tc.conn.String(), tc.path)
+		assert.Equal(t, tc.template, string(template), "%s %s", tc.conn.String(), tc.path)
+	}
+
+	r.Delete("b")
+	_, ok = r.Match(connection("10.0.0.1", "10.0.0.2", 40000, 8080), []byte("/users/1"))
+	assert.False(t, ok)
+	r.Delete("a")
+	assert.Nil(t, r.index.Load())
+}
+
+func TestLoadSpecFiles(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "valid.json")
+	require.NoError(t, os.WriteFile(valid, []byte(swaggerSpec), 0o644))
+	invalid := filepath.Join(dir, "invalid.yaml")
+	require.NoError(t, os.WriteFile(invalid, []byte("paths: {}"), 0o644))
+
+	r := NewRegistry()
+	r.LoadSpecFiles([]*config.OpenAPISpec{
+		{Service: "pets", Path: valid, Ports: []uint16{8080}},
+		{Service: "invalid", Path: invalid},
+		{Service: "missing", Path: filepath.Join(dir, "missing.yaml")},
+	})
+
+	require.Len(t, r.sources[configSource], 1)
+	assert.Equal(t, "pets", r.sources[configSource][0].Name)
+	template, ok := r.Match(connection("10.0.0.1", "10.0.0.2", 40000, 8080), []byte("/v2/pets/rex"))
+	require.True(t, ok)
+	assert.Equal(t, "/v2/pets/{petId}", string(template))
+}
+
+func TestWorkloadService(t *testing.T) {
+	pod := &workloadmeta.KubernetesPod{
+		EntityID: workloadmeta.EntityID{Kind: workloadmeta.KindKubernetesPod, ID: "pod-uid"},
+		EntityMeta: workloadmeta.EntityMeta{
+			Name: "users-7d9f",
+			Annotations: map[string]string{
+				podSpecAnnotation:  swaggerSpec,
+				podPortsAnnotation: "8080, 8443",
+			},
+			Labels: map[string]string{podServiceLabel: "users"},
+		},
+		IP: "10.0.0.2",
+	}
+	s, err := workloadService(pod)
+	require.NoError(t, err)
+	require.NotNil(t, s)
+	assert.Equal(t, "users", s.Name)
+	assert.Equal(t, []uint16{8080, 8443}, s.ports)
+	l, h := util.ToLowHigh(util.AddressFromString("10.0.0.2"))
+	assert.True(t, s.hasAddress(l, h))
+
+	container := &workloadmeta.Container{
+		EntityID: workloadmeta.EntityID{Kind: workloadmeta.KindContainer, ID: "container-id"},
+		EntityMeta: workloadmeta.EntityMeta{
+			Name:   "pets",
+			Labels: map[string]string{containerSpecLabel: openAPISpec},
+		},
+		NetworkIPs: map[string]string{"bridge": "172.17.0.2"},
+	}
+	s, err = workloadService(container)
+	require.NoError(t, err)
+	require.NotNil(t, s)
+	assert.Equal(t, "pets", s.Name)
+	assert.Empty(t, s.ports)
+
+	// no specification or no address
+	container.NetworkIPs = nil
+	s, err = workloadService(container)
+	require.NoError(t, err)
+	assert.Nil(t, s)
+	pod.Annotations = nil
+	s, err = workloadService(pod)
+	require.NoError(t, err)
+	assert.Nil(t, s)
+
+	pod.Annotations = map[string]string{podSpecAnnotation: swaggerSpec, podPortsAnnotation: "http"}
+	_, err = workloadService(pod)
+	assert.Error(t, err)
+}
@@ -0,0 +1,238 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+// Package openapi maps the HTTP paths observed by USM to the route templates declared in the OpenAPI
+// (or Swagger) specifications of the services
+package openapi
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// document holds the parts of an OpenAPI 3 or Swagger 2 specification needed to build the routes. Being
+// a superset of JSON, YAML is used to decode both formats.
+type document struct {
+	Swagger  string `yaml:"swagger"`
+	OpenAPI  string `yaml:"openapi"`
+	BasePath string `yaml:"basePath"`
+	Servers  []struct {
+		URL string `yaml:"url"`
+	} `yaml:"servers"`
+	Paths map[string]any `yaml:"paths"`
+}
+
+// Routes matches paths against the route templates of a specification. The literal segments of the
+// templates take precedence over the templated ones, so that `/users/me` wins over `/users/{id}`.
+type Routes struct {
+	root *node
+	size int
+}
+
+type node struct {
+	literals map[string]*node
+	// patterns are the segments mixing literals and parameters, such as `{name}.json`
+	patterns []*patternNode
+	// param is the child of the segments made of a single parameter, such as `{id}`
+	param *node
+	// template is set when a route ends at this node
+	template []byte
+}
+
+type patternNode struct {
+	segment string
+	re      *regexp.Regexp
+	node    *node
+}
+
+// ParseSpec returns the routes of an OpenAPI 3 or Swagger 2 specification, in JSON or YAML
+func ParseSpec(data []byte) (*Routes, error) {
+	var doc document
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid specification: %w", err)
+	}
+	if doc.Swagger == "" && doc.OpenAPI == "" {
+		return nil, errors.New(`the specification has neither a "swagger" nor an "openapi" version`)
+	}
+	if len(doc.Paths) == 0 {
+		return nil, errors.New("the specification has no paths")
+	}
+
+	basePaths := []string{doc.BasePath}
+	if doc.OpenAPI != "" {
+		basePaths = basePaths[:0]
+		for _, server := range doc.Servers {
+			basePaths = append(basePaths, serverBasePath(server.URL))
+		}
+		if len(basePaths) == 0 {
+			basePaths = append(basePaths, "")
+		}
+	}
+
+	routes := &Routes{root: &node{}}
+	for path := range doc.Paths {
+		if !strings.HasPrefix(path, "/") {
+			return nil, fmt.Errorf("invalid path %q, it must start with a slash", path)
+		}
+		for _, basePath := range basePaths {
+			if err := routes.add(strings.TrimSuffix(basePath, "/") + path); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return routes, nil
+}
+
+// serverBasePath returns the path of the URL of an OpenAPI 3 server, which can be relative or hold
+// variables, e.g. `https://{region}.example.com/v1`
+func serverBasePath(url string) string {
+	if i := strings.Index(url, "://"); i >= 0 {
+		url = url[i+3:]
+		i = strings.IndexByte(url, '/')
+		if i < 0 {
+			return ""
+		}
+		url = url[i:]
+	}
+	if !strings.HasPrefix(url, "/") {
+		return ""
+	}
+	return url
+}
+
+// Len returns the number of route templates
+func (r *Routes) Len() int {
+	return r.size
+}
+
+func (r *Routes) add(template string) error {
+	n := r.root
+	for _, segment := range splitPath(template) {
+		child, err := n.child(segment)
+		if err != nil {
+			return fmt.Errorf("invalid path %q: %w", template, err)
+		}
+		n = child
+	}
+	if n.template == nil {
+		n.template = []byte(template)
+		r.size++
+	}
+	return nil
+}
+
+// child returns the child of the node for a segment of template, creating it if needed
+func (n *node) child(segment string) (*node, error) {
+	open := strings.IndexByte(segment, '{')
+	if open < 0 {
+		if n.literals == nil {
+			n.literals = make(map[string]*node)
+		}
+		child, ok := n.literals[segment]
+		if !ok {
+			child = &node{}
+			n.literals[segment] = child
+		}
+		return child, nil
+	}
+
+	if open == 0 && strings.IndexByte(segment, '}') == len(segment)-1 && strings.Count(segment, "{") == 1 {
+		if n.param == nil {
+			n.param = &node{}
+		}
+		return n.param, nil
+	}
+
+	for _, p := range n.patterns {
+		if p.segment == segment {
+			return p.node, nil
+		}
+	}
+	re, err := segmentRegexp(segment)
+	if err != nil {
+		return nil, err
+	}
+	p := &patternNode{segment: segment, re: re, node: &node{}}
+	n.patterns = append(n.patterns, p)
+	return p.node, nil
+}
+
+// segmentRegexp returns the regular expression matching a segment mixing literals and parameters
+func segmentRegexp(segment string) (*regexp.Regexp, error) {
+	var sb strings.Builder
+	sb.WriteByte('^')
+	for segment != "" {
+		open := strings.IndexByte(segment, '{')
+		if open < 0 {
+			sb.WriteString(regexp.QuoteMeta(segment))
+			break
+		}
+		closing := strings.IndexByte(segment[open:], '}')
+		if closing < 0 {
+			return nil, fmt.Errorf("unclosed parameter in segment %q", segment)
+		}
+		sb.WriteString(regexp.QuoteMeta(segment[:open]))
+		sb.WriteString("[^/]+?")
+		segment = segment[open+closing+1:]
+	}
+	sb.WriteByte('$')
+	return regexp.Compile(sb.String())
+}
+
+// Match returns the route template matching a path
+func (r *Routes) Match(path []byte) ([]byte, bool) {
+	if len(path) == 0 || path[0] != '/' {
+		return nil, false
+	}
+	path = bytes.TrimSuffix(path[1:], []byte{'/'})
+	template := r.root.match(path)
+	return template, template != nil
+}
+
+// match returns the template of the path relative to the node, which lost its leading slash
+func (n *node) match(path []byte) []byte {
+	if len(path) == 0 {
+		return n.template
+	}
+
+	segment, rest := path, []byte(nil)
+	if i := bytes.IndexByte(path, '/'); i >= 0 {
+		segment, rest = path[:i], path[i+1:]
+	}
+
+	if child, ok := n.literals[string(segment)]; ok {
+		if template := child.match(rest); template != nil {
+			return template
+		}
+	}
+	if len(segment) == 0 {
+		return nil
+	}
+	for _, p := range n.patterns {
+		if p.re.Match(segment) {
+			if template := p.node.match(rest); template != nil {
+				return template
+			}
+		}
+	}
+	if n.param != nil {
+		return n.param.match(rest)
+	}
+	return nil
+}
+
+// splitPath returns the segments of a path, without the leading and trailing slashes
+func splitPath(path string) []string {
+	path = strings.TrimSuffix(strings.TrimPrefix(path, "/"), "/")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
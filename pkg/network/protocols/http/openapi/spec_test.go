@@ -0,0 +1,103 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package openapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const openAPISpec = `
+openapi: 3.0.0
+servers:
+  - url: https://{region}.example.com/api/v1
+paths:
+  /users:
+    get: {}
+  /users/me:
+    get: {}
+  /users/{userId}:
+    get: {}
+  /users/{userId}/orders/{orderSlug}:
+    get: {}
+  /files/{name}.{extension}:
+    get: {}
+  /:
+    get: {}
+`
+
+const swaggerSpec = `{
+  "swagger": "2.0",
+  "basePath": "/v2",
+  "paths": {
+    "/pets/{petId}": {"get": {}},
+    "/pets/findByStatus": {"get": {}}
+  }
+}`
+
+func TestParseSpec(t *testing.T) {
+	t.Run("OpenAPI 3", func(t *testing.T) {
+		routes, err := ParseSpec([]byte(openAPISpec))
+		require.NoError(t, err)
+		assert.Equal(t, 6, routes.Len())
+
+		for path, expected := range map[string]string{
+			"/api/v1":            "/api/v1/",
+			"/api/v1/users":      "/api/v1/users",
+			"/api/v1/users/":     "/api/v1/users",
+			"/api/v1/users/me":   "/api/v1/users/me",
+			"/api/v1/users/jdoe": "/api/v1/users/{userId}",
+			"/api/v1/users/jdoe/orders/summer-sale-2": "/api/v1/users/{userId}/orders/{orderSlug}",
+			"/api/v1/files/report.pdf":                "/api/v1/files/{name}.{extension}",
+		} {
+			template, ok := routes.Match([]byte(path))
+			if assert.True(t, ok, path) {
+				assert.Equal(t, expected, string(template), path)
+			}
+		}
+
+		for _, path := range []string{
+			"",
+			"/users",
+			"/api/v1/users/jdoe/orders",
+			"/api/v1/users//orders/sale",
+			"/api/v1/files/report",
+			"/api/v1/unknown",
+		} {
+			_, ok := routes.Match([]byte(path))
+			assert.False(t, ok, path)
+		}
+	})
+
+	t.Run("Swagger 2", func(t *testing.T) {
+		routes, err := ParseSpec([]byte(swaggerSpec))
+		require.NoError(t, err)
+		assert.Equal(t, 2, routes.Len())
+
+		template, ok := routes.Match([]byte("/v2/pets/findByStatus"))
+		require.True(t, ok)
+		assert.Equal(t, "/v2/pets/findByStatus", string(template))
+
+		template, ok = routes.Match([]byte("/v2/pets/rex"))
+		require.True(t, ok)
+		assert.Equal(t, "/v2/pets/{petId}", string(template))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for name, spec := range map[string]string{
+			"not a spec":       "paths: {/users: {}}",
+			"no paths":         "openapi: 3.0.0",
+			"relative path":    "openapi: 3.0.0\npaths: {users: {}}",
+			"unclosed segment": "openapi: 3.0.0\npaths: {\"/files/{name.json\": {}}",
+			"malformed":        "{",
+		} {
+			_, err := ParseSpec([]byte(spec))
+			assert.Error(t, err, name)
+		}
+	})
+}
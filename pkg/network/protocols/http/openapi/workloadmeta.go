@@ -0,0 +1,175 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package openapi
+
+import (
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+	"sync"
+
+	workloadmeta "github.com/DataDog/datadog-agent/comp/core/workloadmeta/def"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	subscriberName = "usm-openapi-specs"
+
+	// podSpecAnnotation holds the specification of the services of a pod, in JSON or YAML
+	podSpecAnnotation = "ad.datadoghq.com/openapi.spec"
+	// podPortsAnnotation holds the comma-separated ports the specification applies to, all of them by default
+	podPortsAnnotation = "ad.datadoghq.com/openapi.ports"
+	podServiceLabel    = "tags.datadoghq.com/service"
+
+	containerSpecLabel    = "com.datadoghq.ad.openapi.spec"
+	containerPortsLabel   = "com.datadoghq.ad.openapi.ports"
+	containerServiceLabel = "com.datadoghq.tags.service"
+)
+
+// WorkloadWatcher keeps the services of the registry in sync with the specifications given by the
+// annotations of the pods and the labels of the containers. The services are identified by the
+// addresses of the workloads.
+type WorkloadWatcher struct {
+	wmeta    workloadmeta.Component
+	registry *Registry
+
+	exit chan struct{}
+	wg   sync.WaitGroup
+	once sync.Once
+}
+
+// NewWorkloadWatcher returns a WorkloadWatcher updating the registry given
+func NewWorkloadWatcher(wmeta workloadmeta.Component, registry *Registry) *WorkloadWatcher {
+	return &WorkloadWatcher{
+		wmeta:    wmeta,
+		registry: registry,
+		exit:     make(chan struct{}),
+	}
+}
+
+// Start watches the workloads
+func (w *WorkloadWatcher) Start() {
+	filter := workloadmeta.NewFilterBuilder().
+		AddKind(workloadmeta.KindKubernetesPod).
+		AddKind(workloadmeta.KindContainer).
+		Build()
+	ch := w.wmeta.Subscribe(subscriberName, workloadmeta.NormalPriority, filter)
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		defer w.wmeta.Unsubscribe(ch)
+		for {
+			select {
+			case bundle, ok := <-ch:
+				if !ok {
+					return
+				}
+				bundle.Acknowledge()
+				for _, event := range bundle.Events {
+					w.handleEvent(event)
+				}
+			case <-w.exit:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops watching the workloads
+func (w *WorkloadWatcher) Stop() {
+	w.once.Do(func() {
+		close(w.exit)
+		w.wg.Wait()
+	})
+}
+
+func (w *WorkloadWatcher) handleEvent(event workloadmeta.Event) {
+	id := event.Entity.GetID()
+	source := fmt.Sprintf("%s/%s", id.Kind, id.ID)
+	if event.Type == workloadmeta.EventTypeUnset {
+		w.registry.Delete(source)
+		return
+	}
+
+	service, err := workloadService(event.Entity)
+	if err != nil {
+		log.Warnf("could not load the OpenAPI specification of %s: %s", source, err)
+		w.registry.Delete(source)
+		return
+	}
+	if service == nil {
+		w.registry.Delete(source)
+		return
+	}
+	w.registry.Set(source, service)
+}
+
+// workloadService returns the service of a pod or a container, or nil when it has no specification
+func workloadService(entity workloadmeta.Entity) (*Service, error) {
+	var spec, ports, name string
+	var addresses []netip.Addr
+	switch e := entity.(type) {
+	case *workloadmeta.KubernetesPod:
+		spec, ports = e.Annotations[podSpecAnnotation], e.Annotations[podPortsAnnotation]
+		name = e.Labels[podServiceLabel]
+		if name == "" {
+			name = e.Name
+		}
+		if addr, err := netip.ParseAddr(e.IP); err == nil {
+			addresses = append(addresses, addr)
+		}
+	case *workloadmeta.Container:
+		spec, ports = e.Labels[containerSpecLabel], e.Labels[containerPortsLabel]
+		name = e.Labels[containerServiceLabel]
+		if name == "" {
+			name = e.Name
+		}
+		for _, ip := range e.NetworkIPs {
+			if addr, err := netip.ParseAddr(ip); err == nil {
+				addresses = append(addresses, addr)
+			}
+		}
+	default:
+		return nil, nil
+	}
+
+	if spec == "" {
+		return nil, nil
+	}
+	if len(addresses) == 0 {
+		// the workload can't be told apart from the others until it has an address
+		return nil, nil
+	}
+
+	portList, err := parsePorts(ports)
+	if err != nil {
+		return nil, err
+	}
+	routes, err := ParseSpec([]byte(spec))
+	if err != nil {
+		return nil, err
+	}
+	return NewService(name, routes, addresses, portList), nil
+}
+
+// parsePorts parses a comma-separated list of ports
+func parsePorts(s string) ([]uint16, error) {
+	var ports []uint16
+	for _, p := range strings.Split(s, ",") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		port, err := strconv.ParseUint(p, 10, 16)
+		if err != nil {
+			return nil, fmt.Errorf("invalid port %q", p)
+		}
+		ports = append(ports, uint16(port))
+	}
+	return ports, nil
+}
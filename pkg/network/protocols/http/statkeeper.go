@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/DataDog/datadog-agent/pkg/network/config"
+	"github.com/DataDog/datadog-agent/pkg/network/protocols/http/openapi"
 	"github.com/DataDog/datadog-agent/pkg/network/usm/utils"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 )
@@ -24,6 +25,7 @@ type StatKeeper struct {
 	incomplete           IncompleteBuffer
 	maxEntries           int
 	quantizer            *URLQuantizer
+	routes               *openapi.Registry
 	telemetry            *Telemetry
 	connectionAggregator *utils.ConnectionAggregator
 
@@ -54,6 +56,7 @@ func NewStatkeeper(c *config.Config, telemetry *Telemetry, incompleteBuffer Inco
 		incomplete:           incompleteBuffer,
 		maxEntries:           c.MaxHTTPStatsBuffered,
 		quantizer:            quantizer,
+		routes:               openapi.DefaultRegistry,
 		replaceRules:         c.HTTPReplaceRules,
 		connectionAggregator: connectionAggregator,
 		buffer:               make([]byte, getPathBufferSize(c)),
@@ -116,9 +119,19 @@ func (h *StatKeeper) add(tx Transaction) {
 		return
 	}
 
-	// Quantize HTTP path
+	// Replace the HTTP path with the route template of the OpenAPI specification of the service
+	// (eg. this turns `/orders/123/view` into `/orders/{orderId}/view`)
+	templated := false
+	if fullPath {
+		var template []byte
+		if template, templated = h.routes.Match(tx.ConnTuple(), rawPath); templated {
+			rawPath = template
+		}
+	}
+
+	// Otherwise quantize HTTP path
 	// (eg. this turns /orders/123/view` into `/orders/*/view`)
-	if h.quantizer != nil {
+	if !templated && h.quantizer != nil {
 		rawPath = h.quantizer.Quantize(rawPath)
 	}
 
@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/DataDog/datadog-agent/pkg/network/config"
+	"github.com/DataDog/datadog-agent/pkg/network/protocols/http/openapi"
 	libtelemetry "github.com/DataDog/datadog-agent/pkg/network/protocols/telemetry"
 	"github.com/DataDog/datadog-agent/pkg/process/util"
 	"github.com/stretchr/testify/assert"
@@ -224,6 +225,46 @@ func TestPathProcessing(t *testing.T) {
 	})
 }
 
+func TestPathTemplating(t *testing.T) {
+	var (
+		sourceIP   = util.AddressFromString("1.1.1.1")
+		sourcePort = 1234
+		destIP     = util.AddressFromString("2.2.2.2")
+		destPort   = 8080
+		statusCode = 200
+		latency    = time.Second
+	)
+	cfg := config.New()
+	cfg.MaxHTTPStatsBuffered = 1000
+	cfg.EnableUSMQuantization = true
+	tel := NewTelemetry("http")
+	sk := NewStatkeeper(cfg, tel, NewIncompleteBuffer(cfg, tel))
+
+	routes, err := openapi.ParseSpec([]byte(`{"openapi": "3.0.0", "paths": {"/orders/{orderSlug}/view": {}}}`))
+	require.NoError(t, err)
+	sk.routes = openapi.NewRegistry()
+	sk.routes.Set("test", openapi.NewService("orders", routes, nil, []uint16{uint16(destPort)}))
+
+	transactions := []Transaction{
+		generateIPv4HTTPTransaction(sourceIP, destIP, sourcePort, destPort, "/orders/summer-sale/view", statusCode, latency),
+		generateIPv4HTTPTransaction(sourceIP, destIP, sourcePort, destPort, "/orders/winter-sale/view", statusCode, latency),
+		// the paths which don't match any route are quantized
+		generateIPv4HTTPTransaction(sourceIP, destIP, sourcePort, destPort, "/users/123/view", statusCode, latency),
+	}
+	for _, tx := range transactions {
+		sk.Process(tx)
+	}
+	stats := sk.GetAndResetAllStats()
+
+	counts := make(map[string]int)
+	for key, metrics := range stats {
+		s := metrics.Data[uint16(statusCode)]
+		require.NotNil(t, s)
+		counts[key.Path.Content.Get()] += s.Count
+	}
+	assert.Equal(t, map[string]int{"/orders/{orderSlug}/view": 2, "/users/*/view": 1}, counts)
+}
+
 func TestHTTPCorrectness(t *testing.T) {
 	t.Run("wrong path format", func(t *testing.T) {
 		cfg := config.New()
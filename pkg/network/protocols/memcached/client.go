@@ -0,0 +1,90 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+//go:build test
+
+package memcached
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// Client is a minimal client of the text protocol of memcached, used to generate traffic in tests.
+type Client struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// NewClient returns a new memcached client connected to the given server.
+func NewClient(serverAddress string, dialer *net.Dialer) (*Client, error) {
+	conn, err := dialer.Dial("tcp", serverAddress)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{
+		conn:   conn,
+		reader: bufio.NewReader(conn),
+	}, nil
+}
+
+// Set stores the value of the given key, and returns the reply of the server.
+func (c *Client) Set(key, value string) (string, error) {
+	return c.roundTrip(fmt.Sprintf("set %s 0 0 %d\r\n%s\r\n", key, len(value), value))
+}
+
+// Get returns the value of the given key, or an empty string if the key does not exist.
+func (c *Client) Get(key string) (string, error) {
+	reply, err := c.roundTrip(fmt.Sprintf("get %s\r\n", key))
+	if err != nil || !strings.HasPrefix(reply, "VALUE") {
+		return "", err
+	}
+	value, err := c.readLine()
+	if err != nil {
+		return "", err
+	}
+	// Consuming the END line terminating the reply.
+	if _, err := c.readLine(); err != nil {
+		return "", err
+	}
+	return value, nil
+}
+
+// Delete deletes the given key, and returns the reply of the server.
+func (c *Client) Delete(key string) (string, error) {
+	return c.roundTrip(fmt.Sprintf("delete %s\r\n", key))
+}
+
+// Incr increments the value of the given key, and returns the reply of the server.
+func (c *Client) Incr(key string, delta uint64) (string, error) {
+	return c.roundTrip(fmt.Sprintf("incr %s %d\r\n", key, delta))
+}
+
+// Raw sends the given command as is, and returns the first line of the reply of the server.
+func (c *Client) Raw(command string) (string, error) {
+	return c.roundTrip(command)
+}
+
+// Close closes the connection to the server.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+func (c *Client) roundTrip(command string) (string, error) {
+	if _, err := c.conn.Write([]byte(command)); err != nil {
+		return "", err
+	}
+	return c.readLine()
+}
+
+func (c *Client) readLine() (string, error) {
+	line, err := c.reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
@@ -0,0 +1,168 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package memcached
+
+// Command represents a memcached command supported by our decoder. The commands of the text and the binary protocols
+// are merged, eg. the quiet variants of the binary commands are reported as the commands themselves.
+type Command uint8
+
+const (
+	// UnknownCommand represents an unknown command.
+	UnknownCommand Command = iota
+	// GetCommand represents the get command.
+	GetCommand
+	// GetsCommand represents the gets command, which also returns the CAS value of the items.
+	GetsCommand
+	// GetAndTouchCommand represents the gat and gats commands.
+	GetAndTouchCommand
+	// SetCommand represents the set command.
+	SetCommand
+	// AddCommand represents the add command.
+	AddCommand
+	// ReplaceCommand represents the replace command.
+	ReplaceCommand
+	// AppendCommand represents the append command.
+	AppendCommand
+	// PrependCommand represents the prepend command.
+	PrependCommand
+	// CASCommand represents the cas command.
+	CASCommand
+	// DeleteCommand represents the delete command.
+	DeleteCommand
+	// IncrCommand represents the incr command.
+	IncrCommand
+	// DecrCommand represents the decr command.
+	DecrCommand
+	// TouchCommand represents the touch command.
+	TouchCommand
+	// VersionCommand represents the version command.
+	VersionCommand
+	// StatsCommand represents the stats command.
+	StatsCommand
+	// NoopCommand represents the noop command of the binary protocol.
+	NoopCommand
+	// FlushCommand represents the flush command.
+	FlushCommand
+)
+
+// String returns the string representation of the command.
+func (c Command) String() string {
+	switch c {
+	case GetCommand:
+		return "GET"
+	case GetsCommand:
+		return "GETS"
+	case GetAndTouchCommand:
+		return "GAT"
+	case SetCommand:
+		return "SET"
+	case AddCommand:
+		return "ADD"
+	case ReplaceCommand:
+		return "REPLACE"
+	case AppendCommand:
+		return "APPEND"
+	case PrependCommand:
+		return "PREPEND"
+	case CASCommand:
+		return "CAS"
+	case DeleteCommand:
+		return "DELETE"
+	case IncrCommand:
+		return "INCR"
+	case DecrCommand:
+		return "DECR"
+	case TouchCommand:
+		return "TOUCH"
+	case VersionCommand:
+		return "VERSION"
+	case StatsCommand:
+		return "STATS"
+	case NoopCommand:
+		return "NOOP"
+	case FlushCommand:
+		return "FLUSH"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// commandFromText returns the Command from the name of a command of the text protocol.
+func commandFromText(name string) Command {
+	switch name {
+	case "get":
+		return GetCommand
+	case "gets":
+		return GetsCommand
+	case "gat", "gats":
+		return GetAndTouchCommand
+	case "set":
+		return SetCommand
+	case "add":
+		return AddCommand
+	case "replace":
+		return ReplaceCommand
+	case "append":
+		return AppendCommand
+	case "prepend":
+		return PrependCommand
+	case "cas":
+		return CASCommand
+	case "delete":
+		return DeleteCommand
+	case "incr":
+		return IncrCommand
+	case "decr":
+		return DecrCommand
+	case "touch":
+		return TouchCommand
+	case "version":
+		return VersionCommand
+	case "stats":
+		return StatsCommand
+	default:
+		return UnknownCommand
+	}
+}
+
+// commandFromOpcode returns the Command from the opcode of a request of the binary protocol.
+// https://github.com/memcached/memcached/wiki/BinaryProtocolRevamped#command-opcodes
+func commandFromOpcode(opcode uint8) Command {
+	switch opcode {
+	case 0x00, 0x09, 0x0c, 0x0d: // Get, GetQ, GetK, GetKQ
+		return GetCommand
+	case 0x01, 0x11: // Set, SetQ
+		return SetCommand
+	case 0x02, 0x12: // Add, AddQ
+		return AddCommand
+	case 0x03, 0x13: // Replace, ReplaceQ
+		return ReplaceCommand
+	case 0x04, 0x14: // Delete, DeleteQ
+		return DeleteCommand
+	case 0x05, 0x15: // Increment, IncrementQ
+		return IncrCommand
+	case 0x06, 0x16: // Decrement, DecrementQ
+		return DecrCommand
+	case 0x08, 0x18: // Flush, FlushQ
+		return FlushCommand
+	case 0x0a: // No-op
+		return NoopCommand
+	case 0x0b: // Version
+		return VersionCommand
+	case 0x0e, 0x19: // Append, AppendQ
+		return AppendCommand
+	case 0x0f, 0x1a: // Prepend, PrependQ
+		return PrependCommand
+	case 0x10: // Stat
+		return StatsCommand
+	case 0x1c: // Touch
+		return TouchCommand
+	case 0x1d, 0x1e, 0x23, 0x24: // GAT, GATQ, GATK, GATKQ
+		return GetAndTouchCommand
+	default:
+		return UnknownCommand
+	}
+}
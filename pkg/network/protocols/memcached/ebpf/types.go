@@ -0,0 +1,24 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+//go:build ignore
+
+package ebpf
+
+/*
+#include "../../ebpf/c/protocols/memcached/types.h"
+#include "../../ebpf/c/protocols/classification/defs.h"
+*/
+import "C"
+
+type ConnTuple = C.conn_tuple_t
+
+type EbpfEvent C.memcached_event_t
+type EbpfTx C.memcached_transaction_t
+
+const (
+	RequestFragmentSize  = C.MEMCACHED_REQUEST_FRAGMENT_SIZE
+	ResponseFragmentSize = C.MEMCACHED_RESPONSE_FRAGMENT_SIZE
+)
@@ -0,0 +1,76 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+//go:build linux_bpf
+
+package memcached
+
+import (
+	"fmt"
+
+	"github.com/DataDog/datadog-agent/pkg/network/protocols"
+	"github.com/DataDog/datadog-agent/pkg/network/protocols/memcached/ebpf"
+	"github.com/DataDog/datadog-agent/pkg/network/types"
+)
+
+// EventWrapper wraps an ebpf event and provides additional methods to extract information from it.
+// We use this wrapper to avoid recomputing the same values (command) multiple times.
+type EventWrapper struct {
+	*ebpf.EbpfEvent
+
+	commandSet bool
+	command    Command
+}
+
+// NewEventWrapper creates a new EventWrapper from an ebpf event.
+func NewEventWrapper(e *ebpf.EbpfEvent) *EventWrapper {
+	return &EventWrapper{EbpfEvent: e}
+}
+
+// ConnTuple returns the connection tuple for the transaction
+func (e *EventWrapper) ConnTuple() types.ConnectionKey {
+	return types.ConnectionKey{
+		SrcIPHigh: e.Tuple.Saddr_h,
+		SrcIPLow:  e.Tuple.Saddr_l,
+		DstIPHigh: e.Tuple.Daddr_h,
+		DstIPLow:  e.Tuple.Daddr_l,
+		SrcPort:   e.Tuple.Sport,
+		DstPort:   e.Tuple.Dport,
+	}
+}
+
+// Command returns the command of the request (GET, SET, DELETE, etc.)
+func (e *EventWrapper) Command() Command {
+	if !e.commandSet {
+		e.command = parseCommand(e.Tx.Request_fragment[:])
+		e.commandSet = true
+	}
+	return e.command
+}
+
+// IsError returns whether the response reports an error
+func (e *EventWrapper) IsError() bool {
+	return isErrorResponse(e.Tx.Response_fragment[:])
+}
+
+// RequestLatency returns the latency of the request in nanoseconds
+func (e *EventWrapper) RequestLatency() float64 {
+	if uint64(e.Tx.Request_started) == 0 || uint64(e.Tx.Response_last_seen) == 0 {
+		return 0
+	}
+	return protocols.NSTimestampToFloat(e.Tx.Response_last_seen - e.Tx.Request_started)
+}
+
+const template = `
+ebpfTx{
+	Command: %q,
+	Error: %t,
+	Latency: %f
+}`
+
+// String returns a string representation of the underlying event
+func (e *EventWrapper) String() string {
+	return fmt.Sprintf(template, e.Command(), e.IsError(), e.RequestLatency())
+}
@@ -0,0 +1,67 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package memcached
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+const (
+	binaryRequestMagic  = 0x80
+	binaryResponseMagic = 0x81
+	binaryHeaderSize    = 24
+
+	binarySetOpcode  = 0x01
+	binarySetQOpcode = 0x11
+
+	// The statuses of the binary protocol which are the regular outcomes of the commands rather than errors.
+	binaryStatusSuccess       = 0x0000
+	binaryStatusKeyNotFound   = 0x0001
+	binaryStatusKeyExists     = 0x0002
+	binaryStatusItemNotStored = 0x0005
+)
+
+var (
+	textErrorReply       = []byte("ERROR")
+	textClientErrorReply = []byte("CLIENT_ERROR")
+	textServerErrorReply = []byte("SERVER_ERROR")
+)
+
+// parseCommand returns the command of the request fragment given, of the text or the binary protocol.
+func parseCommand(request []byte) Command {
+	if len(request) >= binaryHeaderSize && request[0] == binaryRequestMagic {
+		opcode := request[1]
+		// The storage commands of the binary protocol are compare-and-swap operations when they carry a CAS value.
+		if (opcode == binarySetOpcode || opcode == binarySetQOpcode) && binary.BigEndian.Uint64(request[16:24]) != 0 {
+			return CASCommand
+		}
+		return commandFromOpcode(opcode)
+	}
+
+	end := bytes.IndexAny(request, " \r\x00")
+	if end == -1 {
+		end = len(request)
+	}
+	return commandFromText(string(request[:end]))
+}
+
+// isErrorResponse returns whether the response fragment given reports an error. Cache misses, and the storage
+// commands whose conditions are not met, are the regular outcomes of the commands and not errors.
+func isErrorResponse(response []byte) bool {
+	if len(response) >= binaryHeaderSize && response[0] == binaryResponseMagic {
+		switch binary.BigEndian.Uint16(response[6:8]) {
+		case binaryStatusSuccess, binaryStatusKeyNotFound, binaryStatusKeyExists, binaryStatusItemNotStored:
+			return false
+		default:
+			return true
+		}
+	}
+
+	return bytes.HasPrefix(response, textErrorReply) ||
+		bytes.HasPrefix(response, textClientErrorReply) ||
+		bytes.HasPrefix(response, textServerErrorReply)
+}
@@ -0,0 +1,75 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package memcached
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func binaryHeader(magic, opcode uint8, status uint16, cas uint64) []byte {
+	header := make([]byte, binaryHeaderSize)
+	header[0] = magic
+	header[1] = opcode
+	binary.BigEndian.PutUint16(header[6:8], status)
+	binary.BigEndian.PutUint64(header[16:24], cas)
+	return header
+}
+
+func TestParseCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		request  []byte
+		expected Command
+	}{
+		{name: "text get", request: []byte("get key\r\n"), expected: GetCommand},
+		{name: "text gets", request: []byte("gets key1 key2\r\n"), expected: GetsCommand},
+		{name: "text set", request: []byte("set key 0 0 5\r\nvalue\r\n"), expected: SetCommand},
+		{name: "text cas", request: []byte("cas key 0 0 5 42\r\nvalue\r\n"), expected: CASCommand},
+		{name: "text incr", request: []byte("incr counter 1\r\n"), expected: IncrCommand},
+		{name: "text version", request: []byte("version\r\n"), expected: VersionCommand},
+		{name: "text truncated", request: []byte("delete"), expected: DeleteCommand},
+		{name: "text padded fragment", request: append([]byte("stats"), make([]byte, 10)...), expected: StatsCommand},
+		{name: "text unknown", request: []byte("shutdown\r\n"), expected: UnknownCommand},
+		{name: "binary get", request: binaryHeader(binaryRequestMagic, 0x00, 0, 0), expected: GetCommand},
+		{name: "binary quiet get", request: binaryHeader(binaryRequestMagic, 0x09, 0, 0), expected: GetCommand},
+		{name: "binary set", request: binaryHeader(binaryRequestMagic, 0x01, 0, 0), expected: SetCommand},
+		{name: "binary set with cas", request: binaryHeader(binaryRequestMagic, 0x01, 0, 42), expected: CASCommand},
+		{name: "binary touch", request: binaryHeader(binaryRequestMagic, 0x1c, 0, 0), expected: TouchCommand},
+		{name: "binary unknown", request: binaryHeader(binaryRequestMagic, 0x30, 0, 0), expected: UnknownCommand},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, parseCommand(tt.request))
+		})
+	}
+}
+
+func TestIsErrorResponse(t *testing.T) {
+	tests := []struct {
+		name     string
+		response []byte
+		expected bool
+	}{
+		{name: "text value", response: []byte("VALUE key 0 5\r\nvalue\r\nEND\r\n"), expected: false},
+		{name: "text miss", response: []byte("END\r\n"), expected: false},
+		{name: "text not stored", response: []byte("NOT_STORED\r\n"), expected: false},
+		{name: "text error", response: []byte("ERROR\r\n"), expected: true},
+		{name: "text client error", response: []byte("CLIENT_ERROR bad data chunk\r\n"), expected: true},
+		{name: "text server error", response: []byte("SERVER_ERROR out of memory\r\n"), expected: true},
+		{name: "binary success", response: binaryHeader(binaryResponseMagic, 0x00, binaryStatusSuccess, 0), expected: false},
+		{name: "binary key not found", response: binaryHeader(binaryResponseMagic, 0x00, binaryStatusKeyNotFound, 0), expected: false},
+		{name: "binary value too large", response: binaryHeader(binaryResponseMagic, 0x01, 0x0003, 0), expected: true},
+		{name: "binary unknown command", response: binaryHeader(binaryResponseMagic, 0x30, 0x0081, 0), expected: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, isErrorResponse(tt.response))
+		})
+	}
+}
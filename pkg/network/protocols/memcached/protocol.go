@@ -0,0 +1,214 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+//go:build linux_bpf
+
+// Package memcached implements USM's memcached monitoring.
+package memcached
+
+import (
+	"io"
+	"unsafe"
+
+	"github.com/cilium/ebpf"
+	"github.com/davecgh/go-spew/spew"
+
+	manager "github.com/DataDog/ebpf-manager"
+
+	ddebpf "github.com/DataDog/datadog-agent/pkg/ebpf"
+	"github.com/DataDog/datadog-agent/pkg/network/config"
+	netebpf "github.com/DataDog/datadog-agent/pkg/network/ebpf"
+	"github.com/DataDog/datadog-agent/pkg/network/protocols"
+	"github.com/DataDog/datadog-agent/pkg/network/protocols/events"
+	memcachedebpf "github.com/DataDog/datadog-agent/pkg/network/protocols/memcached/ebpf"
+	"github.com/DataDog/datadog-agent/pkg/network/usm/buildmode"
+	"github.com/DataDog/datadog-agent/pkg/network/usm/utils"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	// InFlightMap is the name of the in-flight map.
+	InFlightMap            = "memcached_in_flight"
+	scratchBufferMap       = "memcached_scratch_buffer"
+	processTailCall        = "socket__memcached_process"
+	tlsProcessTailCall     = "uprobe__memcached_tls_process"
+	tlsTerminationTailCall = "uprobe__memcached_tls_termination"
+	eventStream            = "memcached"
+)
+
+// protocol holds the state of the memcached protocol monitoring.
+type protocol struct {
+	cfg            *config.Config
+	eventsConsumer *events.Consumer[memcachedebpf.EbpfEvent]
+	mapCleaner     *ddebpf.MapCleaner[netebpf.ConnTuple, memcachedebpf.EbpfTx]
+	statskeeper    *StatKeeper
+}
+
+// Spec is the protocol spec for the memcached protocol.
+var Spec = &protocols.ProtocolSpec{
+	Factory: newMemcachedProtocol,
+	Maps: []*manager.Map{
+		{
+			Name: InFlightMap,
+		},
+		{
+			Name: scratchBufferMap,
+		},
+		{
+			Name: "memcached_batch_events",
+		},
+		{
+			Name: "memcached_batch_state",
+		},
+		{
+			Name: "memcached_batches",
+		},
+	},
+	TailCalls: []manager.TailCallRoute{
+		{
+			ProgArrayName: protocols.ProtocolDispatcherProgramsMap,
+			Key:           uint32(protocols.ProgramMemcached),
+			ProbeIdentificationPair: manager.ProbeIdentificationPair{
+				EBPFFuncName: processTailCall,
+			},
+		},
+		{
+			ProgArrayName: protocols.TLSDispatcherProgramsMap,
+			Key:           uint32(protocols.ProgramMemcached),
+			ProbeIdentificationPair: manager.ProbeIdentificationPair{
+				EBPFFuncName: tlsProcessTailCall,
+			},
+		},
+		{
+			ProgArrayName: protocols.TLSDispatcherProgramsMap,
+			Key:           uint32(protocols.ProgramMemcachedTermination),
+			ProbeIdentificationPair: manager.ProbeIdentificationPair{
+				EBPFFuncName: tlsTerminationTailCall,
+			},
+		},
+	},
+}
+
+// newMemcachedProtocol is the factory for the memcached protocol object
+func newMemcachedProtocol(cfg *config.Config) (protocols.Protocol, error) {
+	if !cfg.EnableMemcachedMonitoring {
+		return nil, nil
+	}
+	log.Warnf("Memcached monitoring is a debugging feature, its stats are only exposed by the /debug/memcached_monitoring endpoint of system-probe and are not sent to Datadog")
+
+	return &protocol{
+		cfg:         cfg,
+		statskeeper: NewStatkeeper(cfg),
+	}, nil
+}
+
+// Name returns the name of the protocol.
+func (p *protocol) Name() string {
+	return "memcached"
+}
+
+// ConfigureOptions add the necessary options for the memcached monitoring to work, to be used by the manager.
+func (p *protocol) ConfigureOptions(mgr *manager.Manager, opts *manager.Options) {
+	opts.MapSpecEditors[InFlightMap] = manager.MapSpecEditor{
+		MaxEntries: p.cfg.MaxUSMConcurrentRequests,
+		EditorFlag: manager.EditMaxEntries,
+	}
+	utils.EnableOption(opts, "memcached_monitoring_enabled")
+	events.Configure(p.cfg, eventStream, mgr, opts)
+}
+
+// PreStart runs setup required before starting the protocol.
+func (p *protocol) PreStart(mgr *manager.Manager) (err error) {
+	p.eventsConsumer, err = events.NewConsumer(
+		eventStream,
+		mgr,
+		p.processMemcached,
+	)
+	if err != nil {
+		return
+	}
+
+	p.eventsConsumer.Start()
+	return
+}
+
+// PostStart starts the map cleaner.
+func (p *protocol) PostStart(mgr *manager.Manager) error {
+	// Setup map cleaner after manager start.
+	p.setupMapCleaner(mgr)
+	return nil
+}
+
+// Stop stops all resources associated with the protocol.
+func (p *protocol) Stop(*manager.Manager) {
+	// mapCleaner handles nil pointer receivers
+	p.mapCleaner.Stop()
+
+	if p.eventsConsumer != nil {
+		p.eventsConsumer.Stop()
+	}
+}
+
+// DumpMaps dumps map contents for debugging.
+func (p *protocol) DumpMaps(w io.Writer, mapName string, currentMap *ebpf.Map) {
+	if mapName == InFlightMap { // maps/memcached_in_flight (BPF_MAP_TYPE_HASH), key ConnTuple, value EbpfTx
+		var key netebpf.ConnTuple
+		var value memcachedebpf.EbpfTx
+		protocols.WriteMapDumpHeader(w, currentMap, mapName, key, value)
+		iter := currentMap.Iterate()
+		for iter.Next(unsafe.Pointer(&key), unsafe.Pointer(&value)) {
+			spew.Fdump(w, key, value)
+		}
+	}
+}
+
+// GetStats returns nil, the memcached stats are not sent to the agent.
+func (p *protocol) GetStats() *protocols.ProtocolStats {
+	return nil
+}
+
+// GetDebugStats returns a map of memcached stats, for the /debug/memcached_monitoring endpoint of system-probe.
+func (p *protocol) GetDebugStats() *protocols.ProtocolStats {
+	p.eventsConsumer.Sync()
+
+	return &protocols.ProtocolStats{
+		Type:  protocols.Memcached,
+		Stats: p.statskeeper.GetAndResetAllStats(),
+	}
+}
+
+// IsBuildModeSupported returns always true, as memcached module is supported by all modes.
+func (*protocol) IsBuildModeSupported(buildmode.Type) bool {
+	return true
+}
+
+func (p *protocol) processMemcached(events []memcachedebpf.EbpfEvent) {
+	for i := range events {
+		p.statskeeper.Process(NewEventWrapper(&events[i]))
+	}
+}
+
+func (p *protocol) setupMapCleaner(mgr *manager.Manager) {
+	memcachedInFlight, _, err := mgr.GetMap(InFlightMap)
+	if err != nil {
+		log.Errorf("error getting %s map: %s", InFlightMap, err)
+		return
+	}
+
+	mapCleaner, err := ddebpf.NewMapCleaner[netebpf.ConnTuple, memcachedebpf.EbpfTx](memcachedInFlight, 1024, InFlightMap, "usm_monitor")
+	if err != nil {
+		log.Errorf("error creating map cleaner: %s", err)
+		return
+	}
+
+	// Clean up idle connections. We currently use the same TTL as HTTP, but we plan to rename this variable to be more generic.
+	ttl := p.cfg.HTTPIdleConnectionTTL.Nanoseconds()
+	mapCleaner.Clean(p.cfg.HTTPMapCleanerInterval, nil, nil, func(now int64, _ netebpf.ConnTuple, val memcachedebpf.EbpfTx) bool {
+		started := int64(val.Request_started)
+		return started > 0 && (now-started) > ttl
+	})
+
+	p.mapCleaner = mapCleaner
+}
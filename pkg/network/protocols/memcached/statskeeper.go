@@ -0,0 +1,88 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+//go:build linux_bpf
+
+package memcached
+
+import (
+	"sync"
+
+	"github.com/DataDog/datadog-agent/pkg/network/config"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// StatKeeper is a struct to hold the records for the memcached protocol
+type StatKeeper struct {
+	stats      map[Key]*RequestStat
+	statsMutex sync.RWMutex
+	maxEntries int
+}
+
+// NewStatkeeper creates a new StatKeeper
+func NewStatkeeper(c *config.Config) *StatKeeper {
+	newStatKeeper := &StatKeeper{
+		maxEntries: c.MaxMemcachedStatsBuffered,
+	}
+	newStatKeeper.resetNoLock()
+	return newStatKeeper
+}
+
+// Process processes the memcached transaction
+func (s *StatKeeper) Process(tx *EventWrapper) {
+	latency := tx.RequestLatency()
+	if latency <= 0 {
+		return
+	}
+
+	s.statsMutex.Lock()
+	defer s.statsMutex.Unlock()
+
+	key := Key{
+		Command:       tx.Command(),
+		ConnectionKey: tx.ConnTuple(),
+	}
+	requestStats, ok := s.stats[key]
+	if !ok {
+		if len(s.stats) >= s.maxEntries {
+			return
+		}
+		requestStats = new(RequestStat)
+		s.stats[key] = requestStats
+	}
+	requestStats.StaticTags = uint64(tx.Tx.Tags)
+	requestStats.Count++
+	if tx.IsError() {
+		requestStats.ErrorCount++
+	}
+	if requestStats.Count == 1 {
+		requestStats.FirstLatencySample = latency
+		return
+	}
+	if requestStats.Latencies == nil {
+		if err := requestStats.initSketch(); err != nil {
+			return
+		}
+		if err := requestStats.Latencies.Add(requestStats.FirstLatencySample); err != nil {
+			return
+		}
+	}
+	if err := requestStats.Latencies.Add(latency); err != nil {
+		log.Debugf("could not add request latency to ddsketch: %v", err)
+	}
+}
+
+// GetAndResetAllStats returns all the records and resets the statskeeper
+func (s *StatKeeper) GetAndResetAllStats() map[Key]*RequestStat {
+	s.statsMutex.Lock()
+	defer s.statsMutex.Unlock()
+	ret := s.stats // No deep copy needed since `s.stats` gets reset
+	s.resetNoLock()
+	return ret
+}
+
+func (s *StatKeeper) resetNoLock() {
+	s.stats = make(map[Key]*RequestStat)
+}
@@ -0,0 +1,68 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+//go:build linux_bpf
+
+package memcached
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/network/config"
+	"github.com/DataDog/datadog-agent/pkg/network/protocols/memcached/ebpf"
+)
+
+func newEvent(request, response string) *EventWrapper {
+	tx := ebpf.EbpfTx{
+		Request_started:    1,
+		Response_last_seen: 10,
+	}
+	copy(tx.Request_fragment[:], request)
+	copy(tx.Response_fragment[:], response)
+	return NewEventWrapper(&ebpf.EbpfEvent{Tx: tx})
+}
+
+func TestStatKeeperProcess(t *testing.T) {
+	cfg := config.New()
+	cfg.MaxMemcachedStatsBuffered = 100
+	s := NewStatkeeper(cfg)
+	for i := 0; i < 20; i++ {
+		s.Process(newEvent("get key\r\n", "END\r\n"))
+	}
+	s.Process(newEvent("get key\r\n", "SERVER_ERROR out of memory\r\n"))
+	s.Process(newEvent("set key 0 0 5\r\nvalue\r\n", "STORED\r\n"))
+
+	stats := s.GetAndResetAllStats()
+	require.Len(t, stats, 2)
+	for k, stat := range stats {
+		switch k.Command {
+		case GetCommand:
+			require.Equal(t, 21, stat.Count)
+			require.Equal(t, 1, stat.ErrorCount)
+			require.Equal(t, float64(21), stat.Latencies.GetCount())
+		case SetCommand:
+			require.Equal(t, 1, stat.Count)
+			require.Equal(t, 0, stat.ErrorCount)
+			require.Nil(t, stat.Latencies)
+			require.Equal(t, float64(9), stat.FirstLatencySample)
+		default:
+			t.Fatalf("unexpected command %s", k.Command)
+		}
+	}
+	require.Empty(t, s.GetAndResetAllStats())
+}
+
+func TestStatKeeperMaxEntries(t *testing.T) {
+	cfg := config.New()
+	cfg.MaxMemcachedStatsBuffered = 1
+	s := NewStatkeeper(cfg)
+	s.Process(newEvent("get key\r\n", "END\r\n"))
+	s.Process(newEvent("delete key\r\n", "DELETED\r\n"))
+
+	stats := s.GetAndResetAllStats()
+	require.Len(t, stats, 1)
+}
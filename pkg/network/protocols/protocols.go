@@ -65,6 +65,14 @@ type Protocol interface {
 	IsBuildModeSupported(buildmode.Type) bool
 }
 
+// DebugStatsProvider is implemented by the protocols whose stats are not sent
+// to the agent, and are only exposed by the debug endpoints of system-probe.
+type DebugStatsProvider interface {
+	// GetDebugStats returns the monitoring stats collected since the previous
+	// call.
+	GetDebugStats() *ProtocolStats
+}
+
 // ProtocolStats is a "tuple" struct that represents monitoring data from a
 // Protocol implementation. It associates a ProtocolType and stats from this
 // protocols' monitoring.
@@ -206,6 +206,8 @@ func loadTracerFromAsset(buf bytecode.AssetReader, runtimeTracer, coreTracer boo
 	var closeProtocolClassifierSocketFilterFn func()
 	classificationSupported := ClassificationSupported(config)
 	util.AddBoolConst(&mgrOpts, "protocol_classification_enabled", classificationSupported)
+	util.AddBoolConst(&mgrOpts, "memcached_classification_enabled", config.EnableMemcachedMonitoring)
+	util.AddBoolConst(&mgrOpts, "cassandra_classification_enabled", config.EnableCassandraMonitoring)
 	var tailCallsIdentifiersSet map[manager.ProbeIdentificationPair]struct{}
 
 	if classificationSupported {
@@ -97,6 +97,9 @@ type Tracer struct {
 
 	processCache *processCache
 
+	// dnsEventResolver attributes the DNS events to processes, when the DNS events are enabled
+	dnsEventResolver *network.DNSEventResolver
+
 	timeResolver *ktime.Resolver
 
 	telemetryComp telemetryComponent.Component
@@ -182,6 +185,9 @@ func newTracer(cfg *config.Config, telemetryComponent telemetryComponent.Compone
 	}
 
 	tr.reverseDNS = newReverseDNS(cfg, telemetryComponent)
+	if cfg.CollectDNSEvents {
+		tr.dnsEventResolver = network.NewDNSEventResolver(cfg.MaxDNSEventsBuffered)
+	}
 	tr.usmMonitor = newUSMMonitor(cfg, tr.ebpfTracer)
 
 	// Set up the connection_protocol map cleaner if protocol classification is enabled
@@ -335,6 +341,9 @@ func (t *Tracer) storeClosedConnection(cs *network.ConnectionStats) {
 	}
 
 	t.addProcessInfo(cs)
+	if t.dnsEventResolver != nil {
+		t.dnsEventResolver.AddClosedConnection(cs)
+	}
 
 	tracerTelemetry.closedConns.IncWithTags(cs.Type.Tags())
 
@@ -469,6 +478,28 @@ func (t *Tracer) GetActiveConnections(clientID string) (*network.Connections, er
 	return conns, nil
 }
 
+// GetDNSEvents returns the DNS lookups seen since the last call, with the processes which made them
+func (t *Tracer) GetDNSEvents() ([]dns.Event, error) {
+	events := t.reverseDNS.GetDNSEvents()
+	if len(events) == 0 || t.dnsEventResolver == nil {
+		return events, nil
+	}
+
+	buffer := network.NewConnectionBuffer(512, 512)
+	err := t.ebpfTracer.GetConnections(buffer, func(c *network.ConnectionStats) bool {
+		return c.DPort == 53
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving DNS connections: %w", err)
+	}
+	active := buffer.Connections()
+	for i := range active {
+		t.addProcessInfo(&active[i])
+	}
+	t.dnsEventResolver.Resolve(events, active)
+	return events, nil
+}
+
 // RegisterClient registers a clientID with the tracer
 func (t *Tracer) RegisterClient(clientID string) error {
 	t.state.RegisterClient(clientID)
@@ -841,6 +872,16 @@ func (t *Tracer) DebugHostConntrack(ctx context.Context) (*DebugConntrackTable,
 	}, nil
 }
 
+// DebugProtocolStats returns the stats of a protocol which are only exposed for debugging,
+// collected since the previous call.
+func (t *Tracer) DebugProtocolStats(name string) (interface{}, error) {
+	stats, ok := t.usmMonitor.GetProtocolDebugStats(name)
+	if !ok {
+		return nil, fmt.Errorf("%s stats are not available", name)
+	}
+	return stats, nil
+}
+
 // DebugDumpProcessCache dumps the process cache
 func (t *Tracer) DebugDumpProcessCache(_ context.Context) (interface{}, error) {
 	if t.processCache != nil {
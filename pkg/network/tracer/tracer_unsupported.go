@@ -16,6 +16,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/ebpf"
 	"github.com/DataDog/datadog-agent/pkg/network"
 	"github.com/DataDog/datadog-agent/pkg/network/config"
+	"github.com/DataDog/datadog-agent/pkg/network/dns"
 )
 
 // Tracer is not implemented
@@ -54,6 +55,11 @@ func (t *Tracer) DebugNetworkState(_ string) (map[string]interface{}, error) {
 	return nil, ebpf.ErrNotImplemented
 }
 
+// GetDNSEvents is not implemented on this OS for Tracer
+func (t *Tracer) GetDNSEvents() ([]dns.Event, error) {
+	return nil, ebpf.ErrNotImplemented
+}
+
 // DebugNetworkMaps is not implemented on this OS for Tracer
 func (t *Tracer) DebugNetworkMaps() (*network.Connections, error) {
 	return nil, ebpf.ErrNotImplemented
@@ -82,6 +88,11 @@ func (t *Tracer) DebugHostConntrack(_ context.Context) (*DebugConntrackTable, er
 	return nil, ebpf.ErrNotImplemented
 }
 
+// DebugProtocolStats is not implemented on this OS for Tracer
+func (t *Tracer) DebugProtocolStats(_ string) (interface{}, error) {
+	return nil, ebpf.ErrNotImplemented
+}
+
 // DebugDumpProcessCache is not implemented on this OS for Tracer
 func (t *Tracer) DebugDumpProcessCache(context.Context) (interface{}, error) {
 	return nil, ebpf.ErrNotImplemented
@@ -64,6 +64,9 @@ type Tracer struct {
 	hStopClosedLoopEvent windows.Handle
 
 	processCache *processCache
+
+	// dnsEventResolver attributes the DNS events to processes, when the DNS events are enabled
+	dnsEventResolver *network.DNSEventResolver
 }
 
 // NewTracer returns an initialized tracer struct
@@ -119,6 +122,9 @@ func NewTracer(config *config.Config, telemetry telemetry.Component) (*Tracer, e
 		destExcludes:         network.ParseConnectionFilters(config.ExcludedDestinationConnections),
 		hStopClosedLoopEvent: stopEvent,
 	}
+	if config.CollectDNSEvents {
+		tr.dnsEventResolver = network.NewDNSEventResolver(config.MaxDNSEventsBuffered)
+	}
 	if config.EnableProcessEventMonitoring {
 		if tr.processCache, err = newProcessCache(config.MaxProcessesTracked); err != nil {
 			return nil, fmt.Errorf("could not create process cache; %w", err)
@@ -159,6 +165,7 @@ func NewTracer(config *config.Config, telemetry telemetry.Component) (*Tracer, e
 
 				for i := range closedConnStats {
 					tr.addProcessInfo(&closedConnStats[i])
+					tr.addDNSConnection(&closedConnStats[i])
 					tr.state.StoreClosedConnection(&closedConnStats[i])
 				}
 
@@ -227,6 +234,7 @@ func (t *Tracer) GetActiveConnections(clientID string) (*network.Connections, er
 	}
 	for i := range closedConnStats {
 		t.addProcessInfo(&closedConnStats[i])
+		t.addDNSConnection(&closedConnStats[i])
 		t.state.StoreClosedConnection(&closedConnStats[i])
 	}
 
@@ -251,6 +259,23 @@ func (t *Tracer) GetActiveConnections(clientID string) (*network.Connections, er
 	return conns, nil
 }
 
+// GetDNSEvents returns the DNS lookups seen since the last call, with the processes which made them
+func (t *Tracer) GetDNSEvents() ([]dns.Event, error) {
+	events := t.reverseDNS.GetDNSEvents()
+	if len(events) > 0 && t.dnsEventResolver != nil {
+		// the open connections can't be read without disturbing the polling of the clients, the
+		// DNS events are attributed with the closed connections only
+		t.dnsEventResolver.Resolve(events, nil)
+	}
+	return events, nil
+}
+
+func (t *Tracer) addDNSConnection(c *network.ConnectionStats) {
+	if t.dnsEventResolver != nil {
+		t.dnsEventResolver.AddClosedConnection(c)
+	}
+}
+
 // RegisterClient registers the client
 func (t *Tracer) RegisterClient(clientID string) error {
 	t.state.RegisterClient(clientID)
@@ -317,6 +342,11 @@ func (t *Tracer) DebugHostConntrackFull(_ context.Context) (*DebugConntrackTable
 	return nil, ebpf.ErrNotImplemented
 }
 
+// DebugProtocolStats is not implemented on this OS for Tracer
+func (t *Tracer) DebugProtocolStats(_ string) (interface{}, error) {
+	return nil, ebpf.ErrNotImplemented
+}
+
 // DebugDumpProcessCache is not implemented on this OS for Tracer
 func (t *Tracer) DebugDumpProcessCache(_ context.Context) (interface{}, error) {
 	return nil, ebpf.ErrNotImplemented
@@ -29,9 +29,11 @@ import (
 	netebpf "github.com/DataDog/datadog-agent/pkg/network/ebpf"
 	"github.com/DataDog/datadog-agent/pkg/network/ebpf/probes"
 	"github.com/DataDog/datadog-agent/pkg/network/protocols"
+	"github.com/DataDog/datadog-agent/pkg/network/protocols/cassandra"
 	"github.com/DataDog/datadog-agent/pkg/network/protocols/http"
 	"github.com/DataDog/datadog-agent/pkg/network/protocols/http2"
 	"github.com/DataDog/datadog-agent/pkg/network/protocols/kafka"
+	"github.com/DataDog/datadog-agent/pkg/network/protocols/memcached"
 	"github.com/DataDog/datadog-agent/pkg/network/protocols/postgres"
 	"github.com/DataDog/datadog-agent/pkg/network/protocols/redis"
 	"github.com/DataDog/datadog-agent/pkg/network/tracer/offsetguess"
@@ -50,6 +52,8 @@ var (
 		kafka.Spec,
 		postgres.Spec,
 		redis.Spec,
+		memcached.Spec,
+		cassandra.Spec,
 		// opensslSpec is unique, as we're modifying its factory during runtime to allow getting more parameters in the
 		// factory.
 		opensslSpec,
@@ -563,6 +567,20 @@ func (e *ebpfProgram) getProtocolStats() map[protocols.ProtocolType]interface{}
 	return ret
 }
 
+// getProtocolDebugStats returns the stats of the given protocol, if it only exposes them for debugging.
+func (e *ebpfProgram) getProtocolDebugStats(name string) (interface{}, bool) {
+	for _, protocol := range e.enabledProtocols {
+		p, ok := protocol.Instance.(protocols.DebugStatsProvider)
+		if !ok || protocol.Instance.Name() != name {
+			continue
+		}
+		if ps := p.GetDebugStats(); ps != nil {
+			return ps.Stats, true
+		}
+	}
+	return nil, false
+}
+
 // executePerProtocol runs the given callback (`cb`) for every protocol in the given list (`protocolList`).
 // If the callback failed, then we call the error callback (`errorCb`). Eventually returning a list of protocols which
 // successfully executed the callback.
@@ -196,6 +196,16 @@ func (m *Monitor) GetProtocolStats() map[protocols.ProtocolType]interface{} {
 	return m.ebpfProgram.getProtocolStats()
 }
 
+// GetProtocolDebugStats returns the stats collected since the previous call for the given protocol,
+// if the protocol is enabled and its stats are only exposed for debugging.
+func (m *Monitor) GetProtocolDebugStats(name string) (interface{}, bool) {
+	if m == nil {
+		return nil, false
+	}
+
+	return m.ebpfProgram.getProtocolDebugStats(name)
+}
+
 // Stop HTTP monitoring
 func (m *Monitor) Stop() {
 	if m == nil {
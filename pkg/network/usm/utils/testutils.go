@@ -70,6 +70,8 @@ func NewUSMEmptyConfig() *config.Config {
 	cfg.EnableKafkaMonitoring = false
 	cfg.EnablePostgresMonitoring = false
 	cfg.EnableRedisMonitoring = false
+	cfg.EnableMemcachedMonitoring = false
+	cfg.EnableCassandraMonitoring = false
 	cfg.EnableNativeTLSMonitoring = false
 	cfg.EnableIstioMonitoring = false
 	cfg.EnableNodeJSMonitoring = false
@@ -10,9 +10,12 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"math"
+	"slices"
 	"sort"
 	"strconv"
+	"strings"
 	"text/template"
 	"time"
 
@@ -55,7 +58,7 @@ var (
 		"timeMilli": func(v int64) string { return time.UnixMilli(v).UTC().Format(time.RFC3339) },
 		"timeNano":  func(v int64) string { return time.Unix(0, v).UTC().Format(time.RFC3339) },
 		"time":      func(v int64) string { return time.Unix(v, 0).UTC().Format(time.RFC3339) },
-		"cpupct":    func(v float32) string { return humanize.FtoaWithDigits(math.Round(float64(v)*100)/100, 2) + "%" },
+		"cpupct":    formatCPUPercent,
 		"io": func(v float32) string {
 			if v < 0 {
 				return "-"
@@ -65,6 +68,10 @@ var (
 	}
 )
 
+func formatCPUPercent(v float32) string {
+	return humanize.FtoaWithDigits(math.Round(float64(v)*100)/100, 2) + "%"
+}
+
 // HumanFormat takes the messages produced by a check run and outputs them in a human-readable format
 func HumanFormat(check string, msgs []model.MessageBody, w io.Writer) error {
 	switch check {
@@ -145,6 +152,78 @@ func humanFormatProcess(msgs []model.MessageBody, w io.Writer) error {
 	)
 }
 
+// HumanFormatProcessTree takes the messages produced by a process check run and outputs the
+// processes as a tree, each process under its parent
+func HumanFormatProcessTree(msgs []model.MessageBody, w io.Writer) error {
+	processes := map[int32]*model.Process{}
+	for _, m := range msgs {
+		proc, ok := m.(*model.CollectorProc)
+		if !ok {
+			return ErrUnexpectedMessageType
+		}
+		for _, p := range proc.Processes {
+			processes[p.Pid] = p
+		}
+	}
+
+	children := map[int32][]int32{}
+	var roots []int32
+	for pid, p := range processes {
+		ppid := p.GetCommand().GetPpid()
+		if _, found := processes[ppid]; found && ppid != pid {
+			children[ppid] = append(children[ppid], pid)
+		} else {
+			roots = append(roots, pid)
+		}
+	}
+	for _, pids := range children {
+		slices.Sort(pids)
+	}
+	slices.Sort(roots)
+
+	fmt.Fprintf(w, "Process Tree\n============\n")
+	printed := make(map[int32]bool, len(processes))
+	for _, pid := range roots {
+		writeProcessTree(w, processes, children, printed, pid, "", "")
+	}
+	// the processes whose ancestry loops because of PID reuse have no root
+	for _, pid := range slices.Sorted(maps.Keys(processes)) {
+		if !printed[pid] {
+			writeProcessTree(w, processes, children, printed, pid, "", "")
+		}
+	}
+	return nil
+}
+
+func writeProcessTree(w io.Writer, processes map[int32]*model.Process, children map[int32][]int32, printed map[int32]bool, pid int32, prefix, childPrefix string) {
+	if printed[pid] {
+		return
+	}
+	printed[pid] = true
+
+	p := processes[pid]
+	fmt.Fprintf(w, "%s%d %s", prefix, pid, strings.Join(p.GetCommand().GetArgs(), " "))
+	if p.Cpu != nil {
+		fmt.Fprintf(w, " [CPU: %s", formatCPUPercent(p.Cpu.TotalPct))
+		if p.Memory != nil {
+			fmt.Fprintf(w, " RSS: %s", humanize.Bytes(p.Memory.Rss))
+		}
+		fmt.Fprint(w, "]")
+	}
+	if p.ContainerId != "" {
+		fmt.Fprintf(w, " (container %s)", p.ContainerId)
+	}
+	fmt.Fprintln(w)
+
+	for i, child := range children[pid] {
+		if i == len(children[pid])-1 {
+			writeProcessTree(w, processes, children, printed, child, childPrefix+"└── ", childPrefix+"    ")
+		} else {
+			writeProcessTree(w, processes, children, printed, child, childPrefix+"├── ", childPrefix+"│   ")
+		}
+	}
+}
+
 func humanFormatRealTimeProcess(msgs []model.MessageBody, w io.Writer) error {
 	var data struct {
 		ProcessStats   []*model.ProcessStat
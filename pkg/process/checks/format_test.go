@@ -171,6 +171,48 @@ Containers
 	assertEqualAnyLineBreak(t, expectHumanFormat, w.String())
 }
 
+func TestHumanFormatProcessTree(t *testing.T) {
+	process := func(pid, ppid int32, args ...string) *model.Process {
+		return &model.Process{Pid: pid, Command: &model.Command{Ppid: ppid, Args: args}}
+	}
+	nginx := process(100, 1, "nginx", "-g", "daemon off;")
+	nginx.Cpu = &model.CPUStat{TotalPct: 1.5}
+	nginx.Memory = &model.MemoryStat{Rss: 2048}
+	redis := process(201, 200, "redis-server", "*:6379")
+	redis.ContainerId = "redis"
+
+	msgs := []model.MessageBody{
+		&model.CollectorProc{
+			Processes: []*model.Process{
+				process(1, 0, "/sbin/init"),
+				process(102, 101, "sh", "-c", "healthcheck"),
+				nginx,
+				process(101, 100, "nginx: worker process"),
+				process(103, 100, "nginx: worker process"),
+			},
+		},
+		&model.CollectorProc{
+			Processes: []*model.Process{redis, process(300, 299, "sleep", "60")},
+		},
+	}
+
+	w := &strings.Builder{}
+	assert.NoError(t, HumanFormatProcessTree(msgs, w))
+	const expected = `Process Tree
+============
+1 /sbin/init
+└── 100 nginx -g daemon off; [CPU: 1.5% RSS: 2.0 kB]
+    ├── 101 nginx: worker process
+    │   └── 102 sh -c healthcheck
+    └── 103 nginx: worker process
+201 redis-server *:6379 (container redis)
+300 sleep 60
+`
+	assert.Equal(t, expected, w.String())
+
+	assert.ErrorIs(t, HumanFormatProcessTree([]model.MessageBody{&model.CollectorRealTime{}}, w), ErrUnexpectedMessageType)
+}
+
 func TestHumanFormatRealTimeProcess(t *testing.T) {
 	msgs := []model.MessageBody{
 		&model.CollectorRealTime{
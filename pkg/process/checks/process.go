@@ -22,6 +22,7 @@ import (
 	workloadmetacomp "github.com/DataDog/datadog-agent/comp/core/workloadmeta/def"
 	pkgconfigmodel "github.com/DataDog/datadog-agent/pkg/config/model"
 	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
+	"github.com/DataDog/datadog-agent/pkg/config/structure"
 	"github.com/DataDog/datadog-agent/pkg/process/metadata"
 	"github.com/DataDog/datadog-agent/pkg/process/metadata/parser"
 	"github.com/DataDog/datadog-agent/pkg/process/metadata/workloadmeta"
@@ -40,6 +41,8 @@ const (
 	configPrefix               = "process_config."
 	configCustomSensitiveWords = configPrefix + "custom_sensitive_words"
 	configScrubArgs            = configPrefix + "scrub_args"
+	configDisabledDetectors    = configPrefix + "scrub_args_disabled_detectors"
+	configSensitiveArguments   = configPrefix + "custom_sensitive_arguments"
 	configStripProcArgs        = configPrefix + "strip_proc_arguments"
 	configDisallowList         = configPrefix + "blacklist_patterns"
 	configIgnoreZombies        = configPrefix + "ignore_zombie_processes"
@@ -84,6 +87,9 @@ type ProcessCheck struct {
 	// determine if zombies process will be collected
 	ignoreZombieProcesses bool
 
+	// determine if the ancestry of the processes is added to their process context
+	lineageEnabled bool
+
 	hostInfo                   *HostInfo
 	lastCPUTime                cpu.TimesStat
 	lastProcs                  map[int32]*procutil.Process
@@ -170,6 +176,8 @@ func (p *ProcessCheck) Init(syscfg *SysProbeConfig, info *HostInfo, oneShot bool
 
 	p.ignoreZombieProcesses = p.config.GetBool(configIgnoreZombies)
 
+	p.lineageEnabled = p.config.GetBool(configProcessLineage)
+
 	p.initConnRates()
 
 	p.extractors = append(p.extractors, p.serviceExtractor)
@@ -321,6 +329,9 @@ func (p *ProcessCheck) run(groupID int32, collectRealTime bool) (RunResult, erro
 
 	connsRates := p.getLastConnRates()
 	procsByCtr := fmtProcesses(p.scrubber, p.disallowList, procs, p.lastProcs, pidToCid, cpuTimes[0], p.lastCPUTime, p.lastRun, connsRates, p.lookupIdProbe, p.ignoreZombieProcesses, p.serviceExtractor)
+	if p.lineageEnabled {
+		addProcessLineage(procsByCtr, newProcessTree(procs, pidToCid), p.serviceExtractor)
+	}
 	messages, totalProcs, totalContainers := createProcCtrMessages(p.hostInfo, procsByCtr, containers, p.maxBatchSize, p.maxBatchBytes, groupID, p.networkID, collectorProcHints)
 
 	// Store the last state for comparison on the next run.
@@ -694,6 +705,22 @@ func initScrubber(config pkgconfigmodel.Reader, scrubber *procutil.DataScrubber)
 		log.Debug("Adding custom sensitives words to Scrubber:", words)
 	}
 
+	if detectors := config.GetStringSlice(configDisabledDetectors); len(detectors) > 0 {
+		scrubber.DisableDetectors(detectors)
+		log.Debug("Disabling detectors of the Scrubber:", detectors)
+	}
+
+	// Custom arguments to scrub by position or flag
+	if config.IsSet(configSensitiveArguments) {
+		var rules []procutil.ArgumentRule
+		if err := structure.UnmarshalKey(config, configSensitiveArguments, &rules); err != nil {
+			log.Warnf("Ignoring invalid %s: %s", configSensitiveArguments, err)
+		} else {
+			scrubber.AddArgumentRules(rules)
+			log.Debug("Adding custom sensitive arguments to Scrubber:", rules)
+		}
+	}
+
 	// Strips all process arguments
 	if config.GetBool(configStripProcArgs) {
 		log.Debug("Strip all process arguments enabled")
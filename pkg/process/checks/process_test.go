@@ -335,6 +335,39 @@ func TestOnlyEnvConfigArgsScrubbingDisabled(t *testing.T) {
 	}
 }
 
+func TestConfigArgsScrubbingDetectorsAndArguments(t *testing.T) {
+	cfg := configmock.NewFromYAML(t, `
+process_config:
+  scrub_args_disabled_detectors: [url_userinfo]
+  custom_sensitive_arguments:
+    - program: htpasswd
+      position: 4
+    - flag: --vault-key
+`)
+
+	scrubber := procutil.NewDefaultDataScrubber()
+	initScrubber(cfg, scrubber)
+
+	cases := []struct {
+		cmdline       []string
+		parsedCmdline []string
+	}{
+		{
+			[]string{"htpasswd", "-b", "/etc/htpasswd", "admin", "secret"},
+			[]string{"htpasswd", "-b", "/etc/htpasswd", "admin", "********"},
+		},
+		{
+			[]string{"deploy", "--vault-key=1234", "postgres://app:secret@db/app"},
+			[]string{"deploy", "--vault-key=********", "postgres://app:secret@db/app"},
+		},
+	}
+
+	for i := range cases {
+		cases[i].cmdline, _ = scrubber.ScrubCommand(cases[i].cmdline)
+		assert.Equal(t, cases[i].parsedCmdline, cases[i].cmdline)
+	}
+}
+
 func TestDisallowList(t *testing.T) {
 	testDisallowList := []string{
 		"^getty",
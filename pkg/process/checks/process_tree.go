@@ -0,0 +1,124 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package checks
+
+import (
+	"strings"
+
+	model "github.com/DataDog/agent-payload/v5/process"
+
+	"github.com/DataDog/datadog-agent/pkg/process/metadata/parser"
+	"github.com/DataDog/datadog-agent/pkg/process/procutil"
+)
+
+const (
+	configProcessLineage = configPrefix + "process_collection.lineage.enabled"
+
+	ancestorsContext     = "ancestors:"
+	rootProcessContext   = "root_process_context:"
+	serviceContextPrefix = "process_context:"
+	maxProcessTreeDepth  = 64
+)
+
+// processTree links the processes collected by a run to their parents
+type processTree struct {
+	procs    map[int32]*procutil.Process
+	pidToCid map[int]string
+}
+
+func newProcessTree(procs map[int32]*procutil.Process, pidToCid map[int]string) *processTree {
+	return &processTree{
+		procs:    procs,
+		pidToCid: pidToCid,
+	}
+}
+
+// ancestors returns the PIDs of the ancestors of a process, from its parent to the root of
+// its tree. The root is the top-most ancestor below the init process, the ancestry stops at
+// the boundaries of the containers so the root of a containerized process is in its container.
+func (t *processTree) ancestors(pid int32) []int32 {
+	proc, found := t.procs[pid]
+	if !found {
+		return nil
+	}
+	containerID := t.pidToCid[int(pid)]
+
+	var ancestors []int32
+	// the depth is bounded since PID reuse could make a loop
+	for len(ancestors) < maxProcessTreeDepth {
+		parent, found := t.procs[proc.Ppid]
+		if !found || isInitProcess(parent) || parent.Pid == pid || t.pidToCid[int(parent.Pid)] != containerID {
+			break
+		}
+		ancestors = append(ancestors, parent.Pid)
+		proc = parent
+	}
+	return ancestors
+}
+
+func isInitProcess(proc *procutil.Process) bool {
+	return proc.Pid == 1 || proc.Ppid == 0
+}
+
+// rootService returns the service of the root of a tree: its process_context, or its command
+// name when the service of the process wasn't inferred
+func (t *processTree) rootService(root int32, serviceExtractor *parser.ServiceExtractor) string {
+	for _, serviceContext := range serviceExtractor.GetServiceContext(root) {
+		if service, found := strings.CutPrefix(serviceContext, serviceContextPrefix); found {
+			return service
+		}
+	}
+	return t.command(root)
+}
+
+// command returns the command name of a process
+func (t *processTree) command(pid int32) string {
+	if proc, found := t.procs[pid]; found {
+		if proc.Comm != "" {
+			return proc.Comm
+		}
+		return proc.Name
+	}
+	return ""
+}
+
+// ancestorChain returns the command names of the ancestors of a process, from the root of its
+// tree to its parent, separated by slashes
+func (t *processTree) ancestorChain(ancestors []int32) string {
+	commands := make([]string, 0, len(ancestors))
+	for i := len(ancestors) - 1; i >= 0; i-- {
+		commands = append(commands, t.command(ancestors[i]))
+	}
+	return strings.Join(commands, "/")
+}
+
+// addProcessLineage adds the ancestry of the processes to their process context: the command
+// names of their ancestors, and the service of the root of their tree. This attaches the
+// short-lived children of a service, like `sh -c` wrappers, to the service which spawned them.
+// The PIDs of the ancestors are left out of the context, which is used like tags.
+func addProcessLineage(procsByCtr map[string][]*model.Process, tree *processTree, serviceExtractor *parser.ServiceExtractor) {
+	rootServices := make(map[int32]string)
+	for _, procs := range procsByCtr {
+		for _, proc := range procs {
+			ancestors := tree.ancestors(proc.Pid)
+			if len(ancestors) == 0 {
+				continue
+			}
+
+			proc.ProcessContext = append(proc.ProcessContext, ancestorsContext+tree.ancestorChain(ancestors))
+
+			root := ancestors[len(ancestors)-1]
+			service, found := rootServices[root]
+			if !found {
+				service = tree.rootService(root, serviceExtractor)
+				rootServices[root] = service
+			}
+			if service != "" {
+				proc.ProcessContext = append(proc.ProcessContext, rootProcessContext+service)
+			}
+		}
+	}
+}
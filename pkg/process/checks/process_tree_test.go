@@ -0,0 +1,90 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package checks
+
+import (
+	"testing"
+
+	model "github.com/DataDog/agent-payload/v5/process"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DataDog/datadog-agent/pkg/process/metadata/parser"
+	"github.com/DataDog/datadog-agent/pkg/process/procutil"
+)
+
+func makeChildProcess(pid, ppid int32, comm, cmdline string) *procutil.Process {
+	p := makeProcess(pid, cmdline)
+	p.Ppid = ppid
+	p.Comm = comm
+	return p
+}
+
+func makeTestProcessTree() map[int32]*procutil.Process {
+	return map[int32]*procutil.Process{
+		1:   makeChildProcess(1, 0, "systemd", "/sbin/init"),
+		100: makeChildProcess(100, 1, "nginx", "/usr/sbin/nginx -g daemon off;"),
+		101: makeChildProcess(101, 100, "nginx", "nginx: worker process"),
+		102: makeChildProcess(102, 101, "sh", "sh -c /usr/local/bin/healthcheck"),
+		200: makeChildProcess(200, 1, "containerd-shim", "/usr/bin/containerd-shim-runc-v2 -namespace moby"),
+		201: makeChildProcess(201, 200, "redis-server", "redis-server *:6379"),
+		202: makeChildProcess(202, 201, "sh", "sh -c redis-cli ping"),
+		// orphan whose parent exited
+		300: makeChildProcess(300, 299, "sleep", "sleep 60"),
+	}
+}
+
+func TestProcessTreeAncestors(t *testing.T) {
+	tree := newProcessTree(makeTestProcessTree(), map[int]string{201: "redis", 202: "redis"})
+
+	assert.Nil(t, tree.ancestors(1))
+	assert.Nil(t, tree.ancestors(100))
+	assert.Equal(t, []int32{100}, tree.ancestors(101))
+	assert.Equal(t, []int32{101, 100}, tree.ancestors(102))
+	// the ancestry stops at the container boundary
+	assert.Nil(t, tree.ancestors(201))
+	assert.Equal(t, []int32{201}, tree.ancestors(202))
+	assert.Nil(t, tree.ancestors(300))
+	assert.Nil(t, tree.ancestors(999))
+}
+
+func TestProcessTreeAncestorsLoop(t *testing.T) {
+	// PID reuse can make a process the parent of its ancestor
+	tree := newProcessTree(map[int32]*procutil.Process{
+		10: makeChildProcess(10, 11, "a", "a"),
+		11: makeChildProcess(11, 10, "b", "b"),
+	}, nil)
+
+	assert.Equal(t, []int32{11}, tree.ancestors(10))
+}
+
+func TestAddProcessLineage(t *testing.T) {
+	procs := makeTestProcessTree()
+	serviceExtractor := parser.NewServiceExtractor(true, false, false)
+	serviceExtractor.Extract(procs)
+	tree := newProcessTree(procs, map[int]string{201: "redis", 202: "redis"})
+
+	procsByCtr := map[string][]*model.Process{
+		"":      {{Pid: 1}, {Pid: 100}, {Pid: 101}, {Pid: 102, ProcessContext: []string{"process_context:sh"}}, {Pid: 300}},
+		"redis": {{Pid: 201}, {Pid: 202}},
+	}
+	addProcessLineage(procsByCtr, tree, serviceExtractor)
+
+	assert.Empty(t, procsByCtr[""][0].ProcessContext)
+	assert.Empty(t, procsByCtr[""][1].ProcessContext)
+	assert.Equal(t, []string{"ancestors:nginx", "root_process_context:nginx"}, procsByCtr[""][2].ProcessContext)
+	assert.Equal(t, []string{"process_context:sh", "ancestors:nginx/nginx", "root_process_context:nginx"}, procsByCtr[""][3].ProcessContext)
+	assert.Empty(t, procsByCtr[""][4].ProcessContext)
+	assert.Empty(t, procsByCtr["redis"][0].ProcessContext)
+	assert.Equal(t, []string{"ancestors:redis-server", "root_process_context:redis-server"}, procsByCtr["redis"][1].ProcessContext)
+}
+
+func TestRootServiceWithoutServiceContext(t *testing.T) {
+	tree := newProcessTree(makeTestProcessTree(), nil)
+
+	// the command name is used when the service wasn't inferred
+	assert.Equal(t, "nginx", tree.rootService(100, parser.NewServiceExtractor(false, false, false)))
+	assert.Equal(t, "", tree.rootService(999, parser.NewServiceExtractor(false, false, false)))
+}
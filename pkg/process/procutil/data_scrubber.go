@@ -9,15 +9,26 @@ package procutil
 import (
 	"bytes"
 	"regexp"
+	"slices"
 	"strings"
 
+	"github.com/DataDog/datadog-agent/pkg/telemetry"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
+	"github.com/DataDog/datadog-agent/pkg/util/scrubber"
 )
 
 const (
 	defaultCacheMaxCycles = 25
 )
 
+var scrubberTelemetry = struct {
+	cmdlines telemetry.Counter
+	scrubs   telemetry.Counter
+}{
+	telemetry.NewCounter("process_scrubber", "cmdlines", nil, "Count of command lines checked by the data scrubber"),
+	telemetry.NewCounter("process_scrubber", "scrubs", []string{"rule"}, "Count of command lines scrubbed, by detector or rule"),
+}
+
 type processCacheKey struct {
 	pid        int32
 	createTime int64
@@ -30,11 +41,14 @@ type DataScrubberPattern struct {
 }
 
 // DataScrubber allows the agent to disallow-list cmdline arguments that match
-// a list of predefined and custom words
+// a list of predefined and custom words, the secrets found by the detectors,
+// like credentials in URLs or JWTs, and the arguments matching the argument rules
 type DataScrubber struct {
 	Enabled           bool
 	StripAllArguments bool
 	SensitivePatterns []DataScrubberPattern
+	Detectors         []scrubber.Detector
+	ArgumentRules     []ArgumentRule
 	seenProcess       map[processCacheKey]struct{}
 	scrubbedCmdlines  map[processCacheKey][]string
 	cacheCycles       uint32 // used to control the cache age
@@ -48,6 +62,8 @@ func NewDefaultDataScrubber() *DataScrubber {
 	newDataScrubber := &DataScrubber{
 		Enabled:           true,
 		SensitivePatterns: patterns,
+		Detectors:         scrubber.Detectors(),
+		ArgumentRules:     slices.Clone(defaultArgumentRules),
 		seenProcess:       make(map[processCacheKey]struct{}),
 		scrubbedCmdlines:  make(map[processCacheKey][]string),
 		cacheCycles:       0,
@@ -161,11 +177,12 @@ func (ds *DataScrubber) IncrementCacheAge() {
 	}
 }
 
-// ScrubCommand hides the argument value for any key which matches a "sensitive word" pattern.
+// ScrubCommand hides the argument value for any key which matches a "sensitive word" pattern,
+// the argument matching an argument rule, and the secrets found by the detectors.
 // It returns the updated cmdline, as well as a boolean representing whether it was scrubbed
 func (ds *DataScrubber) ScrubCommand(cmdline []string) ([]string, bool) {
-	newCmdline := cmdline
-	rawCmdline := strings.Join(cmdline, " ")
+	newCmdline, matched := ds.scrubArguments(cmdline)
+	rawCmdline := strings.Join(newCmdline, " ")
 	lowerCaseCmdline := strings.ToLower(rawCmdline)
 	changed := false
 	for _, pattern := range ds.SensitivePatterns {
@@ -182,8 +199,14 @@ func (ds *DataScrubber) ScrubCommand(cmdline []string) ([]string, bool) {
 
 	if changed {
 		newCmdline = strings.Split(rawCmdline, " ")
+		matched = append(matched, sensitiveWordTag)
+	}
+
+	scrubberTelemetry.cmdlines.Inc()
+	for _, rule := range matched {
+		scrubberTelemetry.scrubs.Inc(rule)
 	}
-	return newCmdline, changed
+	return newCmdline, len(matched) > 0
 }
 
 // AddCustomSensitiveWords adds custom sensitive words on the DataScrubber object
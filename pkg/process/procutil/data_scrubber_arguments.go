@@ -0,0 +1,159 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package procutil
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+	"github.com/DataDog/datadog-agent/pkg/util/scrubber"
+)
+
+const scrubbedValue = "********"
+
+// ArgumentRule scrubs an argument of a program given its position, or the flag preceding it
+type ArgumentRule struct {
+	// Program is the name of the executable, without its path nor its `.exe` extension. The rule
+	// applies to every program when it's empty.
+	Program string `mapstructure:"program"`
+	// Flag is the argument preceding the scrubbed one. The value attached to the flag with `=`, or
+	// directly after it for single letter flags like `-pSECRET`, is scrubbed too.
+	Flag string `mapstructure:"flag"`
+	// Position is the index of the scrubbed argument when Flag is empty, the executable being at 0
+	Position int `mapstructure:"position"`
+}
+
+// defaultArgumentRules holds the flags of common programs taking credentials as their value
+var defaultArgumentRules = []ArgumentRule{
+	{Program: "curl", Flag: "-u"},
+	{Program: "curl", Flag: "--user"},
+	{Program: "curl", Flag: "-U"},
+	{Program: "curl", Flag: "--proxy-user"},
+	{Program: "wget", Flag: "--http-password"},
+	{Program: "sshpass", Flag: "-p"},
+	{Program: "redis-cli", Flag: "-a"},
+	{Program: "mongosh", Flag: "-p"},
+	{Program: "mongo", Flag: "-p"},
+}
+
+// argumentRuleTag is the tag of the scrubs done by the argument rules in the telemetry
+const argumentRuleTag = "argument_rule"
+
+// sensitiveWordTag is the tag of the scrubs done by the sensitive words in the telemetry
+const sensitiveWordTag = "sensitive_word"
+
+// programName returns the name of the executable of a command line
+func programName(arg0 string) string {
+	if i := strings.LastIndexAny(arg0, `/\`); i >= 0 {
+		arg0 = arg0[i+1:]
+	}
+	return strings.TrimSuffix(strings.ToLower(arg0), ".exe")
+}
+
+// validate returns whether the rule can match arguments
+func (r ArgumentRule) validate() bool {
+	if r.Flag == "" && r.Position <= 0 {
+		log.Warnf("data scrubber: argument rule %+v skipped. It must have a flag or a position greater than 0", r)
+		return false
+	}
+	return true
+}
+
+// scrub returns the argument at index i of args scrubbed by the rule, and whether it was scrubbed
+func (r ArgumentRule) scrub(args []string, i int) (string, bool) {
+	arg := args[i]
+	if arg == scrubbedValue {
+		return arg, false
+	}
+	if r.Flag == "" {
+		return scrubbedValue, i == r.Position
+	}
+	switch {
+	case i > 0 && args[i-1] == r.Flag:
+		return scrubbedValue, true
+	case strings.HasPrefix(arg, r.Flag+"=") && len(arg) > len(r.Flag)+1:
+		return r.Flag + "=" + scrubbedValue, true
+	case len(r.Flag) == 2 && r.Flag[0] == '-' && r.Flag[1] != '-' && strings.HasPrefix(arg, r.Flag) && len(arg) > 2:
+		return r.Flag + scrubbedValue, true
+	}
+	return arg, false
+}
+
+// scrubArguments applies the argument rules and the detectors to a command line. It returns the
+// scrubbed command line, and the rules and detectors that scrubbed it.
+func (ds *DataScrubber) scrubArguments(cmdline []string) ([]string, []string) {
+	if len(cmdline) == 0 {
+		return cmdline, nil
+	}
+	var matched []string
+
+	// the command line can come as a single string, the rules apply to its words
+	args := cmdline
+	if len(cmdline) == 1 {
+		args = strings.Split(cmdline[0], " ")
+	}
+	program := programName(args[0])
+	scrubbedArgs, cloned := args, false
+	for _, rule := range ds.ArgumentRules {
+		if rule.Program != "" && rule.Program != program {
+			continue
+		}
+		for i := 1; i < len(scrubbedArgs); i++ {
+			scrubbed, changed := rule.scrub(scrubbedArgs, i)
+			if !changed {
+				continue
+			}
+			if !cloned {
+				scrubbedArgs, cloned = slices.Clone(args), true
+			}
+			scrubbedArgs[i] = scrubbed
+			matched = appendUnique(matched, argumentRuleTag)
+		}
+	}
+	if cloned {
+		cmdline = scrubbedArgs
+	}
+
+	for _, detector := range ds.Detectors {
+		for i, arg := range cmdline {
+			scrubbed, changed := detector.Scrub(arg)
+			if !changed {
+				continue
+			}
+			if !cloned {
+				cmdline, cloned = slices.Clone(cmdline), true
+			}
+			matched = appendUnique(matched, detector.Name)
+			cmdline[i] = scrubbed
+		}
+	}
+	return cmdline, matched
+}
+
+// DisableDetectors removes detectors of the scrubber by name
+func (ds *DataScrubber) DisableDetectors(names []string) {
+	ds.Detectors = slices.DeleteFunc(ds.Detectors, func(d scrubber.Detector) bool {
+		return slices.Contains(names, d.Name)
+	})
+}
+
+// AddArgumentRules adds argument rules on the DataScrubber object
+func (ds *DataScrubber) AddArgumentRules(rules []ArgumentRule) {
+	for _, rule := range rules {
+		if rule.validate() {
+			rule.Program = strings.TrimSuffix(strings.ToLower(rule.Program), ".exe")
+			ds.ArgumentRules = append(ds.ArgumentRules, rule)
+		}
+	}
+}
+
+func appendUnique(values []string, value string) []string {
+	if slices.Contains(values, value) {
+		return values
+	}
+	return append(values, value)
+}
@@ -0,0 +1,196 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package sigma
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"unicode"
+)
+
+// condOp is the operation of a node of a condition
+type condOp int
+
+const (
+	condIdent condOp = iota
+	condOf
+	condNot
+	condAnd
+	condOr
+)
+
+// condition is a node of the condition of a Sigma rule
+type condition struct {
+	op condOp
+	// name is the search identifier of condIdent, and the pattern of the search identifiers of condOf, `them` being
+	// every search identifier
+	name string
+	// all is whether the search identifiers of condOf must all match, rather than one of them
+	all      bool
+	operands []*condition
+}
+
+// conditionParser is a recursive descent parser of the conditions of the Sigma rules, the aggregations excepted
+type conditionParser struct {
+	tokens []string
+	pos    int
+}
+
+func parseCondition(s string) (*condition, error) {
+	// the aggregations follow a pipe, like `selection | count() > 10`
+	if strings.Contains(s, "|") {
+		return nil, fmt.Errorf("aggregations are not supported")
+	}
+	tokens, err := tokenizeCondition(s)
+	if err != nil {
+		return nil, err
+	}
+	p := &conditionParser{tokens: tokens}
+	c, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok != "" {
+		return nil, fmt.Errorf("unexpected %q", tok)
+	}
+	return c, nil
+}
+
+func tokenizeCondition(s string) ([]string, error) {
+	var tokens []string
+	for i := 0; i < len(s); {
+		r := rune(s[i])
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(' || r == ')':
+			tokens = append(tokens, string(r))
+			i++
+		case isIdentChar(r):
+			start := i
+			for i < len(s) && isIdentChar(rune(s[i])) {
+				i++
+			}
+			tokens = append(tokens, s[start:i])
+		default:
+			return nil, fmt.Errorf("unexpected character %q", r)
+		}
+	}
+	return tokens, nil
+}
+
+func isIdentChar(r rune) bool {
+	return r == '_' || r == '*' || r == '-' || r == '.' || r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
+
+func (p *conditionParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *conditionParser) next() string {
+	tok := p.peek()
+	if tok != "" {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *conditionParser) parseOr() (*condition, error) {
+	return p.parseBinary(condOr, "or", p.parseAnd)
+}
+
+func (p *conditionParser) parseAnd() (*condition, error) {
+	return p.parseBinary(condAnd, "and", p.parseNot)
+}
+
+func (p *conditionParser) parseBinary(op condOp, keyword string, parseOperand func() (*condition, error)) (*condition, error) {
+	operand, err := parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	operands := []*condition{operand}
+	for strings.EqualFold(p.peek(), keyword) {
+		p.next()
+		if operand, err = parseOperand(); err != nil {
+			return nil, err
+		}
+		operands = append(operands, operand)
+	}
+	if len(operands) == 1 {
+		return operands[0], nil
+	}
+	return &condition{op: op, operands: operands}, nil
+}
+
+func (p *conditionParser) parseNot() (*condition, error) {
+	if !strings.EqualFold(p.peek(), "not") {
+		return p.parsePrimary()
+	}
+	p.next()
+	operand, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	return &condition{op: condNot, operands: []*condition{operand}}, nil
+}
+
+func (p *conditionParser) parsePrimary() (*condition, error) {
+	tok := p.next()
+	switch {
+	case tok == "":
+		return nil, fmt.Errorf("unexpected end of condition")
+	case tok == "(":
+		c, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.next() != ")" {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		return c, nil
+	case tok == ")":
+		return nil, fmt.Errorf("unexpected %q", tok)
+	case tok == "1" || strings.EqualFold(tok, "all") || strings.EqualFold(tok, "any"):
+		if !strings.EqualFold(p.peek(), "of") {
+			return nil, fmt.Errorf("expected `of` after %q", tok)
+		}
+		p.next()
+		name := p.next()
+		if name == "" || name == "(" || name == ")" {
+			return nil, fmt.Errorf("expected search identifiers after `%s of`", tok)
+		}
+		return &condition{op: condOf, name: name, all: strings.EqualFold(tok, "all")}, nil
+	case strings.EqualFold(tok, "near"):
+		return nil, fmt.Errorf("`near` is not supported")
+	default:
+		for _, keyword := range []string{"and", "or", "not", "of", "them"} {
+			if strings.EqualFold(tok, keyword) {
+				return nil, fmt.Errorf("unexpected %q", tok)
+			}
+		}
+		return &condition{op: condIdent, name: tok}, nil
+	}
+}
+
+// matchSearches returns the search identifiers matched by the pattern of an `of` condition
+func matchSearches(pattern string, searches []search) []string {
+	var names []string
+	for _, s := range searches {
+		if strings.EqualFold(pattern, "them") {
+			// the identifiers starting with an underscore are excluded from `them`
+			if !strings.HasPrefix(s.name, "_") {
+				names = append(names, s.name)
+			}
+		} else if ok, _ := path.Match(pattern, s.name); ok {
+			names = append(names, s.name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,697 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package sigma
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/netip"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/DataDog/datadog-agent/pkg/security/secl/rules"
+)
+
+// precedence is the precedence of the operator of a SECL expression, to parenthesize its operands
+type precedence int
+
+const (
+	precAtom precedence = iota
+	// precCmp is the precedence of the comparisons, `!` applying to their left operand
+	precCmp
+	precAnd
+	precOr
+)
+
+var nonIDChars = regexp.MustCompile(`[^a-zA-Z0-9_]+`)
+
+// pathError is an error at a location of a Sigma rule
+type pathError struct {
+	path string
+	err  error
+}
+
+func (e *pathError) Error() string {
+	return e.path + ": " + e.err.Error()
+}
+
+// parenthesize parenthesizes an operand of an operator of another precedence, SECL evaluating `a && b || c` as
+// `a && (b || c)`
+func parenthesize(expr string, prec precedence, op precedence) string {
+	if prec == precAtom || prec == precCmp || prec == op {
+		return expr
+	}
+	return "(" + expr + ")"
+}
+
+func errorAt(path string, format string, args ...interface{}) error {
+	return &pathError{path: path, err: fmt.Errorf(format, args...)}
+}
+
+// Importer translates Sigma rules into a SECL policy
+type Importer struct {
+	policy      rules.PolicyDef
+	ruleIDs     map[string]struct{}
+	diagnostics []Diagnostic
+}
+
+// NewImporter returns a new Importer
+func NewImporter() *Importer {
+	return &Importer{
+		policy:  rules.PolicyDef{Rules: []*rules.RuleDefinition{}},
+		ruleIDs: make(map[string]struct{}),
+	}
+}
+
+// Policy returns the policy of the rules imported so far
+func (i *Importer) Policy() *rules.PolicyDef {
+	return &i.policy
+}
+
+// policyOutput is the YAML document of the generated policy. The definitions of the secl rules package are shared
+// with the policy loader, so the output is shaped here to only include the fields the importer sets.
+type policyOutput struct {
+	Macros []macroOutput `yaml:"macros,omitempty"`
+	Rules  []ruleOutput  `yaml:"rules"`
+}
+
+type macroOutput struct {
+	ID          string `yaml:"id"`
+	Expression  string `yaml:"expression"`
+	Description string `yaml:"description,omitempty"`
+}
+
+type ruleOutput struct {
+	ID          string            `yaml:"id"`
+	Expression  string            `yaml:"expression"`
+	Description string            `yaml:"description,omitempty"`
+	Tags        map[string]string `yaml:"tags,omitempty"`
+}
+
+// Marshal returns the YAML policy of the rules imported so far
+func (i *Importer) Marshal() ([]byte, error) {
+	output := policyOutput{Rules: make([]ruleOutput, 0, len(i.policy.Rules))}
+	for _, macro := range i.policy.Macros {
+		output.Macros = append(output.Macros, macroOutput{
+			ID:          macro.ID,
+			Expression:  macro.Expression,
+			Description: macro.Description,
+		})
+	}
+	for _, rule := range i.policy.Rules {
+		output.Rules = append(output.Rules, ruleOutput{
+			ID:          rule.ID,
+			Expression:  rule.Expression,
+			Description: rule.Description,
+			Tags:        rule.Tags,
+		})
+	}
+	return yaml.Marshal(output)
+}
+
+// Diagnostics returns the diagnostics of the rules imported so far
+func (i *Importer) Diagnostics() []Diagnostic {
+	return i.diagnostics
+}
+
+// Import translates the Sigma rules of a YAML document, read from the source. The rules which can't be translated are
+// skipped, and reported by the diagnostics.
+func (i *Importer) Import(source string, data []byte) {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var r rule
+		if err := decoder.Decode(&r); err != nil {
+			if !errors.Is(err, io.EOF) {
+				i.diagnostics = append(i.diagnostics, Diagnostic{Severity: SeverityError, Source: source, Message: fmt.Sprintf("invalid Sigma rule: %s", err)})
+			}
+			return
+		}
+
+		t := &translator{rule: &r, searches: make(map[string]searchExpr)}
+		ruleDef, err := t.translate()
+		if err == nil {
+			if _, exists := i.ruleIDs[ruleDef.ID]; exists {
+				err = fmt.Errorf("the rule ID %s is already used by another rule", ruleDef.ID)
+			}
+		}
+		for _, w := range t.warnings {
+			w.Source, w.Rule = source, r.Title
+			i.diagnostics = append(i.diagnostics, w)
+		}
+		if err != nil {
+			d := Diagnostic{Severity: SeverityError, Source: source, Rule: r.Title, Message: err.Error()}
+			var pathErr *pathError
+			if errors.As(err, &pathErr) {
+				d.Path, d.Message = pathErr.path, pathErr.err.Error()
+			}
+			i.diagnostics = append(i.diagnostics, d)
+			continue
+		}
+
+		i.ruleIDs[ruleDef.ID] = struct{}{}
+		i.policy.Macros = append(i.policy.Macros, t.macros...)
+		i.policy.Rules = append(i.policy.Rules, ruleDef)
+	}
+}
+
+// translator translates a Sigma rule
+type translator struct {
+	rule      *rule
+	category  *category
+	detection *detection
+	ruleID    string
+
+	macros []*rules.MacroDefinition
+	// searches are the expressions of the search identifiers translated
+	searches map[string]searchExpr
+	// usesEventFields is whether the rule uses fields specific to the event type of its category
+	usesEventFields bool
+	warnings        []Diagnostic
+}
+
+// searchExpr is the expression of a search identifier, which is either a macro ID or an inlined expression
+type searchExpr struct {
+	expr string
+	prec precedence
+}
+
+func (t *translator) warn(path string, format string, args ...interface{}) {
+	t.warnings = append(t.warnings, Diagnostic{Severity: SeverityWarning, Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func (t *translator) translate() (*rules.RuleDefinition, error) {
+	r := t.rule
+	if r.Action != "" {
+		return nil, errorAt("action", "rule collections are not supported")
+	}
+	if !strings.EqualFold(r.LogSource.Product, "linux") {
+		return nil, errorAt("logsource.product", "only the linux product is supported, not %q", r.LogSource.Product)
+	}
+	if r.LogSource.Service != "" {
+		return nil, errorAt("logsource.service", "the log services are not supported, only the process_creation, file_event and network_connection categories")
+	}
+	if t.category = categories[r.LogSource.Category]; t.category == nil {
+		return nil, errorAt("logsource.category", "the %q category is not supported, only process_creation, file_event and network_connection", r.LogSource.Category)
+	}
+
+	switch {
+	case r.ID != "":
+		t.ruleID = "sigma_" + nonIDChars.ReplaceAllString(r.ID, "_")
+	case r.Title != "":
+		t.ruleID = "sigma_" + strings.Trim(nonIDChars.ReplaceAllString(strings.ToLower(r.Title), "_"), "_")
+	default:
+		return nil, errorAt("id", "the rule has neither an id nor a title")
+	}
+
+	var err error
+	if t.detection, err = parseDetection(&r.Detection); err != nil {
+		return nil, errorAt("detection", "%s", err)
+	}
+	if t.detection.timeframe != "" {
+		return nil, errorAt("detection.timeframe", "timeframes are not supported")
+	}
+
+	// a list of conditions matches when any of them matches
+	var conditions []string
+	var prec precedence
+	for _, s := range t.detection.conditions {
+		c, err := parseCondition(s)
+		if err != nil {
+			return nil, errorAt("detection.condition", "%s: %s", s, err)
+		}
+		expr, condPrec, err := t.condition(c)
+		if err != nil {
+			return nil, err
+		}
+		if len(t.detection.conditions) > 1 {
+			expr = parenthesize(expr, condPrec, precOr)
+		}
+		prec = condPrec
+		conditions = append(conditions, expr)
+	}
+	condition := strings.Join(conditions, " || ")
+	if len(conditions) > 1 {
+		prec = precOr
+	}
+
+	var expressions []string
+	if t.category.filter != "" {
+		expressions = append(expressions, t.category.filter)
+	} else if !t.usesEventFields {
+		expressions = append(expressions, t.category.anchor)
+	}
+	if len(expressions) > 0 {
+		condition = parenthesize(condition, prec, precAnd)
+	}
+	expressions = append(expressions, condition)
+
+	ruleDef := &rules.RuleDefinition{
+		ID:          t.ruleID,
+		Expression:  strings.Join(expressions, " && "),
+		Description: r.Title,
+		Tags:        map[string]string{},
+	}
+	if r.ID != "" {
+		ruleDef.Tags["sigma_id"] = r.ID
+	}
+	if r.Level != "" {
+		ruleDef.Tags["sigma_level"] = r.Level
+	}
+	return ruleDef, nil
+}
+
+// condition translates a condition into a SECL expression, referencing the macros of the search identifiers
+func (t *translator) condition(c *condition) (string, precedence, error) {
+	switch c.op {
+	case condIdent:
+		s, err := t.search(c.name)
+		return s.expr, s.prec, err
+	case condOf:
+		names := matchSearches(c.name, t.detection.searches)
+		if len(names) == 0 {
+			return "", 0, errorAt("detection.condition", "no search identifier matches %q", c.name)
+		}
+		op, prec := " || ", precOr
+		if c.all {
+			op, prec = " && ", precAnd
+		}
+		exprs := make([]string, 0, len(names))
+		for _, name := range names {
+			s, err := t.search(name)
+			if err != nil {
+				return "", 0, err
+			}
+			if len(names) == 1 {
+				return s.expr, s.prec, nil
+			}
+			exprs = append(exprs, parenthesize(s.expr, s.prec, prec))
+		}
+		return strings.Join(exprs, op), prec, nil
+	case condNot:
+		expr, prec, err := t.condition(c.operands[0])
+		if err != nil {
+			return "", 0, err
+		}
+		if prec != precAtom {
+			expr = "(" + expr + ")"
+		}
+		return "!" + expr, precAtom, nil
+	default:
+		op, prec := " && ", precAnd
+		if c.op == condOr {
+			op, prec = " || ", precOr
+		}
+		exprs := make([]string, 0, len(c.operands))
+		for _, operand := range c.operands {
+			expr, operandPrec, err := t.condition(operand)
+			if err != nil {
+				return "", 0, err
+			}
+			exprs = append(exprs, parenthesize(expr, operandPrec, prec))
+		}
+		return strings.Join(exprs, op), prec, nil
+	}
+}
+
+// search returns the expression of a search identifier, translating it the first time. The search identifiers using
+// only the fields common to every event type are translated to macros, the other ones are inlined in the rule, as SECL
+// infers the event type of a rule from every macro of the rule set.
+func (t *translator) search(name string) (searchExpr, error) {
+	if s, ok := t.searches[name]; ok {
+		return s, nil
+	}
+	var s *search
+	for i := range t.detection.searches {
+		if t.detection.searches[i].name == name {
+			s = &t.detection.searches[i]
+		}
+	}
+	if s == nil {
+		return searchExpr{}, errorAt("detection.condition", "unknown search identifier %q", name)
+	}
+
+	usesEventFields := t.usesEventFields
+	t.usesEventFields = false
+	expr, prec, err := t.searchFields(s)
+	if err != nil {
+		return searchExpr{}, err
+	}
+	translated := searchExpr{expr: expr, prec: prec}
+	if !t.usesEventFields {
+		translated = searchExpr{expr: t.ruleID + "_" + nonIDChars.ReplaceAllString(name, "_"), prec: precAtom}
+		t.macros = append(t.macros, &rules.MacroDefinition{
+			ID:          translated.expr,
+			Expression:  expr,
+			Description: fmt.Sprintf("%s of the Sigma rule %q", name, t.rule.Title),
+		})
+	}
+	t.usesEventFields = t.usesEventFields || usesEventFields
+	t.searches[name] = translated
+	return translated, nil
+}
+
+// searchFields translates a search identifier, which is a map of fields or a list of maps of fields
+func (t *translator) searchFields(s *search) (string, precedence, error) {
+	path := "detection." + s.name
+	switch s.node.Kind {
+	case yaml.MappingNode:
+		return t.fields(path, s.node)
+	case yaml.SequenceNode:
+		var exprs []string
+		var prec precedence
+		for _, item := range s.node.Content {
+			if item.Kind != yaml.MappingNode {
+				return "", 0, errorAt(path, "keywords are not supported, the values must be matched against fields")
+			}
+			expr, itemPrec, err := t.fields(path, item)
+			if err != nil {
+				return "", 0, err
+			}
+			prec = itemPrec
+			if len(s.node.Content) > 1 {
+				expr, prec = parenthesize(expr, itemPrec, precOr), precOr
+			}
+			exprs = append(exprs, expr)
+		}
+		if len(exprs) == 0 {
+			return "", 0, errorAt(path, "the search identifier is empty")
+		}
+		return strings.Join(exprs, " || "), prec, nil
+	default:
+		return "", 0, errorAt(path, "a search identifier must be a map of fields, or a list of maps of fields")
+	}
+}
+
+// fields translates a map of fields, which must all match
+func (t *translator) fields(path string, node *yaml.Node) (string, precedence, error) {
+	var exprs []string
+	var precs []precedence
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i].Value
+		expr, fieldPrec, err := t.field(path+"."+key, key, node.Content[i+1])
+		if err != nil {
+			return "", 0, err
+		}
+		if expr == "" {
+			continue
+		}
+		exprs, precs = append(exprs, expr), append(precs, fieldPrec)
+	}
+	switch len(exprs) {
+	case 0:
+		return "", 0, errorAt(path, "the search identifier has no field translatable to SECL")
+	case 1:
+		return exprs[0], precs[0], nil
+	}
+	for i, expr := range exprs {
+		exprs[i] = parenthesize(expr, precs[i], precAnd)
+	}
+	return strings.Join(exprs, " && "), precAnd, nil
+}
+
+// modifiers are the modifiers of a field
+type modifiers struct {
+	// transform is `contains`, `startswith` or `endswith`
+	transform string
+	all       bool
+	re        bool
+	reFlags   string
+	cidr      bool
+	exists    bool
+	// cased is whether the strings are matched case-sensitively, the Sigma default being case-insensitive
+	cased bool
+	// cmp is the SECL operator of `lt`, `lte`, `gt` or `gte`
+	cmp string
+}
+
+func parseModifiers(path string, mods []string) (modifiers, error) {
+	var m modifiers
+	for _, mod := range mods {
+		switch mod {
+		case "contains", "startswith", "endswith":
+			if m.transform != "" {
+				return m, errorAt(path, "the %q and %q modifiers can't be combined", m.transform, mod)
+			}
+			m.transform = mod
+		case "all":
+			m.all = true
+		case "re":
+			m.re = true
+		case "i", "m", "s":
+			m.reFlags += mod
+		case "cidr":
+			m.cidr = true
+		case "exists":
+			m.exists = true
+		case "cased":
+			m.cased = true
+		case "lt":
+			m.cmp = "<"
+		case "lte":
+			m.cmp = "<="
+		case "gt":
+			m.cmp = ">"
+		case "gte":
+			m.cmp = ">="
+		default:
+			return m, errorAt(path, "the %q modifier is not supported", mod)
+		}
+	}
+	if m.reFlags != "" && !m.re {
+		return m, errorAt(path, "the %q modifiers only apply to regular expressions", m.reFlags)
+	}
+	if m.re && m.transform != "" {
+		return m, errorAt(path, "the %q modifier can't be combined with a regular expression", m.transform)
+	}
+	return m, nil
+}
+
+// field translates the matching of a field against its values, returning an empty expression when the values always
+// match the events of the category
+func (t *translator) field(path string, key string, node *yaml.Node) (string, precedence, error) {
+	parts := strings.Split(key, "|")
+	name := parts[0]
+
+	var values []*yaml.Node
+	switch node.Kind {
+	case yaml.ScalarNode:
+		values = []*yaml.Node{node}
+	case yaml.SequenceNode:
+		values = node.Content
+		for _, v := range values {
+			if v.Kind != yaml.ScalarNode {
+				return "", 0, errorAt(path, "the values must be scalars")
+			}
+		}
+	default:
+		return "", 0, errorAt(path, "the values must be a scalar or a list of scalars")
+	}
+	if len(values) == 0 {
+		return "", 0, errorAt(path, "the field has no value")
+	}
+
+	if expected, ok := t.category.ignoredFields[name]; ok && len(parts) == 1 {
+		for _, v := range values {
+			if !strings.EqualFold(v.Value, expected) {
+				return "", 0, errorAt(path, "only %s: %s matches the events of the %s category", name, expected, t.rule.LogSource.Category)
+			}
+		}
+		return "", precAtom, nil
+	}
+
+	f, ok := t.category.fields[name]
+	if !ok {
+		return "", 0, errorAt(path, "the %s field of the %s category has no SECL equivalent", name, t.rule.LogSource.Category)
+	}
+	m, err := parseModifiers(path, parts[1:])
+	if err != nil {
+		return "", 0, err
+	}
+	if strings.HasPrefix(f.name, t.category.eventType+".") {
+		t.usesEventFields = true
+	}
+
+	var exprs []string
+	switch f.kind {
+	case stringField, pathField:
+		exprs, err = t.stringExprs(path, f, m, values)
+	case intField:
+		exprs, err = intExprs(path, f, m, values)
+	case ipField:
+		exprs, err = ipExprs(path, f, m, values)
+	}
+	if err != nil {
+		return "", 0, err
+	}
+
+	if len(exprs) == 1 {
+		return exprs[0], precCmp, nil
+	}
+	if m.all {
+		return strings.Join(exprs, " && "), precAnd, nil
+	}
+	return strings.Join(exprs, " || "), precOr, nil
+}
+
+// stringExprs returns the expressions matching a string field against its values. They must all match with the
+// `all` modifier, and one of them must match otherwise.
+func (t *translator) stringExprs(path string, f field, m modifiers, values []*yaml.Node) ([]string, error) {
+	if m.cidr || m.cmp != "" {
+		return nil, errorAt(path, "the %s field is a string, it can't be compared to IP ranges or numbers", f.name)
+	}
+	if m.exists {
+		if len(values) != 1 || values[0].Tag != "!!bool" {
+			return nil, errorAt(path, "the exists modifier requires a boolean")
+		}
+		if values[0].Value == "true" {
+			return []string{f.name + ` != ""`}, nil
+		}
+		return []string{f.name + ` == ""`}, nil
+	}
+	if f.warning != "" && (m.re || m.transform == "" || m.transform == "startswith") {
+		t.warn(path, "%s", f.warning)
+	}
+	if m.re && f.kind == pathField {
+		return nil, errorAt(path, "regular expressions are not supported on the %s path field", f.name)
+	}
+
+	// the values of the path fields may apply to their basename field, the values of each field are kept in order
+	var exprs, names []string
+	seclValues := make(map[string][]string)
+	inexact, caseSensitive := false, false
+	for _, v := range values {
+		name, secl := f.name, []string{}
+		switch {
+		case v.Tag == "!!null":
+			secl = append(secl, `""`)
+		case m.re:
+			pattern := v.Value
+			if m.reFlags != "" {
+				pattern = "(?" + m.reFlags + ")" + pattern
+			}
+			if _, err := regexp.Compile(pattern); err != nil {
+				return nil, errorAt(path, "invalid regular expression %q: %s", v.Value, err)
+			}
+			secl = append(secl, regexpValue(pattern))
+		default:
+			segments := parseSigmaString(v.Value)
+			switch m.transform {
+			case "contains":
+				segments = append(append([]segment{{wildcard: '*'}}, segments...), segment{wildcard: '*'})
+			case "startswith":
+				segments = append(segments, segment{wildcard: '*'})
+			case "endswith":
+				segments = append([]segment{{wildcard: '*'}}, segments...)
+			}
+			if f.kind != pathField {
+				secl = append(secl, stringValue(segments, m.cased))
+				break
+			}
+			var valueInexact bool
+			var err error
+			if name, secl, valueInexact, err = pathValues(f, segments, m.cased); err != nil {
+				return nil, errorAt(path, "%s", err)
+			}
+			inexact = inexact || valueInexact
+			caseSensitive = caseSensitive || (name == f.name && !m.cased && hasCase(segments))
+		}
+		if m.all {
+			exprs = append(exprs, membership(name, secl))
+			continue
+		}
+		if _, exists := seclValues[name]; !exists {
+			names = append(names, name)
+		}
+		seclValues[name] = append(seclValues[name], secl...)
+	}
+	if inexact {
+		t.warn(path, "the wildcards of the %s path field don't match `/`, unlike the Sigma ones", f.name)
+	}
+	if caseSensitive {
+		t.warn(path, "the %s path field is matched case-sensitively, unlike the Sigma strings which aren't cased", f.name)
+	}
+
+	for _, name := range names {
+		exprs = append(exprs, membership(name, seclValues[name]))
+	}
+	return exprs, nil
+}
+
+func intExprs(path string, f field, m modifiers, values []*yaml.Node) ([]string, error) {
+	if m.transform != "" || m.re || m.cidr || m.exists {
+		return nil, errorAt(path, "the %s field is a number, only the lt, lte, gt and gte modifiers apply to it", f.name)
+	}
+	ints := make([]string, 0, len(values))
+	for _, v := range values {
+		n, err := strconv.Atoi(v.Value)
+		if err != nil {
+			return nil, errorAt(path, "the %s field is a number, not %q", f.name, v.Value)
+		}
+		ints = append(ints, strconv.Itoa(n))
+	}
+
+	if m.cmp == "" && !m.all {
+		if len(ints) == 1 {
+			return []string{f.name + " == " + ints[0]}, nil
+		}
+		return []string{f.name + " in [" + strings.Join(ints, ", ") + "]"}, nil
+	}
+	op := m.cmp
+	if op == "" {
+		op = "=="
+	}
+	exprs := make([]string, 0, len(ints))
+	for _, n := range ints {
+		exprs = append(exprs, f.name+" "+op+" "+n)
+	}
+	return exprs, nil
+}
+
+func ipExprs(path string, f field, m modifiers, values []*yaml.Node) ([]string, error) {
+	if m.transform != "" || m.re || m.cmp != "" || m.exists {
+		return nil, errorAt(path, "the %s field is an IP address, only the cidr modifier applies to it", f.name)
+	}
+	ips := make([]string, 0, len(values))
+	for _, v := range values {
+		if m.cidr {
+			prefix, err := netip.ParsePrefix(v.Value)
+			if err != nil {
+				return nil, errorAt(path, "invalid CIDR %q", v.Value)
+			}
+			ips = append(ips, prefix.Masked().String())
+			continue
+		}
+		addr, err := netip.ParseAddr(v.Value)
+		if err != nil {
+			return nil, errorAt(path, "invalid IP address %q, use the cidr modifier to match IP ranges", v.Value)
+		}
+		ips = append(ips, addr.String())
+	}
+
+	if m.all {
+		exprs := make([]string, 0, len(ips))
+		for _, ip := range ips {
+			exprs = append(exprs, ipComparison(f.name, ip))
+		}
+		return exprs, nil
+	}
+	if len(ips) == 1 {
+		return []string{ipComparison(f.name, ips[0])}, nil
+	}
+	return []string{f.name + " in [" + strings.Join(ips, ", ") + "]"}, nil
+}
+
+func ipComparison(field string, ip string) string {
+	if strings.Contains(ip, "/") {
+		return field + " in " + ip
+	}
+	return field + " == " + ip
+}
@@ -0,0 +1,576 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package sigma
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/security/secl/rules"
+)
+
+func importRule(t *testing.T, sigmaRule string) *Importer {
+	t.Helper()
+	importer := NewImporter()
+	importer.Import("test.yml", []byte(sigmaRule))
+	return importer
+}
+
+func macroExpressions(policy *rules.PolicyDef) map[string]string {
+	macros := make(map[string]string)
+	for _, macro := range policy.Macros {
+		macros[macro.ID] = macro.Expression
+	}
+	return macros
+}
+
+func TestImportProcessCreation(t *testing.T) {
+	importer := importRule(t, `
+title: Curl Piped To Shell
+id: 2f6b1c3e-0a1b-4c5d-9e8f-123456789abc
+level: high
+logsource:
+  product: linux
+  category: process_creation
+detection:
+  selection_img:
+    Image|endswith:
+      - '/curl'
+      - '/wget'
+  selection_cli:
+    CommandLine|contains|all:
+      - 'http'
+      - '| sh'
+  filter_main:
+    ParentImage|startswith|cased: '/usr/lib/'
+  condition: all of selection_* and not filter_main
+`)
+	require.Empty(t, importer.Diagnostics())
+
+	policy := importer.Policy()
+	prefix := "sigma_2f6b1c3e_0a1b_4c5d_9e8f_123456789abc"
+	// the searches using the fields of the exec events are inlined
+	assert.Equal(t, map[string]string{
+		prefix + "_filter_main": `process.parent.file.path =~ "/usr/lib/**"`,
+	}, macroExpressions(policy))
+
+	require.Len(t, policy.Rules, 1)
+	assert.Equal(t, prefix, policy.Rules[0].ID)
+	// the strings which aren't cased are matched case-insensitively
+	assert.Equal(t, `exec.file.name in [r"(?i)^curl$", r"(?i)^wget$"] && exec.args =~ r"(?i)http" && exec.args =~ r"(?i)\| sh" && !`+prefix+"_filter_main", policy.Rules[0].Expression)
+	assert.Equal(t, "Curl Piped To Shell", policy.Rules[0].Description)
+	assert.Equal(t, map[string]string{"sigma_id": "2f6b1c3e-0a1b-4c5d-9e8f-123456789abc", "sigma_level": "high"}, policy.Rules[0].Tags)
+}
+
+func TestImportFileEvent(t *testing.T) {
+	importer := importRule(t, `
+title: Cron File Created
+logsource:
+  product: linux
+  category: file_event
+detection:
+  selection:
+    TargetFilename|startswith:
+      - '/etc/cron.d/'
+      - '/var/spool/cron'
+  filter:
+    Image:
+      - /usr/bin/dpkg
+      - /usr/bin/rpm
+  condition: selection and not filter
+`)
+	// the globs of the path fields are case-sensitive
+	require.Len(t, importer.Diagnostics(), 2)
+	for _, d := range importer.Diagnostics() {
+		assert.Equal(t, SeverityWarning, d.Severity)
+		assert.Contains(t, d.Message, "path field is matched case-sensitively")
+	}
+
+	policy := importer.Policy()
+	assert.Equal(t, map[string]string{
+		"sigma_cron_file_created_filter": `process.file.path in ["/usr/bin/dpkg", "/usr/bin/rpm"]`,
+	}, macroExpressions(policy))
+	require.Len(t, policy.Rules, 1)
+	assert.Equal(t, `open.flags & O_CREAT > 0 && open.file.path in [~"/etc/cron.d/**", ~"/var/spool/cron*", ~"/var/spool/cron*/**"] && !sigma_cron_file_created_filter`, policy.Rules[0].Expression)
+}
+
+func TestImportNetworkConnection(t *testing.T) {
+	importer := importRule(t, `
+title: Mining Pool Connection
+id: 11111111-2222-3333-4444-555555555555
+logsource:
+  product: linux
+  category: network_connection
+detection:
+  selection:
+    Initiated: 'true'
+    DestinationPort:
+      - 3333
+      - 4444
+  filter:
+    DestinationIp|cidr:
+      - '10.0.0.0/8'
+      - '192.168.1.1/16'
+  miner:
+    Image|endswith: /xmrig
+  condition: selection and not filter or miner
+`)
+	require.Empty(t, importer.Diagnostics())
+
+	policy := importer.Policy()
+	prefix := "sigma_11111111_2222_3333_4444_555555555555"
+	assert.Equal(t, map[string]string{
+		prefix + "_miner": `process.file.name =~ r"(?i)^xmrig$"`,
+	}, macroExpressions(policy))
+	require.Len(t, policy.Rules, 1)
+	// SECL has no operator precedence, and `!` applies to the left operand of the comparisons
+	assert.Equal(t, "(connect.addr.port in [3333, 4444] && !(connect.addr.ip in [10.0.0.0/8, 192.168.0.0/16])) || "+prefix+"_miner", policy.Rules[0].Expression)
+}
+
+func TestImportAnchor(t *testing.T) {
+	importer := importRule(t, `
+title: Process Of Nobody
+logsource:
+  product: linux
+  category: network_connection
+detection:
+  selection:
+    User: nobody
+  other:
+    Image|startswith|cased: /tmp/
+  condition: 1 of them
+`)
+	require.Empty(t, importer.Diagnostics())
+
+	policy := importer.Policy()
+	require.Len(t, policy.Rules, 1)
+	assert.Equal(t, "connect.addr.family in [AF_INET, AF_INET6] && (sigma_process_of_nobody_selection || sigma_process_of_nobody_other)", policy.Rules[0].Expression)
+}
+
+func TestImportPathValues(t *testing.T) {
+	tests := []struct {
+		value         string
+		expected      string
+		inexact       bool
+		caseSensitive bool
+		err           string
+	}{
+		{value: "Image: /bin/sh", expected: `exec.file.path == "/bin/sh"`, caseSensitive: true},
+		{value: "Image|cased: /bin/sh", expected: `exec.file.path == "/bin/sh"`},
+		{value: "Image|endswith: /sh", expected: `exec.file.name =~ r"(?i)^sh$"`},
+		{value: "Image|endswith|cased: /sh", expected: `exec.file.name == "sh"`},
+		{value: "Image|endswith: .sh", expected: `exec.file.name =~ r"(?i)\.sh$"`},
+		{value: "Image|endswith|cased: .sh", expected: `exec.file.name =~ "*.sh"`},
+		{value: "Image|contains: python", expected: `exec.file.name =~ r"(?i)python"`, inexact: true},
+		{value: "Image|startswith: /opt/", expected: `exec.file.path =~ "/opt/**"`, caseSensitive: true},
+		{value: "Image|startswith|cased: /opt", expected: `exec.file.path in [~"/opt*", ~"/opt*/**"]`},
+		{value: "Image|contains: /tmp/", expected: `exec.file.path in [~"*/tmp/**", ~"/tmp/**"]`, inexact: true, caseSensitive: true},
+		{value: "Image|cased: /usr/*/python3", expected: `exec.file.path =~ "/usr/*/python3"`, inexact: true},
+		{value: "Image: /usr/bin/python?", err: "the `?` wildcard is not supported on the path fields"},
+		{value: "Image: sh", err: `"sh" is not an absolute path`},
+		{value: "Image|re: ^/tmp/", err: "regular expressions are not supported on the exec.file.path path field"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			importer := importRule(t, `
+title: Test
+logsource:
+  product: linux
+  category: process_creation
+detection:
+  selection:
+    `+test.value+`
+  condition: selection
+`)
+			if test.err != "" {
+				require.Len(t, importer.Diagnostics(), 1)
+				assert.Equal(t, SeverityError, importer.Diagnostics()[0].Severity)
+				assert.Equal(t, test.err, importer.Diagnostics()[0].Message)
+				return
+			}
+
+			require.Len(t, importer.Policy().Rules, 1)
+			assert.Equal(t, test.expected, importer.Policy().Rules[0].Expression)
+			var warnings []string
+			for _, d := range importer.Diagnostics() {
+				assert.Equal(t, SeverityWarning, d.Severity)
+				warnings = append(warnings, d.Message)
+			}
+			var expected []string
+			if test.inexact {
+				expected = append(expected, "the wildcards of the exec.file.path path field don't match `/`, unlike the Sigma ones")
+			}
+			if test.caseSensitive {
+				expected = append(expected, "the exec.file.path path field is matched case-sensitively, unlike the Sigma strings which aren't cased")
+			}
+			assert.Equal(t, expected, warnings)
+		})
+	}
+}
+
+func TestImportSearches(t *testing.T) {
+	tests := []struct {
+		name      string
+		detection string
+		expected  string
+	}{
+		{
+			name: "list-of-maps",
+			detection: `
+  selection:
+    - Image: /bin/sh
+      User: root
+    - Image: /bin/bash`,
+			expected: `(exec.file.path == "/bin/sh" && exec.user =~ r"(?i)^root$") || exec.file.path == "/bin/bash"`,
+		},
+		{
+			name: "or-field-in-and",
+			detection: `
+  selection:
+    User|contains:
+      - adm
+      - root
+    ParentProcessId:
+      - 1
+      - 2
+    ProcessId|gt: 100`,
+			expected: `exec.user in [r"(?i)adm", r"(?i)root"] && exec.ppid in [1, 2] && exec.pid > 100`,
+		},
+		{
+			name: "all-gte",
+			detection: `
+  selection:
+    ProcessId|gte|all:
+      - 10
+      - 20
+    User|exists: true`,
+			expected: `exec.pid >= 10 && exec.pid >= 20 && exec.user != ""`,
+		},
+		{
+			name: "wildcards",
+			detection: `
+  selection:
+    User: 'ro*t'
+    CommandLine: 'python?'`,
+			expected: `exec.user =~ r"(?i)^ro.*t$" && exec.args =~ r"(?i)^python.$"`,
+		},
+		{
+			name: "cased",
+			detection: `
+  selection:
+    User|cased: 'ro*t'
+    CommandLine|cased: 'python?'`,
+			expected: `exec.user =~ "ro*t" && exec.args =~ r"^python.$"`,
+		},
+		{
+			name: "without-letters",
+			detection: `
+  selection:
+    CommandLine|endswith: ' 2>&1'`,
+			expected: `exec.args =~ "* 2>&1"`,
+		},
+		{
+			name: "escapes",
+			detection: `
+  selection:
+    CommandLine|contains: 'a\*b"c'`,
+			expected: `exec.args =~ r"(?i)a\*b\"c"`,
+		},
+		{
+			name: "regexp-flags",
+			detection: `
+  selection:
+    CommandLine|re|i: 'base64 +-d'`,
+			expected: `exec.args =~ r"(?i)base64 +-d"`,
+		},
+		{
+			name: "null",
+			detection: `
+  selection:
+    User: null`,
+			expected: `exec.user == ""`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			importer := importRule(t, `
+title: Test
+logsource:
+  product: linux
+  category: process_creation
+detection:`+test.detection+`
+  condition: selection
+`)
+			for _, d := range importer.Diagnostics() {
+				assert.Equal(t, SeverityWarning, d.Severity, d.String())
+			}
+			require.Len(t, importer.Policy().Rules, 1)
+			assert.Equal(t, test.expected, importer.Policy().Rules[0].Expression)
+		})
+	}
+}
+
+func TestImportDiagnostics(t *testing.T) {
+	tests := []struct {
+		name     string
+		rule     string
+		path     string
+		expected string
+	}{
+		{
+			name: "product",
+			rule: `
+logsource:
+  product: windows
+  category: process_creation`,
+			path:     "logsource.product",
+			expected: `only the linux product is supported, not "windows"`,
+		},
+		{
+			name: "category",
+			rule: `
+logsource:
+  product: linux
+  category: dns_query`,
+			path:     "logsource.category",
+			expected: `the "dns_query" category is not supported, only process_creation, file_event and network_connection`,
+		},
+		{
+			name: "modifier",
+			rule: `
+logsource:
+  product: linux
+  category: process_creation
+detection:
+  selection:
+    CommandLine|base64: foo
+  condition: selection`,
+			path:     "detection.selection.CommandLine|base64",
+			expected: `the "base64" modifier is not supported`,
+		},
+		{
+			name: "field",
+			rule: `
+logsource:
+  product: linux
+  category: process_creation
+detection:
+  selection:
+    CurrentDirectory: /tmp
+  condition: selection`,
+			path:     "detection.selection.CurrentDirectory",
+			expected: "the CurrentDirectory field of the process_creation category has no SECL equivalent",
+		},
+		{
+			name: "keywords",
+			rule: `
+logsource:
+  product: linux
+  category: process_creation
+detection:
+  keywords:
+    - foo
+  condition: keywords`,
+			path:     "detection.keywords",
+			expected: "keywords are not supported, the values must be matched against fields",
+		},
+		{
+			name: "aggregation",
+			rule: `
+logsource:
+  product: linux
+  category: process_creation
+detection:
+  selection:
+    User: root
+  condition: selection | count() > 3`,
+			path:     "detection.condition",
+			expected: "selection | count() > 3: aggregations are not supported",
+		},
+		{
+			name: "timeframe",
+			rule: `
+logsource:
+  product: linux
+  category: process_creation
+detection:
+  selection:
+    User: root
+  timeframe: 5m
+  condition: selection`,
+			path:     "detection.timeframe",
+			expected: "timeframes are not supported",
+		},
+		{
+			name: "unknown-search",
+			rule: `
+logsource:
+  product: linux
+  category: process_creation
+detection:
+  selection:
+    User: root
+  condition: selection and filter`,
+			path:     "detection.condition",
+			expected: `unknown search identifier "filter"`,
+		},
+		{
+			name: "invalid-cidr",
+			rule: `
+logsource:
+  product: linux
+  category: network_connection
+detection:
+  selection:
+    DestinationIp|cidr: 10.0.0.0
+  condition: selection`,
+			path:     "detection.selection.DestinationIp|cidr",
+			expected: `invalid CIDR "10.0.0.0"`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			importer := importRule(t, "title: Test\n"+test.rule)
+			assert.Empty(t, importer.Policy().Rules)
+			require.Len(t, importer.Diagnostics(), 1)
+
+			d := importer.Diagnostics()[0]
+			assert.Equal(t, SeverityError, d.Severity)
+			assert.Equal(t, "test.yml", d.Source)
+			assert.Equal(t, "Test", d.Rule)
+			assert.Equal(t, test.path, d.Path)
+			assert.Equal(t, test.expected, d.Message)
+		})
+	}
+}
+
+func TestImportWarnings(t *testing.T) {
+	importer := importRule(t, `
+title: Whoami
+logsource:
+  product: linux
+  category: process_creation
+detection:
+  selection:
+    CommandLine: whoami
+  condition: selection
+`)
+	require.Len(t, importer.Policy().Rules, 1)
+	require.Len(t, importer.Diagnostics(), 1)
+	assert.Equal(t, `warning: test.yml: rule "Whoami": detection.selection.CommandLine: `+argsWarning, importer.Diagnostics()[0].String())
+}
+
+func TestImportMultipleDocuments(t *testing.T) {
+	importer := importRule(t, `
+title: First
+id: same
+logsource:
+  product: linux
+  category: process_creation
+detection:
+  selection:
+    User: root
+  condition: selection
+---
+title: Second
+id: same
+logsource:
+  product: linux
+  category: process_creation
+detection:
+  selection:
+    User: nobody
+  condition: selection
+`)
+	require.Len(t, importer.Policy().Rules, 1)
+	assert.Equal(t, "First", importer.Policy().Rules[0].Description)
+	require.Len(t, importer.Diagnostics(), 1)
+	assert.Equal(t, "the rule ID sigma_same is already used by another rule", importer.Diagnostics()[0].Message)
+}
+
+func TestImportPolicyDefinition(t *testing.T) {
+	importer := importRule(t, `
+title: Shell Spawned By Web Server
+logsource:
+  product: linux
+  category: process_creation
+detection:
+  selection:
+    ParentImage|endswith:
+      - /nginx
+      - /httpd
+  filter:
+    ParentCommandLine|contains: healthcheck
+  condition: selection and not filter
+`)
+	require.Empty(t, importer.Diagnostics())
+
+	policy, err := rules.LoadPolicyFromDefinition("sigma", "test", importer.Policy(), nil, nil)
+	require.NoError(t, err)
+	assert.Len(t, policy.GetAcceptedRules(), 1)
+	assert.Len(t, policy.GetAcceptedMacros(), 2)
+}
+
+func TestMarshal(t *testing.T) {
+	importer := importRule(t, `
+title: Shell Spawned By Web Server
+id: 9c4ba1fb-4b5f-4b0c-8d3c-ffb2d0c5a5d2
+logsource:
+  product: linux
+  category: process_creation
+detection:
+  selection:
+    ParentImage|endswith: /nginx
+  condition: selection
+`)
+	require.Empty(t, importer.Diagnostics())
+
+	b, err := importer.Marshal()
+	require.NoError(t, err)
+	for _, field := range []string{"agent_version", "filters", "values", "combine", "version"} {
+		assert.NotContains(t, string(b), field+":")
+	}
+
+	policy, err := rules.LoadPolicy("sigma.policy", "sigma", bytes.NewReader(b), nil, nil)
+	require.NoError(t, err)
+	assert.Len(t, policy.GetAcceptedRules(), 1)
+	assert.Len(t, policy.GetAcceptedMacros(), 1)
+}
+
+func TestParseCondition(t *testing.T) {
+	tests := []struct {
+		condition string
+		err       string
+	}{
+		{condition: "selection"},
+		{condition: "selection and not (filter1 or filter2)"},
+		{condition: "1 of selection_* and all of filter*"},
+		{condition: "any of them"},
+		{condition: "selection and", err: "unexpected end of condition"},
+		{condition: "(selection", err: "missing closing parenthesis"},
+		{condition: "selection near filter", err: `unexpected "near"`},
+		{condition: "selection | count(User) by Image > 5", err: "aggregations are not supported"},
+		{condition: "all selection", err: `expected ` + "`of`" + ` after "all"`},
+		{condition: "selection = 1", err: "unexpected character '='"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.condition, func(t *testing.T) {
+			_, err := parseCondition(test.condition)
+			if test.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package sigma
+
+// fieldKind is the type of the values of a SECL field
+type fieldKind int
+
+const (
+	stringField fieldKind = iota
+	// pathField is a string field whose patterns are globs, where `*` doesn't match `/`, and which doesn't support
+	// regular expressions
+	pathField
+	intField
+	ipField
+)
+
+// field is the SECL field a Sigma field is translated to
+type field struct {
+	name string
+	kind fieldKind
+	// basename is the field of the file name of the path fields
+	basename string
+	// warning is reported when the field is compared as a whole, rather than with `contains` or `endswith`
+	warning string
+}
+
+// category is a Sigma log source category, and the SECL event type it is translated to
+type category struct {
+	eventType string
+	// filter is the expression matching the events of the category among the events of the type
+	filter string
+	// anchor is the expression making the rules of the category match the events of the type, when they only use
+	// fields common to every event type, like `process.file.path`
+	anchor string
+	fields map[string]field
+	// ignoredFields are the fields whose values are always matched by the events of the category
+	ignoredFields map[string]string
+}
+
+const argsWarning = "the arguments of SECL exclude argv[0], which the Sigma command lines include"
+
+// categories are the supported categories of the linux product
+var categories = map[string]*category{
+	"process_creation": {
+		eventType: "exec",
+		anchor:    `exec.file.path != ""`,
+		fields: map[string]field{
+			"Image":             {name: "exec.file.path", kind: pathField, basename: "exec.file.name"},
+			"CommandLine":       {name: "exec.args", kind: stringField, warning: argsWarning},
+			"User":              {name: "exec.user", kind: stringField},
+			"ProcessId":         {name: "exec.pid", kind: intField},
+			"ParentImage":       {name: "process.parent.file.path", kind: pathField, basename: "process.parent.file.name"},
+			"ParentCommandLine": {name: "process.parent.args", kind: stringField, warning: argsWarning},
+			"ParentProcessId":   {name: "exec.ppid", kind: intField},
+		},
+	},
+	"file_event": {
+		eventType: "open",
+		filter:    `open.flags & O_CREAT > 0`,
+		fields: map[string]field{
+			"TargetFilename": {name: "open.file.path", kind: pathField, basename: "open.file.name"},
+			"Image":          {name: "process.file.path", kind: pathField, basename: "process.file.name"},
+			"User":           {name: "process.user", kind: stringField},
+			"ProcessId":      {name: "process.pid", kind: intField},
+		},
+	},
+	"network_connection": {
+		eventType: "connect",
+		anchor:    `connect.addr.family in [AF_INET, AF_INET6]`,
+		fields: map[string]field{
+			"DestinationIp":   {name: "connect.addr.ip", kind: ipField},
+			"DestinationPort": {name: "connect.addr.port", kind: intField},
+			"Image":           {name: "process.file.path", kind: pathField, basename: "process.file.name"},
+			"User":            {name: "process.user", kind: stringField},
+			"ProcessId":       {name: "process.pid", kind: intField},
+		},
+		ignoredFields: map[string]string{
+			// connect events are the connections initiated by the host
+			"Initiated": "true",
+		},
+	},
+}
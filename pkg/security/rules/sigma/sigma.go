@@ -0,0 +1,119 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+// Package sigma translates Sigma rules into SECL rules and macros.
+//
+// The Linux process_creation, file_event and network_connection log sources are supported, with the `contains`,
+// `startswith`, `endswith`, `all`, `re`, `cidr`, `exists`, `cased`, `lt`, `lte`, `gt` and `gte` modifiers. Keywords,
+// aggregations and the other modifiers have no SECL equivalent, the rules using them are reported by diagnostics
+// and skipped. The Sigma strings are case-insensitive unless they're cased, they're translated to case-insensitive
+// regular expressions, but the path fields only support globs, which are case-sensitive and whose wildcards don't
+// match `/`. The rules whose translation may match different events are reported by warnings.
+package sigma
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Severity is the severity of a diagnostic
+type Severity string
+
+const (
+	// SeverityError reports a rule which can't be translated, and is skipped
+	SeverityError Severity = "error"
+	// SeverityWarning reports a rule which is translated, but may not match exactly the same events
+	SeverityWarning Severity = "warning"
+)
+
+// Diagnostic reports an issue with the translation of a Sigma rule
+type Diagnostic struct {
+	Severity Severity
+	// Source is the file of the Sigma rule
+	Source string
+	// Rule is the title of the Sigma rule
+	Rule string
+	// Path is the location of the issue in the Sigma rule, like `detection.selection.Image|base64`
+	Path    string
+	Message string
+}
+
+// String returns the diagnostic as a single line
+func (d Diagnostic) String() string {
+	s := fmt.Sprintf("%s: %s: rule %q", d.Severity, d.Source, d.Rule)
+	if d.Path != "" {
+		s += ": " + d.Path
+	}
+	return s + ": " + d.Message
+}
+
+// logSource is the log source of a Sigma rule
+type logSource struct {
+	Product  string `yaml:"product"`
+	Category string `yaml:"category"`
+	Service  string `yaml:"service"`
+}
+
+// rule is a Sigma rule
+type rule struct {
+	Title       string    `yaml:"title"`
+	ID          string    `yaml:"id"`
+	Status      string    `yaml:"status"`
+	Description string    `yaml:"description"`
+	Level       string    `yaml:"level"`
+	Tags        []string  `yaml:"tags"`
+	Action      string    `yaml:"action"`
+	LogSource   logSource `yaml:"logsource"`
+	Detection   yaml.Node `yaml:"detection"`
+}
+
+// detection is the detection section of a Sigma rule
+type detection struct {
+	// searches are the search identifiers, in the order of the rule
+	searches []search
+	// conditions are the conditions of the rule, which are combined with `or`
+	conditions []string
+	timeframe  string
+}
+
+// search is a search identifier of the detection of a Sigma rule
+type search struct {
+	name string
+	// node is a map of fields, a list of maps of fields, or a list of keywords
+	node *yaml.Node
+}
+
+func parseDetection(node *yaml.Node) (*detection, error) {
+	if node.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("the detection must be a map")
+	}
+
+	d := &detection{}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i].Value, node.Content[i+1]
+		switch key {
+		case "condition":
+			switch value.Kind {
+			case yaml.ScalarNode:
+				d.conditions = []string{value.Value}
+			case yaml.SequenceNode:
+				if err := value.Decode(&d.conditions); err != nil {
+					return nil, fmt.Errorf("invalid condition: %w", err)
+				}
+			default:
+				return nil, fmt.Errorf("the condition must be a string or a list of strings")
+			}
+		case "timeframe":
+			d.timeframe = value.Value
+		default:
+			d.searches = append(d.searches, search{name: key, node: value})
+		}
+	}
+	if len(d.conditions) == 0 {
+		return nil, fmt.Errorf("the detection has no condition")
+	}
+	return d, nil
+}
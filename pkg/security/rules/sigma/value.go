@@ -0,0 +1,236 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package sigma
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// segment is a part of a Sigma string, either a literal or a wildcard
+type segment struct {
+	literal string
+	// wildcard is `*`, `?`, or 0 for the literals
+	wildcard byte
+}
+
+// parseSigmaString splits a Sigma string into its literals and wildcards. `\*`, `\?` and `\\` are the escaped
+// literals, the other backslashes being literals themselves.
+func parseSigmaString(s string) []segment {
+	var segments []segment
+	var literal strings.Builder
+	flush := func() {
+		if literal.Len() > 0 {
+			segments = append(segments, segment{literal: literal.String()})
+			literal.Reset()
+		}
+	}
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\\' && i+1 < len(s) && (s[i+1] == '*' || s[i+1] == '?' || s[i+1] == '\\'):
+			literal.WriteByte(s[i+1])
+			i++
+		case c == '*' || c == '?':
+			flush()
+			segments = append(segments, segment{wildcard: c})
+		default:
+			literal.WriteByte(c)
+		}
+	}
+	flush()
+	return segments
+}
+
+// isRepresentable returns whether a literal can be written as a SECL string, which has no escape sequences
+func isRepresentable(s string) bool {
+	return !strings.ContainsAny(s, `"\`)
+}
+
+// hasCase returns whether the literals of a Sigma string have letters, whose case matters to the comparisons
+func hasCase(segments []segment) bool {
+	for _, s := range segments {
+		if strings.ToLower(s.literal) != strings.ToUpper(s.literal) {
+			return true
+		}
+	}
+	return false
+}
+
+// stringValue returns the SECL value matching a Sigma string, which is a string, a pattern (`~"..."`) or a regular
+// expression (`r"..."`). The SECL strings and patterns are case-sensitive, the Sigma strings which aren't cased are
+// thus matched by case-insensitive regular expressions.
+func stringValue(segments []segment, cased bool) string {
+	caseInsensitive := !cased && hasCase(segments)
+	wildcards, questionMarks, representable := false, false, true
+	for _, s := range segments {
+		switch s.wildcard {
+		case '*':
+			wildcards = true
+		case '?':
+			wildcards, questionMarks = true, true
+		default:
+			representable = representable && isRepresentable(s.literal) && !strings.Contains(s.literal, "*")
+		}
+	}
+
+	if !wildcards && representable && !caseInsensitive {
+		var literal strings.Builder
+		for _, s := range segments {
+			literal.WriteString(s.literal)
+		}
+		return `"` + literal.String() + `"`
+	}
+
+	if representable && !questionMarks && !caseInsensitive {
+		var pattern strings.Builder
+		for _, s := range segments {
+			// `**` is not allowed in the patterns
+			if s.wildcard == '*' && strings.HasSuffix(pattern.String(), "*") {
+				continue
+			}
+			pattern.WriteString(s.literal)
+			if s.wildcard != 0 {
+				pattern.WriteByte(s.wildcard)
+			}
+		}
+		return `~"` + pattern.String() + `"`
+	}
+
+	var re strings.Builder
+	re.WriteByte('^')
+	for _, s := range segments {
+		switch s.wildcard {
+		case '*':
+			re.WriteString(".*")
+		case '?':
+			re.WriteByte('.')
+		default:
+			re.WriteString(regexp.QuoteMeta(s.literal))
+		}
+	}
+	re.WriteByte('$')
+	// the regular expressions are not anchored
+	pattern := strings.TrimSuffix(strings.TrimPrefix(re.String(), "^.*"), ".*$")
+	if caseInsensitive {
+		pattern = "(?i)" + pattern
+	}
+	return regexpValue(pattern)
+}
+
+// pathValues returns the field and the SECL values matching a Sigma string on a path field. The patterns of the path
+// fields are globs, whose `*` match a single directory level, and whose `**` match the subdirectories at the end of
+// the pattern. The file names in any directory are thus matched against the basename field. inexact is set when a
+// wildcard, which matches `/` in Sigma, may match several directory levels. The regular expressions aren't supported on
+// the path fields, only the file names are matched case-insensitively when the Sigma string isn't cased.
+func pathValues(f field, segments []segment, cased bool) (name string, values []string, inexact bool, err error) {
+	var pattern strings.Builder
+	for _, s := range segments {
+		switch s.wildcard {
+		case '?':
+			return "", nil, false, fmt.Errorf("the `?` wildcard is not supported on the path fields")
+		case '*':
+			// `**` is only allowed at the end of the globs
+			if !strings.HasSuffix(pattern.String(), "*") {
+				pattern.WriteByte('*')
+			}
+		default:
+			if !isRepresentable(s.literal) || strings.Contains(s.literal, "*") {
+				return "", nil, false, fmt.Errorf("the path %q can't be represented in SECL", s.literal)
+			}
+			pattern.WriteString(s.literal)
+		}
+	}
+	p := pattern.String()
+
+	if !strings.Contains(p, "*") {
+		if !path.IsAbs(p) || path.Clean(p) != p {
+			return "", nil, false, fmt.Errorf("%q is not an absolute path", p)
+		}
+		return f.name, []string{`"` + p + `"`}, false, nil
+	}
+
+	// a file name in any directory, like `*/curl` or `*.sh`
+	if suffix, ok := strings.CutPrefix(p, "*"); ok && f.basename != "" {
+		base, inDirectory := strings.CutPrefix(suffix, "/")
+		if base != "" && !strings.Contains(base, "/") {
+			if !inDirectory {
+				base = "*" + base
+			}
+			// the file names have no `/`, whose wildcards match the same names as the patterns
+			return f.basename, []string{stringValue(parseSigmaString(base), cased)}, strings.Contains(strings.TrimPrefix(base, "*"), "*"), nil
+		}
+	}
+
+	if !strings.HasPrefix(p, "/") && !strings.HasPrefix(p, "*/") {
+		return "", nil, false, fmt.Errorf("%q is not an absolute path", p)
+	}
+	inexact = strings.Contains(strings.TrimSuffix(p, "*"), "*")
+	globs := []string{p}
+	if strings.HasPrefix(p, "*/") {
+		// the leading `*` matches a single directory level, the root directory is matched by another glob
+		globs = append(globs, p[1:])
+	}
+	for _, glob := range globs {
+		switch {
+		case strings.HasSuffix(glob, "/*"):
+			values = append(values, glob+"*")
+		case strings.HasSuffix(glob, "*"):
+			// the prefix of a file name, or of a directory name
+			values = append(values, glob, glob+"/**")
+		default:
+			values = append(values, glob)
+		}
+	}
+	for i, value := range values {
+		if path.Clean(value) != value {
+			return "", nil, false, fmt.Errorf("%q is not an absolute path", p)
+		}
+		values[i] = `~"` + value + `"`
+	}
+	return f.name, values, inexact, nil
+}
+
+// regexpValue returns the SECL regular expression of a pattern, escaping its quotes
+func regexpValue(pattern string) string {
+	var re strings.Builder
+	re.WriteString(`r"`)
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; {
+		case c == '\\' && i+1 < len(pattern):
+			re.WriteByte(c)
+			re.WriteByte(pattern[i+1])
+			i++
+		case c == '"':
+			re.WriteString(`\"`)
+		default:
+			re.WriteByte(c)
+		}
+	}
+	re.WriteByte('"')
+	return re.String()
+}
+
+// comparison returns the SECL comparison of a field to a single value
+func comparison(field string, value string) string {
+	switch {
+	case strings.HasPrefix(value, `~"`):
+		return field + " =~ " + value[1:]
+	case strings.HasPrefix(value, `r"`):
+		return field + " =~ " + value
+	default:
+		return field + " == " + value
+	}
+}
+
+// membership returns the SECL expression matching a field against any of the values
+func membership(field string, values []string) string {
+	if len(values) == 1 {
+		return comparison(field, values[0])
+	}
+	return field + " in [" + strings.Join(values, ", ") + "]"
+}
@@ -70,6 +70,9 @@ func NewActivityDumpRemoteStorage() (ActivityDumpStorage, error) {
 		return nil, fmt.Errorf("couldn't generate storage endpoints: %w", err)
 	}
 	for _, endpoint := range endpoints.GetReliableEndpoints() {
+		if endpoint.IsLocal() {
+			continue
+		}
 		storage.urls = append(storage.urls, utils.GetEndpointURL(endpoint, "api/v2/secdump"))
 		// TODO - runtime API key refresh: Storing the API key like this will no longer be valid once the
 		// security agent support API key refresh at runtime.
@@ -132,6 +132,12 @@ func parse2ColumnStats(fr fileReader, path string, keyColumn, valueColumn int, v
 	return err
 }
 
+// ParsePSI parses a Pressure Stall Information file outside of a cgroup, like the host-level
+// /proc/pressure/{cpu,memory,io} files, which share the format of the cgroupv2 *.pressure files.
+func ParsePSI(path string, somePsi, fullPsi *PSIStats) error {
+	return parsePSI(defaultFileReader, path, somePsi, fullPsi)
+}
+
 // format is "some avg10=0.00 avg60=0.00 avg300=0.00 total=0"
 func parsePSI(fr fileReader, path string, somePsi, fullPsi *PSIStats) error {
 	return parseColumnStats(fr, path, func(fields []string) error {
@@ -31,6 +31,9 @@ type CollectorMetadata = provider.CollectorMetadata
 // Provider interface allows to mock the metrics provider
 type Provider = provider.Provider
 
+// PressureStallAverages stores the Pressure Stall Information averages, in percent (0-100).
+type PressureStallAverages = provider.PressureStallAverages
+
 // ContainerMemStats stores memory statistics.
 type ContainerMemStats = provider.ContainerMemStats
 
@@ -76,6 +76,17 @@ func GetFullSampleContainerEntry() ContainerEntry {
 				ReadOperations:   pointer.Ptr(20.0),
 				WriteOperations:  pointer.Ptr(40.0),
 				PartialStallTime: pointer.Ptr(98000.0),
+				FullStallTime:    pointer.Ptr(48000.0),
+				PartialStall: &metrics.PressureStallAverages{
+					Avg10:  pointer.Ptr(2.5),
+					Avg60:  pointer.Ptr(1.5),
+					Avg300: pointer.Ptr(0.5),
+				},
+				FullStall: &metrics.PressureStallAverages{
+					Avg10:  pointer.Ptr(1.25),
+					Avg60:  pointer.Ptr(0.75),
+					Avg300: pointer.Ptr(0.25),
+				},
 			},
 			PID: &metrics.ContainerPIDStats{
 				ThreadCount: pointer.Ptr(10.0),
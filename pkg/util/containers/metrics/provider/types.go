@@ -11,6 +11,13 @@ import "time"
 // All fields are float64 as that's is required by the sender API.
 // Common units: nanoseconds, bytes
 
+// PressureStallAverages stores the Pressure Stall Information averages, in percent (0-100).
+type PressureStallAverages struct {
+	Avg10  *float64
+	Avg60  *float64
+	Avg300 *float64
+}
+
 // ContainerMemStats stores memory statistics.
 type ContainerMemStats struct {
 	// Common fields
@@ -27,6 +34,9 @@ type ContainerMemStats struct {
 	Cache            *float64
 	OOMEvents        *float64 // Number of events where memory allocation failed
 	PartialStallTime *float64 // Correspond to PSI Some total
+	FullStallTime    *float64 // Correspond to PSI Full total
+	PartialStall     *PressureStallAverages
+	FullStall        *PressureStallAverages
 	Peak             *float64
 	Pgfault          *float64
 	Pgmajfault       *float64
@@ -53,6 +63,9 @@ type ContainerCPUStats struct {
 	ThrottledPeriods *float64
 	ThrottledTime    *float64
 	PartialStallTime *float64 // Correspond to PSI Some total
+	FullStallTime    *float64 // Correspond to PSI Full total
+	PartialStall     *PressureStallAverages
+	FullStall        *PressureStallAverages
 }
 
 // DeviceIOStats stores Device IO stats.
@@ -74,6 +87,9 @@ type ContainerIOStats struct {
 
 	// Linux only
 	PartialStallTime *float64 // Correspond to PSI Some total
+	FullStallTime    *float64 // Correspond to PSI Full total
+	PartialStall     *PressureStallAverages
+	FullStall        *PressureStallAverages
 
 	Devices map[string]DeviceIOStats
 }
@@ -351,7 +351,8 @@ func buildMemoryStats(cgs *cgroups.MemoryStats) *provider.ContainerMemStats {
 	convertField(cgs.Peak, &cs.Peak)
 	convertField(cgs.Pgfault, &cs.Pgfault)
 	convertField(cgs.Pgmajfault, &cs.Pgmajfault)
-	convertFieldAndUnit(cgs.PSISome.Total, &cs.PartialStallTime, float64(time.Microsecond))
+	convertPSI(cgs.PSISome, &cs.PartialStallTime, &cs.PartialStall)
+	convertPSI(cgs.PSIFull, &cs.FullStallTime, &cs.FullStall)
 
 	// Compute complex fields
 	if cgs.UsageTotal != nil && cgs.InactiveFile != nil {
@@ -376,7 +377,8 @@ func buildCPUStats(cgs *cgroups.CPUStats, parentCPUStatsRetriever func(parentCPU
 	convertField(cgs.ElapsedPeriods, &cs.ElapsedPeriods)
 	convertField(cgs.ThrottledPeriods, &cs.ThrottledPeriods)
 	convertField(cgs.ThrottledTime, &cs.ThrottledTime)
-	convertFieldAndUnit(cgs.PSISome.Total, &cs.PartialStallTime, float64(time.Microsecond))
+	convertPSI(cgs.PSISome, &cs.PartialStallTime, &cs.PartialStall)
+	convertPSI(cgs.PSIFull, &cs.FullStallTime, &cs.FullStall)
 
 	// Compute complex fields
 	cs.Limit, cs.DefaultedLimit = computeCPULimitPct(cgs, parentCPUStatsRetriever)
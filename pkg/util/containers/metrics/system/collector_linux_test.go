@@ -69,7 +69,16 @@ func TestBuildContainerMetrics(t *testing.T) {
 					OOMEvents:    pointer.Ptr(uint64(10)),
 					Peak:         pointer.Ptr(uint64(1024)),
 					PSISome: cgroups.PSIStats{
-						Total: pointer.Ptr(uint64(97)),
+						Avg10:  pointer.Ptr(1.5),
+						Avg60:  pointer.Ptr(1.0),
+						Avg300: pointer.Ptr(0.5),
+						Total:  pointer.Ptr(uint64(97)),
+					},
+					PSIFull: cgroups.PSIStats{
+						Avg10:  pointer.Ptr(0.5),
+						Avg60:  pointer.Ptr(0.25),
+						Avg300: pointer.Ptr(0.1),
+						Total:  pointer.Ptr(uint64(37)),
 					},
 				},
 				IOStats: &cgroups.IOStats{
@@ -120,7 +129,18 @@ func TestBuildContainerMetrics(t *testing.T) {
 					SwapLimit:        pointer.Ptr(500.0),
 					OOMEvents:        pointer.Ptr(10.0),
 					PartialStallTime: pointer.Ptr(97000.0),
-					Peak:             pointer.Ptr(1024.0),
+					FullStallTime:    pointer.Ptr(37000.0),
+					PartialStall: &provider.PressureStallAverages{
+						Avg10:  pointer.Ptr(1.5),
+						Avg60:  pointer.Ptr(1.0),
+						Avg300: pointer.Ptr(0.5),
+					},
+					FullStall: &provider.PressureStallAverages{
+						Avg10:  pointer.Ptr(0.5),
+						Avg60:  pointer.Ptr(0.25),
+						Avg300: pointer.Ptr(0.1),
+					},
+					Peak: pointer.Ptr(1024.0),
 				},
 				IO: &provider.ContainerIOStats{
 					ReadBytes:        pointer.Ptr(100.0),
@@ -7,7 +7,13 @@
 
 package system
 
-import "github.com/DataDog/datadog-agent/pkg/util/pointer"
+import (
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/util/cgroups"
+	"github.com/DataDog/datadog-agent/pkg/util/containers/metrics/provider"
+	"github.com/DataDog/datadog-agent/pkg/util/pointer"
+)
 
 func convertField(s *uint64, t **float64) {
 	if s != nil {
@@ -20,3 +26,15 @@ func convertFieldAndUnit(s *uint64, t **float64, multiplier float64) {
 		*t = pointer.Ptr(float64(*s) * multiplier)
 	}
 }
+
+// convertPSI converts the Pressure Stall Information of a cgroup, whose total is in microseconds
+func convertPSI(s cgroups.PSIStats, total **float64, averages **provider.PressureStallAverages) {
+	convertFieldAndUnit(s.Total, total, float64(time.Microsecond))
+	if s.Avg10 != nil || s.Avg60 != nil || s.Avg300 != nil {
+		*averages = &provider.PressureStallAverages{
+			Avg10:  s.Avg10,
+			Avg60:  s.Avg60,
+			Avg300: s.Avg300,
+		}
+	}
+}
@@ -0,0 +1,143 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package scrubber
+
+import (
+	"bytes"
+	"regexp"
+	"unicode"
+)
+
+// Detector is a Replacer finding one kind of secret, named so that what it scrubbed can be reported.
+// Detectors apply to single values, like the arguments of a command line, and don't use the YAML fields
+// of the Replacer.
+type Detector struct {
+	Name string
+	Replacer
+}
+
+// Names of the default detectors
+const (
+	URLUserinfoDetector  = "url_userinfo"
+	BearerTokenDetector  = "bearer_token"
+	BasicAuthDetector    = "basic_auth"
+	AWSAccessKeyDetector = "aws_access_key"
+	JWTDetector          = "jwt"
+	SecretEnvVarDetector = "secret_env_var"
+	Base64SecretDetector = "base64_secret"
+)
+
+// minBase64SecretLength is the length from which base64 blobs are considered secrets
+const minBase64SecretLength = 40
+
+// Detectors returns the default detectors, in the order they should be applied
+func Detectors() []Detector {
+	return []Detector{
+		{
+			// JWTs are matched first as they are often used as bearer tokens
+			Name: JWTDetector,
+			Replacer: Replacer{
+				Regex: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`),
+				Hints: []string{"eyJ"},
+				Repl:  []byte(defaultReplacement),
+			},
+		},
+		{
+			Name: URLUserinfoDetector,
+			Replacer: Replacer{
+				Regex: regexp.MustCompile(`(?i)\b([a-z][a-z0-9+.-]*://[^:/@\s]*):[^@/\s]+@`),
+				Hints: []string{"://"},
+				Repl:  []byte(`$1:********@`),
+			},
+		},
+		{
+			Name: BearerTokenDetector,
+			Replacer: Replacer{
+				Regex: regexp.MustCompile(`(?i)\b(bearer\s+)[A-Za-z0-9._~+/-]+=*`),
+				Hints: []string{"earer", "EARER"},
+				Repl:  []byte(`$1********`),
+			},
+		},
+		{
+			Name: BasicAuthDetector,
+			Replacer: Replacer{
+				Regex: regexp.MustCompile(`(?i)\b((?:proxy-)?authorization:\s*(?:basic|digest|negotiate)\s+)\S+`),
+				Hints: []string{"uthorization", "UTHORIZATION"},
+				Repl:  []byte(`$1********`),
+			},
+		},
+		{
+			// the access key IDs keep their last 4 characters to be told apart
+			Name: AWSAccessKeyDetector,
+			Replacer: Replacer{
+				Regex: regexp.MustCompile(`\b(?:AKIA|ASIA|ABIA|ACCA)[A-Z0-9]{12}([A-Z0-9]{4})\b`),
+				Hints: []string{"AKIA", "ASIA", "ABIA", "ACCA"},
+				Repl:  []byte(`****************$1`),
+			},
+		},
+		{
+			// environment variables set on the command line, like `sh -c "GITHUB_TOKEN=... make"`
+			Name: SecretEnvVarDetector,
+			Replacer: Replacer{
+				Regex: regexp.MustCompile(`(^|\s)([A-Z_][A-Z0-9_]*(?:TOKEN|SECRET|PASSWORD|PASSWD|API_KEY|APIKEY|ACCESS_KEY|PRIVATE_KEY|CREDENTIALS)[A-Z0-9_]*=)\S+`),
+				Hints: []string{"="},
+				Repl:  []byte(`$1$2********`),
+			},
+		},
+		{
+			// long base64 blobs are only scrubbed when they mix upper and lower case letters and digits,
+			// so that paths, hexadecimal hashes and container IDs are kept
+			Name: Base64SecretDetector,
+			Replacer: Replacer{
+				Regex:    regexp.MustCompile(`[A-Za-z0-9+/_-]{40,}={0,2}`),
+				ReplFunc: scrubBase64Secret,
+			},
+		},
+	}
+}
+
+func scrubBase64Secret(b []byte) []byte {
+	if len(b) < minBase64SecretLength || bytes.Count(b, []byte("/")) > len(b)/8 {
+		return b
+	}
+	var upper, lower, digit bool
+	for _, r := range string(b) {
+		upper = upper || unicode.IsUpper(r)
+		lower = lower || unicode.IsLower(r)
+		digit = digit || unicode.IsDigit(r)
+	}
+	if !upper || !lower || !digit {
+		return b
+	}
+	return []byte(defaultReplacement)
+}
+
+// Scrub returns the value with the secrets found by the detector replaced, and whether any was found
+func (d Detector) Scrub(value string) (string, bool) {
+	data := []byte(value)
+	if len(d.Hints) > 0 && !containsAny(data, d.Hints) {
+		return value, false
+	}
+	var scrubbed []byte
+	if d.ReplFunc != nil {
+		scrubbed = d.Regex.ReplaceAllFunc(data, d.ReplFunc)
+	} else {
+		scrubbed = d.Regex.ReplaceAll(data, d.Repl)
+	}
+	if bytes.Equal(data, scrubbed) {
+		return value, false
+	}
+	return string(scrubbed), true
+}
+
+func containsAny(data []byte, hints []string) bool {
+	for _, hint := range hints {
+		if bytes.Contains(data, []byte(hint)) {
+			return true
+		}
+	}
+	return false
+}
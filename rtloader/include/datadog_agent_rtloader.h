@@ -187,6 +187,16 @@ DATADOG_AGENT_RTLOADER_API char *run_check(rtloader_t *, rtloader_pyobject_t *ch
 */
 DATADOG_AGENT_RTLOADER_API void cancel_check(rtloader_t *, rtloader_pyobject_t *check);
 
+/*! \fn int interrupt_check(rtloader_t *, rtloader_pyobject_t *check)
+    \brief Interrupts the running check instance by raising a TimeoutError in the
+    thread running it. The GIL must be held by the caller.
+    \param rtloader_t A rtloader_t * pointer to the RtLoader instance.
+    \param check A rtloader_pyobject_t * pointer to the check instance we wish to interrupt.
+    \return An integer set to 1 if the check was running and got interrupted, 0 otherwise.
+    \sa rtloader_pyobject_t, rtloader_t
+*/
+DATADOG_AGENT_RTLOADER_API int interrupt_check(rtloader_t *, rtloader_pyobject_t *check);
+
 /*! \fn char **get_checks_warnings(rtloader_t *, rtloader_pyobject_t *check)
     \brief Get all warnings, if any, for a check instance.
     \param rtloader_t A rtloader_t * pointer to the RtLoader instance.
@@ -115,6 +115,16 @@ public:
     */
     virtual void cancelCheck(RtLoaderPyObject *check) = 0;
 
+    //! Pure virtual interruptCheck member.
+    /*!
+      \param check The python object pointer to the check we wish to interrupt.
+      \return A boolean indicating whether the check was running and got interrupted.
+
+      The run is interrupted by raising a TimeoutError in the thread running it, the
+      exception is raised once the interpreter executes the code of the check again.
+    */
+    virtual bool interruptCheck(RtLoaderPyObject *check) = 0;
+
     //! Pure virtual getCheckWarnings member.
     /*!
       \param check The python object pointer to the check we wish to collect existing warnings for.
@@ -272,6 +272,11 @@ void cancel_check(rtloader_t *rtloader, rtloader_pyobject_t *check)
     AS_TYPE(RtLoader, rtloader)->cancelCheck(AS_TYPE(RtLoaderPyObject, check));
 }
 
+int interrupt_check(rtloader_t *rtloader, rtloader_pyobject_t *check)
+{
+    return AS_TYPE(RtLoader, rtloader)->interruptCheck(AS_TYPE(RtLoaderPyObject, check)) ? 1 : 0;
+}
+
 char **get_checks_warnings(rtloader_t *rtloader, rtloader_pyobject_t *check)
 {
     return AS_TYPE(RtLoader, rtloader)->getCheckWarnings(AS_TYPE(RtLoaderPyObject, check));
@@ -448,7 +448,12 @@ char *Three::runCheck(RtLoaderPyObject *check)
     char run[] = "run";
     PyObject *result = NULL;
 
+    unsigned long thread_id = PyThread_get_thread_ident();
+    _runningChecks[py_check] = thread_id;
     result = PyObject_CallMethod(py_check, run, NULL);
+    _runningChecks.erase(py_check);
+    // discard the TimeoutError of an interruption that came too late to be raised
+    PyThreadState_SetAsyncExc(thread_id, NULL);
     if (result == NULL || !PyUnicode_Check(result)) {
         setError("error invoking 'run' method: " + _fetchPythonError());
         goto done;
@@ -485,6 +490,21 @@ void Three::cancelCheck(RtLoaderPyObject *check)
     Py_XDECREF(result);
 }
 
+bool Three::interruptCheck(RtLoaderPyObject *check)
+{
+    if (check == NULL) {
+        return false;
+    }
+
+    PyObject *py_check = reinterpret_cast<PyObject *>(check);
+
+    std::map<PyObject *, unsigned long>::iterator it = _runningChecks.find(py_check);
+    if (it == _runningChecks.end()) {
+        return false;
+    }
+    return PyThreadState_SetAsyncExc(it->second, PyExc_TimeoutError) == 1;
+}
+
 char **Three::getCheckWarnings(RtLoaderPyObject *check)
 {
     if (check == NULL) {
@@ -63,6 +63,7 @@ public:
 
     char *runCheck(RtLoaderPyObject *check);
     void cancelCheck(RtLoaderPyObject *check);
+    bool interruptCheck(RtLoaderPyObject *check);
     char **getCheckWarnings(RtLoaderPyObject *check);
     char *getCheckDiagnoses(RtLoaderPyObject *check);
     void decref(RtLoaderPyObject *obj);
@@ -187,6 +188,8 @@ private:
     PyObject *_baseClass; /*!< PyObject * pointer to the base Agent check class */
     PyPaths _pythonPaths; /*!< string vector containing paths in the PYTHONPATH */
     PyThreadState *_threadState; /*!< PyThreadState * pointer to the saved Python interpreter thread state */
+    std::map<PyObject *, unsigned long> _runningChecks; /*!< map of the running checks to the identifier of the
+                                                           thread running them, only accessed with the GIL held */
 
     //! pymallocAlloc member.
     /*!
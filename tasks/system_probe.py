@@ -501,6 +501,13 @@ def ninja_cgo_type_files(nw: NinjaWriter):
             "pkg/network/protocols/redis/types.go": [
                 "pkg/network/ebpf/c/protocols/redis/types.h",
             ],
+            "pkg/network/protocols/memcached/ebpf/types.go": [
+                "pkg/network/ebpf/c/protocols/memcached/types.h",
+            ],
+            "pkg/network/protocols/cassandra/ebpf/types.go": [
+                "pkg/network/ebpf/c/protocols/cassandra/types.h",
+                "pkg/network/ebpf/c/protocols/cassandra/defs.h",
+            ],
             "pkg/ebpf/telemetry/types.go": [
                 "pkg/ebpf/c/telemetry_types.h",
             ],